@@ -5266,6 +5266,16 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/mileusna/useragent/com_github_mileusna_useragent-v0.0.0-20190129205925-3e331f0949a5.zip",
         ],
     )
+    go_repository(
+        name = "com_github_minio_highwayhash",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/minio/highwayhash",
+        sha256 = "d971055d29b619520113b7775ff8dab6c8bbe3100afd387b268247dbf1f2ce79",
+        strip_prefix = "github.com/minio/highwayhash@v1.0.1",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/minio/highwayhash/com_github_minio_highwayhash-v1.0.1.zip",
+        ],
+    )
     go_repository(
         name = "com_github_minio_minio_go",
         build_file_proto_mode = "disable_global",
@@ -5600,40 +5610,50 @@ def go_deps():
         name = "com_github_nats_io_jwt",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/jwt",
-        sha256 = "d0ab8bb735649df606874454eafc44c9d0b5ae41a0453875260c351e209b1719",
-        strip_prefix = "github.com/nats-io/jwt@v0.3.2",
+        sha256 = "7ee85dd13b6084cd24584f51b39cc820f3a431e1f97fb075894d72645358d675",
+        strip_prefix = "github.com/nats-io/jwt@v1.2.2",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/jwt/com_github_nats_io_jwt-v1.2.2.zip",
+        ],
+    )
+    go_repository(
+        name = "com_github_nats_io_jwt_v2",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/nats-io/jwt/v2",
+        sha256 = "84ef5873205cc0fea535be58d5cd31455cc9499276d6722d73ebb8fc81bbf58f",
+        strip_prefix = "github.com/nats-io/jwt/v2@v2.0.2",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/jwt/com_github_nats_io_jwt-v0.3.2.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/jwt/v2/com_github_nats_io_jwt_v2-v2.0.2.zip",
         ],
     )
     go_repository(
         name = "com_github_nats_io_nats_go",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nats.go",
-        sha256 = "34a735d158d70685faad1fc3153f08da0ddc21c0ae42f6a0cb09430d638364b2",
-        strip_prefix = "github.com/nats-io/nats.go@v1.9.1",
+        sha256 = "714627fb143f8b2e9ab670137e2d01e5dc3a33363ce9f8aaae02bc45a11bb28c",
+        strip_prefix = "github.com/nats-io/nats.go@v1.11.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.9.1.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.11.0.zip",
         ],
     )
     go_repository(
         name = "com_github_nats_io_nats_server_v2",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nats-server/v2",
-        sha256 = "cf6c4affe3eae3f43b67b5ecc401f41819280136d6ef9209198a5f44b62d3280",
-        strip_prefix = "github.com/nats-io/nats-server/v2@v2.1.2",
+        sha256 = "6ec97e7d564cb414f79ca1e6de5dfe3eefeed692588a3f2c8ab25aa456c598dc",
+        strip_prefix = "github.com/nats-io/nats-server/v2@v2.3.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats-server/v2/com_github_nats_io_nats_server_v2-v2.1.2.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats-server/v2/com_github_nats_io_nats_server_v2-v2.3.0.zip",
         ],
     )
     go_repository(
         name = "com_github_nats_io_nkeys",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nkeys",
-        sha256 = "291930a7abcd84edcaffc2cadc75aeb830ebf561144313ead6b8e1fcc03b124f",
-        strip_prefix = "github.com/nats-io/nkeys@v0.1.3",
+        sha256 = "9383fa98356bb67ba1110814918e9997fdbcb83c08ffd6902b5aed7b9d96dfa2",
+        strip_prefix = "github.com/nats-io/nkeys@v0.3.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.1.3.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.3.0.zip",
         ],
     )
     go_repository(
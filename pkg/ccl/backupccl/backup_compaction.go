@@ -0,0 +1,779 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/types"
+)
+
+const compactOp = "BACKUP COMPACT"
+
+// compactBackupPlanHook plans a BACKUP COMPACT, which merges a full backup and
+// the incremental backups appended to it into a new full backup in the same
+// collection. The new backup is built only from the files of the chain: no
+// data is read from the cluster, so the compaction can run long after the
+// data it covers has been garbage collected. Once the new backup is written,
+// it replaces the compacted chain as the LATEST backup of the collection, so
+// that RESTORE and the following incremental backups only need to read it.
+func compactBackupPlanHook(
+	ctx context.Context, backupStmt *annotatedBackupStatement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	if backupStmt.Options.CaptureRevisionHistory {
+		return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s option is not supported by %s", backupOptRevisionHistory, compactOp)
+	}
+
+	var err error
+	subdirFn := func() (string, error) { return latestFileName, nil }
+	if backupStmt.Subdir != nil {
+		subdirFn, err = p.TypeAsString(ctx, backupStmt.Subdir, compactOp)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	toFn, err := p.TypeAsStringArray(ctx, tree.Exprs(backupStmt.To), compactOp)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	incToFn, err := p.TypeAsStringArray(ctx, tree.Exprs(backupStmt.Options.IncrementalStorage),
+		compactOp)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	encryptionParams := jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_None}
+
+	var pwFn func() (string, error)
+	if backupStmt.Options.EncryptionPassphrase != nil {
+		pwFn, err = p.TypeAsString(ctx, backupStmt.Options.EncryptionPassphrase, compactOp)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_Passphrase
+	}
+
+	var kmsFn func() ([]string, error)
+	if backupStmt.Options.EncryptionKMSURI != nil {
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, nil, false,
+				errors.New("cannot have both encryption_passphrase and kms option set")
+		}
+		kmsFn, err = p.TypeAsStringArray(ctx, tree.Exprs(backupStmt.Options.EncryptionKMSURI),
+			compactOp)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_KMS
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, backupStmt.StatementTag())
+		defer span.Finish()
+
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.BackupCompaction) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s is only available once the cluster is fully upgraded", compactOp)
+		}
+		if !(p.ExtendedEvalContext().TxnImplicit || backupStmt.Options.Detached) {
+			return errors.Errorf("%s cannot be used inside a transaction without DETACHED option", compactOp)
+		}
+		if err := p.RequireAdminRole(ctx, compactOp); err != nil {
+			return err
+		}
+		if err := requireEnterprise(p.ExecCfg(), "compaction"); err != nil {
+			return err
+		}
+
+		subdir, err := subdirFn()
+		if err != nil {
+			return err
+		}
+		to, err := toFn()
+		if err != nil {
+			return err
+		}
+		if len(to) > 1 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s does not support partitioned backups", compactOp)
+		}
+		incrementalStorage, err := incToFn()
+		if err != nil {
+			return err
+		}
+
+		switch encryptionParams.Mode {
+		case jobspb.EncryptionMode_Passphrase:
+			if encryptionParams.RawPassphrae, err = pwFn(); err != nil {
+				return err
+			}
+		case jobspb.EncryptionMode_KMS:
+			if encryptionParams.RawKmsUris, err = kmsFn(); err != nil {
+				return err
+			}
+		}
+
+		backupDetails, backupManifest, err := getCompactionDetailsAndManifest(
+			ctx, p.ExecCfg(), p.User(), to, subdir, incrementalStorage, encryptionParams,
+		)
+		if err != nil {
+			return err
+		}
+
+		description, err := backupJobDescription(p, backupStmt.Backup, to, nil, /* incrementalFrom */
+			encryptionParams.RawKmsUris, backupDetails.Compaction.Subdir, incrementalStorage)
+		if err != nil {
+			return err
+		}
+
+		// As for other backups, the manifest is persisted in a temporary
+		// checkpoint file that the job renames when it starts.
+		doWriteBackupManifestCheckpoint := func(ctx context.Context, jobID jobspb.JobID) error {
+			defaultStore, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, backupDetails.URI, p.User())
+			if err != nil {
+				return err
+			}
+			defer defaultStore.Close()
+
+			if err := writeBackupManifest(
+				ctx, p.ExecCfg().Settings, defaultStore, tempCheckpointFileNameForJob(jobID),
+				backupDetails.EncryptionOptions, &backupManifest,
+			); err != nil {
+				return errors.Wrapf(err, "writing checkpoint file")
+			}
+			return nil
+		}
+
+		jr := jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			DescriptorIDs: func() (sqlDescIDs []descpb.ID) {
+				for i := range backupManifest.Descriptors {
+					sqlDescIDs = append(sqlDescIDs,
+						descpb.GetDescriptorID(&backupManifest.Descriptors[i]))
+				}
+				return sqlDescIDs
+			}(),
+			Details:  backupDetails,
+			Progress: jobspb.BackupProgress{},
+		}
+
+		plannerTxn := p.ExtendedEvalContext().Txn
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+
+		if backupStmt.Options.Detached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, plannerTxn,
+			); err != nil {
+				return err
+			}
+			if err := doWriteBackupManifestCheckpoint(ctx, jobID); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, plannerTxn, jr); err != nil {
+				return err
+			}
+			if err := doWriteBackupManifestCheckpoint(ctx, jobID); err != nil {
+				return err
+			}
+			return plannerTxn.Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if backupStmt.Options.Detached {
+		return fn, utilccl.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, utilccl.BulkJobExecutionResultHeader, nil, false, nil
+}
+
+// getCompactionDetailsAndManifest resolves the chain of backups in the given
+// subdir of the collection, and returns the details of the job compacting it
+// along with the manifest of the full backup it will write. The chain is
+// resolved as RESTORE would resolve it, including the incremental backups
+// stored in incrementalStorage if it is set.
+func getCompactionDetailsAndManifest(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	collection []string,
+	subdir string,
+	incrementalStorage []string,
+	encryptionParams jobspb.BackupEncryptionOptions,
+) (jobspb.BackupDetails, BackupManifest, error) {
+	makeCloudStorage := execCfg.DistSQLSrv.ExternalStorageFromURI
+	collectionURI := collection[0]
+
+	if subdir == latestFileName {
+		latest, err := readLatestFile(ctx, collectionURI, makeCloudStorage, user)
+		if err != nil {
+			return jobspb.BackupDetails{}, BackupManifest{}, err
+		}
+		subdir = latest
+	}
+	subdir = "/" + strings.TrimPrefix(subdir, "/")
+
+	fullURI, _, err := getURIsByLocalityKV(collection, subdir)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+	var incFrom []string
+	if len(incrementalStorage) > 0 {
+		incURI, _, err := getURIsByLocalityKV(incrementalStorage, subdir)
+		if err != nil {
+			return jobspb.BackupDetails{}, BackupManifest{}, err
+		}
+		incFrom = []string{incURI}
+	}
+
+	kmsEnv := &backupKMSEnv{settings: execCfg.Settings, conf: &execCfg.ExternalIODirConfig}
+	encryption, err := getEncryptionFromBase(ctx, user, makeCloudStorage, fullURI,
+		encryptionParams, kmsEnv)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+
+	baseStore, err := makeCloudStorage(ctx, fullURI, user)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+	defer baseStore.Close()
+
+	// The compacted backup is encrypted with the key of the chain, so the
+	// encryption info of the full backup is copied alongside it.
+	var encryptionInfo *jobspb.EncryptionInfo
+	if encryption != nil {
+		encryptionInfo, err = readEncryptionOptions(ctx, baseStore)
+		if err != nil {
+			return jobspb.BackupDetails{}, BackupManifest{}, err
+		}
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	chainURIs, chain, _, memSize, err := resolveBackupManifests(
+		ctx, &mem, []cloud.ExternalStorage{baseStore}, makeCloudStorage, [][]string{{fullURI}},
+		incFrom, hlc.Timestamp{}, encryption, user,
+	)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	if len(chain) < 2 {
+		return jobspb.BackupDetails{}, BackupManifest{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"backup %s has no incremental backups to compact", subdir)
+	}
+	for i := range chain {
+		if chain[i].MVCCFilter == MVCCFilter_All {
+			return jobspb.BackupDetails{}, BackupManifest{}, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot compact backups taken with the %s option", backupOptRevisionHistory)
+		}
+		if len(chain[i].PartitionDescriptorFilenames) > 0 {
+			return jobspb.BackupDetails{}, BackupManifest{}, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot compact locality-aware backups")
+		}
+		if !chain[i].ClusterID.Equal(execCfg.ClusterID()) {
+			return jobspb.BackupDetails{}, BackupManifest{}, errors.Newf(
+				"cannot compact backups of another cluster (%s)", chain[i].ClusterID)
+		}
+	}
+
+	last := chain[len(chain)-1]
+	if err := checkCoverage(ctx, last.Spans, chain); err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, errors.Wrap(err,
+			"backup chain does not cover the spans of its latest backup")
+	}
+
+	// The compacted backup is a full backup of the spans and descriptors of the
+	// latest backup of the chain, as of its end time.
+	backupManifest := BackupManifest{
+		EndTime:             last.EndTime,
+		MVCCFilter:          MVCCFilter_Latest,
+		Descriptors:         last.Descriptors,
+		Tenants:             last.Tenants,
+		CompleteDbs:         last.CompleteDbs,
+		Spans:               last.Spans,
+		FormatVersion:       BackupFormatDescriptorTrackingVersion,
+		BuildInfo:           build.GetInfo(),
+		ClusterVersion:      last.ClusterVersion,
+		ClusterID:           last.ClusterID,
+		StatisticsFilenames: last.StatisticsFilenames,
+		DescriptorCoverage:  last.DescriptorCoverage,
+	}
+
+	compactedSubdir := last.EndTime.GoTime().Format(DateBasedIntoFolderName)
+	defaultURI, _, err := getURIsByLocalityKV(collection, compactedSubdir)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+	defaultStore, err := makeCloudStorage(ctx, defaultURI, user)
+	if err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+	defer defaultStore.Close()
+	if err := checkForPreviousBackup(ctx, defaultStore, defaultURI); err != nil {
+		return jobspb.BackupDetails{}, BackupManifest{}, err
+	}
+
+	return jobspb.BackupDetails{
+		Destination: jobspb.BackupDetails_Destination{
+			To:                 collection,
+			Subdir:             compactedSubdir,
+			IncrementalStorage: incrementalStorage,
+		},
+		EndTime:           last.EndTime,
+		URI:               defaultURI,
+		EncryptionOptions: encryption,
+		EncryptionInfo:    encryptionInfo,
+		CollectionURI:     collectionURI,
+		FullCluster:       last.DescriptorCoverage == tree.AllDescriptors,
+		Compaction: &jobspb.BackupDetails_Compaction{
+			ChainURIs: chainURIs,
+			Subdir:    subdir,
+		},
+	}, backupManifest, nil
+}
+
+// compactBackupChain merges the layers of the chain described by the
+// compaction details into the files of backupManifest, which it writes to
+// defaultStore along with the manifest itself and the table statistics of the
+// latest layer.
+//
+// The cover of the chain that RESTORE would use is computed, and the newest
+// revision of each key in each of its entries is written, skipping deleted
+// keys. Entries are processed in key order, and written files are periodically
+// checkpointed in the manifest so that a resumed job skips the spans they
+// cover.
+func compactBackupChain(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	details jobspb.BackupDetails,
+	defaultStore cloud.ExternalStorage,
+	job *jobs.Job,
+	backupManifest *BackupManifest,
+) (RowCount, error) {
+	execCfg := execCtx.ExecCfg()
+	resumerSpan := tracing.SpanFromContext(ctx)
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	chain, memSize, err := getBackupManifests(ctx, &mem, execCtx.User(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, details.Compaction.ChainURIs,
+		details.EncryptionOptions)
+	if err != nil {
+		return RowCount{}, err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	var encryption *roachpb.FileEncryptionOptions
+	if details.EncryptionOptions != nil {
+		key, err := getEncryptionKey(ctx, details.EncryptionOptions, execCfg.Settings,
+			execCfg.ExternalIODirConfig)
+		if err != nil {
+			return RowCount{}, err
+		}
+		encryption = &roachpb.FileEncryptionOptions{Key: key}
+	}
+
+	pkIDs := make(map[uint64]bool)
+	for i := range backupManifest.Descriptors {
+		if t, _, _, _, _ := descpb.FromDescriptor(&backupManifest.Descriptors[i]); t != nil {
+			pkIDs[roachpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	// Skip the entries covered by the files of a previous attempt.
+	var completed roachpb.SpanGroup
+	for _, f := range backupManifest.Files {
+		completed.Add(f.Span)
+	}
+	cover := makeSimpleImportSpans(backupManifest.Spans, chain, nil, /* backupLocalityMap */
+		nil /* lowWaterMark */)
+	sort.Slice(cover, func(i, j int) bool { return cover[i].Span.Key.Compare(cover[j].Span.Key) < 0 })
+	var entries []execinfrapb.RestoreSpanEntry
+	for _, entry := range cover {
+		if !completed.Encloses(entry.Span) {
+			entries = append(entries, entry)
+		}
+	}
+
+	progressLogger := jobs.NewChunkProgressLogger(job, len(entries), job.FractionCompleted(),
+		jobs.ProgressUpdateOnly)
+	entryFinishedCh := make(chan struct{}, len(entries)) // enough buffer to never block
+	var jobProgressLoop func(ctx context.Context) error
+	if len(entries) > 0 {
+		jobProgressLoop = func(ctx context.Context) error {
+			return progressLogger.Loop(ctx, entryFinishedCh)
+		}
+	}
+
+	sink := &compactionSink{
+		dest:       defaultStore,
+		enc:        encryption,
+		settings:   &execCfg.Settings.SV,
+		instanceID: execCfg.NodeID.SQLInstanceID(),
+		pkIDs:      pkIDs,
+		endTime:    backupManifest.EndTime,
+	}
+	defer sink.Close()
+
+	var lastCheckpoint time.Time
+	flush := func(ctx context.Context) error {
+		files, err := sink.flush(ctx)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			backupManifest.Files = append(backupManifest.Files, f)
+			backupManifest.EntryCounts.add(f.EntryCounts)
+		}
+		if timeutil.Since(lastCheckpoint) > BackupCheckpointInterval {
+			if err := writeBackupManifest(
+				ctx, execCfg.Settings, defaultStore, backupManifestCheckpointName,
+				details.EncryptionOptions, backupManifest,
+			); err != nil {
+				log.Errorf(ctx, "unable to checkpoint backup descriptor: %+v", err)
+			}
+			lastCheckpoint = timeutil.Now()
+		}
+		return nil
+	}
+
+	compact := func(ctx context.Context) error {
+		defer close(entryFinishedCh)
+		for _, entry := range entries {
+			if err := compactEntry(ctx, execCfg, entry, encryption, sink); err != nil {
+				return err
+			}
+			if sink.size >= targetFileSize.Get(&execCfg.Settings.SV) {
+				if err := flush(ctx); err != nil {
+					return err
+				}
+			}
+			entryFinishedCh <- struct{}{}
+		}
+		return flush(ctx)
+	}
+
+	resumerSpan.RecordStructured(&types.StringValue{Value: "compacting backup chain"})
+	if err := ctxgroup.GoAndWait(ctx, jobProgressLoop, compact); err != nil {
+		return RowCount{}, errors.Wrapf(err, "compacting %d backups", errors.Safe(len(chain)))
+	}
+
+	backupManifest.ID = uuid.MakeV4()
+	resumerSpan.RecordStructured(&types.StringValue{Value: "writing backup manifest"})
+	if err := writeBackupManifest(
+		ctx, execCfg.Settings, defaultStore, backupManifestName, details.EncryptionOptions,
+		backupManifest,
+	); err != nil {
+		return RowCount{}, err
+	}
+
+	// The statistics of the latest layer are those of the compacted backup.
+	var statsTable StatsTable
+	if err := func() error {
+		lastStore, err := execCfg.DistSQLSrv.ExternalStorageFromURI(
+			ctx, details.Compaction.ChainURIs[len(chain)-1], execCtx.User())
+		if err != nil {
+			return err
+		}
+		defer lastStore.Close()
+		statsTable.Statistics, err = getStatisticsFromBackup(
+			ctx, lastStore, details.EncryptionOptions, chain[len(chain)-1])
+		return err
+	}(); err != nil {
+		// As when backing up, statistics can be recomputed after restore, so
+		// failing to copy them does not fail the job.
+		log.Warningf(ctx, "failed to copy table statistics of compacted backup: %v", err)
+		statsTable.Statistics = nil
+	}
+	resumerSpan.RecordStructured(&types.StringValue{Value: "writing backup table statistics"})
+	if err := writeTableStatistics(
+		ctx, defaultStore, backupStatisticsFileName, details.EncryptionOptions, &statsTable,
+	); err != nil {
+		return RowCount{}, err
+	}
+
+	return backupManifest.EntryCounts, nil
+}
+
+// compactEntry writes the newest revision of each live key of the span of the
+// entry, read from its files in the layers of the chain, to the sink.
+func compactEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	entry execinfrapb.RestoreSpanEntry,
+	encryption *roachpb.FileEncryptionOptions,
+	sink *compactionSink,
+) error {
+	var iters []storage.SimpleMVCCIterator
+	var dirs []cloud.ExternalStorage
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+		for _, dir := range dirs {
+			if err := dir.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+
+	log.VEventf(ctx, 1, "compacting span [%s-%s)", entry.Span.Key, entry.Span.EndKey)
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, dir)
+		iter, err := storageccl.ExternalSSTReader(ctx, dir, file.Path, encryption)
+		if err != nil {
+			return err
+		}
+		iters = append(iters, iter)
+	}
+
+	iter := storage.MakeMultiIterator(iters)
+	defer iter.Close()
+	return sink.write(ctx, entry.Span, iter)
+}
+
+// compactionSink writes the data of consecutive spans to files of roughly
+// bulkio.backup.file_size bytes, recording a BackupManifest_File for each of
+// the spans it wrote data for.
+type compactionSink struct {
+	dest       cloud.ExternalStorage
+	enc        *roachpb.FileEncryptionOptions
+	settings   *settings.Values
+	instanceID base.SQLInstanceID
+	pkIDs      map[uint64]bool
+	endTime    hlc.Timestamp
+
+	ctx     context.Context
+	cancel  func()
+	out     io.WriteCloser
+	outName string
+	sst     storage.SSTWriter
+	// size is the size of the keys and values written to the current file.
+	size  int64
+	files []BackupManifest_File
+}
+
+func (s *compactionSink) open(ctx context.Context) error {
+	s.outName = generateUniqueSSTName(s.instanceID)
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(ctx)
+	}
+	w, err := s.dest.Writer(s.ctx, s.outName)
+	if err != nil {
+		return err
+	}
+	if s.enc != nil {
+		if w, err = storageccl.EncryptingWriter(w, s.enc.Key); err != nil {
+			return err
+		}
+	}
+	s.out = w
+	s.sst = storage.MakeBackupSSTWriter(s.out)
+	return nil
+}
+
+// write copies the newest revision of each live key of the span from the
+// iterator, which must be positioned after anything previously written.
+func (s *compactionSink) write(
+	ctx context.Context, span roachpb.Span, iter storage.SimpleMVCCIterator,
+) error {
+	var counter storage.RowCounter
+	end := storage.MVCCKey{Key: span.EndKey}
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; {
+		ok, err := iter.Valid()
+		if err != nil {
+			return err
+		}
+		if !ok || !iter.UnsafeKey().Less(end) {
+			break
+		}
+		if len(iter.UnsafeValue()) == 0 {
+			// The key is deleted as of the end of the chain.
+			iter.NextKey()
+			continue
+		}
+
+		if s.out == nil {
+			if err := s.open(ctx); err != nil {
+				return err
+			}
+		}
+		k := iter.UnsafeKey()
+		if k.Timestamp.IsEmpty() {
+			err = s.sst.PutUnversioned(k.Key, iter.UnsafeValue())
+		} else {
+			err = s.sst.PutMVCC(k, iter.UnsafeValue())
+		}
+		if err != nil {
+			return err
+		}
+		if err := counter.Count(k.Key); err != nil {
+			return err
+		}
+		size := int64(len(k.Key) + len(iter.UnsafeValue()))
+		counter.DataSize += size
+		s.size += size
+		iter.NextKey()
+	}
+
+	if counter.DataSize == 0 {
+		return nil
+	}
+	entryCounts := countRows(counter.BulkOpSummary, s.pkIDs)
+	// Extend the last file if this span picks up where it ended.
+	if l := len(s.files) - 1; l >= 0 && s.files[l].Span.EndKey.Equal(span.Key) {
+		s.files[l].Span.EndKey = span.EndKey
+		s.files[l].EntryCounts.add(entryCounts)
+		return nil
+	}
+	s.files = append(s.files, BackupManifest_File{
+		Span:        span,
+		Path:        s.outName,
+		EntryCounts: entryCounts,
+		EndTime:     s.endTime,
+	})
+	return nil
+}
+
+// flush finishes the current file, and returns the files recorded for it.
+func (s *compactionSink) flush(ctx context.Context) ([]BackupManifest_File, error) {
+	if s.out == nil {
+		return nil, nil
+	}
+	if err := s.sst.Finish(); err != nil {
+		return nil, err
+	}
+	err := s.out.Close()
+	s.out = nil
+	if err != nil {
+		return nil, errors.Wrap(err, "writing SST")
+	}
+	files := s.files
+	s.files = nil
+	s.size = 0
+	return files, nil
+}
+
+// Close abandons the current file, if it was not flushed.
+func (s *compactionSink) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.out != nil {
+		s.sst.Close()
+		_ = s.out.Close()
+		s.out = nil
+	}
+}
+
+// compactedChainIsLatest returns whether the LATEST file of the collection
+// still points at the compacted chain, and no backup was appended to the
+// chain since the compaction was planned. Only then the compacted backup
+// replaces the chain as the latest backup of the collection, since it would
+// otherwise miss the data of the newer backups.
+func compactedChainIsLatest(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	details jobspb.BackupDetails,
+) (bool, error) {
+	makeCloudStorage := execCfg.DistSQLSrv.ExternalStorageFromURI
+	latest, err := readLatestFile(ctx, details.CollectionURI, makeCloudStorage, user)
+	if err != nil {
+		return false, err
+	}
+	if path.Clean("/"+latest) != path.Clean(details.Compaction.Subdir) {
+		return false, nil
+	}
+
+	chainURI := details.Compaction.ChainURIs[0]
+	if len(details.Destination.IncrementalStorage) > 0 {
+		chainURI, _, err = getURIsByLocalityKV(details.Destination.IncrementalStorage,
+			details.Compaction.Subdir)
+		if err != nil {
+			return false, err
+		}
+	}
+	store, err := makeCloudStorage(ctx, chainURI, user)
+	if err != nil {
+		return false, err
+	}
+	defer store.Close()
+	priors, err := FindPriorBackups(ctx, store, OmitManifest)
+	if err != nil {
+		return false, err
+	}
+	return len(priors)+1 == len(details.Compaction.ChainURIs), nil
+}
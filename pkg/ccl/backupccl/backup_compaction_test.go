@@ -0,0 +1,75 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupCompaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = LocalFoo + "/compact"
+
+	// Make a full backup and a chain of incrementals on top of it with inserts,
+	// updates and deletes.
+	sqlDB.Exec(t, `BACKUP data.bank INTO $1`, collection)
+	sqlDB.Exec(t, `INSERT INTO data.bank VALUES (100, 100, 'new')`)
+	sqlDB.Exec(t, `BACKUP data.bank INTO LATEST IN $1`, collection)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 5`)
+	sqlDB.Exec(t, `BACKUP data.bank INTO LATEST IN $1`, collection)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = 3`)
+	sqlDB.Exec(t, `BACKUP data.bank INTO LATEST IN $1`, collection)
+	expected := sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`)
+
+	// Writes after the last incremental must not appear in the compacted backup.
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = 4`)
+
+	sqlDB.Exec(t, `BACKUP COMPACT LATEST IN $1`, collection)
+
+	backups := sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, collection)
+	require.Equal(t, 2, len(backups))
+
+	// The compacted backup is a single full backup of the table.
+	layers := sqlDB.QueryStr(t,
+		`SELECT DISTINCT backup_type FROM [SHOW BACKUP LATEST IN $1] WHERE object_type = 'table'`, collection)
+	require.Equal(t, [][]string{{"full"}}, layers)
+
+	sqlDB.Exec(t, `CREATE DATABASE compacted`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM LATEST IN $1 WITH into_db = 'compacted'`, collection)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM compacted.bank ORDER BY id`, expected)
+
+	t.Run("no-incrementals", func(t *testing.T) {
+		// The compacted backup is now the latest one, and has no incrementals.
+		sqlDB.ExpectErr(t, `has no incremental backups to compact`,
+			`BACKUP COMPACT LATEST IN $1`, collection)
+	})
+
+	t.Run("revision-history", func(t *testing.T) {
+		const revisions = LocalFoo + "/revisions"
+		sqlDB.Exec(t, `BACKUP data.bank INTO $1 WITH revision_history`, revisions)
+		sqlDB.Exec(t, `BACKUP data.bank INTO LATEST IN $1 WITH revision_history`, revisions)
+		sqlDB.ExpectErr(t, `cannot compact backups taken with the revision_history option`,
+			`BACKUP COMPACT LATEST IN $1`, revisions)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `revision_history option is not supported by BACKUP COMPACT`,
+			`BACKUP COMPACT LATEST IN $1 WITH revision_history`, collection)
+	})
+}
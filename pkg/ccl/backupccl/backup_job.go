@@ -148,7 +148,7 @@ func backup(
 
 	pkIDs := make(map[uint64]bool)
 	for i := range backupManifest.Descriptors {
-		if t, _, _, _ := descpb.FromDescriptor(&backupManifest.Descriptors[i]); t != nil {
+		if t, _, _, _, _ := descpb.FromDescriptor(&backupManifest.Descriptors[i]); t != nil {
 			pkIDs[roachpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
 		}
 	}
@@ -304,7 +304,7 @@ func backup(
 	}
 	var tableStatistics []*stats.TableStatisticProto
 	for i := range backupManifest.Descriptors {
-		if tbl, _, _, _ := descpb.FromDescriptor(&backupManifest.Descriptors[i]); tbl != nil {
+		if tbl, _, _, _, _ := descpb.FromDescriptor(&backupManifest.Descriptors[i]); tbl != nil {
 			tableDesc := tabledesc.NewBuilder(tbl).BuildImmutableTable()
 			// Collect all the table stats for this table.
 			tableStatisticsAcc, err := statsCache.GetTableStats(ctx, tableDesc)
@@ -454,21 +454,25 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 			AttemptNumber: retryCount,
 			RetryError:    tracing.RedactAndTruncateError(err),
 		})
-		res, err = backup(
-			ctx,
-			p,
-			details.URI,
-			details.URIsByLocalityKV,
-			p.ExecCfg().DB,
-			p.ExecCfg().Settings,
-			defaultStore,
-			storageByLocalityKV,
-			b.job,
-			backupManifest,
-			p.ExecCfg().DistSQLSrv.ExternalStorage,
-			details.EncryptionOptions,
-			statsCache,
-		)
+		if details.Compaction != nil {
+			res, err = compactBackupChain(ctx, p, details, defaultStore, b.job, backupManifest)
+		} else {
+			res, err = backup(
+				ctx,
+				p,
+				details.URI,
+				details.URIsByLocalityKV,
+				p.ExecCfg().DB,
+				p.ExecCfg().Settings,
+				defaultStore,
+				storageByLocalityKV,
+				b.job,
+				backupManifest,
+				p.ExecCfg().DistSQLSrv.ExternalStorage,
+				details.EncryptionOptions,
+				statsCache,
+			)
+		}
 		if err == nil {
 			break
 		}
@@ -522,8 +526,15 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 	// get to this file to read it, you could already find its contents from the
 	// listing of the directory it is in -- it exists only to save us a
 	// potentially expensive listing of a giant backup collection to find the most
-	// recent completed entry.
-	if backupManifest.StartTime.IsEmpty() && details.CollectionURI != "" {
+	// recent completed entry. A compacted backup only replaces the chain it
+	// compacted as the most recent entry.
+	updateLatest := backupManifest.StartTime.IsEmpty() && details.CollectionURI != ""
+	if updateLatest && details.Compaction != nil {
+		if updateLatest, err = compactedChainIsLatest(ctx, p.ExecCfg(), p.User(), details); err != nil {
+			return err
+		}
+	}
+	if updateLatest {
 		backupURI, err := url.Parse(details.URI)
 		if err != nil {
 			return err
@@ -558,6 +569,9 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 			numClusterNodes = 1
 		}
 
+		if details.Compaction != nil {
+			telemetry.Count("backup.compaction.succeeded")
+		}
 		telemetry.Count("backup.total.succeeded")
 		const mb = 1 << 20
 		sizeMb := res.DataSize / mb
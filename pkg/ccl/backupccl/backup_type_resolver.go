@@ -0,0 +1,74 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// backupTypeResolver resolves the types referenced by the tables of a backup
+// from their descriptors, which are shipped to the processors reading the data
+// of those tables.
+type backupTypeResolver struct {
+	typesByID map[descpb.ID]*descpb.TypeDescriptor
+}
+
+var _ tree.TypeReferenceResolver = &backupTypeResolver{}
+var _ catalog.TypeDescriptorResolver = &backupTypeResolver{}
+
+func newBackupTypeResolver(typeDescs []*descpb.TypeDescriptor) *backupTypeResolver {
+	r := &backupTypeResolver{
+		typesByID: make(map[descpb.ID]*descpb.TypeDescriptor, len(typeDescs)),
+	}
+	for _, typeDesc := range typeDescs {
+		r.typesByID[typeDesc.GetID()] = typeDesc
+	}
+	return r
+}
+
+// ResolveType implements the tree.TypeReferenceResolver interface.
+func (r *backupTypeResolver) ResolveType(
+	_ context.Context, name *tree.UnresolvedObjectName,
+) (*types.T, error) {
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "type %q does not exist", name)
+}
+
+// ResolveTypeByOID implements the tree.TypeReferenceResolver interface.
+func (r *backupTypeResolver) ResolveTypeByOID(ctx context.Context, oid oid.Oid) (*types.T, error) {
+	id, err := typedesc.UserDefinedTypeOIDToID(oid)
+	if err != nil {
+		return nil, err
+	}
+	name, desc, err := r.GetTypeDescriptor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return desc.MakeTypesT(ctx, &name, r)
+}
+
+// GetTypeDescriptor implements the catalog.TypeDescriptorResolver interface.
+func (r *backupTypeResolver) GetTypeDescriptor(
+	_ context.Context, id descpb.ID,
+) (tree.TypeName, catalog.TypeDescriptor, error) {
+	desc, ok := r.typesByID[id]
+	if !ok {
+		return tree.TypeName{}, nil, errors.Newf("type descriptor could not be resolved for type id %d", id)
+	}
+	return tree.MakeUnqualifiedTypeName(desc.GetName()), typedesc.NewBuilder(desc).BuildImmutableType(), nil
+}
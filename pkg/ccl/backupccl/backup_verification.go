@@ -0,0 +1,797 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+const (
+	verifyBackupOp = "VERIFY BACKUP"
+
+	verifyBackupOptFingerprint = "fingerprint"
+	verifyBackupOptDetached    = "detached"
+)
+
+var verifyBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
+	backupOptEncPassphrase:     sql.KVStringOptRequireValue,
+	backupOptEncKMS:            sql.KVStringOptRequireValue,
+	backupOptIncStorage:        sql.KVStringOptRequireValue,
+	verifyBackupOptFingerprint: sql.KVStringOptRequireNoValue,
+	verifyBackupOptDetached:    sql.KVStringOptRequireNoValue,
+}
+
+// verifyBackupHeader is the header of the results of a VERIFY BACKUP which is
+// not detached. The first row summarizes the verification of the files of the
+// backup, and is followed by a row for each file that is missing or corrupt,
+// and by a row for each fingerprinted index.
+var verifyBackupHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "object_type", Typ: types.String},
+	{Name: "object_name", Typ: types.String},
+	{Name: "fingerprint", Typ: types.String},
+	{Name: "error", Typ: types.String},
+}
+
+// verifyBackupPlanHook implements sql.PlanHookFn for VERIFY BACKUP, which
+// checks that every file referenced by the manifests of a chain of backups is
+// present, readable and, if the backup is encrypted, decrypts, without
+// restoring it. With the fingerprint option, the fingerprints of the indexes of
+// the tables in the backup are then computed as SHOW EXPERIMENTAL_FINGERPRINTS
+// would compute them for the tables as of the end time of the backup.
+func verifyBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	subdirFn, err := p.TypeAsString(ctx, verifyStmt.Subdir, verifyBackupOp)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	fromFn, err := p.TypeAsStringArray(ctx, tree.Exprs(verifyStmt.From), verifyBackupOp)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	optsFn, err := p.TypeAsStringOpts(ctx, verifyStmt.Options, verifyBackupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	opts, err := optsFn()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	_, detached := opts[verifyBackupOptDetached]
+	_, fingerprint := opts[verifyBackupOptFingerprint]
+
+	encryptionParams := jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_None}
+	if passphrase, ok := opts[backupOptEncPassphrase]; ok {
+		encryptionParams.Mode = jobspb.EncryptionMode_Passphrase
+		encryptionParams.RawPassphrae = passphrase
+	}
+	if kms, ok := opts[backupOptEncKMS]; ok {
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, nil, false,
+				errors.New("cannot have both encryption_passphrase and kms option set")
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_KMS
+		encryptionParams.RawKmsUris = []string{kms}
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VerifyBackup) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s is only available once the cluster is fully upgraded", verifyBackupOp)
+		}
+		if !(p.ExtendedEvalContext().TxnImplicit || detached) {
+			return errors.Errorf("%s cannot be used inside a transaction without DETACHED option",
+				verifyBackupOp)
+		}
+		if err := p.RequireAdminRole(ctx, verifyBackupOp); err != nil {
+			return err
+		}
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), verifyBackupOp,
+		); err != nil {
+			return err
+		}
+
+		subdir, err := subdirFn()
+		if err != nil {
+			return err
+		}
+		collections, err := fromFn()
+		if err != nil {
+			return err
+		}
+		var incrementalStorage []string
+		if incStorage, ok := opts[backupOptIncStorage]; ok {
+			incrementalStorage = []string{incStorage}
+		}
+
+		details, err := getVerifyBackupDetails(ctx, p, collections, subdir, incrementalStorage,
+			encryptionParams)
+		if err != nil {
+			return err
+		}
+		details.Fingerprint = fingerprint
+
+		description, err := verifyBackupJobDescription(p, verifyStmt, collections, subdir, opts)
+		if err != nil {
+			return err
+		}
+
+		jr := jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			Details:     details,
+			Progress:    jobspb.VerifyBackupProgress{},
+		}
+
+		plannerTxn := p.ExtendedEvalContext().Txn
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+
+		if detached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, plannerTxn,
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, plannerTxn, jr); err != nil {
+				return err
+			}
+			return plannerTxn.Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if detached {
+		return fn, utilccl.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, verifyBackupHeader, nil, false, nil
+}
+
+// getVerifyBackupDetails resolves the chain of backups in the given subdir of
+// the collection as RESTORE would resolve it, and returns the details of the
+// job verifying it.
+func getVerifyBackupDetails(
+	ctx context.Context,
+	p sql.PlanHookState,
+	collections []string,
+	subdir string,
+	incrementalStorage []string,
+	encryptionParams jobspb.BackupEncryptionOptions,
+) (jobspb.VerifyBackupDetails, error) {
+	execCfg := p.ExecCfg()
+	makeCloudStorage := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	if strings.EqualFold(subdir, latestFileName) {
+		latest, err := readLatestFile(ctx, collections[0], makeCloudStorage, p.User())
+		if err != nil {
+			return jobspb.VerifyBackupDetails{}, errors.Wrap(err, "read LATEST path")
+		}
+		subdir = latest
+	}
+
+	from, err := appendPathsToURIs(collections, subdir)
+	if err != nil {
+		return jobspb.VerifyBackupDetails{}, err
+	}
+	incFrom, err := appendPathsToURIs(incrementalStorage, subdir)
+	if err != nil {
+		return jobspb.VerifyBackupDetails{}, err
+	}
+
+	kmsEnv := &backupKMSEnv{settings: execCfg.Settings, conf: &execCfg.ExternalIODirConfig}
+	encryption, err := getEncryptionFromBase(ctx, p.User(), makeCloudStorage, from[0],
+		encryptionParams, kmsEnv)
+	if err != nil {
+		return jobspb.VerifyBackupDetails{}, err
+	}
+
+	baseStores := make([]cloud.ExternalStorage, len(from))
+	for i := range from {
+		store, err := makeCloudStorage(ctx, from[i], p.User())
+		if err != nil {
+			return jobspb.VerifyBackupDetails{}, errors.Wrapf(err, "failed to open backup storage location")
+		}
+		defer store.Close()
+		baseStores[i] = store
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	uris, _, localityInfo, memSize, err := resolveBackupManifests(
+		ctx, &mem, baseStores, makeCloudStorage, [][]string{from}, incFrom, hlc.Timestamp{},
+		encryption, p.User(),
+	)
+	if err != nil {
+		return jobspb.VerifyBackupDetails{}, err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	return jobspb.VerifyBackupDetails{
+		URIs:               uris,
+		BackupLocalityInfo: localityInfo,
+		Encryption:         encryption,
+	}, nil
+}
+
+// appendPathsToURIs returns the URIs with the subdir appended to their paths.
+func appendPathsToURIs(uris []string, subdir string) ([]string, error) {
+	res := make([]string, len(uris))
+	for i, uri := range uris {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Path = path.Join(parsed.Path, subdir)
+		res[i] = parsed.String()
+	}
+	return res, nil
+}
+
+func verifyBackupJobDescription(
+	p sql.PlanHookState,
+	verifyStmt *tree.VerifyBackup,
+	collections []string,
+	subdir string,
+	opts map[string]string,
+) (string, error) {
+	stmt := tree.VerifyBackup{Subdir: tree.NewDString(subdir)}
+	for _, uri := range collections {
+		clean, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+		if err != nil {
+			return "", err
+		}
+		stmt.From = append(stmt.From, tree.NewDString(clean))
+	}
+	for _, opt := range verifyStmt.Options {
+		k := string(opt.Key)
+		newOpt := tree.KVOption{Key: opt.Key}
+		switch k {
+		case backupOptEncPassphrase:
+			newOpt.Value = tree.NewDString("redacted")
+		case backupOptEncKMS:
+			redacted, err := cloud.RedactKMSURI(opts[k])
+			if err != nil {
+				return "", err
+			}
+			newOpt.Value = tree.NewDString(redacted)
+		case backupOptIncStorage:
+			clean, err := cloud.SanitizeExternalStorageURI(opts[k], nil /* extraParams */)
+			if err != nil {
+				return "", err
+			}
+			newOpt.Value = tree.NewDString(clean)
+		}
+		stmt.Options = append(stmt.Options, newOpt)
+	}
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(&stmt, ann), nil
+}
+
+type verifyBackupResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &verifyBackupResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The files of the backup are verified first, and then the indexes are
+// fingerprinted if this was requested and all the files could be read. Each
+// phase is recorded in the progress of the job once it completes, so that a
+// resumed job does not run it again.
+func (r *verifyBackupResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.VerifyBackupDetails)
+	progress := *r.job.Progress().Details.(*jobspb.Progress_VerifyBackup).VerifyBackup
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	chain, memSize, err := getBackupManifests(ctx, &mem, p.User(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, details.URIs, details.Encryption)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	backupLocalityMap, err := makeBackupLocalityMap(details.BackupLocalityInfo, p.User())
+	if err != nil {
+		return err
+	}
+
+	var encryption *roachpb.FileEncryptionOptions
+	if details.Encryption != nil {
+		key, err := getEncryptionKey(ctx, details.Encryption, execCfg.Settings,
+			execCfg.ExternalIODirConfig)
+		if err != nil {
+			return err
+		}
+		encryption = &roachpb.FileEncryptionOptions{Key: key}
+	}
+
+	if !progress.FilesVerified {
+		if err := r.verifyFiles(ctx, p, details, chain, backupLocalityMap, encryption, &progress); err != nil {
+			return err
+		}
+	}
+	if details.Fingerprint && len(progress.FileErrors) == 0 && !progress.FingerprintsComputed {
+		if err := r.fingerprintIndexes(ctx, p, chain, backupLocalityMap, encryption, &progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyFiles distributes the files of the layers of the chain over the nodes
+// of the cluster, which read them in full, and records the files which could
+// not be read in the progress of the job.
+func (r *verifyBackupResumer) verifyFiles(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.VerifyBackupDetails,
+	chain []BackupManifest,
+	backupLocalityMap map[int]storeByLocalityKV,
+	encryption *roachpb.FileEncryptionOptions,
+	progress *jobspb.VerifyBackupProgress,
+) error {
+	// A file is referenced by the manifest of its layer once for each of the
+	// spans it contains, so it is verified against the span enclosing them.
+	var files []execinfrapb.VerifyBackupDataSpec_File
+	var paths []string
+	for layer := range chain {
+		fileIdx := make(map[string]int)
+		for _, f := range chain[layer].Files {
+			if i, ok := fileIdx[f.Path]; ok {
+				files[i].Span = files[i].Span.Combine(f.Span)
+				continue
+			}
+			fileSpec := execinfrapb.RestoreFileSpec{Path: f.Path, Dir: chain[layer].Dir}
+			uri := details.URIs[layer]
+			if dir, ok := backupLocalityMap[layer][f.LocalityKV]; ok {
+				fileSpec.Dir = dir
+				uri = details.BackupLocalityInfo[layer].URIsByOriginalLocalityKV[f.LocalityKV]
+			}
+			fileIdx[f.Path] = len(files)
+			files = append(files, execinfrapb.VerifyBackupDataSpec_File{
+				ID:   int64(len(files)),
+				File: fileSpec,
+				Span: f.Span,
+			})
+			paths = append(paths, sanitizedBackupFileURI(uri, f.Path))
+		}
+	}
+
+	dsp := p.DistSQLPlanner()
+	planCtx, nodes, err := dsp.SetupAllNodesPlanning(ctx, p.ExtendedEvalContext(), p.ExecCfg())
+	if err != nil {
+		return err
+	}
+	specs := make(map[roachpb.NodeID]*execinfrapb.VerifyBackupDataSpec)
+	for i, f := range files {
+		node := nodes[i%len(nodes)]
+		if _, ok := specs[node]; !ok {
+			specs[node] = &execinfrapb.VerifyBackupDataSpec{Encryption: encryption}
+		}
+		specs[node].Files = append(specs[node].Files, f)
+	}
+
+	var filesChecked, bytesChecked int64
+	fileErrors := make(map[int64]string)
+	progCh := make(chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	updateFraction := util.Every(verifyBackupProgressInterval)
+	progressLoop := func(ctx context.Context) error {
+		for prog := range progCh {
+			var progDetails VerifyBackupDataProgress
+			if err := gogotypes.UnmarshalAny(&prog.ProgressDetails, &progDetails); err != nil {
+				log.Errorf(ctx, "unable to unmarshal verification progress details: %+v", err)
+				continue
+			}
+			filesChecked += progDetails.FilesChecked
+			bytesChecked += progDetails.BytesChecked
+			for id, err := range progDetails.FileErrors {
+				fileErrors[id] = err
+			}
+			if updateFraction.ShouldProcess(timeutil.Now()) {
+				fraction := verifyFilesFraction(filesChecked, len(files))
+				if err := r.job.FractionProgressed(ctx, nil /* txn */, jobs.FractionUpdater(fraction)); err != nil {
+					log.Warningf(ctx, "failed to update job progress: %v", err)
+				}
+			}
+		}
+		return nil
+	}
+	runFlow := func(ctx context.Context) error {
+		return distVerifyBackup(ctx, p, planCtx, dsp, progCh, specs)
+	}
+	if err := ctxgroup.GoAndWait(ctx, progressLoop, runFlow); err != nil {
+		return errors.Wrap(err, "verifying backup files")
+	}
+
+	progress.FilesChecked = filesChecked
+	progress.BytesChecked = bytesChecked
+	progress.FileErrors = progress.FileErrors[:0]
+	for id, err := range fileErrors {
+		progress.FileErrors = append(progress.FileErrors,
+			jobspb.VerifyBackupProgress_FileError{Path: paths[id], Error: err})
+	}
+	sort.Slice(progress.FileErrors, func(i, j int) bool {
+		return progress.FileErrors[i].Path < progress.FileErrors[j].Path
+	})
+	progress.FilesVerified = true
+	return r.checkpoint(ctx, *progress, verifyFilesFraction(filesChecked, len(files)))
+}
+
+// verifyBackupProgressInterval is the interval at which the fraction completed
+// of a VERIFY BACKUP job is updated.
+const verifyBackupProgressInterval = 10 * time.Second
+
+// verifyFilesFraction returns the fraction completed of a job which checked
+// filesChecked of the numFiles files of a backup. Verifying the files is
+// accounted as the first half of the job, which also fingerprints the indexes
+// of the backup.
+func verifyFilesFraction(filesChecked int64, numFiles int) float32 {
+	if numFiles == 0 {
+		return 0.5
+	}
+	return 0.5 * float32(filesChecked) / float32(numFiles)
+}
+
+// fingerprintIndexes distributes the entries of the cover of the spans of the
+// latest layer of the chain, as RESTORE would compute it, over the nodes of
+// the cluster, which fingerprint the indexes whose rows they contain. The
+// fingerprints of each index are XORed together and recorded in the progress
+// of the job.
+func (r *verifyBackupResumer) fingerprintIndexes(
+	ctx context.Context,
+	p sql.JobExecContext,
+	chain []BackupManifest,
+	backupLocalityMap map[int]storeByLocalityKV,
+	encryption *roachpb.FileEncryptionOptions,
+	progress *jobspb.VerifyBackupProgress,
+) error {
+	last := chain[len(chain)-1]
+
+	dbIDToName := make(map[descpb.ID]string)
+	schemaIDToName := make(map[descpb.ID]string)
+	schemaIDToName[keys.PublicSchemaIDForBackup] = catconstants.PublicSchemaName
+	var tables []descpb.TableDescriptor
+	var typeDescs []descpb.TypeDescriptor
+	for i := range last.Descriptors {
+		table, db, typ, schema, _ := descpb.FromDescriptor(&last.Descriptors[i])
+		switch {
+		case table != nil:
+			desc := tabledesc.NewBuilder(table).BuildImmutableTable()
+			if desc.IsTable() && !desc.IsVirtualTable() && desc.Public() {
+				tables = append(tables, *table)
+			}
+		case db != nil:
+			dbIDToName[db.ID] = db.Name
+		case typ != nil:
+			typeDescs = append(typeDescs, *typ)
+		case schema != nil:
+			schemaIDToName[schema.ID] = schema.Name
+		}
+	}
+
+	// Every index is reported, even those without any rows.
+	fingerprints := make(map[tableAndIndex]*jobspb.VerifyBackupProgress_IndexFingerprint)
+	var ordered []tableAndIndex
+	for i := range tables {
+		desc := tabledesc.NewBuilder(&tables[i]).BuildImmutableTable()
+		tableName := fmt.Sprintf("%s.%s.%s", dbIDToName[desc.GetParentID()],
+			schemaIDToName[desc.GetParentSchemaID()], desc.GetName())
+		for _, index := range desc.ActiveIndexes() {
+			if index.GetType() != descpb.IndexDescriptor_FORWARD {
+				continue
+			}
+			key := tableAndIndex{tableID: desc.GetID(), indexID: index.GetID()}
+			ordered = append(ordered, key)
+			fingerprints[key] = &jobspb.VerifyBackupProgress_IndexFingerprint{
+				TableID:   desc.GetID(),
+				IndexID:   index.GetID(),
+				TableName: tableName,
+				IndexName: index.GetName(),
+			}
+		}
+	}
+
+	cover := makeSimpleImportSpans(last.Spans, chain, backupLocalityMap, nil /* lowWaterMark */)
+
+	dsp := p.DistSQLPlanner()
+	planCtx, nodes, err := dsp.SetupAllNodesPlanning(ctx, p.ExtendedEvalContext(), p.ExecCfg())
+	if err != nil {
+		return err
+	}
+	specs := make(map[roachpb.NodeID]*execinfrapb.VerifyBackupDataSpec)
+	for i, entry := range cover {
+		node := nodes[i%len(nodes)]
+		if _, ok := specs[node]; !ok {
+			specs[node] = &execinfrapb.VerifyBackupDataSpec{
+				Tables:     tables,
+				Types:      typeDescs,
+				Encryption: encryption,
+			}
+		}
+		specs[node].Entries = append(specs[node].Entries, entry)
+	}
+
+	var entriesDone int
+	progCh := make(chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	updateFraction := util.Every(verifyBackupProgressInterval)
+	progressLoop := func(ctx context.Context) error {
+		for prog := range progCh {
+			var progDetails VerifyBackupDataProgress
+			if err := gogotypes.UnmarshalAny(&prog.ProgressDetails, &progDetails); err != nil {
+				log.Errorf(ctx, "unable to unmarshal verification progress details: %+v", err)
+				continue
+			}
+			for _, f := range progDetails.Fingerprints {
+				if fp, ok := fingerprints[tableAndIndex{tableID: f.TableID, indexID: f.IndexID}]; ok {
+					fp.Fingerprint ^= f.Fingerprint
+					fp.HashedRows += f.HashedRows
+				}
+			}
+			entriesDone++
+			if updateFraction.ShouldProcess(timeutil.Now()) {
+				fraction := 0.5 + 0.5*float32(entriesDone)/float32(len(cover))
+				if err := r.job.FractionProgressed(ctx, nil /* txn */, jobs.FractionUpdater(fraction)); err != nil {
+					log.Warningf(ctx, "failed to update job progress: %v", err)
+				}
+			}
+		}
+		return nil
+	}
+	runFlow := func(ctx context.Context) error {
+		return distVerifyBackup(ctx, p, planCtx, dsp, progCh, specs)
+	}
+	if err := ctxgroup.GoAndWait(ctx, progressLoop, runFlow); err != nil {
+		return errors.Wrap(err, "fingerprinting backup")
+	}
+
+	progress.Fingerprints = progress.Fingerprints[:0]
+	for _, key := range ordered {
+		progress.Fingerprints = append(progress.Fingerprints, *fingerprints[key])
+	}
+	progress.FingerprintsComputed = true
+	return r.checkpoint(ctx, *progress, 1.0)
+}
+
+// checkpoint records the progress of the job.
+func (r *verifyBackupResumer) checkpoint(
+	ctx context.Context, progress jobspb.VerifyBackupProgress, fraction float32,
+) error {
+	return r.job.FractionProgressed(ctx, nil, /* txn */
+		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			*details.(*jobspb.Progress_VerifyBackup).VerifyBackup = progress
+			return fraction
+		})
+}
+
+// distVerifyBackup is used to plan the processors for a distributed
+// verification of a backup. It streams back progress updates over progCh.
+func distVerifyBackup(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	planCtx *sql.PlanningCtx,
+	dsp *sql.DistSQLPlanner,
+	progCh chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+	specs map[roachpb.NodeID]*execinfrapb.VerifyBackupDataSpec,
+) error {
+	ctx, span := tracing.ChildSpan(ctx, "verify-backup-distsql")
+	defer span.Finish()
+	ctx = logtags.AddTag(ctx, "verify-backup-distsql", nil)
+	evalCtx := execCtx.ExtendedEvalContext()
+	var noTxn *kv.Txn
+
+	if len(specs) == 0 {
+		close(progCh)
+		return nil
+	}
+
+	// Setup a one-stage plan with one proc per input spec.
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, 0, len(specs))
+	for node, spec := range specs {
+		corePlacement = append(corePlacement, physicalplan.ProcessorCorePlacement{
+			NodeID: node,
+			Core:   execinfrapb.ProcessorCoreUnion{VerifyBackupData: spec},
+		})
+	}
+
+	p := planCtx.NewPhysicalPlan()
+	// All of the progress information is sent through the metadata stream, so we
+	// have an empty result stream.
+	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, []*types.T{}, execinfrapb.Ordering{})
+	p.PlanToStreamColMap = []int{}
+
+	dsp.FinalizePlan(planCtx, p)
+
+	metaFn := func(_ context.Context, meta *execinfrapb.ProducerMetadata) error {
+		if meta.BulkProcessorProgress != nil {
+			progCh <- meta.BulkProcessorProgress
+		}
+		return nil
+	}
+
+	rowResultWriter := sql.NewRowResultWriter(nil)
+	recv := sql.MakeDistSQLReceiver(
+		ctx,
+		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+		tree.Rows,
+		nil,   /* rangeCache */
+		noTxn, /* txn - the flow does not read or write the database */
+		nil,   /* clockUpdater */
+		evalCtx.Tracing,
+		evalCtx.ExecCfg.ContentionRegistry,
+		nil, /* testingPushCallback */
+	)
+	defer recv.Release()
+	defer close(progCh)
+
+	// Copy the evalCtx, as dsp.Run() might change it.
+	evalCtxCopy := *evalCtx
+	dsp.Run(planCtx, noTxn, p, recv, &evalCtxCopy, nil /* finishedSetupFn */)()
+	return rowResultWriter.Err()
+}
+
+// sanitizedBackupFileURI returns the URI of a file of the layer of a backup
+// stored at uri, without its credentials.
+func sanitizedBackupFileURI(uri string, file string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return file
+	}
+	parsed.Path = path.Join(parsed.Path, file)
+	sanitized, err := cloud.SanitizeExternalStorageURI(parsed.String(), nil /* extraParams */)
+	if err != nil {
+		return file
+	}
+	return sanitized
+}
+
+// ReportResults implements JobResultsReporter interface.
+func (r *verifyBackupResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	details := r.job.Details().(jobspb.VerifyBackupDetails)
+	progress := r.job.Progress().Details.(*jobspb.Progress_VerifyBackup).VerifyBackup
+	jobID := tree.NewDInt(tree.DInt(r.job.ID()))
+
+	send := func(row tree.Datums) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- row:
+			return nil
+		}
+	}
+
+	backupErr := tree.DNull
+	if n := len(progress.FileErrors); n > 0 {
+		backupErr = tree.NewDString(fmt.Sprintf("%d of %d files are missing or corrupt",
+			n, progress.FilesChecked))
+	}
+	backupName, err := cloud.SanitizeExternalStorageURI(details.URIs[0], nil /* extraParams */)
+	if err != nil {
+		return err
+	}
+	if err := send(tree.Datums{
+		jobID, tree.NewDString("backup"), tree.NewDString(backupName), tree.DNull, backupErr,
+	}); err != nil {
+		return err
+	}
+
+	for _, f := range progress.FileErrors {
+		if err := send(tree.Datums{
+			jobID, tree.NewDString("file"), tree.NewDString(f.Path), tree.DNull, tree.NewDString(f.Error),
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range progress.Fingerprints {
+		fingerprint := tree.DNull
+		if f.HashedRows > 0 {
+			fingerprint = tree.NewDString(strconv.FormatInt(f.Fingerprint, 10))
+		}
+		if err := send(tree.Datums{
+			jobID, tree.NewDString("index"), tree.NewDString(f.TableName + "@" + f.IndexName),
+			fingerprint, tree.DNull,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. A VERIFY BACKUP only
+// reads the backup, so there is nothing to clean up.
+func (r *verifyBackupResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook(verifyBackupPlanHook)
+	jobs.RegisterConstructor(
+		jobspb.TypeVerifyBackup,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &verifyBackupResumer{
+				job: job,
+			}
+		},
+	)
+}
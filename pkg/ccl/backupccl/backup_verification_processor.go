@@ -0,0 +1,537 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+var verifyBackupOutputTypes = []*types.T{}
+
+const verifyBackupProcessorName = "verifyBackupDataProcessor"
+
+// verifyBackupDataProcessor represents the work each node in a cluster
+// performs during a VERIFY BACKUP. It reads each of the files it is assigned
+// in full, and then fingerprints the indexes of the tables in the entries it is
+// assigned. It streams back its progress through the metadata channel provided
+// by DistSQL.
+type verifyBackupDataProcessor struct {
+	execinfra.ProcessorBase
+
+	flowCtx *execinfra.FlowCtx
+	spec    execinfrapb.VerifyBackupDataSpec
+	output  execinfra.RowReceiver
+
+	// cancelAndWaitForWorker cancels the producer goroutine and waits for it to
+	// finish. It can be called multiple times.
+	cancelAndWaitForWorker func()
+	progCh                 chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
+	verifyErr              error
+}
+
+var _ execinfra.Processor = &verifyBackupDataProcessor{}
+var _ execinfra.RowSource = &verifyBackupDataProcessor{}
+
+func newVerifyBackupDataProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.VerifyBackupDataSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	vp := &verifyBackupDataProcessor{
+		flowCtx: flowCtx,
+		spec:    spec,
+		output:  output,
+		progCh:  make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress),
+	}
+	if err := vp.Init(vp, post, verifyBackupOutputTypes, flowCtx, processorID, output,
+		nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				vp.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return vp, nil
+}
+
+// Start is part of the RowSource interface.
+func (vp *verifyBackupDataProcessor) Start(ctx context.Context) {
+	ctx = vp.StartInternal(ctx, verifyBackupProcessorName)
+	ctx, cancel := context.WithCancel(ctx)
+	vp.cancelAndWaitForWorker = func() {
+		cancel()
+		for range vp.progCh {
+		}
+	}
+	if err := vp.flowCtx.Stopper().RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "verify-backup-worker",
+		SpanOpt:  stop.ChildSpan,
+	}, func(ctx context.Context) {
+		vp.verifyErr = runVerifyBackupProcessor(ctx, vp.flowCtx, &vp.spec, vp.progCh)
+		cancel()
+		close(vp.progCh)
+	}); err != nil {
+		// The closure above hasn't run, so we have to do the cleanup.
+		vp.verifyErr = err
+		cancel()
+		close(vp.progCh)
+	}
+}
+
+// Next is part of the RowSource interface.
+func (vp *verifyBackupDataProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	if vp.State != execinfra.StateRunning {
+		return nil, vp.DrainHelper()
+	}
+
+	for prog := range vp.progCh {
+		// Take a copy so that we can send the progress address to the output
+		// processor.
+		p := prog
+		return nil, &execinfrapb.ProducerMetadata{BulkProcessorProgress: &p}
+	}
+
+	if vp.verifyErr != nil {
+		vp.MoveToDraining(vp.verifyErr)
+		return nil, vp.DrainHelper()
+	}
+
+	vp.MoveToDraining(nil /* error */)
+	return nil, vp.DrainHelper()
+}
+
+func (vp *verifyBackupDataProcessor) close() {
+	if vp.cancelAndWaitForWorker != nil {
+		vp.cancelAndWaitForWorker()
+	}
+	vp.ProcessorBase.InternalClose()
+}
+
+// ConsumerClosed is part of the RowSource interface. We have to override the
+// implementation provided by ProcessorBase.
+func (vp *verifyBackupDataProcessor) ConsumerClosed() {
+	vp.close()
+}
+
+func runVerifyBackupProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.VerifyBackupDataSpec,
+	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+) error {
+	sendProgress := func(progDetails VerifyBackupDataProgress) error {
+		var prog execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
+		details, err := gogotypes.MarshalAny(&progDetails)
+		if err != nil {
+			return err
+		}
+		prog.ProgressDetails = *details
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case progCh <- prog:
+			return nil
+		}
+	}
+
+	for _, f := range spec.Files {
+		size, err := verifyBackupFile(ctx, flowCtx, f, spec.Encryption)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		progDetails := VerifyBackupDataProgress{FilesChecked: 1, BytesChecked: size}
+		if err != nil {
+			log.Warningf(ctx, "backup file %s is missing or corrupt: %v", f.File.Path, err)
+			progDetails.FileErrors = map[int64]string{f.ID: err.Error()}
+		}
+		if err := sendProgress(progDetails); err != nil {
+			return err
+		}
+	}
+
+	if len(spec.Entries) == 0 {
+		return nil
+	}
+	fp, err := makeBackupFingerprinter(ctx, flowCtx, spec)
+	if err != nil {
+		return err
+	}
+	for _, entry := range spec.Entries {
+		fingerprints, err := fp.fingerprintEntry(ctx, entry)
+		if err != nil {
+			return errors.Wrapf(err, "fingerprinting span %s", entry.Span)
+		}
+		if err := sendProgress(VerifyBackupDataProgress{Fingerprints: fingerprints}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBackupFile reads every key and value of the file, and returns its size.
+// Reading the file verifies the checksums of its blocks and, if it is
+// encrypted, that it is authenticated by the encryption key. The checksums of
+// the values are verified too, as is that the keys are in the span the
+// manifest records for the file.
+func verifyBackupFile(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	f execinfrapb.VerifyBackupDataSpec_File,
+	encryption *roachpb.FileEncryptionOptions,
+) (int64, error) {
+	dir, err := flowCtx.Cfg.ExternalStorage(ctx, f.File.Dir)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := dir.Close(); err != nil {
+			log.Warningf(ctx, "close export storage failed %v", err)
+		}
+	}()
+
+	size, err := dir.Size(ctx, f.File.Path)
+	if err != nil {
+		return 0, err
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, dir, f.File.Path, encryption)
+	if err != nil {
+		return size, err
+	}
+	defer iter.Close()
+
+	for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return size, err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if !f.Span.ContainsKey(key.Key) {
+			return size, errors.Newf("key %s is outside of the span %s of the file", key.Key, f.Span)
+		}
+		v := roachpb.Value{RawBytes: iter.UnsafeValue()}
+		if err := v.Verify(key.Key); err != nil {
+			return size, err
+		}
+	}
+	return size, nil
+}
+
+// backupFingerprinter computes the fingerprints of the indexes of tables from
+// the files of a backup, like SHOW EXPERIMENTAL_FINGERPRINTS computes them from
+// the data of a table: the fnv64 hashes of the string representation of the
+// fingerprinted columns of each row are XORed together.
+type backupFingerprinter struct {
+	flowCtx    *execinfra.FlowCtx
+	evalCtx    *tree.EvalContext
+	encryption *roachpb.FileEncryptionOptions
+	tables     []catalog.TableDescriptor
+	alloc      tree.DatumAlloc
+
+	// virtualExprs maps the IDs of the tables with virtual columns to the
+	// computed expressions of their public columns, in order.
+	virtualExprs map[descpb.ID][]tree.TypedExpr
+}
+
+func makeBackupFingerprinter(
+	ctx context.Context, flowCtx *execinfra.FlowCtx, spec *execinfrapb.VerifyBackupDataSpec,
+) (*backupFingerprinter, error) {
+	typeDescs := make([]*descpb.TypeDescriptor, len(spec.Types))
+	for i := range spec.Types {
+		typeDescs[i] = &spec.Types[i]
+	}
+	resolver := newBackupTypeResolver(typeDescs)
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.TypeResolver = resolver
+
+	fp := &backupFingerprinter{
+		flowCtx:      flowCtx,
+		evalCtx:      flowCtx.NewEvalCtx(),
+		encryption:   spec.Encryption,
+		virtualExprs: make(map[descpb.ID][]tree.TypedExpr),
+	}
+	for i := range spec.Tables {
+		desc := protoutil.Clone(&spec.Tables[i]).(*descpb.TableDescriptor)
+		if err := typedesc.HydrateTypesInTableDescriptor(ctx, desc, resolver); err != nil {
+			return nil, err
+		}
+		table := tabledesc.NewBuilder(desc).BuildImmutableTable()
+		fp.tables = append(fp.tables, table)
+
+		// Virtual columns are not stored in the primary index, but SHOW
+		// EXPERIMENTAL_FINGERPRINTS hashes them, so they are computed from the
+		// stored columns of each row.
+		hasVirtual := false
+		for _, col := range table.PublicColumns() {
+			hasVirtual = hasVirtual || col.IsVirtual()
+		}
+		if !hasVirtual {
+			continue
+		}
+		exprs, _, err := schemaexpr.MakeComputedExprs(
+			ctx,
+			table.PublicColumns(),
+			table.PublicColumns(),
+			table,
+			tree.NewUnqualifiedTableName(tree.Name(table.GetName())),
+			fp.evalCtx,
+			&semaCtx,
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "computing virtual columns of table %s", table.GetName())
+		}
+		fp.virtualExprs[table.GetID()] = exprs
+	}
+	return fp, nil
+}
+
+// fingerprintEntry returns the partial fingerprints of the indexes whose rows
+// are in the span of the entry, as of the end time of the latest layer of the
+// backup.
+func (fp *backupFingerprinter) fingerprintEntry(
+	ctx context.Context, entry execinfrapb.RestoreSpanEntry,
+) ([]VerifyBackupDataProgress_IndexFingerprint, error) {
+	// The keys of the tables of the backup are encoded for the tenant that took
+	// it, which may not be the one verifying it.
+	_, tenantID, err := keys.DecodeTenantPrefix(entry.Span.Key)
+	if err != nil {
+		return nil, err
+	}
+	codec := keys.MakeSQLCodec(tenantID)
+
+	var iters []storage.SimpleMVCCIterator
+	var dirs []cloud.ExternalStorage
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+		for _, dir := range dirs {
+			if err := dir.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := fp.flowCtx.Cfg.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+		iter, err := storageccl.ExternalSSTReader(ctx, dir, file.Path, fp.encryption)
+		if err != nil {
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+	iter := storage.MakeMultiIterator(iters)
+	defer iter.Close()
+
+	var res []VerifyBackupDataProgress_IndexFingerprint
+	for _, table := range fp.tables {
+		for _, index := range table.ActiveIndexes() {
+			if index.GetType() != descpb.IndexDescriptor_FORWARD {
+				continue
+			}
+			prefix := codec.IndexPrefix(uint32(table.GetID()), uint32(index.GetID()))
+			sp := entry.Span.Intersect(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+			if !sp.Valid() {
+				continue
+			}
+			f, err := fp.fingerprintSpan(ctx, codec, table, index, sp, iter)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fingerprinting index %s of table %s",
+					index.GetName(), table.GetName())
+			}
+			if f.HashedRows > 0 {
+				res = append(res, f)
+			}
+		}
+	}
+	return res, nil
+}
+
+// fingerprintSpan fingerprints the rows of the index in the span, read from
+// the iterator.
+func (fp *backupFingerprinter) fingerprintSpan(
+	ctx context.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	index catalog.Index,
+	sp roachpb.Span,
+	iter storage.SimpleMVCCIterator,
+) (VerifyBackupDataProgress_IndexFingerprint, error) {
+	res := VerifyBackupDataProgress_IndexFingerprint{TableID: table.GetID(), IndexID: index.GetID()}
+
+	cols, err := fingerprintColumns(table, index)
+	if err != nil {
+		return res, err
+	}
+	args := row.FetcherTableArgs{
+		Desc:             table,
+		Index:            index,
+		IsSecondaryIndex: !index.Primary(),
+	}
+	args.InitCols(table, false /* withSystemColumns */, nil /* invertedColumn */)
+	for i, col := range args.Cols {
+		args.ColIdxMap.Set(col.GetID(), i)
+	}
+	ords := make([]int, len(cols))
+	var valNeededForCol util.FastIntSet
+	for i, col := range cols {
+		ords[i] = args.ColIdxMap.GetDefault(col.GetID())
+		if !col.IsVirtual() || !index.Primary() {
+			valNeededForCol.Add(ords[i])
+		}
+	}
+	args.ValNeededForCol = valNeededForCol
+
+	// The fingerprinted columns of a primary index are the public columns of
+	// the table, so its virtual columns are computed from the same row.
+	var virtualExprs []tree.TypedExpr
+	if index.Primary() {
+		virtualExprs = fp.virtualExprs[table.GetID()]
+	}
+	iv := &schemaexpr.RowIndexedVarContainer{
+		Cols:    table.PublicColumns(),
+		Mapping: args.ColIdxMap,
+	}
+	fp.evalCtx.PushIVarContainer(iv)
+	defer fp.evalCtx.PopIVarContainer()
+
+	var rf row.Fetcher
+	if err := rf.Init(
+		ctx,
+		codec,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0, /* lockTimeout */
+		&fp.alloc,
+		nil, /* memMonitor */
+		args,
+	); err != nil {
+		return res, err
+	}
+	// The layers of a backup only contain revisions up to its end time, so the
+	// newest revision of each key is the one as of the end time.
+	kvFetcher := row.MakeBackupSSTKVFetcher(
+		storage.MVCCKey{Key: sp.Key}, storage.MVCCKey{Key: sp.EndKey}, iter,
+		hlc.Timestamp{} /* startTime */, hlc.Timestamp{} /* endTime */, false, /* withRev */
+	)
+	if err := rf.StartScanFrom(ctx, &kvFetcher, false /* traceKV */); err != nil {
+		return res, err
+	}
+
+	h := fnv.New64()
+	for {
+		datums, _, _, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return res, err
+		}
+		if datums == nil {
+			break
+		}
+		iv.CurSourceRow = datums
+		// Like fnv64, NULLs are skipped, and rows with only NULLs do not
+		// contribute to the fingerprint.
+		h.Reset()
+		var hashed bool
+		for i, ord := range ords {
+			d := datums[ord]
+			if virtualExprs != nil && cols[i].IsVirtual() {
+				if d, err = virtualExprs[i].Eval(fp.evalCtx); err != nil {
+					return res, err
+				}
+			}
+			if d == tree.DNull {
+				continue
+			}
+			hashed = true
+			if cols[i].GetType().Family() == types.BytesFamily {
+				_, _ = h.Write([]byte(*d.(*tree.DBytes)))
+				continue
+			}
+			s, err := tree.PerformCast(fp.evalCtx, d, types.String)
+			if err != nil {
+				return res, err
+			}
+			_, _ = h.Write([]byte(tree.MustBeDString(s)))
+		}
+		if hashed {
+			res.Fingerprint ^= int64(h.Sum64())
+			res.HashedRows++
+		}
+	}
+	return res, nil
+}
+
+// fingerprintColumns returns the columns of the rows of the index that are
+// fingerprinted, in the order SHOW EXPERIMENTAL_FINGERPRINTS hashes them. The
+// virtual columns of a primary index are not stored in it, so they must be
+// computed from the other columns of its rows.
+func fingerprintColumns(table catalog.TableDescriptor, index catalog.Index) ([]catalog.Column, error) {
+	var cols []catalog.Column
+	if index.Primary() {
+		return table.PublicColumns(), nil
+	}
+	var colIDs []descpb.ColumnID
+	for i := 0; i < index.NumKeyColumns(); i++ {
+		colIDs = append(colIDs, index.GetKeyColumnID(i))
+	}
+	for i := 0; i < index.NumKeySuffixColumns(); i++ {
+		colIDs = append(colIDs, index.GetKeySuffixColumnID(i))
+	}
+	for i := 0; i < index.NumSecondaryStoredColumns(); i++ {
+		colIDs = append(colIDs, index.GetStoredColumnID(i))
+	}
+	for _, id := range colIDs {
+		col, err := table.FindColumnWithID(id)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func init() {
+	rowexec.NewVerifyBackupDataProcessor = newVerifyBackupDataProcessor
+}
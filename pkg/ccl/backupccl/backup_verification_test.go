@@ -0,0 +1,154 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	gosql "database/sql"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	tc, sqlDB, dir, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = LocalFoo + "/verify"
+
+	sqlDB.Exec(t, `CREATE INDEX balance_idx ON data.bank (balance)`)
+	sqlDB.Exec(t, `BACKUP data.bank INTO $1`, collection)
+	sqlDB.Exec(t, `INSERT INTO data.bank VALUES (100, 100, 'new')`)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 5`)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = 3`)
+	sqlDB.Exec(t, `BACKUP data.bank INTO LATEST IN $1`, collection)
+
+	// The fingerprints of the backup are those of the table as of its end time.
+	var expected [][]string
+	for _, row := range sqlDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.bank`) {
+		expected = append(expected, []string{"data.public.bank@" + row[0], row[1]})
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i][0] < expected[j][0] })
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = 4`)
+
+	verify := func(t *testing.T) (backupErr string, fileErrs []string, fingerprints [][]string) {
+		for _, row := range sqlDB.QueryStr(t, `VERIFY BACKUP LATEST IN $1 WITH fingerprint`, collection) {
+			switch row[1] {
+			case "backup":
+				backupErr = row[4]
+			case "file":
+				fileErrs = append(fileErrs, row[2])
+			case "index":
+				fingerprints = append(fingerprints, []string{row[2], row[3]})
+			}
+		}
+		sort.Slice(fingerprints, func(i, j int) bool { return fingerprints[i][0] < fingerprints[j][0] })
+		return backupErr, fileErrs, fingerprints
+	}
+
+	t.Run("intact", func(t *testing.T) {
+		backupErr, fileErrs, fingerprints := verify(t)
+		require.Equal(t, "NULL", backupErr)
+		require.Empty(t, fileErrs)
+		require.Equal(t, expected, fingerprints)
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `VERIFY BACKUP LATEST IN $1 WITH detached`, collection).Scan(&jobID)
+		waitForSuccessfulJob(t, tc, jobID)
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		// Corrupt a file of the backup and remove another one, from any layer.
+		var ssts []string
+		require.NoError(t, filepath.Walk(filepath.Join(dir, "foo", "verify"),
+			func(p string, _ os.FileInfo, err error) error {
+				if err == nil && strings.HasSuffix(p, ".sst") {
+					ssts = append(ssts, p)
+				}
+				return err
+			}))
+		require.GreaterOrEqual(t, len(ssts), 2)
+		sort.Slice(ssts, func(i, j int) bool { return filepath.Base(ssts[i]) < filepath.Base(ssts[j]) })
+		require.NoError(t, os.WriteFile(ssts[0], []byte("not an sst"), 0644))
+		require.NoError(t, os.Remove(ssts[1]))
+
+		backupErr, fileErrs, fingerprints := verify(t)
+		require.True(t, strings.HasPrefix(backupErr, "2 of "), backupErr)
+		require.Len(t, fileErrs, 2)
+		sort.Slice(fileErrs, func(i, j int) bool { return path.Base(fileErrs[i]) < path.Base(fileErrs[j]) })
+		for i, sst := range ssts[:2] {
+			require.Equal(t, filepath.Base(sst), path.Base(fileErrs[i]))
+		}
+		// The indexes are not fingerprinted when files are missing.
+		require.Empty(t, fingerprints)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `invalid option "revision_history"`,
+			`VERIFY BACKUP LATEST IN $1 WITH revision_history`, collection)
+		tx, err := sqlDB.DB.(*gosql.DB).Begin()
+		require.NoError(t, err)
+		_, err = tx.Exec(`VERIFY BACKUP LATEST IN $1`, collection)
+		require.Regexp(t, `cannot be used inside a transaction without DETACHED option`, err)
+		require.NoError(t, tx.Rollback())
+	})
+}
+
+func TestVerifyBackupVirtualColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, 0 /* numAccounts */, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = LocalFoo + "/verify-virtual"
+
+	sqlDB.Exec(t, `CREATE TYPE data.status AS ENUM ('open', 'closed')`)
+	sqlDB.Exec(t, `CREATE TABLE data.t (
+  k INT PRIMARY KEY,
+  v INT,
+  s data.status,
+  w INT AS (k + v) VIRTUAL,
+  c BOOL AS (s = 'closed') VIRTUAL,
+  INDEX w_idx (w),
+  INDEX c_idx (c) STORING (v)
+)`)
+	sqlDB.Exec(t, `INSERT INTO data.t (k, v, s) VALUES (1, 10, 'open'), (2, NULL, 'closed'), (3, 30, NULL)`)
+	sqlDB.Exec(t, `BACKUP data.t INTO $1`, collection)
+
+	// The fingerprint of the primary index includes its virtual columns, like
+	// the one of SHOW EXPERIMENTAL_FINGERPRINTS.
+	var expected [][]string
+	for _, row := range sqlDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.t`) {
+		expected = append(expected, []string{"data.public.t@" + row[0], row[1]})
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i][0] < expected[j][0] })
+
+	var fingerprints [][]string
+	for _, row := range sqlDB.QueryStr(t, `VERIFY BACKUP LATEST IN $1 WITH fingerprint`, collection) {
+		if row[1] == "index" {
+			fingerprints = append(fingerprints, []string{row[2], row[3]})
+		}
+	}
+	sort.Slice(fingerprints, func(i, j int) bool { return fingerprints[i][0] < fingerprints[j][0] })
+	require.Equal(t, expected, fingerprints)
+}
@@ -572,8 +572,10 @@ func DescriptorsMatchingTargets(
 	}
 
 	// Then process the database expansions.
+	fullyExpandedDBs := make(map[descpb.ID]struct{})
 	for dbID := range alreadyExpandedDBs {
 		if requestedSchemas, ok := alreadyRequestedSchemasByDBs[dbID]; !ok {
+			fullyExpandedDBs[dbID] = struct{}{}
 			for schemaName, schemas := range r.ObjsByName[dbID] {
 				schemaID, err := getSchemaIDByName(schemaName, dbID)
 				if err != nil {
@@ -596,6 +598,23 @@ func DescriptorsMatchingTargets(
 		}
 	}
 
+	// User-defined functions are only included when their whole database is
+	// matched, since the body of a function may reference any object in its
+	// database.
+	for _, desc := range descriptors {
+		fn, ok := desc.(catalog.FunctionDescriptor)
+		if !ok {
+			continue
+		}
+		if _, ok := fullyExpandedDBs[fn.GetParentID()]; !ok {
+			continue
+		}
+		if err := catalog.FilterDescriptorState(fn, tree.CommonLookupFlags{}); err != nil {
+			continue
+		}
+		ret.Descs = append(ret.Descs, fn)
+	}
+
 	return ret, nil
 }
 
@@ -1010,6 +1010,11 @@ func loadSQLDescsFromBackupsAtTime(
 				continue
 			}
 			isObject = true
+		case catalog.FunctionDescriptor:
+			if d.Dropped() {
+				continue
+			}
+			isObject = true
 		case catalog.TypeDescriptor, catalog.SchemaDescriptor:
 			isObject = true
 		}
@@ -35,6 +35,9 @@ type restorationData interface {
 	// included in this bundle.
 	getRekeys() []execinfrapb.TableRekey
 	getPKIDs() map[uint64]bool
+	// getRowFilter returns the filter of the rows to restore of a RESTORE ...
+	// WHERE, or nil if all the rows are restored.
+	getRowFilter() *execinfrapb.RestoreRowFilter
 
 	// addTenant extends the set of data needed to restore to include a new tenant.
 	addTenant(roachpb.TenantID)
@@ -71,6 +74,9 @@ type restorationDataBase struct {
 	// systemTables store the system tables that need to be restored for cluster
 	// backups. Should be nil otherwise.
 	systemTables []catalog.TableDescriptor
+	// rowFilter restricts the rows of the restored table to those matching the
+	// predicate of a RESTORE ... WHERE. Should be nil otherwise.
+	rowFilter *execinfrapb.RestoreRowFilter
 }
 
 // restorationDataBase implements restorationData.
@@ -86,6 +92,11 @@ func (b *restorationDataBase) getPKIDs() map[uint64]bool {
 	return b.pkIDs
 }
 
+// getRowFilter implements restorationData.
+func (b *restorationDataBase) getRowFilter() *execinfrapb.RestoreRowFilter {
+	return b.rowFilter
+}
+
 // getSpans implements restorationData.
 func (b *restorationDataBase) getSpans() []roachpb.Span {
 	return b.spans
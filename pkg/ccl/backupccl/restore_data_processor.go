@@ -119,6 +119,7 @@ func newRestoreDataProcessor(
 	output execinfra.RowReceiver,
 ) (execinfra.Processor, error) {
 	sv := &flowCtx.Cfg.Settings.SV
+	memMonitor := execinfra.NewMonitor(flowCtx.EvalCtx.Ctx(), flowCtx.EvalCtx.Mon, "restore-data-mem")
 
 	rd := &restoreDataProcessor{
 		flowCtx:    flowCtx,
@@ -137,7 +138,7 @@ func newRestoreDataProcessor(
 		return nil, err
 	}
 
-	if err := rd.Init(rd, post, restoreDataOutputTypes, flowCtx, processorID, output, nil, /* memMonitor */
+	if err := rd.Init(rd, post, restoreDataOutputTypes, flowCtx, processorID, output, memMonitor,
 		execinfra.ProcStateOpts{
 			InputsToDrain: []execinfra.RowSource{input},
 			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
@@ -410,6 +411,15 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 	}
 	defer batcher.Close()
 
+	if rd.spec.RowFilter != nil {
+		// RESTORE ... WHERE only restores the rows matching its predicate, and
+		// regenerates the entries of their secondary indexes.
+		if err := rd.restoreFilteredSpanEntry(ctx, entry, iter, batcher); err != nil {
+			return summary, err
+		}
+		return rd.finishRestoreSpanEntry(ctx, batcher)
+	}
+
 	var keyScratch, valueScratch []byte
 
 	startKeyMVCC, endKeyMVCC := storage.MVCCKey{Key: entry.Span.Key},
@@ -472,9 +482,17 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 			return summary, errors.Wrapf(err, "adding to batch: %s -> %s", key, value.PrettyPrint())
 		}
 	}
+	return rd.finishRestoreSpanEntry(ctx, batcher)
+}
+
+// finishRestoreSpanEntry flushes the last batch of the data of a restore span
+// entry and returns the summary of what was ingested.
+func (rd *restoreDataProcessor) finishRestoreSpanEntry(
+	ctx context.Context, batcher *bulk.SSTBatcher,
+) (roachpb.BulkOpSummary, error) {
 	// Flush out the last batch.
 	if err := batcher.Flush(ctx); err != nil {
-		return summary, err
+		return roachpb.BulkOpSummary{}, err
 	}
 
 	if restoreKnobs, ok := rd.flowCtx.TestingKnobs().BackupRestoreTestingKnobs.(*sql.BackupRestoreTestingKnobs); ok {
@@ -540,6 +558,7 @@ func (rd *restoreDataProcessor) ConsumerClosed() {
 			sst.cleanup()
 		}
 	}
+	rd.MemMonitor.Stop(rd.Ctx)
 	rd.InternalClose()
 }
 
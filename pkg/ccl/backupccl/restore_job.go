@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
@@ -27,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -36,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -44,6 +47,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
@@ -113,6 +118,7 @@ func WriteDescriptors(
 	schemas []catalog.SchemaDescriptor,
 	tables []catalog.TableDescriptor,
 	types []catalog.TypeDescriptor,
+	functions []catalog.FunctionDescriptor,
 	descCoverage tree.DescriptorCoverage,
 	extra []roachpb.KeyValue,
 ) error {
@@ -228,6 +234,29 @@ func WriteDescriptors(
 			b.CPut(catalogkeys.EncodeNameKey(codec, typ), typ.GetID(), nil)
 		}
 
+		// Write all function descriptors. Functions do not have namespace
+		// entries; they are referenced by their parent schema instead.
+		for i := range functions {
+			fn := functions[i]
+			updatedPrivileges, err := getRestoringPrivileges(ctx, codec, txn, fn, user, wroteDBs, descCoverage)
+			if err != nil {
+				return err
+			}
+			if updatedPrivileges != nil {
+				if mut, ok := fn.(*funcdesc.Mutable); ok {
+					mut.Privileges = updatedPrivileges
+				} else {
+					log.Fatalf(ctx, "wrong type for function %d, %T, expected Mutable",
+						fn.GetID(), fn)
+				}
+			}
+			if err := descsCol.WriteDescToBatch(
+				ctx, false /* kvTrace */, fn.(catalog.MutableDescriptor), b,
+			); err != nil {
+				return err
+			}
+		}
+
 		for _, kv := range extra {
 			b.InitPut(kv.Key, &kv.Value, false)
 		}
@@ -535,6 +564,7 @@ func restore(
 			dataToRestore.getPKIDs(),
 			encryption,
 			dataToRestore.getRekeys(),
+			dataToRestore.getRowFilter(),
 			endTime,
 			progCh,
 		)
@@ -630,6 +660,10 @@ type restoreResumer struct {
 		// afterPreRestore runs on cluster restores after restoring the "preRestore"
 		// data.
 		afterPreRestore func() error
+		// afterInsertingIntoExistingTableBatch is called after each transaction
+		// inserting a batch of rows of the staging table of a restore into an
+		// existing table but the last one commits.
+		afterInsertingIntoExistingTableBatch func() error
 	}
 }
 
@@ -790,7 +824,7 @@ func spansForAllRestoreTableIndexes(
 		// entire interval. DROPPED tables should never later become PUBLIC.
 		// TODO(pbardea): Consider and test the interaction between revision_history
 		// backups and OFFLINE tables.
-		rawTbl, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTbl, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTbl != nil && !rawTbl.Dropped() {
 			tbl := tabledesc.NewBuilder(rawTbl).BuildImmutableTable()
 			// We only import spans for physical tables.
@@ -844,6 +878,7 @@ func createImportingDescriptors(
 	var writtenTypes []catalog.TypeDescriptor
 	var schemas []*schemadesc.Mutable
 	var types []*typedesc.Mutable
+	var functions []*funcdesc.Mutable
 	// Store the tables as both the concrete mutable structs and the interface
 	// to deal with the lack of slice covariance in go. We want the slice of
 	// mutable descriptors for rewriting but ultimately want to return the
@@ -882,6 +917,12 @@ func createImportingDescriptors(
 		case catalog.TypeDescriptor:
 			mut := typedesc.NewBuilder(desc.TypeDesc()).BuildCreatedMutableType()
 			types = append(types, mut)
+		case catalog.FunctionDescriptor:
+			// Functions are only restored along with their parent database.
+			if _, ok := details.DescriptorRewrites[desc.GetID()]; ok {
+				mut := funcdesc.NewBuilder(desc.FuncDesc()).BuildCreatedMutableFunction()
+				functions = append(functions, mut)
+			}
 		}
 	}
 
@@ -978,6 +1019,14 @@ func createImportingDescriptors(
 		return nil, nil, err
 	}
 
+	if err := rewriteFunctionDescs(functions, details.DescriptorRewrites); err != nil {
+		return nil, nil, err
+	}
+	writtenFunctions := make([]catalog.FunctionDescriptor, len(functions))
+	for i := range functions {
+		writtenFunctions[i] = functions[i]
+	}
+
 	// Set the new descriptors' states to offline.
 	for _, desc := range mutableTables {
 		desc.SetOffline("restoring")
@@ -988,6 +1037,9 @@ func createImportingDescriptors(
 	for _, desc := range schemasToWrite {
 		desc.SetOffline("restoring")
 	}
+	for _, desc := range functions {
+		desc.SetOffline("restoring")
+	}
 	for _, desc := range mutableDatabases {
 		desc.SetOffline("restoring")
 	}
@@ -1079,7 +1131,7 @@ func createImportingDescriptors(
 			// Write the new descriptors which are set in the OFFLINE state.
 			if err := WriteDescriptors(
 				ctx, p.ExecCfg().Codec, txn, p.User(), descsCol, databases, writtenSchemas, tables, writtenTypes,
-				details.DescriptorCoverage, nil, /* extra */
+				writtenFunctions, details.DescriptorCoverage, nil, /* extra */
 			); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors from %d databases", len(tables), len(databases))
 			}
@@ -1236,6 +1288,10 @@ func createImportingDescriptors(
 			for i := range schemasToWrite {
 				details.SchemaDescs[i] = schemasToWrite[i].SchemaDesc()
 			}
+			details.FunctionDescs = make([]*descpb.FunctionDescriptor, len(functions))
+			for i := range functions {
+				details.FunctionDescs[i] = functions[i].FuncDesc()
+			}
 
 			// Update the job once all descs have been prepared for ingestion.
 			err := r.job.SetDetails(ctx, txn, details)
@@ -1505,6 +1561,12 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		mainData.addTenant(roachpb.MakeTenantID(tenant.ID))
 	}
 
+	if details.RowFilter != "" {
+		if mainData.rowFilter, err = makeRestoreRowFilter(ctx, r.execCfg, details); err != nil {
+			return err
+		}
+	}
+
 	numNodes, err := clusterNodeCount(p.ExecCfg().Gossip)
 	if err != nil {
 		if !build.IsRelease() && p.ExecCfg().Codec.ForSystemTenant() {
@@ -1569,8 +1631,12 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		resTotal.add(res)
 	}
 
-	if err := insertStats(ctx, r.job, p.ExecCfg(), remappedStats); err != nil {
-		return errors.Wrap(err, "inserting table statistics")
+	// The statistics of a table restored into an existing table are not those
+	// of the existing table.
+	if details.ExistingTableID == descpb.InvalidID {
+		if err := insertStats(ctx, r.job, p.ExecCfg(), remappedStats); err != nil {
+			return errors.Wrap(err, "inserting table statistics")
+		}
 	}
 
 	var devalidateIndexes map[descpb.ID][]descpb.IndexID
@@ -1614,6 +1680,14 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		}
 	}
 
+	if details.ExistingTableID != descpb.InvalidID {
+		if err := r.insertIntoExistingTable(ctx, p.ExecCfg(), details); err != nil {
+			return err
+		}
+		// Reload the details as we may have updated the job.
+		details = r.job.Details().(jobspb.RestoreDetails)
+	}
+
 	if fn := r.testingKnobs.afterPublishingDescriptors; fn != nil {
 		if err := fn(); err != nil {
 			return err
@@ -1793,6 +1867,248 @@ func insertStats(
 	return nil
 }
 
+// restoreIntoExistingTableBatchSize is the number of rows of the staging table
+// of a RESTORE ... WITH into_existing_table which are inserted into the
+// existing table per transaction.
+var restoreIntoExistingTableBatchSize = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"bulkio.restore.into_existing_table.batch_size",
+	"the number of rows inserted per transaction by a restore into an existing table",
+	1000,
+	settings.PositiveInt,
+)
+
+// insertIntoExistingTable inserts the rows of the staging table of a RESTORE
+// ... WITH into_existing_table into the existing table, and drops the staging
+// table. The rows are inserted in batches in the order of the primary key of
+// the staging table, each in its own transaction, which also checkpoints the
+// key from which the next batch starts in the progress of the job, so that a
+// resumed job continues from there. The staging table is dropped, and it and
+// its row filter are removed from the job, in the transaction of the last
+// batch, so that the table is neither restored nor inserted from again if the
+// job is resumed, nor dropped again if the job then fails.
+//
+// If the insertion of a batch fails, the batches before it remain in the
+// existing table, and the staging table is dropped as the job fails.
+func (r *restoreResumer) insertIntoExistingTable(
+	ctx context.Context, execCfg *sql.ExecutorConfig, details jobspb.RestoreDetails,
+) error {
+	if len(details.TableDescs) == 0 {
+		return nil
+	}
+	staging := tabledesc.NewBuilder(details.TableDescs[0]).BuildImmutableTable()
+	span := staging.PrimaryIndexSpan(execCfg.Codec)
+	progress := *r.job.Progress().Details.(*jobspb.Progress_Restore).Restore
+	if progress.ExistingTableResumeKey != nil {
+		span.Key = progress.ExistingTableResumeKey
+	}
+	batchSize := restoreIntoExistingTableBatchSize.Get(&execCfg.Settings.SV)
+	for {
+		var resumeKey roachpb.Key
+		if err := sql.DescsTxn(ctx, execCfg, func(
+			ctx context.Context, txn *kv.Txn, descsCol *descs.Collection,
+		) error {
+			var err error
+			resumeKey, err = insertIntoExistingTableBatch(
+				ctx, execCfg, txn, descsCol, details, span, batchSize,
+			)
+			if err != nil {
+				return err
+			}
+			if resumeKey == nil {
+				if err := r.dropStagingTable(ctx, execCfg, txn, descsCol, details); err != nil {
+					return err
+				}
+				details.TableDescs = nil
+				details.RowFilter = ""
+				return r.job.SetDetails(ctx, txn, details)
+			}
+			progress.ExistingTableResumeKey = resumeKey
+			return r.job.SetProgress(ctx, txn, progress)
+		}); err != nil {
+			return err
+		}
+		if resumeKey == nil {
+			return nil
+		}
+		span.Key = resumeKey
+		if fn := r.testingKnobs.afterInsertingIntoExistingTableBatch; fn != nil {
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// insertIntoExistingTableBatch inserts at most batchSize rows of the staging
+// table of a RESTORE ... WITH into_existing_table, from the start of the given
+// span of its primary index, into the existing table. The staging table is
+// OFFLINE, so its rows are read from its primary index directly rather than
+// with SQL. The key of the first row which is not inserted is returned, or nil
+// if there is none left.
+func insertIntoExistingTableBatch(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	details jobspb.RestoreDetails,
+	span roachpb.Span,
+	batchSize int64,
+) (roachpb.Key, error) {
+	staging, err := descsCol.GetImmutableTableByID(
+		ctx, txn, details.TableDescs[0].GetID(), tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				Required:       true,
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	cols := staging.PublicColumns()
+	args := row.FetcherTableArgs{
+		Desc:  staging,
+		Index: staging.GetPrimaryIndex(),
+		Cols:  cols,
+	}
+	for i, col := range cols {
+		args.ColIdxMap.Set(col.GetID(), i)
+	}
+	names := make(tree.NameList, len(details.ColumnIDs))
+	colIdxs := make([]int, len(details.ColumnIDs))
+	for i, colID := range details.ColumnIDs {
+		idx, ok := args.ColIdxMap.Get(colID)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"column %d of staging table %s is not public", colID, staging.GetName())
+		}
+		names[i] = cols[idx].ColName()
+		colIdxs[i] = idx
+		args.ValNeededForCol.Add(idx)
+	}
+
+	var alloc tree.DatumAlloc
+	var rf row.Fetcher
+	if err := rf.Init(
+		ctx,
+		execCfg.Codec,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0, /* lockTimeout */
+		&alloc,
+		nil, /* memMonitor */
+		args,
+	); err != nil {
+		return nil, err
+	}
+	defer rf.Close(ctx)
+	if err := rf.StartScan(
+		ctx,
+		txn,
+		roachpb.Spans{span},
+		rowinfra.DefaultBatchBytesLimit,
+		rowinfra.RowLimit(batchSize),
+		false, /* traceKV */
+		false, /* forceProductionKVBatchSize */
+	); err != nil {
+		return nil, err
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "INSERT INTO [%d AS t] (%s) VALUES ",
+		details.ExistingTableID, tree.AsString(&names))
+	var qargs []interface{}
+	for numRows := int64(0); numRows < batchSize; numRows++ {
+		datums, _, _, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if datums == nil {
+			break
+		}
+		if numRows > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		for i, idx := range colIdxs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			qargs = append(qargs, datums[idx])
+			fmt.Fprintf(&buf, "$%d", len(qargs))
+		}
+		buf.WriteByte(')')
+	}
+	// The key following the last row read is that of the first row of the next
+	// batch.
+	resumeKey := rf.Key()
+	if resumeKey != nil {
+		resumeKey = append(roachpb.Key(nil), resumeKey...)
+	}
+	if len(qargs) == 0 {
+		return nil, nil
+	}
+	if _, err := execCfg.InternalExecutor.Exec(
+		ctx, "restore-into-existing-table", txn, buf.String(), qargs...,
+	); err != nil {
+		return nil, errors.Wrapf(err, "inserting the restored rows into the existing table")
+	}
+	return resumeKey, nil
+}
+
+// dropStagingTable drops the OFFLINE staging table of a RESTORE ... WITH
+// into_existing_table once its rows have been inserted into the existing
+// table. Like the tables dropped when a restore fails, its data was never
+// visible to users, so it is garbage collected immediately.
+func (r *restoreResumer) dropStagingTable(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	details jobspb.RestoreDetails,
+) error {
+	staging, err := descsCol.GetMutableTableVersionByID(ctx, details.TableDescs[0].GetID(), txn)
+	if err != nil {
+		return err
+	}
+	b := txn.NewBatch()
+	if err := r.removeExistingTypeBackReferences(
+		ctx, txn, descsCol, b, []*tabledesc.Mutable{staging}, &details,
+	); err != nil {
+		return err
+	}
+	const dropTime = int64(1)
+	staging.SetDropped()
+	staging.DropTime = dropTime
+	b.Del(catalogkeys.EncodeNameKey(execCfg.Codec, staging))
+	descsCol.AddDeletedDescriptor(staging)
+	if err := descsCol.WriteDescToBatch(ctx, false /* kvTrace */, staging, b); err != nil {
+		return errors.Wrap(err, "writing dropping staging table to batch")
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return errors.Wrapf(err, "dropping staging table %s", staging.GetName())
+	}
+
+	gcJobRecord := jobs.Record{
+		Description:   fmt.Sprintf("GC for %s", r.job.Payload().Description),
+		Username:      r.job.Payload().UsernameProto.Decode(),
+		DescriptorIDs: descpb.IDs{staging.GetID()},
+		Details: jobspb.SchemaChangeGCDetails{
+			Tables: []jobspb.SchemaChangeGCDetails_DroppedID{{
+				ID:       staging.GetID(),
+				DropTime: dropTime,
+			}},
+		},
+		Progress:      jobspb.SchemaChangeGCProgress{},
+		NonCancelable: true,
+	}
+	jr := execCfg.JobRegistry
+	_, err = jr.CreateJobWithTxn(ctx, gcJobRecord, jr.MakeJobID(), txn)
+	return err
+}
+
 // publishDescriptors updates the RESTORED descriptors' status from OFFLINE to
 // PUBLIC. The schema change jobs are returned to be started after the
 // transaction commits. The details struct is passed in rather than loaded
@@ -1818,11 +2134,13 @@ func (r *restoreResumer) publishDescriptors(
 	// Write the new descriptors and flip state over to public so they can be
 	// accessed.
 	allMutDescs := make([]catalog.MutableDescriptor, 0,
-		len(details.TableDescs)+len(details.TypeDescs)+len(details.SchemaDescs)+len(details.DatabaseDescs))
+		len(details.TableDescs)+len(details.TypeDescs)+len(details.SchemaDescs)+
+			len(details.FunctionDescs)+len(details.DatabaseDescs))
 	// Create slices of raw descriptors for the restore job details.
 	newTables := make([]*descpb.TableDescriptor, 0, len(details.TableDescs))
 	newTypes := make([]*descpb.TypeDescriptor, 0, len(details.TypeDescs))
 	newSchemas := make([]*descpb.SchemaDescriptor, 0, len(details.SchemaDescs))
+	newFunctions := make([]*descpb.FunctionDescriptor, 0, len(details.FunctionDescs))
 	newDBs := make([]*descpb.DatabaseDescriptor, 0, len(details.DatabaseDescs))
 	checkVersion := func(read catalog.Descriptor, exp descpb.DescriptorVersion) error {
 		if read.GetVersion() == exp {
@@ -1857,8 +2175,11 @@ func (r *restoreResumer) publishDescriptors(
 		newTables = append(newTables, mutTable.TableDesc())
 		// For cluster restores, all the jobs are restored directly from the jobs
 		// table, so there is no need to re-create ongoing schema change jobs,
-		// otherwise we'll create duplicate jobs.
-		if details.DescriptorCoverage != tree.AllDescriptors || len(badIndexes) > 0 {
+		// otherwise we'll create duplicate jobs. The staging table of a restore
+		// into an existing table is dropped once its rows are inserted, so its
+		// mutations are not resumed either.
+		if (details.DescriptorCoverage != tree.AllDescriptors || len(badIndexes) > 0) &&
+			details.ExistingTableID == descpb.InvalidID {
 			// Convert any mutations that were in progress on the table descriptor
 			// when the backup was taken, and convert them to schema change jobs.
 			if err := createSchemaChangeJobsFromMutations(ctx,
@@ -1900,6 +2221,23 @@ func (r *restoreResumer) publishDescriptors(
 		allMutDescs = append(allMutDescs, mutSchema)
 		newSchemas = append(newSchemas, mutSchema.SchemaDesc())
 	}
+	for _, fnDesc := range details.FunctionDescs {
+		mutFn, err := descsCol.GetMutableFunctionByID(ctx, txn, fnDesc.ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				Required:       true,
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(mutFn, fnDesc.Version); err != nil {
+			return err
+		}
+		allMutDescs = append(allMutDescs, mutFn)
+		newFunctions = append(newFunctions, mutFn.FuncDesc())
+	}
 	for _, dbDesc := range details.DatabaseDescs {
 		// Jobs started before 20.2 upgrade finalization don't put databases in
 		// an offline state.
@@ -1923,7 +2261,12 @@ func (r *restoreResumer) publishDescriptors(
 	}
 	b := txn.NewBatch()
 	for _, desc := range allMutDescs {
-		desc.SetPublic()
+		// The staging table of a restore into an existing table stays OFFLINE
+		// until its rows have been inserted into the existing table and it is
+		// dropped, so that it can't be used in the meantime.
+		if details.ExistingTableID == descpb.InvalidID || desc.DescriptorType() != catalog.Table {
+			desc.SetPublic()
+		}
 		if err := descsCol.WriteDescToBatch(
 			ctx, false /* kvTrace */, desc, b,
 		); err != nil {
@@ -1946,6 +2289,7 @@ func (r *restoreResumer) publishDescriptors(
 	details.TableDescs = newTables
 	details.TypeDescs = newTypes
 	details.SchemaDescs = newSchemas
+	details.FunctionDescs = newFunctions
 	details.DatabaseDescs = newDBs
 	if err := r.job.SetDetails(ctx, txn, details); err != nil {
 		return errors.Wrap(err,
@@ -2127,6 +2471,28 @@ func (r *restoreResumer) dropDescriptors(
 		descsCol.AddDeletedDescriptor(mutType)
 	}
 
+	// Drop the function descriptors that this restore created. Functions have
+	// no data or namespace entries, so they are deleted directly.
+	for i := range details.FunctionDescs {
+		fnDesc := details.FunctionDescs[i]
+		mutFn, err := descsCol.GetMutableFunctionByID(ctx, txn, fnDesc.ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				Required:       true,
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		mutFn.SetDropped()
+		if err := descsCol.WriteDescToBatch(ctx, false /* kvTrace */, mutFn, b); err != nil {
+			return errors.Wrap(err, "writing dropping function to batch")
+		}
+		b.Del(catalogkeys.MakeDescMetadataKey(codec, fnDesc.ID))
+		descsCol.AddDeletedDescriptor(mutFn)
+	}
+
 	// Queue a GC job.
 	gcDetails := jobspb.SchemaChangeGCDetails{}
 	for _, tableID := range tablesToGC {
@@ -2160,6 +2526,9 @@ func (r *restoreResumer) dropDescriptors(
 	for _, schema := range details.SchemaDescs {
 		ignoredChildDescIDs[schema.ID] = struct{}{}
 	}
+	for _, fn := range details.FunctionDescs {
+		ignoredChildDescIDs[fn.ID] = struct{}{}
+	}
 	allDescs, err := descsCol.GetAllDescriptors(ctx, txn)
 	if err != nil {
 		return err
@@ -2396,6 +2765,13 @@ func getRestoringPrivileges(
 		if descCoverage == tree.RequestedDescriptors {
 			updatedPrivileges = descpb.NewBasePrivilegeDescriptor(user)
 		}
+	case catalog.FunctionDescriptor:
+		// Like types, the privileges on functions are wiped unless this is a
+		// cluster restore. Functions are executable by public by default.
+		if descCoverage == tree.RequestedDescriptors {
+			updatedPrivileges = descpb.NewBasePrivilegeDescriptor(user)
+			updatedPrivileges.Grant(security.PublicRoleName(), privilege.List{privilege.EXECUTE}, false /* withGrantOption */)
+		}
 	case catalog.DatabaseDescriptor:
 		// If the restore is not a cluster restore we cannot know that the users on
 		// the restoring cluster match the ones that were on the cluster that was
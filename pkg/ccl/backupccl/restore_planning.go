@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -39,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
@@ -71,6 +73,8 @@ const (
 	restoreOptSkipMissingSequenceOwners = "skip_missing_sequence_owners"
 	restoreOptSkipMissingViews          = "skip_missing_views"
 	restoreOptSkipLocalitiesCheck       = "skip_localities_check"
+	restoreOptColumns                   = "columns"
+	restoreOptIntoExistingTable         = "into_existing_table"
 	restoreOptDebugPauseOn              = "debug_pause_on"
 
 	// The temporary database system tables will be restored into for full
@@ -100,6 +104,26 @@ func rewriteViewQueryDBNames(table *tabledesc.Mutable, newDB string) error {
 		return pgerror.Wrapf(err, pgcode.Syntax,
 			"failed to parse underlying query from view %q", table.Name)
 	}
+	// The names of the user-defined functions called by the view are fully
+	// qualified as well, so change their DB names to `newDB` first.
+	stmt.AST, err = tree.SimpleStmtVisit(stmt.AST, func(expr tree.Expr) (bool, tree.Expr, error) {
+		f, ok := expr.(*tree.FuncExpr)
+		if !ok {
+			return true, expr, nil
+		}
+		name, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+		if !ok || name.NumParts < 3 || name.Parts[2] == "" {
+			return true, expr, nil
+		}
+		newName := *name
+		newName.Parts[2] = newDB
+		newFunc := *f
+		newFunc.Func = tree.ResolvableFunctionReference{FunctionReference: &newName}
+		return true, &newFunc, nil
+	})
+	if err != nil {
+		return err
+	}
 	// Re-format to change all DB names to `newDB`.
 	f := tree.NewFmtCtx(
 		tree.FmtParsable,
@@ -227,6 +251,7 @@ func rewriteSequencesInView(viewQuery string, rewrites DescRewriteMap) (string,
 func maybeFilterMissingViews(
 	tablesByID map[descpb.ID]*tabledesc.Mutable,
 	typesByID map[descpb.ID]*typedesc.Mutable,
+	functionsByID map[descpb.ID]*funcdesc.Mutable,
 	skipMissingViews bool,
 ) (map[descpb.ID]*tabledesc.Mutable, error) {
 	// Function that recursively determines whether a given table, if it is a
@@ -246,6 +271,11 @@ func maybeFilterMissingViews(
 				return false
 			}
 		}
+		for _, id := range desc.DependsOnFunctions {
+			if _, ok := functionsByID[id]; !ok {
+				return false
+			}
+		}
 		return true
 	}
 
@@ -302,6 +332,7 @@ func allocateDescriptorRewrites(
 	schemasByID map[descpb.ID]*schemadesc.Mutable,
 	tablesByID map[descpb.ID]*tabledesc.Mutable,
 	typesByID map[descpb.ID]*typedesc.Mutable,
+	functionsByID map[descpb.ID]*funcdesc.Mutable,
 	restoreDBs []catalog.DatabaseDescriptor,
 	descriptorCoverage tree.DescriptorCoverage,
 	opts tree.RestoreOptions,
@@ -417,6 +448,13 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Include the function descriptors when calculating the max ID.
+	for _, fn := range functionsByID {
+		if int64(fn.ID) > maxDescIDInBackup {
+			maxDescIDInBackup = int64(fn.ID)
+		}
+	}
+
 	needsNewParentIDs := make(map[string][]descpb.ID)
 	// Increment the DescIDSequenceKey so that it is higher than the max desc ID
 	// in the backup. This generator keeps produced the next descriptor ID.
@@ -610,10 +648,14 @@ func allocateDescriptorRewrites(
 				}
 				// Check that the table name is _not_ in use.
 				// This would fail the CPut later anyway, but this yields a prettier error.
-				tableName := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
-				err := catalogkv.CheckObjectCollision(ctx, txn, p.ExecCfg().Codec, parentID, table.GetParentSchemaID(), tableName)
-				if err != nil {
-					return err
+				// With into_existing_table, the name is the one of the existing table,
+				// and the table is restored as a staging table under another name.
+				if !opts.IntoExistingTable {
+					tableName := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+					err := catalogkv.CheckObjectCollision(ctx, txn, p.ExecCfg().Codec, parentID, table.GetParentSchemaID(), tableName)
+					if err != nil {
+						return err
+					}
 				}
 
 				// Check privileges.
@@ -771,6 +813,20 @@ func allocateDescriptorRewrites(
 			}
 		}
 
+		// User-defined functions are only restored along with their parent
+		// database, since their bodies may reference any object in it. Functions
+		// whose database is not being restored do not get a rewrite and are
+		// skipped.
+		for _, fn := range functionsByID {
+			targetDB, err := resolveTargetDB(ctx, txn, p, databasesByID, intoDB, descriptorCoverage, fn)
+			if err != nil {
+				return err
+			}
+			if _, ok := restoreDBNames[targetDB]; ok {
+				needsNewParentIDs[targetDB] = append(needsNewParentIDs[targetDB], fn.ID)
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -861,6 +917,19 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Update remapping information for function descriptors.
+	for _, fn := range functionsByID {
+		if _, ok := descriptorRewrites[fn.ID]; !ok {
+			continue
+		}
+		if descriptorCoverage == tree.AllDescriptors {
+			// The function doesn't need to be remapped.
+			descriptorRewrites[fn.ID].ID = fn.ID
+		} else {
+			descriptorsToRemap = append(descriptorsToRemap, fn)
+		}
+	}
+
 	sort.Sort(catalog.Descriptors(descriptorsToRemap))
 
 	// Generate new IDs for the schemas, tables, and types that need to be
@@ -895,6 +964,11 @@ func allocateDescriptorRewrites(
 	for _, typ := range typesByID {
 		rewriteObject(typ)
 	}
+	for _, fn := range functionsByID {
+		if _, ok := descriptorRewrites[fn.ID]; ok {
+			rewriteObject(fn)
+		}
+	}
 
 	return descriptorRewrites, nil
 }
@@ -1132,8 +1206,11 @@ func rewriteTypeDescs(types []*typedesc.Mutable, descriptorRewrites DescRewriteM
 				typ.ReferencingDescriptorIDs[i] = rw.ID
 			}
 		}
+		typ.ReferencingFunctionIDs = rewriteFunctionBackReferences(
+			typ.ReferencingFunctionIDs, descriptorRewrites,
+		)
 		switch t := typ.Kind; t {
-		case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+		case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM, descpb.TypeDescriptor_DOMAIN:
 			if rw, ok := descriptorRewrites[typ.ArrayTypeID]; ok {
 				typ.ArrayTypeID = rw.ID
 			}
@@ -1163,10 +1240,118 @@ func rewriteSchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites DescRe
 
 		sc.ID = rewrite.ID
 		sc.ParentID = rewrite.ParentID
+
+		// Functions which are not being restored are removed from the schema.
+		origFunctions := sc.Functions
+		sc.Functions = nil
+		for _, fn := range origFunctions {
+			fn.OverloadIDs = rewriteFunctionBackReferences(fn.OverloadIDs, descriptorRewrites)
+			if len(fn.OverloadIDs) > 0 {
+				sc.Functions = append(sc.Functions, fn)
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteFunctionDescs rewrites all ID's in the input slice of
+// FunctionDescriptors using the input ID rewrite mapping.
+func rewriteFunctionDescs(
+	functions []*funcdesc.Mutable, descriptorRewrites DescRewriteMap,
+) error {
+	for _, fn := range functions {
+		rewrite, ok := descriptorRewrites[fn.ID]
+		if !ok {
+			return errors.Errorf("missing rewrite for function %d", fn.ID)
+		}
+		// Reset the version and modification time on this new descriptor.
+		fn.Version = 1
+		fn.ModificationTime = hlc.Timestamp{}
+
+		// The body of the function references objects with fully qualified
+		// names, so the database qualifiers need to be updated if the parent
+		// database is being restored under a new name.
+		if dbRewrite, ok := descriptorRewrites[fn.ParentID]; ok && dbRewrite.NewDBName != "" {
+			body, err := rewriteFunctionBodyDBNames(fn.FunctionBody, dbRewrite.NewDBName)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax,
+					"failed to parse body of function %q", fn.Name)
+			}
+			fn.FunctionBody = body
+		}
+
+		fn.ID = rewrite.ID
+		fn.ParentSchemaID = rewrite.ParentSchemaID
+		fn.ParentID = rewrite.ParentID
+
+		for i := range fn.Args {
+			if err := rewriteIDsInTypesT(fn.Args[i].Type, descriptorRewrites); err != nil {
+				return err
+			}
+		}
+		if err := rewriteIDsInTypesT(fn.ReturnType, descriptorRewrites); err != nil {
+			return err
+		}
+		// Functions are only restored along with their parent database, so all
+		// of their dependencies are also being restored.
+		for i, dest := range fn.DependsOn {
+			depRewrite, ok := descriptorRewrites[dest]
+			if !ok {
+				return errors.AssertionFailedf(
+					"cannot restore function %q because referenced relation %d was not found",
+					fn.Name, dest)
+			}
+			fn.DependsOn[i] = depRewrite.ID
+		}
+		for i, dest := range fn.DependsOnTypes {
+			depRewrite, ok := descriptorRewrites[dest]
+			if !ok {
+				return errors.AssertionFailedf(
+					"cannot restore function %q because referenced type %d was not found",
+					fn.Name, dest)
+			}
+			fn.DependsOnTypes[i] = depRewrite.ID
+		}
+		fn.DependedOnBy = rewriteFunctionBackReferences(fn.DependedOnBy, descriptorRewrites)
 	}
 	return nil
 }
 
+// rewriteFunctionBodyDBNames rewrites the database qualifiers of all the
+// names referenced in the body of a function to newDB.
+func rewriteFunctionBodyDBNames(body string, newDB string) (string, error) {
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		return "", err
+	}
+	f := tree.NewFmtCtx(
+		tree.FmtParsable,
+		tree.FmtReformatTableNames(func(ctx *tree.FmtCtx, tn *tree.TableName) {
+			// empty catalog e.g. ``"".information_schema.tables` should stay empty.
+			if tn.CatalogName != "" {
+				tn.CatalogName = tree.Name(newDB)
+			}
+			ctx.WithReformatTableNames(nil, func() {
+				ctx.FormatNode(tn)
+			})
+		}),
+	)
+	f.FormatNode(stmt.AST)
+	return f.CloseAndGetString(), nil
+}
+
+// rewriteFunctionBackReferences rewrites the IDs of the functions referencing
+// a descriptor, dropping the ones which are not being restored.
+func rewriteFunctionBackReferences(ids []descpb.ID, descriptorRewrites DescRewriteMap) []descpb.ID {
+	var ret []descpb.ID
+	for _, id := range ids {
+		if rw, ok := descriptorRewrites[id]; ok {
+			ret = append(ret, rw.ID)
+		}
+	}
+	return ret
+}
+
 // RewriteTableDescs mutates tables to match the ID and privilege specified
 // in descriptorRewrites, as well as adjusting cross-table references to use the
 // new IDs. overrideDB can be specified to set database names in views.
@@ -1281,6 +1466,17 @@ func RewriteTableDescs(
 					table.Name, dest)
 			}
 		}
+		for i, dest := range table.DependsOnFunctions {
+			if depRewrite, ok := descriptorRewrites[dest]; ok {
+				table.DependsOnFunctions[i] = depRewrite.ID
+			} else {
+				// Views with missing dependencies should have been filtered out
+				// or have caused an error in maybeFilterMissingViews().
+				return errors.AssertionFailedf(
+					"cannot restore %q because referenced function %d was not found",
+					table.Name, dest)
+			}
+		}
 		origRefs := table.DependedOnBy
 		table.DependedOnBy = nil
 		for _, ref := range origRefs {
@@ -1289,6 +1485,19 @@ func RewriteTableDescs(
 				table.DependedOnBy = append(table.DependedOnBy, ref)
 			}
 		}
+		table.DependedOnByFunctions = rewriteFunctionBackReferences(
+			table.DependedOnByFunctions, descriptorRewrites,
+		)
+		// Triggers whose function is not being restored are dropped, much like
+		// the back-references above.
+		origTriggers := table.Triggers
+		table.Triggers = nil
+		for _, trigger := range origTriggers {
+			if fnRewrite, ok := descriptorRewrites[trigger.FunctionID]; ok {
+				trigger.FunctionID = fnRewrite.ID
+				table.Triggers = append(table.Triggers, trigger)
+			}
+		}
 
 		if table.IsSequence() && table.SequenceOpts.HasOwner() {
 			if ownerRewrite, ok := descriptorRewrites[table.SequenceOpts.SequenceOwner.OwnerTableID]; ok {
@@ -1378,6 +1587,8 @@ func resolveOptionsForRestoreJobDescription(
 		SkipMissingSequenceOwners: opts.SkipMissingSequenceOwners,
 		SkipMissingViews:          opts.SkipMissingViews,
 		Detached:                  opts.Detached,
+		Columns:                   opts.Columns,
+		IntoExistingTable:         opts.IntoExistingTable,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -1426,6 +1637,7 @@ func restoreJobDescription(
 		AsOf:               restore.AsOf,
 		Targets:            restore.Targets,
 		From:               make([]tree.StringOrPlaceholderOptList, len(restore.From)),
+		Where:              restore.Where,
 	}
 
 	var options tree.RestoreOptions
@@ -1526,6 +1738,13 @@ func restorePlanHook(
 		}
 	}
 
+	if restoreStmt.Where != nil || restoreStmt.Options.Columns != nil ||
+		restoreStmt.Options.IntoExistingTable {
+		if err := checkRestoreRowFilterTargets(restoreStmt); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	var newDBNameFn func() (string, error)
 	if restoreStmt.Options.NewDBName != nil {
 		if restoreStmt.DescriptorCoverage == tree.AllDescriptors || len(restoreStmt.Targets.Databases) != 1 {
@@ -1548,6 +1767,12 @@ func restorePlanHook(
 			return errors.Errorf("RESTORE cannot be used inside a transaction without DETACHED option")
 		}
 
+		if (restoreStmt.Where != nil || restoreStmt.Options.Columns != nil || restoreStmt.Options.IntoExistingTable) &&
+			!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RestoreRowFilter) {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"RESTORE ... WHERE is only available once the cluster is fully upgraded")
+		}
+
 		subdir, err := subdirFn()
 		if err != nil {
 			return err
@@ -1890,7 +2115,7 @@ func doRestorePlan(
 	for _, m := range mainBackupManifests {
 		spans := roachpb.Spans(m.Spans)
 		for i := range m.Descriptors {
-			table, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
+			table, _, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
 			if table == nil {
 				continue
 			}
@@ -1938,6 +2163,32 @@ func doRestorePlan(
 		}
 	}
 
+	var rowFilter string
+	var columnIDs []descpb.ColumnID
+	opts := restoreStmt.Options
+	if restoreStmt.Where != nil || opts.Columns != nil {
+		where := restoreStmt.Where
+		if where == nil {
+			// The columns of all the rows are filtered.
+			where = tree.DBoolTrue
+		}
+		if rowFilter, err = validateRestoreRowFilter(ctx, p, sqlDescs, where); err != nil {
+			return err
+		}
+	}
+	if opts.Columns != nil {
+		if columnIDs, err = validateRestoreColumns(sqlDescs, opts.Columns, opts.IntoExistingTable); err != nil {
+			return err
+		}
+	}
+	if opts.IntoExistingTable {
+		// The restored table is only a staging table for the rows inserted into
+		// the existing table, which checks its own references.
+		opts.SkipMissingFKs = true
+		opts.SkipMissingSequences = true
+		opts.SkipMissingSequenceOwners = true
+	}
+
 	databaseModifiers, newTypeDescs, err := planDatabaseModifiersForRestore(ctx, p, sqlDescs, restoreDBs)
 	if err != nil {
 		return err
@@ -1945,7 +2196,7 @@ func doRestorePlan(
 
 	sqlDescs = append(sqlDescs, newTypeDescs...)
 
-	if err := maybeUpgradeDescriptors(ctx, sqlDescs, restoreStmt.Options.SkipMissingFKs); err != nil {
+	if err := maybeUpgradeDescriptors(ctx, sqlDescs, opts.SkipMissingFKs); err != nil {
 		return err
 	}
 
@@ -1977,6 +2228,7 @@ func doRestorePlan(
 	schemasByID := make(map[descpb.ID]*schemadesc.Mutable)
 	tablesByID := make(map[descpb.ID]*tabledesc.Mutable)
 	typesByID := make(map[descpb.ID]*typedesc.Mutable)
+	functionsByID := make(map[descpb.ID]*funcdesc.Mutable)
 
 	for _, desc := range sqlDescs {
 		switch desc := desc.(type) {
@@ -1988,6 +2240,8 @@ func doRestorePlan(
 			tablesByID[desc.ID] = desc
 		case *typedesc.Mutable:
 			typesByID[desc.ID] = desc
+		case *funcdesc.Mutable:
+			functionsByID[desc.ID] = desc
 		}
 	}
 
@@ -2017,6 +2271,7 @@ func doRestorePlan(
 	filteredTablesByID, err := maybeFilterMissingViews(
 		tablesByID,
 		typesByID,
+		functionsByID,
 		restoreStmt.Options.SkipMissingViews)
 	if err != nil {
 		return err
@@ -2039,9 +2294,10 @@ func doRestorePlan(
 		schemasByID,
 		filteredTablesByID,
 		typesByID,
+		functionsByID,
 		restoreDBs,
 		restoreStmt.DescriptorCoverage,
-		restoreStmt.Options,
+		opts,
 		intoDB,
 		newDBName)
 	if err != nil {
@@ -2072,6 +2328,12 @@ func doRestorePlan(
 	for _, desc := range typesByID {
 		types = append(types, desc)
 	}
+	var functions []*funcdesc.Mutable
+	for i := range functionsByID {
+		if _, ok := descriptorRewrites[i]; ok {
+			functions = append(functions, functionsByID[i])
+		}
+	}
 
 	// We attempt to rewrite ID's in the collected type and table descriptors
 	// to catch errors during this process here, rather than in the job itself.
@@ -2087,9 +2349,25 @@ func doRestorePlan(
 	if err := rewriteTypeDescs(types, descriptorRewrites); err != nil {
 		return err
 	}
+	if err := rewriteFunctionDescs(functions, descriptorRewrites); err != nil {
+		return err
+	}
 	for i := range revalidateIndexes {
 		revalidateIndexes[i].TableID = descriptorRewrites[revalidateIndexes[i].TableID].ID
 	}
+	if rowFilter != "" {
+		// Like partial index predicates, the filter refers to the types of the
+		// backup by ID.
+		if rowFilter, err = rewriteTypesInExpr(rowFilter, descriptorRewrites); err != nil {
+			return err
+		}
+	}
+	var existingTableID descpb.ID
+	if opts.IntoExistingTable {
+		if existingTableID, columnIDs, err = planRestoreIntoExistingTable(ctx, p, tables, columnIDs); err != nil {
+			return err
+		}
+	}
 
 	// Collect telemetry.
 	collectTelemetry := func() {
@@ -2097,6 +2375,15 @@ func doRestorePlan(
 		if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
 			telemetry.Count("restore.full-cluster")
 		}
+		if rowFilter != "" {
+			telemetry.Count("restore.row-filter")
+		}
+		if opts.Columns != nil {
+			telemetry.Count("restore.columns")
+		}
+		if existingTableID != descpb.InvalidID {
+			telemetry.Count("restore.into-existing-table")
+		}
 	}
 
 	encodedTables := make([]*descpb.TableDescriptor, len(tables))
@@ -2126,6 +2413,9 @@ func doRestorePlan(
 			RevalidateIndexes:  revalidateIndexes,
 			DatabaseModifiers:  databaseModifiers,
 			DebugPauseOn:       debugPauseOn,
+			RowFilter:          rowFilter,
+			ColumnIDs:          columnIDs,
+			ExistingTableID:    existingTableID,
 		},
 		Progress: jobspb.RestoreProgress{},
 	}
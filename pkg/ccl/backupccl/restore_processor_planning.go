@@ -44,6 +44,7 @@ func distRestore(
 	pkIDs map[uint64]bool,
 	encryption *jobspb.BackupEncryptionOptions,
 	rekeys []execinfrapb.TableRekey,
+	rowFilter *execinfrapb.RestoreRowFilter,
 	restoreTime hlc.Timestamp,
 	progCh chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
 ) error {
@@ -91,6 +92,7 @@ func distRestore(
 		Encryption:  fileEncryption,
 		Rekeys:      rekeys,
 		PKIDs:       pkIDs,
+		RowFilter:   rowFilter,
 	}
 
 	if len(splitAndScatterSpecs) == 0 {
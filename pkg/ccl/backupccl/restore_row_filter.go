@@ -0,0 +1,670 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/bulk"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// checkRestoreRowFilterTargets checks that the targets of a RESTORE ... WHERE,
+// or of a RESTORE with the columns or into_existing_table options, name a
+// single table, and that its predicate has no placeholders, since their types
+// cannot be inferred before the backup is read.
+func checkRestoreRowFilterTargets(restoreStmt *tree.Restore) error {
+	if restoreStmt.DescriptorCoverage == tree.AllDescriptors ||
+		len(restoreStmt.Targets.Databases) > 0 ||
+		restoreStmt.Targets.Tenant != (roachpb.TenantID{}) ||
+		len(restoreStmt.Targets.Tables) != 1 {
+		switch {
+		case restoreStmt.Where != nil:
+			return errors.New("RESTORE ... WHERE can only be used to restore a single table")
+		case restoreStmt.Options.Columns != nil:
+			return errors.Newf("%s can only be used to restore a single table", restoreOptColumns)
+		default:
+			return errors.Newf("%s can only be used to restore a single table", restoreOptIntoExistingTable)
+		}
+	}
+	if restoreStmt.Where == nil {
+		return nil
+	}
+	_, err := tree.SimpleVisit(restoreStmt.Where, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if _, ok := expr.(*tree.Placeholder); ok {
+			return false, expr, pgerror.New(pgcode.FeatureNotSupported,
+				"placeholders are not supported in the WHERE clause of RESTORE")
+		}
+		return true, expr, nil
+	})
+	return err
+}
+
+// validateRestoreRowFilter checks that the predicate of a RESTORE ... WHERE can
+// be used to filter the rows of the single table being restored, and returns
+// it serialized with its columns dequalified, in terms of the table and types
+// as they are in the backup.
+func validateRestoreRowFilter(
+	ctx context.Context, p sql.PlanHookState, sqlDescs []catalog.Descriptor, where tree.Expr,
+) (string, error) {
+	table, typeDescs, err := restoreRowFilterTable(sqlDescs)
+	if err != nil {
+		return "", err
+	}
+	if len(table.AllMutations()) > 0 {
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"RESTORE ... WHERE cannot be used on table %s, which has schema changes in progress",
+			table.GetName())
+	}
+	// Since only the primary index of the table is read from the backup, the
+	// rows must not have virtual columns to compute for the other indexes.
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"RESTORE ... WHERE cannot be used on table %s, which has virtual column %s",
+				table.GetName(), col.GetName())
+		}
+	}
+
+	// The types of the columns need to be hydrated to type-check constants
+	// against them.
+	resolver := newBackupTypeResolver(typeDescs)
+	desc := protoutil.Clone(table.TableDesc()).(*descpb.TableDescriptor)
+	if err := typedesc.HydrateTypesInTableDescriptor(ctx, desc, resolver); err != nil {
+		return "", err
+	}
+	tn := tree.MakeUnqualifiedTableName(tree.Name(table.GetName()))
+	filter, _, _, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx,
+		tabledesc.NewBuilder(desc).BuildImmutableTable(),
+		where,
+		types.Bool,
+		"RESTORE ... WHERE",
+		p.SemaCtx(),
+		tree.VolatilityImmutable,
+		&tn,
+	)
+	return filter, err
+}
+
+// restoreRowFilterTable returns the single table restored by a RESTORE ...
+// WHERE, as it is in the backup, along with the types that are restored with
+// it.
+func restoreRowFilterTable(
+	sqlDescs []catalog.Descriptor,
+) (*tabledesc.Mutable, []*descpb.TypeDescriptor, error) {
+	var table *tabledesc.Mutable
+	var typeDescs []*descpb.TypeDescriptor
+	for _, desc := range sqlDescs {
+		switch desc := desc.(type) {
+		case *tabledesc.Mutable:
+			if table != nil {
+				return nil, nil, errors.New("RESTORE ... WHERE can only be used to restore a single table")
+			}
+			table = desc
+		case *typedesc.Mutable:
+			typeDescs = append(typeDescs, desc.TypeDesc())
+		}
+	}
+	if table == nil || !table.IsTable() {
+		return nil, nil, errors.New("RESTORE ... WHERE can only be used to restore a table")
+	}
+	return table, typeDescs, nil
+}
+
+// validateRestoreColumns checks that the columns of a RESTORE ... WITH columns
+// are columns of the single table being restored, and returns their IDs.
+//
+// When the table is restored as a new table, the other columns of its rows are
+// NULL, so they must be nullable, and must not be computed or referenced by
+// expressions of the table which would then be inconsistent with them. When
+// the rows are inserted into an existing table, the other columns of the
+// existing table get their default values, like with INSERT.
+func validateRestoreColumns(
+	sqlDescs []catalog.Descriptor, columns tree.NameList, intoExistingTable bool,
+) ([]descpb.ColumnID, error) {
+	table, _, err := restoreRowFilterTable(sqlDescs)
+	if err != nil {
+		return nil, err
+	}
+	var restored catalog.TableColSet
+	columnIDs := make([]descpb.ColumnID, len(columns))
+	for i, name := range columns {
+		col, err := table.FindColumnWithName(name)
+		if err != nil || !col.Public() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist in table %s in the backup", name, table.GetName())
+		}
+		if restored.Contains(col.GetID()) {
+			return nil, pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q specified more than once in %s", name, restoreOptColumns)
+		}
+		restored.Add(col.GetID())
+		columnIDs[i] = col.GetID()
+	}
+	if intoExistingTable {
+		return columnIDs, nil
+	}
+
+	// Collect the columns referenced by the expressions of the table, which
+	// must all be restored.
+	var referenced catalog.TableColSet
+	addReferenced := func(expr string) error {
+		parsed, err := parser.ParseExpr(expr)
+		if err != nil {
+			return err
+		}
+		colIDs, err := schemaexpr.ExtractColumnIDs(table, parsed)
+		if err != nil {
+			return err
+		}
+		referenced.UnionWith(colIDs)
+		return nil
+	}
+	for _, col := range table.PublicColumns() {
+		if col.IsComputed() {
+			if err := addReferenced(col.GetComputeExpr()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, idx := range table.PartialIndexes() {
+		if err := addReferenced(idx.GetPredicate()); err != nil {
+			return nil, err
+		}
+	}
+	for _, check := range table.ActiveChecks() {
+		if err := addReferenced(check.Expr); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, col := range table.PublicColumns() {
+		if restored.Contains(col.GetID()) {
+			continue
+		}
+		switch {
+		case !col.IsNullable():
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"column %q of table %s must be restored, since it is not nullable",
+				col.GetName(), table.GetName())
+		case col.IsComputed():
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"column %q of table %s must be restored, since it is computed",
+				col.GetName(), table.GetName())
+		case referenced.Contains(col.GetID()):
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"column %q of table %s must be restored, since it is referenced by a "+
+					"computed column, partial index predicate or check constraint",
+				col.GetName(), table.GetName())
+		}
+	}
+	return columnIDs, nil
+}
+
+// planRestoreIntoExistingTable plans a RESTORE ... WITH into_existing_table,
+// once the descriptor of the single table being restored has been rewritten.
+// That table is renamed to be restored as a staging table next to the existing
+// table with its name, into which its rows are inserted once it is restored.
+// The ID of the existing table is returned, along with the IDs of the columns
+// that are inserted into it.
+func planRestoreIntoExistingTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	tables []*tabledesc.Mutable,
+	columnIDs []descpb.ColumnID,
+) (descpb.ID, []descpb.ColumnID, error) {
+	if len(tables) != 1 {
+		return descpb.InvalidID, nil, errors.Newf(
+			"%s can only be used to restore a single table", restoreOptIntoExistingTable)
+	}
+	staging := tables[0]
+	txn := p.ExtendedEvalContext().Txn
+	codec := p.ExecCfg().Codec
+	found, id, err := catalogkv.LookupObjectID(
+		ctx, txn, codec, staging.GetParentID(), staging.GetParentSchemaID(), staging.GetName(),
+	)
+	if err != nil {
+		return descpb.InvalidID, nil, err
+	}
+	if !found {
+		return descpb.InvalidID, nil, pgerror.Newf(pgcode.UndefinedTable,
+			"table %q does not exist to restore into with %s", staging.GetName(), restoreOptIntoExistingTable)
+	}
+	existing, err := catalogkv.MustGetTableDescByID(ctx, txn, codec, id)
+	if err != nil {
+		return descpb.InvalidID, nil, err
+	}
+	if !existing.IsTable() || !existing.Public() {
+		return descpb.InvalidID, nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a public table that can be restored into", existing.GetName())
+	}
+	if err := p.CheckPrivilege(ctx, existing, privilege.INSERT); err != nil {
+		return descpb.InvalidID, nil, err
+	}
+
+	// Without the columns option, all the columns of the table in the backup
+	// that can be inserted into are.
+	if columnIDs == nil {
+		for _, col := range staging.PublicColumns() {
+			if !col.IsComputed() {
+				columnIDs = append(columnIDs, col.GetID())
+			}
+		}
+	}
+	for _, colID := range columnIDs {
+		col, err := staging.FindColumnWithID(colID)
+		if err != nil {
+			return descpb.InvalidID, nil, err
+		}
+		existingCol, err := existing.FindColumnWithName(col.ColName())
+		if err != nil || !existingCol.Public() {
+			return descpb.InvalidID, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q of table %s in the backup does not exist in the existing table",
+				col.GetName(), staging.GetName())
+		}
+		if existingCol.IsComputed() {
+			return descpb.InvalidID, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot restore into computed column %q of table %s",
+				existingCol.GetName(), existing.GetName())
+		}
+	}
+
+	staging.SetName(fmt.Sprintf("%s_restore_%d", staging.GetName(), staging.GetID()))
+	stagingName := tree.NewUnqualifiedTableName(tree.Name(staging.GetName()))
+	if err := catalogkv.CheckObjectCollision(
+		ctx, txn, codec, staging.GetParentID(), staging.GetParentSchemaID(), stagingName,
+	); err != nil {
+		return descpb.InvalidID, nil, err
+	}
+	return existing.GetID(), columnIDs, nil
+}
+
+// makeRestoreRowFilter returns the filter of the rows restored by a RESTORE
+// ... WHERE job, once the descriptors it restores have been created.
+func makeRestoreRowFilter(
+	ctx context.Context, execCfg *sql.ExecutorConfig, details jobspb.RestoreDetails,
+) (*execinfrapb.RestoreRowFilter, error) {
+	if len(details.TableDescs) != 1 {
+		return nil, errors.AssertionFailedf(
+			"expected a single table to restore with a row filter, found %d", len(details.TableDescs))
+	}
+	table := tabledesc.NewBuilder(details.TableDescs[0]).BuildImmutableTable()
+	rowFilter := &execinfrapb.RestoreRowFilter{TableID: table.GetID(), Expr: details.RowFilter}
+	if details.ExistingTableID == descpb.InvalidID {
+		// The rows inserted into an existing table are only read from the
+		// columns of the staging table that are inserted.
+		rowFilter.ColumnIDs = details.ColumnIDs
+	}
+
+	// The types referenced by the table may either have been restored along
+	// with it, or already exist in the database it is restored into.
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, descsCol *descs.Collection,
+	) error {
+		rowFilter.Types = nil
+		_, dbDesc, err := descsCol.GetImmutableDatabaseByID(
+			ctx, txn, table.GetParentID(), tree.DatabaseLookupFlags{
+				Required:       true,
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			})
+		if err != nil {
+			return err
+		}
+		getType := func(id descpb.ID) (catalog.TypeDescriptor, error) {
+			return descsCol.GetImmutableTypeByID(ctx, txn, id, tree.ObjectLookupFlags{
+				CommonLookupFlags: tree.CommonLookupFlags{
+					Required:       true,
+					AvoidLeased:    true,
+					IncludeOffline: true,
+				},
+			})
+		}
+		typeIDs, _, err := table.GetAllReferencedTypeIDs(dbDesc, getType)
+		if err != nil {
+			return err
+		}
+		for _, id := range typeIDs {
+			typ, err := getType(id)
+			if err != nil {
+				return err
+			}
+			rowFilter.Types = append(rowFilter.Types, *typ.TypeDesc())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return rowFilter, nil
+}
+
+// mvccKeyValueOverhead is the memory used by a buffered entry of a filtered
+// table, besides its key and value.
+const mvccKeyValueOverhead = int64(unsafe.Sizeof(storage.MVCCKeyValue{}))
+
+// restoreRowFilterIVarContainer resolves the columns referenced by the
+// expressions evaluated over the rows of a filtered table to the datums of the
+// current row.
+type restoreRowFilterIVarContainer struct {
+	cols []catalog.Column
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &restoreRowFilterIVarContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *restoreRowFilterIVarContainer) IndexedVarEval(
+	idx int, _ *tree.EvalContext,
+) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *restoreRowFilterIVarContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.cols[idx].GetType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *restoreRowFilterIVarContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].GetName())
+	return &n
+}
+
+// restoreFilteredSpanEntry restores the rows of the table of a RESTORE ...
+// WHERE in the span of the entry for which the predicate is true. The rows are
+// decoded from the primary index of the table in the backup, and the entries
+// of all of its indexes are encoded from them, as they were in the backup, then
+// go through the KeyRewriter like the keys of an unfiltered restore. The
+// entries of the other indexes in the backup are not read.
+//
+// Since the entries of the indexes of a row are not adjacent, the entries of
+// the rows restored from the span are buffered and sorted before being added
+// to the batcher.
+func (rd *restoreDataProcessor) restoreFilteredSpanEntry(
+	ctx context.Context,
+	entry execinfrapb.RestoreSpanEntry,
+	iter storage.SimpleMVCCIterator,
+	batcher *bulk.SSTBatcher,
+) error {
+	filter := rd.spec.RowFilter
+	typeDescs := make([]*descpb.TypeDescriptor, len(filter.Types))
+	for i := range filter.Types {
+		typeDescs[i] = &filter.Types[i]
+	}
+	resolver := newBackupTypeResolver(typeDescs)
+	table, oldID, err := rd.rowFilterTable(ctx, resolver)
+	if err != nil {
+		return err
+	}
+
+	// The keys of the backup are encoded for the tenant that took it, which
+	// may not be the one restoring it.
+	_, tenantID, err := keys.DecodeTenantPrefix(entry.Span.Key)
+	if err != nil {
+		return err
+	}
+	codec := keys.MakeSQLCodec(tenantID)
+	prefix := codec.IndexPrefix(uint32(oldID), uint32(table.GetPrimaryIndexID()))
+	sp := entry.Span.Intersect(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	if !sp.Valid() {
+		return nil
+	}
+
+	// Each worker evaluates expressions with its own context, since the
+	// container of the columns of the rows is set in it.
+	evalCtx := rd.flowCtx.NewEvalCtx()
+	cols := table.PublicColumns()
+	container := &restoreRowFilterIVarContainer{cols: cols}
+	evalCtx.PushIVarContainer(container)
+	defer evalCtx.PopIVarContainer()
+
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.TypeResolver = resolver
+	semaCtx.IVarContainer = container
+
+	parsed, err := parser.ParseExpr(filter.Expr)
+	if err != nil {
+		return err
+	}
+	source := colinfo.NewSourceInfoForSingleTable(
+		tree.MakeUnqualifiedTableName(tree.Name(table.GetName())),
+		colinfo.ResultColumnsFromColumns(table.GetID(), cols),
+	)
+	var v schemaexpr.NameResolutionVisitor
+	resolved, err := schemaexpr.ResolveNamesUsingVisitor(
+		&v, parsed, source, tree.MakeIndexedVarHelper(container, len(cols)),
+		evalCtx.SessionData().SearchPath,
+	)
+	if err != nil {
+		return err
+	}
+	predicate, err := tree.TypeCheck(ctx, resolved, &semaCtx, types.Bool)
+	if err != nil {
+		return errors.Wrapf(err, "type-checking RESTORE ... WHERE predicate %q", filter.Expr)
+	}
+	partialIndexExprs, _, err := schemaexpr.MakePartialIndexExprs(
+		ctx, table.PartialIndexes(), cols, table, evalCtx, &semaCtx,
+	)
+	if err != nil {
+		return err
+	}
+
+	var alloc tree.DatumAlloc
+	args := row.FetcherTableArgs{
+		Desc:  table,
+		Index: table.GetPrimaryIndex(),
+		Cols:  cols,
+	}
+	var valNeededForCol util.FastIntSet
+	for i, col := range cols {
+		args.ColIdxMap.Set(col.GetID(), i)
+		valNeededForCol.Add(i)
+	}
+	args.ValNeededForCol = valNeededForCol
+
+	// Only the columns listed in the columns option, if any, are restored; the
+	// others are NULL.
+	var nullCols util.FastIntSet
+	if filter.ColumnIDs != nil {
+		var restored catalog.TableColSet
+		for _, colID := range filter.ColumnIDs {
+			restored.Add(colID)
+		}
+		for i, col := range cols {
+			if !restored.Contains(col.GetID()) {
+				nullCols.Add(i)
+			}
+		}
+	}
+	var rf row.Fetcher
+	if err := rf.Init(
+		ctx,
+		codec,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0, /* lockTimeout */
+		&alloc,
+		nil, /* memMonitor */
+		args,
+	); err != nil {
+		return err
+	}
+	ri, err := row.MakeInserter(
+		ctx,
+		nil, /* txn */
+		codec,
+		table,
+		cols,
+		&alloc,
+		&evalCtx.Settings.SV,
+		true, /* internal */
+		nil,  /* metrics */
+	)
+	if err != nil {
+		return err
+	}
+
+	kvFetcher := row.MakeBackupSSTKVFetcher(
+		storage.MVCCKey{Key: sp.Key}, storage.MVCCKey{Key: sp.EndKey}, iter,
+		hlc.Timestamp{} /* startTime */, rd.spec.RestoreTime, false, /* withRev */
+	)
+	if err := rf.StartScanFrom(ctx, &kvFetcher, false /* traceKV */); err != nil {
+		return err
+	}
+
+	// The entries of the secondary indexes of the rows are not in the order of
+	// their primary keys, so they are buffered and sorted before they are added
+	// to the batcher, in chunks bounded by its flush size.
+	acc := rd.MemMonitor.MakeBoundAccount()
+	defer acc.Close(ctx)
+	var kvs []storage.MVCCKeyValue
+	flushKVs := func() error {
+		sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key.Less(kvs[j].Key) })
+		for _, kv := range kvs {
+			if err := batcher.AddMVCCKey(ctx, kv.Key, kv.Value); err != nil {
+				return errors.Wrapf(err, "adding to batch: %s", kv.Key)
+			}
+		}
+		kvs = nil
+		acc.Clear(ctx)
+		if err := batcher.Flush(ctx); err != nil {
+			return err
+		}
+		return batcher.Reset(ctx)
+	}
+	partialIndexPutVals := make(tree.Datums, len(table.PartialIndexes()))
+	for {
+		datums, _, _, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return err
+		}
+		if datums == nil {
+			break
+		}
+		container.row = datums
+		d, err := predicate.Eval(evalCtx)
+		if err != nil {
+			return err
+		}
+		if d != tree.DBoolTrue {
+			continue
+		}
+		nullCols.ForEach(func(i int) {
+			datums[i] = tree.DNull
+		})
+
+		for i, idx := range table.PartialIndexes() {
+			if partialIndexPutVals[i], err = partialIndexExprs[idx.GetID()].Eval(evalCtx); err != nil {
+				return err
+			}
+		}
+		var pm row.PartialIndexUpdateHelper
+		if err := pm.Init(partialIndexPutVals, nil /* partialIndexDelVals */, table); err != nil {
+			return err
+		}
+		ts := rf.RowLastModified()
+		var rewriteErr error
+		if err := ri.InsertRow(
+			ctx,
+			row.KVInserter(func(kv roachpb.KeyValue) {
+				if rewriteErr != nil {
+					return
+				}
+				key, ok, err := rd.kr.RewriteKey(kv.Key)
+				if err == nil && !ok {
+					err = errors.AssertionFailedf("key %s of the restored table was not rewritten", kv.Key)
+				}
+				if err != nil {
+					rewriteErr = err
+					return
+				}
+				kv.Value.InitChecksum(key)
+				if err := acc.Grow(ctx, mvccKeyValueOverhead+int64(len(key)+len(kv.Value.RawBytes))); err != nil {
+					rewriteErr = err
+					return
+				}
+				kvs = append(kvs, storage.MVCCKeyValue{
+					Key:   storage.MVCCKey{Key: key, Timestamp: ts},
+					Value: kv.Value.RawBytes,
+				})
+			}),
+			datums,
+			pm,
+			true,  /* overwrite */
+			false, /* traceKV */
+		); err != nil {
+			return errors.Wrap(err, "encoding row")
+		}
+		if rewriteErr != nil {
+			return rewriteErr
+		}
+		if acc.Used() >= rd.flushBytes {
+			if err := flushKVs(); err != nil {
+				return err
+			}
+		}
+	}
+	return flushKVs()
+}
+
+// rowFilterTable returns the descriptor of the table of a RESTORE ... WHERE,
+// with its types hydrated and with the ID it had in the backup, along with
+// that ID.
+func (rd *restoreDataProcessor) rowFilterTable(
+	ctx context.Context, resolver *backupTypeResolver,
+) (catalog.TableDescriptor, descpb.ID, error) {
+	filter := rd.spec.RowFilter
+	for _, rekey := range rd.spec.Rekeys {
+		var desc descpb.Descriptor
+		if err := protoutil.Unmarshal(rekey.NewDesc, &desc); err != nil {
+			return nil, 0, errors.Wrapf(err, "unmarshalling rekey descriptor for old table id %d", rekey.OldID)
+		}
+		table, _, _, _, _ := descpb.FromDescriptor(&desc)
+		if table == nil || table.ID != filter.TableID {
+			continue
+		}
+		oldID := descpb.ID(rekey.OldID)
+		table.ID = oldID
+		if err := typedesc.HydrateTypesInTableDescriptor(ctx, table, resolver); err != nil {
+			return nil, 0, err
+		}
+		return tabledesc.NewBuilder(table).BuildImmutableTable(), oldID, nil
+	}
+	return nil, 0, errors.AssertionFailedf("no rekey found for table %d of the row filter", filter.TableID)
+}
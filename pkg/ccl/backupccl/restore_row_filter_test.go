@@ -0,0 +1,234 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = LocalFoo + "/filter"
+
+	sqlDB.Exec(t, `CREATE TYPE data.status AS ENUM ('open', 'closed')`)
+	sqlDB.Exec(t, `CREATE TABLE data.t (
+		id INT PRIMARY KEY,
+		tenant_id INT,
+		s data.status,
+		v STRING,
+		INDEX tenant_idx (tenant_id),
+		INDEX open_idx (v) WHERE s = 'open',
+		FAMILY (id, tenant_id),
+		FAMILY (s, v)
+	)`)
+	sqlDB.Exec(t, `INSERT INTO data.t
+		SELECT i, i % 3, IF(i % 2 = 0, 'open', 'closed')::data.status, 'v' || i::STRING
+		FROM generate_series(1, 30) AS g(i)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, collection)
+	// Rows deleted after the backup are restored from it.
+	sqlDB.Exec(t, `DELETE FROM data.t WHERE id < 10`)
+	sqlDB.Exec(t, `INSERT INTO data.t VALUES (100, 1, 'open', 'new')`)
+
+	sqlDB.Exec(t, `CREATE DATABASE restored`)
+	sqlDB.Exec(t, `RESTORE TABLE data.t FROM LATEST IN $1 WHERE tenant_id = 1 AND s = 'open'
+		WITH into_db = 'restored'`, collection)
+
+	expected := [][]string{
+		{"4", "1", "open", "v4"}, {"10", "1", "open", "v10"}, {"16", "1", "open", "v16"},
+		{"22", "1", "open", "v22"}, {"28", "1", "open", "v28"},
+	}
+	sqlDB.CheckQueryResults(t, `SELECT * FROM restored.t ORDER BY id`, expected)
+
+	// The entries of the secondary indexes are those of the restored rows.
+	sqlDB.CheckQueryResults(t,
+		`SELECT id, tenant_id FROM restored.t@tenant_idx ORDER BY id`,
+		[][]string{{"4", "1"}, {"10", "1"}, {"16", "1"}, {"22", "1"}, {"28", "1"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT id, v FROM restored.t@open_idx WHERE s = 'open' ORDER BY id`,
+		[][]string{{"4", "v4"}, {"10", "v10"}, {"16", "v16"}, {"22", "v22"}, {"28", "v28"}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM restored.t@tenant_idx`, [][]string{{"5"}})
+
+	// The restored table can be written to.
+	sqlDB.Exec(t, `INSERT INTO restored.t VALUES (5, 2, 'closed', 'v5')`)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM restored.t`, [][]string{{"6"}})
+
+	var description string
+	sqlDB.QueryRow(t, `SELECT description FROM [SHOW JOBS] WHERE job_type = 'RESTORE'`).Scan(&description)
+	require.Contains(t, description, `WHERE (tenant_id = 1) AND (s = 'open')`)
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `RESTORE ... WHERE can only be used to restore a single table`,
+			`RESTORE DATABASE data FROM LATEST IN $1 WHERE id = 1 WITH new_db_name = 'd2'`, collection)
+		sqlDB.ExpectErr(t, `RESTORE ... WHERE can only be used to restore a single table`,
+			`RESTORE TABLE data.* FROM LATEST IN $1 WHERE id = 1 WITH into_db = 'restored'`, collection)
+		sqlDB.ExpectErr(t, `placeholders are not supported in the WHERE clause of RESTORE`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WHERE id = $2 WITH into_db = 'restored'`, collection, 1)
+		sqlDB.ExpectErr(t, `column "missing" does not exist`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WHERE missing = 1 WITH into_db = 'restored'`, collection)
+		sqlDB.ExpectErr(t, `volatile functions are not allowed in RESTORE \.\.\. WHERE`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WHERE random() < 0.5 WITH into_db = 'restored'`, collection)
+	})
+}
+
+func TestRestoreColumnsIntoExistingTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	tc, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = LocalFoo + "/existing"
+
+	sqlDB.Exec(t, `CREATE TABLE data.t (
+		id INT PRIMARY KEY,
+		a INT NOT NULL,
+		b STRING,
+		c INT AS (a * 2) STORED,
+		d INT,
+		INDEX b_idx (b),
+		CHECK (d IS NULL OR d > 0)
+	)`)
+	sqlDB.Exec(t, `INSERT INTO data.t (id, a, b, d)
+		SELECT i, i * 10, 'b' || i::STRING, i FROM generate_series(1, 20) AS g(i)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, collection)
+	sqlDB.Exec(t, `DELETE FROM data.t WHERE id < 5`)
+
+	t.Run("columns", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE cols`)
+		sqlDB.Exec(t, `RESTORE TABLE data.t FROM LATEST IN $1 WHERE id <= 3
+			WITH into_db = 'cols', columns = (id, a, c, d)`, collection)
+		// The columns which are not restored are NULL, including in the
+		// secondary indexes.
+		sqlDB.CheckQueryResults(t, `SELECT * FROM cols.t ORDER BY id`, [][]string{
+			{"1", "10", "NULL", "20", "1"},
+			{"2", "20", "NULL", "40", "2"},
+			{"3", "30", "NULL", "60", "3"},
+		})
+		sqlDB.CheckQueryResults(t, `SELECT id, b FROM cols.t@b_idx ORDER BY id`, [][]string{
+			{"1", "NULL"}, {"2", "NULL"}, {"3", "NULL"},
+		})
+	})
+
+	t.Run("into existing table", func(t *testing.T) {
+		// The rows deleted after the backup are restored into the live table.
+		sqlDB.Exec(t, `RESTORE TABLE data.t FROM LATEST IN $1 WHERE id < 5
+			WITH into_existing_table, columns = (id, a, b)`, collection)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM data.t WHERE id < 7 ORDER BY id`, [][]string{
+			{"1", "10", "b1", "20", "NULL"},
+			{"2", "20", "b2", "40", "NULL"},
+			{"3", "30", "b3", "60", "NULL"},
+			{"4", "40", "b4", "80", "NULL"},
+			{"5", "50", "b5", "100", "5"},
+			{"6", "60", "b6", "120", "6"},
+		})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.t`, [][]string{{"20"}})
+		// The staging table is dropped.
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW TABLES FROM data] WHERE table_name LIKE 't_restore_%'`,
+			[][]string{{"0"}})
+	})
+
+	t.Run("into existing table conflict", func(t *testing.T) {
+		// Rows which are still in the live table conflict with the restored
+		// ones, and leave it unchanged.
+		sqlDB.Exec(t, `DELETE FROM data.t WHERE id = 1`)
+		sqlDB.ExpectErr(t, `duplicate key value`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WHERE id < 3 WITH into_existing_table`, collection)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.t`, [][]string{{"19"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW TABLES FROM data] WHERE table_name LIKE 't_restore_%'`,
+			[][]string{{"0"}})
+	})
+
+	t.Run("into existing table resumed", func(t *testing.T) {
+		// The rows are inserted in batches of two, and the job pauses after the
+		// first one.
+		sqlDB.Exec(t, `DELETE FROM data.t WHERE id < 6`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.restore.into_existing_table.batch_size = 2`)
+		defer sqlDB.Exec(t, `RESET CLUSTER SETTING bulkio.restore.into_existing_table.batch_size`)
+		var paused bool
+		for _, server := range tc.Servers {
+			registry := server.JobRegistry().(*jobs.Registry)
+			registry.TestingResumerCreationKnobs = map[jobspb.Type]func(raw jobs.Resumer) jobs.Resumer{
+				jobspb.TypeRestore: func(raw jobs.Resumer) jobs.Resumer {
+					r := raw.(*restoreResumer)
+					r.testingKnobs.afterInsertingIntoExistingTableBatch = func() error {
+						if paused {
+							return nil
+						}
+						paused = true
+						return jobs.MarkPauseRequestError(errors.New("injected pause"))
+					}
+					return r
+				},
+			}
+		}
+
+		var jobID int64
+		sqlDB.QueryRow(t, `RESTORE TABLE data.t FROM LATEST IN $1 WHERE id < 6
+			WITH into_existing_table, columns = (id, a, b), detached`, collection).Scan(&jobID)
+		require.NoError(t, waitForStatus(t, sqlDB, jobID, jobs.StatusPaused))
+		// Only the first batch is inserted, and the staging table is still
+		// OFFLINE.
+		sqlDB.CheckQueryResults(t, `SELECT id FROM data.t WHERE id < 6 ORDER BY id`, [][]string{
+			{"1"}, {"2"},
+		})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW TABLES FROM data] WHERE table_name LIKE 't_restore_%'`,
+			[][]string{{"0"}})
+
+		// The resumed job inserts the other batches, rather than the first one
+		// again, which would conflict.
+		sqlDB.Exec(t, `RESUME JOB $1`, jobID)
+		require.NoError(t, waitForStatus(t, sqlDB, jobID, jobs.StatusSucceeded))
+		sqlDB.CheckQueryResults(t, `SELECT id FROM data.t WHERE id < 6 ORDER BY id`, [][]string{
+			{"1"}, {"2"}, {"3"}, {"4"}, {"5"},
+		})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.t`, [][]string{{"20"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW TABLES FROM data] WHERE table_name LIKE 't_restore_%'`,
+			[][]string{{"0"}})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE errs`)
+		sqlDB.ExpectErr(t, `columns can only be used to restore a single table`,
+			`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'd2', columns = (id)`, collection)
+		sqlDB.ExpectErr(t, `into_existing_table can only be used to restore a single table`,
+			`RESTORE TABLE data.* FROM LATEST IN $1 WITH into_existing_table`, collection)
+		sqlDB.ExpectErr(t, `column "missing" does not exist in table t in the backup`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', columns = (id, missing)`, collection)
+		sqlDB.ExpectErr(t, `column "id" specified more than once in columns`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', columns = (id, a, id)`, collection)
+		sqlDB.ExpectErr(t, `column "a" of table t must be restored, since it is not nullable`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', columns = (id)`, collection)
+		sqlDB.ExpectErr(t, `column "c" of table t must be restored, since it is computed`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', columns = (id, a)`, collection)
+		sqlDB.ExpectErr(t, `column "d" of table t must be restored, since it is referenced`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', columns = (id, a, b, c)`, collection)
+		sqlDB.ExpectErr(t, `table "t" does not exist to restore into with into_existing_table`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'errs', into_existing_table`, collection)
+		sqlDB.ExpectErr(t, `cannot restore into computed column "c" of table t`,
+			`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_existing_table, columns = (id, a, c)`, collection)
+	})
+}
@@ -584,6 +584,37 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 				return tree.ParseDJSON(x.(string))
 			},
 		)
+	case types.TSQueryFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return d.(*tree.DTSQuery).TSQuery.String(), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				return tree.ParseDTSQuery(x.(string))
+			},
+		)
+	case types.TSVectorFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return d.(*tree.DTSVector).TSVector.String(), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				return tree.ParseDTSVector(x.(string))
+			},
+		)
+	case types.RangeFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return tree.AsStringWithFlags(d, tree.FmtBareStrings), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				d, _, err := tree.ParseDRangeFromString(nil /* ctx */, x.(string), typ)
+				return d, err
+			},
+		)
 	case types.EnumFamily:
 		setNullable(
 			avroSchemaString,
@@ -421,19 +421,27 @@ func TestAvroSchema(t *testing.T) {
 			`BOX2D`:             `["null","string"]`,
 			`BYTES`:             `["null","bytes"]`,
 			`DATE`:              `["null",{"type":"int","logicalType":"date"}]`,
+			`DATERANGE`:         `["null","string"]`,
 			`FLOAT8`:            `["null","double"]`,
 			`GEOGRAPHY`:         `["null","bytes"]`,
 			`GEOMETRY`:          `["null","bytes"]`,
 			`INET`:              `["null","string"]`,
+			`INT4RANGE`:         `["null","string"]`,
 			`INT8`:              `["null","long"]`,
+			`INT8RANGE`:         `["null","string"]`,
 			`INTERVAL`:          `["null","string"]`,
 			`JSONB`:             `["null","string"]`,
+			`NUMRANGE`:          `["null","string"]`,
 			`STRING`:            `["null","string"]`,
 			`STRING COLLATE fr`: `["null","string"]`,
 			`TIME`:              `["null",{"type":"long","logicalType":"time-micros"}]`,
 			`TIMETZ`:            `["null","string"]`,
 			`TIMESTAMP`:         `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
 			`TIMESTAMPTZ`:       `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
+			`TSQUERY`:           `["null","string"]`,
+			`TSRANGE`:           `["null","string"]`,
+			`TSTZRANGE`:         `["null","string"]`,
+			`TSVECTOR`:          `["null","string"]`,
 			`UUID`:              `["null","string"]`,
 			`VARBIT`:            `["null",{"type":"array","items":"long"}]`,
 
@@ -0,0 +1,485 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// PrevRowName is the name under which the previous value of a changed row
+// can be referenced in a CDC query, e.g. `WHERE cdc_prev.status != status`.
+// References to it are only valid when the changefeed is created with the
+// `diff` option.
+const PrevRowName = "cdc_prev"
+
+// errPrevRowUnavailable is returned when a query references cdc_prev but the
+// previous row values are not available.
+var errPrevRowUnavailable = pgerror.Newf(pgcode.InvalidParameterValue,
+	"%s may only be referenced by changefeeds created with the diff option", PrevRowName)
+
+// cdcExprContext is used in error messages produced while type checking CDC
+// query expressions.
+const cdcExprContext = "CDC expression"
+
+// ParseChangefeedExpression parses the serialized form of a CDC query, as
+// stored in the changefeed job details, back into a select clause.
+func ParseChangefeedExpression(selectStr string) (*tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(selectStr)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.AssertionFailedf("expected SELECT statement, found %T", stmt.AST)
+	}
+	sc, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"unsupported CDC query %s", tree.AsString(sel))
+	}
+	return sc, nil
+}
+
+// ValidateSelectClause checks that the select clause is of the restricted
+// shape supported by changefeeds: a projection over a single table with an
+// optional filter. It returns the name of the watched table.
+func ValidateSelectClause(sc *tree.SelectClause) (*tree.TableName, error) {
+	unsupported := func(what string) error {
+		return pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported in CDC queries", what)
+	}
+	switch {
+	case sc.Distinct || len(sc.DistinctOn) > 0:
+		return nil, unsupported("DISTINCT")
+	case len(sc.GroupBy) > 0:
+		return nil, unsupported("GROUP BY")
+	case sc.Having != nil:
+		return nil, unsupported("HAVING")
+	case len(sc.Window) > 0:
+		return nil, unsupported("WINDOW")
+	case sc.From.AsOf.Expr != nil:
+		return nil, unsupported("AS OF SYSTEM TIME")
+	case sc.TableSelect:
+		return nil, unsupported("TABLE")
+	}
+	if len(sc.From.Tables) != 1 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue,
+			"CDC queries must select from exactly one table")
+	}
+	tn, _, err := tableNameAndAlias(sc.From.Tables[0])
+	return tn, err
+}
+
+// tableNameAndAlias extracts the watched table name, and the name under which
+// its columns may be referenced, from the FROM clause of a CDC query.
+func tableNameAndAlias(expr tree.TableExpr) (*tree.TableName, tree.Name, error) {
+	switch t := expr.(type) {
+	case *tree.TableName:
+		return t, t.ObjectName, nil
+	case *tree.AliasedTableExpr:
+		if len(t.As.Cols) > 0 {
+			return nil, "", pgerror.New(pgcode.FeatureNotSupported,
+				"column aliases are not supported in CDC queries")
+		}
+		tn, _, err := tableNameAndAlias(t.Expr)
+		if err != nil {
+			return nil, "", err
+		}
+		return tn, t.As.Alias, nil
+	default:
+		return nil, "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"CDC queries cannot select from %s", tree.AsString(expr))
+	}
+}
+
+// Evaluator evaluates the projection and the filter of a CDC query
+// (CREATE CHANGEFEED ... AS SELECT ...) over changed rows of the watched
+// table.
+//
+// Expressions are resolved against a particular version of the table
+// descriptor; the evaluator re-binds them whenever it is handed a row decoded
+// with a different descriptor version. Evaluator is not safe for concurrent
+// use.
+type Evaluator struct {
+	sc      *tree.SelectClause
+	alias   tree.Name
+	evalCtx *tree.EvalContext
+
+	// Descriptors which the expressions below are bound to.
+	desc, prevDesc catalog.TableDescriptor
+
+	filter     tree.TypedExpr
+	projection []tree.TypedExpr
+	resultCols colinfo.ResultColumns
+
+	ivars rowContainer
+	alloc tree.DatumAlloc
+}
+
+// Result is the result of evaluating a CDC query over a single row.
+type Result struct {
+	// Matches is set if the row satisfied the WHERE clause of the query.
+	Matches bool
+	// Columns and Datums are the projected row. They are only set if the row
+	// matched the filter.
+	Columns colinfo.ResultColumns
+	Datums  tree.Datums
+}
+
+// NewEvaluator returns an evaluator for the given CDC query. The eval context
+// is used for evaluating the expressions and is mutated by the evaluator, so
+// it should not be shared.
+func NewEvaluator(evalCtx *tree.EvalContext, sc *tree.SelectClause) (*Evaluator, error) {
+	if _, err := ValidateSelectClause(sc); err != nil {
+		return nil, err
+	}
+	_, alias, err := tableNameAndAlias(sc.From.Tables[0])
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{sc: sc, alias: alias, evalCtx: evalCtx}, nil
+}
+
+// Bind resolves and type checks the query against the specified descriptors.
+// prevDesc may be nil if the changefeed does not emit previous row values, in
+// which case the query may not reference cdc_prev. Bind is called implicitly
+// by Eval, but is exposed so that queries can be validated when a changefeed
+// is created.
+func (e *Evaluator) Bind(ctx context.Context, desc, prevDesc catalog.TableDescriptor) error {
+	if e.desc != nil && sameVersion(e.desc, desc) && sameVersion(e.prevDesc, prevDesc) {
+		return nil
+	}
+	e.desc, e.prevDesc = nil, nil
+	e.filter, e.projection, e.resultCols = nil, nil, nil
+
+	e.ivars.cols = desc.PublicColumns()
+	e.ivars.prevCols = nil
+	if prevDesc != nil {
+		e.ivars.prevCols = prevDesc.PublicColumns()
+	}
+	e.ivars.row, e.ivars.prevRow = nil, nil
+	ivarHelper := tree.MakeIndexedVarHelper(&e.ivars, len(e.ivars.cols)+len(e.ivars.prevCols))
+
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = &e.ivars
+	semaCtx.SearchPath = e.evalCtx.SessionData().SearchPath
+
+	typeCheck := func(expr tree.Expr, desired *types.T) (tree.TypedExpr, error) {
+		resolved, err := e.resolveNames(expr, &ivarHelper)
+		if err != nil {
+			return nil, err
+		}
+		semaCtx.Properties.Require(cdcExprContext,
+			tree.RejectSpecial|tree.RejectSubqueries|tree.RejectVolatileFunctions)
+		typedExpr, err := tree.TypeCheck(ctx, resolved, &semaCtx, desired)
+		if err != nil {
+			return nil, err
+		}
+		return e.evalCtx.NormalizeExpr(typedExpr)
+	}
+
+	for _, target := range e.sc.Exprs {
+		if err := e.bindTarget(target, typeCheck); err != nil {
+			return err
+		}
+	}
+
+	if e.sc.Where != nil {
+		filter, err := typeCheck(e.sc.Where.Expr, types.Bool)
+		if err != nil {
+			return err
+		}
+		if typ := filter.ResolvedType(); typ.Family() != types.BoolFamily && typ.Family() != types.UnknownFamily {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"argument of WHERE must be type bool, not type %s", typ)
+		}
+		e.filter = filter
+	}
+
+	e.desc, e.prevDesc = desc, prevDesc
+	return nil
+}
+
+// bindTarget adds the columns produced by a single element of the projection.
+func (e *Evaluator) bindTarget(
+	target tree.SelectExpr, typeCheck func(tree.Expr, *types.T) (tree.TypedExpr, error),
+) error {
+	vn, isVar := target.Expr.(tree.VarName)
+	if isVar {
+		var err error
+		if vn, err = vn.NormalizeVarName(); err != nil {
+			return err
+		}
+		star := false
+		switch t := vn.(type) {
+		case tree.UnqualifiedStar:
+			star = true
+		case *tree.AllColumnsSelector:
+			if tree.Name(t.TableName.Object()) != e.alias || t.TableName.NumParts > 1 {
+				return pgerror.Newf(pgcode.UndefinedTable,
+					"no data source matches pattern: %s", tree.ErrString(t))
+			}
+			star = true
+		}
+		if star {
+			if target.As != "" {
+				return pgerror.New(pgcode.Syntax, "\"*\" cannot be aliased")
+			}
+			for i, col := range e.ivars.cols {
+				if col.IsHidden() {
+					continue
+				}
+				e.projection = append(e.projection, tree.NewTypedOrdinalReference(i, col.GetType()))
+				e.resultCols = append(e.resultCols, colinfo.ResultColumn{Name: col.GetName(), Typ: col.GetType()})
+			}
+			return nil
+		}
+	}
+
+	expr, err := typeCheck(target.Expr, types.Any)
+	if err != nil {
+		return err
+	}
+	name, err := tree.GetRenderColName(e.evalCtx.SessionData().SearchPath, target)
+	if err != nil {
+		return err
+	}
+	e.projection = append(e.projection, expr)
+	e.resultCols = append(e.resultCols, colinfo.ResultColumn{Name: name, Typ: expr.ResolvedType()})
+	return nil
+}
+
+// Eval evaluates the query over a changed row. The row datums must correspond
+// to the public columns of desc; prevDatums, if non-nil, must correspond to the
+// public columns of prevDesc.
+func (e *Evaluator) Eval(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	datums rowenc.EncDatumRow,
+	prevDesc catalog.TableDescriptor,
+	prevDatums rowenc.EncDatumRow,
+) (Result, error) {
+	if prevDatums == nil {
+		prevDesc = nil
+	}
+	if err := e.Bind(ctx, desc, prevDesc); err != nil {
+		return Result{}, err
+	}
+	if err := e.ivars.setRow(datums, prevDatums, &e.alloc); err != nil {
+		return Result{}, err
+	}
+
+	e.evalCtx.PushIVarContainer(&e.ivars)
+	defer e.evalCtx.PopIVarContainer()
+
+	if e.filter != nil {
+		d, err := e.filter.Eval(e.evalCtx)
+		if err != nil {
+			return Result{}, err
+		}
+		if d != tree.DBoolTrue {
+			return Result{}, nil
+		}
+	}
+
+	res := Result{
+		Matches: true,
+		Columns: e.resultCols,
+		Datums:  make(tree.Datums, len(e.projection)),
+	}
+	for i, expr := range e.projection {
+		d, err := expr.Eval(e.evalCtx)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Datums[i] = d
+	}
+	return res, nil
+}
+
+// resolveNames replaces column references in the expression with indexed
+// variables bound to the evaluator's row container.
+func (e *Evaluator) resolveNames(expr tree.Expr, ivarHelper *tree.IndexedVarHelper) (tree.Expr, error) {
+	v := nameResolver{e: e, ivarHelper: ivarHelper}
+	expr, _ = tree.WalkExpr(&v, expr)
+	return expr, v.err
+}
+
+// nameResolver is a tree.Visitor which resolves column references against
+// the current and, under the cdc_prev prefix, the previous row.
+type nameResolver struct {
+	e          *Evaluator
+	ivarHelper *tree.IndexedVarHelper
+	err        error
+}
+
+var _ tree.Visitor = &nameResolver{}
+
+// VisitPre implements tree.Visitor.
+func (v *nameResolver) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+
+	switch t := expr.(type) {
+	case *tree.UnresolvedName:
+		vn, err := t.NormalizeVarName()
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		return v.VisitPre(vn)
+
+	case *tree.ColumnItem:
+		idx, err := v.resolveColumn(t)
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		return false, v.ivarHelper.IndexedVar(idx)
+
+	case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+		v.err = pgerror.Newf(pgcode.Syntax, "%q is not allowed in this context", tree.AsString(t))
+		return false, expr
+
+	case *tree.IndexedVar:
+		v.err = pgerror.Newf(pgcode.Syntax,
+			"ordinal column references are not allowed in CDC queries")
+		return false, expr
+
+	case *tree.Subquery:
+		v.err = pgerror.New(pgcode.FeatureNotSupported, "subqueries are not supported in CDC queries")
+		return false, expr
+	}
+
+	return true, expr
+}
+
+// VisitPost implements tree.Visitor.
+func (*nameResolver) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// resolveColumn returns the ordinal of the column in the row container.
+func (v *nameResolver) resolveColumn(c *tree.ColumnItem) (int, error) {
+	cols, offset := v.e.ivars.cols, 0
+	if c.TableName != nil {
+		if c.TableName.NumParts > 1 {
+			return 0, pgerror.Newf(pgcode.FeatureNotSupported,
+				"column reference %q must not be qualified by schema or database", tree.ErrString(c))
+		}
+		switch tree.Name(c.TableName.Object()) {
+		case v.e.alias:
+		case PrevRowName:
+			if len(v.e.ivars.prevCols) == 0 {
+				return 0, errPrevRowUnavailable
+			}
+			cols, offset = v.e.ivars.prevCols, len(v.e.ivars.cols)
+		default:
+			return 0, pgerror.Newf(pgcode.UndefinedTable,
+				"missing FROM-clause entry for table %q", c.TableName.Object())
+		}
+	}
+	for i, col := range cols {
+		if col.GetName() == c.Column() {
+			return offset + i, nil
+		}
+	}
+	return 0, colinfo.NewUndefinedColumnError(tree.ErrString(c))
+}
+
+// rowContainer is the tree.IndexedVarContainer for CDC query expressions.
+// Ordinals [0, len(cols)) refer to the current row, and ordinals following it
+// refer to the previous row.
+type rowContainer struct {
+	cols, prevCols []catalog.Column
+	row, prevRow   rowenc.EncDatumRow
+}
+
+var _ tree.IndexedVarContainer = &rowContainer{}
+
+func (c *rowContainer) setRow(
+	row, prevRow rowenc.EncDatumRow, alloc *tree.DatumAlloc,
+) error {
+	if len(row) != len(c.cols) {
+		return errors.AssertionFailedf("expected %d datums, found %d", len(c.cols), len(row))
+	}
+	for i := range row {
+		if err := row[i].EnsureDecoded(c.cols[i].GetType(), alloc); err != nil {
+			return err
+		}
+	}
+	if prevRow != nil {
+		if len(prevRow) != len(c.prevCols) {
+			return errors.AssertionFailedf("expected %d previous datums, found %d",
+				len(c.prevCols), len(prevRow))
+		}
+		for i := range prevRow {
+			if err := prevRow[i].EnsureDecoded(c.prevCols[i].GetType(), alloc); err != nil {
+				return err
+			}
+		}
+	}
+	c.row, c.prevRow = row, prevRow
+	return nil
+}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	if idx < len(c.cols) {
+		return c.row[idx].Datum, nil
+	}
+	idx -= len(c.cols)
+	if c.prevRow == nil || idx >= len(c.prevRow) {
+		return tree.DNull, nil
+	}
+	return c.prevRow[idx].Datum, nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarResolvedType(idx int) *types.T {
+	if idx < len(c.cols) {
+		return c.cols[idx].GetType()
+	}
+	idx -= len(c.cols)
+	if idx >= len(c.prevCols) {
+		return types.Unknown
+	}
+	return c.prevCols[idx].GetType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	if idx < len(c.cols) {
+		n := tree.Name(c.cols[idx].GetName())
+		return &n
+	}
+	idx -= len(c.cols)
+	if idx >= len(c.prevCols) {
+		return nil
+	}
+	return &tree.ColumnItem{
+		TableName:  &tree.UnresolvedObjectName{NumParts: 1, Parts: [3]string{PrevRowName}},
+		ColumnName: tree.Name(c.prevCols[idx].GetName()),
+	}
+}
+
+func sameVersion(a, b catalog.TableDescriptor) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.GetID() == b.GetID() && a.GetVersion() == b.GetVersion()
+}
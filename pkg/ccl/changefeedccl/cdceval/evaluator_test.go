@@ -0,0 +1,182 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+
+	desc, err := sql.CreateTestTableDescriptor(ctx, 52 /* parentID */, 53, /* id */
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`,
+		descpb.NewBasePrivilegeDescriptor(security.RootUserName()))
+	require.NoError(t, err)
+
+	row := func(a int, b string, c int) rowenc.EncDatumRow {
+		return rowenc.EncDatumRow{
+			{Datum: tree.NewDInt(tree.DInt(a))},
+			{Datum: tree.NewDString(b)},
+			{Datum: tree.NewDInt(tree.DInt(c))},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		row      rowenc.EncDatumRow
+		prev     rowenc.EncDatumRow
+		matches  bool
+		cols     []string
+		datums   string
+		errRegex string
+	}{
+		{
+			name:    "star",
+			query:   `SELECT * FROM foo`,
+			row:     row(1, "one", 10),
+			matches: true,
+			cols:    []string{"a", "b", "c"},
+			datums:  `(1, 'one', 10)`,
+		},
+		{
+			name:    "projection",
+			query:   `SELECT a, upper(b) AS ub, c + 1 FROM foo`,
+			row:     row(1, "one", 10),
+			matches: true,
+			cols:    []string{"a", "ub", "?column?"},
+			datums:  `(1, 'ONE', 11)`,
+		},
+		{
+			name:    "filter matches",
+			query:   `SELECT a FROM foo AS f WHERE f.b = 'shipped' AND c > 5`,
+			row:     row(1, "shipped", 10),
+			matches: true,
+			cols:    []string{"a"},
+			datums:  `(1)`,
+		},
+		{
+			name:    "filter does not match",
+			query:   `SELECT a FROM foo WHERE b = 'shipped'`,
+			row:     row(1, "pending", 10),
+			matches: false,
+		},
+		{
+			name:    "null filter does not match",
+			query:   `SELECT a FROM foo WHERE c > NULL`,
+			row:     row(1, "pending", 10),
+			matches: false,
+		},
+		{
+			name:    "previous row",
+			query:   `SELECT a, cdc_prev.b AS old_b FROM foo WHERE b != cdc_prev.b`,
+			row:     row(1, "shipped", 10),
+			prev:    row(1, "pending", 10),
+			matches: true,
+			cols:    []string{"a", "old_b"},
+			datums:  `(1, 'pending')`,
+		},
+		{
+			name:     "previous row requires diff",
+			query:    `SELECT a FROM foo WHERE b != cdc_prev.b`,
+			row:      row(1, "shipped", 10),
+			errRegex: `cdc_prev may only be referenced by changefeeds created with the diff option`,
+		},
+		{
+			name:     "unknown column",
+			query:    `SELECT d FROM foo`,
+			row:      row(1, "one", 10),
+			errRegex: `column "d" does not exist`,
+		},
+		{
+			name:     "unknown table",
+			query:    `SELECT bar.a FROM foo`,
+			row:      row(1, "one", 10),
+			errRegex: `missing FROM-clause entry for table "bar"`,
+		},
+		{
+			name:     "volatile function",
+			query:    `SELECT a FROM foo WHERE random() > 0.5`,
+			row:      row(1, "one", 10),
+			errRegex: `volatile functions are not allowed in CDC expression`,
+		},
+		{
+			name:     "aggregate",
+			query:    `SELECT sum(a) FROM foo`,
+			row:      row(1, "one", 10),
+			errRegex: `aggregate functions are not allowed in CDC expression`,
+		},
+		{
+			name:     "non-boolean filter",
+			query:    `SELECT a FROM foo WHERE a`,
+			row:      row(1, "one", 10),
+			errRegex: `argument of WHERE must be type bool, not type int`,
+		},
+		{
+			name:     "group by",
+			query:    `SELECT a FROM foo GROUP BY a`,
+			errRegex: `GROUP BY is not supported in CDC queries`,
+		},
+		{
+			name:     "join",
+			query:    `SELECT a FROM foo, bar`,
+			errRegex: `CDC queries must select from exactly one table`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.query)
+			require.NoError(t, err)
+			e, err := NewEvaluator(evalCtx.Copy(), sc)
+			if err == nil {
+				var prevDesc catalog.TableDescriptor
+				if tc.prev != nil {
+					prevDesc = desc
+				}
+				var res Result
+				res, err = e.Eval(ctx, desc, tc.row, prevDesc, tc.prev)
+				if err == nil {
+					require.Equal(t, tc.matches, res.Matches)
+					if tc.matches {
+						var cols []string
+						for _, c := range res.Columns {
+							cols = append(cols, c.Name)
+						}
+						require.Equal(t, tc.cols, cols)
+						require.Equal(t, tc.datums, tree.AsString(&res.Datums))
+					}
+				}
+			}
+			if tc.errRegex == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Regexp(t, tc.errRegex, err.Error())
+			}
+		})
+	}
+}
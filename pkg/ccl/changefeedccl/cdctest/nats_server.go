@@ -0,0 +1,294 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// NATSAuth is the method with which clients authenticate to a TestNATSServer.
+type NATSAuth int
+
+const (
+	// NATSAuthNone lets any client connect.
+	NATSAuthNone NATSAuth = iota
+	// NATSAuthUserPassword requires a user and a password.
+	NATSAuthUserPassword
+	// NATSAuthToken requires a token.
+	NATSAuthToken
+	// NATSAuthJWT requires the JWT of a user, issued by an account trusted by
+	// the operator of the server, and the signature of the nonce of the server
+	// with the NKey of the user.
+	NATSAuthJWT
+)
+
+func (a NATSAuth) String() string {
+	switch a {
+	case NATSAuthNone:
+		return "none"
+	case NATSAuthUserPassword:
+		return "user"
+	case NATSAuthToken:
+		return "token"
+	case NATSAuthJWT:
+		return "jwt"
+	default:
+		return fmt.Sprintf("NATSAuth(%d)", int(a))
+	}
+}
+
+// TestNATSServer is an embedded NATS server used in tests, with JetStream
+// enabled and a stream which stores the messages published to the subjects
+// with its prefix.
+type TestNATSServer struct {
+	server   *server.Server
+	storeDir string
+	stream   string
+
+	auth               NATSAuth
+	username, password string
+	token              string
+	userJWT, nkeySeed  string
+	// caCert is the PEM encoded certificate of the CA of the server, if clients
+	// connect to it with TLS.
+	caCert []byte
+}
+
+// StartTestNATSServer starts an embedded NATS server with JetStream enabled,
+// and creates a stream which stores the messages published to the subjects
+// starting with the name of the stream followed by a dot. If certificate is
+// not nil, clients connect with TLS and verify the server with the CA
+// certificate caCertBase64.
+func StartTestNATSServer(
+	auth NATSAuth, stream string, certificate *tls.Certificate, caCertBase64 string,
+) (*TestNATSServer, error) {
+	storeDir, err := ioutil.TempDir("", "nats")
+	if err != nil {
+		return nil, err
+	}
+	s := &TestNATSServer{storeDir: storeDir, stream: stream, auth: auth}
+	opts := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  storeDir,
+	}
+	if certificate != nil {
+		if s.caCert, err = base64.StdEncoding.DecodeString(caCertBase64); err != nil {
+			return nil, errors.CombineErrors(err, os.RemoveAll(storeDir))
+		}
+		opts.TLS = true
+		opts.TLSConfig = &tls.Config{Certificates: []tls.Certificate{*certificate}}
+	}
+	if err := s.configureAuth(opts); err != nil {
+		return nil, errors.CombineErrors(err, os.RemoveAll(storeDir))
+	}
+
+	s.server, err = server.NewServer(opts)
+	if err != nil {
+		return nil, errors.CombineErrors(err, os.RemoveAll(storeDir))
+	}
+	go s.server.Start()
+	if !s.server.ReadyForConnections(10 * time.Second) {
+		s.Close()
+		return nil, errors.New("nats server is not ready for connections")
+	}
+
+	if err := s.addStream(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// configureAuth sets up the server so that clients must authenticate with the
+// method of the server, and generates their credentials.
+func (s *TestNATSServer) configureAuth(opts *server.Options) error {
+	switch s.auth {
+	case NATSAuthNone:
+	case NATSAuthUserPassword:
+		s.username, s.password = "changefeed", "hunter2"
+		opts.Username, opts.Password = s.username, s.password
+	case NATSAuthToken:
+		s.token = "s3cr3t"
+		opts.Authorization = s.token
+	case NATSAuthJWT:
+		// The operator of the server issues the JWTs of the system account,
+		// which JetStream requires, and of the account of the user, which must
+		// be allowed to use JetStream.
+		operatorKP, err := nkeys.CreateOperator()
+		if err != nil {
+			return err
+		}
+		operatorPub, err := operatorKP.PublicKey()
+		if err != nil {
+			return err
+		}
+		operatorJWT, err := jwt.NewOperatorClaims(operatorPub).Encode(operatorKP)
+		if err != nil {
+			return err
+		}
+		operatorClaims, err := jwt.DecodeOperatorClaims(operatorJWT)
+		if err != nil {
+			return err
+		}
+		resolver := &server.MemAccResolver{}
+		addAccount := func(jetStream bool) (nkeys.KeyPair, string, error) {
+			accountKP, err := nkeys.CreateAccount()
+			if err != nil {
+				return nil, "", err
+			}
+			accountPub, err := accountKP.PublicKey()
+			if err != nil {
+				return nil, "", err
+			}
+			claims := jwt.NewAccountClaims(accountPub)
+			if jetStream {
+				claims.Limits.JetStreamLimits = jwt.JetStreamLimits{
+					MemoryStorage: -1, DiskStorage: -1, Streams: -1, Consumer: -1,
+				}
+			}
+			accountJWT, err := claims.Encode(operatorKP)
+			if err != nil {
+				return nil, "", err
+			}
+			return accountKP, accountPub, resolver.Store(accountPub, accountJWT)
+		}
+		_, systemPub, err := addAccount(false /* jetStream */)
+		if err != nil {
+			return err
+		}
+		accountKP, _, err := addAccount(true /* jetStream */)
+		if err != nil {
+			return err
+		}
+
+		userKP, err := nkeys.CreateUser()
+		if err != nil {
+			return err
+		}
+		userPub, err := userKP.PublicKey()
+		if err != nil {
+			return err
+		}
+		if s.userJWT, err = jwt.NewUserClaims(userPub).Encode(accountKP); err != nil {
+			return err
+		}
+		seed, err := userKP.Seed()
+		if err != nil {
+			return err
+		}
+		s.nkeySeed = string(seed)
+
+		opts.TrustedOperators = []*jwt.OperatorClaims{operatorClaims}
+		opts.AccountResolver = resolver
+		opts.SystemAccount = systemPub
+	default:
+		return errors.Errorf("unknown nats auth method %s", s.auth)
+	}
+	return nil
+}
+
+// addStream creates the stream of the server.
+func (s *TestNATSServer) addStream() error {
+	conn, err := s.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	js, err := conn.JetStream()
+	if err != nil {
+		return err
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     s.stream,
+		Subjects: []string{s.stream + ".>"},
+	})
+	return err
+}
+
+// SinkURI returns the URI of a changefeed sink which publishes to the stream of
+// the server, with the credentials of the authentication method of the server.
+func (s *TestNATSServer) SinkURI() string {
+	u := url.URL{Scheme: "nats", Host: s.server.Addr().String()}
+	params := url.Values{}
+	params.Set("topic_prefix", s.stream+".")
+	if s.caCert != nil {
+		params.Set("tls_enabled", "true")
+		params.Set("ca_cert", base64.StdEncoding.EncodeToString(s.caCert))
+	}
+	switch s.auth {
+	case NATSAuthUserPassword:
+		u.User = url.UserPassword(s.username, s.password)
+	case NATSAuthToken:
+		params.Set("token", s.token)
+	case NATSAuthJWT:
+		params.Set("user_jwt", s.userJWT)
+		params.Set("nkey_seed", s.nkeySeed)
+	}
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+// Connect returns a connection to the server, authenticated with the
+// credentials of the authentication method of the server.
+func (s *TestNATSServer) Connect() (*nats.Conn, error) {
+	var opts []nats.Option
+	if s.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(s.caCert)
+		opts = append(opts, nats.Secure(&tls.Config{RootCAs: caCertPool}))
+	}
+	switch s.auth {
+	case NATSAuthUserPassword:
+		opts = append(opts, nats.UserInfo(s.username, s.password))
+	case NATSAuthToken:
+		opts = append(opts, nats.Token(s.token))
+	case NATSAuthJWT:
+		userKP, err := nkeys.FromSeed([]byte(s.nkeySeed))
+		if err != nil {
+			return nil, err
+		}
+		userJWT := s.userJWT
+		opts = append(opts, nats.UserJWT(
+			func() (string, error) { return userJWT, nil },
+			userKP.Sign,
+		))
+	}
+	return nats.Connect(s.server.ClientURL(), opts...)
+}
+
+// Stream returns the name of the stream of the server.
+func (s *TestNATSServer) Stream() string {
+	return s.stream
+}
+
+// Close shuts down the server, and removes the messages stored in its stream.
+func (s *TestNATSServer) Close() {
+	if s.server != nil {
+		s.server.Shutdown()
+		s.server.WaitForShutdown()
+	}
+	_ = os.RemoveAll(s.storeDir)
+}
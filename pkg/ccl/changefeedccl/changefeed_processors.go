@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcutils"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeeddist"
@@ -282,9 +283,14 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	if ca.spec.Feed.Opts[changefeedbase.OptFormat] == string(changefeedbase.OptFormatNative) {
 		ca.eventConsumer = newNativeKVConsumer(ca.sink)
 	} else {
-		ca.eventConsumer = newKVEventToRowConsumer(
-			ctx, ca.flowCtx.Cfg, ca.frontier.SpanFrontier(), initialHighWater,
+		ca.eventConsumer, err = newKVEventToRowConsumer(
+			ctx, ca.flowCtx.Cfg, ca.flowCtx.NewEvalCtx(), ca.frontier.SpanFrontier(), initialHighWater,
 			ca.sink, ca.encoder, ca.spec.Feed, ca.knobs)
+		if err != nil {
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
 	}
 }
 
@@ -634,6 +640,15 @@ type kvEventToRowConsumer struct {
 	rfCache   *rowFetcherCache
 	details   jobspb.ChangefeedDetails
 	kvFetcher row.SpanKVFetcher
+
+	// evaluator is set if the changefeed was created as a CDC query; it
+	// filters and projects rows before they are encoded.
+	evaluator *cdceval.Evaluator
+
+	// rowSink is set if the changefeed's format requires the sink to encode
+	// rows itself, in which case the encoder is only used for resolved
+	// timestamps.
+	rowSink rowEncodingSink
 }
 
 var _ kvEventConsumer = &kvEventToRowConsumer{}
@@ -641,13 +656,14 @@ var _ kvEventConsumer = &kvEventToRowConsumer{}
 func newKVEventToRowConsumer(
 	ctx context.Context,
 	cfg *execinfra.ServerConfig,
+	evalCtx *tree.EvalContext,
 	frontier *span.Frontier,
 	cursor hlc.Timestamp,
 	sink Sink,
 	encoder Encoder,
 	details jobspb.ChangefeedDetails,
 	knobs TestingKnobs,
-) kvEventConsumer {
+) (kvEventConsumer, error) {
 	rfCache := newRowFetcherCache(
 		ctx,
 		cfg.Codec,
@@ -656,15 +672,37 @@ func newKVEventToRowConsumer(
 		cfg.DB,
 	)
 
-	return &kvEventToRowConsumer{
-		frontier: frontier,
-		encoder:  encoder,
-		sink:     sink,
-		cursor:   cursor,
-		rfCache:  rfCache,
-		details:  details,
-		knobs:    knobs,
+	var evaluator *cdceval.Evaluator
+	if details.Select != `` {
+		sc, err := cdceval.ParseChangefeedExpression(details.Select)
+		if err != nil {
+			return nil, err
+		}
+		if evaluator, err = cdceval.NewEvaluator(evalCtx, sc); err != nil {
+			return nil, err
+		}
+	}
+
+	var rowSink rowEncodingSink
+	if format := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]); format == changefeedbase.OptFormatParquet {
+		var ok bool
+		if rowSink, ok = sink.(rowEncodingSink); !ok {
+			return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+				changefeedbase.OptFormat, format)
+		}
 	}
+
+	return &kvEventToRowConsumer{
+		frontier:  frontier,
+		encoder:   encoder,
+		sink:      sink,
+		cursor:    cursor,
+		rfCache:   rfCache,
+		details:   details,
+		knobs:     knobs,
+		evaluator: evaluator,
+		rowSink:   rowSink,
+	}, nil
 }
 
 type tableDescriptorTopic struct {
@@ -695,6 +733,28 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 			"or equal to the local frontier %s.", r.updated, c.frontier.Frontier())
 		return nil
 	}
+
+	if c.evaluator != nil {
+		matches, err := c.evaluateCDCQuery(ctx, &r)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			a := ev.DetachAlloc()
+			a.Release(ctx)
+			return nil
+		}
+	}
+
+	if c.rowSink != nil {
+		if c.knobs.BeforeEmitRow != nil {
+			if err := c.knobs.BeforeEmitRow(ctx); err != nil {
+				return err
+			}
+		}
+		return c.rowSink.EncodeAndEmitRow(ctx, tableDescriptorTopic{r.tableDesc}, r, ev.DetachAlloc())
+	}
+
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, r)
 	if err != nil {
@@ -724,6 +784,43 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	return nil
 }
 
+// evaluateCDCQuery applies the changefeed's CDC query to the row, setting its
+// projection. It returns false if the row should not be emitted.
+//
+// Deletions only carry the primary key of the row, so the filter is evaluated
+// against the previous value of the row (standing in for both the current and
+// the cdc_prev row) when it is available, i.e. with the diff option; otherwise
+// deletions are always emitted.
+func (c *kvEventToRowConsumer) evaluateCDCQuery(ctx context.Context, r *encodeRow) (bool, error) {
+	if r.deleted {
+		if r.prevDatums == nil || r.prevDeleted {
+			return true, nil
+		}
+		res, err := c.evaluator.Eval(ctx, r.prevTableDesc, r.prevDatums, r.prevTableDesc, r.prevDatums)
+		if err != nil {
+			return false, err
+		}
+		return res.Matches, nil
+	}
+
+	prevDatums := r.prevDatums
+	if r.prevDeleted {
+		// The row did not previously exist; all cdc_prev references are NULL.
+		prevDatums = make(rowenc.EncDatumRow, len(r.prevTableDesc.PublicColumns()))
+		for i := range prevDatums {
+			prevDatums[i] = rowenc.EncDatum{Datum: tree.DNull}
+		}
+	}
+	res, err := c.evaluator.Eval(ctx, r.tableDesc, r.datums, r.prevTableDesc, prevDatums)
+	if err != nil {
+		return false, err
+	}
+	if res.Matches {
+		r.projection = &res
+	}
+	return res.Matches, nil
+}
+
 func (c *kvEventToRowConsumer) eventToRow(
 	ctx context.Context, event kvevent.Event,
 ) (encodeRow, error) {
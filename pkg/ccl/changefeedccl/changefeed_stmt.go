@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
@@ -197,14 +198,24 @@ func changefeedPlanHook(
 			statementTime = initialHighWater
 		}
 
+		targetList := changefeedStmt.Targets
+		if changefeedStmt.Select != nil {
+			// A CDC query watches the single table in its FROM clause.
+			tn, err := cdceval.ValidateSelectClause(changefeedStmt.Select)
+			if err != nil {
+				return err
+			}
+			targetList = tree.TargetList{Tables: tree.TablePatterns{tn}}
+		}
+
 		// For now, disallow targeting a database or wildcard table selection.
 		// Getting it right as tables enter and leave the set over time is
 		// tricky.
-		if len(changefeedStmt.Targets.Databases) > 0 {
+		if len(targetList.Databases) > 0 {
 			return errors.Errorf(`CHANGEFEED cannot target %s`,
-				tree.AsString(&changefeedStmt.Targets))
+				tree.AsString(&targetList))
 		}
-		for _, t := range changefeedStmt.Targets.Tables {
+		for _, t := range targetList.Tables {
 			p, err := t.NormalizeTablePattern()
 			if err != nil {
 				return err
@@ -216,7 +227,7 @@ func changefeedPlanHook(
 
 		// This grabs table descriptors once to get their ids.
 		targetDescs, _, err := backupresolver.ResolveTargetsToDescriptors(
-			ctx, p, statementTime, &changefeedStmt.Targets)
+			ctx, p, statementTime, &targetList)
 		if err != nil {
 			var m *backupresolver.MissingTableErr
 			if errors.As(err, &m) {
@@ -262,6 +273,9 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if changefeedStmt.Select != nil {
+			details.Select = tree.AsStringWithFlags(changefeedStmt.Select, tree.FmtParsable)
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{},
 			Details: &jobspb.Progress_Changefeed{
@@ -317,6 +331,30 @@ func changefeedPlanHook(
 			return err
 		}
 
+		if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatParquet {
+			for _, desc := range targetDescs {
+				if table, ok := desc.(catalog.TableDescriptor); ok {
+					if err := validateParquetTable(table, details.Opts); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if changefeedStmt.Select != nil {
+			if err := validateCDCQuery(ctx, p, changefeedStmt.Select, details.Opts, targetDescs); err != nil {
+				return err
+			}
+		}
+
+		switch format := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]); format {
+		case changefeedbase.OptFormatCSV, changefeedbase.OptFormatParquet:
+			if !isCloudStorageSink(parsedSink) {
+				return errors.Errorf(`%s=%s is only supported by cloud storage sinks`,
+					changefeedbase.OptFormat, format)
+			}
+		}
+
 		if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
@@ -340,6 +378,9 @@ func changefeedPlanHook(
 		telemetry.Count(`changefeed.create.sink.` + telemetrySink)
 		telemetry.Count(`changefeed.create.format.` + details.Opts[changefeedbase.OptFormat])
 		telemetry.CountBucketed(`changefeed.create.num_tables`, int64(len(targets)))
+		if changefeedStmt.Select != nil {
+			telemetry.Count(`changefeed.create.cdc_query`)
+		}
 
 		if scope, ok := opts[changefeedbase.OptMetricsScope]; ok {
 			if err := utilccl.CheckEnterpriseEnabled(
@@ -490,13 +531,25 @@ func changefeedPlanHook(
 func changefeedJobDescription(
 	p sql.PlanHookState, changefeed *tree.CreateChangefeed, sinkURI string, opts map[string]string,
 ) (string, error) {
-	cleanedSinkURI, err := cloud.SanitizeExternalStorageURI(sinkURI, []string{changefeedbase.SinkParamSASLPassword})
+	cleanedSinkURI, err := cloud.SanitizeExternalStorageURI(sinkURI, []string{
+		changefeedbase.SinkParamSASLPassword,
+		changefeedbase.SinkParamNATSToken,
+		changefeedbase.SinkParamNATSNKeySeed,
+	})
 	if err != nil {
 		return "", err
 	}
+	// So is the password of the user of the sink, such as the one of a NATS user.
+	if u, err := url.Parse(cleanedSinkURI); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "redacted")
+			cleanedSinkURI = u.String()
+		}
+	}
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
 		SinkURI: tree.NewDString(cleanedSinkURI),
+		Select:  changefeed.Select,
 	}
 	for k, v := range opts {
 		if k == changefeedbase.OptWebhookAuthHeader {
@@ -513,6 +566,41 @@ func changefeedJobDescription(
 	return tree.AsStringWithFQNames(c, ann), nil
 }
 
+// validateCDCQuery checks that the CDC query of a changefeed can be evaluated
+// over the rows of its target table with the given options.
+func validateCDCQuery(
+	ctx context.Context,
+	p sql.PlanHookState,
+	sc *tree.SelectClause,
+	opts map[string]string,
+	targetDescs []catalog.Descriptor,
+) error {
+	if format := changefeedbase.FormatType(opts[changefeedbase.OptFormat]); format != changefeedbase.OptFormatJSON {
+		return errors.Errorf(`%s=%s is not supported with CDC queries`, changefeedbase.OptFormat, format)
+	}
+	if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeKeyOnly {
+		return errors.Errorf(`%s=%s is not supported with CDC queries`,
+			changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeKeyOnly)
+	}
+	if len(targetDescs) != 1 {
+		return errors.AssertionFailedf("expected a single target for CDC query, found %d", len(targetDescs))
+	}
+	table, ok := targetDescs[0].(catalog.TableDescriptor)
+	if !ok {
+		return errors.Errorf(`CHANGEFEED cannot target %s`, targetDescs[0].GetName())
+	}
+
+	evaluator, err := cdceval.NewEvaluator(p.ExtendedEvalContext().EvalContext.Copy(), sc)
+	if err != nil {
+		return err
+	}
+	var prevDesc catalog.TableDescriptor
+	if _, withDiff := opts[changefeedbase.OptDiff]; withDiff {
+		prevDesc = table
+	}
+	return evaluator.Bind(ctx, table, prevDesc)
+}
+
 // validateNonNegativeDuration returns a nil error if optValue can be
 // parsed as a duration and is non-negative; otherwise, an error is
 // returned.
@@ -602,7 +690,8 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro,
+			changefeedbase.OptFormatCSV, changefeedbase.OptFormatParquet:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
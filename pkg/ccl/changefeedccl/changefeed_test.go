@@ -125,11 +125,86 @@ func TestChangefeedBasics(t *testing.T) {
 	t.Run(`kafka`, kafkaTest(testFn))
 	t.Run(`webhook`, webhookTest(testFn))
 	t.Run(`pubsub`, pubsubTest(testFn))
+	t.Run(`nats`, natsTest(testFn))
 
 	// NB running TestChangefeedBasics, which includes a DELETE, with
 	// cloudStorageTest is a regression test for #36994.
 }
 
+func TestChangefeedCDCQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'pending', 10), (1, 'shipped', 20)`)
+		foo := feed(t, f, `CREATE CHANGEFEED AS SELECT a, c * 2 AS double_c FROM foo WHERE b = 'shipped'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "double_c": 40}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'pending', 30), (3, 'shipped', 40)`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'shipped' WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "double_c": 80}}`,
+			`foo: [0]->{"after": {"a": 0, "double_c": 20}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
+func TestChangefeedCDCQueryWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'pending')`)
+		foo := feed(t, f, `CREATE CHANGEFEED WITH diff, no_initial_scan AS `+
+			`SELECT a, cdc_prev.b AS old_b, b FROM foo WHERE b IS DISTINCT FROM cdc_prev.b`)
+		defer closeFeed(t, foo)
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'pending' WHERE a = 0`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'shipped' WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "shipped", "old_b": "pending"}, "before": {"a": 0, "b": "pending"}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedCDCQueryValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+		sqlDB.ExpectErr(t, `column "c" does not exist`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' AS SELECT c FROM foo`)
+		sqlDB.ExpectErr(t, `cdc_prev may only be referenced by changefeeds created with the diff option`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' AS SELECT a FROM foo WHERE cdc_prev.b = b`)
+		sqlDB.ExpectErr(t, `volatile functions are not allowed in CDC expression`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' AS SELECT a FROM foo WHERE random() > 0.5`)
+		sqlDB.ExpectErr(t, `format=avro is not supported with CDC queries`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' WITH format=avro, confluent_schema_registry='http://nope' AS SELECT a FROM foo`)
+		sqlDB.ExpectErr(t, `missing FROM-clause entry for table "bar"`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' AS SELECT bar.a FROM foo`)
+	}
+
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedBasicConfluentKafka(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -553,6 +628,7 @@ func TestChangefeedTimestamps(t *testing.T) {
 	t.Run(`kafka`, kafkaTest(testFn))
 	t.Run(`webhook`, webhookTest(testFn))
 	t.Run(`pubsub`, pubsubTest(testFn))
+	t.Run(`nats`, natsTest(testFn))
 }
 
 func TestChangefeedMVCCTimestamps(t *testing.T) {
@@ -1522,7 +1598,7 @@ func fetchDescVersionModificationTime(
 			if err := value.GetProto(&desc); err != nil {
 				t.Fatal(err)
 			}
-			if tableDesc, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
+			if tableDesc, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
 				if int(tableDesc.Version) == version {
 					return tableDesc.ModificationTime
 				}
@@ -3182,6 +3258,29 @@ func TestChangefeedErrors(t *testing.T) {
 		`experimental-nodelocal://0/bar`,
 	)
 
+	// The csv and parquet formats are only supported by the cloudStorageSink.
+	sqlDB.ExpectErr(
+		t, `format=csv is only supported by cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='csv'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=parquet is only supported by cloud storage sinks`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH format='parquet'`,
+	)
+	sqlDB.ExpectErr(
+		t, `diff is not supported with format=csv`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='csv', diff`, `nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `compression is not supported with format=parquet`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='parquet', compression='gzip'`, `nodelocal://0/bar`,
+	)
+	sqlDB.Exec(t, `CREATE TABLE parquet_unsupported (a INT PRIMARY KEY, b INTERVAL)`)
+	sqlDB.ExpectErr(
+		t, `column b of table parquet_unsupported: parquet does not support the IntervalFamily type yet`,
+		`CREATE CHANGEFEED FOR parquet_unsupported INTO $1 WITH format='parquet'`, `nodelocal://0/bar`,
+	)
+
 	// WITH key_in_value requires envelope=wrapped
 	sqlDB.ExpectErr(
 		t, `key_in_value is only usable with envelope=wrapped`,
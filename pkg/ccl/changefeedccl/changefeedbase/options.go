@@ -90,8 +90,10 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `avro`
+	OptFormatJSON    FormatType = `json`
+	OptFormatAvro    FormatType = `avro`
+	OptFormatCSV     FormatType = `csv`
+	OptFormatParquet FormatType = `parquet`
 
 	OptFormatNative FormatType = `native`
 
@@ -109,11 +111,16 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptNATSSinkConfig is a JSON configuration for nats sink (natsSinkConfig).
+	OptNATSSinkConfig = `nats_sink_config`
 
 	SinkParamCACert                 = `ca_cert`
 	SinkParamClientCert             = `client_cert`
 	SinkParamClientKey              = `client_key`
 	SinkParamFileSize               = `file_size`
+	SinkParamNATSNKeySeed           = `nkey_seed`
+	SinkParamNATSToken              = `token`
+	SinkParamNATSUserJWT            = `user_jwt`
 	SinkParamPartitionFormat        = `partition_format`
 	SinkParamSchemaTopic            = `schema_topic`
 	SinkParamTLSEnabled             = `tls_enabled`
@@ -130,7 +137,9 @@ const (
 	SinkSchemeHTTP                  = `http`
 	SinkSchemeHTTPS                 = `https`
 	SinkSchemeKafka                 = `kafka`
+	SinkSchemeNATS                  = `nats`
 	SinkSchemeNull                  = `null`
+	SinkSchemePull                  = `pull`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkParamSASLEnabled            = `sasl_enabled`
@@ -166,6 +175,7 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
 	OptKafkaSinkConfig:          sql.KVStringOptRequireValue,
 	OptWebhookSinkConfig:        sql.KVStringOptRequireValue,
+	OptNATSSinkConfig:           sql.KVStringOptRequireValue,
 	OptWebhookAuthHeader:        sql.KVStringOptRequireValue,
 	OptWebhookClientTimeout:     sql.KVStringOptRequireValue,
 	OptOnError:                  sql.KVStringOptRequireValue,
@@ -203,9 +213,15 @@ var CloudStorageValidOptions = makeStringSet(OptCompression)
 // WebhookValidOptions is options exclusive to webhook sink
 var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
 
+// NATSValidOptions is options exclusive to NATS sink
+var NATSValidOptions = makeStringSet(OptNATSSinkConfig)
+
 // PubsubValidOptions is options exclusice to pubsub sink
 var PubsubValidOptions = makeStringSet()
 
+// PullValidOptions is options exclusive to pull sink
+var PullValidOptions = makeStringSet()
+
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents, OptSchemaChangePolicy, OptOnError)
 
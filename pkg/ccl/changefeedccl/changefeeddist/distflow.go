@@ -10,7 +10,9 @@ package changefeeddist
 
 import (
 	"context"
+	"net/url"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -30,6 +32,12 @@ var ChangefeedResultTypes = []*types.T{
 	types.Bytes,  // value
 }
 
+// isPullSink returns whether the given sink URI is the one of a pull sink.
+func isPullSink(sinkURI string) bool {
+	u, err := url.Parse(sinkURI)
+	return err == nil && u.Scheme == changefeedbase.SinkSchemePull
+}
+
 // StartDistChangefeed starts distributed changefeed execution.
 func StartDistChangefeed(
 	ctx context.Context,
@@ -53,6 +61,12 @@ func StartDistChangefeed(
 	if details.SinkURI == `` {
 		// Sinkless feeds get one ChangeAggregator on the gateway.
 		spanPartitions = []sql.SpanPartition{{Node: dsp.GatewayID(), Spans: trackedSpans}}
+	} else if isPullSink(details.SinkURI) {
+		// Feeds with a pull sink also get one ChangeAggregator on the gateway:
+		// their events are buffered on the node running the job, along with the
+		// resolved timestamps emitted by the ChangeFrontier, until the client
+		// fetches them from that node.
+		spanPartitions = []sql.SpanPartition{{Node: dsp.GatewayID(), Spans: trackedSpans}}
 	} else {
 		// All other feeds get a ChangeAggregator local on the leaseholder.
 		var err error
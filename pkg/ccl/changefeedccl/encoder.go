@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -23,7 +24,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -61,6 +64,10 @@ type encodeRow struct {
 	// prevTableDesc is a TableDescriptor for the table containing `prevDatums`.
 	// It's valid for interpreting the row at `updated.Prev()`.
 	prevTableDesc catalog.TableDescriptor
+	// projection is the result of evaluating the changefeed's CDC query over
+	// `datums`. If set, it replaces the table's columns in the encoded value.
+	// It is nil for changefeeds which were not created as CDC queries.
+	projection *cdceval.Result
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -88,6 +95,10 @@ func getEncoder(opts map[string]string, targets jobspb.ChangefeedTargets) (Encod
 		return makeJSONEncoder(opts, targets)
 	case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
 		return newConfluentAvroEncoder(opts, targets)
+	case changefeedbase.OptFormatCSV:
+		return makeCSVEncoder(opts)
+	case changefeedbase.OptFormatParquet:
+		return makeParquetEncoder(opts)
 	case changefeedbase.OptFormatNative:
 		return &nativeEncoder{}, nil
 	default:
@@ -197,7 +208,20 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 	}
 
 	var after map[string]interface{}
-	if !row.deleted {
+	if !row.deleted && row.projection != nil {
+		after = make(map[string]interface{}, len(row.projection.Columns))
+		for i, col := range row.projection.Columns {
+			var err error
+			after[col.Name], err = tree.AsJSON(
+				row.projection.Datums[i],
+				sessiondatapb.DataConversionConfig{},
+				time.UTC,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if !row.deleted {
 		columns := row.tableDesc.PublicColumns()
 		after = make(map[string]interface{}, len(columns))
 		for i, col := range columns {
@@ -569,6 +593,216 @@ func (e *confluentAvroEncoder) register(
 	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.codec.Schema())
 }
 
+// The csv and parquet formats write one record per row change, made up of the
+// table's columns followed by these metadata columns. Deletions only carry the
+// primary key of the row, so the other columns of a deletion are NULL.
+const (
+	fileFormatEventTypeCol     = jsonMetaSentinel + `event_type`
+	fileFormatUpdatedCol       = jsonMetaSentinel + `updated`
+	fileFormatMVCCTimestampCol = jsonMetaSentinel + `mvcc_timestamp`
+
+	fileFormatEventTypeUpsert = `upsert`
+	fileFormatEventTypeDelete = `delete`
+)
+
+// fileFormatOptions are the options common to the csv and parquet formats.
+type fileFormatOptions struct {
+	updatedField, mvccTimestampField bool
+}
+
+func makeFileFormatOptions(opts map[string]string) (fileFormatOptions, error) {
+	format := opts[changefeedbase.OptFormat]
+	for _, unsupported := range []string{
+		changefeedbase.OptDiff, changefeedbase.OptTopicInValue,
+	} {
+		if _, ok := opts[unsupported]; ok {
+			return fileFormatOptions{}, errors.Errorf(`%s is not supported with %s=%s`,
+				unsupported, changefeedbase.OptFormat, format)
+		}
+	}
+	if envelope := changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]); envelope == changefeedbase.OptEnvelopeKeyOnly {
+		return fileFormatOptions{}, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, envelope, changefeedbase.OptFormat, format)
+	}
+	var o fileFormatOptions
+	_, o.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	_, o.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
+	return o, nil
+}
+
+// eventType returns the value of the event type metadata column for the row.
+func (o fileFormatOptions) eventType(row encodeRow) string {
+	if row.deleted {
+		return fileFormatEventTypeDelete
+	}
+	return fileFormatEventTypeUpsert
+}
+
+// encodeResolvedTimestamp encodes a resolved timestamp payload. The csv and
+// parquet formats write resolved timestamp files in the same JSON format as
+// the default wrapped envelope.
+func (o fileFormatOptions) encodeResolvedTimestamp(resolved hlc.Timestamp) ([]byte, error) {
+	return gojson.Marshal(map[string]interface{}{
+		`resolved`: tree.TimestampToDecimalDatum(resolved).Decimal.String(),
+	})
+}
+
+// primaryKeyColumnOrdinals returns the ordinals, among the table's public
+// columns, of its primary key columns.
+func primaryKeyColumnOrdinals(desc catalog.TableDescriptor) util.FastIntSet {
+	colIdxByID := catalog.ColumnIDToOrdinalMap(desc.PublicColumns())
+	primaryIndex := desc.GetPrimaryIndex()
+	var ords util.FastIntSet
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		if idx, ok := colIdxByID.Get(primaryIndex.GetKeyColumnID(i)); ok {
+			ords.Add(idx)
+		}
+	}
+	return ords
+}
+
+// csvEncoder encodes changefeed rows as CSV records, one per row change. Values
+// are all the columns of the table, formatted as they would be by EXPORT,
+// followed by the file format metadata columns. NULLs are encoded as empty
+// fields. There is no header record, since a file may be the concatenation of
+// rows from several flushes. Resolved timestamps are encoded as JSON.
+type csvEncoder struct {
+	fileFormatOptions
+
+	alloc  tree.DatumAlloc
+	buf    bytes.Buffer
+	writer *csv.Writer
+	fmtCtx *tree.FmtCtx
+	record []string
+}
+
+var _ Encoder = &csvEncoder{}
+
+func makeCSVEncoder(opts map[string]string) (*csvEncoder, error) {
+	o, err := makeFileFormatOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	e := &csvEncoder{
+		fileFormatOptions: o,
+		fmtCtx:            tree.NewFmtCtx(tree.FmtExport),
+	}
+	e.writer = csv.NewWriter(&e.buf)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *csvEncoder) EncodeKey(_ context.Context, row encodeRow) ([]byte, error) {
+	e.record = e.record[:0]
+	columns := row.tableDesc.PublicColumns()
+	pkOrds := primaryKeyColumnOrdinals(row.tableDesc)
+	for i, col := range columns {
+		if !pkOrds.Contains(i) {
+			continue
+		}
+		field, err := e.formatDatum(row.datums[i], col)
+		if err != nil {
+			return nil, err
+		}
+		e.record = append(e.record, field)
+	}
+	return e.writeRecord()
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *csvEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	e.record = e.record[:0]
+	columns := row.tableDesc.PublicColumns()
+	pkOrds := primaryKeyColumnOrdinals(row.tableDesc)
+	for i, col := range columns {
+		if row.deleted && !pkOrds.Contains(i) {
+			e.record = append(e.record, ``)
+			continue
+		}
+		field, err := e.formatDatum(row.datums[i], col)
+		if err != nil {
+			return nil, err
+		}
+		e.record = append(e.record, field)
+	}
+	e.record = append(e.record, e.eventType(row))
+	if e.updatedField {
+		e.record = append(e.record, row.updated.AsOfSystemTime())
+	}
+	if e.mvccTimestampField {
+		e.record = append(e.record, row.mvccTimestamp.AsOfSystemTime())
+	}
+	return e.writeRecord()
+}
+
+func (e *csvEncoder) formatDatum(datum rowenc.EncDatum, col catalog.Column) (string, error) {
+	if err := datum.EnsureDecoded(col.GetType(), &e.alloc); err != nil {
+		return ``, err
+	}
+	if datum.Datum == tree.DNull {
+		return ``, nil
+	}
+	e.fmtCtx.Reset()
+	datum.Datum.Format(e.fmtCtx)
+	return e.fmtCtx.String(), nil
+}
+
+// writeRecord returns the CSV encoding of e.record, without the trailing
+// record terminator; the sink delimits rows.
+func (e *csvEncoder) writeRecord() ([]byte, error) {
+	e.buf.Reset()
+	if err := e.writer.Write(e.record); err != nil {
+		return nil, err
+	}
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(e.buf.Bytes(), []byte{'\n'}), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *csvEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.encodeResolvedTimestamp(resolved)
+}
+
+// parquetEncoder only implements EncodeResolvedTimestamp. Parquet files are
+// columnar and carry their schema, so rows cannot be encoded independently of
+// the file they are written to; instead, sinks which support the format
+// implement rowEncodingSink and write rows using a parquetRowWriter.
+type parquetEncoder struct {
+	fileFormatOptions
+}
+
+var _ Encoder = &parquetEncoder{}
+
+func makeParquetEncoder(opts map[string]string) (*parquetEncoder, error) {
+	o, err := makeFileFormatOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetEncoder{fileFormatOptions: o}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *parquetEncoder) EncodeKey(context.Context, encodeRow) ([]byte, error) {
+	return nil, errors.AssertionFailedf("EncodeKey unexpectedly called on parquetEncoder")
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *parquetEncoder) EncodeValue(context.Context, encodeRow) ([]byte, error) {
+	return nil, errors.AssertionFailedf("EncodeValue unexpectedly called on parquetEncoder")
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *parquetEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.encodeResolvedTimestamp(resolved)
+}
+
 // nativeEncoder only implements EncodeResolvedTimestamp.
 // Unfortunately, the encoder assumes that it operates with encodeRow -- something
 // that's just not the case when emitting raw KVs.
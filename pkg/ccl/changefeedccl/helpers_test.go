@@ -620,6 +620,19 @@ func pubsubTestWithOptions(testFn cdcTestFn, options feedTestOptions) func(*test
 	}
 }
 
+func natsTest(testFn cdcTestFn, testOpts ...feedTestOption) func(t *testing.T) {
+	return natsTestWithOptions(testFn, makeOptions(testOpts...))
+}
+
+func natsTestWithOptions(testFn cdcTestFn, options feedTestOptions) func(*testing.T) {
+	return func(t *testing.T) {
+		s, db, stopServer := startTestServer(t, options)
+		defer stopServer()
+		f := makeNATSFeedFactory(s, db)
+		testFn(t, db, f)
+	}
+}
+
 func serverArgsRegion(args base.TestServerArgs) string {
 	for _, tier := range args.Locality.Tiers {
 		if tier.Key == "region" {
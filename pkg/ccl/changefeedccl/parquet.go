@@ -0,0 +1,148 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/parquetccl"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+)
+
+// parquetRowWriter writes the changes to rows of a single version of a table
+// into a parquet file. The columns of the file are the public columns of the
+// table followed by the file format metadata columns (see
+// fileFormatEventTypeCol). All table columns are optional in the parquet
+// schema, regardless of their nullability, since deletions only carry the
+// primary key of the row.
+type parquetRowWriter struct {
+	fileFormatOptions
+
+	w      *goparquet.FileWriter
+	desc   catalog.TableDescriptor
+	pkOrds util.FastIntSet
+
+	// cols are the parquet columns of the table's public columns, followed by
+	// the metadata columns.
+	cols   []parquetccl.Column
+	alloc  tree.DatumAlloc
+	record map[string]interface{}
+}
+
+// makeParquetColumns returns the parquet columns for rows of the given table
+// version.
+func makeParquetColumns(
+	desc catalog.TableDescriptor, o fileFormatOptions,
+) ([]parquetccl.Column, error) {
+	var cols []parquetccl.Column
+	for _, col := range desc.PublicColumns() {
+		pc, err := parquetccl.NewColumn(col.GetType(), col.GetName(), true /* nullable */)
+		if err != nil {
+			return nil, errors.Wrapf(err, `column %s of table %s`, col.GetName(), desc.GetName())
+		}
+		cols = append(cols, pc)
+	}
+	metaCols := []string{fileFormatEventTypeCol}
+	if o.updatedField {
+		metaCols = append(metaCols, fileFormatUpdatedCol)
+	}
+	if o.mvccTimestampField {
+		metaCols = append(metaCols, fileFormatMVCCTimestampCol)
+	}
+	for _, name := range metaCols {
+		pc, err := parquetccl.NewColumn(types.String, name, false /* nullable */)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, pc)
+	}
+	return cols, nil
+}
+
+// validateParquetTable returns an error if the rows of the table cannot be
+// written in the parquet format.
+func validateParquetTable(desc catalog.TableDescriptor, opts map[string]string) error {
+	o, err := makeFileFormatOptions(opts)
+	if err != nil {
+		return err
+	}
+	_, err = makeParquetColumns(desc, o)
+	return err
+}
+
+func newParquetRowWriter(
+	w io.Writer, desc catalog.TableDescriptor, o fileFormatOptions,
+) (*parquetRowWriter, error) {
+	cols, err := makeParquetColumns(desc, o)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetRowWriter{
+		fileFormatOptions: o,
+		w:                 parquetccl.NewFileWriter(w, parquetccl.NewSchema(cols)),
+		desc:              desc,
+		pkOrds:            primaryKeyColumnOrdinals(desc),
+		cols:              cols,
+		record:            make(map[string]interface{}, len(cols)),
+	}, nil
+}
+
+// addRow buffers the row in the current row group of the file.
+func (p *parquetRowWriter) addRow(row encodeRow) error {
+	if row.tableDesc.GetID() != p.desc.GetID() || row.tableDesc.GetVersion() != p.desc.GetVersion() {
+		return errors.AssertionFailedf("row of %s@%d written to parquet file for %s@%d",
+			row.tableDesc.GetName(), row.tableDesc.GetVersion(), p.desc.GetName(), p.desc.GetVersion())
+	}
+	for k := range p.record {
+		delete(p.record, k)
+	}
+
+	columns := p.desc.PublicColumns()
+	for i, col := range columns {
+		if row.deleted && !p.pkOrds.Contains(i) {
+			continue
+		}
+		datum := row.datums[i]
+		if err := datum.EnsureDecoded(col.GetType(), &p.alloc); err != nil {
+			return err
+		}
+		v, err := p.cols[i].Encode(datum.Datum)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			p.record[p.cols[i].Name] = v
+		}
+	}
+	p.record[fileFormatEventTypeCol] = []byte(p.eventType(row))
+	if p.updatedField {
+		p.record[fileFormatUpdatedCol] = []byte(row.updated.AsOfSystemTime())
+	}
+	if p.mvccTimestampField {
+		p.record[fileFormatMVCCTimestampCol] = []byte(row.mvccTimestamp.AsOfSystemTime())
+	}
+	return p.w.AddData(p.record)
+}
+
+// bufferedSize returns the size of the rows which have been added to the
+// current row group but not yet written out.
+func (p *parquetRowWriter) bufferedSize() int64 {
+	return p.w.CurrentRowGroupSize()
+}
+
+// close writes out any buffered rows along with the file footer. The writer
+// should not be used after it is closed.
+func (p *parquetRowWriter) close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,467 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvfeed"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// replicationKeepaliveInterval is the interval at which keepalive messages are
+// sent to the client of an idle replication stream.
+const replicationKeepaliveInterval = 10 * time.Second
+
+// replicationSlotPersistInterval is the interval at which the position
+// confirmed by the client of a replication stream is persisted in its
+// replication slot. It is a variable so that tests can shorten it.
+var replicationSlotPersistInterval = 10 * time.Second
+
+// errReplicationStreamEnded is returned by the routine writing a replication
+// stream once the client has ended it, to stop the feed.
+var errReplicationStreamEnded = errors.New("replication stream ended")
+
+func init() {
+	sql.StartReplicationHook = startReplication
+}
+
+// startReplication streams the changes to the tables of a replication stream
+// using the pgoutput protocol. It reuses the pipeline of changefeeds: the
+// changes are read by a kvfeed and decoded by a kvEventToRowConsumer, but
+// instead of being emitted to a sink they are buffered until they are
+// resolved, then sent on the connection grouped in transactions.
+//
+// Each transaction is identified by an LSN derived from the wall time of its
+// commit timestamp, so transactions committed at the same wall time are
+// streamed as one.
+func startReplication(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	stream sql.ReplicationStream,
+	conn pgwirebase.ReplicationConn,
+) error {
+	if err := utilccl.CheckEnterpriseEnabled(
+		execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "logical replication",
+	); err != nil {
+		return err
+	}
+
+	cfg := &execCfg.DistSQLSrv.ServerConfig
+	targets := make(jobspb.ChangefeedTargets, len(stream.Tables))
+	spans := make([]roachpb.Span, 0, len(stream.Tables))
+	for _, table := range stream.Tables {
+		if len(table.GetFamilies()) != 1 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"logical replication is only supported on tables with exactly 1 column family: %s has %d",
+				table.GetName(), len(table.GetFamilies()))
+		}
+		targets[table.GetID()] = jobspb.ChangefeedTarget{StatementTimeName: table.GetName()}
+		spans = append(spans, table.PrimaryIndexSpan(cfg.Codec))
+	}
+
+	frontier, err := span.MakeFrontier(spans...)
+	if err != nil {
+		return err
+	}
+	for _, sp := range spans {
+		if _, err := frontier.Forward(sp, stream.StartTime); err != nil {
+			return err
+		}
+	}
+
+	pool := cfg.BackfillerMonitor
+	limit := changefeedbase.PerChangefeedMemLimit.Get(&cfg.Settings.SV)
+	mm := mon.NewMonitorInheritWithLimit("replication", limit, pool)
+	mm.Start(ctx, pool, mon.BoundAccount{})
+	defer mm.Stop(ctx)
+
+	metrics := execCfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
+	buf := kvevent.NewMemBuffer(mm.MakeBoundAccount(), &cfg.Settings.SV, &metrics.KVFeedMetrics)
+	schemaChangeEvents := changefeedbase.OptSchemaChangeEventClassDefault
+	kvfeedCfg := kvfeed.Config{
+		Writer:             buf,
+		Settings:           cfg.Settings,
+		DB:                 cfg.DB,
+		Codec:              cfg.Codec,
+		Clock:              cfg.DB.Clock(),
+		Gossip:             cfg.Gossip,
+		Spans:              spans,
+		Targets:            targets,
+		Metrics:            &metrics.KVFeedMetrics,
+		MM:                 mm,
+		InitialHighWater:   stream.StartTime,
+		WithDiff:           true,
+		SchemaChangeEvents: schemaChangeEvents,
+		SchemaChangePolicy: changefeedbase.OptSchemaChangePolicyNoBackfill,
+		SchemaFeed: schemafeed.New(ctx, cfg, schemaChangeEvents, targets,
+			stream.StartTime, &metrics.SchemaFeedMetrics),
+	}
+
+	s := &replicationStream{
+		execCfg:      execCfg,
+		stream:       stream,
+		conn:         conn,
+		frontier:     frontier,
+		sentVersions: make(map[descpb.ID]descpb.DescriptorVersion),
+	}
+	s.consumer = &kvEventToRowConsumer{
+		frontier: frontier,
+		cursor:   stream.StartTime,
+		rfCache: newRowFetcherCache(
+			ctx, cfg.Codec, cfg.LeaseManager.(*lease.Manager), cfg.CollectionFactory, cfg.DB,
+		),
+		details: jobspb.ChangefeedDetails{
+			Opts: map[string]string{changefeedbase.OptDiff: ``},
+		},
+		rowSink: s,
+	}
+	if s.sent = pgrepl.LSNFromTimestamp(stream.StartTime); s.sent > 0 {
+		// The LSN of the start time has been streamed in full only if it's the
+		// position the stream is resumed from, whose logical part is the
+		// maximum.
+		s.sent--
+	}
+	s.confirmed, s.persisted = s.sent, s.sent
+
+	if err := conn.BeginCopyBoth(ctx); err != nil {
+		return err
+	}
+
+	events := make(chan kvevent.Event)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		return kvfeed.Run(ctx, kvfeedCfg)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for {
+			ev, err := buf.Get(ctx)
+			if err != nil {
+				return err
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		if err := s.run(ctx, events); err != nil {
+			return err
+		}
+		return errReplicationStreamEnded
+	})
+	err = g.Wait()
+	// Persist the last position confirmed by the client, even if the stream
+	// failed.
+	if persistErr := s.persistConfirmed(ctx); persistErr != nil {
+		log.Warningf(ctx, "failed to persist replication slot %q: %v", stream.SlotName, persistErr)
+	}
+	if !errors.Is(err, errReplicationStreamEnded) {
+		return err
+	}
+	if err := conn.SendCopyDone(ctx); err != nil {
+		return err
+	}
+	return conn.SendCommandComplete([]byte("START_REPLICATION"))
+}
+
+// replicationChange is a change to a row, buffered until it is resolved.
+type replicationChange struct {
+	updated hlc.Timestamp
+	table   catalog.TableDescriptor
+	deleted bool
+	// insert is set if the row didn't exist before the change.
+	insert bool
+	// tuple holds the values of the columns of the row, or only of its
+	// primary key for a deletion.
+	tuple pgrepl.Tuple
+	alloc kvevent.Alloc
+}
+
+// replicationStream writes the pgoutput replication stream of a
+// START_REPLICATION command. It implements rowEncodingSink, receiving the rows
+// decoded by a kvEventToRowConsumer.
+type replicationStream struct {
+	execCfg  *sql.ExecutorConfig
+	stream   sql.ReplicationStream
+	conn     pgwirebase.ReplicationConn
+	frontier *span.Frontier
+	consumer *kvEventToRowConsumer
+
+	// pending holds the changes which are not resolved yet.
+	pending []replicationChange
+	// sentVersions holds the version of each table as of the last relation
+	// message sent for it.
+	sentVersions map[descpb.ID]descpb.DescriptorVersion
+	// xid is the transaction ID of the last transaction sent.
+	xid uint32
+	// sent is the position up to which all the changes have been sent.
+	sent pgrepl.LSN
+	// confirmed is the position up to which the client has confirmed to have
+	// flushed the changes, and persisted is the part of it recorded in the
+	// replication slot.
+	confirmed, persisted pgrepl.LSN
+
+	alloc tree.DatumAlloc
+	msg   []byte
+	buf   []byte
+}
+
+var _ rowEncodingSink = &replicationStream{}
+
+// run writes the replication stream until the client ends it.
+func (s *replicationStream) run(ctx context.Context, events <-chan kvevent.Event) error {
+	keepalive := timeutil.NewTimer()
+	defer keepalive.Stop()
+	keepalive.Reset(replicationKeepaliveInterval)
+	persist := timeutil.NewTimer()
+	defer persist.Stop()
+	persist.Reset(replicationSlotPersistInterval)
+	for {
+		select {
+		case ev := <-events:
+			switch ev.Type() {
+			case kvevent.TypeKV:
+				if err := s.consumer.ConsumeEvent(ctx, ev); err != nil {
+					return err
+				}
+			case kvevent.TypeResolved:
+				a := ev.DetachAlloc()
+				a.Release(ctx)
+				resolved := ev.Resolved()
+				advanced, err := s.frontier.Forward(resolved.Span, resolved.Timestamp)
+				if err != nil {
+					return err
+				}
+				if advanced {
+					if err := s.flush(ctx); err != nil {
+						return err
+					}
+				}
+			}
+		case msg, ok := <-s.conn.ClientMessages():
+			if !ok {
+				// The client has ended the stream.
+				return nil
+			}
+			status, ok, err := pgrepl.ParseClientMessage(msg)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if status.Flushed > s.confirmed {
+				// The client can't have flushed changes which haven't been sent.
+				s.confirmed = status.Flushed
+				if s.confirmed > s.sent {
+					s.confirmed = s.sent
+				}
+			}
+			if status.ReplyRequested {
+				if err := s.sendKeepalive(ctx, false /* replyRequested */); err != nil {
+					return err
+				}
+			}
+		case <-keepalive.C:
+			keepalive.Read = true
+			if err := s.sendKeepalive(ctx, true /* replyRequested */); err != nil {
+				return err
+			}
+			keepalive.Reset(replicationKeepaliveInterval)
+		case <-persist.C:
+			persist.Read = true
+			if err := s.persistConfirmed(ctx); err != nil {
+				return err
+			}
+			persist.Reset(replicationSlotPersistInterval)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// EncodeAndEmitRow implements the rowEncodingSink interface. The row is
+// encoded right away, but it is only sent once its timestamp is resolved.
+func (s *replicationStream) EncodeAndEmitRow(
+	ctx context.Context, _ TopicDescriptor, row encodeRow, alloc kvevent.Alloc,
+) error {
+	c := replicationChange{
+		updated: row.updated,
+		table:   row.tableDesc,
+		deleted: row.deleted,
+		insert:  row.prevDeleted,
+		alloc:   alloc,
+	}
+	for i, col := range row.tableDesc.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		if row.deleted && !row.tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(col.GetID()) {
+			c.tuple = append(c.tuple, nil)
+			continue
+		}
+		datum := row.datums[i]
+		if err := datum.EnsureDecoded(col.GetType(), &s.alloc); err != nil {
+			return err
+		}
+		if datum.Datum == tree.DNull {
+			c.tuple = append(c.tuple, nil)
+			continue
+		}
+		c.tuple = append(c.tuple, s.conn.EncodeTextDatum(ctx, datum.Datum, col.GetType()))
+	}
+	s.pending = append(s.pending, c)
+	return nil
+}
+
+// flush sends the pending changes committed before the wall time of the
+// resolved timestamp. The changes with the same wall time as the resolved
+// timestamp are held back since they belong to the same transaction as
+// changes which may not have been received yet.
+func (s *replicationStream) flush(ctx context.Context) error {
+	resolved := pgrepl.LSNFromTimestamp(s.frontier.Frontier())
+	if resolved == 0 || resolved-1 <= s.sent {
+		return nil
+	}
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		return s.pending[i].updated.Less(s.pending[j].updated)
+	})
+	n := sort.Search(len(s.pending), func(i int) bool {
+		return pgrepl.LSNFromTimestamp(s.pending[i].updated) >= resolved
+	})
+	for i := 0; i < n; {
+		lsn := pgrepl.LSNFromTimestamp(s.pending[i].updated)
+		j := i + 1
+		for j < n && pgrepl.LSNFromTimestamp(s.pending[j].updated) == lsn {
+			j++
+		}
+		if err := s.sendTransaction(ctx, lsn, s.pending[i:j]); err != nil {
+			return err
+		}
+		i = j
+	}
+	for i := 0; i < n; i++ {
+		s.pending[i].alloc.Release(ctx)
+	}
+	s.pending = append(s.pending[:0], s.pending[n:]...)
+	s.sent = resolved - 1
+	return s.sendKeepalive(ctx, false /* replyRequested */)
+}
+
+// sendTransaction sends the given changes as a transaction with the given
+// LSN.
+func (s *replicationStream) sendTransaction(
+	ctx context.Context, lsn pgrepl.LSN, changes []replicationChange,
+) error {
+	commitTime := changes[0].updated.GoTime()
+	s.xid++
+	s.msg = pgrepl.AppendBegin(s.msg[:0], lsn, commitTime, s.xid)
+	if err := s.send(ctx, lsn); err != nil {
+		return err
+	}
+	for i := range changes {
+		c := &changes[i]
+		id := c.table.GetID()
+		if v, ok := s.sentVersions[id]; !ok || v != c.table.GetVersion() {
+			s.msg = pgrepl.AppendRelation(s.msg[:0], s.relation(c.table))
+			if err := s.send(ctx, lsn); err != nil {
+				return err
+			}
+			s.sentVersions[id] = c.table.GetVersion()
+		}
+		switch {
+		case c.deleted:
+			s.msg = pgrepl.AppendDelete(s.msg[:0], uint32(id), c.tuple)
+		case c.insert:
+			s.msg = pgrepl.AppendInsert(s.msg[:0], uint32(id), c.tuple)
+		default:
+			s.msg = pgrepl.AppendUpdate(s.msg[:0], uint32(id), c.tuple)
+		}
+		if err := s.send(ctx, lsn); err != nil {
+			return err
+		}
+	}
+	s.msg = pgrepl.AppendCommit(s.msg[:0], lsn, commitTime)
+	return s.send(ctx, lsn)
+}
+
+// relation describes a table in a relation message. Virtual columns are not
+// streamed.
+func (s *replicationStream) relation(table catalog.TableDescriptor) *pgrepl.Relation {
+	rel := &pgrepl.Relation{
+		ID:        uint32(table.GetID()),
+		Namespace: s.stream.SchemaNames[table.GetParentSchemaID()],
+		Name:      table.GetName(),
+	}
+	keyCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		rel.Columns = append(rel.Columns, pgrepl.RelationColumn{
+			Name:    col.GetName(),
+			TypeOID: col.GetType().Oid(),
+			TypeMod: col.GetType().TypeModifier(),
+			Key:     keyCols.Contains(col.GetID()),
+		})
+	}
+	return rel
+}
+
+// send sends the pgoutput message in s.msg.
+func (s *replicationStream) send(ctx context.Context, lsn pgrepl.LSN) error {
+	s.buf = pgrepl.AppendXLogData(s.buf[:0], lsn, lsn, timeutil.Now(), s.msg)
+	return s.conn.SendCopyData(ctx, s.buf)
+}
+
+func (s *replicationStream) sendKeepalive(ctx context.Context, replyRequested bool) error {
+	s.buf = pgrepl.AppendKeepalive(s.buf[:0], s.sent, timeutil.Now(), replyRequested)
+	return s.conn.SendCopyData(ctx, s.buf)
+}
+
+// persistConfirmed records the position confirmed by the client in the
+// replication slot, from which streaming resumes after a reconnection.
+func (s *replicationStream) persistConfirmed(ctx context.Context) error {
+	if s.confirmed <= s.persisted {
+		return nil
+	}
+	if err := sql.AdvanceReplicationSlot(
+		ctx, s.execCfg, s.stream.DatabaseID, s.stream.SlotName, s.confirmed.Timestamp(),
+	); err != nil {
+		return err
+	}
+	s.persisted = s.confirmed
+	return nil
+}
@@ -0,0 +1,422 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// replicationMessage is a message of a pgoutput replication stream.
+type replicationMessage struct {
+	// lsn is the LSN of the transaction the message belongs to.
+	lsn pgrepl.LSN
+	// text describes the message, e.g. `INSERT (1, a)`.
+	text string
+}
+
+// replicationMessageReader reads the fields of a pgoutput message.
+type replicationMessageReader struct {
+	buf []byte
+}
+
+func (r *replicationMessageReader) bytes(n int) []byte {
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *replicationMessageReader) byte() byte     { return r.bytes(1)[0] }
+func (r *replicationMessageReader) uint16() uint16 { return binary.BigEndian.Uint16(r.bytes(2)) }
+func (r *replicationMessageReader) uint32() uint32 { return binary.BigEndian.Uint32(r.bytes(4)) }
+func (r *replicationMessageReader) uint64() uint64 { return binary.BigEndian.Uint64(r.bytes(8)) }
+
+func (r *replicationMessageReader) string() string {
+	i := strings.IndexByte(string(r.buf), 0)
+	s := string(r.buf[:i])
+	r.buf = r.buf[i+1:]
+	return s
+}
+
+// decodeReplicationMessage decodes the payload of a CopyData message of a
+// replication stream. It returns false for keepalive messages.
+func decodeReplicationMessage(t *testing.T, data []byte) (replicationMessage, bool) {
+	t.Helper()
+	r := replicationMessageReader{buf: data}
+	switch typ := r.byte(); typ {
+	case 'k':
+		return replicationMessage{}, false
+	case 'w':
+	default:
+		t.Fatalf("unexpected replication message %q", typ)
+	}
+	m := replicationMessage{lsn: pgrepl.LSN(r.uint64())}
+	// Skip the end LSN and the send time.
+	r.bytes(16)
+
+	switch typ := r.byte(); typ {
+	case 'B':
+		require.Equal(t, m.lsn, pgrepl.LSN(r.uint64()), "final LSN of the transaction")
+		m.text = "BEGIN"
+	case 'C':
+		r.byte() // flags
+		require.Equal(t, m.lsn, pgrepl.LSN(r.uint64()), "commit LSN of the transaction")
+		m.text = "COMMIT"
+	case 'R':
+		r.uint32() // ID
+		namespace, name := r.string(), r.string()
+		r.byte() // replica identity
+		cols := make([]string, r.uint16())
+		for i := range cols {
+			isKey := r.byte() == 1
+			cols[i] = r.string()
+			if isKey {
+				cols[i] += " KEY"
+			}
+			r.uint32() // type OID
+			r.uint32() // type modifier
+		}
+		m.text = fmt.Sprintf("RELATION %s.%s (%s)", namespace, name, strings.Join(cols, ", "))
+	case 'I', 'U', 'D':
+		r.uint32() // relation ID
+		r.byte()   // tuple kind
+		vals := make([]string, r.uint16())
+		for i := range vals {
+			if r.byte() == 'n' {
+				vals[i] = "NULL"
+				continue
+			}
+			vals[i] = string(r.bytes(int(r.uint32())))
+		}
+		op := map[byte]string{'I': "INSERT", 'U': "UPDATE", 'D': "DELETE"}[typ]
+		m.text = fmt.Sprintf("%s (%s)", op, strings.Join(vals, ", "))
+	default:
+		t.Fatalf("unexpected pgoutput message %q", typ)
+	}
+	return m, true
+}
+
+// expectTransaction reads a transaction from a replication stream, and checks
+// that it holds the given messages. It returns the LSN of the transaction.
+func expectTransaction(
+	t *testing.T, next func() replicationMessage, expected ...string,
+) pgrepl.LSN {
+	t.Helper()
+	begin := next()
+	require.Equal(t, "BEGIN", begin.text)
+	var actual []string
+	for {
+		m := next()
+		require.Equal(t, begin.lsn, m.lsn, "LSN of %s", m.text)
+		if m.text == "COMMIT" {
+			break
+		}
+		actual = append(actual, m.text)
+	}
+	require.Equal(t, expected, actual)
+	return begin.lsn
+}
+
+// replicationClient is a client of the streaming replication protocol.
+type replicationClient struct {
+	t    *testing.T
+	conn *pgconn.PgConn
+	// lsn is the LSN of the last transaction received.
+	lsn pgrepl.LSN
+}
+
+func makeReplicationClient(
+	t *testing.T, s serverutils.TestServerInterface,
+) (*replicationClient, func()) {
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	pgURL.Path = `d`
+	q := pgURL.Query()
+	q.Set(`replication`, `database`)
+	pgURL.RawQuery = q.Encode()
+	conn, err := pgconn.Connect(context.Background(), pgURL.String())
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return &replicationClient{t: t, conn: conn}, func() {
+		_ = conn.Close(context.Background())
+		cleanup()
+	}
+}
+
+func (c *replicationClient) exec(sql string) {
+	c.t.Helper()
+	_, err := c.conn.Exec(context.Background(), sql).ReadAll()
+	require.NoError(c.t, err)
+}
+
+func (c *replicationClient) send(msg pgproto3.FrontendMessage) {
+	c.t.Helper()
+	require.NoError(c.t, c.conn.SendBytes(context.Background(), msg.Encode(nil)))
+}
+
+func (c *replicationClient) receive() pgproto3.BackendMessage {
+	c.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testutils.DefaultSucceedsSoonDuration)
+	defer cancel()
+	msg, err := c.conn.ReceiveMessage(ctx)
+	require.NoError(c.t, err)
+	if errMsg, ok := msg.(*pgproto3.ErrorResponse); ok {
+		c.t.Fatalf("unexpected error: %s", errMsg.Message)
+	}
+	return msg
+}
+
+// startReplication runs START_REPLICATION, and waits for the stream to start.
+func (c *replicationClient) startReplication(slot string, startLSN pgrepl.LSN) {
+	c.t.Helper()
+	c.send(&pgproto3.Query{String: fmt.Sprintf(
+		`START_REPLICATION SLOT %s LOGICAL %s (proto_version '1', publication_names 'p')`,
+		slot, startLSN,
+	)})
+	msg := c.receive()
+	require.IsType(c.t, &pgproto3.CopyBothResponse{}, msg)
+}
+
+// next returns the next message of the replication stream, skipping the
+// keepalive messages.
+func (c *replicationClient) next() replicationMessage {
+	c.t.Helper()
+	for {
+		msg := c.receive()
+		data, ok := msg.(*pgproto3.CopyData)
+		if !ok {
+			c.t.Fatalf("unexpected message %T", msg)
+		}
+		if m, ok := decodeReplicationMessage(c.t, data.Data); ok {
+			return m
+		}
+	}
+}
+
+// expectTransaction reads the next transaction of the replication stream,
+// which must be streamed after the previous one.
+func (c *replicationClient) expectTransaction(expected ...string) pgrepl.LSN {
+	c.t.Helper()
+	lsn := expectTransaction(c.t, c.next, expected...)
+	require.Less(c.t, uint64(c.lsn), uint64(lsn))
+	c.lsn = lsn
+	return lsn
+}
+
+// confirm sends a standby status update, confirming that the changes up to
+// the given LSN have been flushed.
+func (c *replicationClient) confirm(flushed pgrepl.LSN) {
+	c.t.Helper()
+	// The written, flushed and applied positions, the client time, and no reply
+	// requested.
+	status := make([]byte, 1+8+8+8+8+1)
+	status[0] = 'r'
+	for i := 0; i < 3; i++ {
+		binary.BigEndian.PutUint64(status[1+8*i:], uint64(flushed))
+	}
+	c.send(&pgproto3.CopyData{Data: status})
+}
+
+// endReplication ends the replication stream, and waits for the command to
+// complete.
+func (c *replicationClient) endReplication() {
+	c.t.Helper()
+	c.send(&pgproto3.CopyDone{})
+	for {
+		switch msg := c.receive().(type) {
+		case *pgproto3.CopyData, *pgproto3.CopyDone:
+		case *pgproto3.CommandComplete:
+			require.Equal(c.t, "START_REPLICATION", string(msg.CommandTag))
+		case *pgproto3.ReadyForQuery:
+			return
+		default:
+			c.t.Fatalf("unexpected message %T", msg)
+		}
+	}
+}
+
+func TestReplicationStream(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer func(old time.Duration) {
+		replicationSlotPersistInterval = old
+	}(replicationSlotPersistInterval)
+	replicationSlotPersistInterval = 10 * time.Millisecond
+
+	s, db, stopServer := startTestServer(t, feedTestOptions{})
+	defer stopServer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `CREATE PUBLICATION p FOR TABLE t`)
+
+	c, cleanup := makeReplicationClient(t, s)
+	defer cleanup()
+	c.exec(`CREATE_REPLICATION_SLOT s LOGICAL pgoutput`)
+
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a')`)
+	sqlDB.Exec(t, `UPDATE t SET b = 'b' WHERE a = 1`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE a = 1`)
+
+	// The changes committed since the creation of the slot are streamed, one
+	// transaction at a time. The table is described before its first change.
+	c.startReplication(`s`, 0)
+	c.expectTransaction(`RELATION public.t (a KEY, b)`, `INSERT (1, a)`)
+	updateLSN := c.expectTransaction(`UPDATE (1, b)`)
+	c.expectTransaction(`DELETE (1, NULL)`)
+
+	// The position confirmed by the client is persisted in the slot while the
+	// stream is running.
+	c.confirm(updateLSN)
+	testutils.SucceedsSoon(t, func() error {
+		var confirmed string
+		sqlDB.QueryRow(t,
+			`SELECT confirmed_flush_lsn FROM pg_replication_slots WHERE slot_name = 's'`,
+		).Scan(&confirmed)
+		if confirmed != updateLSN.String() {
+			return errors.Newf("expected confirmed position %s, got %s", updateLSN, confirmed)
+		}
+		return nil
+	})
+	c.endReplication()
+
+	// Streaming resumes after the confirmed position: the transactions which
+	// were not confirmed are streamed again.
+	sqlDB.Exec(t, `INSERT INTO t VALUES (2, 'c')`)
+	c.lsn = updateLSN
+	c.startReplication(`s`, 0)
+	c.expectTransaction(`RELATION public.t (a KEY, b)`, `DELETE (1, NULL)`)
+	insertLSN := c.expectTransaction(`INSERT (2, c)`)
+
+	// The confirmed position is also persisted when the stream ends.
+	c.confirm(insertLSN)
+	c.endReplication()
+	sqlDB.CheckQueryResults(t,
+		`SELECT confirmed_flush_lsn FROM pg_replication_slots WHERE slot_name = 's'`,
+		[][]string{{insertLSN.String()}},
+	)
+	c.lsn = insertLSN
+	c.startReplication(`s`, 0)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (3, 'd')`)
+	c.expectTransaction(`RELATION public.t (a KEY, b)`, `INSERT (3, d)`)
+	c.endReplication()
+}
+
+// recordingReplicationConn implements pgwirebase.ReplicationConn, recording
+// the messages of the replication stream.
+type recordingReplicationConn struct {
+	pgwirebase.ReplicationConn
+	msgs [][]byte
+}
+
+func (c *recordingReplicationConn) SendCopyData(_ context.Context, data []byte) error {
+	c.msgs = append(c.msgs, append([]byte(nil), data...))
+	return nil
+}
+
+// TestReplicationStreamSameWallTime checks that the transactions committed at
+// the same wall time, which have the same LSN, are streamed as one.
+func TestReplicationStreamSameWallTime(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, stopServer := startTestServer(t, feedTestOptions{})
+	defer stopServer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+	table := catalogkv.TestingGetTableDescriptor(s.DB(), keys.SystemSQLCodec, `d`, `t`)
+
+	tableSpan := table.PrimaryIndexSpan(keys.SystemSQLCodec)
+	frontier, err := span.MakeFrontier(tableSpan)
+	require.NoError(t, err)
+	conn := &recordingReplicationConn{}
+	rs := &replicationStream{
+		stream: sql.ReplicationStream{
+			SchemaNames: map[descpb.ID]string{table.GetParentSchemaID(): `public`},
+		},
+		conn:         conn,
+		frontier:     frontier,
+		sentVersions: make(map[descpb.ID]descpb.DescriptorVersion),
+	}
+	const wallTime = 1000
+	insert := func(ts hlc.Timestamp, a string) {
+		rs.pending = append(rs.pending, replicationChange{
+			updated: ts,
+			table:   table,
+			insert:  true,
+			tuple:   pgrepl.Tuple{[]byte(a), nil},
+		})
+	}
+	// Two transactions committed at the same wall time, and a later one.
+	insert(hlc.Timestamp{WallTime: wallTime, Logical: 2}, `2`)
+	insert(hlc.Timestamp{WallTime: wallTime + 1}, `3`)
+	insert(hlc.Timestamp{WallTime: wallTime, Logical: 1}, `1`)
+
+	next := func() replicationMessage {
+		for len(conn.msgs) > 0 {
+			data := conn.msgs[0]
+			conn.msgs = conn.msgs[1:]
+			if m, ok := decodeReplicationMessage(t, data); ok {
+				return m
+			}
+		}
+		t.Fatal("expected replication message")
+		return replicationMessage{}
+	}
+	forward := func(ts hlc.Timestamp) {
+		_, err := frontier.Forward(tableSpan, ts)
+		require.NoError(t, err)
+		require.NoError(t, rs.flush(ctx))
+	}
+
+	// Changes committed at the wall time of the resolved timestamp are held
+	// back, since more changes with the same LSN may still be received.
+	forward(hlc.Timestamp{WallTime: wallTime, Logical: 5})
+	for _, data := range conn.msgs {
+		_, ok := decodeReplicationMessage(t, data)
+		require.False(t, ok, "unexpected message before the resolved timestamp")
+	}
+	conn.msgs = nil
+	require.Equal(t, pgrepl.LSN(wallTime-1), rs.sent)
+
+	// Once the wall time is resolved, its transactions are streamed as one, in
+	// the order of their timestamps.
+	forward(hlc.Timestamp{WallTime: wallTime + 2})
+	require.Equal(t, pgrepl.LSN(wallTime), expectTransaction(t, next,
+		`RELATION public.t (a KEY, b)`, `INSERT (1, NULL)`, `INSERT (2, NULL)`))
+	require.Equal(t, pgrepl.LSN(wallTime+1), expectTransaction(t, next, `INSERT (3, NULL)`))
+	require.Equal(t, pgrepl.LSN(wallTime+1), rs.sent)
+	require.Empty(t, rs.pending)
+}
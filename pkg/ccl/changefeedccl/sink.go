@@ -66,6 +66,20 @@ type Sink interface {
 	Close() error
 }
 
+// rowEncodingSink is implemented by sinks which encode rows themselves rather
+// than emitting the bytes produced by an Encoder. This is needed for columnar
+// file formats, such as parquet, where a row cannot be encoded independently of
+// the rest of the file it is written to.
+type rowEncodingSink interface {
+	// EncodeAndEmitRow is like EmitRow, but is handed the decoded row.
+	EncodeAndEmitRow(
+		ctx context.Context,
+		topic TopicDescriptor,
+		row encodeRow,
+		alloc kvevent.Alloc,
+	) error
+}
+
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -104,6 +118,10 @@ func getSink(
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
 				return makeKafkaSink(ctx, sinkURL{URL: u}, feedCfg.Targets, feedCfg.Opts, m)
 			})
+		case isNATSSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.NATSValidOptions, func() (Sink, error) {
+				return makeNATSSink(ctx, sinkURL{URL: u}, feedCfg.Targets, feedCfg.Opts, m)
+			})
 		case isWebhookSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.WebhookValidOptions, func() (Sink, error) {
 				return makeWebhookSink(ctx, sinkURL{URL: u}, feedCfg.Opts,
@@ -121,6 +139,10 @@ func getSink(
 					feedCfg.Opts, timestampOracle, serverCfg.ExternalStorageFromURI, user, m,
 				)
 			})
+		case u.Scheme == changefeedbase.SinkSchemePull:
+			return validateOptionsAndMakeSink(changefeedbase.PullValidOptions, func() (Sink, error) {
+				return makePullSink(serverCfg.NodeID.SQLInstanceID(), jobID, m)
+			})
 		case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 			return validateOptionsAndMakeSink(changefeedbase.SQLValidOptions, func() (Sink, error) {
 				return makeSQLSink(sinkURL{URL: u}, sqlSinkTableName, feedCfg.Targets, m)
@@ -249,6 +271,20 @@ func (s errorWrapperSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements the rowEncodingSink interface.
+func (s errorWrapperSink) EncodeAndEmitRow(
+	ctx context.Context, topic TopicDescriptor, row encodeRow, alloc kvevent.Alloc,
+) error {
+	wrapped, ok := s.wrapped.(rowEncodingSink)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not encode rows", s.wrapped)
+	}
+	if err := wrapped.EncodeAndEmitRow(ctx, topic, row, alloc); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements Sink interface.
 func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -66,6 +66,9 @@ type cloudStorageSinkFile struct {
 	alloc         kvevent.Alloc
 	oldestMVCC    hlc.Timestamp
 	recordMetrics recordEmittedMessagesCallback
+	// parquet is set, once the first row has been written, for files in the
+	// parquet format. It writes directly to buf.
+	parquet *parquetRowWriter
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -284,6 +287,9 @@ type cloudStorageSink struct {
 
 	ext          string
 	rowDelimiter []byte
+	// parquet is set if files are written in the parquet format, in which case
+	// rows are emitted by EncodeAndEmitRow rather than EmitRow.
+	parquet *fileFormatOptions
 
 	compression string
 
@@ -378,6 +384,16 @@ func makeCloudStorageSink(
 		// would require a bit of refactoring.
 		s.ext = `.ndjson`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatCSV:
+		s.ext = `.csv`
+		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatParquet:
+		o, err := makeFileFormatOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		s.ext = `.parquet`
+		s.parquet = &o
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
@@ -395,6 +411,11 @@ func makeCloudStorageSink(
 	}
 
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
+		if s.parquet != nil {
+			// Parquet files compress their pages themselves.
+			return nil, errors.Errorf(`%s is not supported with %s=%s`,
+				changefeedbase.OptCompression, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+		}
 		if strings.EqualFold(codec, "gzip") {
 			s.compression = sinkCompressionGzip
 			s.ext = s.ext + ".gz"
@@ -445,6 +466,9 @@ func (s *cloudStorageSink) EmitRow(
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
+	if s.parquet != nil {
+		return errors.AssertionFailedf(`EmitRow unexpectedly called on a parquet sink`)
+	}
 
 	file := s.getOrCreateFile(topic, mvcc)
 	file.alloc.Merge(&alloc)
@@ -464,6 +488,49 @@ func (s *cloudStorageSink) EmitRow(
 	return nil
 }
 
+var _ rowEncodingSink = (*cloudStorageSink)(nil)
+
+// EncodeAndEmitRow implements the rowEncodingSink interface. It is used for
+// files in the parquet format.
+func (s *cloudStorageSink) EncodeAndEmitRow(
+	ctx context.Context, topic TopicDescriptor, row encodeRow, alloc kvevent.Alloc,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	if s.parquet == nil {
+		return errors.AssertionFailedf(`EncodeAndEmitRow unexpectedly called on a %s sink`, s.ext)
+	}
+
+	file := s.getOrCreateFile(topic, row.mvccTimestamp)
+	file.alloc.Merge(&alloc)
+
+	// The file is keyed by the table version, so every row written to it has
+	// the same schema.
+	if file.parquet == nil {
+		var err error
+		if file.parquet, err = newParquetRowWriter(&file.buf, row.tableDesc, *s.parquet); err != nil {
+			return err
+		}
+	}
+	if err := file.parquet.addRow(row); err != nil {
+		return err
+	}
+	// Rows are buffered by the parquet writer until a row group is complete,
+	// so the size of the file is the size of the row groups written to buf so
+	// far plus the size of the current one.
+	size := int64(file.buf.Len()) + file.parquet.bufferedSize()
+	file.rawSize = int(size)
+	file.numMessages++
+
+	if size > s.targetMaxFileSize {
+		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *cloudStorageSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -566,6 +633,11 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 			return err
 		}
 	}
+	if file.parquet != nil {
+		if err := file.parquet.close(); err != nil {
+			return err
+		}
+	}
 
 	// We use this monotonically increasing fileID to ensure correct ordering
 	// among files emitted at the same timestamp during the same job session.
@@ -13,6 +13,7 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"net/url"
@@ -34,11 +35,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
+	goparquet "github.com/fraugster/parquet-go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -704,4 +708,147 @@ func TestCloudStorageSink(t *testing.T) {
 			"w1\n",
 		}, slurpDir(t, dir))
 	})
+
+	t.Run(`csv`, func(t *testing.T) {
+		csvOpts := map[string]string{
+			changefeedbase.OptFormat:     string(changefeedbase.OptFormatCSV),
+			changefeedbase.OptEnvelope:   string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue: ``,
+		}
+		desc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		enc, err := getEncoder(csvOpts, jobspb.ChangefeedTargets{})
+		require.NoError(t, err)
+
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf, err := span.MakeFrontier(testSpan)
+		require.NoError(t, err)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		dir := `csv`
+		s, err := makeCloudStorageSink(
+			ctx, sinkURI(dir, unlimitedFileSize), 1, settings,
+			csvOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		emit := func(r encodeRow) {
+			value, err := enc.EncodeValue(ctx, r)
+			require.NoError(t, err)
+			require.NoError(t, s.EmitRow(ctx, tableDescriptorTopic{desc}, noKey, value, r.updated, r.mvccTimestamp, zeroAlloc))
+		}
+		emit(encodeRow{datums: makeRow(1, `one`), tableDesc: desc, updated: ts(1), mvccTimestamp: ts(1)})
+		emit(encodeRow{datums: makeRow(2, `two, "quoted"`), tableDesc: desc, updated: ts(2), mvccTimestamp: ts(2)})
+		emit(encodeRow{datums: makeRow(1, ``), deleted: true, tableDesc: desc, updated: ts(3), mvccTimestamp: ts(3)})
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{
+			"1,one,upsert\n2,\"two, \"\"quoted\"\"\",upsert\n1,,delete\n",
+		}, slurpDir(t, dir))
+		csvFiles, err := filepath.Glob(filepath.Join(settings.ExternalIODir, dir, `*`, `*.csv`))
+		require.NoError(t, err)
+		require.Len(t, csvFiles, 1)
+	})
+
+	t.Run(`parquet`, func(t *testing.T) {
+		parquetOpts := map[string]string{
+			changefeedbase.OptFormat:            string(changefeedbase.OptFormatParquet),
+			changefeedbase.OptEnvelope:          string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue:        ``,
+			changefeedbase.OptUpdatedTimestamps: ``,
+		}
+		desc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		descV2, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		require.NoError(t, err)
+		descV2.TableDesc().Version = desc.GetVersion() + 1
+
+		readParquet := func(t *testing.T, contents string) []map[string]interface{} {
+			r, err := goparquet.NewFileReader(bytes.NewReader([]byte(contents)))
+			require.NoError(t, err)
+			var rows []map[string]interface{}
+			for {
+				row, err := r.NextRow()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				rows = append(rows, row)
+			}
+			return rows
+		}
+
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf, err := span.MakeFrontier(testSpan)
+		require.NoError(t, err)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		dir := `parquet`
+		s, err := makeCloudStorageSink(
+			ctx, sinkURI(dir, unlimitedFileSize), 1, settings,
+			parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+		rs := s.(rowEncodingSink)
+
+		// Rows must be emitted through EncodeAndEmitRow.
+		require.Regexp(t, `EmitRow unexpectedly called on a parquet sink`,
+			s.EmitRow(ctx, tableDescriptorTopic{desc}, noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+
+		var pool testAllocPool
+		require.NoError(t, rs.EncodeAndEmitRow(ctx, tableDescriptorTopic{desc},
+			encodeRow{datums: makeRow(1, `one`), tableDesc: desc, updated: ts(1), mvccTimestamp: ts(1)}, pool.alloc()))
+		require.NoError(t, rs.EncodeAndEmitRow(ctx, tableDescriptorTopic{desc},
+			encodeRow{datums: makeRow(1, ``), deleted: true, tableDesc: desc, updated: ts(2), mvccTimestamp: ts(2)}, pool.alloc()))
+		// A new version of the table starts a new file, with the new schema.
+		require.NoError(t, rs.EncodeAndEmitRow(ctx, tableDescriptorTopic{descV2},
+			encodeRow{datums: append(makeRow(2, `two`), rowenc.EncDatum{Datum: tree.NewDInt(20)}),
+				tableDesc: descV2, updated: ts(3), mvccTimestamp: ts(3)}, pool.alloc()))
+		require.NoError(t, s.Flush(ctx))
+		require.EqualValues(t, 0, pool.used())
+
+		files := slurpDir(t, dir)
+		require.Len(t, files, 2)
+		require.Equal(t, []map[string]interface{}{
+			{`a`: int64(1), `b`: []byte(`one`), `__crdb__event_type`: []byte(`upsert`), `__crdb__updated`: []byte(`1.0000000000`)},
+			{`a`: int64(1), `__crdb__event_type`: []byte(`delete`), `__crdb__updated`: []byte(`2.0000000000`)},
+		}, readParquet(t, files[0]))
+		require.Equal(t, []map[string]interface{}{
+			{`a`: int64(2), `b`: []byte(`two`), `c`: int64(20), `__crdb__event_type`: []byte(`upsert`), `__crdb__updated`: []byte(`3.0000000000`)},
+		}, readParquet(t, files[1]))
+
+		// Files are rolled over once they exceed the target file size.
+		dir = `parquet-file-size`
+		s2, err := makeCloudStorageSink(
+			ctx, sinkURI(dir, 1), 1, settings,
+			parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s2.Close()) }()
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, s2.(rowEncodingSink).EncodeAndEmitRow(ctx, tableDescriptorTopic{desc},
+				encodeRow{datums: makeRow(i, `x`), tableDesc: desc, updated: ts(4), mvccTimestamp: ts(4)}, zeroAlloc))
+		}
+		files = slurpDir(t, dir)
+		require.Len(t, files, 3)
+		for i, f := range files {
+			rows := readParquet(t, f)
+			require.Len(t, rows, 1)
+			require.Equal(t, int64(i+1), rows[0][`a`])
+		}
+
+		// Parquet files are compressed internally.
+		parquetOpts[changefeedbase.OptCompression] = `gzip`
+		_, err = makeCloudStorageSink(
+			ctx, sinkURI(`parquet-gzip`, unlimitedFileSize), 1, settings,
+			parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.EqualError(t, err, `compression is not supported with format=parquet`)
+	})
+}
+
+func makeRow(a int, b string) rowenc.EncDatumRow {
+	return rowenc.EncDatumRow{
+		{Datum: tree.NewDInt(tree.DInt(a))},
+		{Datum: tree.NewDString(b)},
+	}
 }
@@ -0,0 +1,537 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// natsKeyHeader is the header of the NATS messages which holds the key of the
+// row, NATS messages having no key of their own. Header values are text, and
+// keys in formats such as avro are binary, so the key is base64 encoded (with
+// the standard encoding, padded).
+const natsKeyHeader = `Crdb-Key`
+
+// defaultNATSMaxPending is the default maximum number of messages published to
+// JetStream and not yet acknowledged.
+const defaultNATSMaxPending = 4000
+
+type natsSinkConfig struct {
+	// Flush describes how messages are batched before being published. By
+	// default, messages are published as soon as they are emitted.
+	Flush batchConfig `json:",omitempty"`
+	// MaxPending is the maximum number of messages published and not yet
+	// acknowledged by JetStream, after which publishing blocks.
+	MaxPending int `json:",omitempty"`
+}
+
+func getNATSSinkConfig(opts map[string]string) (natsSinkConfig, error) {
+	var cfg natsSinkConfig
+	if configStr, ok := opts[changefeedbase.OptNATSSinkConfig]; ok {
+		if err := json.Unmarshal([]byte(configStr), &cfg); err != nil {
+			return cfg, errors.Wrapf(err, "failed to parse nats config; check %s option",
+				changefeedbase.OptNATSSinkConfig)
+		}
+	}
+	if cfg.Flush.Messages < 0 || cfg.Flush.Bytes < 0 || cfg.Flush.Frequency < 0 || cfg.MaxPending < 0 {
+		return cfg, errors.Errorf("invalid option value %s, all config values must be non-negative",
+			changefeedbase.OptNATSSinkConfig)
+	}
+	if cfg.MaxPending == 0 {
+		cfg.MaxPending = defaultNATSMaxPending
+	}
+	if (cfg.Flush.Messages > 0 || cfg.Flush.Bytes > 0) && cfg.Flush.Frequency == 0 {
+		return cfg, errors.Errorf("invalid option value %s, flush frequency is not set, messages may never be sent",
+			changefeedbase.OptNATSSinkConfig)
+	}
+	return cfg, nil
+}
+
+// natsPublisher publishes messages to JetStream asynchronously. It is
+// implemented by nats.JetStreamContext, and mocked in tests.
+type natsPublisher interface {
+	PublishMsgAsync(m *nats.Msg, opts ...nats.PubOpt) (nats.PubAckFuture, error)
+}
+
+// natsMessage is a message emitted to the sink which hasn't been acknowledged
+// yet.
+type natsMessage struct {
+	msg           *nats.Msg
+	alloc         kvevent.Alloc
+	mvcc          hlc.Timestamp
+	updateMetrics recordEmittedMessagesCallback
+	ack           nats.PubAckFuture
+}
+
+// natsSink publishes messages to NATS JetStream, one subject per table. The
+// messages are published asynchronously and a flush waits until all of them
+// are acknowledged by JetStream, which stores them in the streams bound to
+// their subjects, so messages are delivered at least once.
+type natsSink struct {
+	ctx      context.Context
+	servers  string
+	natsOpts []nats.Option
+	subjects map[descpb.ID]string
+	cfg      natsSinkConfig
+	metrics  *sliMetrics
+
+	conn *nats.Conn
+	js   natsPublisher
+
+	// acks receives the published messages, in order, so that the ack worker
+	// waits for their acknowledgements.
+	acks         chan natsMessage
+	stopWorkerCh chan struct{}
+	workers      sync.WaitGroup
+
+	// publishMu serializes the publication of batches, so that messages are
+	// published in the order in which they are emitted.
+	publishMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		// batch holds the messages emitted and not yet published.
+		batch      []natsMessage
+		batchBytes int
+		// inflight is the number of messages emitted and not yet acknowledged.
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+var _ Sink = (*natsSink)(nil)
+
+func isNATSSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeNATS
+}
+
+func makeNATSSink(
+	ctx context.Context,
+	u sinkURL,
+	targets jobspb.ChangefeedTargets,
+	opts map[string]string,
+	m *sliMetrics,
+) (Sink, error) {
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+
+	natsOpts, err := buildNATSOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getNATSSinkConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown nats sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	servers := strings.Split(u.Host, `,`)
+	for i := range servers {
+		servers[i] = changefeedbase.SinkSchemeNATS + `://` + servers[i]
+	}
+	return &natsSink{
+		ctx:      ctx,
+		servers:  strings.Join(servers, `,`),
+		natsOpts: natsOpts,
+		subjects: makeTopicsMap(topicPrefix, topicName, targets),
+		cfg:      cfg,
+		metrics:  m,
+	}, nil
+}
+
+// buildNATSOptions returns the options used to connect to NATS, which are
+// given by the URL of the sink.
+func buildNATSOptions(u sinkURL) ([]nats.Option, error) {
+	dialConfig := struct {
+		tlsEnabled    bool
+		tlsSkipVerify bool
+		caCert        []byte
+		clientCert    []byte
+		clientKey     []byte
+		token         string
+		userJWT       string
+		nkeySeed      string
+	}{}
+
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &dialConfig.tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &dialConfig.tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &dialConfig.caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &dialConfig.clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &dialConfig.clientKey); err != nil {
+		return nil, err
+	}
+	dialConfig.token = u.consumeParam(changefeedbase.SinkParamNATSToken)
+	dialConfig.userJWT = u.consumeParam(changefeedbase.SinkParamNATSUserJWT)
+	dialConfig.nkeySeed = u.consumeParam(changefeedbase.SinkParamNATSNKeySeed)
+
+	natsOpts := []nats.Option{nats.Name(`CockroachDB`)}
+
+	if dialConfig.tlsEnabled {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: dialConfig.tlsSkipVerify,
+		}
+		if dialConfig.caCert != nil {
+			caCertPool := x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM(dialConfig.caCert)
+			tlsConfig.RootCAs = caCertPool
+		}
+		if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+		}
+		if dialConfig.clientCert != nil && dialConfig.clientKey != nil {
+			cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
+			if err != nil {
+				return nil, errors.Wrap(err, `invalid client certificate data provided`)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		natsOpts = append(natsOpts, nats.Secure(tlsConfig))
+	} else {
+		if dialConfig.caCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if dialConfig.clientCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+	}
+
+	// Clients authenticate with a user and a password, a token, or the JWT and
+	// the NKey seed of a user, as in NATS credentials files.
+	var authMethods int
+	if u.User != nil {
+		authMethods++
+		password, _ := u.User.Password()
+		natsOpts = append(natsOpts, nats.UserInfo(u.User.Username(), password))
+	}
+	if dialConfig.token != `` {
+		authMethods++
+		natsOpts = append(natsOpts, nats.Token(dialConfig.token))
+	}
+	if dialConfig.userJWT != `` || dialConfig.nkeySeed != `` {
+		authMethods++
+		if dialConfig.userJWT == `` {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamNATSNKeySeed, changefeedbase.SinkParamNATSUserJWT)
+		} else if dialConfig.nkeySeed == `` {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamNATSUserJWT, changefeedbase.SinkParamNATSNKeySeed)
+		}
+		kp, err := nkeys.FromSeed([]byte(dialConfig.nkeySeed))
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid %s`, changefeedbase.SinkParamNATSNKeySeed)
+		}
+		userJWT := dialConfig.userJWT
+		natsOpts = append(natsOpts, nats.UserJWT(
+			func() (string, error) { return userJWT, nil },
+			kp.Sign,
+		))
+	}
+	if authMethods > 1 {
+		return nil, errors.Errorf(`only one of a user, %s, or %s and %s may be provided`,
+			changefeedbase.SinkParamNATSToken, changefeedbase.SinkParamNATSUserJWT, changefeedbase.SinkParamNATSNKeySeed)
+	}
+	return natsOpts, nil
+}
+
+// Dial implements the Sink interface.
+func (s *natsSink) Dial() error {
+	conn, err := nats.Connect(s.servers, s.natsOpts...)
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow, `connecting to nats: %s`, s.servers)
+	}
+	js, err := conn.JetStream(nats.PublishAsyncMaxPending(s.cfg.MaxPending))
+	if err != nil {
+		conn.Close()
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow, `connecting to nats: %s`, s.servers)
+	}
+	s.conn, s.js = conn, js
+	s.start()
+	return nil
+}
+
+func (s *natsSink) start() {
+	s.acks = make(chan natsMessage, s.cfg.MaxPending)
+	s.stopWorkerCh = make(chan struct{})
+	s.workers.Add(1)
+	go s.ackLoop()
+	if s.cfg.Flush.Frequency > 0 {
+		s.workers.Add(1)
+		go s.flushLoop()
+	}
+}
+
+// Close implements the Sink interface.
+func (s *natsSink) Close() error {
+	if s.js == nil {
+		return nil
+	}
+	close(s.stopWorkerCh)
+	s.workers.Wait()
+	// If we're shutting down, we don't care what happens to the outstanding
+	// messages.
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *natsSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	subject, isKnownTopic := s.subjects[topicDescr.GetID()]
+	if !isKnownTopic {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, topicDescr.GetName())
+	}
+	msg := nats.NewMsg(subject)
+	msg.Header.Set(natsKeyHeader, base64.StdEncoding.EncodeToString(key))
+	msg.Data = value
+	return s.emitMessage(ctx, natsMessage{
+		msg:           msg,
+		alloc:         alloc,
+		mvcc:          mvcc,
+		updateMetrics: s.metrics.recordEmittedMessages(),
+	})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *natsSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	// A single subject may be shared by several tables.
+	seen := make(map[string]struct{}, len(s.subjects))
+	for _, subject := range s.subjects {
+		if _, ok := seen[subject]; ok {
+			continue
+		}
+		seen[subject] = struct{}{}
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, subject, resolved)
+		if err != nil {
+			return err
+		}
+		msg := nats.NewMsg(subject)
+		msg.Data = append([]byte(nil), payload...)
+		if err := s.emitMessage(ctx, natsMessage{msg: msg}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *natsSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if err := s.publishBatch(ctx); err != nil {
+		return err
+	}
+
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+// emitMessage adds the message to the current batch, and publishes the batch
+// once it is full.
+func (s *natsSink) emitMessage(ctx context.Context, m natsMessage) error {
+	s.mu.Lock()
+	s.mu.inflight++
+	s.mu.batch = append(s.mu.batch, m)
+	s.mu.batchBytes += len(m.msg.Data)
+	full := s.batchFullLocked()
+	s.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+	return s.publishBatch(ctx)
+}
+
+// batchFullLocked returns whether the current batch should be published. As
+// with the webhook sink, messages are published as soon as they are emitted
+// unless batching is configured, in which case the batch is published when any
+// of the configured thresholds is reached.
+func (s *natsSink) batchFullLocked() bool {
+	switch {
+	case s.cfg.Flush.Messages == 0 && s.cfg.Flush.Bytes == 0 && s.cfg.Flush.Frequency == 0:
+		return true
+	case s.cfg.Flush.Messages > 0 && len(s.mu.batch) >= s.cfg.Flush.Messages:
+		return true
+	case s.cfg.Flush.Bytes > 0 && s.mu.batchBytes >= s.cfg.Flush.Bytes:
+		return true
+	default:
+		return false
+	}
+}
+
+// publishBatch publishes the messages of the current batch asynchronously, and
+// hands them to the ack worker.
+func (s *natsSink) publishBatch(ctx context.Context) error {
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+
+	s.mu.Lock()
+	batch := s.mu.batch
+	s.mu.batch, s.mu.batchBytes = nil, 0
+	s.mu.Unlock()
+
+	for i := range batch {
+		ack, err := s.js.PublishMsgAsync(batch[i].msg)
+		if err != nil {
+			// The remaining messages of the batch are not published: they are
+			// accounted for as failed, so that the next flush returns the error.
+			for j := i; j < len(batch); j++ {
+				s.finishMessage(batch[j], err)
+			}
+			return err
+		}
+		batch[i].ack = ack
+		select {
+		case s.acks <- batch[i]:
+		case <-ctx.Done():
+			for j := i; j < len(batch); j++ {
+				s.finishMessage(batch[j], ctx.Err())
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// flushLoop periodically publishes the current batch, so that messages are not
+// held back when the batch doesn't fill up.
+func (s *natsSink) flushLoop() {
+	defer s.workers.Done()
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		timer.Reset(time.Duration(s.cfg.Flush.Frequency))
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-timer.C:
+			timer.Read = true
+			if err := s.publishBatch(s.ctx); err != nil {
+				// The error is returned by the next flush.
+				log.Warningf(s.ctx, "publishing to nats: %v", err)
+			}
+		}
+	}
+}
+
+// ackLoop waits for the acknowledgements of the published messages.
+func (s *natsSink) ackLoop() {
+	defer s.workers.Done()
+
+	for {
+		var m natsMessage
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case m = <-s.acks:
+		}
+
+		var ackErr error
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-m.ack.Ok():
+		case ackErr = <-m.ack.Err():
+		}
+		s.finishMessage(m, ackErr)
+	}
+}
+
+// finishMessage accounts for a message which was acknowledged, or which failed
+// to be published.
+func (s *natsSink) finishMessage(m natsMessage, err error) {
+	if m.updateMetrics != nil && err == nil {
+		m.updateMetrics(1, m.mvcc, len(m.msg.Header.Get(natsKeyHeader))+len(m.msg.Data), sinkDoesNotCompress)
+	}
+	m.alloc.Release(s.ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.inflight--
+	if s.mu.flushErr == nil && err != nil {
+		s.mu.flushErr = err
+	}
+	if s.mu.inflight == 0 && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPullMaxEvents is the number of events returned by a request to a pull
+// sink which doesn't specify it.
+const defaultPullMaxEvents = 1000
+
+func init() {
+	sql.GetChangefeedEventsFetcherHook = func() sql.ChangefeedEventsFetcher {
+		return pullEventsFetcher{}
+	}
+}
+
+// pullBuffers holds the buffers of the changefeeds with a pull sink running in
+// this process, by node and job. The sinks of the ChangeAggregator and of the
+// ChangeFrontier of a changefeed, which all run on the node of its job, share
+// the same buffer.
+var pullBuffers = struct {
+	syncutil.Mutex
+	m map[pullBufferKey]*pullBuffer
+}{m: make(map[pullBufferKey]*pullBuffer)}
+
+type pullBufferKey struct {
+	instanceID base.SQLInstanceID
+	jobID      jobspb.JobID
+}
+
+// acquirePullBuffer returns the buffer of the given changefeed, creating it if
+// needed. It must be released with releasePullBuffer.
+func acquirePullBuffer(key pullBufferKey) *pullBuffer {
+	pullBuffers.Lock()
+	defer pullBuffers.Unlock()
+	b, ok := pullBuffers.m[key]
+	if !ok {
+		b = &pullBuffer{session: uuid.MakeV4().Short()}
+		b.mu.notify = make(chan struct{})
+		pullBuffers.m[key] = b
+	}
+	b.refs++
+	return b
+}
+
+// releasePullBuffer releases a buffer acquired with acquirePullBuffer. The
+// buffer is discarded once it is released by all the sinks of the changefeed.
+func releasePullBuffer(ctx context.Context, key pullBufferKey, b *pullBuffer) {
+	pullBuffers.Lock()
+	defer pullBuffers.Unlock()
+	if b.refs--; b.refs > 0 {
+		return
+	}
+	delete(pullBuffers.m, key)
+	b.close(ctx)
+}
+
+func lookupPullBuffer(key pullBufferKey) *pullBuffer {
+	pullBuffers.Lock()
+	defer pullBuffers.Unlock()
+	return pullBuffers.m[key]
+}
+
+// pullEvent is an event buffered until it is acknowledged.
+type pullEvent struct {
+	seq   int64
+	event serverpb.ChangefeedEventsResponse_Event
+	alloc kvevent.Alloc
+}
+
+// pullBuffer buffers the events of a changefeed with a pull sink until the
+// client acknowledges them.
+//
+// Events are numbered in the order in which they are emitted, and the cursors
+// returned to the client are made of the session of the buffer and of the
+// number of the last event returned. The session changes when the changefeed
+// restarts, in which case the changefeed resumes from its last checkpoint and
+// the acknowledgements of events of an earlier session are ignored.
+type pullBuffer struct {
+	// refs is protected by the mutex of pullBuffers.
+	refs    int
+	session string
+
+	mu struct {
+		syncutil.Mutex
+		// events are the events which haven't been acknowledged, in order.
+		events []pullEvent
+		// acked is the number of the last event acknowledged, and last the number
+		// of the last event emitted.
+		acked, last int64
+		closed      bool
+		// notify is closed, and replaced, when events are emitted or
+		// acknowledged, or when the buffer is closed.
+		notify chan struct{}
+	}
+}
+
+func (b *pullBuffer) notifyLocked() {
+	close(b.mu.notify)
+	b.mu.notify = make(chan struct{})
+}
+
+func (b *pullBuffer) add(ev serverpb.ChangefeedEventsResponse_Event, alloc kvevent.Alloc) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.mu.closed {
+		return errors.New("pull sink closed")
+	}
+	b.mu.last++
+	b.mu.events = append(b.mu.events, pullEvent{seq: b.mu.last, event: ev, alloc: alloc})
+	b.notifyLocked()
+	return nil
+}
+
+// ackLocked acknowledges the events up to the given one.
+func (b *pullBuffer) ackLocked(ctx context.Context, seq int64) {
+	if seq > b.mu.last {
+		seq = b.mu.last
+	}
+	if seq <= b.mu.acked {
+		return
+	}
+	n := 0
+	for n < len(b.mu.events) && b.mu.events[n].seq <= seq {
+		b.mu.events[n].alloc.Release(ctx)
+		n++
+	}
+	b.mu.events = append(b.mu.events[:0], b.mu.events[n:]...)
+	b.mu.acked = seq
+	b.notifyLocked()
+}
+
+// waitAcked waits until all the events emitted so far are acknowledged.
+func (b *pullBuffer) waitAcked(ctx context.Context) error {
+	b.mu.Lock()
+	last := b.mu.last
+	b.mu.Unlock()
+	for {
+		b.mu.Lock()
+		acked, closed, notify := b.mu.acked, b.mu.closed, b.mu.notify
+		b.mu.Unlock()
+		if acked >= last {
+			return nil
+		}
+		if closed {
+			return errors.New("pull sink closed")
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *pullBuffer) close(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.mu.events {
+		b.mu.events[i].alloc.Release(ctx)
+	}
+	b.mu.events = nil
+	b.mu.closed = true
+	b.notifyLocked()
+}
+
+// fetch acknowledges the events up to the given cursor, and returns the next
+// ones, waiting up to the given duration for events if there are none.
+func (b *pullBuffer) fetch(
+	ctx context.Context, cursor string, maxEvents int, wait time.Duration,
+) (*serverpb.ChangefeedEventsResponse, error) {
+	if cursor != "" {
+		session, seq, err := parsePullCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if session == b.session {
+			b.mu.Lock()
+			b.ackLocked(ctx, seq)
+			b.mu.Unlock()
+		}
+	}
+	if maxEvents <= 0 {
+		maxEvents = defaultPullMaxEvents
+	}
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(wait)
+	for {
+		b.mu.Lock()
+		if b.mu.closed {
+			b.mu.Unlock()
+			return nil, status.Errorf(codes.Unavailable, "changefeed is not running")
+		}
+		if len(b.mu.events) > 0 || wait <= 0 {
+			resp := &serverpb.ChangefeedEventsResponse{}
+			last := b.mu.acked
+			for i := 0; i < len(b.mu.events) && i < maxEvents; i++ {
+				resp.Events = append(resp.Events, b.mu.events[i].event)
+				last = b.mu.events[i].seq
+			}
+			b.mu.Unlock()
+			resp.Cursor = makePullCursor(b.session, last)
+			return resp, nil
+		}
+		notify := b.mu.notify
+		b.mu.Unlock()
+		select {
+		case <-notify:
+		case <-timer.C:
+			timer.Read = true
+			wait = 0
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func makePullCursor(session string, seq int64) string {
+	return fmt.Sprintf("%s/%d", session, seq)
+}
+
+func parsePullCursor(cursor string) (session string, seq int64, err error) {
+	i := strings.LastIndexByte(cursor, '/')
+	if i >= 0 {
+		seq, err = strconv.ParseInt(cursor[i+1:], 10, 64)
+	}
+	if i < 0 || err != nil {
+		return "", 0, status.Errorf(codes.InvalidArgument, "invalid cursor %q", cursor)
+	}
+	return cursor[:i], seq, nil
+}
+
+// pullEventsFetcher implements sql.ChangefeedEventsFetcher with the buffers of
+// the pull sinks.
+type pullEventsFetcher struct{}
+
+var _ sql.ChangefeedEventsFetcher = pullEventsFetcher{}
+
+// FetchChangefeedEvents implements the sql.ChangefeedEventsFetcher interface.
+func (pullEventsFetcher) FetchChangefeedEvents(
+	ctx context.Context, instanceID base.SQLInstanceID, req *serverpb.ChangefeedEventsRequest,
+) (*serverpb.ChangefeedEventsResponse, error) {
+	return fetchChangefeedEvents(ctx, instanceID, req)
+}
+
+// fetchChangefeedEvents returns the events buffered by the changefeed of the
+// request, which runs on the given SQL instance.
+func fetchChangefeedEvents(
+	ctx context.Context, instanceID base.SQLInstanceID, req *serverpb.ChangefeedEventsRequest,
+) (*serverpb.ChangefeedEventsResponse, error) {
+	b := lookupPullBuffer(pullBufferKey{instanceID: instanceID, jobID: jobspb.JobID(req.JobID)})
+	if b == nil {
+		return nil, status.Errorf(codes.NotFound,
+			"changefeed %d with a pull sink is not running on node %d", req.JobID, instanceID)
+	}
+	return b.fetch(ctx, req.Cursor, int(req.MaxEvents), req.Wait)
+}
+
+// pullSink buffers the events of a changefeed until they are fetched and
+// acknowledged by a client through the ChangefeedEvents RPC, instead of
+// delivering them to an external system. Flush waits for the acknowledgement
+// of the events emitted so far, so the changefeed only checkpoints events
+// which the client has acknowledged.
+type pullSink struct {
+	key     pullBufferKey
+	buf     *pullBuffer
+	metrics *sliMetrics
+}
+
+var _ Sink = (*pullSink)(nil)
+
+func makePullSink(
+	instanceID base.SQLInstanceID, jobID jobspb.JobID, m *sliMetrics,
+) (Sink, error) {
+	if jobID == jobspb.InvalidJobID {
+		// This is the sink created to validate the changefeed: the buffer is
+		// created by the sinks of its processors.
+		return &pullSink{metrics: m}, nil
+	}
+	key := pullBufferKey{instanceID: instanceID, jobID: jobID}
+	return &pullSink{key: key, buf: acquirePullBuffer(key), metrics: m}, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *pullSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	defer s.metrics.recordEmittedMessages()(1, mvcc, len(key)+len(value), sinkDoesNotCompress)
+	return s.buf.add(serverpb.ChangefeedEventsResponse_Event{
+		Topic:   topic.GetName(),
+		Key:     key,
+		Value:   value,
+		Updated: updated,
+	}, alloc)
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pullSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, "" /* topic */, resolved)
+	if err != nil {
+		return err
+	}
+	return s.buf.add(serverpb.ChangefeedEventsResponse_Event{
+		Value:    payload,
+		Resolved: resolved,
+	}, kvevent.Alloc{})
+}
+
+// Flush implements the Sink interface.
+func (s *pullSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+	if s.buf == nil {
+		return nil
+	}
+	return s.buf.waitAcked(ctx)
+}
+
+// Close implements the Sink interface.
+func (s *pullSink) Close() error {
+	if s.buf != nil {
+		releasePullBuffer(context.Background(), s.key, s.buf)
+		s.buf = nil
+	}
+	return nil
+}
+
+// Dial implements the Sink interface.
+func (s *pullSink) Dial() error {
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPullSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const instanceID = base.SQLInstanceID(1)
+	const jobID = jobspb.JobID(42)
+
+	fetch := func(cursor string, maxEvents int32) (*serverpb.ChangefeedEventsResponse, error) {
+		return fetchChangefeedEvents(ctx, instanceID, &serverpb.ChangefeedEventsRequest{
+			JobID: int64(jobID), Cursor: cursor, MaxEvents: maxEvents,
+		})
+	}
+	keys := func(resp *serverpb.ChangefeedEventsResponse) []string {
+		var ret []string
+		for _, ev := range resp.Events {
+			ret = append(ret, string(ev.Key))
+		}
+		return ret
+	}
+
+	_, err := fetch("", 0)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The aggregator and the frontier share the buffer of the changefeed.
+	aggregator, err := makePullSink(instanceID, jobID, nil /* metrics */)
+	require.NoError(t, err)
+	frontier, err := makePullSink(instanceID, jobID, nil /* metrics */)
+	require.NoError(t, err)
+
+	// Nothing to acknowledge.
+	require.NoError(t, frontier.Flush(ctx))
+
+	var pool testAllocPool
+	for _, k := range []string{`a`, `b`, `c`} {
+		require.NoError(t, aggregator.EmitRow(
+			ctx, topic(`t`), []byte(k), []byte(`v`), hlc.Timestamp{WallTime: 1}, zeroTS, pool.alloc()))
+	}
+	require.EqualValues(t, 3, pool.used())
+
+	resp, err := fetch("", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{`a`, `b`}, keys(resp))
+	require.Equal(t, `t`, resp.Events[0].Topic)
+	first := resp.Cursor
+
+	// Events are returned until they are acknowledged.
+	resp, err = fetch("", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{`a`, `b`, `c`}, keys(resp))
+
+	// Flush waits until the events are acknowledged.
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, frontier.Flush(timeoutCtx), context.DeadlineExceeded)
+
+	resp, err = fetch(first, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{`c`}, keys(resp))
+	require.EqualValues(t, 1, pool.used())
+
+	flushed := make(chan error, 1)
+	go func() { flushed <- frontier.Flush(ctx) }()
+	resp, err = fetch(resp.Cursor, 0)
+	require.NoError(t, err)
+	require.Empty(t, resp.Events)
+	require.NoError(t, <-flushed)
+	require.EqualValues(t, 0, pool.used())
+
+	// Cursors of another session are ignored, and invalid ones are rejected.
+	require.NoError(t, aggregator.EmitRow(
+		ctx, topic(`t`), []byte(`d`), nil, hlc.Timestamp{WallTime: 2}, zeroTS, pool.alloc()))
+	resp, err = fetch("other/10", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{`d`}, keys(resp))
+	_, err = fetch("garbage", 0)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The buffer is released once all the sinks are closed.
+	require.NoError(t, aggregator.Close())
+	_, err = fetch("", 0)
+	require.NoError(t, err)
+	require.NoError(t, frontier.Close())
+	require.EqualValues(t, 0, pool.used())
+	_, err = fetch("", 0)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
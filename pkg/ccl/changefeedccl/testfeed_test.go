@@ -12,6 +12,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	gosql "database/sql"
 	"encoding/base64"
 	gojson "encoding/json"
@@ -50,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgx/v4"
+	"github.com/nats-io/nats.go"
 )
 
 type sinklessFeedFactory struct {
@@ -1696,3 +1698,160 @@ func (p *pubsubFeed) Close() error {
 	}
 	return nil
 }
+
+type natsFeedFactory struct {
+	enterpriseFeedFactory
+	auth   cdctest.NATSAuth
+	useTLS bool
+}
+
+var _ cdctest.TestFeedFactory = (*natsFeedFactory)(nil)
+
+// makeNATSFeedFactory returns a TestFeedFactory implementation using the `nats`
+// uri, which publishes to an embedded NATS server requiring a random
+// authentication method, with or without TLS.
+func makeNATSFeedFactory(srv serverutils.TestServerInterface, db *gosql.DB) cdctest.TestFeedFactory {
+	auth := cdctest.NATSAuth(rand.Intn(int(cdctest.NATSAuthJWT) + 1))
+	return makeNATSFeedFactoryWithAuth(srv, db, auth, rand.Float32() < 0.5)
+}
+
+// makeNATSFeedFactoryWithAuth returns a TestFeedFactory implementation using
+// the `nats` uri, which publishes to an embedded NATS server requiring the
+// given authentication method.
+func makeNATSFeedFactoryWithAuth(
+	srv serverutils.TestServerInterface, db *gosql.DB, auth cdctest.NATSAuth, useTLS bool,
+) cdctest.TestFeedFactory {
+	return &natsFeedFactory{
+		enterpriseFeedFactory: enterpriseFeedFactory{
+			s:  srv,
+			db: db,
+			di: newDepInjector(srv),
+		},
+		auth:   auth,
+		useTLS: useTLS,
+	}
+}
+
+// Feed implements cdctest.TestFeedFactory
+func (f *natsFeedFactory) Feed(create string, args ...interface{}) (cdctest.TestFeed, error) {
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return nil, err
+	}
+	createStmt := parsed.AST.(*tree.CreateChangefeed)
+
+	var cert *tls.Certificate
+	var caCert string
+	if f.useTLS {
+		if cert, caCert, err = cdctest.NewCACertBase64Encoded(); err != nil {
+			return nil, err
+		}
+	}
+	natsServer, err := cdctest.StartTestNATSServer(f.auth, `changefeed`, cert, caCert)
+	if err != nil {
+		return nil, err
+	}
+	if createStmt.SinkURI == nil {
+		createStmt.SinkURI = tree.NewStrVal(natsServer.SinkURI())
+	}
+
+	c := &natsFeed{
+		jobFeed:        newJobFeed(f.db, func(s Sink) Sink { return s }),
+		seenTrackerMap: make(map[string]struct{}),
+		server:         natsServer,
+		// The messages are read from the stream by a JetStream consumer, so that
+		// the feed only sees the messages which JetStream stored, and therefore
+		// acknowledged.
+		source: make(chan *nats.Msg, 1024),
+	}
+	if c.conn, err = natsServer.Connect(); err != nil {
+		c.closeServer()
+		return nil, err
+	}
+	js, err := c.conn.JetStream()
+	if err != nil {
+		c.closeServer()
+		return nil, err
+	}
+	if _, err := js.ChanSubscribe(
+		natsServer.Stream()+`.>`, c.source, nats.DeliverAll(), nats.AckNone(),
+	); err != nil {
+		c.closeServer()
+		return nil, err
+	}
+
+	if err := f.startFeedJob(c.jobFeed, createStmt.String(), args...); err != nil {
+		c.closeServer()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Server implements TestFeedFactory
+func (f *natsFeedFactory) Server() serverutils.TestServerInterface {
+	return f.s
+}
+
+type natsFeed struct {
+	*jobFeed
+	seenTrackerMap
+
+	server *cdctest.TestNATSServer
+	conn   *nats.Conn
+	source chan *nats.Msg
+}
+
+var _ cdctest.TestFeed = (*natsFeed)(nil)
+
+// Partitions implements TestFeed
+func (f *natsFeed) Partitions() []string {
+	return []string{``}
+}
+
+// Next implements TestFeed
+func (f *natsFeed) Next() (*cdctest.TestFeedMessage, error) {
+	for {
+		var msg *nats.Msg
+		select {
+		case <-f.shutdown:
+			return nil, f.terminalJobError()
+		case msg = <-f.source:
+		}
+
+		// Each table is published to its own subject, which is the name of the
+		// table prefixed with the name of the stream.
+		m := &cdctest.TestFeedMessage{
+			Topic: strings.TrimPrefix(msg.Subject, f.server.Stream()+`.`),
+		}
+		encodedKey := msg.Header.Get(natsKeyHeader)
+		if encodedKey == `` {
+			// It's a resolved timestamp.
+			m.Resolved = msg.Data
+			return m, nil
+		}
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, err
+		}
+		m.Key, m.Value = key, msg.Data
+		if isNew := f.markSeen(m); isNew {
+			return m, nil
+		}
+	}
+}
+
+// closeServer closes the connection to the embedded NATS server, and shuts it
+// down.
+func (f *natsFeed) closeServer() {
+	if f.conn != nil {
+		f.conn.Close()
+	}
+	f.server.Close()
+}
+
+// Close implements TestFeed
+func (f *natsFeed) Close() error {
+	err := f.jobFeed.Close()
+	f.closeServer()
+	return err
+}
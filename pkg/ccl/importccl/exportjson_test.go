@@ -0,0 +1,98 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/workload/bank"
+	"github.com/stretchr/testify/require"
+)
+
+const exportJSONFilePattern = "export*-n*.0.json"
+
+func TestExportJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	tc := testcluster.StartTestCluster(
+		t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{ExternalIODir: dir}})
+	defer tc.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	db.Exec(t, `
+		CREATE TABLE t (id INT PRIMARY KEY, s STRING, f FLOAT, b BOOL, a INT[], j JSONB);
+		INSERT INTO t VALUES
+			(1, 'a"b', 1.5, true, ARRAY[1, 2], '{"k": [1, null]}'),
+			(2, NULL, NULL, NULL, NULL, NULL);
+	`)
+
+	// Columns are written in order, and NULLs as JSON nulls.
+	db.Exec(t, `EXPORT INTO JSON 'nodelocal://0/t' FROM SELECT * FROM t ORDER BY id`)
+	contents := readFileByGlob(t, filepath.Join(dir, "t", exportJSONFilePattern))
+	require.Equal(t,
+		`{"id": 1, "s": "a\"b", "f": 1.5, "b": true, "a": [1, 2], "j": {"k": [1, null]}}`+"\n"+
+			`{"id": 2, "s": null, "f": null, "b": null, "a": null, "j": null}`+"\n",
+		string(contents))
+
+	db.Exec(t, `EXPORT INTO JSON 'nodelocal://0/gz' WITH compression = 'gzip' FROM SELECT id FROM t ORDER BY id`)
+	gz, err := gzip.NewReader(bytes.NewReader(
+		readFileByGlob(t, filepath.Join(dir, "gz", exportJSONFilePattern+".gz"))))
+	require.NoError(t, err)
+	contents, err = ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "{\"id\": 1}\n{\"id\": 2}\n", string(contents))
+
+	// The export can be imported back.
+	db.Exec(t, `CREATE TABLE t2 (id INT PRIMARY KEY, s STRING, f FLOAT, b BOOL, a INT[], j JSONB)`)
+	db.Exec(t, `IMPORT INTO t2 JSON DATA ('nodelocal://0/t/export*-n*.0.json')`)
+	db.CheckQueryResults(t, `SELECT * FROM t2 ORDER BY id`, db.QueryStr(t, `SELECT * FROM t ORDER BY id`))
+}
+
+func TestExportImportBankJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	db, _, cleanup := setupExportableBank(t, 3, 100)
+	defer cleanup()
+
+	db.Exec(t, "UPDATE bank SET payload = payload || '✅' WHERE id = 5")
+	db.Exec(t, "UPDATE bank SET payload = NULL WHERE id % 2 = 0")
+
+	var asOf string
+	db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&asOf)
+
+	// Each of the export processors writes its own files.
+	db.Exec(t, `EXPORT INTO JSON 'userfile:///t/' WITH chunk_rows = $1
+		FROM SELECT * FROM bank AS OF SYSTEM TIME `+asOf, 13)
+
+	schema := bank.FromRows(1).Tables()[0].Schema
+	db.Exec(t, "CREATE TABLE bank2 "+schema)
+	db.Exec(t, `IMPORT INTO bank2 JSON DATA ('userfile:///t/*')`)
+
+	db.CheckQueryResults(t,
+		`SELECT * FROM bank AS OF SYSTEM TIME `+asOf+` ORDER BY id`,
+		db.QueryStr(t, `SELECT * FROM bank2 ORDER BY id`),
+	)
+}
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/parquetccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -26,9 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
 	goparquet "github.com/fraugster/parquet-go"
-	"github.com/fraugster/parquet-go/parquet"
 	"github.com/fraugster/parquet-go/parquetschema"
-	"github.com/lib/pq/oid"
 )
 
 const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
@@ -39,7 +38,7 @@ type parquetExporter struct {
 	buf            *bytes.Buffer
 	parquetWriter  *goparquet.FileWriter
 	schema         *parquetschema.SchemaDefinition
-	parquetColumns []parquetColumn
+	parquetColumns []parquetccl.Column
 }
 
 // Write appends a record to a parquet file.
@@ -65,7 +64,7 @@ func (c *parquetExporter) Bytes() []byte {
 
 func (c *parquetExporter) ResetBuffer() {
 	c.buf.Reset()
-	c.parquetWriter = buildFileWriter(c.buf, c.schema)
+	c.parquetWriter = parquetccl.NewFileWriter(c.buf, c.schema)
 }
 
 // Len returns length of the buffer with content.
@@ -97,7 +96,7 @@ func newParquetExporter(
 	if err != nil {
 		return nil, err
 	}
-	schema := newParquetSchema(parquetColumns)
+	schema := parquetccl.NewSchema(parquetColumns)
 
 	exporter = &parquetExporter{
 		buf:            buf,
@@ -107,23 +106,13 @@ func newParquetExporter(
 	return exporter, nil
 }
 
-// parquetColumn contains the relevant data to map a crdb table column to a parquet table column.
-type parquetColumn struct {
-	name     string
-	crbdType *types.T
-
-	//definition contains all relevant information around the parquet type for the table column
-	definition *parquetschema.ColumnDefinition
-
-	//encodeFn converts crdb table column value to a native go type.
-	encodeFn func(datum tree.Datum) (interface{}, error)
-}
-
 // newParquetColumns creates a list of parquet columns, given the input relation's column types.
-func newParquetColumns(typs []*types.T, sp execinfrapb.ParquetWriterSpec) ([]parquetColumn, error) {
-	parquetColumns := make([]parquetColumn, len(typs))
+func newParquetColumns(
+	typs []*types.T, sp execinfrapb.ParquetWriterSpec,
+) ([]parquetccl.Column, error) {
+	parquetColumns := make([]parquetccl.Column, len(typs))
 	for i := 0; i < len(typs); i++ {
-		parquetCol, err := newParquetColumn(typs[i], sp.ColNames[i], sp.ColNullability[i])
+		parquetCol, err := parquetccl.NewColumn(typs[i], sp.ColNames[i], sp.ColNullability[i])
 		if err != nil {
 			return nil, err
 		}
@@ -132,174 +121,6 @@ func newParquetColumns(typs []*types.T, sp execinfrapb.ParquetWriterSpec) ([]par
 	return parquetColumns, nil
 }
 
-// newParquetColumn populates a parquetColumn by finding the right parquet type and defining the
-// encodeFn.
-func newParquetColumn(typ *types.T, name string, nullable bool) (parquetColumn, error) {
-	col := parquetColumn{}
-	col.definition = new(parquetschema.ColumnDefinition)
-	col.definition.SchemaElement = parquet.NewSchemaElement()
-	col.name = name
-	col.crbdType = typ
-
-	/*
-			The type of a parquet column is either a group (i.e.
-		  an array in crdb) or a primitive type (e.g., int, float, boolean,
-		  string) and the repetition can be one of the three following cases:
-
-		  - required: exactly one occurrence (i.e. the column value is a scalar, and
-		  cannot have null values). A column is set to required if the user
-		  specified the CRDB column as NOT NULL.
-		  - optional: 0 or 1 occurrence (i.e. same as above, but can have values)
-		  - repeated: 0 or more occurrences (the column value will be an array. A
-				value within the array will have its own repetition type)
-
-			See this blog post for more on parquet type specification:
-			https://blog.twitter.com/engineering/en_us/a/2013/dremel-made-simple-with-parquet
-	*/
-	col.definition.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL)
-	if !nullable {
-		col.definition.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED)
-	}
-	col.definition.SchemaElement.Name = col.name
-
-	// MB figured out the low level properties of the encoding by running the goland debugger on
-	// the following vendor example:
-	// https://github.com/fraugster/parquet-go/blob/master/examples/write-low-level/main.go
-	switch typ.Family() {
-	case types.BoolFamily:
-		col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_BOOLEAN)
-		col.encodeFn = func(d tree.Datum) (interface{}, error) {
-			return bool(*d.(*tree.DBool)), nil
-		}
-
-	case types.StringFamily:
-		col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
-		col.definition.SchemaElement.LogicalType = parquet.NewLogicalType()
-		col.definition.SchemaElement.LogicalType.STRING = parquet.NewStringType()
-		col.definition.SchemaElement.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)
-		col.encodeFn = func(d tree.Datum) (interface{}, error) {
-			return []byte(*d.(*tree.DString)), nil
-		}
-
-	case types.IntFamily:
-		if typ.Oid() == oid.T_int8 {
-			col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_INT64)
-			col.encodeFn = func(d tree.Datum) (interface{}, error) {
-				return int64(*d.(*tree.DInt)), nil
-			}
-		} else {
-			col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_INT32)
-			col.encodeFn = func(d tree.Datum) (interface{}, error) {
-				return int32(*d.(*tree.DInt)), nil
-			}
-		}
-
-	case types.FloatFamily:
-		if typ.Oid() == oid.T_float8 {
-			col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_DOUBLE)
-			col.encodeFn = func(d tree.Datum) (interface{}, error) {
-				return float64(*d.(*tree.DFloat)), nil
-			}
-		} else {
-			col.definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_FLOAT)
-			col.encodeFn = func(d tree.Datum) (interface{}, error) {
-				return float32(*d.(*tree.DFloat)), nil
-			}
-		}
-
-	case types.ArrayFamily:
-
-		// Define a list such that the parquet schema in json is:
-		/*
-			required group colName (LIST){ // parent
-				repeated group list { // child
-					required colType element; //grandChild
-				}
-			}
-		*/
-		// MB figured this out by running toy examples of the fraugster-parquet
-		// vendor repository for added context, checkout this issue
-		// https://github.com/fraugster/parquet-go/issues/18
-
-		// First, define the grandChild definition, the schema for the array value.
-		grandChild, err := newParquetColumn(typ.ArrayContents(), "element", true)
-		if err != nil {
-			return col, err
-		}
-
-		// Next define the child definition, required by fraugster-parquet vendor library. Again,
-		// there's little documentation on this. MB figured this out using a debugger.
-		child := &parquetschema.ColumnDefinition{}
-		child.SchemaElement = parquet.NewSchemaElement()
-		child.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.
-			FieldRepetitionType_REPEATED)
-		child.SchemaElement.Name = "list"
-		child.Children = []*parquetschema.ColumnDefinition{grandChild.definition}
-		ngc := int32(len(child.Children))
-		child.SchemaElement.NumChildren = &ngc
-
-		// Finally, define the parent definition.
-		col.definition.Children = []*parquetschema.ColumnDefinition{child}
-		nc := int32(len(col.definition.Children))
-		child.SchemaElement.NumChildren = &nc
-		col.definition.SchemaElement.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_LIST)
-		col.encodeFn = func(d tree.Datum) (interface{}, error) {
-
-			datumArr := d.(*tree.DArray)
-			els := make([]map[string]interface{}, datumArr.Len())
-			for i, elt := range datumArr.Array {
-				var el interface{}
-				if elt.ResolvedType().Family() == types.UnknownFamily {
-					// skip encoding the datum
-				} else {
-					el, err = grandChild.encodeFn(elt)
-					if err != nil {
-						return col, err
-					}
-				}
-				els[i] = map[string]interface{}{"element": el}
-			}
-			encEl := map[string]interface{}{"list": els}
-			return encEl, nil
-		}
-
-	default:
-		return col, errors.Errorf("parquet export does not support the %v type yet", typ.Family())
-	}
-
-	return col, nil
-}
-
-// newParquetSchema creates the schema for the parquet file,
-// see example schema:
-//     https://github.com/fraugster/parquet-go/issues/18#issuecomment-946013210
-// see docs here:
-//     https://pkg.go.dev/github.com/fraugster/parquet-go/parquetschema#SchemaDefinition
-func newParquetSchema(parquetFields []parquetColumn) *parquetschema.SchemaDefinition {
-
-	schemaDefinition := new(parquetschema.SchemaDefinition)
-	schemaDefinition.RootColumn = new(parquetschema.ColumnDefinition)
-	schemaDefinition.RootColumn.SchemaElement = parquet.NewSchemaElement()
-
-	for i := 0; i < len(parquetFields); i++ {
-		schemaDefinition.RootColumn.Children = append(schemaDefinition.RootColumn.Children,
-			parquetFields[i].definition)
-		schemaDefinition.RootColumn.SchemaElement.Name = "root"
-	}
-	return schemaDefinition
-}
-
-func buildFileWriter(
-	buf *bytes.Buffer, schema *parquetschema.SchemaDefinition,
-) *goparquet.FileWriter {
-	pw := goparquet.NewFileWriter(buf,
-		// TODO(MB): allow for user defined compression
-		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
-		goparquet.WithSchemaDefinition(schema),
-	)
-	return pw
-}
-
 func newParquetWriterProcessor(
 	flowCtx *execinfra.FlowCtx,
 	processorID int32,
@@ -391,16 +212,16 @@ func (sp *parquetWriterProcessor) Run(ctx context.Context) {
 
 				for i, ed := range row {
 					if ed.IsNull() {
-						parquetRow[exporter.parquetColumns[i].name] = nil
+						parquetRow[exporter.parquetColumns[i].Name] = nil
 					} else {
 						if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
 							return err
 						}
-						edNative, err := exporter.parquetColumns[i].encodeFn(ed.Datum)
+						edNative, err := exporter.parquetColumns[i].EncodeFn(ed.Datum)
 						if err != nil {
 							return err
 						}
-						parquetRow[exporter.parquetColumns[i].name] = edNative
+						parquetRow[exporter.parquetColumns[i].Name] = edNative
 					}
 				}
 				if err := exporter.Write(parquetRow); err != nil {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -93,6 +94,10 @@ const (
 	avroSchema    = "schema"
 	avroSchemaURI = "schema_uri"
 
+	// Name of a JSONB column into which the whole objects of JSON files are
+	// imported.
+	jsonColumn = "json_column"
+
 	pgDumpIgnoreAllUnsupported     = "ignore_unsupported_statements"
 	pgDumpIgnoreShuntFileDest      = "log_ignored_statements"
 	pgDumpUnsupportedSchemaStmtLog = "unsupported_schema_stmts"
@@ -134,6 +139,8 @@ var importOptionExpectValues = map[string]sql.KVStringOptValidate{
 	avroBinRecords:         sql.KVStringOptRequireNoValue,
 	avroJSONRecords:        sql.KVStringOptRequireNoValue,
 
+	jsonColumn: sql.KVStringOptRequireValue,
+
 	pgDumpIgnoreAllUnsupported: sql.KVStringOptRequireNoValue,
 	pgDumpIgnoreShuntFileDest:  sql.KVStringOptRequireValue,
 }
@@ -166,6 +173,8 @@ var avroAllowedOptions = makeStringSet(
 	avroStrict, avroBinRecords, avroJSONRecords,
 	avroRecordsSeparatedBy, avroSchema, avroSchemaURI, optMaxRowSize, csvRowLimit,
 )
+var parquetAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
+var jsonAllowedOptions = makeStringSet(avroStrict, csvRowLimit, jsonColumn, optMaxRowSize)
 var csvAllowedOptions = makeStringSet(
 	csvDelimiter, csvComment, csvNullIf, csvSkip, csvStrictQuotes, csvRowLimit,
 )
@@ -187,6 +196,8 @@ var allowedIntoFormats = map[string]struct{}{
 	"CSV":       {},
 	"AVRO":      {},
 	"DELIMITED": {},
+	"JSON":      {},
+	"PARQUET":   {},
 	"PGCOPY":    {},
 }
 
@@ -724,6 +735,59 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ImportParquet) {
+				return pgerror.New(pgcode.FeatureNotSupported,
+					"IMPORT from Parquet files is only available once the cluster is fully upgraded")
+			}
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.Parquet.RowLimit = int64(rowLimit)
+			}
+		case "JSON":
+			if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ImportExportJSON) {
+				return pgerror.New(pgcode.FeatureNotSupported,
+					"IMPORT from JSON files is only available once the cluster is fully upgraded")
+			}
+			if err = validateFormatOptions(importStmt.FileFormat, opts, jsonAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_JSON
+			_, format.Json.StrictMode = opts[avroStrict]
+			format.Json.JsonColumn = opts[jsonColumn]
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.Json.RowLimit = int64(rowLimit)
+			}
+			maxRowSize := int32(defaultScanBuffer)
+			if override, ok := opts[optMaxRowSize]; ok {
+				sz, err := humanizeutil.ParseBytes(override)
+				if err != nil {
+					return err
+				}
+				if sz < 1 || sz > math.MaxInt32 {
+					return errors.Errorf("%d out of range: %d", maxRowSize, sz)
+				}
+				maxRowSize = int32(sz)
+			}
+			format.Json.MaxRowSize = maxRowSize
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -887,6 +951,13 @@ func importPlanHook(
 
 		telemetry.CountBucketed("import.files", int64(len(files)))
 
+		if format.Format == roachpb.IOFileFormat_Parquet {
+			files, err = splitParquetFiles(ctx, p, files, &format)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Record telemetry for userfile being used as the import target.
 		for _, file := range files {
 			uri, err := url.Parse(file)
@@ -994,6 +1065,34 @@ func importPlanHook(
 	return fn, utilccl.BulkJobExecutionResultHeader, nil, false, nil
 }
 
+// splitParquetFiles lists each Parquet file as several inputs when there are
+// fewer files than nodes, so that the row groups of the files are read by
+// processors on all the nodes. See roachpb.ParquetOptions.RowGroupSlices.
+func splitParquetFiles(
+	ctx context.Context, p sql.PlanHookState, files []string, format *roachpb.IOFileFormat,
+) ([]string, error) {
+	if format.Parquet.RowLimit > 0 {
+		// The row limit applies to whole files.
+		return files, nil
+	}
+	_, nodes, err := p.DistSQLPlanner().SetupAllNodesPlanning(ctx, p.ExtendedEvalContext(), p.ExecCfg())
+	if err != nil {
+		return nil, err
+	}
+	slices := len(nodes) / len(files)
+	if slices <= 1 {
+		return files, nil
+	}
+	format.Parquet.RowGroupSlices = int32(slices)
+	split := make([]string, 0, len(files)*slices)
+	for _, file := range files {
+		for i := 0; i < slices; i++ {
+			split = append(split, file)
+		}
+	}
+	return split, nil
+}
+
 func parseAvroOptions(
 	ctx context.Context, opts map[string]string, p sql.PlanHookState, format *roachpb.IOFileFormat,
 ) error {
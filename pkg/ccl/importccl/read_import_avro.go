@@ -158,6 +158,9 @@ var familyToAvroT = map[types.Family][]string{
 	types.BitFamily:            {"string"},
 	types.DecimalFamily:        {"string"},
 	types.EnumFamily:           {"string"},
+	types.TSQueryFamily:        {"string"},
+	types.TSVectorFamily:       {"string"},
+	types.RangeFamily:          {"string"},
 }
 
 // avroConsumer implements importRowConsumer interface.
@@ -0,0 +1,282 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// jsonToDatum converts a JSON value to a datum of the target type. Any value
+// is imported as is into JSONB columns. Otherwise, arrays are converted
+// element by element into array columns, and strings, numbers and booleans
+// are parsed as the target type, like CSV fields.
+func jsonToDatum(j json.JSON, targetT *types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if j.Type() == json.NullJSONType {
+		return tree.DNull, nil
+	}
+	if targetT.Family() == types.JsonFamily {
+		return tree.NewDJSON(j), nil
+	}
+	switch j.Type() {
+	case json.ArrayJSONType:
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Errorf("cannot convert JSON array to %s", targetT)
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for i := 0; i < j.Len(); i++ {
+			elem, err := j.FetchValIdx(i)
+			if err != nil {
+				return nil, err
+			}
+			d, err := jsonToDatum(elem, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case json.ObjectJSONType:
+		return nil, errors.Errorf("cannot convert JSON object to %s", targetT)
+	}
+	s, err := j.AsText()
+	if err != nil {
+		return nil, err
+	}
+	return rowenc.ParseDatumStringAs(targetT, *s, evalCtx)
+}
+
+// jsonConsumer implements importRowConsumer interface.
+type jsonConsumer struct {
+	fieldNameToIdx map[string]int
+	// jsonColumnIdx is the index of the column into which the whole objects are
+	// imported, or -1.
+	jsonColumnIdx int
+	strict        bool
+}
+
+var _ importRowConsumer = &jsonConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (c *jsonConsumer) FillDatums(
+	native interface{}, rowNum int64, conv *row.DatumRowConverter,
+) error {
+	line := native.(string)
+	j, err := json.ParseJSON(line)
+	if err != nil {
+		return newImportRowError(err, line, rowNum)
+	}
+	if j.Type() != json.ObjectJSONType {
+		return newImportRowError(errors.New("expected a JSON object"), line, rowNum)
+	}
+	if c.jsonColumnIdx >= 0 {
+		conv.Datums[c.jsonColumnIdx] = tree.NewDJSON(j)
+	}
+
+	it, err := j.ObjectIter()
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		// A key maps to the column with the same name if there is one, or else
+		// to the column named like the key as an unquoted identifier.
+		field := it.Key()
+		idx, ok := c.fieldNameToIdx[field]
+		if !ok {
+			idx, ok = c.fieldNameToIdx[lexbase.NormalizeName(field)]
+		}
+		if !ok {
+			if c.strict && c.jsonColumnIdx < 0 {
+				return newImportRowError(
+					fmt.Errorf("could not find column for JSON key %s", field), line, rowNum)
+			}
+			continue
+		}
+		if idx == c.jsonColumnIdx {
+			continue
+		}
+		datum, err := jsonToDatum(it.Value(), conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			col := conv.VisibleCols[idx]
+			return newImportRowError(errors.Wrapf(
+				err,
+				"encountered error when attempting to parse %q as %s",
+				col.GetName(), col.GetType().SQLString(),
+			), line, rowNum)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// Set any nil datums to DNull (in case the object didn't have the key).
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if c.strict {
+				return newImportRowError(fmt.Errorf(
+					"field %s was not set in the JSON import", conv.VisibleCols[i].GetName()), line, rowNum)
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// jsonLineStream produces the lines of a newline-delimited JSON file,
+// skipping empty lines.
+type jsonLineStream struct {
+	input   *fileReader
+	scanner *bufio.Scanner
+	err     error
+}
+
+var _ importRowProducer = &jsonLineStream{}
+
+// Scan implements importRowProducer interface.
+func (p *jsonLineStream) Scan() bool {
+	for p.scanner.Scan() {
+		if len(bytes.TrimSpace(p.scanner.Bytes())) > 0 {
+			return true
+		}
+	}
+	p.err = p.scanner.Err()
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (p *jsonLineStream) Err() error {
+	return p.err
+}
+
+// Skip implements importRowProducer interface.
+func (p *jsonLineStream) Skip() error {
+	return nil // no-op
+}
+
+// Row implements importRowProducer interface.
+func (p *jsonLineStream) Row() (interface{}, error) {
+	// The line is copied since the scanner reuses its buffer.
+	return string(p.scanner.Bytes()), nil
+}
+
+// Progress implements importRowProducer interface.
+func (p *jsonLineStream) Progress() float32 {
+	return p.input.ReadFraction()
+}
+
+type jsonInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.JSONOptions
+	// jsonColumnIdx is the index of the visible column named by the
+	// json_column option, or -1.
+	jsonColumnIdx int
+}
+
+var _ inputConverter = &jsonInputReader{}
+
+func newJSONInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	jsonOpts roachpb.JSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) (*jsonInputReader, error) {
+	jsonColumnIdx := -1
+	if jsonOpts.JsonColumn != "" {
+		for idx, col := range tableDesc.VisibleColumns() {
+			if col.GetName() != jsonOpts.JsonColumn {
+				continue
+			}
+			if col.GetType().Family() != types.JsonFamily {
+				return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+					"%s column %q must be of type JSONB", jsonColumn, jsonOpts.JsonColumn)
+			}
+			jsonColumnIdx = idx
+		}
+		if jsonColumnIdx < 0 {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"%s column %q does not exist", jsonColumn, jsonOpts.JsonColumn)
+		}
+	}
+
+	return &jsonInputReader{
+		importContext: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts:          jsonOpts,
+		jsonColumnIdx: jsonColumnIdx,
+	}, nil
+}
+
+func (j *jsonInputReader) start(group ctxgroup.Group) {}
+
+func (j *jsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, j.readFile, makeExternalStorage, user)
+}
+
+func (j *jsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range j.importContext.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.GetName()] = idx
+	}
+	consumer := &jsonConsumer{
+		fieldNameToIdx: fieldIdxByName,
+		jsonColumnIdx:  j.jsonColumnIdx,
+		strict:         j.opts.StrictMode,
+	}
+
+	maxRowSize := int(j.opts.MaxRowSize)
+	if maxRowSize <= 0 {
+		maxRowSize = defaultScanBuffer
+	}
+	s := bufio.NewScanner(input)
+	s.Buffer(nil, maxRowSize)
+	producer := &jsonLineStream{input: input, scanner: s}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: j.opts.RowLimit,
+	}
+	return runParallelImport(ctx, j.importContext, fileCtx, producer, consumer)
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	const events = `{"id": 1, "Name": "a", "tags": ["x", "y"], "props": {"k": 1}, "extra": true}
+
+{"id": "2", "name": null, "ts": "2022-01-01 00:00:00", "amount": 1.25}
+{"id": 3, "amount": "2.5", "tags": "{z}"}
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "events.ndjson"), []byte(events), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad.ndjson"), []byte("[1, 2]\n"), 0644))
+	const mixedCase = `{"id": 1, "userId": "u1", "USERID": "u2", "Name": "a"}
+{"id": 2, "UserId": "u3"}
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "mixed.ndjson"), []byte(mixedCase), 0644))
+
+	tc := testcluster.StartTestCluster(
+		t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{ExternalIODir: dir}})
+	defer tc.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	const eventsURI = `'nodelocal://0/events.ndjson'`
+
+	t.Run("columns", func(t *testing.T) {
+		// Keys are mapped to the columns of the same name, and keys without a
+		// column are ignored.
+		db.Exec(t, `CREATE TABLE e (
+			id INT PRIMARY KEY, name STRING, tags STRING[], props JSONB, ts TIMESTAMP, amount DECIMAL
+		)`)
+		db.Exec(t, `IMPORT INTO e JSON DATA (`+eventsURI+`)`)
+		db.CheckQueryResults(t, `SELECT id, name, tags, props, ts::STRING, amount FROM e ORDER BY id`, [][]string{
+			{"1", "a", "{x,y}", `{"k": 1}`, "NULL", "NULL"},
+			{"2", "NULL", "NULL", "NULL", "2022-01-01 00:00:00", "1.25"},
+			{"3", "NULL", "{z}", "NULL", "NULL", "2.5"},
+		})
+	})
+
+	t.Run("mixed-case", func(t *testing.T) {
+		// Keys match quoted column names exactly, and otherwise match the
+		// column named like the key as an unquoted identifier.
+		db.Exec(t, `CREATE TABLE m (id INT PRIMARY KEY, "userId" STRING, userid STRING, name STRING)`)
+		db.Exec(t, `IMPORT INTO m JSON DATA ('nodelocal://0/mixed.ndjson')`)
+		db.CheckQueryResults(t, `SELECT id, "userId", userid, name FROM m ORDER BY id`, [][]string{
+			{"1", "u1", "u2", "a"},
+			{"2", "NULL", "u3", "NULL"},
+		})
+	})
+
+	t.Run("json-column", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE raw (id INT PRIMARY KEY, payload JSONB)`)
+		db.Exec(t, `IMPORT INTO raw JSON DATA (`+eventsURI+`) WITH json_column = 'payload'`)
+		db.CheckQueryResults(t, `SELECT id, payload->>'extra', payload->'id' FROM raw ORDER BY id`, [][]string{
+			{"1", "true", "1"},
+			{"2", "NULL", `"2"`},
+			{"3", "NULL", "3"},
+		})
+
+		db.Exec(t, `CREATE TABLE notjson (id INT PRIMARY KEY, payload STRING)`)
+		db.ExpectErr(t, `json_column column "payload" must be of type JSONB`,
+			`IMPORT INTO notjson JSON DATA (`+eventsURI+`) WITH json_column = 'payload'`)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE s (id INT PRIMARY KEY, name STRING)`)
+		db.ExpectErr(t, `could not find column for JSON key`,
+			`IMPORT INTO s JSON DATA (`+eventsURI+`) WITH strict_validation`)
+	})
+
+	t.Run("row-limit", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE l (id INT PRIMARY KEY)`)
+		db.Exec(t, `IMPORT INTO l JSON DATA (`+eventsURI+`) WITH row_limit = '2'`)
+		db.CheckQueryResults(t, `SELECT id FROM l ORDER BY id`, [][]string{{"1"}, {"2"}})
+	})
+
+	t.Run("not-an-object", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE b (id INT PRIMARY KEY)`)
+		db.ExpectErr(t, `expected a JSON object`, `IMPORT INTO b JSON DATA ('nodelocal://0/bad.ndjson')`)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		db.ExpectErr(t, `invalid option "delimiter"`,
+			`IMPORT INTO b JSON DATA (`+eventsURI+`) WITH delimiter = '|'`)
+	})
+}
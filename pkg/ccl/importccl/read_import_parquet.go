@@ -0,0 +1,800 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+)
+
+// parquetLogicalType is the logical type of a primitive parquet column, as
+// given by its logical type annotation, or by its legacy converted type.
+type parquetLogicalType int
+
+const (
+	parquetNone parquetLogicalType = iota
+	parquetString
+	parquetJSON
+	parquetUUID
+	parquetDate
+	parquetDecimal
+	parquetTime
+	parquetTimestamp
+	parquetTimestampTZ
+)
+
+// parquetLogicalTypeOf returns the logical type of a primitive column, and the
+// unit of its values for times and timestamps.
+func parquetLogicalTypeOf(el *parquet.SchemaElement) (parquetLogicalType, time.Duration) {
+	timeUnit := func(u *parquet.TimeUnit) time.Duration {
+		switch {
+		case u == nil:
+			return time.Millisecond
+		case u.IsSetMICROS():
+			return time.Microsecond
+		case u.IsSetNANOS():
+			return time.Nanosecond
+		default:
+			return time.Millisecond
+		}
+	}
+	if lt := el.LogicalType; lt != nil {
+		switch {
+		case lt.IsSetSTRING(), lt.IsSetENUM():
+			return parquetString, 0
+		case lt.IsSetJSON():
+			return parquetJSON, 0
+		case lt.IsSetUUID():
+			return parquetUUID, 0
+		case lt.IsSetDATE():
+			return parquetDate, 0
+		case lt.IsSetDECIMAL():
+			return parquetDecimal, 0
+		case lt.IsSetTIME():
+			return parquetTime, timeUnit(lt.TIME.Unit)
+		case lt.IsSetTIMESTAMP():
+			if lt.TIMESTAMP.IsAdjustedToUTC {
+				return parquetTimestampTZ, timeUnit(lt.TIMESTAMP.Unit)
+			}
+			return parquetTimestamp, timeUnit(lt.TIMESTAMP.Unit)
+		}
+	}
+	if el.ConvertedType != nil {
+		switch *el.ConvertedType {
+		case parquet.ConvertedType_UTF8, parquet.ConvertedType_ENUM:
+			return parquetString, 0
+		case parquet.ConvertedType_JSON:
+			return parquetJSON, 0
+		case parquet.ConvertedType_DATE:
+			return parquetDate, 0
+		case parquet.ConvertedType_DECIMAL:
+			return parquetDecimal, 0
+		case parquet.ConvertedType_TIME_MILLIS:
+			return parquetTime, time.Millisecond
+		case parquet.ConvertedType_TIME_MICROS:
+			return parquetTime, time.Microsecond
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			return parquetTimestampTZ, time.Millisecond
+		case parquet.ConvertedType_TIMESTAMP_MICROS:
+			return parquetTimestampTZ, time.Microsecond
+		}
+	}
+	return parquetNone, 0
+}
+
+// parquetDecimalScale returns the scale of a decimal column.
+func parquetDecimalScale(el *parquet.SchemaElement) int32 {
+	if lt := el.LogicalType; lt != nil && lt.IsSetDECIMAL() {
+		return lt.DECIMAL.Scale
+	}
+	return el.GetScale()
+}
+
+// julianDayOfUnixEpoch is the Julian day of 1970-01-01, used to decode the
+// legacy INT96 timestamps.
+const julianDayOfUnixEpoch = 2440588
+
+// parquetLeafToDatum converts a non-NULL value of a primitive parquet column,
+// as returned by the parquet reader, to the datum of the corresponding type.
+func parquetLeafToDatum(v interface{}, el *parquet.SchemaElement) (tree.Datum, error) {
+	logical, unit := parquetLogicalTypeOf(el)
+	switch x := v.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(x)), nil
+	case int32:
+		return parquetIntToDatum(int64(x), el, logical, unit)
+	case int64:
+		return parquetIntToDatum(x, el, logical, unit)
+	case float32:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case [12]byte:
+		// Legacy INT96 timestamps are made of the nanoseconds within the day, and
+		// of the Julian day.
+		nanos := int64(binary.LittleEndian.Uint64(x[:8]))
+		days := int64(binary.LittleEndian.Uint32(x[8:])) - julianDayOfUnixEpoch
+		t := timeutil.Unix(days*24*60*60, nanos)
+		return tree.MakeDTimestamp(t, time.Microsecond)
+	case []byte:
+		switch logical {
+		case parquetString:
+			return tree.NewDString(string(x)), nil
+		case parquetJSON:
+			j, err := json.ParseJSON(string(x))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDJSON(j), nil
+		case parquetUUID:
+			u, err := uuid.FromBytes(x)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+		case parquetDecimal:
+			// The unscaled value is a big-endian two's complement integer.
+			i := new(big.Int).SetBytes(x)
+			if len(x) > 0 && x[0]&0x80 != 0 {
+				i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(x))*8))
+			}
+			var coeff apd.BigInt
+			coeff.SetMathBigInt(i)
+			return &tree.DDecimal{Decimal: *apd.NewWithBigInt(&coeff, -parquetDecimalScale(el))}, nil
+		}
+		return tree.NewDBytes(tree.DBytes(x)), nil
+	}
+	return nil, errors.Errorf("cannot handle type %T of parquet column %s", v, el.Name)
+}
+
+func parquetIntToDatum(
+	i int64, el *parquet.SchemaElement, logical parquetLogicalType, unit time.Duration,
+) (tree.Datum, error) {
+	switch logical {
+	case parquetDate:
+		d, err := pgdate.MakeDateFromUnixEpoch(i)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(d), nil
+	case parquetDecimal:
+		return &tree.DDecimal{Decimal: *apd.New(i, -parquetDecimalScale(el))}, nil
+	case parquetTime:
+		micros := time.Duration(i) * unit / time.Microsecond
+		return tree.MakeDTime(timeofday.FromInt(int64(micros))), nil
+	case parquetTimestamp:
+		return tree.MakeDTimestamp(timeutil.Unix(0, i*int64(unit)), time.Microsecond)
+	case parquetTimestampTZ:
+		return tree.MakeDTimestampTZ(timeutil.Unix(0, i*int64(unit)), time.Microsecond)
+	}
+	return tree.NewDInt(tree.DInt(i)), nil
+}
+
+func parquetIsRepeated(def *parquetschema.ColumnDefinition) bool {
+	rep := def.SchemaElement.RepetitionType
+	return rep != nil && *rep == parquet.FieldRepetitionType_REPEATED
+}
+
+// parquetIsList returns whether a group column is annotated as a list, made of
+// a repeated field whose values are the elements of the list.
+func parquetIsList(def *parquetschema.ColumnDefinition) bool {
+	el := def.SchemaElement
+	annotated := (el.LogicalType != nil && el.LogicalType.IsSetLIST()) ||
+		(el.ConvertedType != nil && *el.ConvertedType == parquet.ConvertedType_LIST)
+	return annotated && len(def.Children) == 1 && parquetIsRepeated(def.Children[0])
+}
+
+// parquetIsMap returns whether a group column is annotated as a map, made of a
+// repeated group of keys and values.
+func parquetIsMap(def *parquetschema.ColumnDefinition) bool {
+	el := def.SchemaElement
+	annotated := (el.LogicalType != nil && el.LogicalType.IsSetMAP()) ||
+		(el.ConvertedType != nil && (*el.ConvertedType == parquet.ConvertedType_MAP ||
+			*el.ConvertedType == parquet.ConvertedType_MAP_KEY_VALUE))
+	return annotated && len(def.Children) == 1 && parquetIsRepeated(def.Children[0]) &&
+		len(def.Children[0].Children) == 2
+}
+
+// parquetRepeatedValues returns the values of a repeated field, which the
+// parquet reader returns as a slice.
+func parquetRepeatedValues(v interface{}) ([]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if values, ok := v.([]interface{}); ok {
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, errors.Errorf("unexpected type %T for repeated parquet field", v)
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// parquetListElements returns the elements of a value of a list column, and
+// the definition of the elements. Lists are made of a repeated group with a
+// single element field, or, in files written by legacy writers, of a repeated
+// field which is itself the element.
+func parquetListElements(
+	v interface{}, def *parquetschema.ColumnDefinition,
+) ([]interface{}, *parquetschema.ColumnDefinition, error) {
+	record, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.Errorf("unexpected type %T for parquet list %s", v, def.SchemaElement.Name)
+	}
+	repeated := def.Children[0]
+	values, err := parquetRepeatedValues(record[repeated.SchemaElement.Name])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(repeated.Children) != 1 {
+		return values, repeated, nil
+	}
+	elemDef := repeated.Children[0]
+	elems := make([]interface{}, len(values))
+	for i, value := range values {
+		if value, ok := value.(map[string]interface{}); ok {
+			elems[i] = value[elemDef.SchemaElement.Name]
+		}
+	}
+	return elems, elemDef, nil
+}
+
+// parquetToJSONValue converts the value of a parquet column, which may be
+// repeated or nested, to a value accepted by json.MakeJSON. Lists and repeated
+// fields become arrays, while maps and other groups become objects.
+func parquetToJSONValue(
+	v interface{}, def *parquetschema.ColumnDefinition, evalCtx *tree.EvalContext,
+) (interface{}, error) {
+	if v == nil || !parquetIsRepeated(def) {
+		return parquetElemToJSONValue(v, def, evalCtx)
+	}
+	values, err := parquetRepeatedValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return parquetElemsToJSONValue(values, def, evalCtx)
+}
+
+func parquetElemsToJSONValue(
+	elems []interface{}, def *parquetschema.ColumnDefinition, evalCtx *tree.EvalContext,
+) (interface{}, error) {
+	arr := make([]interface{}, len(elems))
+	for i, elem := range elems {
+		var err error
+		if arr[i], err = parquetElemToJSONValue(elem, def, evalCtx); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+// parquetElemToJSONValue is like parquetToJSONValue, for a single value of a
+// repeated field.
+func parquetElemToJSONValue(
+	v interface{}, def *parquetschema.ColumnDefinition, evalCtx *tree.EvalContext,
+) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if len(def.Children) == 0 {
+		d, err := parquetLeafToDatum(v, def.SchemaElement)
+		if err != nil {
+			return nil, err
+		}
+		return tree.AsJSON(d, evalCtx.SessionData().DataConversionConfig, evalCtx.GetLocation())
+	}
+	if parquetIsList(def) {
+		elems, elemDef, err := parquetListElements(v, def)
+		if err != nil {
+			return nil, err
+		}
+		return parquetElemsToJSONValue(elems, elemDef, evalCtx)
+	}
+	record, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("unexpected type %T for parquet group %s", v, def.SchemaElement.Name)
+	}
+	if parquetIsMap(def) {
+		entries, err := parquetRepeatedValues(record[def.Children[0].SchemaElement.Name])
+		if err != nil {
+			return nil, err
+		}
+		keyDef, valueDef := def.Children[0].Children[0], def.Children[0].Children[1]
+		obj := make(map[string]interface{}, len(entries))
+		for _, entry := range entries {
+			entry, ok := entry.(map[string]interface{})
+			if !ok || entry[keyDef.SchemaElement.Name] == nil {
+				continue
+			}
+			key, err := parquetLeafToDatum(entry[keyDef.SchemaElement.Name], keyDef.SchemaElement)
+			if err != nil {
+				return nil, err
+			}
+			value, err := parquetToJSONValue(entry[valueDef.SchemaElement.Name], valueDef, evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			obj[tree.AsStringWithFlags(key, tree.FmtBareStrings)] = value
+		}
+		return obj, nil
+	}
+	obj := make(map[string]interface{}, len(def.Children))
+	for _, child := range def.Children {
+		value, err := parquetToJSONValue(record[child.SchemaElement.Name], child, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		obj[child.SchemaElement.Name] = value
+	}
+	return obj, nil
+}
+
+// parquetToDatum converts the value of a top-level parquet column to a datum
+// of the type of the target column. Lists and repeated fields become arrays
+// if the target column is an array, and nested values are otherwise
+// converted to JSON. The datums are finally parsed or cast to the target type
+// if needed.
+func parquetToDatum(
+	v interface{}, def *parquetschema.ColumnDefinition, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if parquetIsRepeated(def) {
+		values, err := parquetRepeatedValues(v)
+		if err != nil {
+			return nil, err
+		}
+		return parquetElemsToDatum(values, def, targetT, evalCtx)
+	}
+	return parquetElemToDatum(v, def, targetT, evalCtx)
+}
+
+func parquetElemsToDatum(
+	elems []interface{},
+	def *parquetschema.ColumnDefinition,
+	targetT *types.T,
+	evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if targetT.Family() != types.ArrayFamily {
+		j, err := parquetElemsToJSONValue(elems, def, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		return parquetJSONToDatum(j, targetT, evalCtx)
+	}
+	arr := tree.NewDArray(targetT.ArrayContents())
+	for _, elem := range elems {
+		d, err := parquetElemToDatum(elem, def, targetT.ArrayContents(), evalCtx)
+		if err == nil {
+			err = arr.Append(d)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+func parquetElemToDatum(
+	v interface{}, def *parquetschema.ColumnDefinition, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if len(def.Children) > 0 {
+		if parquetIsList(def) {
+			elems, elemDef, err := parquetListElements(v, def)
+			if err != nil {
+				return nil, err
+			}
+			return parquetElemsToDatum(elems, elemDef, targetT, evalCtx)
+		}
+		j, err := parquetElemToJSONValue(v, def, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		return parquetJSONToDatum(j, targetT, evalCtx)
+	}
+
+	d, err := parquetLeafToDatum(v, def.SchemaElement)
+	if err != nil {
+		return nil, err
+	}
+	switch t := d.(type) {
+	case *tree.DString:
+		// Like for CSV, strings may be imported into columns of any type which
+		// they can be parsed as.
+		return rowenc.ParseDatumStringAs(targetT, string(*t), evalCtx)
+	case *tree.DBytes:
+		// Byte arrays without a logical type are often strings written by legacy
+		// writers.
+		if targetT.Family() != types.BytesFamily {
+			return rowenc.ParseDatumStringAs(targetT, string(*t), evalCtx)
+		}
+	}
+	return parquetCastDatum(d, targetT, evalCtx)
+}
+
+func parquetJSONToDatum(
+	v interface{}, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	j, err := json.MakeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return parquetCastDatum(tree.NewDJSON(j), targetT, evalCtx)
+}
+
+// parquetCastDatum casts the datum to the target type, if it isn't already of
+// that type.
+func parquetCastDatum(d tree.Datum, targetT *types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if targetT.Equivalent(d.ResolvedType()) {
+		return d, nil
+	}
+	res, err := tree.PerformCast(evalCtx, d, targetT)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot convert type %s to %s", d.ResolvedType(), targetT)
+	}
+	return res, nil
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	// fieldToIdx maps the top-level columns of the file to the indexes of the
+	// visible columns of the table they are imported into.
+	fieldToIdx map[string]int
+	// columns are the definitions of the top-level columns of the file.
+	columns map[string]*parquetschema.ColumnDefinition
+	// inFile are the indexes of the table columns which are in the file.
+	inFile map[int]struct{}
+	strict bool
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+func newParquetConsumer(
+	tableDesc catalog.TableDescriptor, schema *parquetschema.SchemaDefinition, strict bool,
+) (*parquetConsumer, error) {
+	colIdxByName := make(map[string]int)
+	for idx, col := range tableDesc.VisibleColumns() {
+		colIdxByName[col.GetName()] = idx
+	}
+	c := &parquetConsumer{
+		fieldToIdx: make(map[string]int),
+		columns:    make(map[string]*parquetschema.ColumnDefinition),
+		inFile:     make(map[int]struct{}),
+		strict:     strict,
+	}
+	for _, def := range schema.RootColumn.Children {
+		name := def.SchemaElement.Name
+		idx, ok := colIdxByName[lexbase.NormalizeName(name)]
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("could not find column for parquet field %s", name)
+			}
+			continue
+		}
+		c.fieldToIdx[name] = idx
+		c.columns[name] = def
+		c.inFile[idx] = struct{}{}
+	}
+	return c, nil
+}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected native type; expected map[string]interface{} found %T instead", native)
+	}
+	for f, v := range record {
+		idx, ok := p.fieldToIdx[f]
+		if !ok {
+			continue
+		}
+		datum, err := parquetToDatum(v, p.columns[f], conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "column %s", f)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// The reader omits NULL values of optional columns.
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if _, ok := p.inFile[i]; !ok && p.strict {
+				return fmt.Errorf("field %s was not set in the parquet import", conv.VisibleCols[i].GetName())
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// parquetRowGroupStream produces the rows of a set of row groups of a parquet
+// file.
+type parquetRowGroupStream struct {
+	reader    *goparquet.FileReader
+	rowGroups []int
+	// next is the index in rowGroups of the next row group to read, and
+	// remaining the number of rows left to read in the current row group.
+	next      int
+	remaining int64
+	numRows   int64
+	row       map[string]interface{}
+	err       error
+}
+
+var _ importRowProducer = &parquetRowGroupStream{}
+
+// Scan implements importRowProducer interface.
+func (p *parquetRowGroupStream) Scan() bool {
+	for p.remaining == 0 {
+		if p.next >= len(p.rowGroups) {
+			return false
+		}
+		if p.err = p.reader.SeekToRowGroup(p.rowGroups[p.next]); p.err != nil {
+			return false
+		}
+		p.next++
+		p.numRows = p.reader.CurrentRowGroup().NumRows
+		p.remaining = p.numRows
+	}
+	p.row, p.err = p.reader.NextRow()
+	if p.err != nil {
+		return false
+	}
+	p.remaining--
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (p *parquetRowGroupStream) Err() error {
+	return p.err
+}
+
+// Skip implements importRowProducer interface.
+func (p *parquetRowGroupStream) Skip() error {
+	p.row = nil
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (p *parquetRowGroupStream) Row() (interface{}, error) {
+	res := p.row
+	p.row = nil
+	return res, nil
+}
+
+// Progress implements importRowProducer interface.
+func (p *parquetRowGroupStream) Progress() float32 {
+	if len(p.rowGroups) == 0 {
+		return 0
+	}
+	done := float32(p.next)
+	if p.numRows > 0 {
+		done -= float32(p.remaining) / float32(p.numRows)
+	}
+	return done / float32(len(p.rowGroups))
+}
+
+// storageReadSeeker implements io.ReadSeeker over a file in external storage,
+// which the parquet reader needs to read the footer and then the column chunks
+// of the row groups. Reads following a seek reopen the file at the new
+// position.
+type storageReadSeeker struct {
+	ctx  context.Context
+	es   cloud.ExternalStorage
+	size int64
+	pos  int64
+
+	r    io.ReadCloser
+	rPos int64
+}
+
+var _ io.ReadSeeker = &storageReadSeeker{}
+
+// Read implements the io.Reader interface.
+func (s *storageReadSeeker) Read(p []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+	if s.r != nil && s.rPos != s.pos {
+		s.Close()
+	}
+	if s.r == nil {
+		r, _, err := s.es.ReadFileAt(s.ctx, "", s.pos)
+		if err != nil {
+			return 0, err
+		}
+		s.r, s.rPos = r, s.pos
+	}
+	n, err := s.r.Read(p)
+	s.pos += int64(n)
+	s.rPos = s.pos
+	return n, err
+}
+
+// Seek implements the io.Seeker interface.
+func (s *storageReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("negative position %d", offset)
+	}
+	s.pos = offset
+	return s.pos, nil
+}
+
+// Close closes the reader of the file, if it is open.
+func (s *storageReadSeeker) Close() {
+	if s.r != nil {
+		_ = s.r.Close()
+		s.r = nil
+	}
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	parquetOpts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) (*parquetInputReader, error) {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: parquetOpts,
+	}, nil
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+// readFiles implements the inputConverter interface. Parquet files can't be
+// streamed like the files of the other formats since their metadata is at
+// their end, so they are read through storageReadSeeker rather than with
+// readInputFiles.
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	done := ctx.Done()
+	for dataFileIndex, dataFile := range dataFiles {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+		if err := p.readFile(
+			ctx, dataFile, dataFileIndex, resumePos[dataFileIndex], makeExternalStorage, user,
+		); err != nil {
+			return errors.Wrapf(err, "%s", dataFile)
+		}
+	}
+	return nil
+}
+
+// rowGroups returns the row groups read by the given input: all of them,
+// unless the file is split into several inputs.
+func (p *parquetInputReader) rowGroups(inputIdx int32, numRowGroups int) []int {
+	slices := int(p.opts.RowGroupSlices)
+	if slices <= 1 {
+		slices = 1
+	}
+	var rowGroups []int
+	for rg := int(inputIdx) % slices; rg < numRowGroups; rg += slices {
+		rowGroups = append(rowGroups, rg)
+	}
+	return rowGroups
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context,
+	dataFile string,
+	inputIdx int32,
+	resumePos int64,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	conf, err := cloud.ExternalStorageConfFromURI(dataFile, user)
+	if err != nil {
+		return err
+	}
+	es, err := makeExternalStorage(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	size, err := es.Size(ctx, "")
+	if err != nil {
+		return err
+	}
+	input := &storageReadSeeker{ctx: ctx, es: es, size: size}
+	defer input.Close()
+
+	reader, err := goparquet.NewFileReader(input)
+	if err != nil {
+		return err
+	}
+	consumer, err := newParquetConsumer(
+		p.importContext.tableDesc, reader.GetSchemaDefinition(), p.opts.StrictMode)
+	if err != nil {
+		return err
+	}
+	producer := &parquetRowGroupStream{
+		reader:    reader,
+		rowGroups: p.rowGroups(inputIdx, reader.RowGroupCount()),
+	}
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rowLimit: p.opts.RowLimit,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
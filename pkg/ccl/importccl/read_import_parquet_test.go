@@ -0,0 +1,158 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/stretchr/testify/require"
+)
+
+const testParquetSchema = `message test {
+	required int64 id;
+	optional binary name (STRING);
+	optional int32 d (DATE);
+	optional int64 ts (TIMESTAMP(MICROS, true));
+	optional int64 amount (DECIMAL(10, 2));
+	optional group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+	optional group attrs (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			optional int64 value;
+		}
+	}
+	optional group point {
+		required double x;
+		required double y;
+	}
+}`
+
+// writeTestParquetFile writes a parquet file with the given number of rows,
+// and row groups of the given number of rows.
+func writeTestParquetFile(t *testing.T, path string, numRows, rowGroupSize int) {
+	schema, err := parquetschema.ParseSchemaDefinition(testParquetSchema)
+	require.NoError(t, err)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := goparquet.NewFileWriter(f, goparquet.WithSchemaDefinition(schema))
+	for i := 1; i <= numRows; i++ {
+		data := map[string]interface{}{
+			"id": int64(i),
+			// 2022-01-01 plus i days.
+			"d":      int32(18993 + i),
+			"ts":     int64(1640995200000000 + i),
+			"amount": int64(i * 100),
+			"tags": map[string]interface{}{"list": []map[string]interface{}{
+				{"element": []byte("x")},
+				{"element": []byte(fmt.Sprintf("y%d", i))},
+			}},
+			"attrs": map[string]interface{}{"key_value": []map[string]interface{}{
+				{"key": []byte("k"), "value": int64(i)},
+			}},
+			"point": map[string]interface{}{"x": 1.5, "y": float64(i)},
+		}
+		// Leave the name of every other row NULL.
+		if i%2 == 1 {
+			data["name"] = []byte(fmt.Sprintf("name%d", i))
+		}
+		require.NoError(t, w.AddData(data))
+		if i%rowGroupSize == 0 {
+			require.NoError(t, w.FlushRowGroup())
+		}
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const nodes = 3
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	writeTestParquetFile(t, filepath.Join(dir, "data.parquet"), 10 /* numRows */, 2 /* rowGroupSize */)
+
+	tc := testcluster.StartTestCluster(t, nodes, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{ExternalIODir: dir},
+	})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	t.Run("types", func(t *testing.T) {
+		// The row groups of the file are split across the processors of the
+		// three nodes.
+		sqlDB.Exec(t, `CREATE TABLE t (
+			id INT PRIMARY KEY, name STRING, d DATE, ts TIMESTAMPTZ, amount DECIMAL(10, 2),
+			tags STRING[], attrs JSONB, point JSONB
+		)`)
+		sqlDB.Exec(t, `IMPORT INTO t PARQUET DATA ('nodelocal://0/data.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), sum(id) FROM t`, [][]string{{"10", "55"}})
+		sqlDB.CheckQueryResults(t, `
+			SELECT id, name, d::STRING, ts = '2022-01-01 00:00:00+00'::TIMESTAMPTZ + id * '1us'::INTERVAL,
+				amount, tags, attrs, point
+			FROM t WHERE id IN (1, 2) ORDER BY id`, [][]string{
+			{"1", "name1", "2022-01-02", "true", "1.00", "{x,y1}", `{"k": 1}`, `{"x": 1.5, "y": 1}`},
+			{"2", "NULL", "2022-01-03", "true", "2.00", "{x,y2}", `{"k": 2}`, `{"x": 1.5, "y": 2}`},
+		})
+	})
+
+	t.Run("lists-to-json", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE j (id INT PRIMARY KEY, tags JSONB)`)
+		sqlDB.Exec(t, `IMPORT INTO j PARQUET DATA ('nodelocal://0/data.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT tags FROM j WHERE id = 3`, [][]string{{`["x", "y3"]`}})
+	})
+
+	t.Run("row-limit", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE l (id INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `IMPORT INTO l PARQUET DATA ('nodelocal://0/data.parquet') WITH row_limit = '3'`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM l`, [][]string{{"3"}})
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE s (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.ExpectErr(t, `could not find column for parquet field d`,
+			`IMPORT INTO s PARQUET DATA ('nodelocal://0/data.parquet') WITH strict_validation`)
+		sqlDB.Exec(t, `IMPORT INTO s PARQUET DATA ('nodelocal://0/data.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), count(name) FROM s`, [][]string{{"10", "5"}})
+	})
+}
+
+func TestParquetRowGroups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r := &parquetInputReader{}
+	require.Equal(t, []int{0, 1, 2, 3, 4}, r.rowGroups(7, 5))
+
+	r.opts = roachpb.ParquetOptions{RowGroupSlices: 3}
+	require.Equal(t, []int{0, 3}, r.rowGroups(3, 5))
+	require.Equal(t, []int{1, 4}, r.rowGroups(4, 5))
+	require.Equal(t, []int{2}, r.rowGroups(5, 5))
+	require.Empty(t, r.rowGroups(2, 2))
+}
@@ -28,7 +28,7 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-// defaultScanBuffer is the default max row size of the PGCOPY and PGDUMP
+// defaultScanBuffer is the default max row size of the PGCOPY, PGDUMP and JSON
 // scanner.
 const defaultScanBuffer = 1024 * 1024 * 4
 
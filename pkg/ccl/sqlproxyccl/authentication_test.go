@@ -38,7 +38,8 @@ func TestAuthenticateOK(t *testing.T) {
 		require.Equal(t, beMsg, &pgproto3.ReadyForQuery{})
 	}()
 
-	require.NoError(t, authenticate(srv, cli, nilThrottleHook))
+	_, err := authenticate(srv, cli, nil, nilThrottleHook)
+	require.NoError(t, err)
 }
 
 func TestAuthenticateClearText(t *testing.T) {
@@ -80,7 +81,37 @@ func TestAuthenticateClearText(t *testing.T) {
 		require.Equal(t, beMsg, &pgproto3.ReadyForQuery{})
 	}()
 
-	require.NoError(t, authenticate(srv, cli, nilThrottleHook))
+	_, err := authenticate(srv, cli, nil, nilThrottleHook)
+	require.NoError(t, err)
+}
+
+func TestAuthenticateBackendKeyData(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cli, srv := net.Pipe()
+	be := pgproto3.NewBackend(pgproto3.NewChunkReader(srv), srv)
+	fe := pgproto3.NewFrontend(pgproto3.NewChunkReader(cli), cli)
+
+	go func() {
+		err := be.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 2})
+		require.NoError(t, err)
+		beMsg, err := fe.Receive()
+		require.NoError(t, err)
+		// The client gets the BackendKeyData of the proxy.
+		require.Equal(t, beMsg, &pgproto3.BackendKeyData{ProcessID: 3, SecretKey: 4})
+
+		err = be.Send(&pgproto3.ReadyForQuery{})
+		require.NoError(t, err)
+		beMsg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, beMsg, &pgproto3.ReadyForQuery{})
+	}()
+
+	crdbBackendKeyData, err := authenticate(
+		srv, cli, &pgproto3.BackendKeyData{ProcessID: 3, SecretKey: 4}, nilThrottleHook,
+	)
+	require.NoError(t, err)
+	require.Equal(t, &pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 2}, crdbBackendKeyData)
 }
 
 func TestAuthenticateThrottled(t *testing.T) {
@@ -144,7 +175,7 @@ func TestAuthenticateThrottled(t *testing.T) {
 			go server(t, sqlServer, &pgproto3.AuthenticationOk{})
 			go client(t, sqlClient)
 
-			err := authenticate(proxyToClient, proxyToServer, func(status throttler.AttemptStatus) error {
+			_, err := authenticate(proxyToClient, proxyToServer, nil, func(status throttler.AttemptStatus) error {
 				require.Equal(t, throttler.AttemptOK, status)
 				return throttledError
 			})
@@ -172,7 +203,7 @@ func TestAuthenticateError(t *testing.T) {
 		require.Equal(t, beMsg, &pgproto3.ErrorResponse{Severity: "FATAL", Code: "foo"})
 	}()
 
-	err := authenticate(srv, cli, nilThrottleHook)
+	_, err := authenticate(srv, cli, nil, nilThrottleHook)
 	require.Error(t, err)
 	codeErr := (*codeError)(nil)
 	require.True(t, errors.As(err, &codeErr))
@@ -193,7 +224,7 @@ func TestAuthenticateUnexpectedMessage(t *testing.T) {
 		require.Error(t, err)
 	}()
 
-	err := authenticate(srv, cli, nilThrottleHook)
+	_, err := authenticate(srv, cli, nil, nilThrottleHook)
 
 	srv.Close()
 
@@ -0,0 +1,282 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgproto3/v2"
+)
+
+// connMigrationTimeout bounds the time spent transferring a connection, during
+// which messages of the client are held back by the proxy.
+const connMigrationTimeout = 15 * time.Second
+
+// serializeSessionQuery retrieves the state of the session and a token which
+// allows the proxy to authenticate a new session on behalf of the user.
+const serializeSessionQuery = "SELECT crdb_internal.serialize_session(), crdb_internal.create_session_revival_token()"
+
+// serverError is an ErrorResponse sent by a SQL pod. The connection to the pod
+// remains usable after such an error.
+type serverError struct {
+	code    string
+	message string
+}
+
+func (e *serverError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.message)
+}
+
+// maybeTransfer transfers the connection to another SQL pod if a transfer was
+// requested and the session is at a safe transfer point. It returns the reader
+// to use for the server connection, which is a new one if the transfer
+// succeeded. Failing to transfer leaves the session on its current pod, unless
+// the current connection was left in an unknown state, in which case an error
+// is returned and the client connection must be closed.
+//
+// The lock is only held to check and update the state of the forwarder, and
+// not while talking to the pods, so that cancel requests can be served during
+// the transfer. Messages of the client are held back in the meantime.
+func (f *forwarder) maybeTransfer(r *msgReader) (*msgReader, error) {
+	f.mu.Lock()
+	if !f.mu.transferRequested || !f.isSafeTransferPointLocked() || len(r.buf) > 0 {
+		f.mu.Unlock()
+		return r, nil
+	}
+	f.mu.transferRequested = false
+	f.mu.transferring = true
+	oldConn, oldAddr := f.mu.serverConn, f.mu.podAddr
+	f.mu.Unlock()
+
+	newConn, newAddr, newBackendKeyData, newReader, err := f.transfer(oldConn, oldAddr, r)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mu.transferring = false
+	f.mu.transferDone.Broadcast()
+	if err != nil || newConn == nil {
+		return r, err
+	}
+	if f.mu.closed {
+		// The forwarder was closed during the transfer.
+		_ = newConn.Close()
+		return r, newErrorf(codeBackendDisconnected, "transferring connection: forwarder closed")
+	}
+	f.mu.serverConn = newConn
+	f.mu.podAddr = newAddr
+	// Cancel requests of the client now go to the new pod.
+	f.mu.crdbBackendKeyData = newBackendKeyData
+	_ = oldConn.Close()
+	f.metrics.ConnMigrationSuccessCount.Inc(1)
+	log.Infof(f.ctx, "transferred connection from %s to %s", oldAddr, newAddr)
+	return newReader, nil
+}
+
+// transfer serializes the session of the connection to the SQL pod at oldAddr
+// and restores it on another pod. It returns the connection to the new pod,
+// or a nil connection if the session remains on its current pod. An error is
+// returned if the current connection was left in an unknown state.
+func (f *forwarder) transfer(
+	oldConn net.Conn, oldAddr string, r *msgReader,
+) (
+	_ net.Conn,
+	podAddr string,
+	crdbBackendKeyData *pgproto3.BackendKeyData,
+	_ *msgReader,
+	_ error,
+) {
+	if err := oldConn.SetDeadline(timeutil.Now().Add(connMigrationTimeout)); err != nil {
+		return nil, "", nil, nil, err
+	}
+	rows, err := runQuery(oldConn, r, serializeSessionQuery)
+	if err != nil {
+		if !errors.HasType(err, (*serverError)(nil)) {
+			f.metrics.ConnMigrationErrorFatalCount.Inc(1)
+			return nil, "", nil, nil, newErrorf(codeBackendDisconnected, "transferring connection: %v", err)
+		}
+		f.metrics.ConnMigrationErrorRecoverableCount.Inc(1)
+		log.Warningf(f.ctx, "unable to transfer connection from %s: %v", oldAddr, err)
+		return nil, "", nil, nil, oldConn.SetDeadline(time.Time{})
+	}
+	if err := oldConn.SetDeadline(time.Time{}); err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	newConn, newAddr, newBackendKeyData, newReader, err := f.restoreSession(rows)
+	if err != nil {
+		f.metrics.ConnMigrationErrorRecoverableCount.Inc(1)
+		log.Warningf(f.ctx, "unable to transfer connection from %s: %v", oldAddr, err)
+		return nil, "", nil, nil, nil
+	}
+	return newConn, newAddr, newBackendKeyData, newReader, nil
+}
+
+// restoreSession connects to a new SQL pod, and restores the session described
+// by the result of serializeSessionQuery. It returns the BackendKeyData sent by
+// the new pod along with the connection. The new connection is closed if an
+// error is returned.
+func (f *forwarder) restoreSession(
+	rows [][][]byte,
+) (
+	_ net.Conn,
+	podAddr string,
+	crdbBackendKeyData *pgproto3.BackendKeyData,
+	_ *msgReader,
+	retErr error,
+) {
+	if len(rows) != 1 || len(rows[0]) != 2 {
+		return nil, "", nil, nil, errors.Newf("unexpected result for session serialization: %d rows", len(rows))
+	}
+	state, err := decodeBytea(rows[0][0])
+	if err != nil {
+		return nil, "", nil, nil, errors.Wrap(err, "decoding session state")
+	}
+	token, err := decodeBytea(rows[0][1])
+	if err != nil {
+		return nil, "", nil, nil, errors.Wrap(err, "decoding session revival token")
+	}
+	var session sessiondatapb.MigratableSession
+	if err := protoutil.Unmarshal(state, &session); err != nil {
+		return nil, "", nil, nil, errors.Wrap(err, "decoding session state")
+	}
+
+	conn, podAddr, err := f.dialPod(f.ctx, token)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			_ = conn.Close()
+		}
+	}()
+	if err := conn.SetDeadline(timeutil.Now().Add(connMigrationTimeout)); err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	// Wait for the new session to be authenticated.
+	r := newMsgReader(conn)
+	if _, crdbBackendKeyData, err = readUntilReadyWithKeyData(r); err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	// Restore the session variables. Prepared statements are not part of
+	// the state restored by deserialize_session, and must be prepared again.
+	if _, err := runQuery(conn, r, fmt.Sprintf(
+		"SELECT crdb_internal.deserialize_session(decode('%s', 'hex'))", hex.EncodeToString(state),
+	)); err != nil {
+		return nil, "", nil, nil, errors.Wrap(err, "restoring session")
+	}
+	if len(session.PreparedStatements) > 0 {
+		var buf []byte
+		for _, stmt := range session.PreparedStatements {
+			parse := &pgproto3.Parse{
+				Name:          stmt.Name,
+				Query:         stmt.SQL,
+				ParameterOIDs: make([]uint32, len(stmt.PlaceholderTypeHints)),
+			}
+			for i, typ := range stmt.PlaceholderTypeHints {
+				parse.ParameterOIDs[i] = uint32(typ)
+			}
+			buf = parse.Encode(buf)
+		}
+		buf = (&pgproto3.Sync{}).Encode(buf)
+		if _, err := conn.Write(buf); err != nil {
+			return nil, "", nil, nil, err
+		}
+		if _, err := readUntilReady(r); err != nil {
+			return nil, "", nil, nil, errors.Wrap(err, "restoring prepared statements")
+		}
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, "", nil, nil, err
+	}
+	return conn, podAddr, crdbBackendKeyData, r, nil
+}
+
+// runQuery runs the given query using the simple query protocol, and returns
+// the rows of the result in the text format.
+func runQuery(conn net.Conn, r *msgReader, query string) ([][][]byte, error) {
+	if _, err := conn.Write((&pgproto3.Query{String: query}).Encode(nil)); err != nil {
+		return nil, err
+	}
+	return readUntilReady(r)
+}
+
+// readUntilReady reads messages until the server sends ReadyForQuery, and
+// returns the data rows received in the meantime. It returns a *serverError if
+// the server reported an error.
+func readUntilReady(r *msgReader) ([][][]byte, error) {
+	rows, _, err := readUntilReadyWithKeyData(r)
+	return rows, err
+}
+
+// readUntilReadyWithKeyData is like readUntilReady, but also returns the
+// BackendKeyData sent by the server, or nil if it did not send any.
+func readUntilReadyWithKeyData(r *msgReader) ([][][]byte, *pgproto3.BackendKeyData, error) {
+	var rows [][][]byte
+	var keyData *pgproto3.BackendKeyData
+	var retErr error
+	for {
+		msg, err := r.readMsg()
+		if err != nil {
+			return nil, nil, err
+		}
+		body := msg[pgHeaderSize:]
+		switch msg[0] {
+		case pgServerReadyForQuery:
+			return rows, keyData, retErr
+		case pgServerAuthentication:
+			var authOK pgproto3.AuthenticationOk
+			if err := authOK.Decode(body); err != nil {
+				return nil, nil, errors.Wrap(err, "unexpected authentication request")
+			}
+		case pgServerBackendKeyData:
+			keyData = &pgproto3.BackendKeyData{}
+			if err := keyData.Decode(body); err != nil {
+				return nil, nil, err
+			}
+		case pgServerDataRow:
+			var dataRow pgproto3.DataRow
+			if err := dataRow.Decode(body); err != nil {
+				return nil, nil, err
+			}
+			// The values reference the buffer of the reader, so they are copied.
+			row := make([][]byte, len(dataRow.Values))
+			for i, v := range dataRow.Values {
+				row[i] = append([]byte(nil), v...)
+			}
+			rows = append(rows, row)
+		case pgServerErrorResponse:
+			var errResp pgproto3.ErrorResponse
+			if err := errResp.Decode(body); err != nil {
+				return nil, nil, err
+			}
+			retErr = &serverError{code: errResp.Code, message: errResp.Message}
+		}
+	}
+}
+
+// decodeBytea decodes a BYTES value in the text format of pgwire.
+func decodeBytea(val []byte) ([]byte, error) {
+	s := string(val)
+	if !strings.HasPrefix(s, `\x`) {
+		return nil, errors.New("unsupported encoding for bytes value")
+	}
+	return hex.DecodeString(s[2:])
+}
@@ -0,0 +1,396 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgproto3/v2"
+)
+
+// pgwire message types which are inspected by the forwarder.
+const (
+	// Messages sent by the client.
+	pgClientQuery    = 'Q'
+	pgClientSync     = 'S'
+	pgClientParse    = 'P'
+	pgClientBind     = 'B'
+	pgClientExecute  = 'E'
+	pgClientDescribe = 'D'
+	pgClientClose    = 'C'
+
+	// Messages sent by the server.
+	pgServerAuthentication = 'R'
+	pgServerReadyForQuery  = 'Z'
+	pgServerErrorResponse  = 'E'
+	pgServerDataRow        = 'D'
+	pgServerBackendKeyData = 'K'
+
+	// pgHeaderSize is the size of the header of a pgwire message, which is made
+	// of a one byte message type and a four byte message length.
+	pgHeaderSize = 5
+
+	// pgTxnStatusIdle is the transaction status reported by ReadyForQuery when
+	// the session is not in a transaction block.
+	pgTxnStatusIdle = 'I'
+)
+
+// forwarder forwards pgwire messages between a client connection and the
+// connection to the SQL pod which currently serves that client. Unlike
+// ConnectionCopy, it keeps track of the protocol state of the session, which
+// allows the connection to the SQL pod to be transferred to another pod of the
+// same tenant in between transactions, without the client noticing.
+type forwarder struct {
+	// ctx is the context of the client connection.
+	ctx context.Context
+
+	// clientConn is the connection to the client. It never changes.
+	clientConn net.Conn
+
+	// dialPod connects to a SQL pod of the tenant, authenticating using the
+	// given session revival token. It returns the connection and the address
+	// of the pod. It is used when transferring the connection.
+	dialPod func(ctx context.Context, revivalToken []byte) (net.Conn, string, error)
+
+	// metrics contains the connection migration counters.
+	metrics *metrics
+
+	mu struct {
+		syncutil.Mutex
+
+		// serverConn is the connection to the SQL pod currently serving the
+		// client. It is replaced when the connection is transferred.
+		serverConn net.Conn
+
+		// podAddr is the address of the SQL pod serving the client.
+		podAddr string
+
+		// crdbBackendKeyData is the BackendKeyData sent by the SQL pod serving
+		// the client, which is needed to cancel its queries. It is nil if the
+		// pod did not send any.
+		crdbBackendKeyData *pgproto3.BackendKeyData
+
+		// pendingSyncs is the number of Query and Sync messages sent by the
+		// client for which the server has not yet replied with ReadyForQuery.
+		pendingSyncs int
+
+		// needSync is true if the client sent messages of the extended query
+		// protocol which have not yet been followed by a Sync.
+		needSync bool
+
+		// txnStatus is the transaction status reported by the last
+		// ReadyForQuery message of the server.
+		txnStatus byte
+
+		// transferRequested is true if the connection should be transferred to
+		// another SQL pod at the next safe transfer point.
+		transferRequested bool
+
+		// transferring is true while the connection is being transferred. The
+		// messages of the client are held back until it is done, which is
+		// signaled through transferDone.
+		transferring bool
+		transferDone *sync.Cond
+
+		// closed is true once the forwarder is closed.
+		closed bool
+	}
+}
+
+// newForwarder creates a forwarder for a connection which was authenticated
+// against the SQL pod at podAddr, which sent crdbBackendKeyData.
+func newForwarder(
+	ctx context.Context,
+	clientConn, serverConn net.Conn,
+	podAddr string,
+	crdbBackendKeyData *pgproto3.BackendKeyData,
+	dialPod func(ctx context.Context, revivalToken []byte) (net.Conn, string, error),
+	metrics *metrics,
+) *forwarder {
+	f := &forwarder{
+		ctx:        ctx,
+		clientConn: clientConn,
+		dialPod:    dialPod,
+		metrics:    metrics,
+	}
+	f.mu.transferDone = sync.NewCond(&f.mu.Mutex)
+	f.mu.serverConn = serverConn
+	f.mu.podAddr = podAddr
+	f.mu.crdbBackendKeyData = crdbBackendKeyData
+	// Authentication ends with a ReadyForQuery message outside of any
+	// transaction.
+	f.mu.txnStatus = pgTxnStatusIdle
+	return f
+}
+
+// run forwards messages in both directions until either connection
+// terminates. Its error handling mirrors ConnectionCopy.
+func (f *forwarder) run() error {
+	errOutgoing := make(chan error, 1)
+	errIncoming := make(chan error, 1)
+
+	go func() {
+		errOutgoing <- f.forwardClientToServer()
+	}()
+	go func() {
+		errIncoming <- f.forwardServerToClient()
+	}()
+
+	select {
+	case err := <-errIncoming:
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil
+		} else if codeErr := (*codeError)(nil); errors.As(err, &codeErr) {
+			return codeErr
+		} else if ne := (net.Error)(nil); errors.As(err, &ne) && ne.Timeout() {
+			return newErrorf(codeIdleDisconnect, "terminating connection due to idle timeout: %v", err)
+		} else {
+			return newErrorf(codeBackendDisconnected, "copying from target server to client: %s", err)
+		}
+	case err := <-errOutgoing:
+		// The incoming connection got closed.
+		if err != nil && !errors.Is(err, io.EOF) {
+			return newErrorf(codeClientDisconnected, "copying from target server to client: %v", err)
+		}
+		return nil
+	}
+}
+
+// Close closes the connection to the SQL pod currently serving the client.
+func (f *forwarder) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mu.closed = true
+	_ = f.mu.serverConn.Close()
+}
+
+// PodAddr returns the address of the SQL pod currently serving the client.
+func (f *forwarder) PodAddr() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mu.podAddr
+}
+
+// CancelTarget returns the address of the SQL pod currently serving the
+// client, and the BackendKeyData to use to cancel its queries, or nil if the
+// pod did not send any.
+func (f *forwarder) CancelTarget() (podAddr string, crdbBackendKeyData *pgproto3.BackendKeyData) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mu.podAddr, f.mu.crdbBackendKeyData
+}
+
+// RequestTransfer asks for the connection to be transferred to another SQL
+// pod. The transfer happens asynchronously, as soon as the session reaches a
+// safe transfer point. A failed transfer is not retried unless it is
+// requested again.
+func (f *forwarder) RequestTransfer() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mu.transferRequested = true
+	// Interrupt any pending read on the server connection, so that an idle
+	// session gets transferred right away. The reads of a transfer in progress
+	// are not interrupted.
+	if !f.mu.transferring {
+		_ = f.mu.serverConn.SetReadDeadline(timeutil.Now())
+	}
+}
+
+// forwardClientToServer forwards the messages of the client to the server,
+// keeping track of the messages which require a reply from the server.
+func (f *forwarder) forwardClientToServer() error {
+	r := newMsgReader(f.clientConn)
+	for {
+		msg, err := r.readMsg()
+		if err != nil {
+			return err
+		}
+		// Messages are not sent in the middle of a transfer, and holding the
+		// lock while writing prevents them from being sent to a stale server
+		// connection.
+		f.mu.Lock()
+		for f.mu.transferring {
+			f.mu.transferDone.Wait()
+		}
+		f.onClientMsgLocked(msg[0])
+		_, err = f.mu.serverConn.Write(msg)
+		f.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// forwardServerToClient forwards the messages of the server to the client,
+// and transfers the connection when requested and safe to do so.
+func (f *forwarder) forwardServerToClient() error {
+	f.mu.Lock()
+	r := newMsgReader(f.mu.serverConn)
+	f.mu.Unlock()
+
+	w := bufio.NewWriterSize(f.clientConn, 16<<10)
+	for {
+		msg, err := r.readMsg()
+		if err != nil {
+			if ne := (net.Error)(nil); !errors.As(err, &ne) || !ne.Timeout() {
+				return err
+			}
+			// The read was interrupted by RequestTransfer.
+			if r, err = f.onReadInterrupted(r); err != nil {
+				return err
+			}
+			continue
+		}
+
+		f.mu.Lock()
+		f.onServerMsgLocked(msg)
+		f.mu.Unlock()
+
+		if _, err := w.Write(msg); err != nil {
+			return err
+		}
+		// Only flush once there are no more complete messages to forward, in
+		// order to batch writes of large result sets. ReadyForQuery is always
+		// flushed since the connection may be transferred right after it.
+		if msg[0] == pgServerReadyForQuery || !r.hasBufferedMsg() {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if msg[0] == pgServerReadyForQuery {
+			if r, err = f.maybeTransfer(r); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// onReadInterrupted is called when a read on the server connection times out
+// because of RequestTransfer. It clears the deadline and attempts the
+// transfer. It returns the reader to use for the server connection.
+func (f *forwarder) onReadInterrupted(r *msgReader) (*msgReader, error) {
+	f.mu.Lock()
+	err := f.mu.serverConn.SetReadDeadline(time.Time{})
+	f.mu.Unlock()
+	if err != nil {
+		return r, err
+	}
+	return f.maybeTransfer(r)
+}
+
+// onClientMsgLocked updates the protocol state for a message of the given type
+// sent by the client.
+func (f *forwarder) onClientMsgLocked(typ byte) {
+	switch typ {
+	case pgClientQuery, pgClientSync:
+		// Each of these messages results in a ReadyForQuery message.
+		f.mu.pendingSyncs++
+		f.mu.needSync = false
+	case pgClientParse, pgClientBind, pgClientExecute, pgClientDescribe, pgClientClose:
+		f.mu.needSync = true
+	}
+}
+
+// onServerMsgLocked updates the protocol state for a message sent by the
+// server.
+func (f *forwarder) onServerMsgLocked(msg []byte) {
+	if msg[0] != pgServerReadyForQuery || len(msg) <= pgHeaderSize {
+		return
+	}
+	if f.mu.pendingSyncs > 0 {
+		f.mu.pendingSyncs--
+	}
+	f.mu.txnStatus = msg[pgHeaderSize]
+}
+
+// isSafeTransferPointLocked returns true if the server has replied to every
+// message of the client, and the session is not in a transaction. At that
+// point, the session state can be fully serialized.
+func (f *forwarder) isSafeTransferPointLocked() bool {
+	return f.mu.pendingSyncs == 0 && !f.mu.needSync && f.mu.txnStatus == pgTxnStatusIdle
+}
+
+// msgReader reads pgwire messages from a connection. Unlike pgproto3, it
+// retains partially read messages when a read fails because of a deadline, so
+// the read can be resumed afterwards.
+type msgReader struct {
+	conn net.Conn
+	buf  []byte
+}
+
+// minReadSize is the minimum amount of space available in the buffer of a
+// msgReader when reading from its connection.
+const minReadSize = 8 << 10
+
+func newMsgReader(conn net.Conn) *msgReader {
+	return &msgReader{conn: conn}
+}
+
+// readMsg returns the next message, including its header. The returned slice
+// is only valid until the next call to readMsg.
+func (r *msgReader) readMsg() ([]byte, error) {
+	for {
+		if len(r.buf) >= pgHeaderSize && binary.BigEndian.Uint32(r.buf[1:pgHeaderSize]) < 4 {
+			return nil, errors.Newf("invalid length for pgwire message of type %q", r.buf[0])
+		}
+		if size, ok := r.bufferedMsgSize(); ok {
+			msg := r.buf[:size:size]
+			r.buf = r.buf[size:]
+			return msg, nil
+		}
+		if cap(r.buf)-len(r.buf) < minReadSize {
+			newCap := 2 * cap(r.buf)
+			if newCap < len(r.buf)+minReadSize {
+				newCap = len(r.buf) + minReadSize
+			}
+			newBuf := make([]byte, len(r.buf), newCap)
+			copy(newBuf, r.buf)
+			r.buf = newBuf
+		}
+		n, err := r.conn.Read(r.buf[len(r.buf):cap(r.buf)])
+		r.buf = r.buf[:len(r.buf)+n]
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(r.buf) > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+}
+
+// hasBufferedMsg returns true if the next call to readMsg will not need to
+// read from the connection.
+func (r *msgReader) hasBufferedMsg() bool {
+	_, ok := r.bufferedMsgSize()
+	return ok
+}
+
+// bufferedMsgSize returns the size of the next message, if the buffer
+// contains the full message.
+func (r *msgReader) bufferedMsgSize() (int, bool) {
+	if len(r.buf) < pgHeaderSize {
+		return 0, false
+	}
+	// The length includes itself, but not the message type.
+	size := 1 + int(binary.BigEndian.Uint32(r.buf[1:pgHeaderSize]))
+	if len(r.buf) < size {
+		return 0, false
+	}
+	return size, true
+}
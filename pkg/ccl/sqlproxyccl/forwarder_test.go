@@ -0,0 +1,347 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwarderSafeTransferPoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	f := newForwarder(context.Background(), nil, nil, "pod", nil, nil, nil)
+	readyForQuery := func(txnStatus byte) []byte {
+		return (&pgproto3.ReadyForQuery{TxStatus: txnStatus}).Encode(nil)
+	}
+	require.True(t, f.isSafeTransferPointLocked())
+
+	// Simple query protocol.
+	f.onClientMsgLocked(pgClientQuery)
+	require.False(t, f.isSafeTransferPointLocked())
+	f.onServerMsgLocked(readyForQuery('I'))
+	require.True(t, f.isSafeTransferPointLocked())
+
+	// Extended query protocol, with pipelined Syncs.
+	f.onClientMsgLocked(pgClientParse)
+	f.onClientMsgLocked(pgClientBind)
+	require.False(t, f.isSafeTransferPointLocked())
+	f.onClientMsgLocked(pgClientSync)
+	f.onClientMsgLocked(pgClientExecute)
+	f.onClientMsgLocked(pgClientSync)
+	f.onServerMsgLocked(readyForQuery('I'))
+	require.False(t, f.isSafeTransferPointLocked())
+	f.onServerMsgLocked(readyForQuery('I'))
+	require.True(t, f.isSafeTransferPointLocked())
+
+	// Explicit transactions.
+	f.onClientMsgLocked(pgClientQuery)
+	f.onServerMsgLocked(readyForQuery('T'))
+	require.False(t, f.isSafeTransferPointLocked())
+	f.onClientMsgLocked(pgClientQuery)
+	f.onServerMsgLocked(readyForQuery('E'))
+	require.False(t, f.isSafeTransferPointLocked())
+	f.onClientMsgLocked(pgClientQuery)
+	f.onServerMsgLocked(readyForQuery('I'))
+	require.True(t, f.isSafeTransferPointLocked())
+}
+
+func TestForwarderCancelRequest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	handler := &proxyHandler{}
+	handler.forwarders.m = make(map[*forwarder]struct{})
+	handler.forwarders.byBackendKeyData = make(map[pgproto3.BackendKeyData]*forwarder)
+
+	type cancel struct {
+		podAddr            string
+		crdbBackendKeyData pgproto3.BackendKeyData
+	}
+	var sent []cancel
+	defer testutils.TestingHook(&sendCancelRequest,
+		func(podAddr string, crdbBackendKeyData *pgproto3.BackendKeyData) error {
+			sent = append(sent, cancel{podAddr, *crdbBackendKeyData})
+			return nil
+		})()
+
+	proxyBackendKeyData, err := handler.reserveBackendKeyData()
+	require.NoError(t, err)
+	f := newForwarder(
+		context.Background(), nil, nil, "pod1",
+		&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 10}, nil, nil,
+	)
+	handler.addForwarder(f, proxyBackendKeyData)
+
+	ctx := context.Background()
+	cancelRequest := &pgproto3.CancelRequest{
+		ProcessID: proxyBackendKeyData.ProcessID,
+		SecretKey: proxyBackendKeyData.SecretKey,
+	}
+	handler.handleCancelRequest(ctx, cancelRequest)
+	require.Equal(t, []cancel{{"pod1", pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 10}}}, sent)
+
+	// Once the connection is transferred, the same request of the client is
+	// forwarded to the new pod.
+	f.mu.Lock()
+	f.mu.podAddr = "pod2"
+	f.mu.crdbBackendKeyData = &pgproto3.BackendKeyData{ProcessID: 2, SecretKey: 20}
+	f.mu.Unlock()
+	sent = nil
+	handler.handleCancelRequest(ctx, cancelRequest)
+	require.Equal(t, []cancel{{"pod2", pgproto3.BackendKeyData{ProcessID: 2, SecretKey: 20}}}, sent)
+
+	// Requests with an unknown key are ignored.
+	sent = nil
+	handler.handleCancelRequest(ctx, &pgproto3.CancelRequest{
+		ProcessID: cancelRequest.ProcessID, SecretKey: cancelRequest.SecretKey + 1,
+	})
+	require.Empty(t, sent)
+
+	// The key can no longer be used once the connection is closed.
+	handler.removeForwarder(f)
+	handler.releaseBackendKeyData(proxyBackendKeyData)
+	handler.handleCancelRequest(ctx, cancelRequest)
+	require.Empty(t, sent)
+}
+
+func TestMsgReaderResumesAfterTimeout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cli, srv := net.Pipe()
+	defer func() { _ = cli.Close() }()
+	defer func() { _ = srv.Close() }()
+
+	msg := (&pgproto3.Query{String: "SELECT 1"}).Encode(nil)
+	r := newMsgReader(srv)
+
+	// Write the first half of the message, and interrupt the read.
+	go func() { _, _ = cli.Write(msg[:4]) }()
+	require.NoError(t, srv.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err := r.readMsg()
+	var ne net.Error
+	require.True(t, errors.As(err, &ne) && ne.Timeout(), "unexpected error: %v", err)
+	require.NoError(t, srv.SetReadDeadline(time.Time{}))
+
+	// The partially read message is retained.
+	go func() { _, _ = cli.Write(msg[4:]) }()
+	res, err := r.readMsg()
+	require.NoError(t, err)
+	require.Equal(t, msg, res)
+	require.False(t, r.hasBufferedMsg())
+}
+
+func TestForwarderTransfer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	session := sessiondatapb.MigratableSession{
+		PreparedStatements: []sessiondatapb.MigratableSession_PreparedStatement{{
+			Name:                 "stmt",
+			PlaceholderTypeHints: []oid.Oid{oid.T_int8},
+			SQL:                  "SELECT $1",
+		}},
+	}
+	state, err := protoutil.Marshal(&session)
+	require.NoError(t, err)
+	token := []byte("token")
+	encodeBytes := func(b []byte) []byte {
+		return []byte(`\x` + hex.EncodeToString(b))
+	}
+
+	clientConn, proxyClientConn := net.Pipe()
+	pod1Conn, proxyPod1Conn := net.Pipe()
+	pod2Conn, proxyPod2Conn := net.Pipe()
+	defer func() { _ = clientConn.Close() }()
+	defer func() { _ = pod1Conn.Close() }()
+	defer func() { _ = pod2Conn.Close() }()
+
+	// receive reads a message sent by the proxy to a pod, and checks that it
+	// is as expected.
+	receive := func(be *pgproto3.Backend, expected pgproto3.FrontendMessage) error {
+		msg, err := be.Receive()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(expected.Encode(nil), msg.Encode(nil)) {
+			return errors.Newf("expected %#v, got %#v", expected, msg)
+		}
+		return nil
+	}
+	send := func(be *pgproto3.Backend, msgs ...pgproto3.BackendMessage) error {
+		for _, msg := range msgs {
+			if err := be.Send(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The first pod serves a query, then serializes the session.
+	pod1Err := make(chan error, 1)
+	go func() {
+		pod1Err <- func() error {
+			be := pgproto3.NewBackend(pgproto3.NewChunkReader(pod1Conn), pod1Conn)
+			if err := receive(be, &pgproto3.Query{String: "SELECT 1"}); err != nil {
+				return err
+			}
+			if err := send(be,
+				&pgproto3.DataRow{Values: [][]byte{[]byte("1")}},
+				&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			); err != nil {
+				return err
+			}
+			if err := receive(be, &pgproto3.Query{String: serializeSessionQuery}); err != nil {
+				return err
+			}
+			if err := send(be,
+				&pgproto3.DataRow{Values: [][]byte{encodeBytes(state), encodeBytes(token)}},
+				&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			); err != nil {
+				return err
+			}
+			// The proxy closes the connection once the transfer is done.
+			if _, err := be.Receive(); err == nil {
+				return errors.New("expected connection to be closed")
+			}
+			return nil
+		}()
+	}()
+
+	// The second pod restores the session, then serves a query.
+	pod2Err := make(chan error, 1)
+	go func() {
+		pod2Err <- func() error {
+			be := pgproto3.NewBackend(pgproto3.NewChunkReader(pod2Conn), pod2Conn)
+			if err := send(be,
+				&pgproto3.AuthenticationOk{},
+				&pgproto3.ParameterStatus{Name: "application_name", Value: "test"},
+				&pgproto3.BackendKeyData{ProcessID: 2, SecretKey: 20},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			); err != nil {
+				return err
+			}
+			if err := receive(be, &pgproto3.Query{String: fmt.Sprintf(
+				"SELECT crdb_internal.deserialize_session(decode('%s', 'hex'))", hex.EncodeToString(state),
+			)}); err != nil {
+				return err
+			}
+			if err := send(be,
+				&pgproto3.DataRow{Values: [][]byte{[]byte("t")}},
+				&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			); err != nil {
+				return err
+			}
+			if err := receive(be, &pgproto3.Parse{
+				Name: "stmt", Query: "SELECT $1", ParameterOIDs: []uint32{uint32(oid.T_int8)},
+			}); err != nil {
+				return err
+			}
+			if err := receive(be, &pgproto3.Sync{}); err != nil {
+				return err
+			}
+			if err := send(be,
+				&pgproto3.ParseComplete{},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			); err != nil {
+				return err
+			}
+			if err := receive(be, &pgproto3.Query{String: "SELECT 2"}); err != nil {
+				return err
+			}
+			return send(be,
+				&pgproto3.DataRow{Values: [][]byte{[]byte("2")}},
+				&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'},
+			)
+		}()
+	}()
+
+	// Dialing the second pod blocks until the test lets it proceed.
+	dialStarted := make(chan struct{})
+	unblockDial := make(chan struct{})
+	dialPod := func(ctx context.Context, revivalToken []byte) (net.Conn, string, error) {
+		close(dialStarted)
+		<-unblockDial
+		if string(revivalToken) != string(token) {
+			return nil, "", errors.Newf("unexpected token %q", revivalToken)
+		}
+		return proxyPod2Conn, "pod2", nil
+	}
+	m := makeProxyMetrics()
+	f := newForwarder(
+		context.Background(), proxyClientConn, proxyPod1Conn, "pod1",
+		&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 10}, dialPod, &m,
+	)
+	defer f.Close()
+	runErr := make(chan error, 1)
+	go func() { runErr <- f.run() }()
+
+	fe := pgproto3.NewFrontend(pgproto3.NewChunkReader(clientConn), clientConn)
+	query := func(sql string, expected string) {
+		require.NoError(t, fe.Send(&pgproto3.Query{String: sql}))
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.DataRow{Values: [][]byte{[]byte(expected)}}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.IsType(t, &pgproto3.CommandComplete{}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.ReadyForQuery{TxStatus: 'I'}, msg)
+	}
+
+	query("SELECT 1", "1")
+	require.Equal(t, "pod1", f.PodAddr())
+	podAddr, crdbBackendKeyData := f.CancelTarget()
+	require.Equal(t, "pod1", podAddr)
+	require.Equal(t, &pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 10}, crdbBackendKeyData)
+
+	// The session is idle, so it gets transferred right away.
+	f.RequestTransfer()
+	<-dialStarted
+	// The lock of the forwarder is not held while the new pod is dialed, so
+	// cancel requests still go to the old pod in the meantime.
+	podAddr, crdbBackendKeyData = f.CancelTarget()
+	require.Equal(t, "pod1", podAddr)
+	require.Equal(t, &pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 10}, crdbBackendKeyData)
+	close(unblockDial)
+	require.NoError(t, <-pod1Err)
+	require.Eventually(t, func() bool {
+		return f.PodAddr() == "pod2"
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(1), m.ConnMigrationSuccessCount.Count())
+
+	// Cancel requests now go to the new pod, with its BackendKeyData.
+	podAddr, crdbBackendKeyData = f.CancelTarget()
+	require.Equal(t, "pod2", podAddr)
+	require.Equal(t, &pgproto3.BackendKeyData{ProcessID: 2, SecretKey: 20}, crdbBackendKeyData)
+
+	query("SELECT 2", "2")
+	require.NoError(t, <-pod2Err)
+
+	// Closing the client connection terminates the forwarder.
+	require.NoError(t, clientConn.Close())
+	require.NoError(t, <-runErr)
+}
@@ -20,10 +20,11 @@ import (
 // message received from the PG SQL client. The connection returned should never
 // be nil in case of error. Depending on whether the error happened before the
 // connection was upgraded to TLS or not it will either be the original or the
-// TLS connection.
+// TLS connection. If the client sent a CancelRequest, it is returned instead of
+// the startup message.
 var FrontendAdmit = func(
 	conn net.Conn, incomingTLSConfig *tls.Config,
-) (net.Conn, *pgproto3.StartupMessage, error) {
+) (net.Conn, *pgproto3.StartupMessage, *pgproto3.CancelRequest, error) {
 	// `conn` could be replaced by `conn` embedded in a `tls.Conn` connection,
 	// hence it's important to close `conn` rather than `proxyConn` since closing
 	// the latter will not call `Close` method of `tls.Conn`.
@@ -32,15 +33,14 @@ var FrontendAdmit = func(
 	// Read first message from client.
 	m, err := pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn).ReceiveStartupMessage()
 	if err != nil {
-		return conn, nil, newErrorf(codeClientReadFailed, "while receiving startup message")
+		return conn, nil, nil, newErrorf(codeClientReadFailed, "while receiving startup message")
 	}
 
 	// CancelRequest is unencrypted and unauthenticated, regardless of whether
-	// the server requires TLS connections. For now, ignore the request to cancel,
-	// and send back a nil StartupMessage, which will cause the proxy to just
-	// close the connection in response.
-	if _, ok := m.(*pgproto3.CancelRequest); ok {
-		return conn, nil, nil
+	// the server requires TLS connections. It is forwarded by the proxy to the
+	// server of the session it targets, and the connection is then closed.
+	if cancelRequest, ok := m.(*pgproto3.CancelRequest); ok {
+		return conn, nil, cancelRequest, nil
 	}
 
 	// If we have an incoming TLS Config, require that the client initiates with
@@ -48,12 +48,12 @@ var FrontendAdmit = func(
 	if incomingTLSConfig != nil {
 		if _, ok := m.(*pgproto3.SSLRequest); !ok {
 			code := codeUnexpectedInsecureStartupMessage
-			return conn, nil, newErrorf(code, "unsupported startup message: %T", m)
+			return conn, nil, nil, newErrorf(code, "unsupported startup message: %T", m)
 		}
 
 		_, err = conn.Write([]byte{pgAcceptSSLRequest})
 		if err != nil {
-			return conn, nil, newErrorf(codeClientWriteFailed, "acking SSLRequest: %v", err)
+			return conn, nil, nil, newErrorf(codeClientWriteFailed, "acking SSLRequest: %v", err)
 		}
 
 		cfg := incomingTLSConfig.Clone()
@@ -67,7 +67,7 @@ var FrontendAdmit = func(
 		// Now that SSL is established, read the encrypted startup message.
 		m, err = pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn).ReceiveStartupMessage()
 		if err != nil {
-			return conn, nil, newErrorf(codeClientReadFailed, "receiving post-TLS startup message: %v", err)
+			return conn, nil, nil, newErrorf(codeClientReadFailed, "receiving post-TLS startup message: %v", err)
 		}
 	}
 
@@ -76,9 +76,15 @@ var FrontendAdmit = func(
 		if sniServerName != "" {
 			startup.Parameters["sni-server"] = sniServerName
 		}
-		return conn, startup, nil
+		// The session revival token is only ever sent by the proxy itself, when
+		// transferring a connection to another server.
+		if _, ok := startup.Parameters[sessionRevivalTokenStartupParam]; ok {
+			code := codeUnexpectedStartupMessage
+			return conn, nil, nil, newErrorf(code, "parameter %s is not allowed", sessionRevivalTokenStartupParam)
+		}
+		return conn, startup, nil, nil
 	}
 
 	code := codeUnexpectedStartupMessage
-	return conn, nil, newErrorf(code, "unsupported post-TLS startup message: %T", m)
+	return conn, nil, nil, newErrorf(code, "unsupported post-TLS startup message: %T", m)
 }
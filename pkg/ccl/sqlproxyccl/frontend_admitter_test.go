@@ -58,10 +58,11 @@ func TestFrontendAdmitWithClientSSLDisableAndCustomParam(t *testing.T) {
 		fmt.Printf("Done\n")
 	}()
 
-	frontendCon, msg, err := FrontendAdmit(srv, nil)
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, nil)
 	require.NoError(t, err)
 	require.Equal(t, srv, frontendCon)
 	require.NotNil(t, msg)
+	require.Nil(t, cancelRequest)
 	require.Contains(t, msg.Parameters, "p1")
 	require.Equal(t, msg.Parameters["p1"], "a")
 }
@@ -88,11 +89,12 @@ func TestFrontendAdmitWithClientSSLRequire(t *testing.T) {
 
 	tlsConfig, err := tlsConfig()
 	require.NoError(t, err)
-	frontendCon, msg, err := FrontendAdmit(srv, tlsConfig)
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, tlsConfig)
 	require.NoError(t, err)
 	defer func() { _ = frontendCon.Close() }()
 	require.NotEqual(t, srv, frontendCon) // The connection was replaced by SSL
 	require.NotNil(t, msg)
+	require.Nil(t, cancelRequest)
 }
 
 // TestFrontendAdmitRequireEncryption sends StartupRequest when SSlRequest is
@@ -115,12 +117,13 @@ func TestFrontendAdmitRequireEncryption(t *testing.T) {
 
 	tlsConfig, err := tlsConfig()
 	require.NoError(t, err)
-	frontendCon, msg, err := FrontendAdmit(srv, tlsConfig)
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, tlsConfig)
 	require.EqualError(t, err,
 		"codeUnexpectedInsecureStartupMessage: "+
 			"unsupported startup message: *pgproto3.StartupMessage")
 	require.NotNil(t, frontendCon)
 	require.Nil(t, msg)
+	require.Nil(t, cancelRequest)
 }
 
 // TestFrontendAdmitWithCancel sends CancelRequest.
@@ -137,10 +140,38 @@ func TestFrontendAdmitWithCancel(t *testing.T) {
 		require.NoError(t, err)
 	}()
 
-	frontendCon, msg, err := FrontendAdmit(srv, nil)
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, nil)
 	require.NoError(t, err)
 	require.NotNil(t, frontendCon)
 	require.Nil(t, msg)
+	require.Equal(t, &pgproto3.CancelRequest{ProcessID: 1, SecretKey: 2}, cancelRequest)
+}
+
+// TestFrontendAdmitWithRevivalToken sends a StartupMessage with a session
+// revival token, which only the proxy is allowed to send.
+func TestFrontendAdmitWithRevivalToken(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cli, srv := net.Pipe()
+	require.NoError(t, srv.SetReadDeadline(timeutil.Now().Add(3e9)))
+	require.NoError(t, cli.SetReadDeadline(timeutil.Now().Add(3e9)))
+
+	go func() {
+		startup := pgproto3.StartupMessage{
+			ProtocolVersion: pgproto3.ProtocolVersionNumber,
+			Parameters:      map[string]string{sessionRevivalTokenStartupParam: "abc"},
+		}
+		_, err := cli.Write(startup.Encode([]byte{}))
+		require.NoError(t, err)
+	}()
+
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, nil)
+	require.EqualError(t, err,
+		"codeUnexpectedStartupMessage: "+
+			"parameter crdb:session_revival_token_base64 is not allowed")
+	require.NotNil(t, frontendCon)
+	require.Nil(t, msg)
+	require.Nil(t, cancelRequest)
 }
 
 // TestFrontendAdmitWithSSLAndCancel sends SSLRequest followed by CancelRequest.
@@ -167,11 +198,12 @@ func TestFrontendAdmitWithSSLAndCancel(t *testing.T) {
 
 	tlsConfig, err := tlsConfig()
 	require.NoError(t, err)
-	frontendCon, msg, err := FrontendAdmit(srv, tlsConfig)
+	frontendCon, msg, cancelRequest, err := FrontendAdmit(srv, tlsConfig)
 	require.EqualError(t, err,
 		"codeUnexpectedStartupMessage: "+
 			"unsupported post-TLS startup message: *pgproto3.CancelRequest",
 	)
 	require.NotNil(t, frontendCon)
 	require.Nil(t, msg)
+	require.Nil(t, cancelRequest)
 }
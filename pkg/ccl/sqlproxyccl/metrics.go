@@ -25,6 +25,10 @@ type metrics struct {
 	SuccessfulConnCount    *metric.Counter
 	AuthFailedCount        *metric.Counter
 	ExpiredClientConnCount *metric.Counter
+
+	ConnMigrationSuccessCount          *metric.Counter
+	ConnMigrationErrorFatalCount       *metric.Counter
+	ConnMigrationErrorRecoverableCount *metric.Counter
 }
 
 // MetricStruct implements the metrics.Struct interface.
@@ -93,6 +97,27 @@ var (
 		Measurement: "Expired Client Connections",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaConnMigrationSuccessCount = metric.Metadata{
+		Name:        "proxy.conn_migration.success",
+		Help:        "Number of successful connection transfers",
+		Measurement: "Connection Migrations",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnMigrationErrorFatalCount = metric.Metadata{
+		// When connection migrations errored out, connections will be closed.
+		Name:        "proxy.conn_migration.error_fatal",
+		Help:        "Number of failed connection transfers which resulted in terminations",
+		Measurement: "Connection Migrations",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnMigrationErrorRecoverableCount = metric.Metadata{
+		// When connection migrations errored out, connections will be resumed
+		// on the original SQL pod.
+		Name:        "proxy.conn_migration.error_recoverable",
+		Help:        "Number of failed connection transfers that were recoverable",
+		Measurement: "Connection Migrations",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // makeProxyMetrics instantiates the metrics holder for proxy monitoring.
@@ -108,6 +133,10 @@ func makeProxyMetrics() metrics {
 		SuccessfulConnCount:    metric.NewCounter(metaSuccessfulConnCount),
 		AuthFailedCount:        metric.NewCounter(metaAuthFailedCount),
 		ExpiredClientConnCount: metric.NewCounter(metaExpiredClientConnCount),
+
+		ConnMigrationSuccessCount:          metric.NewCounter(metaConnMigrationSuccessCount),
+		ConnMigrationErrorFatalCount:       metric.NewCounter(metaConnMigrationErrorFatalCount),
+		ConnMigrationErrorRecoverableCount: metric.NewCounter(metaConnMigrationErrorRecoverableCount),
 	}
 }
 
@@ -10,7 +10,10 @@ package sqlproxyccl
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"regexp"
@@ -28,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/netutil/addr"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
@@ -51,6 +55,11 @@ var (
 )
 
 const (
+	// sessionRevivalTokenStartupParam is the startup parameter used to
+	// authenticate with a session revival token when transferring a
+	// connection to another pod.
+	sessionRevivalTokenStartupParam = "crdb:session_revival_token_base64"
+
 	// Cluster identifier is in the form "<cluster name>-<tenant id>. Tenant ID
 	// is always in the end but the cluster name can also contain '-' or digits.
 	// (e.g. In "foo-7-10", cluster name is "foo-7" and tenant ID is "10")
@@ -100,6 +109,10 @@ type ProxyOptions struct {
 	// ThrottleBaseDelay is the initial exponential backoff triggered in
 	// response to the first connection failure.
 	ThrottleBaseDelay time.Duration
+	// ConnMigration if set, will transfer connections away from DRAINING pods
+	// to other pods of the same tenant, in between transactions. This requires
+	// the directory, and session revival tokens to be enabled on the tenant.
+	ConnMigration bool
 }
 
 // proxyHandler is the default implementation of a proxy handler.
@@ -131,6 +144,19 @@ type proxyHandler struct {
 
 	// CertManger keeps up to date the certificates used.
 	certManager *certmgr.CertManager
+
+	// forwarders tracks the forwarders of all the connections being proxied,
+	// so that their connections can be transferred to other pods.
+	forwarders struct {
+		syncutil.Mutex
+		m map[*forwarder]struct{}
+
+		// byBackendKeyData maps the BackendKeyData sent by the proxy to each
+		// client to the forwarder of its connection, so that cancel requests
+		// reach the pod currently serving the client. The forwarder is nil
+		// while the connection is being authenticated.
+		byBackendKeyData map[pgproto3.BackendKeyData]*forwarder
+	}
 }
 
 const throttledErrorHint string = `Connection throttling is triggered by repeated authentication failure. Make
@@ -152,6 +178,8 @@ func newProxyHandler(
 		ProxyOptions: options,
 		certManager:  certmgr.NewCertManager(ctx),
 	}
+	handler.forwarders.m = make(map[*forwarder]struct{})
+	handler.forwarders.byBackendKeyData = make(map[pgproto3.BackendKeyData]*forwarder)
 
 	err := handler.setupIncomingCert()
 	if err != nil {
@@ -184,11 +212,13 @@ func newProxyHandler(
 		// If a drain timeout has been specified, then start the idle monitor
 		// and the pod watcher. When a pod enters the DRAINING state, the pod
 		// watcher will set the idle monitor to detect connections without
-		// activity and terminate them.
+		// activity and terminate them. If connection migration is enabled, the
+		// pod watcher will also transfer the connections of DRAINING pods.
 		var dirOpts []tenant.DirOption
 		if options.DrainTimeout != 0 {
 			handler.idleMonitor = idle.NewMonitor(ctx, options.DrainTimeout)
-
+		}
+		if options.DrainTimeout != 0 || options.ConnMigration {
 			podWatcher := make(chan *tenant.Pod)
 			go handler.startPodWatcher(ctx, podWatcher)
 			dirOpts = append(dirOpts, tenant.PodWatcher(podWatcher))
@@ -207,17 +237,17 @@ func newProxyHandler(
 // handle is called by the proxy server to handle a single incoming client
 // connection.
 func (handler *proxyHandler) handle(ctx context.Context, incomingConn *proxyConn) error {
-	conn, msg, err := FrontendAdmit(incomingConn, handler.incomingTLSConfig())
+	conn, msg, cancelRequest, err := FrontendAdmit(incomingConn, handler.incomingTLSConfig())
 	defer func() { _ = conn.Close() }()
 	if err != nil {
 		SendErrToClient(conn, err)
 		return err
 	}
 
-	// This currently only happens for CancelRequest type of startup messages
-	// that we don't support. Return nil to the server, which simply closes the
-	// connection.
-	if msg == nil {
+	// The server never replies to a CancelRequest, so the connection is simply
+	// closed once the request has been forwarded.
+	if cancelRequest != nil {
+		handler.handleCancelRequest(ctx, cancelRequest)
 		return nil
 	}
 
@@ -320,33 +350,15 @@ func (handler *proxyHandler) handle(ctx context.Context, incomingConn *proxyConn
 			break
 		}
 
-		// NB: TLS options for the proxy are split into Insecure and
-		// SkipVerify. In insecure mode, tlsConf is expected to be nil. This
-		// will cause BackendDial to skip TLS entirely. If SkipVerify is true,
-		// tlsConf will be set to a non-nil config with InsecureSkipVerify set
-		// to true. InsecureSkipVerify will provide an encrypted connection but
-		// not verify that the connection recipient is a trusted party.
 		var tlsConf *tls.Config
-		if !handler.Insecure {
-			// Use an empty string as the default port as we only care about the
-			// correctly parsing the outgoingHost/IP here.
-			outgoingHost, _, err := addr.SplitHostPort(outgoingAddress, "")
-			if err != nil {
-				log.Errorf(ctx, "could not split outgoing address '%s' into host and port: %v", outgoingAddress, err.Error())
-				// Remap error for external consumption.
-				clientErr := newErrorf(
-					codeParamsRoutingFailed, "cluster %s-%d not found", clusterName, tenID.ToUint64())
-				updateMetricsAndSendErrToClient(clientErr, conn, handler.metrics)
-				return clientErr
-			}
-
-			tlsConf = &tls.Config{
-				// Always set ServerName, if SkipVerify is true, it will be
-				// ignored. When SkipVerify is false, it is required to
-				// establish a TLS connection.
-				ServerName:         outgoingHost,
-				InsecureSkipVerify: handler.SkipVerify,
-			}
+		tlsConf, err = handler.backendTLSConfig(outgoingAddress)
+		if err != nil {
+			log.Errorf(ctx, "could not split outgoing address '%s' into host and port: %v", outgoingAddress, err.Error())
+			// Remap error for external consumption.
+			clientErr := newErrorf(
+				codeParamsRoutingFailed, "cluster %s-%d not found", clusterName, tenID.ToUint64())
+			updateMetricsAndSendErrToClient(clientErr, conn, handler.metrics)
+			return clientErr
 		}
 
 		// Now actually dial the backend server.
@@ -392,27 +404,41 @@ func (handler *proxyHandler) handle(ctx context.Context, incomingConn *proxyConn
 	}
 
 	// Monitor for idle connection, if requested.
+	onIdle := func() {
+		err := newErrorf(codeIdleDisconnect, "idle connection closed")
+		select {
+		case errConnection <- err: /* error reported */
+		default: /* the channel already contains an error */
+		}
+	}
 	if handler.idleMonitor != nil {
-		crdbConn = handler.idleMonitor.DetectIdle(crdbConn, func() {
-			err := newErrorf(codeIdleDisconnect, "idle connection closed")
-			select {
-			case errConnection <- err: /* error reported */
-			default: /* the channel already contains an error */
-			}
-		})
+		crdbConn = handler.idleMonitor.DetectIdle(crdbConn, onIdle)
 	}
 
 	defer func() { _ = crdbConn.Close() }()
 
+	// The client is given a BackendKeyData generated by the proxy, which
+	// remains valid when the connection is transferred to another pod.
+	proxyBackendKeyData, err := handler.reserveBackendKeyData()
+	if err != nil {
+		log.Errorf(ctx, "generating BackendKeyData: %v", err)
+		err = newErrorf(codeProxyRefusedConnection, "connection refused")
+		updateMetricsAndSendErrToClient(err, conn, handler.metrics)
+		return err
+	}
+	defer handler.releaseBackendKeyData(proxyBackendKeyData)
+
 	// Perform user authentication.
-	if err := authenticate(conn, crdbConn, func(status throttler.AttemptStatus) error {
+	throttleHook := func(status throttler.AttemptStatus) error {
 		err := handler.throttleService.ReportAttempt(ctx, throttleTags, throttleTime, status)
 		if err != nil {
 			log.Errorf(ctx, "throttler refused connection after authentication: %v", err.Error())
 			return throttledError
 		}
 		return nil
-	}); err != nil {
+	}
+	crdbBackendKeyData, err := authenticate(conn, crdbConn, proxyBackendKeyData, throttleHook)
+	if err != nil {
 		handler.metrics.updateForError(err)
 		log.Ops.Errorf(ctx, "authenticate: %s", err)
 		return err
@@ -429,10 +455,46 @@ func (handler *proxyHandler) handle(ctx context.Context, incomingConn *proxyConn
 		log.Infof(ctx, "closing after %.2fs", timeutil.Since(connBegin).Seconds())
 	}()
 
-	// Copy all pgwire messages from frontend to backend connection until we
-	// encounter an error or shutdown signal.
+	// dialPod connects to a pod of the tenant using a session revival token,
+	// when the connection is transferred.
+	dialPod := func(ctx context.Context, revivalToken []byte) (net.Conn, string, error) {
+		podAddr, err := handler.outgoingAddress(ctx, clusterName, tenID)
+		if err != nil {
+			return nil, "", err
+		}
+		tlsConf, err := handler.backendTLSConfig(podAddr)
+		if err != nil {
+			return nil, "", err
+		}
+		startupMsg := &pgproto3.StartupMessage{
+			ProtocolVersion: backendStartupMsg.ProtocolVersion,
+			Parameters:      make(map[string]string, len(backendStartupMsg.Parameters)+1),
+		}
+		for key, value := range backendStartupMsg.Parameters {
+			startupMsg.Parameters[key] = value
+		}
+		startupMsg.Parameters[sessionRevivalTokenStartupParam] =
+			base64.StdEncoding.EncodeToString(revivalToken)
+		podConn, err := BackendDial(startupMsg, podAddr, tlsConf)
+		if err != nil {
+			return nil, "", err
+		}
+		if handler.idleMonitor != nil {
+			podConn = handler.idleMonitor.DetectIdle(podConn, onIdle)
+		}
+		return podConn, podAddr, nil
+	}
+	f := newForwarder(
+		ctx, conn, crdbConn, outgoingAddress, crdbBackendKeyData, dialPod, handler.metrics,
+	)
+	defer f.Close()
+	handler.addForwarder(f, proxyBackendKeyData)
+	defer handler.removeForwarder(f)
+
+	// Forward all pgwire messages between the frontend and backend
+	// connections until we encounter an error or shutdown signal.
 	go func() {
-		err := ConnectionCopy(crdbConn, conn)
+		err := f.run()
 		select {
 		case errConnection <- err: /* error reported */
 		default: /* the channel already contains an error */
@@ -462,7 +524,8 @@ func (handler *proxyHandler) handle(ctx context.Context, incomingConn *proxyConn
 // startPodWatcher runs on a background goroutine and listens to pod change
 // notifications. When a pod enters the DRAINING state, connections to that pod
 // are subject to an idle timeout that closes them after a short period of
-// inactivity. If a pod transitions back to the RUNNING state or to the DELETING
+// inactivity, and are transferred to other pods if connection migration is
+// enabled. If a pod transitions back to the RUNNING state or to the DELETING
 // state, then the idle timeout needs to be cleared.
 func (handler *proxyHandler) startPodWatcher(ctx context.Context, podWatcher chan *tenant.Pod) {
 	for {
@@ -471,8 +534,13 @@ func (handler *proxyHandler) startPodWatcher(ctx context.Context, podWatcher cha
 			return
 		case pod := <-podWatcher:
 			if pod.State == tenant.DRAINING {
-				handler.idleMonitor.SetIdleChecks(pod.Addr)
-			} else {
+				if handler.idleMonitor != nil {
+					handler.idleMonitor.SetIdleChecks(pod.Addr)
+				}
+				if handler.ConnMigration {
+					handler.transferConnsFromPod(ctx, pod.Addr)
+				}
+			} else if handler.idleMonitor != nil {
 				// Clear idle checks either for RUNNING or DELETING.
 				handler.idleMonitor.ClearIdleChecks(pod.Addr)
 			}
@@ -480,6 +548,127 @@ func (handler *proxyHandler) startPodWatcher(ctx context.Context, podWatcher cha
 	}
 }
 
+// addForwarder registers the forwarder of a new connection, whose client was
+// given proxyBackendKeyData.
+func (handler *proxyHandler) addForwarder(
+	f *forwarder, proxyBackendKeyData *pgproto3.BackendKeyData,
+) {
+	handler.forwarders.Lock()
+	defer handler.forwarders.Unlock()
+	handler.forwarders.m[f] = struct{}{}
+	handler.forwarders.byBackendKeyData[*proxyBackendKeyData] = f
+}
+
+// removeForwarder unregisters the forwarder of a closed connection.
+func (handler *proxyHandler) removeForwarder(f *forwarder) {
+	handler.forwarders.Lock()
+	defer handler.forwarders.Unlock()
+	delete(handler.forwarders.m, f)
+}
+
+// reserveBackendKeyData generates a BackendKeyData for a new client connection,
+// which is not used by any other connection. It must be released with
+// releaseBackendKeyData once the connection is closed.
+func (handler *proxyHandler) reserveBackendKeyData() (*pgproto3.BackendKeyData, error) {
+	handler.forwarders.Lock()
+	defer handler.forwarders.Unlock()
+	var buf [8]byte
+	for {
+		// The secret key must not be guessable, since cancel requests are not
+		// authenticated.
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		keyData := pgproto3.BackendKeyData{
+			ProcessID: binary.BigEndian.Uint32(buf[:4]),
+			SecretKey: binary.BigEndian.Uint32(buf[4:]),
+		}
+		if _, ok := handler.forwarders.byBackendKeyData[keyData]; !ok {
+			handler.forwarders.byBackendKeyData[keyData] = nil
+			return &keyData, nil
+		}
+	}
+}
+
+// releaseBackendKeyData releases a BackendKeyData reserved with
+// reserveBackendKeyData.
+func (handler *proxyHandler) releaseBackendKeyData(keyData *pgproto3.BackendKeyData) {
+	handler.forwarders.Lock()
+	defer handler.forwarders.Unlock()
+	delete(handler.forwarders.byBackendKeyData, *keyData)
+}
+
+// handleCancelRequest forwards a cancel request of a client to the pod
+// currently serving the connection it targets, with the BackendKeyData sent by
+// that pod. Requests which do not match any connection are ignored, the same
+// way a server ignores cancel requests with an unknown key.
+func (handler *proxyHandler) handleCancelRequest(
+	ctx context.Context, cancelRequest *pgproto3.CancelRequest,
+) {
+	handler.forwarders.Lock()
+	f := handler.forwarders.byBackendKeyData[pgproto3.BackendKeyData{
+		ProcessID: cancelRequest.ProcessID,
+		SecretKey: cancelRequest.SecretKey,
+	}]
+	handler.forwarders.Unlock()
+	if f == nil {
+		return
+	}
+	podAddr, crdbBackendKeyData := f.CancelTarget()
+	if crdbBackendKeyData == nil {
+		return
+	}
+	if err := sendCancelRequest(podAddr, crdbBackendKeyData); err != nil {
+		log.Warningf(ctx, "forwarding cancel request to %s: %v", podAddr, err)
+	}
+}
+
+// cancelRequestTimeout bounds the time spent forwarding a cancel request.
+const cancelRequestTimeout = 5 * time.Second
+
+// sendCancelRequest sends a CancelRequest with the given BackendKeyData to the
+// pod at podAddr. Like for any client, the request is sent unencrypted.
+var sendCancelRequest = func(podAddr string, crdbBackendKeyData *pgproto3.BackendKeyData) error {
+	conn, err := net.DialTimeout("tcp", podAddr, cancelRequestTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(timeutil.Now().Add(cancelRequestTimeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write((&pgproto3.CancelRequest{
+		ProcessID: crdbBackendKeyData.ProcessID,
+		SecretKey: crdbBackendKeyData.SecretKey,
+	}).Encode(nil))
+	return err
+}
+
+// transferConnsFromPod requests the transfer of all the connections to the
+// pod with the given address. Connections are transferred in between
+// transactions, so this does not wait for the transfers to happen.
+func (handler *proxyHandler) transferConnsFromPod(ctx context.Context, podAddr string) {
+	// Copy the forwarders so that the lock isn't held while waiting on
+	// connections which are in the middle of a transfer.
+	handler.forwarders.Lock()
+	forwarders := make([]*forwarder, 0, len(handler.forwarders.m))
+	for f := range handler.forwarders.m {
+		forwarders = append(forwarders, f)
+	}
+	handler.forwarders.Unlock()
+
+	var count int
+	for _, f := range forwarders {
+		if f.PodAddr() == podAddr {
+			f.RequestTransfer()
+			count++
+		}
+	}
+	if count > 0 {
+		log.Infof(ctx, "requested transfer of %d connections from DRAINING pod %s", count, podAddr)
+	}
+}
+
 // resolveTCPAddr indirection to allow test hooks.
 var resolveTCPAddr = net.ResolveTCPAddr
 
@@ -514,6 +703,33 @@ func (handler *proxyHandler) outgoingAddress(
 	return addr, nil
 }
 
+// backendTLSConfig returns the TLS config used to connect to the pod with the
+// given address.
+//
+// NB: TLS options for the proxy are split into Insecure and SkipVerify. In
+// insecure mode, the config is expected to be nil. This will cause BackendDial
+// to skip TLS entirely. If SkipVerify is true, the config will be non-nil with
+// InsecureSkipVerify set to true. InsecureSkipVerify will provide an encrypted
+// connection but not verify that the connection recipient is a trusted party.
+func (handler *proxyHandler) backendTLSConfig(outgoingAddress string) (*tls.Config, error) {
+	if handler.Insecure {
+		return nil, nil
+	}
+	// Use an empty string as the default port as we only care about the
+	// correctly parsing the outgoingHost/IP here.
+	outgoingHost, _, err := addr.SplitHostPort(outgoingAddress, "")
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		// Always set ServerName, if SkipVerify is true, it will be ignored.
+		// When SkipVerify is false, it is required to establish a TLS
+		// connection.
+		ServerName:         outgoingHost,
+		InsecureSkipVerify: handler.SkipVerify,
+	}, nil
+}
+
 // incomingTLSConfig gets back the current TLS config for the incoming client
 // connection endpoint.
 func (handler *proxyHandler) incomingTLSConfig() *tls.Config {
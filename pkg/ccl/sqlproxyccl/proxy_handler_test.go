@@ -178,9 +178,9 @@ func TestUnexpectedError(t *testing.T) {
 	// non-codeError error.
 	defer testutils.TestingHook(&FrontendAdmit, func(
 		conn net.Conn, incomingTLSConfig *tls.Config,
-	) (net.Conn, *pgproto3.StartupMessage, error) {
+	) (net.Conn, *pgproto3.StartupMessage, *pgproto3.CancelRequest, error) {
 		log.Infof(context.Background(), "frontend admitter returning unexpected error")
-		return conn, nil, errors.New("unexpected error")
+		return conn, nil, nil, errors.New("unexpected error")
 	})()
 
 	stopper := stop.NewStopper()
@@ -344,7 +344,7 @@ func TestProxyTLSClose(t *testing.T) {
 	originalFrontendAdmit := FrontendAdmit
 	defer testutils.TestingHook(&FrontendAdmit, func(
 		conn net.Conn, incomingTLSConfig *tls.Config,
-	) (net.Conn, *pgproto3.StartupMessage, error) {
+	) (net.Conn, *pgproto3.StartupMessage, *pgproto3.CancelRequest, error) {
 		proxyIncomingConn.Store(conn)
 		return originalFrontendAdmit(conn, incomingTLSConfig)
 	})()
@@ -469,8 +469,8 @@ func TestErroneousFrontend(t *testing.T) {
 
 	defer testutils.TestingHook(&FrontendAdmit, func(
 		conn net.Conn, incomingTLSConfig *tls.Config,
-	) (net.Conn, *pgproto3.StartupMessage, error) {
-		return conn, nil, errors.New(frontendError)
+	) (net.Conn, *pgproto3.StartupMessage, *pgproto3.CancelRequest, error) {
+		return conn, nil, nil, errors.New(frontendError)
 	})()
 
 	stopper := stop.NewStopper()
@@ -1023,10 +1023,14 @@ func newTester() *tester {
 	// Record successful connection and authentication.
 	originalAuthenticate := authenticate
 	te.restoreAuthenticate =
-		testutils.TestingHook(&authenticate, func(clientConn, crdbConn net.Conn, throttleHook func(status throttler.AttemptStatus) error) error {
-			err := originalAuthenticate(clientConn, crdbConn, throttleHook)
+		testutils.TestingHook(&authenticate, func(
+			clientConn, crdbConn net.Conn,
+			proxyBackendKeyData *pgproto3.BackendKeyData,
+			throttleHook func(status throttler.AttemptStatus) error,
+		) (*pgproto3.BackendKeyData, error) {
+			crdbBackendKeyData, err := originalAuthenticate(clientConn, crdbConn, proxyBackendKeyData, throttleHook)
 			te.setAuthenticated(err == nil)
-			return err
+			return crdbBackendKeyData, err
 		})
 
 	// Capture any error sent to the client.
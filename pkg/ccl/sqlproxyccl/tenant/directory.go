@@ -355,6 +355,12 @@ func (d *Directory) watchPods(ctx context.Context, stopper *stop.Stopper) error
 				continue
 			}
 
+			// Update the directory entry for the tenant with the latest
+			// information about this pod. This happens before notifying the
+			// caller, so that pods which are DRAINING are no longer chosen by
+			// the time the caller reacts to the notification.
+			d.updateTenantEntry(ctx, resp.Pod)
+
 			// If caller is watching pods, send to its channel now.
 			if d.options.podWatcher != nil {
 				select {
@@ -363,10 +369,6 @@ func (d *Directory) watchPods(ctx context.Context, stopper *stop.Stopper) error
 					break
 				}
 			}
-
-			// Update the directory entry for the tenant with the latest
-			// information about this pod.
-			d.updateTenantEntry(ctx, resp.Pod)
 		}
 	})
 	if err != nil {
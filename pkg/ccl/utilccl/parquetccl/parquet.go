@@ -0,0 +1,214 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+// Package parquetccl contains the logic, shared by EXPORT and changefeeds, to
+// map CockroachDB columns and datums onto parquet columns and values.
+package parquetccl
+
+import (
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/lib/pq/oid"
+)
+
+// Column contains the relevant data to map a crdb table column to a parquet
+// table column.
+type Column struct {
+	Name string
+	Type *types.T
+
+	// Definition contains all relevant information around the parquet type for
+	// the table column.
+	Definition *parquetschema.ColumnDefinition
+
+	// EncodeFn converts a non-NULL crdb table column value to a native go type.
+	EncodeFn func(datum tree.Datum) (interface{}, error)
+}
+
+// Encode converts the datum to the native go value expected by the parquet
+// writer for this column. NULLs are encoded as nil.
+func (c Column) Encode(d tree.Datum) (interface{}, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	return c.EncodeFn(d)
+}
+
+// NewColumn populates a Column by finding the right parquet type and defining
+// the EncodeFn.
+func NewColumn(typ *types.T, name string, nullable bool) (Column, error) {
+	col := Column{}
+	col.Definition = new(parquetschema.ColumnDefinition)
+	col.Definition.SchemaElement = parquet.NewSchemaElement()
+	col.Name = name
+	col.Type = typ
+
+	/*
+			The type of a parquet column is either a group (i.e.
+		  an array in crdb) or a primitive type (e.g., int, float, boolean,
+		  string) and the repetition can be one of the three following cases:
+
+		  - required: exactly one occurrence (i.e. the column value is a scalar, and
+		  cannot have null values). A column is set to required if the user
+		  specified the CRDB column as NOT NULL.
+		  - optional: 0 or 1 occurrence (i.e. same as above, but can have values)
+		  - repeated: 0 or more occurrences (the column value will be an array. A
+				value within the array will have its own repetition type)
+
+			See this blog post for more on parquet type specification:
+			https://blog.twitter.com/engineering/en_us/a/2013/dremel-made-simple-with-parquet
+	*/
+	col.Definition.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL)
+	if !nullable {
+		col.Definition.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED)
+	}
+	col.Definition.SchemaElement.Name = col.Name
+
+	// MB figured out the low level properties of the encoding by running the goland debugger on
+	// the following vendor example:
+	// https://github.com/fraugster/parquet-go/blob/master/examples/write-low-level/main.go
+	switch typ.Family() {
+	case types.BoolFamily:
+		col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_BOOLEAN)
+		col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+			return bool(*d.(*tree.DBool)), nil
+		}
+
+	case types.StringFamily:
+		col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		col.Definition.SchemaElement.LogicalType = parquet.NewLogicalType()
+		col.Definition.SchemaElement.LogicalType.STRING = parquet.NewStringType()
+		col.Definition.SchemaElement.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)
+		col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(*d.(*tree.DString)), nil
+		}
+
+	case types.IntFamily:
+		if typ.Oid() == oid.T_int8 {
+			col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_INT64)
+			col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+				return int64(*d.(*tree.DInt)), nil
+			}
+		} else {
+			col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_INT32)
+			col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+				return int32(*d.(*tree.DInt)), nil
+			}
+		}
+
+	case types.FloatFamily:
+		if typ.Oid() == oid.T_float8 {
+			col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_DOUBLE)
+			col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+				return float64(*d.(*tree.DFloat)), nil
+			}
+		} else {
+			col.Definition.SchemaElement.Type = parquet.TypePtr(parquet.Type_FLOAT)
+			col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+				return float32(*d.(*tree.DFloat)), nil
+			}
+		}
+
+	case types.ArrayFamily:
+
+		// Define a list such that the parquet schema in json is:
+		/*
+			required group colName (LIST){ // parent
+				repeated group list { // child
+					required colType element; //grandChild
+				}
+			}
+		*/
+		// MB figured this out by running toy examples of the fraugster-parquet
+		// vendor repository for added context, checkout this issue
+		// https://github.com/fraugster/parquet-go/issues/18
+
+		// First, define the grandChild definition, the schema for the array value.
+		grandChild, err := NewColumn(typ.ArrayContents(), "element", true)
+		if err != nil {
+			return col, err
+		}
+
+		// Next define the child definition, required by fraugster-parquet vendor library. Again,
+		// there's little documentation on this. MB figured this out using a debugger.
+		child := &parquetschema.ColumnDefinition{}
+		child.SchemaElement = parquet.NewSchemaElement()
+		child.SchemaElement.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.
+			FieldRepetitionType_REPEATED)
+		child.SchemaElement.Name = "list"
+		child.Children = []*parquetschema.ColumnDefinition{grandChild.Definition}
+		ngc := int32(len(child.Children))
+		child.SchemaElement.NumChildren = &ngc
+
+		// Finally, define the parent definition.
+		col.Definition.Children = []*parquetschema.ColumnDefinition{child}
+		nc := int32(len(col.Definition.Children))
+		child.SchemaElement.NumChildren = &nc
+		col.Definition.SchemaElement.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_LIST)
+		col.EncodeFn = func(d tree.Datum) (interface{}, error) {
+
+			datumArr := d.(*tree.DArray)
+			els := make([]map[string]interface{}, datumArr.Len())
+			for i, elt := range datumArr.Array {
+				var el interface{}
+				if elt.ResolvedType().Family() == types.UnknownFamily {
+					// skip encoding the datum
+				} else {
+					el, err = grandChild.EncodeFn(elt)
+					if err != nil {
+						return col, err
+					}
+				}
+				els[i] = map[string]interface{}{"element": el}
+			}
+			encEl := map[string]interface{}{"list": els}
+			return encEl, nil
+		}
+
+	default:
+		return col, errors.Errorf("parquet does not support the %v type yet", typ.Family())
+	}
+
+	return col, nil
+}
+
+// NewSchema creates the schema for the parquet file. See
+// https://github.com/fraugster/parquet-go/issues/18#issuecomment-946013210 for
+// an example schema, and the docs at
+// https://pkg.go.dev/github.com/fraugster/parquet-go/parquetschema#SchemaDefinition.
+func NewSchema(parquetFields []Column) *parquetschema.SchemaDefinition {
+
+	schemaDefinition := new(parquetschema.SchemaDefinition)
+	schemaDefinition.RootColumn = new(parquetschema.ColumnDefinition)
+	schemaDefinition.RootColumn.SchemaElement = parquet.NewSchemaElement()
+
+	for i := 0; i < len(parquetFields); i++ {
+		schemaDefinition.RootColumn.Children = append(schemaDefinition.RootColumn.Children,
+			parquetFields[i].Definition)
+		schemaDefinition.RootColumn.SchemaElement.Name = "root"
+	}
+	return schemaDefinition
+}
+
+// NewFileWriter returns a parquet writer which writes files with the given
+// schema to w. Written data is only guaranteed to be in w after the writer is
+// closed.
+func NewFileWriter(w io.Writer, schema *parquetschema.SchemaDefinition) *goparquet.FileWriter {
+	pw := goparquet.NewFileWriter(w,
+		// TODO(MB): allow for user defined compression
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+		goparquet.WithSchemaDefinition(schema),
+	)
+	return pw
+}
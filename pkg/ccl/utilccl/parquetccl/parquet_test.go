@@ -0,0 +1,85 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package parquetccl
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadBack(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var cols []Column
+	for _, c := range []struct {
+		name     string
+		typ      *types.T
+		nullable bool
+	}{
+		{"a", types.Int, false},
+		{"b", types.String, true},
+		{"c", types.Float, true},
+		{"d", types.Bool, true},
+	} {
+		col, err := NewColumn(c.typ, c.name, c.nullable)
+		require.NoError(t, err)
+		cols = append(cols, col)
+	}
+
+	rows := []tree.Datums{
+		{tree.NewDInt(1), tree.NewDString("one"), tree.NewDFloat(1.5), tree.DBoolTrue},
+		{tree.NewDInt(2), tree.DNull, tree.DNull, tree.DBoolFalse},
+	}
+
+	var buf bytes.Buffer
+	w := NewFileWriter(&buf, NewSchema(cols))
+	for _, row := range rows {
+		record := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			v, err := col.Encode(row[i])
+			require.NoError(t, err)
+			if v != nil {
+				record[col.Name] = v
+			}
+		}
+		require.NoError(t, w.AddData(record))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := goparquet.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(rows)), r.NumRows())
+
+	first, err := r.NextRow()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"a": int64(1), "b": []byte("one"), "c": 1.5, "d": true,
+	}, first)
+
+	second, err := r.NextRow()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": int64(2), "d": false}, second)
+
+	_, err = r.NextRow()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestUnsupportedType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, err := NewColumn(types.Interval, "i", true)
+	require.EqualError(t, err, "parquet does not support the IntervalFamily type yet")
+}
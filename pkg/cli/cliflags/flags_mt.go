@@ -98,6 +98,11 @@ This rule must include the port of the SQL pod.`,
 		Description: "Close DRAINING connections idle for this duration.",
 	}
 
+	ConnMigration = FlagInfo{
+		Name:        "conn-migration",
+		Description: "Transfer connections away from DRAINING pods in between transactions.",
+	}
+
 	TestDirectoryListenPort = FlagInfo{
 		Name:        "port",
 		Description: "Test directory server binds and listens on this port.",
@@ -638,6 +638,7 @@ func setProxyContextDefaults() {
 	proxyContext.ValidateAccessInterval = 30 * time.Second
 	proxyContext.PollConfigInterval = 30 * time.Second
 	proxyContext.DrainTimeout = 0
+	proxyContext.ConnMigration = false
 	proxyContext.ThrottleBaseDelay = time.Second
 }
 
@@ -239,6 +239,49 @@ const (
 	// EnableSpanConfigStore enables the use of the span configs infrastructure
 	// in KV.
 	EnableSpanConfigStore
+	// RowLevelTTL is the version where we allow row level TTL tables.
+	RowLevelTTL
+	// UserDefinedFunctions is the version where function descriptors may be
+	// created.
+	UserDefinedFunctions
+	// RowLevelTriggers is the version where row-level triggers may be created on
+	// tables.
+	RowLevelTriggers
+	// TSearch adds the TSVECTOR and TSQUERY types and full-text search support.
+	TSearch
+	// DeferrableConstraints is the version where foreign key and unique
+	// constraints may be declared DEFERRABLE.
+	DeferrableConstraints
+	// NotificationsTable adds the system.notifications table used by LISTEN and
+	// NOTIFY.
+	NotificationsTable
+	// ReadCommittedIsolation allows transactions to run with the READ COMMITTED
+	// isolation level, which requires all the nodes to understand the isolation
+	// level of transactions.
+	ReadCommittedIsolation
+	// ExclusionConstraints enables the creation of exclusion constraints.
+	ExclusionConstraints
+	// RangeTypes adds the built-in range types, such as INT8RANGE and TSTZRANGE.
+	RangeTypes
+	// DomainTypes enables the creation of domain types.
+	DomainTypes
+	// Publications enables the creation of publications and logical replication
+	// slots, stored in database descriptors.
+	Publications
+	// ImportParquet enables IMPORT INTO from Parquet files.
+	ImportParquet
+	// ImportExportJSON enables IMPORT INTO and EXPORT of newline-delimited JSON
+	// files.
+	ImportExportJSON
+	// BackupCompaction adds BACKUP COMPACT, which merges a chain of incremental
+	// backups into a new full backup.
+	BackupCompaction
+	// VerifyBackup adds the VERIFY BACKUP job, which checks the files of a
+	// backup without restoring it.
+	VerifyBackup
+	// RestoreRowFilter adds RESTORE ... WHERE, which restores only the rows of a
+	// table that match a predicate.
+	RestoreRowFilter
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -359,6 +402,70 @@ var versionsSingleton = keyedVersions{
 		Key:     EnableSpanConfigStore,
 		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 42},
 	},
+	{
+		Key:     RowLevelTTL,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 44},
+	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 46},
+	},
+	{
+		Key:     RowLevelTriggers,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 48},
+	},
+	{
+		Key:     TSearch,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 50},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 52},
+	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 54},
+	},
+	{
+		Key:     ReadCommittedIsolation,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 56},
+	},
+	{
+		Key:     ExclusionConstraints,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 58},
+	},
+	{
+		Key:     RangeTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 60},
+	},
+	{
+		Key:     DomainTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 62},
+	},
+	{
+		Key:     Publications,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 64},
+	},
+	{
+		Key:     ImportParquet,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 66},
+	},
+	{
+		Key:     ImportExportJSON,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 68},
+	},
+	{
+		Key:     BackupCompaction,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 70},
+	},
+	{
+		Key:     VerifyBackup,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 72},
+	},
+	{
+		Key:     RestoreRowFilter,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 74},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
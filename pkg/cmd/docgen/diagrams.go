@@ -563,6 +563,10 @@ var specs = []stmtSpec{
 		inline:  []string{"opt_with_copy_options", "copy_options_list", "opt_with", "opt_where_clause", "where_clause"},
 		exclude: []*regexp.Regexp{regexp.MustCompile("'WHERE'")},
 	},
+	{
+		name:   "copy_to_stmt",
+		inline: []string{"opt_with_copy_options", "copy_options_list", "opt_with"},
+	},
 	{
 		name:    "cancel_job",
 		stmt:    "cancel_jobs_stmt",
@@ -635,6 +639,7 @@ var specs = []stmtSpec{
 		},
 		nosplit: true,
 	},
+	{name: "create_publication_stmt"},
 	{
 		name:   "create_schedule_for_backup_stmt",
 		inline: []string{"string_or_placeholder_opt_list", "string_or_placeholder_list", "opt_with_backup_options", "cron_expr", "opt_full_backup_clause", "opt_with_schedule_options", "opt_backup_targets"},
@@ -702,7 +707,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:   "delete_stmt",
-		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant"},
+		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_using_clause", "from_list", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant"},
 		replace: map[string]string{
 			"relation_expr": "table_name",
 		},
@@ -761,6 +766,7 @@ var specs = []stmtSpec{
 		},
 		replace: map[string]string{"standalone_index_name": "index_name"},
 	},
+	{name: "drop_publication_stmt"},
 	{
 		name:    "drop_role_stmt",
 		inline:  []string{"role_or_group_or_user"},
@@ -950,12 +956,14 @@ var specs = []stmtSpec{
 		nosplit: true,
 	},
 	{name: "iso_level"},
+	{name: "listen_stmt"},
 	{
 		name: "not_null_column_level",
 		stmt: "stmt_block",
 		replace: map[string]string{"	stmt": "	'CREATE' 'TABLE' table_name '(' column_name column_type 'NOT NULL' ( column_constraints | ) ( ',' ( column_def ( ',' column_def )* ) | ) ( table_constraints | ) ')' ')'"},
 		unlink: []string{"table_name", "column_name", "column_type", "table_constraints"},
 	},
+	{name: "notify_stmt"},
 	{
 		name:   "opt_with_storage_parameter_list",
 		inline: []string{"storage_parameter_list"},
@@ -1392,6 +1400,7 @@ var specs = []stmtSpec{
 		replace: map[string]string{"	stmt": "	'CREATE' 'TABLE' table_name '(' ( column_def ( ',' column_def )* ) ( 'CONSTRAINT' name | ) 'UNIQUE' '(' ( column_name ( ',' column_name )* ) ')' ( table_constraints | ) ')'"},
 		unlink: []string{"table_name", "check_expr", "table_constraints"},
 	},
+	{name: "unlisten_stmt"},
 	{
 		name:    "unsplit_index_at",
 		stmt:    "alter_unsplit_index_stmt",
@@ -1403,6 +1412,50 @@ var specs = []stmtSpec{
 		stmt:   "alter_unsplit_stmt",
 		unlink: []string{"table_name"},
 	},
+	{
+		name:   "verify_backup",
+		stmt:   "verify_backup_stmt",
+		inline: []string{"opt_with_options", "string_or_placeholder_opt_list"},
+		replace: map[string]string{
+			"'BACKUP' string_or_placeholder": "'BACKUP' subdirectory",
+			"'IN' string_or_placeholder":     "'IN' location",
+		},
+		unlink: []string{"subdirectory", "location"},
+	},
+	{
+		name: "merge_stmt",
+		inline: []string{
+			"opt_with_clause",
+			"with_clause",
+			"cte_list",
+			"table_expr_opt_alias_idx",
+			"table_name_opt_idx",
+			"merge_when_list",
+			"merge_when_clause",
+			"opt_merge_when_cond",
+			"set_clause_list",
+			"set_clause",
+			"single_set_clause",
+			"multiple_set_clause",
+			"in_expr",
+			"expr_list",
+			"expr_tuple1_ambiguous",
+			"tuple1_ambiguous_values",
+			"insert_column_list",
+			"insert_column_item",
+			"opt_only",
+			"opt_descendant",
+		},
+		replace: map[string]string{
+			"relation_expr":      "table_name",
+			"select_with_parens": "'(' select_stmt ')'",
+		},
+		relink: map[string]string{
+			"table_name":       "relation_expr",
+			"column_name_list": "insert_column_list",
+		},
+		nosplit: true,
+	},
 	{
 		name: "update_stmt",
 		inline: []string{
@@ -408,6 +408,7 @@ const (
 	TenantUsageTableID                  = 45
 	SQLInstancesTableID                 = 46
 	SpanConfigurationsTableID           = 47
+	NotificationsTableID                = 48
 )
 
 // CommentType the type of the schema object on which a comment has been
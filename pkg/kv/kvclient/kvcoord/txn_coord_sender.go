@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -830,6 +831,17 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 		return retErr
 	}
 
+	if newTxn.Epoch == pErr.GetTxn().Epoch {
+		// The transaction was only prepared for the retry of its current
+		// statement. The client will roll back the writes of the statement to
+		// a savepoint, so the epoch-based coordinator state is kept; only the
+		// reads of the statement are forgotten.
+		retErr.StatementRestart = true
+		tc.mu.txn.Update(&newTxn)
+		tc.interceptorAlloc.txnSpanRefresher.resetRefreshSpansLocked()
+		return retErr
+	}
+
 	// This is where we get a new epoch.
 	tc.mu.txn.Update(&newTxn)
 
@@ -1015,6 +1027,24 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsoLevel(level isolation.Level) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && level != tc.mu.txn.IsoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.txn.IsoLevel = level
+	return nil
+}
+
+// IsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsoLevel() isolation.Level {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.IsoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -1129,6 +1159,10 @@ func (tc *TxnCoordSender) IsSerializablePushAndRefreshNotPossible() bool {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
+	if tc.mu.txn.IsoLevel.ToleratesWriteSkew() {
+		// The transaction can commit above its read timestamp without a refresh.
+		return false
+	}
 	isTxnPushed := tc.mu.txn.WriteTimestamp != tc.mu.txn.ReadTimestamp
 	refreshAttemptNotPossible := tc.interceptorAlloc.txnSpanRefresher.refreshInvalid ||
 		tc.mu.txn.CommitTimestampFixed
@@ -1302,6 +1336,29 @@ func (tc *TxnCoordSender) Step(ctx context.Context) error {
 	return tc.interceptorAlloc.txnSeqNumAllocator.stepLocked(ctx)
 }
 
+// StepReadTimestamp is part of the TxnSender interface.
+func (tc *TxnCoordSender) StepReadTimestamp(ctx context.Context) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if !tc.mu.txn.IsoLevel.PerStatementReadSnapshot() || tc.mu.txn.CommitTimestampFixed {
+		return nil
+	}
+	if tc.mu.txnState == txnFinalized {
+		return errors.WithContextTags(errors.AssertionFailedf(
+			"cannot step the read timestamp of finalized txn %s", tc.mu.txn), ctx)
+	}
+	// Move the read snapshot of the transaction up to the present. The
+	// uncertainty interval of the transaction starts over along with it, so
+	// the observed timestamps, which were all taken before now, are no longer
+	// useful.
+	now := tc.clock.Now()
+	tc.mu.txn.Refresh(now)
+	tc.mu.txn.GlobalUncertaintyLimit.Forward(now.Add(tc.clock.MaxOffset().Nanoseconds(), 0))
+	tc.mu.txn.ObservedTimestamps = nil
+	tc.interceptorAlloc.txnSpanRefresher.resetRefreshSpansLocked()
+	return nil
+}
+
 // ConfigureStepping is part of the TxnSender interface.
 func (tc *TxnCoordSender) ConfigureStepping(
 	ctx context.Context, mode kv.SteppingMode,
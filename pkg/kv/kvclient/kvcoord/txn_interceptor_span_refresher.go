@@ -447,8 +447,11 @@ func (sr *txnSpanRefresher) maybeRefreshPreemptivelyLocked(
 	refreshFree := ba.CanForwardReadTimestamp
 
 	// If true, this batch is guaranteed to fail without a refresh.
+	// Transactions that tolerate write skew commit at their write timestamp
+	// without refreshing their reads.
 	args, hasET := ba.GetArg(roachpb.EndTxn)
-	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit
+	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit &&
+		!ba.Txn.IsoLevel.ToleratesWriteSkew()
 
 	// If neither condition is true, defer the refresh.
 	if !refreshFree && !refreshInevitable && !force {
@@ -683,6 +686,15 @@ func (sr *txnSpanRefresher) importLeafFinalState(
 
 // epochBumpedLocked implements the txnInterceptor interface.
 func (sr *txnSpanRefresher) epochBumpedLocked() {
+	sr.resetRefreshSpansLocked()
+}
+
+// resetRefreshSpansLocked forgets about the reads performed by the
+// transaction so far. It is called when the transaction starts reading from a
+// new snapshot, either because its epoch was bumped or because a transaction
+// with per-statement read snapshots started or retried a statement. The reads
+// of the earlier snapshots never need to be refreshed.
+func (sr *txnSpanRefresher) resetRefreshSpansLocked() {
 	sr.refreshFootprint.clear()
 	sr.refreshInvalid = false
 	sr.refreshedTimestamp.Reset()
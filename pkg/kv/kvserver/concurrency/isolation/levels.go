@@ -0,0 +1,30 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package isolation provides type definitions for isolation level concepts
+// used by concurrency control in the key-value layer.
+package isolation
+
+// ToleratesWriteSkew returns whether transactions running at the isolation
+// level may commit with a write timestamp above their read timestamp without
+// first refreshing their reads.
+func (l Level) ToleratesWriteSkew() bool {
+	return l == ReadCommitted
+}
+
+// PerStatementReadSnapshot returns whether transactions running at the
+// isolation level read from a new snapshot at the start of each SQL
+// statement, instead of from a single snapshot for their entire duration.
+func (l Level) PerStatementReadSnapshot() bool {
+	return l == ReadCommitted
+}
+
+// SafeValue implements redact.SafeValue.
+func (Level) SafeValue() {}
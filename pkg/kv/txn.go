@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -305,6 +306,26 @@ func (txn *Txn) UserPriority() roachpb.UserPriority {
 	return txn.mu.userPriority
 }
 
+// SetIsoLevel sets the transaction's isolation level. Transactions default to
+// Serializable isolation. The isolation level must be set before any
+// operations are performed on the transaction.
+func (txn *Txn) SetIsoLevel(level isolation.Level) error {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("SetIsoLevel() called on leaf txn"))
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetIsoLevel(level)
+}
+
+// IsoLevel returns the transaction's isolation level.
+func (txn *Txn) IsoLevel() isolation.Level {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.IsoLevel()
+}
+
 // SetDebugName sets the debug name associated with the transaction which will
 // appear in log files and the web UI.
 func (txn *Txn) SetDebugName(name string) {
@@ -1030,6 +1051,12 @@ func (txn *Txn) PrepareForRetry(ctx context.Context, err error) {
 	txn.commitTriggers = nil
 	log.VEventf(ctx, 2, "automatically retrying transaction: %s because of error: %s",
 		txn.DebugName(), err)
+	if t := (*roachpb.TransactionRetryWithProtoRefreshError)(nil); errors.As(err, &t) && t.StatementRestart {
+		// The transaction was only prepared for the retry of its current
+		// statement, but the whole closure is about to be retried, so the
+		// writes of its earlier statements must be discarded as well.
+		txn.ManualRestart(ctx, txn.db.clock.Now())
+	}
 }
 
 // IsRetryableErrMeantForTxn returns true if err is a retryable
@@ -1576,6 +1603,20 @@ func (txn *Txn) Step(ctx context.Context) error {
 	return txn.mu.sender.Step(ctx)
 }
 
+// StepReadTimestamp moves the read snapshot of a transaction with
+// per-statement read snapshots up to the present. See the TxnSender interface
+// for details.
+func (txn *Txn) StepReadTimestamp(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.WithContextTags(
+			errors.AssertionFailedf("StepReadTimestamp() called on leaf txn"), ctx)
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.StepReadTimestamp(ctx)
+}
+
 // ConfigureStepping configures step-wise execution in the
 // transaction.
 func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevMode SteppingMode) {
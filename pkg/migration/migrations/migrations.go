@@ -91,6 +91,12 @@ var migrations = []migration.Migration{
 		toCV(clusterversion.EnsureSpanConfigSubscription),
 		ensureSpanConfigSubscription,
 	),
+	migration.NewTenantMigration(
+		"add the system.notifications table",
+		toCV(clusterversion.NotificationsTable),
+		NoPrecondition,
+		notificationsTableMigration,
+	),
 }
 
 func init() {
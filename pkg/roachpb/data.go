@@ -1122,6 +1122,21 @@ func (t *Transaction) Restart(
 	t.IgnoredSeqNums = nil
 }
 
+// RestartStatement reconfigures a transaction with per-statement read
+// snapshots for the retry of its current statement. Unlike Restart, the epoch
+// and the epoch-scoped state are preserved, so the writes of the earlier
+// statements remain valid. The writes of the failed statement must be rolled
+// back to a savepoint by the client. The read timestamp of the transaction is
+// moved up to its write timestamp, which is forwarded to the specified
+// timestamp.
+func (t *Transaction) RestartStatement(
+	userPriority UserPriority, upgradePriority enginepb.TxnPriority, timestamp hlc.Timestamp,
+) {
+	t.Refresh(timestamp)
+	t.UpgradePriority(MakePriority(userPriority))
+	t.UpgradePriority(upgradePriority)
+}
+
 // BumpEpoch increments the transaction's epoch, allowing for an in-place
 // restart. This invalidates all write intents previously written at lower
 // epochs.
@@ -1424,6 +1439,10 @@ func (tr *TransactionRecord) AsTransaction() Transaction {
 //
 // In case retryErr tells us that a new Transaction needs to be created,
 // isolation and name help initialize this new transaction.
+//
+// A transaction with per-statement read snapshots is only prepared for the
+// retry of its current statement, without incrementing its epoch, if the
+// error allows it (see canRestartStatement).
 func PrepareTransactionForRetry(
 	ctx context.Context, pErr *Error, pri UserPriority, clock *hlc.Clock,
 ) Transaction {
@@ -1461,6 +1480,7 @@ func PrepareTransactionForRetry(
 		)
 		// Use the priority communicated back by the server.
 		txn.Priority = errTxnPri
+		txn.IsoLevel = pErr.GetTxn().IsoLevel
 	case *ReadWithinUncertaintyIntervalError:
 		txn.WriteTimestamp.Forward(readWithinUncertaintyIntervalRetryTimestamp(tErr))
 	case *TransactionPushError:
@@ -1501,11 +1521,37 @@ func PrepareTransactionForRetry(
 		if txn.Status.IsFinalized() {
 			log.Fatalf(ctx, "transaction unexpectedly finalized in (%T): %s", pErr.GetDetail(), pErr)
 		}
-		txn.Restart(pri, txn.Priority, txn.WriteTimestamp)
+		if canRestartStatement(pErr, &txn) {
+			txn.RestartStatement(pri, txn.Priority, txn.WriteTimestamp)
+		} else {
+			txn.Restart(pri, txn.Priority, txn.WriteTimestamp)
+		}
 	}
 	return txn
 }
 
+// canRestartStatement returns whether the retryable error can be handled by
+// retrying the current statement of the transaction at a higher timestamp,
+// instead of restarting the whole transaction at a new epoch. This is only
+// possible for transactions with per-statement read snapshots, whose earlier
+// statements don't need to observe the same snapshot as the current one, and
+// for errors caused by the timestamp of the current statement.
+func canRestartStatement(pErr *Error, txn *Transaction) bool {
+	if !txn.IsoLevel.PerStatementReadSnapshot() || txn.CommitTimestampFixed {
+		return false
+	}
+	switch tErr := pErr.GetDetail().(type) {
+	case *ReadWithinUncertaintyIntervalError, *TransactionPushError, *WriteTooOldError:
+		return true
+	case *TransactionRetryError:
+		// Asynchronous write failures and deadline violations are not caused
+		// by the current statement, so they require a restart.
+		return tErr.Reason == RETRY_WRITE_TOO_OLD || tErr.Reason == RETRY_SERIALIZABLE
+	default:
+		return false
+	}
+}
+
 // TransactionRefreshTimestamp returns whether the supplied error is a retry
 // error that can be discarded if the transaction in the error is refreshed. If
 // true, the function returns the timestamp that the Transaction object should
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -58,6 +59,19 @@ var (
 		),
 		90*24*time.Hour, // 90 days
 	).WithPublic()
+
+	// notificationsTTL is the TTL for rows in system.notifications. Listeners
+	// receive notifications through a rangefeed as soon as they are written, so
+	// the rows only need to outlive transient rangefeed disconnections.
+	notificationsTTL = settings.RegisterDurationSetting(
+		settings.TenantWritable,
+		"server.notifications.ttl",
+		fmt.Sprintf(
+			"if nonzero, entries in system.notifications older than this duration are deleted every %s",
+			systemLogGCPeriod,
+		),
+		time.Hour,
+	)
 )
 
 // gcSystemLog deletes entries in the given system log table between
@@ -151,8 +165,8 @@ type systemLogGCConfig struct {
 	timestampLowerBound time.Time
 }
 
-// startSystemLogsGC starts a worker which periodically GCs system.rangelog
-// and system.eventlog.
+// startSystemLogsGC starts a worker which periodically GCs system.rangelog,
+// system.eventlog and system.notifications.
 // The TTLs for each of these logs is retrieved from cluster settings.
 func (s *Server) startSystemLogsGC(ctx context.Context) {
 	systemLogsToGC := map[string]*systemLogGCConfig{
@@ -164,6 +178,10 @@ func (s *Server) startSystemLogsGC(ctx context.Context) {
 			ttl:                 eventLogTTL,
 			timestampLowerBound: timeutil.Unix(0, 0),
 		},
+		"notifications": {
+			ttl:                 notificationsTTL,
+			timestampLowerBound: timeutil.Unix(0, 0),
+		},
 	}
 
 	_ = s.stopper.RunAsyncTask(ctx, "system-log-gc", func(ctx context.Context) {
@@ -179,6 +197,11 @@ func (s *Server) startSystemLogsGC(ctx context.Context) {
 			select {
 			case <-t.C:
 				for table, gcConfig := range systemLogsToGC {
+					if table == "notifications" &&
+						!s.cfg.Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+						// The table is created by a migration.
+						continue
+					}
 					ttl := gcConfig.ttl.Get(&s.cfg.Settings.SV)
 					if ttl > 0 {
 						timestampUpperBound := timeutil.Unix(0, s.clock.PhysicalNow()-int64(ttl))
@@ -55,6 +55,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
@@ -2837,3 +2839,52 @@ func (s *statusServer) JobStatus(
 
 	return &serverpb.JobStatusResponse{Job: res}, nil
 }
+
+// ChangefeedEvents fetches the events buffered by a changefeed with a pull
+// sink. The request is routed to the node running the changefeed, which is the
+// node that has claimed its job unless a node is specified.
+func (s *statusServer) ChangefeedEvents(
+	ctx context.Context, req *serverpb.ChangefeedEventsRequest,
+) (*serverpb.ChangefeedEventsResponse, error) {
+	ctx = s.AnnotateCtx(propagateGatewayMetadata(ctx))
+
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.NodeID == "" {
+		row, err := s.internalExecutor.QueryRowEx(
+			ctx, "changefeed-events-claim", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			"SELECT claim_instance_id FROM system.jobs WHERE id = $1", req.JobID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return nil, status.Errorf(codes.NotFound, "job %d not found", req.JobID)
+		}
+		if row[0] == tree.DNull {
+			return nil, status.Errorf(codes.Unavailable, "job %d is not running", req.JobID)
+		}
+		req.NodeID = strconv.Itoa(int(tree.MustBeDInt(row[0])))
+	}
+
+	nodeID, local, err := s.parseNodeID(req.NodeID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		status, err := s.dialNode(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.ChangefeedEvents(ctx, req)
+	}
+
+	fetcher, err := sql.GetChangefeedEventsFetcher()
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, err.Error())
+	}
+	return fetcher.FetchChangefeedEvents(ctx, base.SQLInstanceID(nodeID), req)
+}
@@ -0,0 +1,265 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n      *tree.AlterDomain
+	prefix catalog.ResolvedObjectPrefix
+	desc   *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+// AlterDomain alters a domain type.
+// Privileges: ownership of the domain.
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+
+	// Resolve the domain.
+	prefix, desc, err := p.ResolveMutableTypeDescriptor(ctx, n.Domain, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a domain", tree.AsStringWithFQNames(n.Domain, &p.semaCtx.Annotations))
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{
+		n:      n,
+		prefix: prefix,
+		desc:   desc,
+	}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	// Renaming the domain, changing its schema or its owner is handled in the
+	// same way as for any other type.
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterTypeRename, *tree.AlterTypeSetSchema, *tree.AlterTypeOwner:
+		typeNode := &alterTypeNode{
+			n:      &tree.AlterType{Type: n.n.Domain, Cmd: t.(tree.AlterTypeCmd)},
+			prefix: n.prefix,
+			desc:   n.desc,
+		}
+		return typeNode.startExec(params)
+	}
+
+	telemetry.Inc(n.n.Cmd.TelemetryCounter())
+
+	cfg := n.desc.DomainConfig
+	domainName := n.desc.GetName()
+	var err error
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainSetDefault:
+		if t.Default == nil {
+			cfg.DefaultExpr = nil
+			break
+		}
+		var defaultExpr string
+		defaultExpr, err = validateDomainDefaultExpr(params, t.Default, cfg.BaseType)
+		if err == nil {
+			cfg.DefaultExpr = &defaultExpr
+		}
+	case *tree.AlterDomainSetNotNull:
+		if t.NotNull && !cfg.NotNull {
+			err = params.p.validateDomainColumns(params.ctx, n.desc, "NOT NULL",
+				func(col tree.Expr) (tree.Expr, error) {
+					return &tree.IsNullExpr{Expr: col}, nil
+				},
+			)
+		}
+		if err == nil {
+			cfg.NotNull = t.NotNull
+		}
+	case *tree.AlterDomainAddConstraint:
+		if err = addDomainCheckConstraint(params, domainName, cfg, &t.Constraint); err != nil {
+			break
+		}
+		added := &cfg.CheckConstraints[len(cfg.CheckConstraints)-1]
+		err = params.p.validateDomainCheckConstraint(params.ctx, n.desc, added)
+	case *tree.AlterDomainDropConstraint:
+		idx := findDomainCheckConstraint(cfg, string(t.Constraint))
+		if idx == -1 {
+			if t.IfExists {
+				params.p.BufferClientNotice(
+					params.ctx,
+					pgnotice.Newf("constraint %q of domain %q does not exist, skipping",
+						t.Constraint, domainName),
+				)
+				return nil
+			}
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q of domain %q does not exist", t.Constraint, domainName)
+		}
+		cfg.CheckConstraints = append(cfg.CheckConstraints[:idx], cfg.CheckConstraints[idx+1:]...)
+	case *tree.AlterDomainRenameConstraint:
+		idx := findDomainCheckConstraint(cfg, string(t.Constraint))
+		if idx == -1 {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q of domain %q does not exist", t.Constraint, domainName)
+		}
+		if t.Constraint == t.NewName {
+			return nil
+		}
+		if findDomainCheckConstraint(cfg, string(t.NewName)) != -1 {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"constraint %q for domain %s already exists", t.NewName, domainName)
+		}
+		cfg.CheckConstraints[idx].Name = string(t.NewName)
+	default:
+		err = errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := params.p.writeTypeSchemaChange(
+		params.ctx, n.desc, tree.AsStringWithFQNames(n.n, params.p.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.Domain, params.p.Ann()),
+		})
+}
+
+// findDomainCheckConstraint returns the index of the CHECK constraint of the
+// domain with the given name, or -1 if there is none.
+func findDomainCheckConstraint(cfg *descpb.TypeDescriptor_DomainConfig, name string) int {
+	for i := range cfg.CheckConstraints {
+		if cfg.CheckConstraints[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateDomainCheckConstraint verifies that the values stored in all the
+// columns of the given domain type satisfy the given CHECK constraint.
+func (p *planner) validateDomainCheckConstraint(
+	ctx context.Context,
+	desc *typedesc.Mutable,
+	c *descpb.TypeDescriptor_DomainConfig_CheckConstraint,
+) error {
+	expr, err := parser.ParseExpr(c.Expr)
+	if err != nil {
+		return err
+	}
+	return p.validateDomainColumns(ctx, desc, fmt.Sprintf("CHECK %q", c.Name),
+		func(col tree.Expr) (tree.Expr, error) {
+			// The column is cast to the base type of the domain, against which the
+			// constraint was type-checked.
+			value := &tree.CastExpr{
+				Expr:       col,
+				Type:       desc.DomainConfig.BaseType,
+				SyntaxMode: tree.CastShort,
+			}
+			check, err := schemaexpr.ReplaceDomainValue(expr, value)
+			if err != nil {
+				return nil, err
+			}
+			return &tree.NotExpr{Expr: check}, nil
+		},
+	)
+}
+
+// validateDomainColumns verifies that no row of a table with a column of the
+// given domain type satisfies the filter predicate built for the column. It is
+// used to validate new constraints on the domain against existing data.
+//
+// Only the data visible to the current transaction is validated.
+func (p *planner) validateDomainColumns(
+	ctx context.Context,
+	desc *typedesc.Mutable,
+	constraint string,
+	predicate func(col tree.Expr) (tree.Expr, error),
+) error {
+	domainOID := typedesc.TypeIDToOID(desc.GetID())
+	for _, id := range desc.ReferencingDescriptorIDs {
+		tableDesc, err := p.Descriptors().GetImmutableTableByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return err
+		}
+		if !tableDesc.IsPhysicalTable() {
+			continue
+		}
+		for _, col := range tableDesc.PublicColumns() {
+			if col.GetType().Oid() != domainOID || col.IsVirtual() {
+				continue
+			}
+			colName := tree.Name(col.GetName())
+			filter, err := predicate(&tree.ColumnItem{ColumnName: colName})
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf(`SELECT 1 FROM [%d AS t] WHERE %s LIMIT 1`,
+				tableDesc.GetID(), tree.Serialize(filter))
+			log.Infof(ctx, "validating %s of domain %q with query %q", constraint, desc.GetName(), query)
+			row, err := p.ExecCfg().InternalExecutor.QueryRow(ctx, "validate domain constraint", p.txn, query)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				if constraint == "NOT NULL" {
+					return pgerror.Newf(pgcode.NotNullViolation,
+						"column %s of table %s contains null values",
+						colName, tree.Name(tableDesc.GetName()))
+				}
+				return pgerror.Newf(pgcode.CheckViolation,
+					"column %s of table %s contains values that violate the new constraint %s",
+					colName, tree.Name(tableDesc.GetName()), constraint)
+			}
+		}
+	}
+	return nil
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
@@ -131,6 +131,18 @@ func (p *planner) AlterPrimaryKey(
 		}
 	}
 
+	// The TTL job pages through the primary index in ascending order.
+	if tableDesc.HasRowLevelTTL() {
+		for _, elem := range alterPKNode.Columns {
+			if elem.Direction == tree.Descending {
+				return pgerror.Newf(
+					pgcode.FeatureNotSupported,
+					"non-ascending ordering on PRIMARY KEYs are not supported with row level TTL",
+				)
+			}
+		}
+	}
+
 	// Validate if the end result is the same as the current
 	// primary index, which would mean nothing needs to be modified
 	// here.
@@ -209,6 +209,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					continue
 				}
+				deferrable := d.Deferrability.Deferrable()
+				if deferrable {
+					if err := checkDeferrableUniqueConstraint(d); err != nil {
+						return err
+					}
+				}
 
 				if d.PrimaryKey {
 					// Translate this operation into an ALTER PRIMARY KEY command.
@@ -264,11 +270,16 @@ func (n *alterTableNode) startExec(params runParams) error {
 						return err
 					}
 				}
+				// The index of a DEFERRABLE unique constraint is not unique, and is
+				// left unnamed since the constraint is added with its name below.
 				idx := descpb.IndexDescriptor{
 					Name:             string(d.Name),
-					Unique:           true,
+					Unique:           !deferrable,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
+				if deferrable {
+					idx.Name = ""
+				}
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
@@ -292,7 +303,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 				if err != nil {
 					return err
 				}
-				foundIndex, err := n.tableDesc.FindIndexWithName(string(d.Name))
+				foundIndex, err := n.tableDesc.FindIndexWithName(idx.Name)
 				if err == nil {
 					if foundIndex.Dropped() {
 						return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
@@ -325,6 +336,22 @@ func (n *alterTableNode) startExec(params runParams) error {
 						return err
 					}
 				}
+
+				if deferrable {
+					if err := addUniqueWithoutIndexTableDef(
+						params.ctx,
+						params.EvalContext(),
+						params.SessionData(),
+						d,
+						n.tableDesc,
+						*tn,
+						NonEmptyTable,
+						t.ValidationBehavior,
+						params.p.SemaCtx(),
+					); err != nil {
+						return err
+					}
+				}
 			case *tree.CheckConstraintTableDef:
 				var err error
 				params.p.runWithOptions(resolveFlags{contextDatabaseID: n.tableDesc.ParentID}, func() {
@@ -430,6 +457,31 @@ func (n *alterTableNode) startExec(params runParams) error {
 				// 	return err
 				// }
 
+			case *tree.ExclusionConstraintTableDef:
+				// Postgres does not support NOT VALID exclusion constraints either.
+				if t.ValidationBehavior == tree.ValidationSkip {
+					return pgerror.New(pgcode.FeatureNotSupported,
+						"exclusion constraints cannot be marked NOT VALID")
+				}
+				ec, err := makeExclusionConstraint(
+					params.ctx, params.ExecCfg().Settings, d, n.tableDesc, *tn, params.p.SemaCtx(),
+				)
+				if err != nil {
+					return err
+				}
+				n.tableDesc.ExclusionConstraints = append(n.tableDesc.ExclusionConstraints, ec)
+				// Unlike other constraints, exclusion constraints are validated
+				// against the existing rows in the current transaction, rather than
+				// by the schema changer.
+				if err := validateExclusionConstraintInTxn(
+					params.ctx, params.ExecCfg().InternalExecutorFactory(
+						params.ctx, params.SessionData(),
+					), n.tableDesc, params.EvalContext().Txn, ec.Name,
+				); err != nil {
+					return err
+				}
+				descriptorChanged = true
+
 			default:
 				return errors.AssertionFailedf(
 					"unsupported constraint: %T", t.ConstraintDef)
@@ -668,6 +720,30 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			n.tableDesc.UniqueWithoutIndexConstraints = n.tableDesc.UniqueWithoutIndexConstraints[:sliceIdx]
 
+			// Drop exclusion constraints that reference the column, either in their
+			// columns or in their predicate.
+			sliceIdx = 0
+			for i := range n.tableDesc.ExclusionConstraints {
+				constraint := n.tableDesc.ExclusionConstraints[i]
+				referencesColumn := descpb.ColumnIDs(constraint.ColumnIDs).Contains(colToDrop.GetID())
+				if !referencesColumn && constraint.IsPartial() {
+					expr, err := parser.ParseExpr(constraint.Predicate)
+					if err != nil {
+						return err
+					}
+					colIDs, err := schemaexpr.ExtractColumnIDs(n.tableDesc, expr)
+					if err != nil {
+						return err
+					}
+					referencesColumn = colIDs.Contains(colToDrop.GetID())
+				}
+				if !referencesColumn {
+					n.tableDesc.ExclusionConstraints[sliceIdx] = constraint
+					sliceIdx++
+				}
+			}
+			n.tableDesc.ExclusionConstraints = n.tableDesc.ExclusionConstraints[:sliceIdx]
+
 			// Drop check constraints which reference the column.
 			constraintsToDrop := make([]string, 0, len(n.tableDesc.Checks))
 			constraintInfo, err := n.tableDesc.GetConstraintInfo()
@@ -1576,6 +1652,9 @@ func validateConstraintNameIsNotUsed(
 	case *tree.ForeignKeyConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
+	case *tree.ExclusionConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
 	case *tree.UniqueConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -694,6 +695,8 @@ func (p *planner) checkCanAlterToNewOwner(
 		objType = "table"
 	case *schemadesc.Mutable:
 		objType = "schema"
+	case *funcdesc.Mutable:
+		objType = "function"
 	case *dbdesc.Mutable:
 		objType = "database"
 	default:
@@ -0,0 +1,28 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package catpb
+
+// TTLDefaultExpirationColumnName is the name of the hidden column added to
+// tables with row-level TTL, which holds the time at which each row expires.
+const TTLDefaultExpirationColumnName = "crdb_internal_expiration"
+
+// DefaultTTLDeletionCron is the cron expression on which the TTL job of a
+// table runs if ttl_job_cron is not set.
+const DefaultTTLDeletionCron = "@hourly"
+
+// DeletionCronOrDefault returns the cron expression on which the TTL job
+// runs.
+func (m *RowLevelTTL) DeletionCronOrDefault() string {
+	if m.DeletionCron != "" {
+		return m.DeletionCron
+	}
+	return DefaultTTLDeletionCron
+}
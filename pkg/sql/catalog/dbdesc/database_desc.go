@@ -14,6 +14,7 @@ package dbdesc
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -230,6 +231,30 @@ func (desc *immutable) HasPublicSchemaWithDescriptor() bool {
 	return found
 }
 
+// GetPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) GetPublication(name string) *descpb.DatabaseDescriptor_Publication {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= name
+	})
+	if i < len(desc.Publications) && desc.Publications[i].Name == name {
+		return &desc.Publications[i]
+	}
+	return nil
+}
+
+// GetReplicationSlot implements the DatabaseDescriptor interface.
+func (desc *immutable) GetReplicationSlot(
+	name string,
+) *descpb.DatabaseDescriptor_ReplicationSlot {
+	i := sort.Search(len(desc.ReplicationSlots), func(i int) bool {
+		return desc.ReplicationSlots[i].Name >= name
+	})
+	if i < len(desc.ReplicationSlots) && desc.ReplicationSlots[i].Name == name {
+		return &desc.ReplicationSlots[i]
+	}
+	return nil
+}
+
 // GetNonDroppedSchemaName returns the name in the schema mapping entry for the
 // given ID, if it's not marked as dropped, empty string otherwise.
 func (desc *immutable) GetNonDroppedSchemaName(schemaID descpb.ID) string {
@@ -262,6 +287,27 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
 	}
+
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		vea.Report(catalog.ValidateName(pub.Name, "publication"))
+		if i > 0 && desc.Publications[i-1].Name >= pub.Name {
+			vea.Report(errors.AssertionFailedf(
+				"publications are not sorted by name or contain duplicate %q", pub.Name))
+		}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q for all tables has table IDs %v", pub.Name, pub.TableIDs))
+		}
+	}
+	for i := range desc.ReplicationSlots {
+		slot := &desc.ReplicationSlots[i]
+		vea.Report(catalog.ValidateName(slot.Name, "replication slot"))
+		if i > 0 && desc.ReplicationSlots[i-1].Name >= slot.Name {
+			vea.Report(errors.AssertionFailedf(
+				"replication slots are not sorted by name or contain duplicate %q", slot.Name))
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
@@ -498,6 +544,54 @@ func (desc *Mutable) AddSchemaToDatabase(
 	desc.Schemas[schemaName] = schemaInfo
 }
 
+// AddPublication adds a publication to the database, keeping the publications
+// sorted by name. The caller is responsible for checking that no publication
+// with the same name exists.
+func (desc *Mutable) AddPublication(pub descpb.DatabaseDescriptor_Publication) {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= pub.Name
+	})
+	desc.Publications = append(desc.Publications, descpb.DatabaseDescriptor_Publication{})
+	copy(desc.Publications[i+1:], desc.Publications[i:])
+	desc.Publications[i] = pub
+}
+
+// RemovePublication removes the publication with the given name from the
+// database. It returns false if there is no such publication.
+func (desc *Mutable) RemovePublication(name string) bool {
+	for i := range desc.Publications {
+		if desc.Publications[i].Name == name {
+			desc.Publications = append(desc.Publications[:i], desc.Publications[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AddReplicationSlot adds a replication slot to the database, keeping the
+// slots sorted by name. The caller is responsible for checking that no slot
+// with the same name exists.
+func (desc *Mutable) AddReplicationSlot(slot descpb.DatabaseDescriptor_ReplicationSlot) {
+	i := sort.Search(len(desc.ReplicationSlots), func(i int) bool {
+		return desc.ReplicationSlots[i].Name >= slot.Name
+	})
+	desc.ReplicationSlots = append(desc.ReplicationSlots, descpb.DatabaseDescriptor_ReplicationSlot{})
+	copy(desc.ReplicationSlots[i+1:], desc.ReplicationSlots[i:])
+	desc.ReplicationSlots[i] = slot
+}
+
+// RemoveReplicationSlot removes the replication slot with the given name from
+// the database. It returns false if there is no such slot.
+func (desc *Mutable) RemoveReplicationSlot(name string) bool {
+	for i := range desc.ReplicationSlots {
+		if desc.ReplicationSlots[i].Name == name {
+			desc.ReplicationSlots = append(desc.ReplicationSlots[:i], desc.ReplicationSlots[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // maybeRemoveDroppedSelfEntryFromSchemas removes an entry in the Schemas map corresponding to the
 // database itself which was added due to a bug in prior versions when dropping any user-defined schema.
 // The bug inserted an entry for the database rather than the schema being dropped. This function fixes the
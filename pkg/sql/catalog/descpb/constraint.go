@@ -68,6 +68,23 @@ var ForeignKeyReferenceActionValue = [...]catpb.ForeignKeyAction{
 	tree.Cascade:    catpb.ForeignKeyAction_CASCADE,
 }
 
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]ConstraintDeferrability{
+	tree.ConstraintNotDeferrable:      ConstraintDeferrability_NOT_DEFERRABLE,
+	tree.ConstraintInitiallyImmediate: ConstraintDeferrability_INITIALLY_IMMEDIATE,
+	tree.ConstraintInitiallyDeferred:  ConstraintDeferrability_INITIALLY_DEFERRED,
+}
+
+// ConstraintDeferrabilityType allows the conversion from a
+// ConstraintDeferrability to a tree.ConstraintDeferrability. This should match
+// ConstraintDeferrabilityValue.
+var ConstraintDeferrabilityType = [...]tree.ConstraintDeferrability{
+	ConstraintDeferrability_NOT_DEFERRABLE:      tree.ConstraintNotDeferrable,
+	ConstraintDeferrability_INITIALLY_IMMEDIATE: tree.ConstraintInitiallyImmediate,
+	ConstraintDeferrability_INITIALLY_DEFERRED:  tree.ConstraintInitiallyDeferred,
+}
+
 // ConstraintType is used to identify the type of a constraint.
 type ConstraintType string
 
@@ -80,6 +97,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -104,4 +123,20 @@ type ConstraintDetail struct {
 
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
+
+	// Only populated for Exclusion Constraints.
+	ExclusionConstraint *ExclusionConstraint
+}
+
+// Deferrability returns the deferrability of the constraint. Only foreign key
+// constraints and unique constraints without an index can be deferrable.
+func (c ConstraintDetail) Deferrability() ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrability
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrability
+	default:
+		return ConstraintDeferrability_NOT_DEFERRABLE
+	}
 }
@@ -367,6 +367,29 @@ func (u *UniqueWithoutIndexConstraint) IsPartial() bool {
 	return u.Predicate != ""
 }
 
+// ExclusionConstraintOperators maps the operators supported by exclusion
+// constraints to the corresponding comparison operators.
+var ExclusionConstraintOperators = map[string]tree.ComparisonOperatorSymbol{
+	tree.EQ.String():       tree.EQ,
+	tree.Overlaps.String(): tree.Overlaps,
+}
+
+// IsPartial returns true if the constraint is a partial exclusion constraint.
+func (c *ExclusionConstraint) IsPartial() bool {
+	return c.Predicate != ""
+}
+
+// Operator returns the comparison operator used for the ith column of the
+// constraint.
+func (c *ExclusionConstraint) Operator(i int) (tree.ComparisonOperatorSymbol, error) {
+	op, ok := ExclusionConstraintOperators[c.Operators[i]]
+	if !ok {
+		return 0, errors.AssertionFailedf(
+			"unsupported operator %q in exclusion constraint %q", c.Operators[i], c.Name)
+	}
+	return op, nil
+}
+
 // GetParentID implements the catalog.NameKeyHaver interface.
 func (ni NameInfo) GetParentID() ID {
 	return ni.ParentID
@@ -44,6 +44,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -240,6 +243,12 @@ type DatabaseDescriptor interface {
 	// database.
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
 	HasPublicSchemaWithDescriptor() bool
+	// GetPublication returns the publication of the database with the given
+	// name, or nil if there is none.
+	GetPublication(name string) *descpb.DatabaseDescriptor_Publication
+	// GetReplicationSlot returns the replication slot of the database with the
+	// given name, or nil if there is none.
+	GetReplicationSlot(name string) *descpb.DatabaseDescriptor_ReplicationSlot
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -315,6 +324,11 @@ type TableDescriptor interface {
 	GetPrimaryIndex() Index
 	// IsPartitionAllBy returns whether the table has a PARTITION ALL BY clause.
 	IsPartitionAllBy() bool
+	// HasRowLevelTTL returns whether the table has row-level TTL enabled.
+	HasRowLevelTTL() bool
+	// GetRowLevelTTL returns the row-level TTL configuration of the table, or
+	// nil if the table does not have row-level TTL enabled.
+	GetRowLevelTTL() *catpb.RowLevelTTL
 
 	// PrimaryIndexSpan returns the Span that corresponds to the entire primary
 	// index; can be used for a full table scan.
@@ -558,12 +572,21 @@ type TableDescriptor interface {
 	ForeachDependedOnBy(f func(dep *descpb.TableDescriptor_Reference) error) error
 	// GetDependedOnBy returns information on all relations that depend on this one.
 	GetDependedOnBy() []descpb.TableDescriptor_Reference
+	// GetDependedOnByFunctions returns the IDs of the user-defined functions
+	// whose body references this relation.
+	GetDependedOnByFunctions() []descpb.ID
+	// GetTriggers returns the row-level triggers defined on the table.
+	GetTriggers() []descpb.TriggerDescriptor
 	// GetDependsOn returns the IDs of all relations that this view depends on.
 	// It's only non-nil if IsView is true.
 	GetDependsOn() []descpb.ID
 	// GetDependsOnTypes returns the IDs of all types that this view depends on.
 	// It's only non-nil if IsView is true.
 	GetDependsOnTypes() []descpb.ID
+	// GetDependsOnFunctions returns the IDs of the user-defined functions
+	// called by the query of this view, or by the DEFAULT and ON UPDATE
+	// expressions and CHECK constraints of this table.
+	GetDependsOnFunctions() []descpb.ID
 
 	// GetConstraintInfoWithLookup returns a summary of all constraints on the
 	// table using the provided function to fetch a TableDescriptor from an ID.
@@ -579,6 +602,9 @@ type TableDescriptor interface {
 	// ones on the table descriptor which are being enforced for all writes, and
 	// "inactive" ones queued in the mutations list.
 	AllActiveAndInactiveUniqueWithoutIndexConstraints() []*descpb.UniqueWithoutIndexConstraint
+	// GetExclusionConstraints returns all the exclusion constraints defined on
+	// this table.
+	GetExclusionConstraints() []descpb.ExclusionConstraint
 
 	// ForeachOutboundFK calls f for every outbound foreign key in desc until an
 	// error is returned.
@@ -682,6 +708,12 @@ type TypeDescriptor interface {
 	// or are being removed.
 	TransitioningRegionNames() (catpb.RegionNames, error)
 
+	// The following fields are only valid for domain types.
+
+	// GetDomainConfig returns the definition of a domain type, or nil if the
+	// type is not a domain.
+	GetDomainConfig() *descpb.TypeDescriptor_DomainConfig
+
 	// The following fields are set if the type is an enum or a multi-region enum.
 
 	// NumEnumMembers returns the number of enum members if the type is an
@@ -703,6 +735,9 @@ type TypeDescriptor interface {
 	// GetReferencingDescriptorID returns the ID of the referencing descriptor at
 	// ordinal refOrdinal.
 	GetReferencingDescriptorID(refOrdinal int) descpb.ID
+	// GetReferencingFunctionIDs returns the IDs of the user-defined functions
+	// referencing this type.
+	GetReferencingFunctionIDs() []descpb.ID
 }
 
 // TypeDescriptorResolver is an interface used during hydration of type
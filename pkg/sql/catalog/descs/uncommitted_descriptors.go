@@ -56,6 +56,12 @@ func (u uncommittedDescriptor) GetID() descpb.ID {
 	return u.immutable.GetID()
 }
 
+// DescriptorType returns the type of the descriptor, which allows nstree to
+// index function descriptors only by ID.
+func (u uncommittedDescriptor) DescriptorType() catalog.DescriptorType {
+	return u.immutable.DescriptorType()
+}
+
 // getMutable is how the mutable descriptor should be accessed. It constructs
 // a new descriptor in the case that this descriptor is a cached, in-memory
 // singleton for a system descriptor.
@@ -149,6 +149,19 @@ func AsTypeDescriptor(desc Descriptor) (TypeDescriptor, error) {
 	return typ, nil
 }
 
+// AsFunctionDescriptor tries to cast desc to a FunctionDescriptor.
+// Returns an ErrDescriptorWrongType otherwise.
+func AsFunctionDescriptor(desc Descriptor) (FunctionDescriptor, error) {
+	fn, ok := desc.(FunctionDescriptor)
+	if !ok {
+		if desc == nil {
+			return nil, NewDescriptorTypeError(desc)
+		}
+		return nil, WrapFunctionDescRefErr(desc.GetID(), NewDescriptorTypeError(desc))
+	}
+	return fn, nil
+}
+
 // WrapDatabaseDescRefErr wraps an error pertaining to a database descriptor id.
 func WrapDatabaseDescRefErr(id descpb.ID, err error) error {
 	return errors.Wrapf(err, "referenced database ID %d", errors.Safe(id))
@@ -169,6 +182,12 @@ func WrapTypeDescRefErr(id descpb.ID, err error) error {
 	return errors.Wrapf(err, "referenced type ID %d", errors.Safe(id))
 }
 
+// WrapFunctionDescRefErr wraps an error pertaining to a function descriptor
+// id.
+func WrapFunctionDescRefErr(id descpb.ID, err error) error {
+	return errors.Wrapf(err, "referenced function ID %d", errors.Safe(id))
+}
+
 // NewMutableAccessToVirtualSchemaError is returned when trying to mutably
 // access a virtual schema object.
 func NewMutableAccessToVirtualSchemaError(entry VirtualSchema, object string) error {
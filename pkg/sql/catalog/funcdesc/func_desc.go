@@ -0,0 +1,449 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package funcdesc contains the concrete implementations of
+// catalog.FunctionDescriptor.
+package funcdesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+var _ catalog.FunctionDescriptor = (*immutable)(nil)
+var _ catalog.FunctionDescriptor = (*Mutable)(nil)
+var _ catalog.MutableDescriptor = (*Mutable)(nil)
+
+// immutable wraps a function descriptor and provides methods on it.
+type immutable struct {
+	descpb.FunctionDescriptor
+
+	// isUncommittedVersion is set to true if this descriptor was created from
+	// a copy of a Mutable with an uncommitted version.
+	isUncommittedVersion bool
+}
+
+// Mutable is a mutable reference to a FunctionDescriptor.
+type Mutable struct {
+	immutable
+
+	ClusterVersion *immutable
+
+	// changed represents whether or not the descriptor was changed
+	// after RunPostDeserializationChanges.
+	changed bool
+}
+
+var _ redact.SafeMessager = (*immutable)(nil)
+
+// NewMutableFunctionDescriptor returns a Mutable for a new function with the
+// given properties. The caller is expected to set the body, the volatility and
+// the dependencies of the function.
+func NewMutableFunctionDescriptor(
+	id descpb.ID,
+	parentID descpb.ID,
+	parentSchemaID descpb.ID,
+	name string,
+	args []descpb.FunctionDescriptor_Argument,
+	returnType *types.T,
+	returnSet bool,
+	privs *descpb.PrivilegeDescriptor,
+) Mutable {
+	return Mutable{
+		immutable: immutable{
+			FunctionDescriptor: descpb.FunctionDescriptor{
+				Name:           name,
+				ID:             id,
+				ParentID:       parentID,
+				ParentSchemaID: parentSchemaID,
+				Args:           args,
+				ReturnType:     returnType,
+				ReturnSet:      returnSet,
+				Lang:           descpb.FunctionDescriptor_SQL,
+				Version:        1,
+				Privileges:     privs,
+			},
+		},
+	}
+}
+
+// SafeMessage makes immutable a SafeMessager.
+func (desc *immutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.immutable", desc)
+}
+
+// SafeMessage makes Mutable a SafeMessager.
+func (desc *Mutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Mutable", desc)
+}
+
+func formatSafeMessage(typeName string, desc catalog.FunctionDescriptor) string {
+	var buf redact.StringBuilder
+	buf.Printf(typeName + ": {")
+	catalog.FormatSafeDescriptorProperties(&buf, desc)
+	buf.Printf("}")
+	return buf.String()
+}
+
+// GetDrainingNames implements the Descriptor interface. Functions are not
+// stored in the namespace table and therefore never have draining names.
+func (desc *immutable) GetDrainingNames() []descpb.NameInfo {
+	return nil
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *immutable) IsUncommittedVersion() bool {
+	return desc.isUncommittedVersion
+}
+
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *immutable) GetAuditMode() descpb.TableDescriptor_AuditMode {
+	return descpb.TableDescriptor_DISABLED
+}
+
+// DescriptorType implements the DescriptorProto interface.
+func (desc *immutable) DescriptorType() catalog.DescriptorType {
+	return catalog.Function
+}
+
+// FuncDesc implements the FunctionDescriptor interface.
+func (desc *immutable) FuncDesc() *descpb.FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// ArgTypes implements the FunctionDescriptor interface.
+func (desc *immutable) ArgTypes() []*types.T {
+	ret := make([]*types.T, len(desc.Args))
+	for i := range desc.Args {
+		ret[i] = desc.Args[i].Type
+	}
+	return ret
+}
+
+// Public implements the Descriptor interface.
+func (desc *immutable) Public() bool {
+	return desc.State == descpb.DescriptorState_PUBLIC
+}
+
+// Adding implements the Descriptor interface.
+func (desc *immutable) Adding() bool {
+	return false
+}
+
+// Offline implements the Descriptor interface.
+func (desc *immutable) Offline() bool {
+	return desc.State == descpb.DescriptorState_OFFLINE
+}
+
+// Dropped implements the Descriptor interface.
+func (desc *immutable) Dropped() bool {
+	return desc.State == descpb.DescriptorState_DROP
+}
+
+// DescriptorProto wraps a FunctionDescriptor in a Descriptor.
+func (desc *immutable) DescriptorProto() *descpb.Descriptor {
+	return &descpb.Descriptor{
+		Union: &descpb.Descriptor_Function{
+			Function: &desc.FunctionDescriptor,
+		},
+	}
+}
+
+// ByteSize implements the Descriptor interface.
+func (desc *immutable) ByteSize() int64 {
+	return int64(desc.Size())
+}
+
+// NewBuilder implements the catalog.Descriptor interface.
+func (desc *immutable) NewBuilder() catalog.DescriptorBuilder {
+	return NewBuilder(desc.FuncDesc())
+}
+
+// ValidateSelf implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
+	// Validate local properties of the descriptor.
+	vea.Report(catalog.ValidateName(desc.GetName(), "function"))
+	if desc.GetID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid ID %d", desc.GetID()))
+	}
+	if desc.GetParentID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parentID %d", desc.GetParentID()))
+	}
+	if desc.GetParentSchemaID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parentSchemaID %d", desc.GetParentSchemaID()))
+	}
+	if desc.Privileges == nil {
+		vea.Report(errors.AssertionFailedf("privileges not set"))
+	} else {
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Function))
+	}
+
+	// Validate the signature and the body of the function.
+	for i, arg := range desc.Args {
+		if arg.Type == nil {
+			vea.Report(errors.AssertionFailedf("type of argument %d is not set", i+1))
+		}
+	}
+	if desc.ReturnType == nil {
+		vea.Report(errors.AssertionFailedf("return type is not set"))
+	}
+	if desc.FunctionBody == "" {
+		vea.Report(errors.AssertionFailedf("function body is empty"))
+	}
+	if desc.LeakProof && desc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		vea.Report(errors.AssertionFailedf("leakproof is set for non-immutable function"))
+	}
+
+	// Validate the dependencies.
+	for i, id := range desc.DependsOn {
+		if id == descpb.InvalidID || (i > 0 && desc.DependsOn[i-1] == id) {
+			vea.Report(errors.AssertionFailedf("invalid or duplicate relation dependency %d", id))
+		}
+	}
+	for i, id := range desc.DependsOnTypes {
+		if id == descpb.InvalidID || (i > 0 && desc.DependsOnTypes[i-1] == id) {
+			vea.Report(errors.AssertionFailedf("invalid or duplicate type dependency %d", id))
+		}
+	}
+	for i, id := range desc.DependedOnBy {
+		if id == descpb.InvalidID || (i > 0 && desc.DependedOnBy[i-1] == id) {
+			vea.Report(errors.AssertionFailedf("invalid or duplicate depended-on-by relation %d", id))
+		}
+	}
+}
+
+// GetReferencedDescIDs returns the IDs of all descriptors referenced by
+// this descriptor, including itself.
+func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
+	ret := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID(), desc.GetParentSchemaID())
+	for _, id := range desc.DependsOn {
+		ret.Add(id)
+	}
+	for _, id := range desc.DependsOnTypes {
+		ret.Add(id)
+	}
+	for _, id := range desc.DependedOnBy {
+		ret.Add(id)
+	}
+	return ret, nil
+}
+
+// ValidateCrossReferences implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateCrossReferences(
+	vea catalog.ValidationErrorAccumulator, vdg catalog.ValidationDescGetter,
+) {
+	// Validate the parent database.
+	dbDesc, err := vdg.GetDatabaseDescriptor(desc.GetParentID())
+	if err != nil {
+		vea.Report(err)
+	}
+
+	// Validate the parent schema, which must contain the function.
+	scDesc, err := vdg.GetSchemaDescriptor(desc.GetParentSchemaID())
+	if err != nil {
+		vea.Report(err)
+	} else {
+		if dbDesc != nil && scDesc.GetParentID() != dbDesc.GetID() {
+			vea.Report(errors.AssertionFailedf("parent schema %d is in different database %d",
+				desc.GetParentSchemaID(), scDesc.GetParentID()))
+		}
+		if fn, _ := scDesc.GetFunction(desc.GetName()); !containsID(fn.OverloadIDs, desc.GetID()) {
+			vea.Report(errors.AssertionFailedf("not present in parent schema [%d] functions mapping",
+				desc.GetParentSchemaID()))
+		}
+	}
+
+	// Validate that the relations the function depends on exist and refer back
+	// to the function.
+	for _, id := range desc.DependsOn {
+		tableDesc, err := vdg.GetTableDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !containsID(tableDesc.GetDependedOnByFunctions(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf("depends-on relation %q (%d) has no corresponding depended-on-by back reference",
+				tableDesc.GetName(), id))
+		}
+	}
+
+	// Validate that the types the function depends on exist and refer back to
+	// the function.
+	for _, id := range desc.DependsOnTypes {
+		typeDesc, err := vdg.GetTypeDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !containsID(typeDesc.GetReferencingFunctionIDs(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf("depends-on type %q (%d) has no corresponding referencing-function back reference",
+				typeDesc.GetName(), id))
+		}
+	}
+
+	// Validate that the relations which call the function exist and refer to
+	// it.
+	for _, id := range desc.DependedOnBy {
+		tableDesc, err := vdg.GetTableDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !containsID(tableDesc.GetDependsOnFunctions(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf("depended-on-by relation %q (%d) has no corresponding depends-on-functions reference",
+				tableDesc.GetName(), id))
+		}
+	}
+}
+
+// ValidateTxnCommit implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateTxnCommit(
+	_ catalog.ValidationErrorAccumulator, _ catalog.ValidationDescGetter,
+) {
+	// No-op.
+}
+
+// MaybeIncrementVersion implements the MutableDescriptor interface.
+func (desc *Mutable) MaybeIncrementVersion() {
+	// Already incremented, no-op.
+	if desc.ClusterVersion == nil || desc.Version == desc.ClusterVersion.Version+1 {
+		return
+	}
+	desc.Version++
+	desc.ModificationTime = hlc.Timestamp{}
+}
+
+// SetDrainingNames implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) SetDrainingNames(names []descpb.NameInfo) {}
+
+// AddDrainingName implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) AddDrainingName(name descpb.NameInfo) {}
+
+// OriginalName implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalName() string {
+	if desc.ClusterVersion == nil {
+		return ""
+	}
+	return desc.ClusterVersion.Name
+}
+
+// OriginalID implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalID() descpb.ID {
+	if desc.ClusterVersion == nil {
+		return descpb.InvalidID
+	}
+	return desc.ClusterVersion.ID
+}
+
+// OriginalVersion implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalVersion() descpb.DescriptorVersion {
+	if desc.ClusterVersion == nil {
+		return 0
+	}
+	return desc.ClusterVersion.Version
+}
+
+// ImmutableCopy implements the MutableDescriptor interface.
+func (desc *Mutable) ImmutableCopy() catalog.Descriptor {
+	imm := NewBuilder(desc.FuncDesc()).BuildImmutable()
+	imm.(*immutable).isUncommittedVersion = desc.IsUncommittedVersion()
+	return imm
+}
+
+// IsNew implements the MutableDescriptor interface.
+func (desc *Mutable) IsNew() bool {
+	return desc.ClusterVersion == nil
+}
+
+// SetPublic implements the MutableDescriptor interface.
+func (desc *Mutable) SetPublic() {
+	desc.State = descpb.DescriptorState_PUBLIC
+	desc.OfflineReason = ""
+}
+
+// SetDropped implements the MutableDescriptor interface.
+func (desc *Mutable) SetDropped() {
+	desc.State = descpb.DescriptorState_DROP
+	desc.OfflineReason = ""
+}
+
+// SetOffline implements the MutableDescriptor interface.
+func (desc *Mutable) SetOffline(reason string) {
+	desc.State = descpb.DescriptorState_OFFLINE
+	desc.OfflineReason = reason
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Mutable) IsUncommittedVersion() bool {
+	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
+}
+
+// HasPostDeserializationChanges returns if the MutableDescriptor was changed after running
+// RunPostDeserializationChanges.
+func (desc *Mutable) HasPostDeserializationChanges() bool {
+	return desc.changed
+}
+
+// SetBody sets the body and the dependencies of the function. The
+// dependencies are sorted and deduplicated.
+func (desc *Mutable) SetBody(body string, dependsOn, dependsOnTypes []descpb.ID) {
+	desc.FunctionBody = body
+	desc.DependsOn = sortedUniqueIDs(dependsOn)
+	desc.DependsOnTypes = sortedUniqueIDs(dependsOnTypes)
+}
+
+// AddDependedOnBy adds a reference from the relation with the given ID, which
+// calls the function, if it does not exist yet.
+func (desc *Mutable) AddDependedOnBy(id descpb.ID) {
+	if !containsID(desc.DependedOnBy, id) {
+		desc.DependedOnBy = sortedUniqueIDs(append(desc.DependedOnBy, id))
+	}
+}
+
+// RemoveDependedOnBy removes the reference from the relation with the given
+// ID, if it exists.
+func (desc *Mutable) RemoveDependedOnBy(id descpb.ID) {
+	for i, other := range desc.DependedOnBy {
+		if other == id {
+			desc.DependedOnBy = append(desc.DependedOnBy[:i], desc.DependedOnBy[i+1:]...)
+			return
+		}
+	}
+}
+
+func sortedUniqueIDs(ids []descpb.ID) []descpb.ID {
+	if len(ids) == 0 {
+		return nil
+	}
+	var s catalog.DescriptorIDSet
+	for _, id := range ids {
+		s.Add(id)
+	}
+	return s.Ordered()
+}
+
+func containsID(ids []descpb.ID, id descpb.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
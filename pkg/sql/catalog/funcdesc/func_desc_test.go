@@ -0,0 +1,178 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSafeMessage(t *testing.T) {
+	for _, tc := range []struct {
+		desc catalog.Descriptor
+		exp  string
+	}{
+		{
+			desc: funcdesc.NewBuilder(&descpb.FunctionDescriptor{
+				ID:             52,
+				Version:        1,
+				ParentID:       50,
+				ParentSchemaID: 51,
+				State:          descpb.DescriptorState_OFFLINE,
+				OfflineReason:  "foo",
+			}).BuildImmutable(),
+			exp: "funcdesc.immutable: {ID: 52, Version: 1, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 51, State: OFFLINE, OfflineReason: \"foo\"}",
+		},
+		{
+			desc: funcdesc.NewBuilder(&descpb.FunctionDescriptor{
+				ID:             53,
+				Version:        1,
+				ParentID:       50,
+				ParentSchemaID: 51,
+				State:          descpb.DescriptorState_DROP,
+			}).BuildCreatedMutable(),
+			exp: "funcdesc.Mutable: {ID: 53, Version: 1, IsUncommitted: true, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 51, State: DROP}",
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			redacted := string(redact.Sprint(tc.desc).Redact())
+			require.Equal(t, tc.exp, redacted)
+			{
+				var m map[string]interface{}
+				require.NoError(t, yaml.UnmarshalStrict([]byte(redacted), &m))
+			}
+		})
+	}
+}
+
+func TestValidateFunctionDesc(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	const (
+		dbID     = descpb.ID(50)
+		schemaID = descpb.ID(51)
+		fnID     = descpb.ID(52)
+	)
+	privs := descpb.NewBasePrivilegeDescriptor(security.AdminRoleName())
+	dbDesc := dbdesc.NewBuilder(&descpb.DatabaseDescriptor{
+		ID:         dbID,
+		Name:       "db",
+		Privileges: privs,
+		Schemas: map[string]descpb.DatabaseDescriptor_SchemaInfo{
+			"sc": {ID: schemaID},
+		},
+	}).BuildImmutable()
+	makeSchema := func(fns ...descpb.SchemaDescriptor_Function) catalog.Descriptor {
+		return schemadesc.NewBuilder(&descpb.SchemaDescriptor{
+			ID:         schemaID,
+			ParentID:   dbID,
+			Name:       "sc",
+			Privileges: privs,
+			Functions:  fns,
+		}).BuildImmutable()
+	}
+	makeFunction := func(mutate func(desc *descpb.FunctionDescriptor)) descpb.FunctionDescriptor {
+		desc := descpb.FunctionDescriptor{
+			ID:             fnID,
+			ParentID:       dbID,
+			ParentSchemaID: schemaID,
+			Name:           "f",
+			Args:           []descpb.FunctionDescriptor_Argument{{Name: "a", Type: types.Int}},
+			ReturnType:     types.Int,
+			FunctionBody:   "SELECT a + 1",
+			Volatility:     descpb.FunctionDescriptor_IMMUTABLE,
+			Privileges:     descpb.NewBasePrivilegeDescriptor(security.AdminRoleName()),
+		}
+		if mutate != nil {
+			mutate(&desc)
+		}
+		return desc
+	}
+
+	for i, test := range []struct {
+		err    string
+		desc   descpb.FunctionDescriptor
+		schema catalog.Descriptor
+	}{
+		{ // 0
+			desc:   makeFunction(nil),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "f", OverloadIDs: []descpb.ID{fnID}}),
+		},
+		{ // 1
+			err:    `not present in parent schema [51] functions mapping`,
+			desc:   makeFunction(nil),
+			schema: makeSchema(),
+		},
+		{ // 2
+			err:    `not present in parent schema [51] functions mapping`,
+			desc:   makeFunction(nil),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "g", OverloadIDs: []descpb.ID{fnID}}),
+		},
+		{ // 3
+			err: `function body is empty`,
+			desc: makeFunction(func(desc *descpb.FunctionDescriptor) {
+				desc.FunctionBody = ""
+			}),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "f", OverloadIDs: []descpb.ID{fnID}}),
+		},
+		{ // 4
+			err: `leakproof is set for non-immutable function`,
+			desc: makeFunction(func(desc *descpb.FunctionDescriptor) {
+				desc.Volatility = descpb.FunctionDescriptor_STABLE
+				desc.LeakProof = true
+			}),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "f", OverloadIDs: []descpb.ID{fnID}}),
+		},
+		{ // 5
+			err: `referenced table ID 100: referenced descriptor not found`,
+			desc: makeFunction(func(desc *descpb.FunctionDescriptor) {
+				desc.DependsOn = []descpb.ID{100}
+			}),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "f", OverloadIDs: []descpb.ID{fnID}}),
+		},
+		{ // 6
+			err: `referenced table ID 100: referenced descriptor not found`,
+			desc: makeFunction(func(desc *descpb.FunctionDescriptor) {
+				desc.DependedOnBy = []descpb.ID{100}
+			}),
+			schema: makeSchema(descpb.SchemaDescriptor_Function{Name: "f", OverloadIDs: []descpb.ID{fnID}}),
+		},
+	} {
+		descs := catalog.MakeMapDescGetter()
+		desc := funcdesc.NewBuilder(&test.desc).BuildImmutable()
+		descs.Descriptors[desc.GetID()] = desc
+		descs.Descriptors[dbID] = dbDesc
+		descs.Descriptors[schemaID] = test.schema
+		expectedErr := fmt.Sprintf("%s %q (%d): %s", desc.DescriptorType(), desc.GetName(), desc.GetID(), test.err)
+		results := catalog.Validate(ctx, descs, catalog.NoValidationTelemetry, catalog.ValidationLevelCrossReferences, desc)
+		if err := results.CombinedError(); err == nil {
+			if test.err != "" {
+				t.Errorf("%d: expected \"%s\", but found success: %+v", i, expectedErr, test.desc)
+			}
+		} else if expectedErr != err.Error() {
+			t.Errorf("%d: expected \"%s\", but found \"%s\"", i, expectedErr, err.Error())
+		}
+	}
+}
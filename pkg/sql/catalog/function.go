@@ -0,0 +1,54 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package catalog
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// FunctionDescriptor is an interface around the function descriptor types.
+// Each overload of a user-defined function has its own descriptor.
+type FunctionDescriptor interface {
+	Descriptor
+
+	// FuncDesc returns the underlying protocol buffer.
+	FuncDesc() *descpb.FunctionDescriptor
+
+	// GetArgs returns the arguments of the function, in order.
+	GetArgs() []descpb.FunctionDescriptor_Argument
+	// GetReturnType returns the type returned by the function, or the type of
+	// the rows it returns if GetReturnSet is true.
+	GetReturnType() *types.T
+	// GetReturnSet returns true if the function returns a set of rows.
+	GetReturnSet() bool
+	// GetFunctionBody returns the body of the function.
+	GetFunctionBody() string
+	// GetVolatility returns the volatility of the function.
+	GetVolatility() descpb.FunctionDescriptor_Volatility
+	// GetLeakProof returns true if the function was declared as LEAKPROOF.
+	GetLeakProof() bool
+	// GetNullInputBehavior returns how the function handles NULL arguments.
+	GetNullInputBehavior() descpb.FunctionDescriptor_NullInputBehavior
+	// GetDependsOn returns the IDs of the relations referenced by the body of
+	// the function.
+	GetDependsOn() []descpb.ID
+	// GetDependsOnTypes returns the IDs of the user-defined types referenced by
+	// the function.
+	GetDependsOnTypes() []descpb.ID
+	// GetDependedOnBy returns the IDs of the relations which call the function
+	// in their view query, DEFAULT or ON UPDATE expressions or CHECK
+	// constraints.
+	GetDependedOnBy() []descpb.ID
+
+	// ArgTypes returns the types of the arguments of the function.
+	ArgTypes() []*types.T
+}
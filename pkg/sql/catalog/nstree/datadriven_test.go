@@ -27,6 +27,7 @@ const (
 	argID
 	argName
 	argStopAfter
+	argFunction
 )
 
 type args struct {
@@ -34,6 +35,7 @@ type args struct {
 	parentID, parentSchemaID, id descpb.ID
 	name                         string
 	stopAfter                    int
+	function                     bool
 }
 
 func parseArgs(t *testing.T, d *datadriven.TestData, required, allowed argType) args {
@@ -108,6 +110,13 @@ var (
 			"stop-after",
 			setIntFunc(func(a *args) *int { return &a.stopAfter }),
 		},
+		argFunction: {
+			"function",
+			func(t *testing.T, d *datadriven.TestData, key string, a *args) bool {
+				a.function = true
+				return true
+			},
+		},
 	}
 	argKeys = func() map[string]struct{} {
 		m := make(map[string]struct{}, len(argParser))
@@ -33,13 +33,17 @@ type Map struct {
 type EntryIterator func(entry catalog.NameEntry) error
 
 // Upsert adds the descriptor to the tree. If any descriptor exists in the
-// tree with the same name or id, it will be removed.
+// tree with the same name or id, it will be removed. Function descriptors are
+// only indexed by id: they have no namespace entry and the overloads of a
+// function share the same name.
 func (dt *Map) Upsert(d catalog.NameEntry) {
 	dt.maybeInitialize()
-	if replaced := dt.byName.upsert(d); replaced != nil {
-		dt.byID.delete(replaced.GetID())
+	if !isFunction(d) {
+		if replaced := dt.byName.upsert(d); replaced != nil {
+			dt.byID.delete(replaced.GetID())
+		}
 	}
-	if replaced := dt.byID.upsert(d); replaced != nil {
+	if replaced := dt.byID.upsert(d); replaced != nil && !isFunction(replaced) {
 		dt.byName.delete(replaced)
 	}
 }
@@ -49,12 +53,20 @@ func (dt *Map) Upsert(d catalog.NameEntry) {
 func (dt *Map) Remove(id descpb.ID) catalog.NameEntry {
 	dt.maybeInitialize()
 	if d := dt.byID.delete(id); d != nil {
-		dt.byName.delete(d)
+		if !isFunction(d) {
+			dt.byName.delete(d)
+		}
 		return d
 	}
 	return nil
 }
 
+// isFunction returns true if the entry is a function descriptor.
+func isFunction(d catalog.NameEntry) bool {
+	typed, ok := d.(interface{ DescriptorType() catalog.DescriptorType })
+	return ok && typed.DescriptorType() == catalog.Function
+}
+
 // GetByID gets a descriptor from the tree by id.
 func (dt *Map) GetByID(id descpb.ID) catalog.NameEntry {
 	if !dt.initialized() {
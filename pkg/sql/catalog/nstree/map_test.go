@@ -25,8 +25,9 @@ import (
 // TestMapDataDriven tests the Map using a data-driven
 // exposition format. The tests support the following commands:
 //
-//   add [parent-id=...] [parent-schema-id=...] name=... id=...
+//   add [parent-id=...] [parent-schema-id=...] name=... id=... [function]
 //     Calls the add method with an entry matching the spec.
+//     If function is specified, the entry is a function descriptor.
 //     Prints the entry.
 //
 //   remove id=...
@@ -63,8 +64,11 @@ func TestMapDataDriven(t *testing.T) {
 func testMapDataDriven(t *testing.T, d *datadriven.TestData, tr *Map) string {
 	switch d.Cmd {
 	case "add":
-		a := parseArgs(t, d, argID|argName, argParentID|argParentSchemaID)
-		entry := makeNameEntryFromArgs(a)
+		a := parseArgs(t, d, argID|argName, argParentID|argParentSchemaID|argFunction)
+		var entry catalog.NameEntry = makeNameEntryFromArgs(a)
+		if a.function {
+			entry = functionEntry{entry.(nameEntry)}
+		}
 		tr.Upsert(entry)
 		return formatNameEntry(entry)
 	case "get-by-id":
@@ -123,6 +127,12 @@ type nameEntry struct {
 
 func (ne nameEntry) GetID() descpb.ID { return ne.id }
 
+type functionEntry struct {
+	nameEntry
+}
+
+func (fe functionEntry) DescriptorType() catalog.DescriptorType { return catalog.Function }
+
 func makeNameEntryFromArgs(a args) nameEntry {
 	ne := nameEntry{}
 	ne.ParentID = a.parentID
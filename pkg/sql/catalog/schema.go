@@ -26,6 +26,14 @@ type SchemaDescriptor interface {
 	// GetDefaultPrivilegeDescriptor returns the default privileges for this
 	// database.
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
+
+	// GetFunction returns the overloads of the user-defined function with the
+	// given name, if it exists in the schema.
+	GetFunction(name string) (descpb.SchemaDescriptor_Function, bool)
+
+	// ForEachFunction calls f for each user-defined function in the schema, in
+	// order of name. iterutil.StopIteration is supported.
+	ForEachFunction(f func(fn descpb.SchemaDescriptor_Function) error) error
 }
 
 // ResolvedSchemaKind is an enum that represents what kind of schema
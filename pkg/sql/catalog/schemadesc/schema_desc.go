@@ -12,6 +12,7 @@ package schemadesc
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -168,6 +170,17 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		vea.Report(fmt.Errorf("invalid schema ID %d", desc.GetID()))
 	}
 
+	// Validate the functions mapping.
+	for i, fn := range desc.Functions {
+		if i > 0 && desc.Functions[i-1].Name >= fn.Name {
+			vea.Report(errors.AssertionFailedf("functions are not sorted by name or have duplicates at %q",
+				errors.Safe(fn.Name)))
+		}
+		if len(fn.OverloadIDs) == 0 {
+			vea.Report(errors.AssertionFailedf("function %q has no overloads", errors.Safe(fn.Name)))
+		}
+	}
+
 	// Validate the privilege descriptor.
 	vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Schema))
 	// The DefaultPrivilegeDescriptor may be nil.
@@ -180,7 +193,13 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
 // this descriptor, including itself.
 func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
-	return catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID()), nil
+	ret := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID())
+	for _, fn := range desc.Functions {
+		for _, id := range fn.OverloadIDs {
+			ret.Add(id)
+		}
+	}
+	return ret, nil
 }
 
 // ValidateCrossReferences implements the catalog.Descriptor interface.
@@ -223,6 +242,22 @@ func (desc *immutable) ValidateCrossReferences(
 		vea.Report(errors.AssertionFailedf("not present in parent database [%d] schemas mapping",
 			desc.GetParentID()))
 	}
+
+	// Check that the functions exist and reside in this schema.
+	for _, fn := range desc.Functions {
+		for _, id := range fn.OverloadIDs {
+			fnDesc, err := vdg.GetFunctionDescriptor(id)
+			if err != nil {
+				vea.Report(err)
+				continue
+			}
+			if fnDesc.GetParentSchemaID() != desc.GetID() || fnDesc.GetName() != fn.Name {
+				vea.Report(errors.AssertionFailedf("function %q [%d] is present in functions mapping but "+
+					"refers to function %q in schema [%d]",
+					errors.Safe(fn.Name), id, errors.Safe(fnDesc.GetName()), fnDesc.GetParentSchemaID()))
+			}
+		}
+	}
 }
 
 // ValidateTxnCommit implements the catalog.Descriptor interface.
@@ -241,6 +276,30 @@ func (desc *immutable) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeD
 	return catprivilege.MakeDefaultPrivileges(defaultPrivilegeDescriptor)
 }
 
+// GetFunction implements the SchemaDescriptor interface.
+func (desc *immutable) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	i := sort.Search(len(desc.Functions), func(i int) bool {
+		return desc.Functions[i].Name >= name
+	})
+	if i < len(desc.Functions) && desc.Functions[i].Name == name {
+		return desc.Functions[i], true
+	}
+	return descpb.SchemaDescriptor_Function{}, false
+}
+
+// ForEachFunction implements the SchemaDescriptor interface.
+func (desc *immutable) ForEachFunction(f func(fn descpb.SchemaDescriptor_Function) error) error {
+	for _, fn := range desc.Functions {
+		if err := f(fn); err != nil {
+			if iterutil.Done(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // MaybeIncrementVersion implements the MutableDescriptor interface.
 func (desc *Mutable) MaybeIncrementVersion() {
 	// Already incremented, no-op.
@@ -305,6 +364,46 @@ func (desc *Mutable) SetOffline(reason string) {
 	desc.OfflineReason = reason
 }
 
+// AddFunction adds an overload of the function with the given name to the
+// functions of the schema.
+func (desc *Mutable) AddFunction(name string, id descpb.ID) {
+	i := sort.Search(len(desc.Functions), func(i int) bool {
+		return desc.Functions[i].Name >= name
+	})
+	if i < len(desc.Functions) && desc.Functions[i].Name == name {
+		desc.Functions[i].OverloadIDs = append(desc.Functions[i].OverloadIDs, id)
+		return
+	}
+	desc.Functions = append(desc.Functions, descpb.SchemaDescriptor_Function{})
+	copy(desc.Functions[i+1:], desc.Functions[i:])
+	desc.Functions[i] = descpb.SchemaDescriptor_Function{
+		Name:        name,
+		OverloadIDs: []descpb.ID{id},
+	}
+}
+
+// RemoveFunction removes an overload of the function with the given name from
+// the functions of the schema. The function is removed altogether once it
+// has no overloads left.
+func (desc *Mutable) RemoveFunction(name string, id descpb.ID) {
+	for i := range desc.Functions {
+		fn := &desc.Functions[i]
+		if fn.Name != name {
+			continue
+		}
+		for j, overloadID := range fn.OverloadIDs {
+			if overloadID == id {
+				fn.OverloadIDs = append(fn.OverloadIDs[:j], fn.OverloadIDs[j+1:]...)
+				break
+			}
+		}
+		if len(fn.OverloadIDs) == 0 {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+		}
+		return
+	}
+}
+
 // SetName sets the name of the schema.
 func (desc *Mutable) SetName(name string) {
 	desc.Name = name
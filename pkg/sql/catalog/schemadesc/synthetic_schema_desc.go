@@ -112,3 +112,14 @@ func (p synthetic) SchemaDesc() *descpb.SchemaDescriptor {
 func (p synthetic) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeDescriptor {
 	return catprivilege.MakeDefaultPrivileges(catprivilege.MakeDefaultPrivilegeDescriptor(descpb.DefaultPrivilegeDescriptor_SCHEMA))
 }
+
+// GetFunction implements the SchemaDescriptor interface. Synthetic schemas
+// never contain user-defined functions.
+func (p synthetic) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	return descpb.SchemaDescriptor_Function{}, false
+}
+
+// ForEachFunction implements the SchemaDescriptor interface.
+func (p synthetic) ForEachFunction(f func(fn descpb.SchemaDescriptor_Function) error) error {
+	return nil
+}
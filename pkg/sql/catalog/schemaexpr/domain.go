@@ -0,0 +1,65 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain refer
+// to the value being checked.
+const DomainValueName tree.Name = "value"
+
+// ValidateDomainCheckExpr verifies that the CHECK constraint expression of a
+// domain with the given base type is valid. The expression must be boolean, may
+// only refer to the value being checked, and may only contain immutable
+// functions. The serialized type-checked expression is returned.
+func ValidateDomainCheckExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (string, error) {
+	replacedExpr, err := ReplaceDomainValue(expr, &dummyColumn{typ: baseType, name: DomainValueName})
+	if err != nil {
+		return "", err
+	}
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx, replacedExpr, types.Bool, "domain CHECK constraints", semaCtx, tree.VolatilityImmutable,
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
+
+// ReplaceDomainValue replaces the references to VALUE in the CHECK constraint
+// expression of a domain with the given expression. Any other variable
+// reference results in an error.
+func ReplaceDomainValue(expr tree.Expr, value tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return true, expr, nil
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+		if c, ok := v.(*tree.ColumnItem); ok && c.TableName == nil && c.ColumnName == DomainValueName {
+			return false, value, nil
+		}
+		return false, nil, pgerror.New(pgcode.InvalidColumnReference,
+			"cannot use column reference in domain check constraint")
+	})
+}
@@ -649,6 +649,19 @@ CREATE TABLE system.span_configurations (
     CONSTRAINT check_bounds CHECK (start_key < end_key),
     FAMILY "primary" (start_key, end_key, config)
 )`
+
+	// NotificationsTableSchema stores the notifications sent by NOTIFY. Every
+	// node tails the table with a rangefeed to deliver them to the sessions
+	// listening on their channel.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+    timestamp  TIMESTAMP NOT NULL DEFAULT now(),
+    unique_id  INT8 NOT NULL DEFAULT unique_rowid(),
+    channel    STRING NOT NULL,
+    payload    STRING NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (timestamp, unique_id),
+    FAMILY "primary" (timestamp, unique_id, channel, payload)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2287,6 +2300,36 @@ var (
 			}}
 		},
 	)
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = registerSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			keys.NotificationsTableID,
+			[]descpb.ColumnDescriptor{
+				{Name: "timestamp", ID: 1, Type: types.Timestamp, DefaultExpr: &nowString},
+				{Name: "unique_id", ID: 2, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "channel", ID: 3, Type: types.String},
+				{Name: "payload", ID: 4, Type: types.String},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"timestamp", "unique_id", "channel", "payload"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"timestamp", "unique_id"},
+				KeyColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1, 2},
+			},
+		))
 )
 
 type descRefByName struct {
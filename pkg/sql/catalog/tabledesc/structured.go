@@ -118,6 +118,11 @@ func (desc *wrapper) IsPartitionAllBy() bool {
 	return desc.PartitionAllBy
 }
 
+// HasRowLevelTTL implements the TableDescriptor interface.
+func (desc *wrapper) HasRowLevelTTL() bool {
+	return desc.RowLevelTTL != nil
+}
+
 // GetParentSchemaID returns the ParentSchemaID if the descriptor has
 // one. If the descriptor was created before the field was added, then the
 // descriptor belongs to a table under the `public` physical schema. The static
@@ -1395,6 +1400,18 @@ func (desc *Mutable) DropConstraint(
 			}
 		}
 
+	case descpb.ConstraintTypeExclusion:
+		// Exclusion constraints are never queued in mutations, so they can be
+		// removed from the descriptor immediately.
+		for i := range desc.ExclusionConstraints {
+			if desc.ExclusionConstraints[i].Name == name {
+				desc.ExclusionConstraints = append(
+					desc.ExclusionConstraints[:i], desc.ExclusionConstraints[i+1:]...,
+				)
+				return nil
+			}
+		}
+
 	case descpb.ConstraintTypeFK:
 		if detail.FK.Validity == descpb.ConstraintValidity_Validating {
 			return unimplemented.NewWithIssueDetailf(42844,
@@ -1530,6 +1547,10 @@ func (desc *Mutable) RenameConstraint(
 		detail.CheckConstraint.Name = newName
 		return nil
 
+	case descpb.ConstraintTypeExclusion:
+		detail.ExclusionConstraint.Name = newName
+		return nil
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(oldName))
@@ -341,6 +341,23 @@ func (desc *wrapper) collectConstraintInfo(
 		info[uc.Name] = detail
 	}
 
+	for i := range desc.ExclusionConstraints {
+		ec := &desc.ExclusionConstraints[i]
+		if _, ok := info[ec.Name]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate constraint name: %q", ec.Name)
+		}
+		detail := descpb.ConstraintDetail{Kind: descpb.ConstraintTypeExclusion}
+		detail.Unvalidated = ec.Validity != descpb.ConstraintValidity_Validated
+		var err error
+		detail.Columns, err = desc.NamesForColumnIDs(ec.ColumnIDs)
+		if err != nil {
+			return nil, err
+		}
+		detail.ExclusionConstraint = ec
+		info[ec.Name] = detail
+	}
+
 	fks := desc.AllActiveAndInactiveForeignKeys()
 	for _, fk := range fks {
 		if _, ok := info[fk.Name]; ok {
@@ -614,6 +631,15 @@ func RenameColumnInTable(
 		}
 	}
 
+	// Rename the column in partial exclusion constraint predicates.
+	for i := range tableDesc.ExclusionConstraints {
+		if ec := &tableDesc.ExclusionConstraints[i]; ec.Predicate != "" {
+			if err := renameInExpr(&ec.Predicate); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Rename the column in partial idx predicates.
 	for _, idx := range tableDesc.PublicNonPrimaryIndexes() {
 		if idx.IsPartial() {
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
@@ -112,6 +113,15 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, ref := range desc.GetDependedOnBy() {
 		ids.Add(ref.ID)
 	}
+	for _, id := range desc.GetDependedOnByFunctions() {
+		ids.Add(id)
+	}
+	for _, id := range desc.GetDependsOnFunctions() {
+		ids.Add(id)
+	}
+	for i := range desc.Triggers {
+		ids.Add(desc.Triggers[i].FunctionID)
+	}
 	// Add sequence dependencies
 	return ids, nil
 }
@@ -167,6 +177,39 @@ func (desc *wrapper) ValidateCrossReferences(
 		vea.Report(desc.validateInboundFK(&desc.InboundFKs[i], vdg))
 	}
 
+	// Check that the functions depending on this table exist.
+	for _, id := range desc.GetDependedOnByFunctions() {
+		_, err := vdg.GetFunctionDescriptor(id)
+		vea.Report(err)
+	}
+
+	// Check that the functions called by this relation exist and refer back to
+	// it.
+	for _, id := range desc.GetDependsOnFunctions() {
+		fnDesc, err := vdg.GetFunctionDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		found := false
+		for _, other := range fnDesc.GetDependedOnBy() {
+			found = found || other == desc.GetID()
+		}
+		if !found {
+			vea.Report(errors.AssertionFailedf("depends-on function %q (%d) has no corresponding depended-on-by back reference",
+				fnDesc.GetName(), id))
+		}
+	}
+
+	// Check that the functions invoked by triggers exist.
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if _, err := vdg.GetFunctionDescriptor(trigger.FunctionID); err != nil {
+			vea.Report(errors.Wrapf(err, "invalid trigger %q: missing function=%d",
+				trigger.Name, trigger.FunctionID))
+		}
+	}
+
 	// Check partitioning is correctly set.
 	// We only check these for active indexes, as inactive indexes may be in the
 	// process of being backfilled without PartitionAllBy.
@@ -184,6 +227,63 @@ func (desc *wrapper) ValidateCrossReferences(
 	}
 }
 
+// validateRowLevelTTL validates that the table's row-level TTL configuration,
+// if any, is well-formed, that the expiration column exists, and that the
+// primary key is ascending, as the TTL job pages through the primary index in
+// ascending order.
+func (desc *wrapper) validateRowLevelTTL() error {
+	ttl := desc.GetRowLevelTTL()
+	if ttl == nil {
+		return nil
+	}
+	if ttl.DurationExpr == "" {
+		return errors.AssertionFailedf("row-level TTL duration expression must be set")
+	}
+	col, err := desc.FindColumnWithName(catpb.TTLDefaultExpirationColumnName)
+	if err != nil {
+		return errors.Wrapf(err, "expected column %s for row-level TTL", catpb.TTLDefaultExpirationColumnName)
+	}
+	if col.GetType().Family() != types.TimestampTZFamily {
+		return errors.AssertionFailedf(
+			"expected column %s for row-level TTL to be of type %s, found %s",
+			catpb.TTLDefaultExpirationColumnName, types.TimestampTZ.SQLString(), col.GetType().SQLString(),
+		)
+	}
+	pk := desc.GetPrimaryIndex()
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		if pk.GetKeyColumnDirection(i) != descpb.IndexDescriptor_ASC {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"non-ascending ordering on PRIMARY KEYs are not supported with row level TTL",
+			)
+		}
+	}
+	return nil
+}
+
+// validateTriggers validates that the names of the table's triggers are unique
+// and that each trigger fires for at least one event.
+func (desc *wrapper) validateTriggers() error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if trigger.Name == "" {
+			return errors.Newf("empty trigger name")
+		}
+		if _, ok := names[trigger.Name]; ok {
+			return errors.Newf("duplicate trigger name: %q", trigger.Name)
+		}
+		names[trigger.Name] = struct{}{}
+		if len(trigger.Events) == 0 {
+			return errors.AssertionFailedf("trigger %q has no events", trigger.Name)
+		}
+		if trigger.FunctionID == descpb.InvalidID {
+			return errors.AssertionFailedf("trigger %q has no function", trigger.Name)
+		}
+	}
+	return nil
+}
+
 func (desc *wrapper) validateOutboundFK(
 	fk *descpb.ForeignKeyConstraint, vdg catalog.ValidationDescGetter,
 ) error {
@@ -372,8 +472,11 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnIDs),
 			desc.validateCheckConstraints(columnIDs),
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
+			desc.validateExclusionConstraints(columnIDs),
 			desc.validateTableIndexes(columnNames),
 			desc.validatePartitioning(),
+			desc.validateRowLevelTTL(),
+			desc.validateTriggers(),
 		}
 		hasErrs := false
 		for _, err := range newErrs {
@@ -756,6 +859,67 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that exclusion constraints are well
+// formed. Checks include validating the column IDs, operators and column
+// names.
+func (desc *wrapper) validateExclusionConstraints(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	for i := range desc.ExclusionConstraints {
+		c := &desc.ExclusionConstraints[i]
+		if err := catalog.ValidateName(c.Name, "exclusion constraint"); err != nil {
+			return err
+		}
+
+		// Verify that the table ID is valid.
+		if c.TableID != desc.ID {
+			return errors.Newf(
+				"TableID mismatch for exclusion constraint %q: \"%d\" doesn't match descriptor: \"%d\"",
+				c.Name, c.TableID, desc.ID,
+			)
+		}
+
+		if len(c.ColumnIDs) == 0 || len(c.Operators) != len(c.ColumnIDs) {
+			return errors.Newf(
+				"exclusion constraint %q has %d columns and %d operators",
+				c.Name, len(c.ColumnIDs), len(c.Operators),
+			)
+		}
+
+		// Verify that the constraint's column IDs and operators are valid.
+		for j, colID := range c.ColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return errors.Newf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.Name, colID,
+				)
+			}
+			if _, err := c.Operator(j); err != nil {
+				return err
+			}
+		}
+
+		if c.IsPartial() {
+			expr, err := parser.ParseExpr(c.Predicate)
+			if err != nil {
+				return err
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf(
+					"partial exclusion constraint %q refers to unknown columns in predicate: %s",
+					c.Name,
+					c.Predicate,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -309,6 +309,11 @@ func (v TableImplicitRecordType) GetKind() descpb.TypeDescriptor_Kind {
 	return descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE
 }
 
+// GetDomainConfig implements the TypeDescriptorInterface.
+func (v TableImplicitRecordType) GetDomainConfig() *descpb.TypeDescriptor_DomainConfig {
+	return nil
+}
+
 // NumEnumMembers implements the TypeDescriptorInterface.
 func (v TableImplicitRecordType) NumEnumMembers() int { return 0 }
 
@@ -327,6 +332,9 @@ func (v TableImplicitRecordType) NumReferencingDescriptors() int { return 0 }
 // GetReferencingDescriptorID implements the TypeDescriptorInterface.
 func (v TableImplicitRecordType) GetReferencingDescriptorID(_ int) descpb.ID { return 0 }
 
+// GetReferencingFunctionIDs implements the TypeDescriptorInterface.
+func (v TableImplicitRecordType) GetReferencingFunctionIDs() []descpb.ID { return nil }
+
 func (v TableImplicitRecordType) panicNotSupported(message string) {
 	panic(errors.AssertionFailedf("implicit table record type for table %q: not supported: %s", v.GetName(), message))
 }
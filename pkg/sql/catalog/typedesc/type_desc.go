@@ -444,6 +444,28 @@ func (desc *Mutable) RemoveReferencingDescriptorID(remove descpb.ID) {
 	}
 }
 
+// AddReferencingFunctionID adds a new referencing function ID to the
+// TypeDescriptor. It ensures that duplicates are not added.
+func (desc *Mutable) AddReferencingFunctionID(new descpb.ID) {
+	for _, id := range desc.ReferencingFunctionIDs {
+		if new == id {
+			return
+		}
+	}
+	desc.ReferencingFunctionIDs = append(desc.ReferencingFunctionIDs, new)
+}
+
+// RemoveReferencingFunctionID removes the desired referencing function ID
+// from the TypeDescriptor. It has no effect if the requested ID is not present.
+func (desc *Mutable) RemoveReferencingFunctionID(remove descpb.ID) {
+	for i, id := range desc.ReferencingFunctionIDs {
+		if id == remove {
+			desc.ReferencingFunctionIDs = append(desc.ReferencingFunctionIDs[:i], desc.ReferencingFunctionIDs[i+1:]...)
+			return
+		}
+	}
+}
+
 // SetParentSchemaID sets the SchemaID of the type.
 func (desc *Mutable) SetParentSchemaID(schemaID descpb.ID) {
 	desc.ParentSchemaID = schemaID
@@ -522,6 +544,20 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.GetArrayTypeID() != descpb.InvalidID {
 			vea.Report(errors.AssertionFailedf("ALIAS type desc has array type ID %d", desc.GetArrayTypeID()))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Type))
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if len(desc.EnumMembers) > 0 {
+			vea.Report(errors.AssertionFailedf("found enum members on %s type desc", desc.Kind.String()))
+		}
+		if desc.DomainConfig == nil || desc.DomainConfig.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+		} else if desc.DomainConfig.BaseType.UserDefined() {
+			vea.Report(errors.AssertionFailedf(
+				"DOMAIN type desc has user defined base type %s", desc.DomainConfig.BaseType.SQLString()))
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -575,6 +611,9 @@ func (desc *immutable) validateEnumMembers(vea catalog.ValidationErrorAccumulato
 // this descriptor, including itself.
 func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	ids := catalog.MakeDescriptorIDSet(desc.GetReferencingDescriptorIDs()...)
+	for _, id := range desc.GetReferencingFunctionIDs() {
+		ids.Add(id)
+	}
 	ids.Add(desc.GetParentID())
 	// TODO(richardjcai): Remove logic for keys.PublicSchemaID in 22.2.
 	if desc.GetParentSchemaID() != keys.PublicSchemaID {
@@ -617,7 +656,7 @@ func (desc *immutable) ValidateCrossReferences(
 
 	// Validate that the referenced types exist.
 	switch desc.GetKind() {
-	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM, descpb.TypeDescriptor_DOMAIN:
 		// Ensure that the referenced array type exists.
 		if _, err := vdg.GetTypeDescriptor(desc.GetArrayTypeID()); err != nil {
 			vea.Report(errors.Wrapf(err, "arrayTypeID %d does not exist for %q", desc.GetArrayTypeID(), desc.GetKind()))
@@ -646,6 +685,17 @@ func (desc *immutable) ValidateCrossReferences(
 				"referencing table %d was dropped without dependency unlinking", id))
 		}
 	}
+	for _, id := range desc.GetReferencingFunctionIDs() {
+		fnDesc, err := vdg.GetFunctionDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if fnDesc.Dropped() {
+			vea.Report(errors.AssertionFailedf(
+				"referencing function %d was dropped without dependency unlinking", id))
+		}
+	}
 }
 
 func (desc *immutable) validateMultiRegion(
@@ -738,6 +788,14 @@ func (desc *immutable) MakeTypesT(
 			return nil, err
 		}
 		return desc.Alias, nil
+	case descpb.TypeDescriptor_DOMAIN:
+		typ := types.MakeDomain(
+			TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), desc.DomainConfig.BaseType,
+		)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	default:
 		return nil, errors.AssertionFailedf("unknown type kind %s", t.String())
 	}
@@ -816,6 +874,24 @@ func (desc *immutable) HydrateTypeInfoWithName(
 			IsMemberReadOnly:        desc.readOnlyMembers,
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if !typ.IsDomain() {
+			return errors.New("cannot hydrate a non-domain type with a domain type descriptor")
+		}
+		cfg := desc.DomainConfig
+		checks := make([]types.DomainCheckConstraint, len(cfg.CheckConstraints))
+		for i := range cfg.CheckConstraints {
+			checks[i] = types.DomainCheckConstraint{
+				Name: cfg.CheckConstraints[i].Name,
+				Expr: cfg.CheckConstraints[i].Expr,
+			}
+		}
+		typ.TypeMeta.DomainData = &types.DomainMetadata{
+			NotNull:          cfg.NotNull,
+			DefaultExpr:      cfg.DefaultExpr,
+			CheckConstraints: checks,
+		}
+		return nil
 	case descpb.TypeDescriptor_ALIAS:
 		if typ.UserDefined() {
 			switch typ.Family() {
@@ -898,6 +974,19 @@ func (desc *immutable) IsCompatibleWith(other catalog.TypeDescriptor) error {
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if other.GetKind() != desc.Kind {
+			return errors.Newf("%q of type %q is not compatible with type %q",
+				other.GetName(), other.GetKind(), desc.Kind)
+		}
+		// Values of both domains must have the same disk encoding, which is the
+		// case iff their base types are identical.
+		thisBase, otherBase := desc.DomainConfig.BaseType, other.GetDomainConfig().BaseType
+		if !thisBase.Identical(otherBase) {
+			return errors.Newf("%q has differing base type %s, expected %s",
+				other.GetName(), otherBase.SQLString(), thisBase.SQLString())
+		}
+		return nil
 	default:
 		return errors.Newf("compatibility comparison unsupported for type kind %s", desc.Kind.String())
 	}
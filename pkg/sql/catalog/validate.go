@@ -349,6 +349,10 @@ type ValidationDescGetter interface {
 	// GetTypeDescriptor returns the corresponding TypeDescriptor or an error instead.
 	GetTypeDescriptor(id descpb.ID) (TypeDescriptor, error)
 
+	// GetFunctionDescriptor returns the corresponding FunctionDescriptor or an
+	// error instead.
+	GetFunctionDescriptor(id descpb.ID) (FunctionDescriptor, error)
+
 	// Seals this interface.
 	sealed()
 }
@@ -402,11 +406,26 @@ func (vdg *validationDescGetterImpl) GetTypeDescriptor(id descpb.ID) (TypeDescri
 	return descriptor, err
 }
 
+// GetFunctionDescriptor implements the ValidationDescGetter interface.
+func (vdg *validationDescGetterImpl) GetFunctionDescriptor(
+	id descpb.ID,
+) (FunctionDescriptor, error) {
+	desc, found := vdg.Descriptors[id]
+	if !found || desc == nil {
+		return nil, WrapFunctionDescRefErr(id, ErrReferencedDescriptorNotFound)
+	}
+	return AsFunctionDescriptor(desc)
+}
+
 func (vdg *validationDescGetterImpl) addNamespaceEntries(
 	ctx context.Context, descriptors []Descriptor, maybeBatchDescGetter DescGetter,
 ) (err error) {
 	reqs := make([]descpb.NameInfo, 0, len(descriptors))
 	for _, desc := range descriptors {
+		if desc.DescriptorType() == Function {
+			// Functions don't have namespace entries.
+			continue
+		}
 		reqs = append(reqs, descpb.NameInfo{
 			ParentID:       desc.GetParentID(),
 			ParentSchemaID: desc.GetParentSchemaID(),
@@ -535,6 +554,11 @@ func validateNamespace(
 	if desc.GetID() == keys.NamespaceTableID || desc.GetID() == keys.DeprecatedNamespaceTableID {
 		return
 	}
+	if desc.DescriptorType() == Function {
+		// Functions are looked up by name through their parent schema, rather
+		// than through the namespace table.
+		return
+	}
 
 	id := namespace[descpb.NameInfo{
 		ParentID:       desc.GetParentID(),
@@ -0,0 +1,43 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/errors"
+)
+
+// ChangefeedEventsFetcher fetches the events buffered by the changefeeds with
+// a pull sink which run in this process. It backs the ChangefeedEvents RPC of
+// the status server.
+type ChangefeedEventsFetcher interface {
+	// FetchChangefeedEvents fetches the events buffered by the changefeed of
+	// the request, which runs on the given SQL instance.
+	FetchChangefeedEvents(
+		ctx context.Context, instanceID base.SQLInstanceID, req *serverpb.ChangefeedEventsRequest,
+	) (*serverpb.ChangefeedEventsResponse, error)
+}
+
+// GetChangefeedEventsFetcherHook is the hook to get access to the
+// ChangefeedEventsFetcher. It is set by the CCL changefeed package.
+var GetChangefeedEventsFetcherHook func() ChangefeedEventsFetcher
+
+// GetChangefeedEventsFetcher returns a ChangefeedEventsFetcher if a CCL binary
+// is loaded.
+func GetChangefeedEventsFetcher() (ChangefeedEventsFetcher, error) {
+	if GetChangefeedEventsFetcherHook == nil {
+		return nil, errors.New("changefeeds with a pull sink require a CCL binary")
+	}
+	return GetChangefeedEventsFetcherHook(), nil
+}
@@ -419,6 +419,152 @@ func validateUniqueConstraint(
 	return nil
 }
 
+// exclusionViolationQuery generates and returns a query for pairs of rows that
+// violate the specified exclusion constraint.
+//
+// For example, an exclusion constraint EXCLUDE (a WITH =, b WITH &&) on the
+// table "tbl" with primary key k would require the following query:
+//
+// SELECT l.a, l.b, r.a, r.b
+// FROM tbl AS l
+// JOIN tbl AS r ON l.a = r.a AND l.b && r.b AND (l.k) != (r.k)
+// LIMIT 1
+//
+// If the constraint is partial, both sides of the join are filtered by the
+// constraint predicate.
+func exclusionViolationQuery(
+	srcTbl catalog.TableDescriptor, ec *descpb.ExclusionConstraint,
+) (sql string, colNames []string, _ error) {
+	colNames, err := srcTbl.NamesForColumnIDs(ec.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	leftCols := make([]string, len(colNames))
+	rightCols := make([]string, len(colNames))
+	// There will be a condition in the ON clause for each of the columns, and
+	// one for the primary key.
+	conds := make([]string, 0, len(colNames)+1)
+	for i, n := range colNames {
+		leftCols[i] = fmt.Sprintf("l.%s", tree.NameString(n))
+		rightCols[i] = fmt.Sprintf("r.%s", tree.NameString(n))
+		conds = append(conds, fmt.Sprintf("%s %s %s", leftCols[i], ec.Operators[i], rightCols[i]))
+	}
+
+	pkColNames := srcTbl.GetPrimaryIndex().IndexDesc().KeyColumnNames
+	leftPK := make([]string, len(pkColNames))
+	rightPK := make([]string, len(pkColNames))
+	for i, n := range pkColNames {
+		leftPK[i] = fmt.Sprintf("l.%s", tree.NameString(n))
+		rightPK[i] = fmt.Sprintf("r.%s", tree.NameString(n))
+	}
+	conds = append(conds, fmt.Sprintf(
+		"(%s) != (%s)", strings.Join(leftPK, ", "), strings.Join(rightPK, ", "),
+	))
+
+	src := fmt.Sprintf("[%d AS tbl]", srcTbl.GetID())
+	if ec.IsPartial() {
+		src = fmt.Sprintf("(SELECT * FROM [%d AS tbl] WHERE %s)", srcTbl.GetID(), ec.Predicate)
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s, %[2]s FROM %[3]s AS l JOIN %[3]s AS r ON %[4]s LIMIT 1`,
+		strings.Join(leftCols, ", "),  // 1
+		strings.Join(rightCols, ", "), // 2
+		src,                           // 3
+		strings.Join(conds, " AND "),  // 4
+	), colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows in the srcTable
+// conflict according to the given exclusion constraint.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	ec *descpb.ExclusionConstraint,
+	ie sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query, colNames, err := exclusionViolationQuery(srcTable, ec)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		ec.Name,
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	values, err := ie.QueryRowEx(ctx, "validate exclusion constraint", txn,
+		sessiondata.NodeUserSessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		n := len(colNames)
+		leftStr := make([]string, n)
+		rightStr := make([]string, n)
+		for i := 0; i < n; i++ {
+			leftStr[i] = values[i].String()
+			rightStr[i] = values[n+i].String()
+		}
+		cols := strings.Join(colNames, ", ")
+		// Note: this error message mirrors the message produced by Postgres
+		// when it fails to add an exclusion constraint due to conflicting rows.
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.ExclusionViolation, "could not create exclusion constraint %q", ec.Name,
+				),
+				ec.Name,
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) conflicts with key (%s)=(%s).",
+				cols, strings.Join(leftStr, ", "), cols, strings.Join(rightStr, ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// validateExclusionConstraintInTxn validates the exclusion constraint with
+// the given name on the table descriptor, which may contain uncommitted
+// changes.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionConstraintInTxn(
+	ctx context.Context,
+	ie sqlutil.InternalExecutor,
+	tableDesc *tabledesc.Mutable,
+	txn *kv.Txn,
+	constraintName string,
+) error {
+	var syntheticDescs []catalog.Descriptor
+	if tableDesc.Version > tableDesc.ClusterVersion.Version {
+		syntheticDescs = append(syntheticDescs, tableDesc)
+	}
+
+	var ec *descpb.ExclusionConstraint
+	for i := range tableDesc.ExclusionConstraints {
+		if def := &tableDesc.ExclusionConstraints[i]; def.Name == constraintName {
+			ec = def
+			break
+		}
+	}
+	if ec == nil {
+		return errors.AssertionFailedf("exclusion constraint %s does not exist", constraintName)
+	}
+
+	return ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+		return validateExclusionConstraint(ctx, tableDesc, ec, ie, txn)
+	})
+}
+
 func formatValues(colNames []string, values tree.Datums) string {
 	var pairs bytes.Buffer
 	for i := range values {
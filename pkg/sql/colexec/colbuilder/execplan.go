@@ -246,9 +246,11 @@ var (
 	errChangeFrontierWrap             = errors.New("core.ChangeFrontier is not supported")
 	errReadImportWrap                 = errors.New("core.ReadImport is not supported")
 	errBackupDataWrap                 = errors.New("core.BackupData is not supported")
+	errVerifyBackupDataWrap           = errors.New("core.VerifyBackupData is not supported")
 	errBackfillerWrap                 = errors.New("core.Backfiller is not supported (not an execinfra.RowSource)")
 	errCSVWriterWrap                  = errors.New("core.CSVWriter is not supported (not an execinfra.RowSource)")
 	errParquetWriterWrap              = errors.New("core.ParquetWriter is not supported (not an execinfra.RowSource)")
+	errJSONWriterWrap                 = errors.New("core.JSONWriter is not supported (not an execinfra.RowSource)")
 	errSamplerWrap                    = errors.New("core.Sampler is not supported (not an execinfra.RowSource)")
 	errSampleAggregatorWrap           = errors.New("core.SampleAggregator is not supported (not an execinfra.RowSource)")
 	errExperimentalWrappingProhibited = errors.New("wrapping for non-JoinReader and non-LocalPlanNode cores is prohibited in vectorize=experimental_always")
@@ -278,6 +280,8 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, spec *execinfrapb.ProcessorSp
 		return errCSVWriterWrap
 	case spec.Core.ParquetWriter != nil:
 		return errParquetWriterWrap
+	case spec.Core.JSONWriter != nil:
+		return errJSONWriterWrap
 	case spec.Core.Sampler != nil:
 		return errSamplerWrap
 	case spec.Core.SampleAggregator != nil:
@@ -310,6 +314,8 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, spec *execinfrapb.ProcessorSp
 	case spec.Core.InvertedJoiner != nil:
 	case spec.Core.BackupData != nil:
 		return errBackupDataWrap
+	case spec.Core.VerifyBackupData != nil:
+		return errVerifyBackupDataWrap
 	case spec.Core.SplitAndScatter != nil:
 	case spec.Core.RestoreData != nil:
 	case spec.Core.Filterer != nil:
@@ -827,6 +833,13 @@ func NewColOperator(
 			}
 			inputTypes := make([]*types.T, len(spec.Input[0].ColumnTypes))
 			copy(inputTypes, spec.Input[0].ColumnTypes)
+			if aggSpec.HasGroupingSets() {
+				// Every input tuple is expanded into one tuple per grouping set
+				// which are then aggregated by the hash aggregator.
+				inputs[0].Root = colexec.NewGroupingSetsExpander(
+					getStreamingAllocator(ctx, args), inputs[0].Root, inputTypes, aggSpec,
+				)
+			}
 			// Make a copy of the evalCtx since we're modifying it below.
 			evalCtx := flowCtx.NewEvalCtx()
 			newAggArgs := &colexecagg.NewAggregatorArgs{
@@ -963,6 +976,14 @@ func NewColOperator(
 				newAggArgs.MemAccount = args.StreamingMemAccount
 				result.Root = colexec.NewOrderedAggregator(newAggArgs)
 			}
+			if aggSpec.HasGroupingSets() {
+				// The empty grouping sets produce a row even if there are no
+				// input tuples, which the hash aggregator doesn't handle.
+				scalarAggArgs := *newAggArgs
+				scalarAggArgs.Allocator = getStreamingAllocator(ctx, args)
+				scalarAggArgs.MemAccount = args.StreamingMemAccount
+				result.Root = colexec.NewGroupingSetsEmptyInputOp(result.Root, &scalarAggArgs)
+			}
 			result.ToClose = append(result.ToClose, result.Root.(colexecop.Closer))
 
 		case core.Distinct != nil:
@@ -0,0 +1,232 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// groupingSetsExpanderOp is an operator that expands every input batch into
+// one batch per grouping set of an aggregation with GROUPING SETS, ROLLUP or
+// CUBE. In the batch for a grouping set, the group columns that are not part
+// of the set are NULL and the grouping set ID column contains the ordinal of
+// the set. The hash aggregator that consumes the output of this operator then
+// computes all grouping sets at once.
+type groupingSetsExpanderOp struct {
+	colexecop.OneInputHelper
+	colexecop.NonExplainable
+
+	allocator  *colmem.Allocator
+	inputTypes []*types.T
+	idCol      int
+	// nullCols contains, for each grouping set, the group columns (other than
+	// idCol) that are not part of the set.
+	nullCols [][]int
+
+	// batch is the current input batch and setIdx is the ordinal of the next
+	// grouping set to emit it for.
+	batch  coldata.Batch
+	setIdx int
+	output coldata.Batch
+}
+
+var _ colexecop.Operator = &groupingSetsExpanderOp{}
+
+// NewGroupingSetsExpander returns an operator that expands the input tuples
+// into one tuple per grouping set of the given aggregator spec.
+func NewGroupingSetsExpander(
+	allocator *colmem.Allocator,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.AggregatorSpec,
+) colexecop.Operator {
+	op := &groupingSetsExpanderOp{
+		OneInputHelper: colexecop.MakeOneInputHelper(input),
+		allocator:      allocator,
+		inputTypes:     inputTypes,
+		idCol:          int(spec.GroupingSetIDCol),
+		nullCols:       make([][]int, len(spec.GroupingSets)),
+	}
+	for i := range spec.GroupingSets {
+		var inSet util.FastIntSet
+		for _, c := range spec.GroupingSets[i].Cols {
+			inSet.Add(int(c))
+		}
+		for _, c := range spec.GroupCols {
+			if int(c) != op.idCol && !inSet.Contains(int(c)) {
+				op.nullCols[i] = append(op.nullCols[i], int(c))
+			}
+		}
+	}
+	return op
+}
+
+func (e *groupingSetsExpanderOp) Next() coldata.Batch {
+	if e.batch == nil || e.setIdx == len(e.nullCols) {
+		e.batch = e.Input.Next()
+		e.setIdx = 0
+		if e.batch.Length() == 0 {
+			return coldata.ZeroBatch
+		}
+	}
+	n := e.batch.Length()
+	// The hash aggregator limits the size of its own batches, so we don't use
+	// a memory limit here.
+	e.output, _ = e.allocator.ResetMaybeReallocate(
+		e.inputTypes, e.output, n, math.MaxInt64, /* maxBatchMemSize */
+	)
+	sel := e.batch.Selection()
+	e.allocator.PerformOperation(e.output.ColVecs(), func() {
+		for i := range e.inputTypes {
+			e.output.ColVec(i).Copy(
+				coldata.SliceArgs{
+					Src:       e.batch.ColVec(i),
+					Sel:       sel,
+					SrcEndIdx: n,
+				},
+			)
+		}
+		for _, c := range e.nullCols[e.setIdx] {
+			e.output.ColVec(c).Nulls().SetNullRange(0, n)
+		}
+		idVec := e.output.ColVec(e.idCol)
+		ids := idVec.Int64()
+		for i := 0; i < n; i++ {
+			ids.Set(i, int64(e.setIdx))
+			idVec.Nulls().UnsetNull(i)
+		}
+	})
+	e.output.SetLength(n)
+	e.setIdx++
+	return e.output
+}
+
+// groupingSetsEmptyInputOp passes through the output of a hash aggregator
+// that computes grouping sets. If the aggregator doesn't produce any tuples
+// (meaning that its input was empty), it instead emits a tuple for every empty
+// grouping set, similar to a scalar aggregation over an empty input.
+type groupingSetsEmptyInputOp struct {
+	colexecop.OneInputInitCloserHelper
+
+	// scalarAgg computes the results of the aggregate functions over an empty
+	// input.
+	scalarAgg colexecop.Operator
+	// emptySets contains the IDs of the empty grouping sets.
+	emptySets []int64
+	// idOutputCols contains the output columns of the ANY_NOT_NULL
+	// aggregations over the grouping set ID column.
+	idOutputCols []int
+
+	seenTuples   bool
+	inputDone    bool
+	defaultBatch coldata.Batch
+}
+
+var _ colexecop.ClosableOperator = &groupingSetsEmptyInputOp{}
+
+// NewGroupingSetsEmptyInputOp returns an operator that emits the results of
+// the empty grouping sets if the given hash aggregator doesn't produce any
+// tuples. aggArgs must be the arguments that the aggregator was created with.
+func NewGroupingSetsEmptyInputOp(
+	aggregator colexecop.Operator, aggArgs *colexecagg.NewAggregatorArgs,
+) colexecop.ClosableOperator {
+	spec := aggArgs.Spec
+	op := &groupingSetsEmptyInputOp{
+		OneInputInitCloserHelper: colexecop.MakeOneInputInitCloserHelper(aggregator),
+	}
+	for i := range spec.GroupingSets {
+		if len(spec.GroupingSets[i].Cols) == 0 {
+			op.emptySets = append(op.emptySets, int64(i))
+		}
+	}
+	scalarSpec := &execinfrapb.AggregatorSpec{
+		Type:         execinfrapb.AggregatorSpec_SCALAR,
+		Aggregations: make([]execinfrapb.AggregatorSpec_Aggregation, len(spec.Aggregations)),
+	}
+	for i, agg := range spec.Aggregations {
+		if agg.Func == execinfrapb.AnyNotNull && len(agg.ColIdx) == 1 &&
+			agg.ColIdx[0] == spec.GroupingSetIDCol {
+			op.idOutputCols = append(op.idOutputCols, i)
+		}
+		// The filters are irrelevant since there are no input tuples.
+		agg.FilterColIdx = nil
+		scalarSpec.Aggregations[i] = agg
+	}
+	scalarArgs := *aggArgs
+	scalarArgs.Spec = scalarSpec
+	scalarArgs.Input = colexecutils.NewFixedNumTuplesNoInputOp(
+		aggArgs.Allocator, 0 /* numTuples */, nil, /* opToInitialize */
+	)
+	op.scalarAgg = NewOrderedAggregator(&scalarArgs)
+	return op
+}
+
+func (e *groupingSetsEmptyInputOp) Init(ctx context.Context) {
+	if !e.InitHelper.Init(ctx) {
+		return
+	}
+	e.Input.Init(e.Ctx)
+	e.scalarAgg.Init(e.Ctx)
+}
+
+func (e *groupingSetsEmptyInputOp) Next() coldata.Batch {
+	if !e.inputDone {
+		batch := e.Input.Next()
+		if batch.Length() > 0 {
+			e.seenTuples = true
+			return batch
+		}
+		e.inputDone = true
+		if e.seenTuples {
+			e.emptySets = nil
+			return coldata.ZeroBatch
+		}
+		e.defaultBatch = e.scalarAgg.Next()
+	}
+	if len(e.emptySets) == 0 {
+		return coldata.ZeroBatch
+	}
+	// The scalar aggregation produces a single tuple which we emit once for
+	// every empty grouping set with the corresponding ID.
+	id := e.emptySets[0]
+	e.emptySets = e.emptySets[1:]
+	for _, c := range e.idOutputCols {
+		vec := e.defaultBatch.ColVec(c)
+		vec.Int64().Set(0, id)
+		vec.Nulls().UnsetNull(0)
+	}
+	return e.defaultBatch
+}
+
+func (e *groupingSetsEmptyInputOp) Close() error {
+	if !e.CloserHelper.Close() {
+		return nil
+	}
+	var lastErr error
+	for _, op := range []colexecop.Operator{e.Input, e.scalarAgg} {
+		if closer, ok := op.(colexecop.Closer); ok {
+			if err := closer.Close(); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,173 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// makeGroupingSetsSpec returns the spec of an aggregation over the columns
+// (a, b, id, v) which computes ANY_NOT_NULL(a), ANY_NOT_NULL(b),
+// ANY_NOT_NULL(id) and SUM_INT(v) for the given grouping sets of a and b, with
+// id as the grouping set ID column.
+func makeGroupingSetsSpec(sets ...[]uint32) *execinfrapb.AggregatorSpec {
+	spec := &execinfrapb.AggregatorSpec{
+		Type:      execinfrapb.AggregatorSpec_NON_SCALAR,
+		GroupCols: []uint32{0, 1, 2},
+		Aggregations: []execinfrapb.AggregatorSpec_Aggregation{
+			{Func: execinfrapb.AnyNotNull, ColIdx: []uint32{0}},
+			{Func: execinfrapb.AnyNotNull, ColIdx: []uint32{1}},
+			{Func: execinfrapb.AnyNotNull, ColIdx: []uint32{2}},
+			{Func: execinfrapb.SumInt, ColIdx: []uint32{3}},
+		},
+		GroupingSetIDCol: 2,
+	}
+	for _, cols := range sets {
+		spec.GroupingSets = append(spec.GroupingSets, execinfrapb.AggregatorSpec_GroupingSet{Cols: cols})
+	}
+	return spec
+}
+
+func TestGroupingSetsExpander(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// ROLLUP (a, b).
+	spec := makeGroupingSetsSpec([]uint32{0, 1}, []uint32{0}, nil)
+	tcs := []struct {
+		tuples   colexectestutils.Tuples
+		expected colexectestutils.Tuples
+	}{
+		{
+			tuples: colexectestutils.Tuples{{1, 2, 0, 10}, {3, 4, 0, 20}},
+			expected: colexectestutils.Tuples{
+				{1, 2, 0, 10}, {1, nil, 1, 10}, {nil, nil, 2, 10},
+				{3, 4, 0, 20}, {3, nil, 1, 20}, {nil, nil, 2, 20},
+			},
+		},
+		{
+			tuples: colexectestutils.Tuples{{nil, 2, 0, 10}, {3, nil, 0, nil}},
+			expected: colexectestutils.Tuples{
+				{nil, 2, 0, 10}, {nil, nil, 1, 10}, {nil, nil, 2, 10},
+				{3, nil, 0, nil}, {3, nil, 1, nil}, {nil, nil, 2, nil},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		colexectestutils.RunTests(t, testAllocator, []colexectestutils.Tuples{tc.tuples}, tc.expected, colexectestutils.UnorderedVerifier, func(input []colexecop.Operator) (colexecop.Operator, error) {
+			return NewGroupingSetsExpander(testAllocator, input[0], types.FourIntCols, spec), nil
+		})
+	}
+}
+
+func TestGroupingSetsAggregation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	tcs := []struct {
+		name     string
+		spec     *execinfrapb.AggregatorSpec
+		tuples   colexectestutils.Tuples
+		expected colexectestutils.Tuples
+	}{
+		{
+			name: "rollup",
+			spec: makeGroupingSetsSpec([]uint32{0, 1}, []uint32{0}, nil),
+			tuples: colexectestutils.Tuples{
+				{1, 1, 0, 10},
+				{1, 2, 0, 20},
+				{2, 1, 0, 30},
+				{1, 1, 0, 40},
+			},
+			expected: colexectestutils.Tuples{
+				{1, 1, 0, 50},
+				{1, 2, 0, 20},
+				{2, 1, 0, 30},
+				{1, nil, 1, 70},
+				{2, nil, 1, 30},
+				{nil, nil, 2, 100},
+			},
+		},
+		{
+			name: "grouping sets without empty set",
+			spec: makeGroupingSetsSpec([]uint32{0}, []uint32{1}),
+			tuples: colexectestutils.Tuples{
+				{1, 1, 0, 10},
+				{1, 2, 0, 20},
+				{nil, 2, 0, 30},
+			},
+			expected: colexectestutils.Tuples{
+				{1, nil, 0, 30},
+				{nil, nil, 0, 30},
+				{nil, 1, 1, 10},
+				{nil, 2, 1, 50},
+			},
+		},
+		{
+			name:     "rollup with empty input",
+			spec:     makeGroupingSetsSpec([]uint32{0, 1}, []uint32{0}, nil),
+			tuples:   colexectestutils.Tuples{},
+			expected: colexectestutils.Tuples{{nil, nil, 2, nil}},
+		},
+		{
+			name:     "multiple empty sets with empty input",
+			spec:     makeGroupingSetsSpec(nil, []uint32{0}, nil),
+			tuples:   colexectestutils.Tuples{},
+			expected: colexectestutils.Tuples{{nil, nil, 0, nil}, {nil, nil, 2, nil}},
+		},
+		{
+			name:     "no empty set with empty input",
+			spec:     makeGroupingSetsSpec([]uint32{0}, []uint32{1}),
+			tuples:   colexectestutils.Tuples{},
+			expected: colexectestutils.Tuples{},
+		},
+	}
+	for _, tc := range tcs {
+		log.Infof(context.Background(), "%s", tc.name)
+		constructors, constArguments, outputTypes, err := colexecagg.ProcessAggregations(
+			&evalCtx, nil /* semaCtx */, tc.spec.Aggregations, types.FourIntCols,
+		)
+		require.NoError(t, err)
+		colexectestutils.RunTestsWithTyps(t, testAllocator, []colexectestutils.Tuples{tc.tuples}, [][]*types.T{types.FourIntCols}, tc.expected, colexectestutils.UnorderedVerifier,
+			func(sources []colexecop.Operator) (colexecop.Operator, error) {
+				args := &colexecagg.NewAggregatorArgs{
+					Allocator:      testAllocator,
+					MemAccount:     testMemAcc,
+					Input:          NewGroupingSetsExpander(testAllocator, sources[0], types.FourIntCols, tc.spec),
+					InputTypes:     types.FourIntCols,
+					Spec:           tc.spec,
+					EvalCtx:        &evalCtx,
+					Constructors:   constructors,
+					ConstArguments: constArguments,
+					OutputTypes:    outputTypes,
+				}
+				aggregator := NewHashAggregator(
+					args, nil /* newSpillingQueueArgs */, testAllocator, math.MaxInt64,
+				)
+				return NewGroupingSetsEmptyInputOp(aggregator, args), nil
+			})
+	}
+}
@@ -97,6 +97,9 @@ func (n *commentOnConstraintNode) startExec(params runParams) error {
 	case descpb.ConstraintTypeCheck:
 		constraintDesc := constraint.CheckConstraint
 		n.oid = hasher.CheckConstraintOid(n.tableDesc.GetParentID(), schema.GetName(), n.tableDesc.GetID(), constraintDesc)
+	case descpb.ConstraintTypeExclusion:
+		constraintDesc := constraint.ExclusionConstraint
+		n.oid = hasher.ExclusionConstraintOid(n.tableDesc.GetParentID(), schema.GetName(), n.tableDesc.GetID(), constraintDesc)
 
 	}
 	// Setting the comment to NULL is the
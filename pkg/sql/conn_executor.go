@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
@@ -638,6 +640,15 @@ func (s *Server) SetupConn(
 		ctx, sdMutIterator, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.GetApplicationStats(sd.ApplicationName),
 	)
+	if r := s.cfg.NotificationRegistry; r != nil {
+		// Notifications received while the session is idle are delivered
+		// right away by pushing a command that interrupts the wait for the
+		// next client command.
+		ex.notificationListener = r.NewListener(func() {
+			_ = stmtBuf.Push(ctx, DeliverNotifications{})
+		})
+		ex.planner.notificationListener = ex.notificationListener
+	}
 	return ConnectionHandler{ex}, nil
 }
 
@@ -925,6 +936,7 @@ func (s *Server) newConnExecutorWithTxn(
 		txn.ReadTimestamp().GoTime(),
 		nil, /* historicalTimestamp */
 		roachpb.UnspecifiedUserPriority,
+		isolation.Serializable, /* isoLevel */
 		tree.ReadWrite,
 		txn,
 		ex.transitionCtx)
@@ -1066,6 +1078,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notificationListener != nil {
+		ex.notificationListener.Close()
+	}
+
 	// Stop idle timer if the connExecutor is closed to ensure cancel session
 	// is not called.
 	ex.mu.IdleInSessionTimeout.Stop()
@@ -1130,6 +1146,9 @@ type connExecutor struct {
 	stmtBuf *StmtBuf
 	// The interface for communicating statement results to the client.
 	clientComm ClientComm
+	// notificationListener queues the notifications for the channels this
+	// session listens on. It is nil for internal sessions.
+	notificationListener *notify.Listener
 	// Finity "the machine" Automaton is the state machine controlling the state
 	// below.
 	machine fsm.Machine
@@ -1276,6 +1295,10 @@ type connExecutor struct {
 
 		schemaChangerState SchemaChangerState
 
+		// deferredConstraints tracks the checks of DEFERRABLE constraints that
+		// were postponed until the transaction commits.
+		deferredConstraints deferredConstraints
+
 		// shouldCollectTxnExecutionStats specifies whether the statements in
 		// this transaction should collect execution stats.
 		shouldCollectTxnExecutionStats bool
@@ -1462,6 +1485,28 @@ func (ns prepStmtNamespace) HasPrepared() bool {
 	return len(ns.prepStmts) > 0 || len(ns.portals) > 0
 }
 
+// HasActivePortals returns true if there are portals in the session.
+func (ns prepStmtNamespace) HasActivePortals() bool {
+	return len(ns.portals) > 0
+}
+
+// MigratablePreparedStatements returns a serialization of the prepared
+// statements.
+func (ns prepStmtNamespace) MigratablePreparedStatements() []sessiondatapb.MigratableSession_PreparedStatement {
+	ret := make([]sessiondatapb.MigratableSession_PreparedStatement, 0, len(ns.prepStmts))
+	for name, stmt := range ns.prepStmts {
+		ret = append(
+			ret,
+			sessiondatapb.MigratableSession_PreparedStatement{
+				Name:                 name,
+				PlaceholderTypeHints: stmt.InferredTypes,
+				SQL:                  stmt.SQL,
+			},
+		)
+	}
+	return ret
+}
+
 func (ns prepStmtNamespace) String() string {
 	var sb strings.Builder
 	sb.WriteString("Prep stmts: ")
@@ -1523,6 +1568,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 	ex.extraTxnState.schemaChangerState = SchemaChangerState{
 		mode: ex.sessionData().NewSchemaChangerMode,
 	}
+	ex.extraTxnState.deferredConstraints.reset()
 
 	for k := range ex.extraTxnState.schemaChangeJobRecords {
 		delete(ex.extraTxnState.schemaChangeJobRecords, k)
@@ -1889,6 +1935,22 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	case CopyOut:
+		copyRes := ex.clientComm.CreateCopyOutResult(
+			tcmd.Stmt,
+			pos,
+			ex.sessionData().DataConversionConfig,
+			ex.sessionData().GetLocation(),
+		)
+		res = copyRes
+		var err error
+		ev, payload, err = ex.execCopyOut(ctx, tcmd, copyRes)
+		if err != nil {
+			return err
+		}
+	case StartReplication:
+		res = ex.clientComm.CreateStartReplicationResult(pos)
+		ev, payload = ex.execStartReplication(ctx, tcmd)
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -1902,6 +1964,11 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// The pending notifications are buffered on the result below, once we
+		// know that we're not inside a transaction. Closing the res will flush
+		// the connection's buffer.
+		res = ex.clientComm.CreateDeliverNotificationsResult(pos)
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -1937,6 +2004,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 				res.SetError(pe.errorCause())
 			}
 		}
+		if ns, ok := res.(NotificationSender); ok {
+			ex.maybeBufferNotifications(ns)
+		}
 		res.Close(ctx, stateToTxnStatusIndicator(ex.machine.CurState()))
 	} else {
 		res.Discard()
@@ -2004,6 +2074,21 @@ func (ex *connExecutor) idleConn() bool {
 	}
 }
 
+// maybeBufferNotifications buffers the pending asynchronous notifications on
+// the given result if the session is not inside a transaction. Like Postgres,
+// we never deliver notifications in the middle of a transaction.
+func (ex *connExecutor) maybeBufferNotifications(res NotificationSender) {
+	if ex.notificationListener == nil {
+		return
+	}
+	if _, noTxn := ex.machine.CurState().(stateNoTxn); !noTxn {
+		return
+	}
+	for _, n := range ex.notificationListener.TakePending() {
+		res.BufferNotification(n)
+	}
+}
+
 // updateTxnRewindPosMaybe checks whether the ex.extraTxnState.txnRewindPos
 // should be advanced, based on the advInfo produced by running cmd at position
 // pos.
@@ -2088,10 +2173,16 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case CopyOut:
+				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -2258,6 +2349,103 @@ func (ex *connExecutor) execCopyIn(
 	return nil, nil, nil
 }
 
+// We handle the CopyTo statement by running its query with a copyOutMachine,
+// which streams the rows to the client through res. Like the copyMachine, the
+// copyOutMachine runs the query in the current transaction if there is one,
+// and in a transaction of its own otherwise.
+func (ex *connExecutor) execCopyOut(
+	ctx context.Context, cmd CopyOut, res CopyOutResult,
+) (_ fsm.Event, retPayload fsm.EventPayload, retErr error) {
+	ex.incrementStartedStmtCounter(cmd.Stmt)
+	defer func() {
+		if retErr == nil && !payloadHasError(retPayload) {
+			ex.incrementExecutedStmtCounter(cmd.Stmt)
+		}
+	}()
+
+	state := ex.machine.CurState()
+	_, isNoTxn := state.(stateNoTxn)
+	_, isOpen := state.(stateOpen)
+	if !isNoTxn && !isOpen {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{
+			err: sqlerrors.NewTransactionAbortedError("" /* customMsg */)}
+		return ev, payload, nil
+	}
+
+	var txnOpt copyTxnOpt
+	if isOpen {
+		// Like execStmtInOpenState, create a sequencing point so that the query
+		// observes the writes of the previous statements of the txn.
+		txn := ex.state.mu.txn
+		prevSteppingMode := txn.ConfigureStepping(ctx, kv.SteppingEnabled)
+		defer func() { _ = txn.ConfigureStepping(ctx, prevSteppingMode) }()
+		if err := txn.Step(ctx); err != nil {
+			ev := eventNonRetriableErr{IsCommit: fsm.False}
+			payload := eventNonRetriableErrPayload{err: err}
+			return ev, payload, nil
+		}
+		// Like other statements, the query of a READ COMMITTED transaction
+		// observes the writes committed before it starts.
+		if txn.IsoLevel().PerStatementReadSnapshot() {
+			if err := txn.StepReadTimestamp(ctx); err != nil {
+				ev := eventNonRetriableErr{IsCommit: fsm.False}
+				payload := eventNonRetriableErrPayload{err: err}
+				return ev, payload, nil
+			}
+		}
+		txnOpt = copyTxnOpt{
+			txn:           txn,
+			txnTimestamp:  ex.state.sqlTimestamp,
+			stmtTimestamp: ex.server.cfg.Clock.PhysicalTime(),
+		}
+	} else {
+		txnOpt = copyTxnOpt{
+			resetExtraTxnState: func(ctx context.Context) error {
+				return ex.resetExtraTxnState(ctx, noEvent)
+			},
+		}
+	}
+
+	var monToStop *mon.BytesMonitor
+	defer func() {
+		if monToStop != nil {
+			monToStop.Stop(ctx)
+		}
+	}()
+	if isNoTxn {
+		// HACK: We're reaching inside ex.state and starting the monitor, like
+		// execCopyIn does.
+		ex.state.mon.Start(ctx, ex.sessionMon, mon.BoundAccount{} /* reserved */)
+		monToStop = ex.state.mon
+	}
+	txnOpt.resetPlanner = func(ctx context.Context, p *planner, txn *kv.Txn, txnTS time.Time, stmtTS time.Time) {
+		// HACK: We're reaching inside ex.state and changing sqlTimestamp by hand.
+		// See execCopyIn.
+		ex.state.sqlTimestamp = txnTS
+		ex.statsCollector.Reset(ex.applicationStats, ex.phaseTimes)
+		ex.initPlanner(ctx, p)
+		ex.resetPlanner(ctx, p, txn, stmtTS)
+	}
+	cm := newCopyOutMachine(
+		cmd.Stmt, res, txnOpt, ex.server.cfg,
+		// execQueryPlan
+		func(ctx context.Context, p *planner, res RestrictedCommandResult) error {
+			_, err := ex.execWithDistSQLEngine(ctx, p, tree.Rows, res, false /* distribute */, nil /* progressAtomic */)
+			return err
+		},
+	)
+	if err := cm.run(ctx); err != nil {
+		// As for COPY FROM, we don't distinguish communication errors from query
+		// errors and we abort the txn (if any). Note that rows might already have
+		// been sent to the client, so the query cannot be retried.
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload, nil
+	}
+	return nil, nil, nil
+}
+
 // stmtHasNoData returns true if describing a result of the input statement
 // type should return NoData.
 func stmtHasNoData(stmt tree.Statement) bool {
@@ -2356,6 +2544,7 @@ func (ex *connExecutor) makeErrEvent(err error, stmt tree.Statement) (fsm.Event,
 
 	retriable := errIsRetriable(err)
 	if retriable {
+		ex.maybeRestartTxnPreparedForStmtRetry(err)
 		var rc rewindCapability
 		var canAutoRetry bool
 		if ex.implicitTxn() || !ex.sessionData().InjectRetryErrorsEnabled {
@@ -2382,6 +2571,21 @@ func (ex *connExecutor) makeErrEvent(err error, stmt tree.Statement) (fsm.Event,
 	return ev, payload
 }
 
+// maybeRestartTxnPreparedForStmtRetry restarts the KV transaction if the
+// given retriable error only prepared it for the retry of the failed statement
+// (see roachpb.TransactionRetryWithProtoRefreshError.StatementRestart). This
+// happens to transactions with per-statement read snapshots when the failed
+// statement can't be retried on its own. The state machine expects the whole
+// transaction to have been prepared for a retry by the time it handles the
+// error.
+func (ex *connExecutor) maybeRestartTxnPreparedForStmtRetry(err error) {
+	retryErr := (*roachpb.TransactionRetryWithProtoRefreshError)(nil)
+	if !errors.As(err, &retryErr) || !retryErr.StatementRestart || ex.state.mu.txn == nil {
+		return
+	}
+	ex.state.mu.txn.ManualRestart(ex.Ctx(), ex.server.cfg.Clock.Now())
+}
+
 // setTransactionModes implements the txnModesSetter interface.
 func (ex *connExecutor) setTransactionModes(
 	modes tree.TransactionModes, asOfTs hlc.Timestamp,
@@ -2398,9 +2602,16 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
-		return errors.AssertionFailedf(
-			"unknown isolation level: %s", errors.Safe(modes.Isolation))
+	if modes.Isolation != tree.UnspecifiedIsolation {
+		level, err := ex.txnIsolationLevelToKV(modes.Isolation)
+		if err != nil {
+			return err
+		}
+		if err := ex.state.setIsoLevel(level); err != nil {
+			return pgerror.WithCandidateCode(errors.Wrap(err,
+				"SET TRANSACTION ISOLATION LEVEL must be called before any query"),
+				pgcode.ActiveSQLTransaction)
+		}
 	}
 	rwMode := modes.ReadWriteMode
 	if modes.AsOf.Expr != nil && asOfTs.IsEmpty() {
@@ -2442,6 +2653,32 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsolationLevelToKV returns the KV isolation level of a transaction
+// started with the given SQL isolation level. READ COMMITTED is upgraded to
+// SERIALIZABLE unless it is enabled on the cluster.
+func (ex *connExecutor) txnIsolationLevelToKV(level tree.IsolationLevel) (isolation.Level, error) {
+	switch level {
+	case tree.UnspecifiedIsolation, tree.SerializableIsolation:
+		return isolation.Serializable, nil
+	case tree.ReadCommittedIsolation:
+		if readCommittedIsolationEnabled(ex.Ctx(), ex.server.cfg.Settings) {
+			return isolation.ReadCommitted, nil
+		}
+		return isolation.Serializable, nil
+	default:
+		return 0, errors.AssertionFailedf("unknown isolation level: %s", errors.Safe(level))
+	}
+}
+
+func (ex *connExecutor) txnIsolationLevelWithSessionDefault(
+	level tree.IsolationLevel,
+) (isolation.Level, error) {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData().DefaultTxnIsolationLevel)
+	}
+	return ex.txnIsolationLevelToKV(level)
+}
+
 func (ex *connExecutor) readWriteModeWithSessionDefault(
 	mode tree.ReadWriteMode,
 ) tree.ReadWriteMode {
@@ -2528,6 +2765,7 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
 	evalCtx.SchemaChangerState = &ex.extraTxnState.schemaChangerState
+	evalCtx.DeferredConstraints = &ex.extraTxnState.deferredConstraints
 
 	// If we are retrying due to an unsatisfiable timestamp bound which is
 	// retriable, it means we were unable to serve the previous minimum timestamp
@@ -2573,6 +2811,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutatorIterator = ex.dataMutatorIterator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.notificationListener = ex.notificationListener
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -3130,7 +3369,7 @@ func (sc *StatementCounters) incrementCount(ex *connExecutor, stmt tree.Statemen
 		} else {
 			sc.RollbackToSavepointCount.Inc()
 		}
-	case *tree.CopyFrom:
+	case *tree.CopyFrom, *tree.CopyTo:
 		sc.CopyCount.Inc()
 	default:
 		if tree.CanModifySchema(stmt) {
@@ -701,6 +701,13 @@ func (ex *connExecutor) execStmtInOpenState(
 		return makeErrEvent(err)
 	}
 
+	// Transactions with per-statement read snapshots (i.e. READ COMMITTED
+	// transactions) observe all the writes committed before the start of each
+	// of their statements.
+	if err := ex.state.mu.txn.StepReadTimestamp(ctx); err != nil {
+		return makeErrEvent(err)
+	}
+
 	if err := p.semaCtx.Placeholders.Assign(pinfo, stmt.NumPlaceholders); err != nil {
 		return makeErrEvent(err)
 	}
@@ -718,7 +725,11 @@ func (ex *connExecutor) execStmtInOpenState(
 		ctx, stmtThresholdSpan = createRootOrChildSpan(ctx, "trace-stmt-threshold", ex.transitionCtx.tracer, tracing.WithRecording(tracing.RecordingVerbose))
 	}
 
-	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+	dispatch := ex.dispatchToExecutionEngine
+	if ex.state.mu.txn.IsoLevel().PerStatementReadSnapshot() {
+		dispatch = ex.dispatchToExecutionEngineWithStmtRetries
+	}
+	if err := dispatch(ctx, p, res); err != nil {
 		stmtThresholdSpan.Finish()
 		return nil, nil, err
 	}
@@ -882,6 +893,13 @@ func (ex *connExecutor) reportSessionDataChanges(fn func() error) error {
 func (ex *connExecutor) commitSQLTransactionInternal(
 	ctx context.Context, ast tree.Statement,
 ) error {
+	if err := ex.extraTxnState.deferredConstraints.runChecks(
+		ctx, ex.state.mu.txn, ex.server.cfg.InternalExecutor,
+		&ex.extraTxnState.descCollection, false, /* onlyImmediate */
+	); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -949,6 +967,59 @@ func (ex *connExecutor) rollbackSQLTransaction(
 	return eventTxnFinishAborted{}, nil
 }
 
+// maxStmtRetries is the maximum number of times a statement of a transaction
+// with per-statement read snapshots is retried on its own after a retriable
+// error, before the whole transaction is restarted instead.
+const maxStmtRetries = 10
+
+// dispatchToExecutionEngineWithStmtRetries is like dispatchToExecutionEngine,
+// but it is used by transactions with per-statement read snapshots, which
+// retry a statement on its own, on a new read snapshot, when its execution
+// encounters a retriable error (for instance a write-write conflict), instead
+// of restarting the whole transaction. This is only possible as long as none of
+// the results of the statement have been delivered to the client. Otherwise,
+// the error is left for the state machine to handle like in any other
+// transaction.
+func (ex *connExecutor) dispatchToExecutionEngineWithStmtRetries(
+	ctx context.Context, planner *planner, res RestrictedCommandResult,
+) error {
+	txn := ex.state.mu.txn
+	// The savepoint allows the writes of a failed attempt of the statement to be
+	// rolled back.
+	savepoint, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	for retries := 0; ; retries++ {
+		if err := ex.dispatchToExecutionEngine(ctx, planner, res); err != nil {
+			return err
+		}
+		retryErr := (*roachpb.TransactionRetryWithProtoRefreshError)(nil)
+		if !errors.As(res.Err(), &retryErr) || !retryErr.StatementRestart {
+			return nil
+		}
+		if retries >= maxStmtRetries {
+			log.VEventf(ctx, 2, "giving up on retrying statement after %d retries", retries)
+			return nil
+		}
+		retriableRes, ok := res.(RetriableCommandResult)
+		if !ok || !retriableRes.ResetForStmtRetry(ctx) {
+			log.VEventf(ctx, 2, "statement results already delivered; cannot retry statement")
+			return nil
+		}
+		log.VEventf(ctx, 2, "retrying statement after error: %v", retryErr)
+		if err := txn.RollbackToSavepoint(ctx, savepoint); err != nil {
+			return err
+		}
+		if err := txn.Step(ctx); err != nil {
+			return err
+		}
+		if err := txn.StepReadTimestamp(ctx); err != nil {
+			return err
+		}
+	}
+}
+
 // dispatchToExecutionEngine executes the statement, writes the result to res
 // and returns an event for the connection's state machine.
 //
@@ -1507,10 +1578,15 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		if err != nil {
 			return ex.makeErrEvent(err, s)
 		}
+		isoLevel, err := ex.txnIsolationLevelWithSessionDefault(s.Modes.Isolation)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
 		ex.sessionDataStack.PushTopClone()
 		return eventStartExplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				isoLevel,
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1528,9 +1604,14 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		if err != nil {
 			return ex.makeErrEvent(err, s)
 		}
+		isoLevel, err := ex.txnIsolationLevelWithSessionDefault(tree.UnspecifiedIsolation)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
 		return eventStartImplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				isoLevel,
 				mode,
 				sqlTs,
 				historicalTs,
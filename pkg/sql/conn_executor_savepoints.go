@@ -157,6 +157,7 @@ func (ex *connExecutor) execRelease(
 			env.push(*entry)
 			ex.sessionDataStack.PushTopClone()
 
+			ex.maybeRestartTxnPreparedForStmtRetry(err)
 			rc, canAutoRetry := ex.getRewindTxnCapability()
 			ev := eventRetriableErr{
 				IsCommit:     fsm.FromBool(isCommit(s)),
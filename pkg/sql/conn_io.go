@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
@@ -332,6 +333,44 @@ func (CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// CopyOut is the command for execution of the Copy-out pgwire subprotocol.
+// Unlike CopyIn, it does not take control of the connection: the rows of the
+// CopyTo statement are delivered through a CopyOutResult.
+type CopyOut struct {
+	Stmt *tree.CopyTo
+}
+
+// command implements the Command interface.
+func (CopyOut) command() string { return "copy out" }
+
+func (CopyOut) String() string {
+	return "CopyOut"
+}
+
+var _ Command = CopyOut{}
+
+// StartReplication is the command for the execution of a START_REPLICATION
+// command of the streaming replication protocol. The replication stream is
+// sent in the Copy-both subprotocol until the client ends it.
+type StartReplication struct {
+	Stmt *tree.StartReplication
+	// Conn is the network connection on which the stream is sent.
+	Conn pgwirebase.ReplicationConn
+	// Done is closed once execution finishes, signaling the network routine
+	// that the messages sent by the client are no longer to be handed over to
+	// the stream.
+	Done chan struct{}
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+func (StartReplication) String() string {
+	return "StartReplication"
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -347,6 +386,21 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// DeliverNotifications is pushed when asynchronous notifications (see
+// notify.Listener) are received for the session. Upon execution, the pending
+// notifications are sent to the client if the session is not inside a
+// transaction; otherwise, they're sent once the transaction finishes.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -647,8 +701,22 @@ type ClientComm interface {
 	CreateEmptyQueryResult(pos CmdPos) EmptyQueryResult
 	// CreateCopyInResult creates a result for a Copy-in command.
 	CreateCopyInResult(pos CmdPos) CopyInResult
+	// CreateCopyOutResult creates a result for a Copy-out command. conv and
+	// location indicate how the values of the rows are encoded.
+	CreateCopyOutResult(
+		stmt *tree.CopyTo,
+		pos CmdPos,
+		conv sessiondatapb.DataConversionConfig,
+		location *time.Location,
+	) CopyOutResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateDeliverNotificationsResult creates a result for a
+	// DeliverNotifications command.
+	CreateDeliverNotificationsResult(pos CmdPos) DeliverNotificationsResult
+	// CreateStartReplicationResult creates a result for a StartReplication
+	// command.
+	CreateStartReplicationResult(pos CmdPos) StartReplicationResult
 
 	// lockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -773,6 +841,16 @@ type RestrictedCommandResult interface {
 	DisableBuffering()
 }
 
+// RetriableCommandResult is implemented by the CommandResults whose statement
+// can be retried on its own by transactions with per-statement read snapshots.
+type RetriableCommandResult interface {
+	// ResetForStmtRetry discards the results accumulated so far, including the
+	// error, so that the statement can be executed again. It returns false,
+	// without discarding anything, if some of the results have already been
+	// delivered to the client.
+	ResetForStmtRetry(ctx context.Context) bool
+}
+
 // DescribeResult represents the result of a Describe command (for either
 // describing a prepared statement or a portal).
 type DescribeResult interface {
@@ -816,6 +894,7 @@ type DeleteResult interface {
 // flushed.
 type SyncResult interface {
 	ResultBase
+	NotificationSender
 }
 
 // FlushResult represents the result of a Flush command. When this result is
@@ -830,6 +909,28 @@ type DrainResult interface {
 	ResultBase
 }
 
+// StartReplicationResult represents the result of a StartReplication command.
+// Closing this result produces no output for the client: the end of the
+// replication stream is sent on the connection directly.
+type StartReplicationResult interface {
+	ResultBase
+}
+
+// DeliverNotificationsResult represents the result of a DeliverNotifications
+// command. When closed, the buffered notifications are flushed to the client.
+type DeliverNotificationsResult interface {
+	ResultBase
+	NotificationSender
+}
+
+// NotificationSender is implemented by the results on which asynchronous
+// notifications can be delivered to the client.
+type NotificationSender interface {
+	// BufferNotification buffers a notification to be sent to the client
+	// when the result is closed.
+	BufferNotification(notify.Notification)
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
@@ -842,6 +943,34 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// CopyOutResult represents the result of a CopyOut command. Its rows are sent
+// to the client in CopyData messages. Closing the result finishes the Copy-out
+// subprotocol and produces a CommandComplete message, unless an error was set.
+type CopyOutResult interface {
+	ResultBase
+
+	// SendCopyOut starts the Copy-out subprotocol by sending the message
+	// describing the columns of the result. It needs to be called before any
+	// row is added.
+	SendCopyOut(ctx context.Context, cols colinfo.ResultColumns, opts CopyOutOptions) error
+
+	// AddRow encodes a row according to the options passed to SendCopyOut and
+	// sends it to the client.
+	AddRow(ctx context.Context, row tree.Datums) error
+
+	// RowsAffected returns the number of rows added so far.
+	RowsAffected() int
+}
+
+// CopyOutOptions describes how the rows of a CopyOutResult are encoded.
+type CopyOutOptions struct {
+	Format tree.CopyFormat
+	// Delimiter separates the values of a row in the text and CSV formats.
+	Delimiter byte
+	// Null is the representation of NULL values in the text and CSV formats.
+	Null string
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
@@ -939,6 +1068,11 @@ func (r *streamingCommandResult) BufferNotice(notice pgnotice.Notice) {
 	panic("unimplemented")
 }
 
+// BufferNotification is part of the NotificationSender interface.
+func (r *streamingCommandResult) BufferNotification(notify.Notification) {
+	panic("unimplemented")
+}
+
 // ResetStmtType is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) ResetStmtType(stmt tree.Statement) {
 	panic("unimplemented")
@@ -0,0 +1,195 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// copyOutMachine runs the query of a COPY TO statement and streams the rows it
+// produces to the client through a CopyOutResult, which encodes them in the
+// requested format.
+type copyOutMachine struct {
+	stmt *tree.CopyTo
+	res  CopyOutResult
+
+	txnOpt copyTxnOpt
+
+	// execQueryPlan runs the plan of the query that has been prepared in the
+	// planner, delivering its rows to res.
+	execQueryPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error
+
+	// p is the planner used to plan the query. preparePlannerForCopy() needs to
+	// be called before use.
+	p planner
+
+	// rowMemAcc accounts for the memory of the rows that are being encoded. The
+	// encoded rows are buffered by the connection and flushed to the network
+	// once enough of them have accumulated, so only the largest row seen so far
+	// is accounted for.
+	rowMemAcc mon.BoundAccount
+}
+
+// newCopyOutMachine creates a new copyOutMachine.
+func newCopyOutMachine(
+	n *tree.CopyTo,
+	res CopyOutResult,
+	txnOpt copyTxnOpt,
+	execCfg *ExecutorConfig,
+	execQueryPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error,
+) *copyOutMachine {
+	return &copyOutMachine{
+		stmt:   n,
+		res:    res,
+		txnOpt: txnOpt,
+		// The planner will be prepared before use.
+		p:             planner{execCfg: execCfg, alloc: &tree.DatumAlloc{}},
+		execQueryPlan: execQueryPlan,
+	}
+}
+
+// run plans and executes the query, sending the CopyOutResponse message
+// followed by the encoded rows to the client.
+func (c *copyOutMachine) run(ctx context.Context) (retErr error) {
+	cleanup := c.p.preparePlannerForCopy(ctx, c.txnOpt)
+	defer func() {
+		retErr = cleanup(ctx, retErr)
+	}()
+
+	opts, err := c.evalOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.p.stmt = Statement{}
+	c.p.stmt.AST = c.query()
+	if err := c.p.makeOptimizerPlan(ctx); err != nil {
+		return err
+	}
+	defer c.p.curPlan.close(ctx)
+
+	if err := c.res.SendCopyOut(ctx, c.p.curPlan.main.planColumns(), opts); err != nil {
+		return err
+	}
+
+	c.rowMemAcc = c.p.extendedEvalCtx.Mon.MakeBoundAccount()
+	defer c.rowMemAcc.Close(ctx)
+	res := streamingCommandResult{w: c}
+	if err := c.execQueryPlan(ctx, &c.p, &res); err != nil {
+		return err
+	}
+	return res.Err()
+}
+
+// query returns the statement whose rows are copied to the client.
+func (c *copyOutMachine) query() tree.Statement {
+	if c.stmt.Statement != nil {
+		return c.stmt.Statement
+	}
+	exprs := tree.SelectExprs{tree.StarSelectExpr()}
+	if len(c.stmt.Columns) > 0 {
+		exprs = make(tree.SelectExprs, len(c.stmt.Columns))
+		for i := range c.stmt.Columns {
+			exprs[i] = tree.SelectExpr{Expr: &tree.ColumnItem{ColumnName: c.stmt.Columns[i]}}
+		}
+	}
+	table := c.stmt.Table
+	return &tree.Select{
+		Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&table}},
+		},
+	}
+}
+
+// evalOptions evaluates the options of the statement, using the defaults of
+// the format for the options that are not specified.
+func (c *copyOutMachine) evalOptions(ctx context.Context) (CopyOutOptions, error) {
+	n := &c.stmt.Options
+	opts := CopyOutOptions{Format: n.CopyFormat}
+	switch opts.Format {
+	case tree.CopyFormatText:
+		opts.Null = `\N`
+		opts.Delimiter = '\t'
+	case tree.CopyFormatCSV:
+		opts.Null = ""
+		opts.Delimiter = ','
+	}
+
+	if n.Destination != nil {
+		return CopyOutOptions{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"DESTINATION unsupported in COPY TO")
+	}
+	if n.Delimiter != nil {
+		if opts.Format == tree.CopyFormatBinary {
+			return CopyOutOptions{}, errors.Newf("DELIMITER unsupported in BINARY format")
+		}
+		fn, err := c.p.TypeAsString(ctx, n.Delimiter, "COPY")
+		if err != nil {
+			return CopyOutOptions{}, err
+		}
+		delim, err := fn()
+		if err != nil {
+			return CopyOutOptions{}, err
+		}
+		if len(delim) != 1 || !utf8.ValidString(delim) {
+			return CopyOutOptions{}, errors.Newf("delimiter must be a single-byte character")
+		}
+		if delim[0] == '\n' || delim[0] == '\r' {
+			return CopyOutOptions{}, pgerror.Newf(pgcode.FeatureNotSupported,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		opts.Delimiter = delim[0]
+	}
+	if n.Null != nil {
+		if opts.Format == tree.CopyFormatBinary {
+			return CopyOutOptions{}, errors.Newf("NULL unsupported in BINARY format")
+		}
+		fn, err := c.p.TypeAsString(ctx, n.Null, "COPY")
+		if err != nil {
+			return CopyOutOptions{}, err
+		}
+		if opts.Null, err = fn(); err != nil {
+			return CopyOutOptions{}, err
+		}
+	}
+	return opts, nil
+}
+
+var _ ieResultWriter = &copyOutMachine{}
+
+// addResult is part of the ieResultWriter interface. The rows produced by the
+// query are passed on to the CopyOutResult.
+func (c *copyOutMachine) addResult(ctx context.Context, result ieIteratorResult) error {
+	if result.row == nil {
+		return nil
+	}
+	var sz int64
+	for _, d := range result.row {
+		sz += int64(d.Size())
+	}
+	if used := c.rowMemAcc.Used(); sz > used {
+		if err := c.rowMemAcc.Grow(ctx, sz-used); err != nil {
+			return err
+		}
+	}
+	return c.res.AddRow(ctx, result.row)
+}
+
+// finish is part of the ieResultWriter interface.
+func (c *copyOutMachine) finish() {}
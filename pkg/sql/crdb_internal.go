@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -2260,6 +2261,42 @@ func writeCreateTypeDescRow(
 			tree.NewDString(tree.AsString(node)),      // create_statement
 			enumLabelsDatum,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+		if err != nil {
+			return false, err
+		}
+		cfg := typeDesc.GetDomainConfig()
+		node := &tree.CreateDomain{
+			TypeName: name,
+			Type:     cfg.BaseType,
+			NotNull:  cfg.NotNull,
+		}
+		if cfg.DefaultExpr != nil {
+			if node.DefaultExpr, err = parser.ParseExpr(*cfg.DefaultExpr); err != nil {
+				return false, err
+			}
+		}
+		for i := range cfg.CheckConstraints {
+			c := &cfg.CheckConstraints[i]
+			expr, err := parser.ParseExpr(c.Expr)
+			if err != nil {
+				return false, err
+			}
+			node.CheckConstraints = append(node.CheckConstraints, tree.DomainCheckConstraint{
+				Name: tree.Name(c.Name),
+				Expr: expr,
+			})
+		}
+		return true, addRow(
+			tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+			tree.NewDString(db.GetName()),             // database_name
+			tree.NewDString(sc),                       // schema_name
+			tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+			tree.NewDString(typeDesc.GetName()),       // descriptor_name
+			tree.NewDString(tree.AsString(node)),      // create_statement
+			tree.DNull,
+		)
 	case descpb.TypeDescriptor_MULTIREGION_ENUM:
 		// Multi-region enums are created implicitly, so we don't have create
 		// statements for them.
@@ -0,0 +1,264 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createDomainNode struct {
+	n        *tree.CreateDomain
+	typeName *tree.TypeName
+	dbDesc   catalog.DatabaseDescriptor
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createDomainNode{n: nil}
+
+// CreateDomain creates a domain type.
+// Privileges: CREATE on the database.
+func (p *planner) CreateDomain(ctx context.Context, n *tree.CreateDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.DomainTypes) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"domain types are only available once the cluster is fully upgraded",
+		)
+	}
+
+	// Resolve the desired new type name.
+	typeName, db, err := resolveNewTypeName(p.RunParams(ctx), n.TypeName)
+	if err != nil {
+		return nil, err
+	}
+	n.TypeName.SetAnnotation(&p.semaCtx.Annotations, typeName)
+	return &createDomainNode{
+		n:        n,
+		typeName: typeName,
+		dbDesc:   db,
+	}, nil
+}
+
+func (n *createDomainNode) startExec(params runParams) error {
+	// Check if a type with the same name exists already.
+	flags := tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+		Required:    false,
+		AvoidLeased: true,
+	}}
+	found, _, err := params.p.Descriptors().GetImmutableTypeByName(params.ctx, params.p.Txn(), n.typeName, flags)
+	if err != nil {
+		return err
+	}
+	if found && n.n.IfNotExists {
+		params.p.BufferClientNotice(
+			params.ctx,
+			pgnotice.Newf("type %q already exists, skipping", n.typeName),
+		)
+		return nil
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("domain"))
+
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+
+	domainConfig, err := makeDomainConfig(params, n.typeName, n.n)
+	if err != nil {
+		return err
+	}
+
+	// Generate a stable ID for the new type.
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	privs := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		n.dbDesc.GetID(),
+		params.SessionData().User(),
+		tree.Types,
+		n.dbDesc.GetPrivileges(),
+	)
+
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Version:        1,
+		Privileges:     privs,
+		DomainConfig:   domainConfig,
+	}).BuildCreatedMutableType()
+
+	// Create the implicit array type for this type before finishing the type.
+	arrayTypeID, err := params.p.createArrayType(params, n.typeName, typeDesc, n.dbDesc, schema.GetID())
+	if err != nil {
+		return err
+	}
+	typeDesc.ArrayTypeID = arrayTypeID
+
+	// Now create the type after the implicit array type as been created.
+	if err := params.p.createDescriptorWithID(
+		params.ctx,
+		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, n.dbDesc.GetID(), schema.GetID(), n.typeName.Type()),
+		id,
+		typeDesc,
+		params.EvalContext().Settings,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	// Log the event.
+	return params.p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+// makeDomainConfig validates the definition of a new domain and returns its
+// DomainConfig.
+func makeDomainConfig(
+	params runParams, typeName *tree.TypeName, n *tree.CreateDomain,
+) (*descpb.TypeDescriptor_DomainConfig, error) {
+	baseType, err := tree.ResolveType(params.ctx, n.Type, params.p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDomainBaseType(baseType); err != nil {
+		return nil, err
+	}
+
+	cfg := &descpb.TypeDescriptor_DomainConfig{
+		BaseType: baseType,
+		NotNull:  n.NotNull,
+	}
+	if n.DefaultExpr != nil {
+		defaultExpr, err := validateDomainDefaultExpr(params, n.DefaultExpr, baseType)
+		if err != nil {
+			return nil, err
+		}
+		cfg.DefaultExpr = &defaultExpr
+	}
+	for i := range n.CheckConstraints {
+		if err := addDomainCheckConstraint(params, typeName.Type(), cfg, &n.CheckConstraints[i]); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// validateDomainBaseType returns an error if domains cannot be defined over the
+// given type. Only built-in scalar types are supported.
+func validateDomainBaseType(typ *types.T) error {
+	switch {
+	case typ.UserDefined():
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"domains over user-defined type %s are not supported", typ.SQLString())
+	case typ.Family() == types.ArrayFamily, typ.Family() == types.TupleFamily:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"domains over type %s are not supported", typ.SQLString())
+	case typ.Family() == types.AnyFamily, typ.Family() == types.UnknownFamily,
+		typ.Family() == types.VoidFamily:
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"%q is not a valid base type for a domain", typ.SQLString())
+	}
+	return nil
+}
+
+// validateDomainDefaultExpr type-checks the default expression of a domain
+// against its base type and returns the serialized expression.
+func validateDomainDefaultExpr(
+	params runParams, expr tree.Expr, baseType *types.T,
+) (string, error) {
+	typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+		params.ctx, expr, baseType, "DEFAULT", params.p.SemaCtx(), tree.VolatilityVolatile,
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
+
+// addDomainCheckConstraint validates the given CHECK constraint and adds it to
+// the DomainConfig. Like in Postgres, an unnamed constraint is named after the
+// domain.
+func addDomainCheckConstraint(
+	params runParams,
+	domainName string,
+	cfg *descpb.TypeDescriptor_DomainConfig,
+	c *tree.DomainCheckConstraint,
+) error {
+	inUse := make(map[string]struct{}, len(cfg.CheckConstraints))
+	for i := range cfg.CheckConstraints {
+		inUse[cfg.CheckConstraints[i].Name] = struct{}{}
+	}
+	name := string(c.Name)
+	if name == "" {
+		name = fmt.Sprintf("%s_check", domainName)
+		for i := 1; ; i++ {
+			if _, ok := inUse[name]; !ok {
+				break
+			}
+			name = fmt.Sprintf("%s_check%d", domainName, i)
+		}
+	} else if _, ok := inUse[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %s already exists", name, domainName)
+	}
+
+	expr, err := schemaexpr.ValidateDomainCheckExpr(params.ctx, c.Expr, cfg.BaseType, params.p.SemaCtx())
+	if err != nil {
+		return err
+	}
+	cfg.CheckConstraints = append(cfg.CheckConstraints, descpb.TypeDescriptor_DomainConfig_CheckConstraint{
+		Name: name,
+		Expr: expr,
+	})
+	return nil
+}
+
+func (n *createDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createDomainNode) Close(ctx context.Context)           {}
+func (n *createDomainNode) ReadingOwnWrites()                   {}
@@ -0,0 +1,335 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+// createFunctionNode represents a CREATE FUNCTION statement.
+type createFunctionNode struct {
+	cf *tree.CreateFunction
+	// funcBody is the body of the function, with all data source names fully
+	// qualified.
+	funcBody string
+	dbDesc   catalog.DatabaseDescriptor
+	scDesc   catalog.SchemaDescriptor
+
+	// planDeps tracks which tables and views the body of the function depends
+	// on. This is collected during the construction of the logical plan of the
+	// body.
+	planDeps planDependencies
+
+	// typeDeps tracks which types the signature and the body of the function
+	// depend on.
+	typeDeps typeDependencies
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if !params.p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.UserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"user-defined functions are only available once the cluster is fully upgraded")
+	}
+	if n.cf.Replace {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("or_replace_function"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+	}
+
+	fnName := n.cf.FuncName.Object()
+	log.VEventf(params.ctx, 2, "dependencies for function %s:\n%s", fnName, n.planDeps.String())
+
+	// Functions are looked up by name through their parent schema, so the
+	// schema must be backed by a descriptor.
+	if n.scDesc.SchemaKind() != catalog.SchemaUserDefined {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot create functions in schema %q", n.scDesc.GetName())
+	}
+	// Calls to functions are resolved against builtins first, so a function
+	// with the name of a builtin could never be called.
+	if _, ok := tree.FunDefs[fnName]; ok {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q already exists as a built-in function", fnName)
+	}
+
+	args := make([]descpb.FunctionDescriptor_Argument, len(n.cf.Args))
+	argTypes := make([]*types.T, len(n.cf.Args))
+	for i := range n.cf.Args {
+		typ, err := tree.ResolveType(params.ctx, n.cf.Args[i].Type, params.p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return err
+		}
+		args[i] = descpb.FunctionDescriptor_Argument{Name: string(n.cf.Args[i].Name), Type: typ}
+		argTypes[i] = typ
+	}
+	retType, err := tree.ResolveType(params.ctx, n.cf.ReturnType, params.p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+
+	// Look for an existing overload with the same signature. Since overloads
+	// are resolved together, an overload which returns a set cannot be mixed
+	// with one which returns a single value.
+	mutScDesc, err := params.p.Descriptors().GetMutableDescriptorByID(params.ctx, n.scDesc.GetID(), params.p.txn)
+	if err != nil {
+		return err
+	}
+	sc, ok := mutScDesc.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("expected schema descriptor, found %T", mutScDesc)
+	}
+	var replacing *funcdesc.Mutable
+	if fn, ok := sc.GetFunction(fnName); ok {
+		for _, id := range fn.OverloadIDs {
+			existing, err := params.p.Descriptors().GetMutableFunctionByID(params.ctx, params.p.txn, id,
+				tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+			if err != nil {
+				return err
+			}
+			if !funcArgTypesMatch(existing.ArgTypes(), argTypes) {
+				if existing.ReturnSet != n.cf.ReturnSet {
+					return unimplemented.NewWithIssuef(17511,
+						"overloads of function %q cannot mix set-returning and scalar return types", fnName)
+				}
+				continue
+			}
+			if !n.cf.Replace {
+				return sqlerrors.NewFunctionAlreadyExistsError(funcSignature(fnName, argTypes))
+			}
+			hasOwnership, err := params.p.HasOwnership(params.ctx, existing)
+			if err != nil {
+				return err
+			}
+			if !hasOwnership {
+				return pgerror.Newf(pgcode.InsufficientPrivilege,
+					"must be owner of function %s", funcSignature(fnName, argTypes))
+			}
+			if existing.ReturnSet != n.cf.ReturnSet || !existing.ReturnType.Identical(retType) {
+				return errors.WithHintf(
+					pgerror.New(pgcode.InvalidFunctionDefinition, "cannot change return type of existing function"),
+					"Use DROP FUNCTION %s first.", funcSignature(fnName, argTypes),
+				)
+			}
+			replacing = existing
+		}
+	}
+
+	// The relations and types which the function depends on. Table-typed
+	// arguments and return values reference the implicit record type of the
+	// table, which is tracked as a dependency on the table itself.
+	var dependsOn, dependsOnTypes []descpb.ID
+	for id, dep := range n.planDeps {
+		if dep.desc.IsVirtualTable() {
+			continue
+		}
+		dependsOn = append(dependsOn, id)
+	}
+	for id := range n.typeDeps {
+		desc, err := params.p.Descriptors().GetImmutableDescriptorByID(params.ctx, params.p.txn, id,
+			tree.CommonLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		if tbl, ok := desc.(catalog.TableDescriptor); ok {
+			if !tbl.IsVirtualTable() {
+				dependsOn = append(dependsOn, id)
+			}
+			continue
+		}
+		dependsOnTypes = append(dependsOnTypes, id)
+	}
+	for _, id := range dependsOn {
+		tbl, err := params.p.Descriptors().GetImmutableTableByID(params.ctx, params.p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return err
+		}
+		if tbl.GetParentID() != n.dbDesc.GetID() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"the function cannot refer to other databases")
+		}
+		if tbl.IsTemporary() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"the function cannot refer to temporary relation %q", tbl.GetName())
+		}
+	}
+
+	var fnDesc *funcdesc.Mutable
+	if replacing != nil {
+		if err := params.p.removeFunctionBackReferences(params.ctx, replacing); err != nil {
+			return err
+		}
+		replacing.Args = args
+		fnDesc = replacing
+	} else {
+		id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB, params.p.ExecCfg().Codec)
+		if err != nil {
+			return err
+		}
+		// Like in Postgres, everyone may execute a newly created function.
+		privs := descpb.NewBasePrivilegeDescriptor(params.SessionData().User())
+		privs.Grant(security.PublicRoleName(), privilege.List{privilege.EXECUTE}, false /* withGrantOption */)
+		desc := funcdesc.NewMutableFunctionDescriptor(
+			id,
+			n.dbDesc.GetID(),
+			n.scDesc.GetID(),
+			fnName,
+			args,
+			retType,
+			n.cf.ReturnSet,
+			privs,
+		)
+		fnDesc = &desc
+	}
+	setFunctionOptions(fnDesc, n.cf.Options)
+	fnDesc.SetBody(n.funcBody, dependsOn, dependsOnTypes)
+
+	if err := params.p.addFunctionBackReferences(params.ctx, fnDesc); err != nil {
+		return err
+	}
+
+	if replacing != nil {
+		if err := params.p.writeFunctionDescChange(
+			params.ctx, fnDesc, tree.AsStringWithFQNames(n.cf, params.Ann()),
+		); err != nil {
+			return err
+		}
+	} else {
+		sc.AddFunction(fnName, fnDesc.GetID())
+		if err := params.p.writeSchemaDescChange(
+			params.ctx, sc, fmt.Sprintf("adding function %q to schema %q", fnName, sc.GetName()),
+		); err != nil {
+			return err
+		}
+		if err := params.p.Descriptors().WriteDesc(
+			params.ctx, params.extendedEvalCtx.Tracing.KVTracingEnabled(), fnDesc, params.p.txn,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := validateDescriptor(params.ctx, params.p, fnDesc); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return params.p.logEvent(params.ctx,
+		fnDesc.GetID(),
+		&eventpb.CreateFunction{
+			FunctionName: tree.AsStringWithFQNames(n.cf.FuncName, params.Ann()),
+			Owner:        fnDesc.GetPrivileges().Owner().Normalized(),
+		})
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createFunctionNode) Close(ctx context.Context)  {}
+
+// setFunctionOptions sets the attributes of the function descriptor according
+// to the options of a CREATE FUNCTION statement. Attributes which are not
+// specified are reset to their defaults, like in Postgres.
+func setFunctionOptions(fnDesc *funcdesc.Mutable, options tree.FunctionOptions) {
+	fnDesc.Volatility = descpb.FunctionDescriptor_VOLATILE
+	fnDesc.LeakProof = false
+	fnDesc.NullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+	for _, option := range options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			switch t {
+			case tree.FunctionStable:
+				fnDesc.Volatility = descpb.FunctionDescriptor_STABLE
+			case tree.FunctionImmutable:
+				fnDesc.Volatility = descpb.FunctionDescriptor_IMMUTABLE
+			}
+		case tree.FunctionLeakproof:
+			fnDesc.LeakProof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			if t != tree.FunctionCalledOnNullInput {
+				fnDesc.NullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+			}
+		}
+	}
+}
+
+// addFunctionBackReferences adds references to the function to all the
+// relations and types it depends on. Using a type in a function requires the
+// USAGE privilege on the type.
+func (p *planner) addFunctionBackReferences(ctx context.Context, fnDesc *funcdesc.Mutable) error {
+	for _, id := range fnDesc.DependsOn {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if !containsDescID(tbl.DependedOnByFunctions, fnDesc.GetID()) {
+			tbl.DependedOnByFunctions = append(tbl.DependedOnByFunctions, fnDesc.GetID())
+		}
+		if err := p.writeSchemaChange(
+			ctx, tbl, descpb.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)",
+				fnDesc.GetName(), tbl.GetName(), tbl.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	for _, id := range fnDesc.DependsOnTypes {
+		typ, err := p.Descriptors().GetMutableTypeVersionByID(ctx, p.txn, id)
+		if err != nil {
+			return err
+		}
+		if err := p.CheckPrivilege(ctx, typ, privilege.USAGE); err != nil {
+			return err
+		}
+		typ.AddReferencingFunctionID(fnDesc.GetID())
+		if err := p.writeTypeSchemaChange(
+			ctx, typ, fmt.Sprintf("updating type back reference %d for function %d", id, fnDesc.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsDescID returns true if ids contains id.
+func containsDescID(ids []descpb.ID, id descpb.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
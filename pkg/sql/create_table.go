@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -366,6 +368,12 @@ func (n *createTableNode) startExec(params runParams) error {
 			return err
 		}
 
+		if desc.HasRowLevelTTL() {
+			return unimplemented.New(
+				"ttl-ctas", "CREATE TABLE ... AS is not supported with row level TTL",
+			)
+		}
+
 		// If we have an implicit txn we want to run CTAS async, and consequently
 		// ensure it gets queued as a SchemaChange.
 		if params.p.ExtendedEvalContext().TxnImplicit {
@@ -400,6 +408,18 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	// Create the schedule of the job deleting expired rows. The schedule ID is
+	// recorded in the descriptor, so this must happen before it is written.
+	if ttl := desc.GetRowLevelTTL(); ttl != nil {
+		sj, err := createRowLevelTTLScheduledJob(
+			params.ctx, params.ExecCfg(), params.p.txn, params.p.User(), desc.GetID(), ttl,
+		)
+		if err != nil {
+			return err
+		}
+		ttl.ScheduleID = sj.ScheduleID()
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx,
@@ -637,6 +657,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		descpb.ConstraintDeferrability_NOT_DEFERRABLE,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -647,7 +668,9 @@ func addUniqueWithoutIndexColumnTableDef(
 
 // addUniqueWithoutIndexTableDef runs various checks on the given
 // UniqueConstraintTableDef before adding it as a UNIQUE WITHOUT INDEX
-// constraint to the given table descriptor.
+// constraint to the given table descriptor. It is also used to add the
+// constraint which enforces a DEFERRABLE unique constraint with an index (see
+// checkDeferrableUniqueConstraint).
 func addUniqueWithoutIndexTableDef(
 	ctx context.Context,
 	evalCtx *tree.EvalContext,
@@ -659,20 +682,25 @@ func addUniqueWithoutIndexTableDef(
 	validationBehavior tree.ValidationBehavior,
 	semaCtx *tree.SemaContext,
 ) error {
-	if !sessionData.EnableUniqueWithoutIndexConstraints {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"unique constraints without an index are not yet supported",
-		)
-	}
-	if len(d.Storing) > 0 {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"unique constraints without an index cannot store columns",
-		)
+	if d.WithoutIndex {
+		if !sessionData.EnableUniqueWithoutIndexConstraints {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"unique constraints without an index are not yet supported",
+			)
+		}
+		if len(d.Storing) > 0 {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"unique constraints without an index cannot store columns",
+			)
+		}
+		if d.PartitionByIndex.ContainsPartitions() {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"partitioned unique constraints without an index are not supported",
+			)
+		}
 	}
-	if d.PartitionByIndex.ContainsPartitions() {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"partitioned unique constraints without an index are not supported",
-		)
+	if err := checkConstraintDeferrability(ctx, evalCtx.Settings, d.Deferrability); err != nil {
+		return err
 	}
 
 	// If there is a predicate, validate it.
@@ -693,7 +721,8 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate,
+		descpb.ConstraintDeferrabilityValue[d.Deferrability], ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -713,6 +742,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability descpb.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -766,11 +796,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Predicate: predicate,
-		Validity:  validity,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		Deferrability: deferrability,
 	}
 
 	if ts == NewTable {
@@ -782,6 +813,161 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// checkConstraintDeferrability returns an error if a new constraint cannot be
+// declared with the given deferrability because the cluster is not fully
+// upgraded.
+func checkConstraintDeferrability(
+	ctx context.Context, st *cluster.Settings, deferrability tree.ConstraintDeferrability,
+) error {
+	if deferrability.Deferrable() && !st.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"DEFERRABLE constraints are only available once the cluster is fully upgraded",
+		)
+	}
+	return nil
+}
+
+// makeExclusionConstraint runs various checks on the given
+// ExclusionConstraintTableDef and returns the descriptor of the corresponding
+// exclusion constraint, which is not yet added to the given table descriptor.
+func makeExclusionConstraint(
+	ctx context.Context,
+	st *cluster.Settings,
+	d *tree.ExclusionConstraintTableDef,
+	desc *tabledesc.Mutable,
+	tn tree.TableName,
+	semaCtx *tree.SemaContext,
+) (descpb.ExclusionConstraint, error) {
+	if !st.Version.IsActive(ctx, clusterversion.ExclusionConstraints) {
+		return descpb.ExclusionConstraint{}, pgerror.New(pgcode.FeatureNotSupported,
+			"exclusion constraints are only available once the cluster is fully upgraded",
+		)
+	}
+
+	colNames := make([]string, len(d.Elems))
+	columnIDs := make(descpb.ColumnIDs, len(d.Elems))
+	operators := make([]string, len(d.Elems))
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		if elem.Elem.Expr != nil {
+			return descpb.ExclusionConstraint{}, unimplemented.NewWithIssueDetail(46657,
+				"exclusion constraint expression",
+				"exclusion constraints on expressions are not supported",
+			)
+		}
+		col, err := desc.FindColumnWithName(elem.Elem.Column)
+		if err != nil {
+			return descpb.ExclusionConstraint{}, err
+		}
+		if !col.Public() {
+			return descpb.ExclusionConstraint{}, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"column %q is not yet available; add the column first, then add the exclusion constraint",
+				col.GetName(),
+			)
+		}
+		op := elem.Operator.Symbol
+		if _, ok := descpb.ExclusionConstraintOperators[op.String()]; !ok {
+			return descpb.ExclusionConstraint{}, unimplemented.NewWithIssueDetailf(46657,
+				"exclusion constraint operator",
+				"operator %s is not supported in exclusion constraints; only = and && are supported",
+				op,
+			)
+		}
+		if _, ok := tree.CmpOps[op].LookupImpl(col.GetType(), col.GetType()); !ok {
+			return descpb.ExclusionConstraint{}, pgerror.Newf(pgcode.UndefinedFunction,
+				"operator does not exist: %s %s %s", col.GetType().SQLString(), op, col.GetType().SQLString(),
+			)
+		}
+		colNames[i] = col.GetName()
+		columnIDs[i] = col.GetID()
+		operators[i] = op.String()
+	}
+
+	// If there is a predicate, validate it.
+	var predicate string
+	if d.Predicate != nil {
+		var err error
+		predicate, _, _, err = schemaexpr.DequalifyAndValidateExpr(
+			ctx,
+			desc,
+			d.Predicate,
+			types.Bool,
+			"exclusion constraint predicate",
+			semaCtx,
+			tree.VolatilityImmutable,
+			&tn,
+		)
+		if err != nil {
+			return descpb.ExclusionConstraint{}, err
+		}
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintInfo, err := desc.GetConstraintInfo()
+	if err != nil {
+		return descpb.ExclusionConstraint{}, err
+	}
+	name := string(d.Name)
+	if name == "" {
+		name = tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", desc.Name, strings.Join(colNames, "_")),
+			func(p string) bool {
+				_, ok := constraintInfo[p]
+				return ok
+			},
+		)
+	} else if _, ok := constraintInfo[name]; ok {
+		return descpb.ExclusionConstraint{}, pgerror.Newf(pgcode.DuplicateObject,
+			"duplicate constraint name: %q", name)
+	}
+
+	return descpb.ExclusionConstraint{
+		Name:        name,
+		TableID:     desc.ID,
+		ColumnIDs:   columnIDs,
+		Operators:   operators,
+		Predicate:   predicate,
+		Validity:    descpb.ConstraintValidity_Validated,
+		IndexMethod: d.IndexMethod,
+	}, nil
+}
+
+// checkDeferrableUniqueConstraint checks that a DEFERRABLE unique constraint
+// with an index can be created.
+//
+// Unique indexes are checked as the rows are written, so they cannot be
+// deferred. CREATE TABLE and ALTER TABLE ... ADD CONSTRAINT therefore rewrite
+//
+//   CONSTRAINT c UNIQUE (a, b) DEFERRABLE
+//
+// into two parts:
+//
+//   - a UNIQUE WITHOUT INDEX constraint named c on (a, b), with the same
+//     deferrability, which enforces the uniqueness with checks that are run at
+//     the end of the statement or, when deferred, before the transaction
+//     commits (see deferredConstraints).
+//   - a non-unique index on (a, b), with an automatically generated name, which
+//     serves the lookups of these checks.
+//
+// This is also how the constraint is shown by SHOW CREATE TABLE. A partitioned
+// unique constraint or one on an expression can't be rewritten this way, since
+// UNIQUE WITHOUT INDEX constraints support neither.
+func checkDeferrableUniqueConstraint(d *tree.UniqueConstraintTableDef) error {
+	if d.PartitionByIndex.ContainsPartitioningClause() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"DEFERRABLE unique constraints cannot be partitioned",
+		)
+	}
+	for _, column := range d.Columns {
+		if column.Expr != nil {
+			return pgerror.New(pgcode.InvalidTableDefinition,
+				"cannot create a DEFERRABLE unique constraint on an expression",
+			)
+		}
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -820,6 +1006,10 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if err := checkConstraintDeferrability(ctx, evalCtx.Settings, d.Deferrability); err != nil {
+		return err
+	}
+
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -1025,6 +1215,7 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrability:       descpb.ConstraintDeferrabilityValue[d.Deferrability],
 	}
 
 	if ts == NewTable {
@@ -1277,11 +1468,55 @@ func NewTableDesc(
 		semaCtx,
 		evalCtx,
 		n.StorageParams,
-		&paramparse.TableStorageParamObserver{},
+		&paramparse.TableStorageParamObserver{TableDesc: &desc.TableDescriptor},
 	); err != nil {
 		return nil, err
 	}
 
+	// Add the hidden expiration column of row-level TTL, unless the table
+	// definition already has it.
+	if ttl := desc.GetRowLevelTTL(); ttl != nil {
+		if !st.Version.IsActive(ctx, clusterversion.RowLevelTTL) {
+			return nil, pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"row level TTL is only available once the cluster is fully upgraded",
+			)
+		}
+		if persistence.IsTemporary() {
+			return nil, pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"row level TTL is not supported on temporary tables",
+			)
+		}
+		ttlColExists := false
+		for _, def := range n.Defs {
+			if d, ok := def.(*tree.ColumnTableDef); ok && d.Name == catpb.TTLDefaultExpirationColumnName {
+				t, err := tree.ResolveType(ctx, d.Type, vt)
+				if err != nil {
+					return nil, err
+				}
+				if t.Family() != types.TimestampTZFamily {
+					return nil, pgerror.Newf(
+						pgcode.InvalidTableDefinition,
+						"column %s of a table with row level TTL must be of type %s, not %s",
+						catpb.TTLDefaultExpirationColumnName,
+						types.TimestampTZ.SQLString(),
+						t.SQLString(),
+					)
+				}
+				ttlColExists = true
+			}
+		}
+		if !ttlColExists {
+			def, err := rowLevelTTLAutomaticColumnDef(ttl)
+			if err != nil {
+				return nil, err
+			}
+			n.Defs = append(n.Defs, def)
+			cdd = append(cdd, nil)
+		}
+	}
+
 	indexEncodingVersion := descpb.LatestNonPrimaryIndexDescriptorVersion
 	isRegionalByRow := n.Locality != nil && n.Locality.LocalityLevel == tree.LocalityLevelRow
 
@@ -1795,10 +2030,18 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			// The index of a DEFERRABLE unique constraint is not unique, and is
+			// left unnamed since the constraint is added with its name below.
+			deferrable := d.Deferrability.Deferrable()
+			if deferrable {
+				if err := checkDeferrableUniqueConstraint(d); err != nil {
+					return nil, err
+				}
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
-			if d.Name != "" {
+			if d.Name != "" && !deferrable {
 				if idx, _ := desc.FindIndexWithName(d.Name.String()); idx != nil {
 					return nil, pgerror.Newf(pgcode.DuplicateRelation, "duplicate index name: %q", d.Name)
 				}
@@ -1821,10 +2064,13 @@ func NewTableDesc(
 			}
 			idx := descpb.IndexDescriptor{
 				Name:             string(d.Name),
-				Unique:           true,
+				Unique:           !deferrable,
 				StoreColumnNames: d.Storing.ToStrings(),
 				Version:          indexEncodingVersion,
 			}
+			if deferrable {
+				idx.Name = ""
+			}
 			columns := d.Columns
 			if d.Sharded != nil {
 				if isRegionalByRow {
@@ -1901,7 +2147,8 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2067,7 +2314,7 @@ func NewTableDesc(
 			}
 
 		case *tree.UniqueConstraintTableDef:
-			if d.WithoutIndex {
+			if d.WithoutIndex || d.Deferrability.Deferrable() {
 				if err := addUniqueWithoutIndexTableDef(
 					ctx, evalCtx, sessionData, d, &desc, n.Table, NewTable, tree.ValidationDefault, semaCtx,
 				); err != nil {
@@ -2093,6 +2340,13 @@ func NewTableDesc(
 				return nil, err
 			}
 
+		case *tree.ExclusionConstraintTableDef:
+			ec, err := makeExclusionConstraint(ctx, st, d, &desc, n.Table, semaCtx)
+			if err != nil {
+				return nil, err
+			}
+			desc.ExclusionConstraints = append(desc.ExclusionConstraints, ec)
+
 		default:
 			return nil, errors.Errorf("unsupported table def: %T", def)
 		}
@@ -2420,6 +2674,34 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					}
 				}
 			}
+			for _, c := range td.ExclusionConstraints {
+				def := tree.ExclusionConstraintTableDef{
+					Name:        tree.Name(c.Name),
+					IndexMethod: c.IndexMethod,
+					Elems:       make(tree.ExclusionConstraintElemList, len(c.ColumnIDs)),
+				}
+				colNames, err := td.NamesForColumnIDs(c.ColumnIDs)
+				if err != nil {
+					return nil, err
+				}
+				for i := range colNames {
+					op, err := c.Operator(i)
+					if err != nil {
+						return nil, err
+					}
+					def.Elems[i] = tree.ExclusionConstraintElem{
+						Elem:     tree.IndexElem{Column: tree.Name(colNames[i])},
+						Operator: tree.MakeComparisonOperator(op),
+					}
+				}
+				if c.IsPartial() {
+					def.Predicate, err = parser.ParseExpr(c.Predicate)
+					if err != nil {
+						return nil, err
+					}
+				}
+				defs = append(defs, &def)
+			}
 		}
 		if opts.Has(tree.LikeTableOptIndexes) {
 			for _, idx := range td.NonDropIndexes() {
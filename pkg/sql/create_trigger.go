@@ -0,0 +1,200 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+	fnDesc    catalog.FunctionDescriptor
+}
+
+// CreateTrigger creates a row-level trigger on a table.
+// Privileges: CREATE on table and EXECUTE on the trigger function.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if tableDesc.IsTemporary() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot create triggers on temporary table %q", tableDesc.GetName())
+	}
+
+	fnDesc, err := p.getTriggerFunction(ctx, n.FuncName, tableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	if err := checkTriggerFunction(n, tableDesc, fnDesc); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, fnDesc: fnDesc}, nil
+}
+
+// getTriggerFunction returns the user-defined function with the given name
+// which takes a single argument of the implicit record type of the table.
+func (p *planner) getTriggerFunction(
+	ctx context.Context, name *tree.UnresolvedObjectName, tableDesc catalog.TableDescriptor,
+) (catalog.FunctionDescriptor, error) {
+	rowTypeOID := typedesc.TypeIDToOID(tableDesc.GetID())
+	_, ids, err := p.resolveFunctionSchema(ctx, name, p.CurrentSearchPath())
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return nil, err
+		}
+		if argTypes := fnDesc.ArgTypes(); len(argTypes) == 1 && argTypes[0].Oid() == rowTypeOID {
+			return fnDesc, nil
+		}
+	}
+	return nil, sqlerrors.NewUndefinedFunctionError(
+		tree.AsString(name) + "(" + tree.NameString(tableDesc.GetName()) + ")",
+	)
+}
+
+// checkTriggerFunction checks that the function can be invoked by the given
+// trigger. The function of a BEFORE trigger returns the row to write in place
+// of the new row, so it must return the row type of the table. The function of
+// an AFTER trigger inserts rows and returns VOID.
+func checkTriggerFunction(
+	n *tree.CreateTrigger, tableDesc catalog.TableDescriptor, fnDesc catalog.FunctionDescriptor,
+) error {
+	stmt, err := parser.ParseOne(fnDesc.GetFunctionBody())
+	if err != nil {
+		return err
+	}
+	switch n.ActionTime {
+	case tree.TriggerBefore:
+		if fnDesc.GetReturnSet() || fnDesc.GetReturnType().Oid() != typedesc.TypeIDToOID(tableDesc.GetID()) {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"function %s must return type %s to be used by a BEFORE trigger",
+				fnDesc.GetName(), tree.NameString(tableDesc.GetName()))
+		}
+		if _, ok := stmt.AST.(*tree.Select); !ok {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"function %s must have a SELECT body to be used by a BEFORE trigger", fnDesc.GetName())
+		}
+	case tree.TriggerAfter:
+		if fnDesc.GetReturnType().Family() != types.VoidFamily {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"function %s must return VOID to be used by an AFTER trigger", fnDesc.GetName())
+		}
+		if _, ok := stmt.AST.(*tree.Insert); !ok {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"function %s must have an INSERT body to be used by an AFTER trigger", fnDesc.GetName())
+		}
+	}
+	return nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	if !params.p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.RowLevelTriggers) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"triggers are only available once the cluster is fully upgraded")
+	}
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	for i := range n.tableDesc.Triggers {
+		if n.tableDesc.Triggers[i].Name == string(n.n.Name) {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", n.n.Name, n.tableDesc.GetName())
+		}
+	}
+
+	trigger := descpb.TriggerDescriptor{
+		Name:       string(n.n.Name),
+		FunctionID: n.fnDesc.GetID(),
+	}
+	switch n.n.ActionTime {
+	case tree.TriggerBefore:
+		trigger.ActionTime = descpb.TriggerDescriptor_BEFORE
+	case tree.TriggerAfter:
+		trigger.ActionTime = descpb.TriggerDescriptor_AFTER
+	}
+	for _, event := range n.n.Events {
+		var e descpb.TriggerDescriptor_Event
+		switch event {
+		case tree.TriggerEventInsert:
+			e = descpb.TriggerDescriptor_INSERT
+		case tree.TriggerEventUpdate:
+			e = descpb.TriggerDescriptor_UPDATE
+		case tree.TriggerEventDelete:
+			e = descpb.TriggerDescriptor_DELETE
+		}
+		trigger.Events = append(trigger.Events, e)
+	}
+	n.tableDesc.Triggers = append(n.tableDesc.Triggers, trigger)
+
+	if err := validateDescriptor(params.ctx, params.p, n.tableDesc); err != nil {
+		return err
+	}
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Log a Create Trigger event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.GetID(),
+		&eventpb.CreateTrigger{
+			TableName:   tree.AsStringWithFQNames(&n.n.Table, params.Ann()),
+			TriggerName: string(n.n.Name),
+		})
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
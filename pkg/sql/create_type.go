@@ -211,7 +211,7 @@ func findFreeArrayTypeName(
 }
 
 // CreateEnumArrayTypeDesc creates a type descriptor for the array of the
-// given enum.
+// given enum or domain.
 func CreateEnumArrayTypeDesc(
 	params runParams,
 	typDesc *typedesc.Mutable,
@@ -226,6 +226,10 @@ func CreateEnumArrayTypeDesc(
 	switch t := typDesc.Kind; t {
 	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
 		elemTyp = types.MakeEnum(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id))
+	case descpb.TypeDescriptor_DOMAIN:
+		elemTyp = types.MakeDomain(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), typDesc.DomainConfig.BaseType,
+		)
 	default:
 		return nil, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -65,6 +65,11 @@ type createViewNode struct {
 	// depends on. This is collected during the construction of
 	// the view query's logical plan.
 	typeDeps typeDependencies
+
+	// funcDeps tracks which user-defined functions the view being
+	// created depends on. This is collected during the construction
+	// of the view query's logical plan.
+	funcDeps funcDependencies
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -256,6 +261,13 @@ func (n *createViewNode) startExec(params runParams) error {
 		}
 		desc.DependsOnTypes = append(desc.DependsOnTypes, orderedTypeDeps.Ordered()...)
 
+		// Collect all user-defined functions this view depends on.
+		orderedFuncDeps := catalog.DescriptorIDSet{}
+		for backrefID := range n.funcDeps {
+			orderedFuncDeps.Add(backrefID)
+		}
+		desc.DependsOnFunctions = append(desc.DependsOnFunctions, orderedFuncDeps.Ordered()...)
+
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
 		if err = params.p.createDescriptorWithID(
@@ -311,6 +323,13 @@ func (n *createViewNode) startExec(params runParams) error {
 		}
 	}
 
+	// Add back references for the user-defined function dependencies.
+	for id := range n.funcDeps {
+		if err := params.p.addFunctionBackReference(params.ctx, id, newDesc); err != nil {
+			return err
+		}
+	}
+
 	if err := validateDescriptor(params.ctx, params.p, newDesc); err != nil {
 		return err
 	}
@@ -565,6 +584,16 @@ func (p *planner) replaceViewDesc(
 		return nil, err
 	}
 
+	// For each old user-defined function dependency, see if we still depend
+	// on it. If not, then remove the back reference.
+	for _, id := range toReplace.DependsOnFunctions {
+		if _, ok := n.funcDeps[id]; !ok {
+			if err := p.removeFunctionBackReference(ctx, id, toReplace); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Since the view query has been replaced, the dependencies that this
 	// table descriptor had are gone.
 	toReplace.DependsOn = make([]descpb.ID, 0, len(n.planDeps))
@@ -575,6 +604,11 @@ func (p *planner) replaceViewDesc(
 	for backrefID := range n.typeDeps {
 		toReplace.DependsOnTypes = append(toReplace.DependsOnTypes, backrefID)
 	}
+	orderedFuncDeps := catalog.DescriptorIDSet{}
+	for backrefID := range n.funcDeps {
+		orderedFuncDeps.Add(backrefID)
+	}
+	toReplace.DependsOnFunctions = orderedFuncDeps.Ordered()
 
 	// Since we are replacing an existing view here, we need to write the new
 	// descriptor into place.
@@ -0,0 +1,311 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+)
+
+// constraintMode is the checking mode of a DEFERRABLE constraint in a
+// transaction, as set by SET CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault indicates that the constraint is checked according
+	// to its INITIALLY DEFERRED or INITIALLY IMMEDIATE declaration.
+	constraintModeDefault constraintMode = iota
+	// constraintModeImmediate indicates that the constraint is checked at the
+	// end of each statement.
+	constraintModeImmediate
+	// constraintModeDeferred indicates that the constraint is checked when the
+	// transaction commits.
+	constraintModeDeferred
+)
+
+// deferredConstraints tracks the DEFERRABLE constraint checks that were
+// postponed in the current transaction, along with the constraint modes set by
+// SET CONSTRAINTS. It lives in the connExecutor's extraTxnState and is reset
+// for every transaction.
+type deferredConstraints struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintMode
+	// byName contains the modes set by SET CONSTRAINTS for individual
+	// constraints. These take precedence over allMode, and are cleared by SET
+	// CONSTRAINTS ALL.
+	byName map[string]constraintMode
+
+	// pending contains the checks that must be run before the transaction
+	// commits. seen contains the keys of the pending checks, so that the same
+	// key is only checked once.
+	pending []deferredCheck
+	seen    map[string]struct{}
+}
+
+// deferredCheck is a postponed check of a foreign key or unique constraint
+// for a single key.
+type deferredCheck struct {
+	tableID           descpb.ID
+	constraintName    string
+	isUnique          bool
+	initiallyDeferred bool
+	// keyVals are the values of the constraint columns that may violate the
+	// constraint.
+	keyVals tree.Datums
+	// err is the error that is returned if the constraint is still violated
+	// when the check is run.
+	err error
+}
+
+// isDeferred returns whether the checks of the given constraint are
+// currently deferred.
+func (dc *deferredConstraints) isDeferred(name string, initiallyDeferred bool) bool {
+	mode, ok := dc.byName[name]
+	if !ok {
+		mode = dc.allMode
+	}
+	switch mode {
+	case constraintModeImmediate:
+		return false
+	case constraintModeDeferred:
+		return true
+	default:
+		return initiallyDeferred
+	}
+}
+
+// setMode changes the mode of the given constraints, or of all constraints if
+// names is nil.
+func (dc *deferredConstraints) setMode(names tree.NameList, deferred bool) {
+	mode := constraintModeImmediate
+	if deferred {
+		mode = constraintModeDeferred
+	}
+	if names == nil {
+		dc.allMode = mode
+		dc.byName = nil
+		return
+	}
+	if dc.byName == nil {
+		dc.byName = make(map[string]constraintMode)
+	}
+	for _, name := range names {
+		dc.byName[string(name)] = mode
+	}
+}
+
+// add records a check that was postponed until commit time.
+func (dc *deferredConstraints) add(check *exec.DeferrableCheck, row tree.Datums, err error) {
+	keyVals := check.MkKeyVals(row)
+	var key strings.Builder
+	fmt.Fprintf(&key, "%d/%s/", check.TableID, check.ConstraintName)
+	for _, d := range keyVals {
+		key.WriteString(d.String())
+		key.WriteByte('/')
+	}
+	if _, ok := dc.seen[key.String()]; ok {
+		return
+	}
+	if dc.seen == nil {
+		dc.seen = make(map[string]struct{})
+	}
+	dc.seen[key.String()] = struct{}{}
+	dc.pending = append(dc.pending, deferredCheck{
+		tableID:           descpb.ID(check.TableID),
+		constraintName:    check.ConstraintName,
+		isUnique:          check.IsUnique,
+		initiallyDeferred: check.InitiallyDeferred,
+		keyVals:           keyVals,
+		err:               err,
+	})
+}
+
+// reset clears all state; it is called when a transaction finishes or
+// restarts.
+func (dc *deferredConstraints) reset() {
+	*dc = deferredConstraints{}
+}
+
+// runChecks runs the pending checks and returns the error of the first check
+// that finds a violation. If onlyImmediate is true, only the checks of the
+// constraints that are no longer deferred are run; the others stay pending.
+//
+// The checks are re-evaluated against the current state of the tables, so a
+// violation that was fixed by a later statement in the transaction is not
+// reported. Checks for constraints or tables that were dropped are skipped.
+func (dc *deferredConstraints) runChecks(
+	ctx context.Context,
+	txn *kv.Txn,
+	ie sqlutil.InternalExecutor,
+	descsCol *descs.Collection,
+	onlyImmediate bool,
+) error {
+	remaining := dc.pending[:0]
+	for i := range dc.pending {
+		c := &dc.pending[i]
+		if onlyImmediate && dc.isDeferred(c.constraintName, c.initiallyDeferred) {
+			remaining = append(remaining, *c)
+			continue
+		}
+		violated, err := c.run(ctx, txn, ie, descsCol)
+		if err != nil {
+			return err
+		}
+		if violated {
+			return c.err
+		}
+	}
+	dc.pending = remaining
+	if len(dc.pending) == 0 {
+		dc.seen = nil
+	}
+	return nil
+}
+
+// run returns whether the constraint is currently violated for the key of
+// the check.
+func (c *deferredCheck) run(
+	ctx context.Context, txn *kv.Txn, ie sqlutil.InternalExecutor, descsCol *descs.Collection,
+) (violated bool, _ error) {
+	flags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{IncludeDropped: true},
+	}
+	desc, err := descsCol.GetImmutableTableByID(ctx, txn, c.tableID, flags)
+	if err != nil {
+		return false, err
+	}
+	if desc.Dropped() {
+		return false, nil
+	}
+	// Like the checks performed at the end of a statement, checks in
+	// transactions with per-statement read snapshots lock the rows they read,
+	// so that they cannot miss a concurrent write that violates the constraint.
+	lock := txn.IsoLevel().PerStatementReadSnapshot()
+	var query string
+	if c.isUnique {
+		query, err = c.uniqueCheckQuery(desc, lock)
+	} else {
+		query, err = c.fkCheckQuery(ctx, txn, descsCol, desc, flags, lock)
+	}
+	if err != nil || query == "" {
+		return false, err
+	}
+	qargs := make([]interface{}, len(c.keyVals))
+	for i := range c.keyVals {
+		qargs[i] = c.keyVals[i]
+	}
+	row, err := ie.QueryRowEx(
+		ctx, "deferred-constraint-check", txn,
+		sessiondata.NodeUserSessionDataOverride, query, qargs...,
+	)
+	if err != nil {
+		return false, err
+	}
+	return row != nil, nil
+}
+
+// uniqueCheckQuery returns a query that produces a row if there are
+// duplicates for the key of the check, or the empty string if the constraint
+// no longer exists. If lock is true, the rows read are locked FOR UPDATE.
+func (c *deferredCheck) uniqueCheckQuery(
+	desc catalog.TableDescriptor, lock bool,
+) (string, error) {
+	for _, uc := range desc.GetUniqueWithoutIndexConstraints() {
+		if uc.Name != c.constraintName {
+			continue
+		}
+		cols, err := desc.NamesForColumnIDs(uc.ColumnIDs)
+		if err != nil {
+			return "", err
+		}
+		var buf strings.Builder
+		fmt.Fprintf(&buf, "SELECT 1 FROM [%d AS t] WHERE ", desc.GetID())
+		writeKeyFilter(&buf, "t", cols, "=")
+		if uc.IsPartial() {
+			fmt.Fprintf(&buf, " AND (%s)", uc.Predicate)
+		}
+		buf.WriteString(" OFFSET 1 LIMIT 1")
+		if lock {
+			buf.WriteString(" FOR UPDATE")
+		}
+		return buf.String(), nil
+	}
+	return "", nil
+}
+
+// fkCheckQuery returns a query that produces a row if there is a row in the
+// origin table with the key of the check that has no match in the referenced
+// table, or the empty string if the constraint no longer exists. If lock is
+// true, the rows read from the referenced table are locked FOR UPDATE.
+func (c *deferredCheck) fkCheckQuery(
+	ctx context.Context,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	desc catalog.TableDescriptor,
+	flags tree.ObjectLookupFlags,
+	lock bool,
+) (string, error) {
+	var fk *descpb.ForeignKeyConstraint
+	_ = desc.ForeachOutboundFK(func(candidate *descpb.ForeignKeyConstraint) error {
+		if candidate.Name == c.constraintName {
+			fk = candidate
+		}
+		return nil
+	})
+	if fk == nil {
+		return "", nil
+	}
+	refDesc, err := descsCol.GetImmutableTableByID(ctx, txn, fk.ReferencedTableID, flags)
+	if err != nil {
+		return "", err
+	}
+	originCols, err := desc.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return "", err
+	}
+	refCols, err := refDesc.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "SELECT 1 FROM [%d AS o] WHERE ", desc.GetID())
+	// NULLs are compared with IS NOT DISTINCT FROM in the origin table so that
+	// MATCH FULL violations that mix NULL and non-NULL values are found.
+	writeKeyFilter(&buf, "o", originCols, "IS NOT DISTINCT FROM")
+	fmt.Fprintf(&buf, " AND NOT EXISTS (SELECT 1 FROM [%d AS r] WHERE ", refDesc.GetID())
+	writeKeyFilter(&buf, "r", refCols, "=")
+	if lock {
+		buf.WriteString(" FOR UPDATE")
+	}
+	buf.WriteString(") LIMIT 1")
+	return buf.String(), nil
+}
+
+// writeKeyFilter writes a conjunction that compares the given columns to the
+// placeholders $1, $2, etc.
+func writeKeyFilter(buf *strings.Builder, alias string, cols []string, cmp string) {
+	for i, col := range cols {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		fmt.Fprintf(buf, "%s.%s %s $%d", alias, tree.NameString(col), cmp, i+1)
+	}
+}
@@ -51,9 +51,16 @@ type deleteRun struct {
 
 	// partialIndexDelValsOffset is the offset of partial index delete
 	// indicators in the source values. It is equal to the number of fetched
-	// columns.
+	// columns plus the number of passthrough columns.
 	partialIndexDelValsOffset int
 
+	// numPassthrough is the number of columns in addition to the set of columns
+	// of the target table being returned, that must be passed through from the
+	// input node. These are the columns of the USING tables that are referenced
+	// by the RETURNING clause. They follow the fetched columns in the source
+	// values.
+	numPassthrough int
+
 	// rowIdxToRetIdx is the mapping from the columns returned by the deleter
 	// to the columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the column at that index is not part of the resultRowBuffer
@@ -170,6 +177,11 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		sourceVals = sourceVals[:d.run.partialIndexDelValsOffset]
 	}
 
+	// Separate the passthrough values from the values of the fetched columns.
+	fetchEnd := len(sourceVals) - d.run.numPassthrough
+	passthroughValues := sourceVals[fetchEnd:]
+	sourceVals = sourceVals[:fetchEnd]
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, pm, d.run.traceKV); err != nil {
 		return err
@@ -184,12 +196,24 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		// d.run.rows.NumCols() is guaranteed to only contain the requested
 		// public columns.
 		resultValues := make(tree.Datums, d.run.td.rows.NumCols())
+		largestRetIdx := -1
 		for i, retIdx := range d.run.rowIdxToRetIdx {
 			if retIdx >= 0 {
+				if retIdx >= largestRetIdx {
+					largestRetIdx = retIdx
+				}
 				resultValues[retIdx] = sourceVals[i]
 			}
 		}
 
+		// At this point we've extracted all the RETURNING values that are part
+		// of the target table. We must now extract the columns in the RETURNING
+		// clause that refer to other tables (from the USING clause of the delete).
+		for i := range passthroughValues {
+			largestRetIdx++
+			resultValues[largestRetIdx] = passthroughValues[i]
+		}
+
 		if _, err := d.run.td.rows.AddRow(params.ctx, resultValues); err != nil {
 			return err
 		}
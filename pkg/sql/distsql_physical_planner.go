@@ -1629,6 +1629,8 @@ type aggregatorPlanningInfo struct {
 	isScalar                 bool
 	groupCols                []int
 	groupColOrdering         colinfo.ColumnOrdering
+	groupingSets             []util.FastIntSet
+	groupingSetIDCol         int
 	inputMergeOrdering       execinfrapb.Ordering
 	reqOrdering              ReqOrdering
 	allowPartialDistribution bool
@@ -1673,6 +1675,8 @@ func (dsp *DistSQLPlanner) addAggregators(
 		isScalar:             n.isScalar,
 		groupCols:            n.groupCols,
 		groupColOrdering:     n.groupColOrdering,
+		groupingSets:         n.groupingSets,
+		groupingSetIDCol:     n.groupingSetIDCol,
 		inputMergeOrdering:   dsp.convertOrdering(planReqOrdering(n.plan), p.PlanToStreamColMap),
 		reqOrdering:          n.reqOrdering,
 	})
@@ -1715,6 +1719,23 @@ func (dsp *DistSQLPlanner) planAggregators(
 		orderedGroupCols[i] = uint32(p.PlanToStreamColMap[c.ColIdx])
 		orderedGroupColSet.Add(c.ColIdx)
 	}
+	var groupingSets []execinfrapb.AggregatorSpec_GroupingSet
+	var groupingSetIDCol uint32
+	hasEmptyGroupingSet := false
+	if len(info.groupingSets) > 0 {
+		groupingSets = make([]execinfrapb.AggregatorSpec_GroupingSet, len(info.groupingSets))
+		for i, set := range info.groupingSets {
+			cols := make([]uint32, 0, set.Len())
+			set.ForEach(func(idx int) {
+				cols = append(cols, uint32(p.PlanToStreamColMap[idx]))
+			})
+			groupingSets[i].Cols = cols
+			if len(cols) == 0 {
+				hasEmptyGroupingSet = true
+			}
+		}
+		groupingSetIDCol = uint32(p.PlanToStreamColMap[info.groupingSetIDCol])
+	}
 
 	// We can have a local stage of distinct processors if all aggregation
 	// functions are distinct.
@@ -1778,10 +1799,12 @@ func (dsp *DistSQLPlanner) planAggregators(
 	// just a final stage. We only use a local stage if:
 	//  - the previous stage is distributed on multiple nodes, and
 	//  - all aggregation functions support it, and
-	//  - no function is performing distinct aggregation.
+	//  - no function is performing distinct aggregation, and
+	//  - there is no empty grouping set (whose row must be produced even if
+	//    there are no input rows).
 	//  TODO(radu): we could relax this by splitting the aggregation into two
 	//  different paths and joining on the results.
-	multiStage := prevStageNode == 0
+	multiStage := prevStageNode == 0 && !hasEmptyGroupingSet
 	if multiStage {
 		for _, e := range info.aggregations {
 			if e.Distinct {
@@ -1810,6 +1833,8 @@ func (dsp *DistSQLPlanner) planAggregators(
 			GroupCols:        groupCols,
 			OrderedGroupCols: orderedGroupCols,
 			OutputOrdering:   finalOutputOrdering,
+			GroupingSets:     groupingSets,
+			GroupingSetIDCol: groupingSetIDCol,
 		}
 	} else {
 		// Some aggregations might need multiple aggregation as part of
@@ -2026,12 +2051,17 @@ func (dsp *DistSQLPlanner) planAggregators(
 			}
 		}
 
+		// The grouping sets are expanded by the local stage, so the final stage
+		// only needs to group on all group columns (including the grouping set
+		// ID column).
 		localAggsSpec := execinfrapb.AggregatorSpec{
 			Type:             aggType,
 			Aggregations:     localAggs,
 			GroupCols:        groupCols,
 			OrderedGroupCols: orderedGroupCols,
 			OutputOrdering:   execinfrapb.Ordering{Columns: ordCols},
+			GroupingSets:     groupingSets,
+			GroupingSetIDCol: groupingSetIDCol,
 		}
 
 		p.AddNoGroupingStage(
@@ -2127,10 +2157,14 @@ func (dsp *DistSQLPlanner) planAggregators(
 	// has been programmed to produce the same columns as the groupNode.
 	p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(info.aggregations))
 
-	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 {
-		// No GROUP BY, or we have a single stream. Use a single final aggregator.
-		// If the previous stage was all on a single node, put the final
-		// aggregator there. Otherwise, bring the results back on this node.
+	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 ||
+		finalAggsSpec.HasGroupingSets() {
+		// No GROUP BY, or we have a single stream, or the final aggregator has to
+		// expand the input rows into grouping sets (rows that belong to the
+		// same expanded group cannot be routed by hash before the expansion).
+		// Use a single final aggregator. If the previous stage was all on a
+		// single node, put the final aggregator there. Otherwise, bring the
+		// results back on this node.
 		node := dsp.gatewayNodeID
 		if prevStageNode != 0 {
 			node = prevStageNode
@@ -3890,7 +3924,7 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSV/Parquet Writer processors to the input plan.
+// We add a new stage of CSV/Parquet/JSON Writer processors to the input plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -3922,8 +3956,19 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 			ColNames:       n.colNames,
 			ColNullability: n.colNullability,
 		}
+	} else if n.jsonOpts != nil {
+		core.JSONWriter = &execinfrapb.JSONWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			Options:          *n.jsonOpts,
+			ChunkRows:        int64(n.chunkRows),
+			ChunkSize:        n.chunkSize,
+			CompressionCodec: n.fileCompression,
+			UserProto:        planCtx.planner.User().EncodeProto(),
+			ColNames:         n.colNames,
+		}
 	} else {
-		return nil, errors.AssertionFailedf("parquetOpts and csvOpts are both empty. " +
+		return nil, errors.AssertionFailedf("parquetOpts, csvOpts and jsonOpts are all empty. " +
 			"One must be not nil")
 	}
 
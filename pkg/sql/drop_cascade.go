@@ -36,6 +36,7 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []descpb.ID
 
 	droppedNames []string
 }
@@ -66,6 +67,14 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	// User-defined functions are not stored in system.namespace, so they are
+	// collected from the schema itself.
+	if err := schema.ForEachFunction(func(fn descpb.SchemaDescriptor_Function) error {
+		d.functionsToDelete = append(d.functionsToDelete, fn.OverloadIDs...)
+		return nil
+	}); err != nil {
+		return err
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
@@ -178,6 +187,25 @@ func (d *dropCascadeState) resolveCollectedObjects(
 	if err != nil {
 		return err
 	}
+	// The views which call the collected functions are dropped along with the
+	// functions, each with its own job, so they are neither dropped again
+	// below nor by the job of the parent.
+	viewsDroppedByFunctions, err := p.accumulateViewsUsingFunctions(ctx, d.functionsToDelete)
+	if err != nil {
+		return err
+	}
+	if len(viewsDroppedByFunctions) > 0 {
+		filtered := allObjectsToDelete[:0]
+		for _, desc := range allObjectsToDelete {
+			if _, found := viewsDroppedByFunctions[desc.ID]; !found {
+				filtered = append(filtered, desc)
+			}
+		}
+		allObjectsToDelete = filtered
+		for id, desc := range viewsDroppedByFunctions {
+			implicitDeleteMap[id] = desc
+		}
+	}
 	d.allTableObjectsToDelete = allObjectsToDelete
 	d.td = filterImplicitlyDeletedObjects(d.td, implicitDeleteMap)
 	d.toDeleteByID = make(map[descpb.ID]*toDelete)
@@ -188,6 +216,12 @@ func (d *dropCascadeState) resolveCollectedObjects(
 }
 
 func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *planner) error {
+	// Delete all of the collected functions first, which removes their
+	// references from the tables and types below.
+	if err := p.dropDependentFunctions(ctx, d.functionsToDelete); err != nil {
+		return err
+	}
+
 	// Delete all of the collected tables.
 	for _, toDel := range d.td {
 		desc := toDel.desc
@@ -0,0 +1,382 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n      *tree.DropFunction
+	toDrop []*funcdesc.Mutable
+}
+
+// DropFunction drops one or more user-defined functions.
+// Privileges: ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	for i := range n.Functions {
+		fnDesc, err := p.getMutableFunctionByName(ctx, &n.Functions[i], n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if fnDesc == nil {
+			continue
+		}
+		// If we've already seen this function, then skip it.
+		seen := false
+		for _, other := range node.toDrop {
+			seen = seen || other.GetID() == fnDesc.GetID()
+		}
+		if seen {
+			continue
+		}
+		hasOwnership, err := p.HasOwnership(ctx, fnDesc)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of function %s", funcSignature(fnDesc.GetName(), fnDesc.ArgTypes()))
+		}
+		if err := p.canRemoveTriggersUsingFunction(ctx, fnDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
+		if err := p.canRemoveViewsUsingFunction(ctx, fnDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
+		node.toDrop = append(node.toDrop, fnDesc)
+	}
+	if len(node.toDrop) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+
+	for _, fnDesc := range n.toDrop {
+		if err := params.p.dropFunctionImpl(
+			params.ctx, fnDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+		); err != nil {
+			return err
+		}
+		// Log a Drop Function event. This is an auditable log event and is
+		// recorded in the same transaction as the function descriptor update.
+		if err := params.p.logEvent(params.ctx,
+			fnDesc.GetID(),
+			&eventpb.DropFunction{
+				FunctionName: funcSignature(fnDesc.GetName(), fnDesc.ArgTypes()),
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
+
+// dropFunctionImpl marks the function as dropped, removing it from its parent
+// schema and removing the references to it from the objects it depends on,
+// including the triggers which invoke it. The views which call the function
+// are dropped as well.
+// The descriptor itself is deleted by the schema change job.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, fnDesc *funcdesc.Mutable, jobDesc string,
+) error {
+	if fnDesc.Dropped() {
+		return errors.Errorf("function %q is already being dropped", fnDesc.GetName())
+	}
+	if err := p.removeTriggersUsingFunction(ctx, fnDesc); err != nil {
+		return err
+	}
+	if err := p.dropViewsUsingFunction(ctx, fnDesc); err != nil {
+		return err
+	}
+	if err := p.removeFunctionBackReferences(ctx, fnDesc); err != nil {
+		return err
+	}
+
+	mutScDesc, err := p.Descriptors().GetMutableDescriptorByID(ctx, fnDesc.GetParentSchemaID(), p.txn)
+	if err != nil {
+		return err
+	}
+	sc, ok := mutScDesc.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("expected schema descriptor, found %T", mutScDesc)
+	}
+	// The schema is also being deleted, so we don't have to remove the
+	// function from it.
+	if !sc.Dropped() {
+		sc.RemoveFunction(fnDesc.GetName(), fnDesc.GetID())
+		if err := p.writeSchemaDescChange(
+			ctx, sc, fmt.Sprintf("removing function %q from schema %q", fnDesc.GetName(), sc.GetName()),
+		); err != nil {
+			return err
+		}
+	}
+
+	fnDesc.SetDropped()
+	return p.writeFunctionDescChange(ctx, fnDesc, jobDesc)
+}
+
+// removeFunctionBackReferences removes the references to the function from
+// all the relations and types it depends on.
+func (p *planner) removeFunctionBackReferences(ctx context.Context, fnDesc *funcdesc.Mutable) error {
+	for _, id := range fnDesc.DependsOn {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return errors.Wrapf(err, "error resolving dependency relation ID %d", id)
+		}
+		// The dependency is also being deleted, so we don't have to remove the
+		// references.
+		if tbl.Dropped() {
+			continue
+		}
+		tbl.DependedOnByFunctions = removeDescID(tbl.DependedOnByFunctions, fnDesc.GetID())
+		if err := p.writeSchemaChange(
+			ctx, tbl, descpb.InvalidMutationID,
+			fmt.Sprintf("removing references for function %s from table %s(%d)",
+				fnDesc.GetName(), tbl.GetName(), tbl.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	for _, id := range fnDesc.DependsOnTypes {
+		typ, err := p.Descriptors().GetMutableTypeVersionByID(ctx, p.txn, id)
+		if err != nil {
+			return err
+		}
+		if typ.Dropped() {
+			continue
+		}
+		typ.RemoveReferencingFunctionID(fnDesc.GetID())
+		if err := p.writeTypeSchemaChange(
+			ctx, typ, fmt.Sprintf("removing type back reference %d for function %d", id, fnDesc.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	fnDesc.DependsOn = nil
+	fnDesc.DependsOnTypes = nil
+	return nil
+}
+
+// canRemoveViewsUsingFunction returns an error if any views call the function
+// which is being dropped and CASCADE was not specified.
+func (p *planner) canRemoveViewsUsingFunction(
+	ctx context.Context, fnDesc *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	if behavior == tree.DropCascade || len(fnDesc.DependedOnBy) == 0 {
+		return nil
+	}
+	viewDesc, err := p.Descriptors().GetImmutableTableByID(ctx, p.txn, fnDesc.DependedOnBy[0],
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+	if err != nil {
+		return err
+	}
+	viewName, err := p.getQualifiedTableName(ctx, viewDesc)
+	if err != nil {
+		return err
+	}
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot drop function %s because view %q depends on it",
+			funcSignature(fnDesc.GetName(), fnDesc.ArgTypes()), viewName.FQString()),
+		"you can drop %s instead.", viewName.FQString())
+}
+
+// dropViewsUsingFunction drops the views which call the function which is
+// being dropped. Views which are already being dropped are skipped.
+func (p *planner) dropViewsUsingFunction(ctx context.Context, fnDesc *funcdesc.Mutable) error {
+	// Copy the IDs, since dropping a view removes its reference from the
+	// function.
+	for _, id := range append([]descpb.ID(nil), fnDesc.DependedOnBy...) {
+		viewDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if viewDesc.Dropped() {
+			continue
+		}
+		if _, err := p.dropViewImpl(
+			ctx, viewDesc, true /* queueJob */, "dropping dependent view", tree.DropCascade,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accumulateViewsUsingFunctions returns the views which are dropped along
+// with the user-defined functions with the given IDs, that is the views which
+// call them and the views which depend on those.
+func (p *planner) accumulateViewsUsingFunctions(
+	ctx context.Context, fnIDs []descpb.ID,
+) (map[descpb.ID]*tabledesc.Mutable, error) {
+	views := make(map[descpb.ID]*tabledesc.Mutable)
+	for _, fnID := range fnIDs {
+		fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, fnID,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range fnDesc.GetDependedOnBy() {
+			viewDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+			if err != nil {
+				return nil, err
+			}
+			views[id] = viewDesc
+			if err := p.accumulateCascadingViews(ctx, views, viewDesc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return views, nil
+}
+
+// addFunctionBackReference adds a reference from the relation to the
+// user-defined function with the given ID, which the relation calls.
+func (p *planner) addFunctionBackReference(
+	ctx context.Context, fnID descpb.ID, tbl catalog.TableDescriptor,
+) error {
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, fnID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+	if err != nil {
+		return err
+	}
+	fnDesc.AddDependedOnBy(tbl.GetID())
+	return p.writeFunctionDescChange(ctx, fnDesc,
+		fmt.Sprintf("updating function back reference %d for table %d", fnID, tbl.GetID()))
+}
+
+// removeFunctionBackReference removes the reference from the relation to the
+// user-defined function with the given ID, unless the function is being
+// dropped.
+func (p *planner) removeFunctionBackReference(
+	ctx context.Context, fnID descpb.ID, tbl catalog.TableDescriptor,
+) error {
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, fnID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+			Required:       true,
+			IncludeDropped: true,
+		}})
+	if err != nil {
+		return err
+	}
+	if fnDesc.Dropped() {
+		return nil
+	}
+	fnDesc.RemoveDependedOnBy(tbl.GetID())
+	return p.writeFunctionDescChange(ctx, fnDesc,
+		fmt.Sprintf("removing function back reference %d for table %d", fnID, tbl.GetID()))
+}
+
+// canRemoveDependentFunctions returns an error if any user-defined functions
+// depend on the object which is being dropped and CASCADE was not specified.
+func (p *planner) canRemoveDependentFunctions(
+	ctx context.Context,
+	typeName string,
+	objName string,
+	fnIDs []descpb.ID,
+	behavior tree.DropBehavior,
+) error {
+	if behavior == tree.DropCascade {
+		return nil
+	}
+	return p.dependentFunctionError(ctx, typeName, objName, fnIDs, "drop")
+}
+
+// dependentFunctionError returns an error if any user-defined functions
+// depend on the object, which prevents the given operation on it.
+func (p *planner) dependentFunctionError(
+	ctx context.Context, typeName, objName string, fnIDs []descpb.ID, op string,
+) error {
+	if len(fnIDs) == 0 {
+		return nil
+	}
+	fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, fnIDs[0],
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+	if err != nil {
+		return err
+	}
+	fnName := funcSignature(fnDesc.GetName(), fnDesc.ArgTypes())
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot %s %s %q because function %s depends on it",
+			op, typeName, objName, fnName),
+		"you can drop %s instead.", fnName)
+}
+
+// dropDependentFunctions drops the user-defined functions with the given IDs
+// as part of a DROP ... CASCADE. Functions which are already being dropped
+// are skipped.
+func (p *planner) dropDependentFunctions(ctx context.Context, fnIDs []descpb.ID) error {
+	// Copy the IDs, since dropping a function modifies the references of the
+	// objects it depends on.
+	for _, id := range append([]descpb.ID(nil), fnIDs...) {
+		fnDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+				Required:       true,
+				IncludeDropped: true,
+			}})
+		if err != nil {
+			return err
+		}
+		if fnDesc.Dropped() {
+			continue
+		}
+		if err := p.dropFunctionImpl(ctx, fnDesc, "dropping dependent function"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDescID returns ids without id.
+func removeDescID(ids []descpb.ID, id descpb.ID) []descpb.ID {
+	ret := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			ret = append(ret, other)
+		}
+	}
+	return ret
+}
@@ -57,6 +57,11 @@ func (p *planner) DropSequence(ctx context.Context, n *tree.DropSequence) (planN
 		if depErr := p.sequenceDependencyError(ctx, droppedDesc, n.DropBehavior); depErr != nil {
 			return nil, depErr
 		}
+		if err := p.canRemoveDependentFunctions(
+			ctx, "sequence", droppedDesc.Name, droppedDesc.DependedOnByFunctions, n.DropBehavior,
+		); err != nil {
+			return nil, err
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -121,6 +126,9 @@ func (p *planner) dropSequenceImpl(
 			return err
 		}
 	}
+	if err := p.dropDependentFunctions(ctx, seqDesc.DependedOnByFunctions); err != nil {
+		return err
+	}
 	return p.initiateDropTable(ctx, seqDesc, queueJob, jobDesc)
 }
 
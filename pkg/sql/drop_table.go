@@ -97,6 +97,11 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if err := p.canRemoveAllTableOwnedSequences(ctx, droppedDesc, n.DropBehavior); err != nil {
 			return nil, err
 		}
+		if err := p.canRemoveDependentFunctions(
+			ctx, "relation", droppedDesc.Name, droppedDesc.DependedOnByFunctions, n.DropBehavior,
+		); err != nil {
+			return nil, err
+		}
 
 	}
 
@@ -234,6 +239,12 @@ func (p *planner) dropTableImpl(
 ) ([]string, error) {
 	var droppedViews []string
 
+	// Drop the user-defined functions which depend on this table. Whether
+	// they may be dropped was checked when planning the statement.
+	if err := p.dropDependentFunctions(ctx, tableDesc.DependedOnByFunctions); err != nil {
+		return droppedViews, err
+	}
+
 	// Remove foreign key back references from tables that this table has foreign
 	// keys to.
 	// Copy out the set of outbound fks as it may be overwritten in the loop.
@@ -306,6 +317,13 @@ func (p *planner) dropTableImpl(
 		return droppedViews, err
 	}
 
+	// Remove the schedule of the row-level TTL job.
+	if ttl := tableDesc.GetRowLevelTTL(); ttl != nil {
+		if err := p.deleteRowLevelTTLSchedule(ctx, ttl); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	// Remove any references to types.
 	//
 	// Note: In some historical context this attempted to defer these removals to
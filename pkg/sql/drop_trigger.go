@@ -0,0 +1,183 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+}
+
+// DropTrigger drops a trigger from a table.
+// Privileges: CREATE on table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &n.Table, !n.IfExists, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	if findTrigger(tableDesc, string(n.Name)) == -1 {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %q for table %q does not exist", n.Name, tableDesc.GetName())
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+// findTrigger returns the index of the trigger with the given name in the
+// triggers of the table, or -1 if there is no such trigger.
+func findTrigger(tableDesc *tabledesc.Mutable, name string) int {
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	i := findTrigger(n.tableDesc, string(n.n.Name))
+	if i == -1 {
+		return errors.AssertionFailedf("trigger %q not found", n.n.Name)
+	}
+	n.tableDesc.Triggers = append(n.tableDesc.Triggers[:i], n.tableDesc.Triggers[i+1:]...)
+
+	if err := validateDescriptor(params.ctx, params.p, n.tableDesc); err != nil {
+		return err
+	}
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Log a Drop Trigger event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.GetID(),
+		&eventpb.DropTrigger{
+			TableName:   tree.AsStringWithFQNames(&n.n.Table, params.Ann()),
+			TriggerName: string(n.n.Name),
+		})
+}
+
+func (*dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTriggerNode) Close(context.Context)        {}
+
+// canRemoveTriggersUsingFunction returns an error if any triggers invoke the
+// function which is being dropped and CASCADE was not specified.
+func (p *planner) canRemoveTriggersUsingFunction(
+	ctx context.Context, fnDesc *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	if behavior == tree.DropCascade {
+		return nil
+	}
+	// The function of a trigger takes the row type of the table as argument,
+	// so the table is one of the dependencies of the function.
+	for _, id := range fnDesc.DependsOn {
+		tbl, err := p.Descriptors().GetImmutableTableByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+				Required:       true,
+				IncludeDropped: true,
+			}})
+		if err != nil {
+			return err
+		}
+		if tbl.Dropped() {
+			continue
+		}
+		for _, t := range tbl.GetTriggers() {
+			if t.FunctionID == fnDesc.GetID() {
+				fnName := funcSignature(fnDesc.GetName(), fnDesc.ArgTypes())
+				return errors.WithHintf(
+					sqlerrors.NewDependentObjectErrorf(
+						"cannot drop function %s because trigger %q on table %q depends on it",
+						fnName, t.Name, tbl.GetName()),
+					"you can drop trigger %q instead.", t.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// removeTriggersUsingFunction removes the triggers which invoke the function
+// which is being dropped.
+func (p *planner) removeTriggersUsingFunction(ctx context.Context, fnDesc *funcdesc.Mutable) error {
+	for _, id := range fnDesc.DependsOn {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if tbl.Dropped() {
+			continue
+		}
+		triggers := tbl.Triggers[:0]
+		for _, t := range tbl.Triggers {
+			if t.FunctionID != fnDesc.GetID() {
+				triggers = append(triggers, t)
+			}
+		}
+		if len(triggers) == len(tbl.Triggers) {
+			continue
+		}
+		tbl.Triggers = triggers
+		if err := p.writeSchemaChange(
+			ctx, tbl, descpb.InvalidMutationID,
+			fmt.Sprintf("removing triggers using function %s from table %s(%d)",
+				fnDesc.GetName(), tbl.GetName(), tbl.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
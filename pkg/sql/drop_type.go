@@ -37,10 +37,33 @@ type dropTypeNode struct {
 var _ planNode = &dropTypeNode{n: nil}
 
 func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	return p.dropTypes(ctx, n, false /* dropDomain */)
+}
+
+// DropDomain drops domain types.
+// Privileges: ownership of the domain.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	return p.dropTypes(ctx, &tree.DropType{
+		Names:        n.Names,
+		IfExists:     n.IfExists,
+		DropBehavior: n.DropBehavior,
+	}, true /* dropDomain */)
+}
+
+// dropTypes plans the drop of the given types. If dropDomain is set, the
+// statement is a DROP DOMAIN, and all of the types must be domains. Otherwise,
+// none of them may be.
+func (p *planner) dropTypes(
+	ctx context.Context, n *tree.DropType, dropDomain bool,
+) (planNode, error) {
+	stmtName := "DROP TYPE"
+	if dropDomain {
+		stmtName = "DROP DOMAIN"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TYPE",
+		stmtName,
 	); err != nil {
 		return nil, err
 	}
@@ -65,6 +88,9 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		if dropDomain && typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
@@ -84,6 +110,13 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 				"try ALTER DATABASE DROP REGION %s", name)
 		case descpb.TypeDescriptor_ENUM:
 			sqltelemetry.IncrementEnumCounter(sqltelemetry.EnumDrop)
+		case descpb.TypeDescriptor_DOMAIN:
+			if !dropDomain {
+				return nil, errors.WithHint(
+					pgerror.Newf(pgcode.WrongObjectType, "%q is a domain", name),
+					"Use DROP DOMAIN to remove a domain.",
+				)
+			}
 		case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 			return nil, pgerror.Newf(
 				pgcode.DependentObjectsStillExist,
@@ -131,7 +164,7 @@ func (p *planner) canDropTypeDesc(
 			dependentNames,
 		)
 	}
-	return nil
+	return p.canRemoveDependentFunctions(ctx, "type", desc.Name, desc.ReferencingFunctionIDs, behavior)
 }
 
 func (n *dropTypeNode) startExec(params runParams) error {
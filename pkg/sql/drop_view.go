@@ -76,6 +76,11 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 				return nil, err
 			}
 		}
+		if err := p.canRemoveDependentFunctions(
+			ctx, "relation", droppedDesc.Name, droppedDesc.DependedOnByFunctions, n.DropBehavior,
+		); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(td) == 0 {
@@ -190,6 +195,11 @@ func (p *planner) dropViewImpl(
 ) ([]string, error) {
 	var cascadeDroppedViews []string
 
+	// Drop the user-defined functions which depend on this view.
+	if err := p.dropDependentFunctions(ctx, viewDesc.DependedOnByFunctions); err != nil {
+		return cascadeDroppedViews, err
+	}
+
 	// Remove back-references from the tables/views this view depends on.
 	dependedOn := append([]descpb.ID(nil), viewDesc.DependsOn...)
 	for _, depID := range dependedOn {
@@ -222,6 +232,14 @@ func (p *planner) dropViewImpl(
 		return cascadeDroppedViews, err
 	}
 
+	// Remove back-references from the user-defined functions this view calls.
+	for _, fnID := range viewDesc.DependsOnFunctions {
+		if err := p.removeFunctionBackReference(ctx, fnID, viewDesc); err != nil {
+			return cascadeDroppedViews, err
+		}
+	}
+	viewDesc.DependsOnFunctions = nil
+
 	if behavior == tree.DropCascade {
 		dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), viewDesc.DependedOnBy...)
 		for _, ref := range dependedOnBy {
@@ -26,6 +26,12 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the wrapped node is the check query of a DEFERRABLE
+	// constraint. If the constraint is deferred in the current transaction,
+	// all rows are recorded in the transaction's deferred constraints instead
+	// of causing an error.
+	deferrable *exec.DeferrableCheck
+
 	nexted bool
 }
 
@@ -39,6 +45,17 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
+	if dc := n.deferredConstraints(params); dc != nil {
+		for {
+			ok, err := n.plan.Next(params)
+			if err != nil || !ok {
+				return false, err
+			}
+			row := n.plan.Values()
+			dc.add(n.deferrable, row, n.mkErr(row))
+		}
+	}
+
 	ok, err := n.plan.Next(params)
 	if err != nil {
 		return false, err
@@ -49,6 +66,21 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	return false, nil
 }
 
+// deferredConstraints returns the deferred constraints of the transaction if
+// the check should be postponed until commit time, or nil otherwise. Checks
+// are never deferred in implicit transactions, since those commit at the end
+// of the statement anyway.
+func (n *errorIfRowsNode) deferredConstraints(params runParams) *deferredConstraints {
+	dc := params.extendedEvalCtx.DeferredConstraints
+	if n.deferrable == nil || dc == nil || params.EvalContext().TxnImplicit {
+		return nil
+	}
+	if !dc.isDeferred(n.deferrable.ConstraintName, n.deferrable.InitiallyDeferred) {
+		return nil
+	}
+	return dc
+}
+
 func (n *errorIfRowsNode) Values() tree.Datums {
 	return nil
 }
@@ -63,6 +63,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob/gcjobnotifier"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -242,6 +243,25 @@ var traceSessionEventLogEnabled = settings.RegisterBoolSetting(
 	false,
 ).WithPublic()
 
+// allowReadCommittedIsolation controls whether transactions may run with the
+// READ COMMITTED isolation level. Like in Postgres, an isolation level that
+// isn't supported is upgraded to a stronger one.
+var allowReadCommittedIsolation = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.txn.read_committed_isolation.enabled",
+	"if true, transactions may run with the READ COMMITTED isolation level; "+
+		"otherwise, READ COMMITTED transactions are upgraded to SERIALIZABLE",
+	false,
+).WithPublic()
+
+// readCommittedIsolationEnabled returns whether READ COMMITTED transactions
+// run with the READ COMMITTED isolation level, instead of being upgraded to
+// SERIALIZABLE.
+func readCommittedIsolationEnabled(ctx context.Context, st *cluster.Settings) bool {
+	return allowReadCommittedIsolation.Get(&st.SV) &&
+		st.Version.IsActive(ctx, clusterversion.ReadCommittedIsolation)
+}
+
 // ReorderJoinsLimitClusterSettingName is the name of the cluster setting for
 // the maximum number of joins to reorder.
 const ReorderJoinsLimitClusterSettingName = "sql.defaults.reorder_joins_limit"
@@ -1191,6 +1211,10 @@ type ExecutorConfig struct {
 
 	RangeFeedFactory *rangefeed.Factory
 
+	// NotificationRegistry delivers the notifications sent by NOTIFY to the
+	// sessions that executed LISTEN.
+	NotificationRegistry *notify.Registry
+
 	// VersionUpgradeHook is called after validating a `SET CLUSTER SETTING
 	// version` but before executing it. It can carry out arbitrary migrations
 	// that allow us to eventually remove legacy code.
@@ -1658,6 +1682,9 @@ func checkResultType(typ *types.T) error {
 	case types.TupleFamily:
 	case types.EnumFamily:
 	case types.VoidFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
+	case types.RangeFamily:
 	case types.ArrayFamily:
 		if typ.ArrayContents().Family() == types.ArrayFamily {
 			// Technically we could probably return arrays of arrays to a
@@ -1830,6 +1857,15 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// SessionRevivalToken may contain a token generated from a different session
+	// that can be used to authenticate this session. If it is set, all other
+	// authentication is skipped. Once the token is used to authenticate, this
+	// value should be zeroed out.
+	SessionRevivalToken []byte
+	// Replication is set if the connection was started in logical replication
+	// mode, in which the commands of the streaming replication protocol are
+	// accepted in addition to SQL statements.
+	Replication bool
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -2768,6 +2804,10 @@ func (m *sessionDataMutator) SetDefaultTransactionPriority(val tree.UserPriority
 	m.data.DefaultTxnPriority = int64(val)
 }
 
+func (m *sessionDataMutator) SetDefaultTransactionIsolationLevel(val tree.IsolationLevel) {
+	m.data.DefaultTxnIsolationLevel = int64(val)
+}
+
 func (m *sessionDataMutator) SetDefaultTransactionReadOnly(val bool) {
 	m.data.DefaultTxnReadOnly = val
 }
@@ -77,6 +77,11 @@ func (m *ParquetWriterSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *JSONWriterSpec) User() security.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ReadImportDataSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
@@ -114,6 +114,13 @@ func (a *AggregatorSpec) summary() (string, []string) {
 	if len(a.OrderedGroupCols) > 0 {
 		details = append(details, fmt.Sprintf("Ordered: %s", colListStr(a.OrderedGroupCols)))
 	}
+	if len(a.GroupingSets) > 0 {
+		sets := make([]string, len(a.GroupingSets))
+		for i := range a.GroupingSets {
+			sets[i] = fmt.Sprintf("(%s)", colListStr(a.GroupingSets[i].Cols))
+		}
+		details = append(details, fmt.Sprintf("Grouping Sets: %s", strings.Join(sets, ",")))
+	}
 	for _, agg := range a.Aggregations {
 		var buf bytes.Buffer
 		buf.WriteString(agg.Func.String())
@@ -502,6 +509,19 @@ func (s *CSVWriterSpec) summary() (string, []string) {
 	return "CSVWriter", []string{s.Destination}
 }
 
+// summary implements the diagramCellType interface.
+func (m *VerifyBackupDataSpec) summary() (string, []string) {
+	return "VerifyBackupData", []string{
+		fmt.Sprintf("Files: %d", len(m.Files)),
+		fmt.Sprintf("Entries: %d", len(m.Entries)),
+	}
+}
+
+// summary implements the diagramCellType interface.
+func (s *JSONWriterSpec) summary() (string, []string) {
+	return "JSONWriter", []string{s.Destination}
+}
+
 // summary implements the diagramCellType interface.
 func (s *BulkRowWriterSpec) summary() (string, []string) {
 	return "BulkRowWriterSpec", []string{}
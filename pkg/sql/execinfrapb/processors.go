@@ -160,6 +160,12 @@ func (spec *AggregatorSpec) IsScalar() bool {
 	}
 }
 
+// HasGroupingSets returns true if the aggregator computes multiple grouping
+// sets.
+func (spec *AggregatorSpec) HasGroupingSets() bool {
+	return len(spec.GroupingSets) > 0
+}
+
 // IsRowCount returns true if the aggregator spec is scalar and has a single
 // COUNT_ROWS aggregation with no FILTER or DISTINCT.
 func (spec *AggregatorSpec) IsRowCount() bool {
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -43,6 +44,7 @@ type exportNode struct {
 	fileNamePattern string
 	csvOpts         *roachpb.CSVOptions
 	parquetOpts     *roachpb.ParquetOptions
+	jsonOpts        *roachpb.JSONOptions
 	chunkRows       int
 	chunkSize       int64
 	fileCompression execinfrapb.FileCompression
@@ -90,6 +92,7 @@ const exportFilePatternPart = "%part%"
 const exportCompressionCodec = "gzip"
 const csvSuffix = "csv"
 const parquetSuffix = "parquet"
+const jsonSuffix = "json"
 
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
@@ -129,7 +132,7 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != csvSuffix && fileFormat != parquetSuffix {
+	if fileFormat != csvSuffix && fileFormat != parquetSuffix && fileFormat != jsonSuffix {
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -166,6 +169,7 @@ func (ef *execFactory) ConstructExport(
 		exportFilePattern string
 		csvOpts           *roachpb.CSVOptions
 		parquetOpts       *roachpb.ParquetOptions
+		jsonOpts          *roachpb.JSONOptions
 	)
 
 	cols := planColumns(input.(planNode))
@@ -193,6 +197,16 @@ func (ef *execFactory) ConstructExport(
 	case parquetSuffix:
 		parquetOpts = &roachpb.ParquetOptions{}
 		exportFilePattern = exportFilePatternPart + "." + parquetSuffix
+
+	case jsonSuffix:
+		if !ef.planner.ExecCfg().Settings.Version.IsActive(
+			ef.planner.EvalContext().Context, clusterversion.ImportExportJSON,
+		) {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"EXPORT to JSON files is only available once the cluster is fully upgraded")
+		}
+		jsonOpts = &roachpb.JSONOptions{}
+		exportFilePattern = exportFilePatternPart + "." + jsonSuffix
 	}
 
 	chunkRows := exportChunkRowsDefault
@@ -237,6 +251,7 @@ func (ef *execFactory) ConstructExport(
 		fileNamePattern: namePattern,
 		csvOpts:         csvOpts,
 		parquetOpts:     parquetOpts,
+		jsonOpts:        jsonOpts,
 		chunkRows:       chunkRows,
 		chunkSize:       chunkSize,
 		fileCompression: codec,
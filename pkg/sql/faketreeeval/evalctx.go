@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
@@ -238,6 +239,21 @@ func (*DummyEvalPlanner) DecodeGist(gist string) ([]string, error) {
 	return nil, errors.WithStack(errEvalPlanner)
 }
 
+// CreateSessionRevivalToken is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) CreateSessionRevivalToken() (*tree.DBytes, error) {
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
+// SendNotification is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) SendNotification(ctx context.Context, channel, payload string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// ListeningChannels is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) ListeningChannels() []string {
+	return nil
+}
+
 // ExecutorConfig is part of the EvalPlanner interface.
 func (*DummyEvalPlanner) ExecutorConfig() interface{} {
 	return nil
@@ -475,3 +491,14 @@ var _ tree.PreparedStatementState = (*DummyPreparedStatementState)(nil)
 func (ps *DummyPreparedStatementState) HasPrepared() bool {
 	return false
 }
+
+// HasActivePortals is part of the tree.PreparedStatementState interface.
+func (ps *DummyPreparedStatementState) HasActivePortals() bool {
+	return false
+}
+
+// MigratablePreparedStatements is part of the tree.PreparedStatementState
+// interface.
+func (ps *DummyPreparedStatementState) MigratablePreparedStatements() []sessiondatapb.MigratableSession_PreparedStatement {
+	return nil
+}
@@ -0,0 +1,376 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// writeFunctionDescChange writes the function descriptor, queuing a schema
+// change job which takes care of deleting the descriptor once it is dropped.
+func (p *planner) writeFunctionDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	record, recordExists := p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID]
+	if recordExists {
+		// Update it.
+		record.AppendDescription(jobDesc)
+		log.Infof(ctx, "job %d: updated job's specification for change on function %d", record.JobID, desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			JobID:         p.extendedEvalCtx.ExecCfg.JobRegistry.MakeJobID(),
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress:      jobspb.SchemaChangeProgress{},
+			NonCancelable: true,
+		}
+		p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID] = &jobRecord
+		log.Infof(ctx, "queued new schema change job %d for function %d", jobRecord.JobID, desc.ID)
+	}
+
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// resolveFunctionSchema returns the schema in which the user-defined function
+// with the given name resides, along with the IDs of its overloads. If the
+// name is not qualified, the schemas on the search path are searched in order
+// and the first one which contains a function with that name is used. A nil
+// schema is returned if no such function exists.
+func (p *planner) resolveFunctionSchema(
+	ctx context.Context, name *tree.UnresolvedObjectName, path sessiondata.SearchPath,
+) (catalog.SchemaDescriptor, []descpb.ID, error) {
+	dbName := p.CurrentDatabase()
+	if name.HasExplicitCatalog() {
+		dbName = name.Catalog()
+	}
+	if dbName == "" {
+		return nil, nil, nil
+	}
+	db, err := p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, dbName,
+		tree.DatabaseLookupFlags{
+			Required:    name.HasExplicitCatalog(),
+			AvoidLeased: p.avoidLeasedDescriptors,
+		})
+	if err != nil || db == nil {
+		return nil, nil, err
+	}
+	lookup := func(scName string) (catalog.SchemaDescriptor, []descpb.ID, error) {
+		sc, err := p.Descriptors().GetSchemaByName(ctx, p.txn, db, scName,
+			tree.SchemaLookupFlags{AvoidLeased: p.avoidLeasedDescriptors})
+		if err != nil || sc == nil {
+			return nil, nil, err
+		}
+		fn, ok := sc.GetFunction(name.Object())
+		if !ok {
+			return nil, nil, nil
+		}
+		return sc, fn.OverloadIDs, nil
+	}
+	if name.HasExplicitSchema() {
+		return lookup(name.Schema())
+	}
+	iter := path.Iter()
+	for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+		sc, ids, err := lookup(scName)
+		if err != nil || sc != nil {
+			return sc, ids, err
+		}
+	}
+	return nil, nil, nil
+}
+
+// ResolveFunction returns the definition of the user-defined function with
+// the given name, which contains all of its overloads. It returns nil if no
+// such function exists.
+func (p *planner) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	if name.NumParts > 3 || name.Star {
+		return nil, nil
+	}
+	fnName, err := name.ToUnresolvedObjectName(tree.NoAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	sc, ids, err := p.resolveFunctionSchema(ctx, fnName, path)
+	if err != nil || sc == nil {
+		return nil, err
+	}
+	overloads := make([]tree.Overload, len(ids))
+	var qualifiedName *tree.UnresolvedObjectName
+	props := tree.FunctionProperties{
+		// NULL arguments are handled when the function is inlined, according to
+		// the null input behavior of the function.
+		NullableArgs: true,
+	}
+	for i, id := range ids {
+		fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+				Required:    true,
+				AvoidLeased: p.avoidLeasedDescriptors,
+			}})
+		if err != nil {
+			return nil, err
+		}
+		if err := p.makeUDFOverload(ctx, fnDesc, &overloads[i]); err != nil {
+			return nil, err
+		}
+		if fnDesc.GetReturnSet() {
+			props.Class = tree.GeneratorClass
+		}
+		if qualifiedName == nil {
+			if qualifiedName, err = p.getQualifiedFunctionName(ctx, fnDesc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tree.NewUDFDefinition(qualifiedName, &props, overloads), nil
+}
+
+// ResolveFunctionByID returns the definition of the user-defined function
+// overload with the given ID. The definition contains only that overload.
+func (p *planner) ResolveFunctionByID(
+	ctx context.Context, id cat.StableID,
+) (*tree.FunctionDefinition, error) {
+	fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, descpb.ID(id),
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+			Required:    true,
+			AvoidLeased: p.avoidLeasedDescriptors,
+		}})
+	if err != nil {
+		return nil, err
+	}
+	overloads := make([]tree.Overload, 1)
+	if err := p.makeUDFOverload(ctx, fnDesc, &overloads[0]); err != nil {
+		return nil, err
+	}
+	props := tree.FunctionProperties{NullableArgs: true}
+	if fnDesc.GetReturnSet() {
+		props.Class = tree.GeneratorClass
+	}
+	qualifiedName, err := p.getQualifiedFunctionName(ctx, fnDesc)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewUDFDefinition(qualifiedName, &props, overloads), nil
+}
+
+// getQualifiedFunctionName returns the fully qualified name of the
+// user-defined function represented by the provided descriptor.
+func (p *planner) getQualifiedFunctionName(
+	ctx context.Context, fnDesc catalog.FunctionDescriptor,
+) (*tree.UnresolvedObjectName, error) {
+	scDesc, err := p.Descriptors().GetImmutableSchemaByID(ctx, p.txn, fnDesc.GetParentSchemaID(),
+		tree.SchemaLookupFlags{
+			Required:    true,
+			AvoidLeased: p.avoidLeasedDescriptors,
+		})
+	if err != nil {
+		return nil, err
+	}
+	_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByID(ctx, p.txn, fnDesc.GetParentID(),
+		tree.DatabaseLookupFlags{
+			Required:    true,
+			AvoidLeased: p.avoidLeasedDescriptors,
+		})
+	if err != nil {
+		return nil, err
+	}
+	parts := [3]string{fnDesc.GetName(), scDesc.GetName(), dbDesc.GetName()}
+	return tree.NewUnresolvedObjectName(3 /* numParts */, parts, tree.NoAnnotation)
+}
+
+// makeUDFOverload populates the overload from the function descriptor,
+// hydrating any user-defined types in its signature.
+func (p *planner) makeUDFOverload(
+	ctx context.Context, fnDesc catalog.FunctionDescriptor, ov *tree.Overload,
+) error {
+	// Make a copy of the descriptor, since hydrating the types modifies them.
+	fd := protoutil.Clone(fnDesc.FuncDesc()).(*descpb.FunctionDescriptor)
+	argTypes := make(tree.ArgTypes, len(fd.Args))
+	argNames := make([]string, len(fd.Args))
+	for i := range fd.Args {
+		if err := typedesc.EnsureTypeIsHydrated(ctx, fd.Args[i].Type, p); err != nil {
+			return err
+		}
+		argTypes[i].Name = fd.Args[i].Name
+		argTypes[i].Typ = fd.Args[i].Type
+		argNames[i] = fd.Args[i].Name
+	}
+	if err := typedesc.EnsureTypeIsHydrated(ctx, fd.ReturnType, p); err != nil {
+		return err
+	}
+	*ov = tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(fd.ReturnType),
+		Volatility: udfVolatility(fd),
+		UDF: &tree.UDFOverload{
+			ID:        uint32(fd.ID),
+			ArgNames:  argNames,
+			Body:      fd.FunctionBody,
+			ReturnSet: fd.ReturnSet,
+			Strict:    fd.NullInputBehavior == descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT,
+		},
+	}
+	return nil
+}
+
+// udfVolatility maps the volatility of a function descriptor to the
+// volatility of its overload.
+func udfVolatility(fd *descpb.FunctionDescriptor) tree.Volatility {
+	switch fd.Volatility {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		if fd.LeakProof {
+			return tree.VolatilityLeakProof
+		}
+		return tree.VolatilityImmutable
+	case descpb.FunctionDescriptor_STABLE:
+		return tree.VolatilityStable
+	default:
+		return tree.VolatilityVolatile
+	}
+}
+
+// CheckFunctionPrivilege verifies that the current user has the given
+// privilege on the user-defined function with the given ID.
+func (p *planner) CheckFunctionPrivilege(
+	ctx context.Context, id cat.StableID, priv privilege.Kind,
+) error {
+	fnDesc, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, descpb.ID(id),
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+			Required:    true,
+			AvoidLeased: p.avoidLeasedDescriptors,
+		}})
+	if err != nil {
+		return err
+	}
+	return p.CheckPrivilege(ctx, fnDesc, priv)
+}
+
+// getMutableFunctionByName returns the overload of the user-defined function
+// identified by fn which matches its argument types, if they were specified.
+// An error is returned if the argument types were omitted and the function
+// has several overloads. If no overload matches, an error is returned unless
+// missingOk is true, in which case nil is returned.
+func (p *planner) getMutableFunctionByName(
+	ctx context.Context, fn *tree.FuncObj, missingOk bool,
+) (*funcdesc.Mutable, error) {
+	var argTypes []*types.T
+	if fn.Args != nil {
+		argTypes = make([]*types.T, len(fn.Args))
+		for i, arg := range fn.Args {
+			typ, err := tree.ResolveType(ctx, arg, p.semaCtx.GetTypeResolver())
+			if err != nil {
+				return nil, err
+			}
+			argTypes[i] = typ
+		}
+	}
+	notFound := func() (*funcdesc.Mutable, error) {
+		if missingOk {
+			return nil, nil
+		}
+		if fn.Args == nil {
+			return nil, sqlerrors.NewUndefinedFunctionError(tree.AsString(fn.FuncName))
+		}
+		return nil, sqlerrors.NewUndefinedFunctionError(funcSignature(fn.FuncName.Object(), argTypes))
+	}
+
+	sc, ids, err := p.resolveFunctionSchema(ctx, fn.FuncName, p.CurrentSearchPath())
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil {
+		return notFound()
+	}
+	if fn.Args == nil && len(ids) > 1 {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.AmbiguousFunction,
+				"function name %q is not unique", tree.ErrString(fn.FuncName)),
+			"Specify the argument list to select the function unambiguously.",
+		)
+	}
+	for _, id := range ids {
+		mut, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, id,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return nil, err
+		}
+		if fn.Args == nil || funcArgTypesMatch(mut.ArgTypes(), argTypes) {
+			return mut, nil
+		}
+	}
+	return notFound()
+}
+
+// funcArgTypesMatch returns true if the function signatures with the given
+// argument types are the same. Like in Postgres, type modifiers are not part
+// of the signature of a function.
+func funcArgTypesMatch(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Oid() != b[i].Oid() {
+			return false
+		}
+	}
+	return true
+}
+
+// funcSignature formats the signature of a function for use in error
+// messages, e.g. f(INT8, STRING).
+func funcSignature(name string, argTypes []*types.T) string {
+	var sb strings.Builder
+	sb.WriteString(tree.NameString(name))
+	sb.WriteByte('(')
+	for i, typ := range argTypes {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(typ.SQLString())
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -324,6 +325,22 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 						TypeName:                       d.Name, // FIXME
 					}})
 			}
+		case *funcdesc.Mutable:
+			if err := p.writeFunctionDescChange(
+				ctx, d, fmt.Sprintf("updating privileges for function %d", d.ID),
+			); err != nil {
+				return err
+			}
+			for _, grantee := range n.grantees {
+				privs := eventDetails // copy the granted/revoked privilege list.
+				privs.Grantee = grantee.Normalized()
+				events = append(events, eventLogEntry{
+					targetID: int32(d.ID),
+					event: &eventpb.ChangeFunctionPrivilege{
+						CommonSQLPrivilegeEventDetails: privs,
+						FunctionName:                   funcSignature(d.GetName(), d.ArgTypes()),
+					}})
+			}
 		case *schemadesc.Mutable:
 			if err := p.writeSchemaDescChange(
 				ctx,
@@ -379,6 +396,9 @@ func getGrantOnObject(targets tree.TargetList, incIAMFunc func(on string)) privi
 	case targets.Types != nil:
 		incIAMFunc(sqltelemetry.OnType)
 		return privilege.Type
+	case targets.Functions != nil:
+		incIAMFunc(sqltelemetry.OnFunction)
+		return privilege.Function
 	default:
 		incIAMFunc(sqltelemetry.OnTable)
 		return privilege.Table
@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
 // A groupNode implements the planNode interface and handles the grouping logic.
@@ -37,6 +38,16 @@ type groupNode struct {
 	// even if there are no input rows, e.g. SELECT MIN(x) FROM t.
 	isScalar bool
 
+	// groupingSets, if non-empty, contains the grouping sets of a GROUPING
+	// SETS, ROLLUP or CUBE aggregation as sets of column indices in the source
+	// plan. Each set is a subset of groupCols.
+	groupingSets []util.FastIntSet
+
+	// groupingSetIDCol is the index of the grouping column in the source plan
+	// that identifies the grouping set of each row. Only used if groupingSets
+	// is non-empty.
+	groupingSetIDCol int
+
 	// funcs contains the information about all aggregate functions.
 	funcs []*aggregateFuncHolder
 
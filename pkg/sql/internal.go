@@ -935,11 +935,30 @@ func (icc *internalClientComm) CreateCopyInResult(pos CmdPos) CopyInResult {
 	panic("unimplemented")
 }
 
+// CreateCopyOutResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateCopyOutResult(
+	stmt *tree.CopyTo, pos CmdPos, conv sessiondatapb.DataConversionConfig, location *time.Location,
+) CopyOutResult {
+	panic("unimplemented")
+}
+
 // CreateDrainResult is part of the ClientComm interface.
 func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
 }
 
+// CreateDeliverNotificationsResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateDeliverNotificationsResult(
+	pos CmdPos,
+) DeliverNotificationsResult {
+	panic("unimplemented")
+}
+
+// CreateStartReplicationResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateStartReplicationResult(pos CmdPos) StartReplicationResult {
+	panic("unimplemented")
+}
+
 // noopClientLock is an implementation of ClientLock that says that no results
 // have been communicated to the client.
 type noopClientLock internalClientComm
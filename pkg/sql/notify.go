@@ -0,0 +1,150 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// maxNotifyPayloadLength is the maximum length of the payload of a
+// notification, as in Postgres.
+const maxNotifyPayloadLength = 8000
+
+type listenNode struct {
+	n *tree.Listen
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+//
+// Unlike in Postgres, the session starts listening immediately rather than
+// when the current transaction commits.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if p.notificationListener == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is only supported in client sessions")
+	}
+	return &listenNode{n: n}, nil
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	return params.p.notificationListener.Listen(string(n.n.ChannelName))
+}
+
+func (n *listenNode) Next(runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *listenNode) Close(context.Context)        {}
+
+type unlistenNode struct {
+	n *tree.Unlisten
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+//
+// Like LISTEN, it takes effect immediately.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	return &unlistenNode{n: n}, nil
+}
+
+func (n *unlistenNode) startExec(params runParams) error {
+	l := params.p.notificationListener
+	if l == nil {
+		// Nothing to do: the session can't be listening.
+		return nil
+	}
+	if n.n.Star {
+		l.UnlistenAll()
+	} else {
+		l.Unlisten(string(n.n.ChannelName))
+	}
+	return nil
+}
+
+func (n *unlistenNode) Next(runParams) (bool, error) { return false, nil }
+func (n *unlistenNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *unlistenNode) Close(context.Context)        {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return nil, err
+	}
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.SendNotification(params.ctx, string(n.n.ChannelName), n.n.Payload)
+}
+
+func (n *notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *notifyNode) Close(context.Context)        {}
+
+// SendNotification is part of the tree.EvalPlanner interface.
+//
+// The notification is written to system.notifications in the current
+// transaction, so it is only delivered to the listening sessions, on any
+// node, once the transaction commits.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return err
+	}
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(payload) >= maxNotifyPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"notify",
+		p.Txn(),
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		"INSERT INTO system.notifications (channel, payload) VALUES ($1, $2)",
+		channel,
+		payload,
+	)
+	return err
+}
+
+// ListeningChannels is part of the tree.EvalPlanner interface.
+func (p *planner) ListeningChannels() []string {
+	if p.notificationListener == nil {
+		return nil
+	}
+	return p.notificationListener.Channels()
+}
+
+// checkNotificationsEnabled returns an error if system.notifications may not
+// exist yet.
+func (p *planner) checkNotificationsEnabled(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"asynchronous notifications are only available once the cluster is fully upgraded")
+	}
+	return nil
+}
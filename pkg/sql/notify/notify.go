@@ -0,0 +1,290 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notify implements the delivery of the asynchronous notifications
+// sent by NOTIFY to the sessions that executed LISTEN.
+//
+// NOTIFY inserts a row into system.notifications in the notifying
+// transaction, so the notification becomes visible exactly when that
+// transaction commits. Each node runs (lazily, once the first session on the
+// node starts listening) a rangefeed over the table and hands every new row
+// to the local sessions listening on its channel. Rows are garbage collected
+// after server.notifications.ttl.
+package notify
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maxPendingNotifications is the maximum number of notifications that are
+// queued for a single session. Notifications are only delivered while the
+// session is idle, so a session that listens but stays in a transaction for a
+// long time would otherwise accumulate notifications without bound.
+const maxPendingNotifications = 10000
+
+// Notification is an asynchronous notification sent by NOTIFY or pg_notify.
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+// Registry tails system.notifications and dispatches the notifications to
+// the Listeners on this node.
+type Registry struct {
+	ambientCtx log.AmbientContext
+	codec      keys.SQLCodec
+	clock      *hlc.Clock
+	f          *rangefeed.Factory
+
+	// decoder and alloc are only used from the rangefeed callback, which is
+	// never invoked concurrently.
+	decoder valueside.Decoder
+	alloc   tree.DatumAlloc
+
+	mu struct {
+		syncutil.Mutex
+		// started is set once the rangefeed has been started.
+		started   bool
+		listeners map[*Listener]struct{}
+		// frontier is the timestamp below which all the notifications have
+		// been dispatched. seen contains the keys of the notifications
+		// dispatched above the frontier. Together they prevent the
+		// redelivery of the notifications that the rangefeed emits again
+		// after a restart.
+		frontier hlc.Timestamp
+		seen     map[string]hlc.Timestamp
+	}
+}
+
+// NewRegistry constructs a Registry.
+func NewRegistry(
+	ambientCtx log.AmbientContext, codec keys.SQLCodec, clock *hlc.Clock, f *rangefeed.Factory,
+) *Registry {
+	r := &Registry{
+		ambientCtx: ambientCtx,
+		codec:      codec,
+		clock:      clock,
+		f:          f,
+		decoder:    valueside.MakeDecoder(systemschema.NotificationsTable.PublicColumns()),
+	}
+	r.mu.listeners = make(map[*Listener]struct{})
+	r.mu.seen = make(map[string]hlc.Timestamp)
+	return r
+}
+
+// NewListener registers a new Listener. wake is called whenever a
+// notification is queued on a Listener that had no pending notifications.
+// The Listener must be closed once the session terminates.
+func (r *Registry) NewListener(wake func()) *Listener {
+	l := &Listener{registry: r, wake: wake}
+	l.mu.channels = make(map[string]hlc.Timestamp)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.listeners[l] = struct{}{}
+	return l
+}
+
+// maybeStartRangeFeed starts the rangefeed over system.notifications if it
+// isn't running yet.
+func (r *Registry) maybeStartRangeFeed() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.started {
+		return nil
+	}
+	// The rangefeed outlives the session that started it, so it doesn't use
+	// the session's context.
+	ctx := r.ambientCtx.AnnotateCtx(context.Background())
+	prefix := r.codec.TablePrefix(uint32(systemschema.NotificationsTable.GetID()))
+	now := r.clock.Now()
+	if _, err := r.f.RangeFeed(ctx,
+		"notifications",
+		[]roachpb.Span{{Key: prefix, EndKey: prefix.PrefixEnd()}},
+		now,
+		r.onValue,
+		rangefeed.WithOnFrontierAdvance(r.onFrontierAdvance),
+	); err != nil {
+		return errors.Wrap(err, "failed to start notifications rangefeed")
+	}
+	r.mu.started = true
+	r.mu.frontier = now
+	return nil
+}
+
+func (r *Registry) onValue(ctx context.Context, value *roachpb.RangeFeedValue) {
+	if !value.Value.IsPresent() {
+		// Rows are only ever deleted by the garbage collection.
+		return
+	}
+	n, err := r.decode(value.Value)
+	if err != nil {
+		log.Warningf(ctx, "failed to decode notification %s: %v", value.Key, err)
+		return
+	}
+	r.dispatch(ctx, string(value.Key), value.Value.Timestamp, n)
+}
+
+// decode returns the notification stored in the given value of
+// system.notifications.
+func (r *Registry) decode(value roachpb.Value) (Notification, error) {
+	bytes, err := value.GetTuple()
+	if err != nil {
+		return Notification{}, err
+	}
+	datums, err := r.decoder.Decode(&r.alloc, bytes)
+	if err != nil {
+		return Notification{}, err
+	}
+	var n Notification
+	if d := datums[2]; d != tree.DNull {
+		n.Channel = string(tree.MustBeDString(d))
+	}
+	if d := datums[3]; d != tree.DNull {
+		n.Payload = string(tree.MustBeDString(d))
+	}
+	return n, nil
+}
+
+// dispatch queues the notification committed at ts under the given key on
+// all the Listeners of its channel.
+func (r *Registry) dispatch(ctx context.Context, key string, ts hlc.Timestamp, n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ts.LessEq(r.mu.frontier) {
+		return
+	}
+	if _, ok := r.mu.seen[key]; ok {
+		return
+	}
+	r.mu.seen[key] = ts
+	for l := range r.mu.listeners {
+		l.deliver(ctx, ts, n)
+	}
+}
+
+func (r *Registry) onFrontierAdvance(_ context.Context, ts hlc.Timestamp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.frontier.Forward(ts)
+	for key, seenTS := range r.mu.seen {
+		if seenTS.LessEq(r.mu.frontier) {
+			delete(r.mu.seen, key)
+		}
+	}
+}
+
+// Listener queues the notifications for a single session.
+type Listener struct {
+	registry *Registry
+	wake     func()
+
+	mu struct {
+		syncutil.Mutex
+		// channels maps each channel the session listens on to the timestamp
+		// at which it started listening. Notifications committed earlier are
+		// not delivered.
+		channels map[string]hlc.Timestamp
+		pending  []Notification
+	}
+}
+
+// Listen starts listening on the given channel. It is a no-op if the
+// Listener is already listening on it.
+func (l *Listener) Listen(channel string) error {
+	if err := l.registry.maybeStartRangeFeed(); err != nil {
+		return err
+	}
+	now := l.registry.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.mu.channels[channel]; !ok {
+		l.mu.channels[channel] = now
+	}
+	return nil
+}
+
+// Unlisten stops listening on the given channel. It is a no-op if the
+// Listener isn't listening on it.
+func (l *Listener) Unlisten(channel string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.mu.channels, channel)
+}
+
+// UnlistenAll stops listening on all the channels.
+func (l *Listener) UnlistenAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.channels = make(map[string]hlc.Timestamp)
+}
+
+// Channels returns the sorted names of the channels the Listener listens on.
+func (l *Listener) Channels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	channels := make([]string, 0, len(l.mu.channels))
+	for channel := range l.mu.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// TakePending returns and clears the queued notifications, in the order in
+// which they were received.
+func (l *Listener) TakePending() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	return pending
+}
+
+// Close unregisters the Listener.
+func (l *Listener) Close() {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.listeners, l)
+}
+
+// deliver queues the notification committed at ts if the Listener was
+// listening on its channel at that time.
+func (l *Listener) deliver(ctx context.Context, ts hlc.Timestamp, n Notification) {
+	l.mu.Lock()
+	since, ok := l.mu.channels[n.Channel]
+	if !ok || ts.Less(since) {
+		l.mu.Unlock()
+		return
+	}
+	if len(l.mu.pending) >= maxPendingNotifications {
+		l.mu.Unlock()
+		log.Warningf(ctx, "too many pending notifications; dropping notification on channel %q", n.Channel)
+		return
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	wake := len(l.mu.pending) == 1
+	l.mu.Unlock()
+	if wake {
+		l.wake()
+	}
+}
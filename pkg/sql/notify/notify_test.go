@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	manual := hlc.NewManualClock(1)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	r := NewRegistry(log.AmbientContext{}, keys.SystemSQLCodec, clock, nil /* f */)
+	// Pretend that the rangefeed is running; the notifications are dispatched
+	// by hand below.
+	r.mu.started = true
+
+	var wakes int
+	l := r.NewListener(func() { wakes++ })
+	defer l.Close()
+	other := r.NewListener(func() {})
+	defer other.Close()
+
+	at := func(nanos int64) hlc.Timestamp { return hlc.Timestamp{WallTime: nanos} }
+	n := func(channel, payload string) Notification {
+		return Notification{Channel: channel, Payload: payload}
+	}
+
+	manual.Set(10)
+	require.NoError(t, l.Listen("b"))
+	require.NoError(t, l.Listen("a"))
+	require.NoError(t, other.Listen("b"))
+	require.Equal(t, []string{"a", "b"}, l.Channels())
+
+	// Notifications committed before LISTEN are not delivered.
+	r.dispatch(ctx, "k1", at(5), n("a", "early"))
+	// Notifications on other channels are not delivered.
+	r.dispatch(ctx, "k2", at(20), n("c", "other"))
+	require.Nil(t, l.TakePending())
+	require.Equal(t, 0, wakes)
+
+	r.dispatch(ctx, "k3", at(20), n("a", "1"))
+	r.dispatch(ctx, "k4", at(21), n("b", "2"))
+	// Redelivered notifications are ignored.
+	r.dispatch(ctx, "k3", at(20), n("a", "1"))
+	require.Equal(t, 1, wakes)
+	require.Equal(t, []Notification{n("a", "1"), n("b", "2")}, l.TakePending())
+	require.Equal(t, []Notification{n("b", "2")}, other.TakePending())
+
+	// Notifications at or below the frontier were already dispatched.
+	r.onFrontierAdvance(ctx, at(30))
+	r.dispatch(ctx, "k5", at(25), n("a", "3"))
+	require.Nil(t, l.TakePending())
+
+	r.dispatch(ctx, "k6", at(31), n("a", "4"))
+	require.Equal(t, 2, wakes)
+	require.Equal(t, []Notification{n("a", "4")}, l.TakePending())
+
+	l.Unlisten("a")
+	r.dispatch(ctx, "k7", at(32), n("a", "5"))
+	require.Nil(t, l.TakePending())
+	require.Equal(t, []string{"b"}, l.Channels())
+
+	l.UnlistenAll()
+	require.Empty(t, l.Channels())
+	r.dispatch(ctx, "k8", at(33), n("b", "6"))
+	require.Nil(t, l.TakePending())
+	require.Equal(t, []Notification{n("b", "6")}, other.TakePending())
+}
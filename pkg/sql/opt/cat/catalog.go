@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/lib/pq/oid"
 )
@@ -126,10 +127,28 @@ type Catalog interface {
 		ctx context.Context, name *tree.UnresolvedObjectName,
 	) (*types.T, error)
 
+	// ResolveFunction locates the user-defined function with the given name,
+	// searching the schemas on the given search path if the name is not
+	// qualified. The returned definition contains all the overloads of the
+	// function. If no such function exists, then ResolveFunction returns nil.
+	// Built-in functions are not resolved by ResolveFunction.
+	ResolveFunction(
+		ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+	) (*tree.FunctionDefinition, error)
+
+	// ResolveFunctionByID locates the user-defined function overload with the
+	// given ID. The returned definition contains only that overload.
+	ResolveFunctionByID(ctx context.Context, id StableID) (*tree.FunctionDefinition, error)
+
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
 
+	// CheckFunctionPrivilege verifies that the current user has the given
+	// privilege on the user-defined function overload with the given ID. If
+	// not, then CheckFunctionPrivilege returns an error.
+	CheckFunctionPrivilege(ctx context.Context, id StableID, priv privilege.Kind) error
+
 	// CheckAnyPrivilege verifies that the current user has any privilege on
 	// the given catalog object. If not, then CheckAnyPrivilege returns an error.
 	CheckAnyPrivilege(ctx context.Context, o Object) error
@@ -136,6 +136,22 @@ type Table interface {
 
 	// Zone returns a table's zone.
 	Zone() Zone
+
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith row-level trigger defined on this table, where
+	// i < TriggerCount. Triggers are returned in the order in which they fire.
+	Trigger(i int) Trigger
+
+	// ExclusionCount returns the number of exclusion constraints defined on
+	// this table.
+	ExclusionCount() int
+
+	// Exclusion returns the ith exclusion constraint defined on this table,
+	// where i < ExclusionCount.
+	Exclusion(i int) ExclusionConstraint
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -150,6 +166,20 @@ type CheckConstraint struct {
 	Validated  bool
 }
 
+// Trigger describes a row-level trigger on a table, which invokes the
+// user-defined function with the given ID on each row modified by one of the
+// given events. For example:
+//
+//   CREATE TRIGGER tr BEFORE INSERT OR UPDATE ON t
+//   FOR EACH ROW EXECUTE FUNCTION f()
+//
+type Trigger struct {
+	Name       tree.Name
+	ActionTime tree.TriggerActionTime
+	Events     tree.TriggerEvents
+	FunctionID StableID
+}
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -249,6 +279,12 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks of the constraint can be deferred
+	// until the end of the transaction. The data of a transaction is not
+	// guaranteed to satisfy a deferrable constraint, so it cannot be used for
+	// optimizations.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -287,6 +323,42 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrability returns whether the checks of the constraint can be deferred
+	// until the end of the transaction. The data of a transaction is not
+	// guaranteed to satisfy a deferrable constraint, so it cannot be used for
+	// optimizations.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// ExclusionConstraint represents an exclusion constraint on a table. An
+// exclusion constraint guarantees that no two rows of the table compare true
+// using all of the constraint's operators on their respective columns. A
+// unique constraint is the special case where every operator is equality. For
+// example, this constraint ensures that the reservations of a room do not
+// overlap:
+//
+//   CREATE TABLE r (room INT, during TSTZRANGE, EXCLUDE (room WITH =, during WITH &&))
+//
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the comparison operator used for the ith column in this
+	// constraint. It is either tree.EQ or tree.Overlaps.
+	Operator(i int) tree.ComparisonOperatorSymbol
+
+	// Predicate returns the partial predicate expression and true if the
+	// constraint is a partial exclusion constraint. If it is not, the empty
+	// string and false are returned.
+	Predicate() (string, bool)
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
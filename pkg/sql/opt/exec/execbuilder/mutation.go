@@ -123,6 +123,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -156,6 +160,18 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	// We cannot use the fast path if there are any cascades (which are used to
+	// execute AFTER triggers).
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
+	// We cannot use the fast path if FK checks must lock the rows they read
+	// (see buildCheckQuery), since its lookups do not acquire locks.
+	if len(ins.FKChecks) > 0 && b.perStatementReadSnapshot() {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
@@ -169,6 +185,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability().Deferrable() {
+			// The check may have to be postponed until the end of the
+			// transaction, which the fast path does not support.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -410,7 +431,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
 	cnt := len(ups.InsertCols) + len(ups.FetchCols) + len(ups.UpdateCols) + len(ups.CheckCols) +
-		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 1
+		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 2
 	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, ups.InsertCols)
 	colList = appendColsWhenPresent(colList, ups.FetchCols)
@@ -418,6 +439,9 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		colList = append(colList, ups.CanaryCol)
 	}
+	if ups.DeleteCol != 0 {
+		colList = append(colList, ups.DeleteCol)
+	}
 	colList = appendColsWhenPresent(colList, ups.CheckCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexPutCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexDelCols)
@@ -434,6 +458,10 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		canaryCol = input.getNodeColumnOrdinal(ups.CanaryCol)
 	}
+	deleteCol := exec.NodeColumnOrdinal(-1)
+	if ups.DeleteCol != 0 {
+		deleteCol = input.getNodeColumnOrdinal(ups.DeleteCol)
+	}
 	insertColOrds := ordinalSetFromColList(ups.InsertCols)
 	fetchColOrds := ordinalSetFromColList(ups.FetchCols)
 	updateColOrds := ordinalSetFromColList(ups.UpdateCols)
@@ -445,6 +473,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		ups.ArbiterIndexes,
 		ups.ArbiterConstraints,
 		canaryCol,
+		deleteCol,
 		insertColOrds,
 		fetchColOrds,
 		updateColOrds,
@@ -490,8 +519,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols)+len(del.PartialIndexDelCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -504,11 +539,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -687,19 +733,39 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 	for i := range checks {
 		c := &checks[i]
 		// Construct the query that returns uniqueness violations.
-		query, err := b.buildRelational(c.Check)
+		query, err := b.buildCheckQuery(c.Check)
 		if err != nil {
 			return err
 		}
 		// Wrap the query in an error node.
+		mkKeyVals := mkCheckKeyValsFn(query, c.KeyCols)
+		if c.Exclusion {
+			// Exclusion constraints cannot be deferred.
+			mkErr := func(row tree.Datums) error {
+				return mkExclusionCheckErr(md, c, mkKeyVals(row))
+			}
+			node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, nil /* deferrable */)
+			if err != nil {
+				return err
+			}
+			b.checks = append(b.checks, node)
+			continue
+		}
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+			return mkUniqueCheckErr(md, c, mkKeyVals(row))
+		}
+		var deferrable *exec.DeferrableCheck
+		tabMeta := md.TableMeta(c.Table)
+		if uc := tabMeta.Table.Unique(c.CheckOrdinal); uc.Deferrability().Deferrable() {
+			deferrable = &exec.DeferrableCheck{
+				TableID:           tabMeta.Table.ID(),
+				ConstraintName:    uc.Name(),
+				IsUnique:          true,
+				InitiallyDeferred: uc.Deferrability() == tree.ConstraintInitiallyDeferred,
+				MkKeyVals:         mkKeyVals,
 			}
-			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -713,19 +779,18 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	for i := range checks {
 		c := &checks[i]
 		// Construct the query that returns FK violations.
-		query, err := b.buildRelational(c.Check)
+		query, err := b.buildCheckQuery(c.Check)
 		if err != nil {
 			return err
 		}
 		// Wrap the query in an error node.
+		mkKeyVals := mkCheckKeyValsFn(query, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
-			}
-			return mkFKCheckErr(md, c, keyVals)
+			return mkFKCheckErr(md, c, mkKeyVals(row))
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(
+			query.root, mkErr, mkDeferrableFKCheck(md, c, mkKeyVals),
+		)
 		if err != nil {
 			return err
 		}
@@ -734,6 +799,71 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// buildCheckQuery builds the query of a FK, uniqueness or exclusion check.
+//
+// In transactions with per-statement read snapshots (i.e. READ COMMITTED
+// transactions), the check reads the rows it depends on with FOR UPDATE
+// locking. The snapshot of the statement does not include the writes of
+// transactions that commit while it runs, so a check performed with a plain
+// read could miss a concurrent write that violates the constraint, e.g. the
+// deletion of the parent row of an inserted child row, and the transaction
+// does not refresh its reads when it commits. A locking read instead waits for
+// conflicting writers, and fails if it encounters a value committed after the
+// read snapshot, in which case the statement is retried on a new snapshot.
+// The locks then prevent the rows read from changing until the transaction
+// commits.
+func (b *Builder) buildCheckQuery(check memo.RelExpr) (execPlan, error) {
+	if b.perStatementReadSnapshot() {
+		// Re-entrance is not possible because checks are never nested.
+		b.forceForUpdateLocking = true
+		defer func() { b.forceForUpdateLocking = false }()
+	}
+	return b.buildRelational(check)
+}
+
+// mkCheckKeyValsFn returns a function that extracts the values of the given
+// key columns from a row produced by a check query.
+func mkCheckKeyValsFn(query execPlan, keyCols opt.ColList) func(tree.Datums) tree.Datums {
+	return func(row tree.Datums) tree.Datums {
+		keyVals := make(tree.Datums, len(keyCols))
+		for i, col := range keyCols {
+			keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+		}
+		return keyVals
+	}
+}
+
+// mkDeferrableFKCheck returns the description of a deferrable FK check, or nil
+// if the check cannot be deferred. Checks for a RESTRICT action are never
+// deferred, matching Postgres.
+func mkDeferrableFKCheck(
+	md *opt.Metadata, c *memo.FKChecksItem, mkKeyVals func(tree.Datums) tree.Datums,
+) *exec.DeferrableCheck {
+	origin := md.TableMeta(c.OriginTable)
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = origin.Table.OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.TableMeta(c.ReferencedTable).Table.InboundForeignKey(c.FKOrdinal)
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			return nil
+		}
+	}
+	if !fk.Deferrability().Deferrable() {
+		return nil
+	}
+	return &exec.DeferrableCheck{
+		TableID:           origin.Table.ID(),
+		ConstraintName:    fk.Name(),
+		InitiallyDeferred: fk.Deferrability() == tree.ConstraintInitiallyDeferred,
+		MkKeyVals:         mkKeyVals,
+	}
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -776,6 +906,60 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values of the new row that
+// correspond to the cat.ExclusionConstraint columns, followed by the values of
+// the existing row that conflicts with it.
+func mkExclusionCheckErr(
+	md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums,
+) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.Exclusion(c.CheckOrdinal)
+	constraintName := ec.Name()
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k)=(2) conflicts with existing key (k)=(2).
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
+
+	var cols bytes.Buffer
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			cols.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		cols.WriteString(string(col.ColName()))
+	}
+	writeKey := func(vals tree.Datums) {
+		details.WriteString("(")
+		details.Write(cols.Bytes())
+		details.WriteString(")=(")
+		for i, d := range vals {
+			if i > 0 {
+				details.WriteString(", ")
+			}
+			details.WriteString(d.String())
+		}
+		details.WriteString(")")
+	}
+	n := ec.ColumnCount()
+	details.WriteString("Key ")
+	writeKey(keyVals[:n])
+	details.WriteString(" conflicts with existing key ")
+	writeKey(keyVals[n:])
+	details.WriteString(".")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			constraintName,
+		),
+		details.String(),
+	)
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -985,7 +1169,7 @@ func (b *Builder) shouldApplyImplicitLockingToMutationInput(mutExpr memo.RelExpr
 // not worth risking the transformation being a pessimization, so it is only
 // applied when doing so does not risk creating artificial contention.
 func (b *Builder) shouldApplyImplicitLockingToUpdateInput(upd *memo.UpdateExpr) bool {
-	if !b.evalCtx.SessionData().ImplicitSelectForUpdate {
+	if !b.implicitLockingEnabled() {
 		return false
 	}
 
@@ -1006,7 +1190,7 @@ func (b *Builder) shouldApplyImplicitLockingToUpdateInput(upd *memo.UpdateExpr)
 // should apply a FOR UPDATE row-level locking mode to the initial row scan of
 // an UPSERT statement.
 func (b *Builder) shouldApplyImplicitLockingToUpsertInput(ups *memo.UpsertExpr) bool {
-	if !b.evalCtx.SessionData().ImplicitSelectForUpdate {
+	if !b.implicitLockingEnabled() {
 		return false
 	}
 
@@ -1038,10 +1222,43 @@ func (b *Builder) shouldApplyImplicitLockingToUpsertInput(ups *memo.UpsertExpr)
 // should apply a FOR UPDATE row-level locking mode to the initial row scan of
 // an DELETE statement.
 //
+// Locking is only applied to the input of a DELETE in transactions with
+// per-statement read snapshots, using the same pattern as UPDATE statements.
+//
 // TODO(nvanbenschoten): implement this method to match on appropriate Delete
-// expression trees and apply a row-level locking mode.
+// expression trees and apply a row-level locking mode in all transactions.
 func (b *Builder) shouldApplyImplicitLockingToDeleteInput(del *memo.DeleteExpr) bool {
-	return false
+	if !b.perStatementReadSnapshot() {
+		return false
+	}
+
+	// Try to match the Delete's input expression against the pattern:
+	//
+	//   [Project]* [IndexJoin] Scan
+	//
+	input := del.Input
+	input = unwrapProjectExprs(input)
+	if idxJoin, ok := input.(*memo.IndexJoinExpr); ok {
+		input = idxJoin.Input
+	}
+	_, ok := input.(*memo.ScanExpr)
+	return ok
+}
+
+// implicitLockingEnabled returns whether implicit row-level locking may be
+// applied to the initial row scan of mutations. It is always enabled for
+// transactions with per-statement read snapshots (i.e. READ COMMITTED
+// transactions), in which locking the rows read by a mutation prevents
+// concurrent writers from modifying them before they are written, which would
+// force the statement to be retried.
+func (b *Builder) implicitLockingEnabled() bool {
+	return b.evalCtx.SessionData().ImplicitSelectForUpdate || b.perStatementReadSnapshot()
+}
+
+// perStatementReadSnapshot returns whether the statement is executed in a
+// transaction that uses a new read snapshot for every statement.
+func (b *Builder) perStatementReadSnapshot() bool {
+	return b.evalCtx.Txn != nil && b.evalCtx.Txn.IsoLevel().PerStatementReadSnapshot()
 }
 
 // unwrapProjectExprs unwraps zero or more nested ProjectExprs. It returns the
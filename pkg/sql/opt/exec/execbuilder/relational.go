@@ -290,6 +290,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.CreateViewExpr:
 		ep, err = b.buildCreateView(t)
 
+	case *memo.CreateFunctionExpr:
+		ep, err = b.buildCreateFunction(t)
+
 	case *memo.WithExpr:
 		ep, err = b.buildWith(t)
 
@@ -1367,8 +1370,18 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 		))
 		reqOrdering := ep.reqOrdering(groupBy)
 		orderType := exec.GroupingOrderType(groupBy.GroupingOrderType(&groupBy.RequiredPhysical().Ordering))
+		var groupingSets []exec.NodeColumnOrdinalSet
+		var groupingSetIDCol exec.NodeColumnOrdinal
+		if groupBy.HasGroupingSets() {
+			groupingSets = make([]exec.NodeColumnOrdinalSet, len(groupBy.GroupingSets))
+			for i := range groupBy.GroupingSets {
+				groupingSets[i] = input.getNodeColumnOrdinalSet(groupBy.GroupingSets[i])
+			}
+			groupingSetIDCol = input.getNodeColumnOrdinal(groupBy.GroupingSetIDCol)
+		}
 		ep.root, err = b.factory.ConstructGroupBy(
 			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering, orderType,
+			groupingSets, groupingSetIDCol,
 		)
 	}
 	if err != nil {
@@ -68,6 +68,20 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 		cols,
 		cv.Deps,
 		cv.TypeDeps,
+		cv.FuncDeps,
+	)
+	return execPlan{root: root}, err
+}
+
+func (b *Builder) buildCreateFunction(cf *memo.CreateFunctionExpr) (execPlan, error) {
+	md := b.mem.Metadata()
+	schema := md.Schema(cf.Schema)
+	root, err := b.factory.ConstructCreateFunction(
+		schema,
+		cf.Syntax,
+		cf.FuncBody,
+		cf.Deps,
+		cf.TypeDeps,
 	)
 	return execPlan{root: root}, err
 }
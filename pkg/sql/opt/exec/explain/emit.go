@@ -282,6 +282,7 @@ var nodeNames = [...]string{
 	cancelSessionsOp:       "cancel sessions",
 	controlJobsOp:          "control jobs",
 	controlSchedulesOp:     "control schedules",
+	createFunctionOp:       "create function",
 	createStatisticsOp:     "create statistics",
 	createTableOp:          "create table",
 	createTableAsOp:        "create table as",
@@ -564,6 +565,9 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			a.Input.Columns(),
 			a.Aggregations, a.GroupCols, a.GroupColOrdering, false, /* isScalar */
 		)
+		if len(a.GroupingSets) > 0 {
+			e.emitGroupingSetsAttributes(a.Input.Columns(), a.GroupingSets)
+		}
 
 	case scalarGroupByOp:
 		a := n.args.(*scalarGroupByArgs)
@@ -865,6 +869,7 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		createTableOp,
 		createTableAsOp,
 		createViewOp,
+		createFunctionOp,
 		sequenceSelectOp,
 		saveTableOp,
 		errorIfRowsOp,
@@ -998,6 +1003,19 @@ func (e *emitter) emitGroupByAttributes(
 	}
 }
 
+func (e *emitter) emitGroupingSetsAttributes(
+	inputCols colinfo.ResultColumns, groupingSets []exec.NodeColumnOrdinalSet,
+) {
+	var buf bytes.Buffer
+	for i, set := range groupingSets {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "(%s)", printColumnSet(inputCols, set))
+	}
+	e.ob.Attr("grouping sets", buf.String())
+}
+
 func (e *emitter) emitJoinAttributes(
 	leftCols, rightCols colinfo.ResultColumns,
 	leftEqCols, rightEqCols []exec.NodeColumnOrdinal,
@@ -268,6 +268,33 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheck describes a foreign key or unique constraint check that can
+// be postponed until the end of the transaction because the constraint was
+// declared DEFERRABLE.
+type DeferrableCheck struct {
+	// TableID is the table on which the constraint is defined. For foreign
+	// keys, this is the origin (referencing) table.
+	TableID cat.StableID
+
+	// ConstraintName is the name of the constraint; it is the name used by SET
+	// CONSTRAINTS.
+	ConstraintName string
+
+	// IsUnique is true if the constraint is a unique constraint and false if it
+	// is a foreign key constraint.
+	IsUnique bool
+
+	// InitiallyDeferred is true if the check is deferred unless the
+	// transaction changed the constraint mode with SET CONSTRAINTS.
+	InitiallyDeferred bool
+
+	// MkKeyVals returns the values of the constraint columns, given an input
+	// row. For foreign keys these correspond to the columns of the constraint
+	// in the table that was modified. They are used to re-check the constraint
+	// when the check is finally run.
+	MkKeyVals func(tree.Datums) tree.Datums
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
@@ -190,6 +190,12 @@ func (c *indexConstraintCtx) makeSpansForSingleColumn(
 func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 	offset int, op opt.Operator, datum tree.Datum, out *constraint.Constraint,
 ) (tight bool) {
+	if c.colType(offset).Family() == types.RangeFamily && datum != tree.DNull {
+		switch op {
+		case opt.ContainsOp, opt.OverlapsOp:
+			return c.makeSpansForRangeDatum(offset, op, datum, out)
+		}
+	}
 	if !c.verifyType(offset, datum.ResolvedType()) {
 		c.unconstrained(offset, out)
 		return false
@@ -345,6 +351,71 @@ func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 	return false
 }
 
+// makeSpansForRangeDatum creates spans for a range index column from a
+// containment (@>) or overlap (&&) expression with a constant value on the
+// right-hand side. Ranges are ordered by their lower bound first, so these
+// expressions constrain the lower bound of the ranges in the column:
+//
+//   r @> x         =>  lower(r) <= x
+//   r @> '[x,y)'   =>  lower(r) <= x
+//   r && '[x,y)'   =>  lower(r) <= y
+//
+// Empty ranges (which sort first) never satisfy these expressions. The spans
+// are never tight.
+func (c *indexConstraintCtx) makeSpansForRangeDatum(
+	offset int, op opt.Operator, datum tree.Datum, out *constraint.Constraint,
+) (tight bool) {
+	colType := c.colType(offset)
+	var bound tree.RangeBound
+	switch t := datum.(type) {
+	case *tree.DRange:
+		if !colType.Equivalent(t.ResolvedType()) {
+			c.unconstrained(offset, out)
+			return false
+		}
+		if t.Empty {
+			if op == opt.OverlapsOp {
+				// Nothing overlaps the empty range.
+				c.contradiction(offset, out)
+				return true
+			}
+			// Every range contains the empty range.
+			c.makeNotNullSpan(offset, out)
+			return true
+		}
+		if op == opt.ContainsOp {
+			bound = t.Lower
+		} else {
+			bound = tree.RangeBound{Val: t.Upper.Val, Inclusive: true}
+		}
+	default:
+		if op != opt.ContainsOp || !colType.RangeContents().Equivalent(datum.ResolvedType()) {
+			c.unconstrained(offset, out)
+			return false
+		}
+		bound = tree.RangeBound{Val: datum, Inclusive: true}
+	}
+
+	startKey := constraint.MakeKey(tree.NewDEmptyRange(colType))
+	endKey := emptyKey
+	if !bound.IsInf() || op == opt.ContainsOp {
+		// The ranges that satisfy the expression are at most the range that
+		// starts at the bound and is unbounded above.
+		end, err := tree.NewDRange(colType, bound, tree.RangeBound{})
+		if err != nil {
+			c.unconstrained(offset, out)
+			return false
+		}
+		endKey = constraint.MakeKey(end)
+	}
+	c.singleSpan(
+		offset, startKey, excludeBoundary, endKey, includeBoundary,
+		c.columns[offset].Descending(),
+		out,
+	)
+	return false
+}
+
 // makeSpansForTupleInequality creates spans for index columns starting at
 // <offset> from a tuple inequality.
 // Assumes that ev.Operator() is an inequality and both sides are tuples.
@@ -194,6 +194,7 @@ func (m *Memo) CheckExpr(e opt.Expr) {
 	case *GroupByExpr, *ScalarGroupByExpr:
 		checkErrorOnDup(e.(RelExpr))
 		checkNullsAreDistinct(e.(RelExpr))
+		checkGroupingSets(e.(RelExpr))
 
 		// Check that aggregates cannot be FirstAgg.
 		for _, item := range *t.Child(1).(*AggregationsExpr) {
@@ -460,6 +461,30 @@ func checkNullsAreDistinct(e RelExpr) {
 	}
 }
 
+func checkGroupingSets(e RelExpr) {
+	// Only GroupBy should compute grouping sets, and every set must be a subset
+	// of the grouping columns which must include the grouping set ID column.
+	private := e.Private().(*GroupingPrivate)
+	if !private.HasGroupingSets() {
+		if private.GroupingSetIDCol != 0 {
+			panic(errors.AssertionFailedf("grouping set ID column set without grouping sets"))
+		}
+		return
+	}
+	if e.Op() != opt.GroupByOp {
+		panic(errors.AssertionFailedf("%s should never have grouping sets", log.Safe(e.Op())))
+	}
+	if !private.GroupingCols.Contains(private.GroupingSetIDCol) {
+		panic(errors.AssertionFailedf("grouping set ID column is not a grouping column"))
+	}
+	for _, set := range private.GroupingSets {
+		if !set.SubsetOf(private.GroupingCols) || set.Contains(private.GroupingSetIDCol) {
+			panic(errors.AssertionFailedf(
+				"grouping set %s is not a subset of grouping columns %s", set, private.GroupingCols))
+		}
+	}
+}
+
 func checkOutputCols(e opt.Expr) {
 	set := opt.ColSet{}
 
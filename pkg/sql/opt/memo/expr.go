@@ -975,8 +975,9 @@ type FKCascades []FKCascade
 
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
+// AFTER triggers are also executed as cascading queries.
 type FKCascade struct {
-	// FKName is the name of the FK constraint.
+	// FKName is the name of the FK constraint, or of the trigger.
 	FKName string
 
 	// Builder is an object that can be used as the "optbuilder" for the cascading
@@ -1053,6 +1054,11 @@ const (
 // all columns match, and PartialStreaming if only some match. It is similar to
 // StreamingGroupingColOrdering, but does not build an ordering.
 func (g *GroupingPrivate) GroupingOrderType(required *props.OrderingChoice) GroupingOrderType {
+	if g.HasGroupingSets() {
+		// Rows are expanded into one row per grouping set before they are
+		// grouped, so the input ordering cannot be used to stream the groups.
+		return NoStreaming
+	}
 	inputOrdering := required.Intersection(&g.Ordering)
 	count := 0
 	for i := range inputOrdering.Columns {
@@ -1074,3 +1080,44 @@ func (g *GroupingPrivate) GroupingOrderType(required *props.OrderingChoice) Grou
 	}
 	return PartialStreaming
 }
+
+// HasGroupingSets returns true if the grouping operator computes multiple
+// grouping sets (i.e. GROUPING SETS, ROLLUP or CUBE).
+func (g *GroupingPrivate) HasGroupingSets() bool {
+	return len(g.GroupingSets) > 0
+}
+
+// GroupingSets is the list of grouping sets computed by a GroupBy operator.
+// Each set is a subset of the operator's grouping columns; the grouping set ID
+// column is not part of any set. The GroupBy operator produces a separate
+// aggregation for each set, as if each input row were duplicated once per set
+// with the grouping columns that are not part of that set replaced by NULL.
+// The same set can appear multiple times, in which case its groups are
+// produced once per occurrence.
+type GroupingSets []opt.ColSet
+
+// Equals returns true if the two lists contain the same sets in the same
+// order.
+func (s GroupingSets) Equals(other GroupingSets) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if !s[i].Equals(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsEmptySet returns true if one of the grouping sets is empty. An empty
+// grouping set produces a single group over all input rows, so the operator
+// must produce a row for it even if the input is empty.
+func (s GroupingSets) ContainsEmptySet() bool {
+	for i := range s {
+		if s[i].Empty() {
+			return true
+		}
+	}
+	return false
+}
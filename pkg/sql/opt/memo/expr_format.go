@@ -212,7 +212,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *AlterRangeRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -312,6 +312,22 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		if !f.HasFlags(ExprFmtHideColumns) && !private.GroupingCols.Empty() {
 			f.formatColList(e, tp, "grouping columns:", private.GroupingCols.ToList())
 		}
+		if !f.HasFlags(ExprFmtHideColumns) && private.HasGroupingSets() {
+			n := tp.Child("grouping sets")
+			for _, set := range private.GroupingSets {
+				f.Buffer.Reset()
+				f.Buffer.WriteByte('(')
+				set.ForEach(func(col opt.ColumnID) {
+					if f.Buffer.Len() > 1 {
+						f.space()
+					}
+					f.formatColSimple("" /* label */, col)
+				})
+				f.Buffer.WriteByte(')')
+				n.Child(f.Buffer.String())
+			}
+			f.formatColList(e, tp, "grouping set id:", opt.ColList{private.GroupingSetIDCol})
+		}
 		if !f.HasFlags(ExprFmtHidePhysProps) && !private.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", private.Ordering)
 		}
@@ -608,6 +624,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 				f.formatArbiterIndexes(tp, t.ArbiterIndexes, t.Table)
 				f.formatArbiterConstraints(tp, t.ArbiterConstraints, t.Table)
 				f.formatColList(e, tp, "canary column:", opt.ColList{t.CanaryCol})
+				if t.DeleteCol != 0 {
+					f.formatColList(e, tp, "delete column:", opt.ColList{t.DeleteCol})
+				}
 				f.formatOptionalColList(e, tp, "fetch columns:", t.FetchCols)
 				f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
 				f.formatMutationCols(e, tp, "update-mapping:", t.UpdateCols, t.Table)
@@ -688,6 +707,14 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			n.Child(f.Buffer.String())
 		}
 
+	case *CreateFunctionExpr:
+		tp.Child(t.FuncBody)
+		n := tp.Child("dependencies")
+		for _, dep := range t.Deps {
+			name := dep.DataSource.Name()
+			n.Child(name.String())
+		}
+
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
 
@@ -1109,7 +1136,15 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
-		constraint := tab.Table.Unique(t.CheckOrdinal)
+		var constraint interface {
+			ColumnCount() int
+			ColumnOrdinal(tab cat.Table, i int) int
+		}
+		if t.Exclusion {
+			constraint = tab.Table.Exclusion(t.CheckOrdinal)
+		} else {
+			constraint = tab.Table.Unique(t.CheckOrdinal)
+		}
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
 			if i > 0 {
@@ -1452,6 +1487,9 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 
 	case *GroupingPrivate:
 		fmt.Fprintf(f.Buffer, " cols=%s", t.GroupingCols.String())
+		if t.HasGroupingSets() {
+			fmt.Fprintf(f.Buffer, ",sets=%d", len(t.GroupingSets))
+		}
 		if !t.Ordering.Any() {
 			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
 		}
@@ -1532,6 +1570,10 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		schema := f.Memo.Metadata().Schema(t.Schema)
+		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.Syntax.FuncName.Object())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
@@ -449,6 +449,13 @@ func (h *hasher) HashColSet(val opt.ColSet) {
 	h.hash = hash
 }
 
+func (h *hasher) HashGroupingSets(val GroupingSets) {
+	for i := range val {
+		h.HashColSet(val[i])
+	}
+	h.HashInt(len(val))
+}
+
 func (h *hasher) HashColList(val opt.ColList) {
 	hash := h.hash
 	for _, id := range val {
@@ -607,6 +614,15 @@ func (h *hasher) HashViewTypeDeps(val opt.ViewTypeDeps) {
 	h.hash = hash
 }
 
+func (h *hasher) HashViewFuncDeps(val opt.ViewFuncDeps) {
+	hash := h.hash
+	val.ForEach(func(i int) {
+		hash ^= internHash(i)
+		hash *= prime64
+	})
+	h.hash = hash
+}
+
 func (h *hasher) HashWindowFrame(val WindowFrame) {
 	h.HashInt(int(val.StartBoundType))
 	h.HashInt(int(val.EndBoundType))
@@ -900,6 +916,10 @@ func (h *hasher) IsColSetEqual(l, r opt.ColSet) bool {
 	return l.Equals(r)
 }
 
+func (h *hasher) IsGroupingSetsEqual(l, r GroupingSets) bool {
+	return l.Equals(r)
+}
+
 func (h *hasher) IsColListEqual(l, r opt.ColList) bool {
 	return l.Equals(r)
 }
@@ -1024,6 +1044,10 @@ func (h *hasher) IsViewTypeDepsEqual(l, r opt.ViewTypeDeps) bool {
 	return l.Equals(r)
 }
 
+func (h *hasher) IsViewFuncDepsEqual(l, r opt.ViewFuncDeps) bool {
+	return l.Equals(r)
+}
+
 func (h *hasher) IsWindowFrameEqual(l, r WindowFrame) bool {
 	return l.StartBoundType == r.StartBoundType &&
 		l.EndBoundType == r.EndBoundType &&
@@ -326,6 +326,13 @@ func TestInterner(t *testing.T) {
 			{val1: opt.ColList{1, 2}, val2: opt.ColList{1, 2, 3}, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashGroupingSets, eqFn: in.hasher.IsGroupingSetsEqual, variations: []testVariation{
+			{val1: GroupingSets{}, val2: GroupingSets{}, equal: true},
+			{val1: GroupingSets{opt.MakeColSet(1, 2), opt.MakeColSet()}, val2: GroupingSets{opt.MakeColSet(2, 1), opt.MakeColSet()}, equal: true},
+			{val1: GroupingSets{opt.MakeColSet(1, 2), opt.MakeColSet(1)}, val2: GroupingSets{opt.MakeColSet(1), opt.MakeColSet(1, 2)}, equal: false},
+			{val1: GroupingSets{opt.MakeColSet(1)}, val2: GroupingSets{opt.MakeColSet(1), opt.MakeColSet()}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashOrdering, eqFn: in.hasher.IsOrderingEqual, variations: []testVariation{
 			{val1: opt.Ordering{}, val2: opt.Ordering{}, equal: true},
 			{val1: opt.Ordering{-1, 1}, val2: opt.Ordering{-1, 1}, equal: true},
@@ -573,7 +573,16 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 	// Not Null Columns
 	// ----------------
 	// Propagate not null setting from input columns that are being grouped.
+	// With grouping sets, a grouping column is NULL in the groups of every set
+	// that does not contain it.
 	rel.NotNullCols = inputProps.NotNullCols.Intersection(groupingCols)
+	if groupPrivate.HasGroupingSets() {
+		for _, set := range groupPrivate.GroupingSets {
+			nullCols := groupingCols.Difference(set)
+			nullCols.Remove(groupPrivate.GroupingSetIDCol)
+			rel.NotNullCols.DifferenceWith(nullCols)
+		}
+	}
 
 	for i := range aggs {
 		item := &aggs[i]
@@ -586,9 +595,10 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 		}
 
 		// If there is a possibility that the aggregate function has zero input
-		// rows, then it may return NULL. This is possible with ScalarGroupBy and
-		// with AggFilter.
-		if groupExpr.Op() == opt.ScalarGroupByOp || item.Agg.Op() == opt.AggFilterOp {
+		// rows, then it may return NULL. This is possible with ScalarGroupBy, with
+		// AggFilter and with an empty grouping set.
+		if groupExpr.Op() == opt.ScalarGroupByOp || item.Agg.Op() == opt.AggFilterOp ||
+			groupPrivate.GroupingSets.ContainsEmptySet() {
 			continue
 		}
 
@@ -610,25 +620,13 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 
 	// Functional Dependencies
 	// -----------------------
-	rel.FuncDeps.CopyFrom(&inputProps.FuncDeps)
-	if groupingCols.Empty() {
-		// When there are no grouping columns, then there is a single group, and
-		// therefore at most one output row.
-		rel.FuncDeps.MakeMax1Row(rel.OutputCols)
+	if groupPrivate.HasGroupingSets() {
+		// The grouping columns of a set that does not contain them are NULL, so
+		// the dependencies of the input don't hold. However, the grouping columns
+		// (which include the grouping set ID column) still form a strict key.
+		rel.FuncDeps.AddStrictKey(groupingCols, rel.OutputCols)
 	} else {
-		// Start by eliminating input columns that aren't projected.
-		rel.FuncDeps.ProjectCols(rel.OutputCols)
-
-		// The output of most of the grouping operators forms a strict key because
-		// they eliminate all duplicates in the grouping columns. However, the
-		// UpsertDistinctOn and EnsureUpsertDistinctOn operators do not group
-		// NULL values together, so they only form a lax key when NULL values
-		// are possible.
-		if groupPrivate.NullsAreDistinct && !groupingCols.SubsetOf(rel.NotNullCols) {
-			rel.FuncDeps.AddLaxKey(groupingCols, rel.OutputCols)
-		} else {
-			rel.FuncDeps.AddStrictKey(groupingCols, rel.OutputCols)
-		}
+		b.buildGroupingFuncDeps(groupPrivate, inputProps, rel)
 	}
 
 	// Cardinality
@@ -636,6 +634,17 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 	if groupExpr.Op() == opt.ScalarGroupByOp {
 		// Scalar GroupBy returns exactly one row.
 		rel.Cardinality = props.OneCardinality
+	} else if groupPrivate.HasGroupingSets() {
+		// Every grouping set acts like a separate GroupBy, except that an empty
+		// set always returns exactly one row.
+		rel.Cardinality = props.Cardinality{}
+		for _, set := range groupPrivate.GroupingSets {
+			if set.Empty() {
+				rel.Cardinality = rel.Cardinality.Add(props.OneCardinality)
+			} else {
+				rel.Cardinality = rel.Cardinality.Add(inputProps.Cardinality.AsLowAs(1))
+			}
+		}
 	} else {
 		// GroupBy and DistinctOn act like a filter, never returning more rows
 		// than the input has. However, if the input has at least one row, then
@@ -655,6 +664,34 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 	}
 }
 
+// buildGroupingFuncDeps derives the functional dependencies of a grouping
+// operator without grouping sets from the dependencies of its input.
+func (b *logicalPropsBuilder) buildGroupingFuncDeps(
+	groupPrivate *GroupingPrivate, inputProps *props.Relational, rel *props.Relational,
+) {
+	groupingCols := groupPrivate.GroupingCols
+	rel.FuncDeps.CopyFrom(&inputProps.FuncDeps)
+	if groupingCols.Empty() {
+		// When there are no grouping columns, then there is a single group, and
+		// therefore at most one output row.
+		rel.FuncDeps.MakeMax1Row(rel.OutputCols)
+	} else {
+		// Start by eliminating input columns that aren't projected.
+		rel.FuncDeps.ProjectCols(rel.OutputCols)
+
+		// The output of most of the grouping operators forms a strict key because
+		// they eliminate all duplicates in the grouping columns. However, the
+		// UpsertDistinctOn and EnsureUpsertDistinctOn operators do not group
+		// NULL values together, so they only form a lax key when NULL values
+		// are possible.
+		if groupPrivate.NullsAreDistinct && !groupingCols.SubsetOf(rel.NotNullCols) {
+			rel.FuncDeps.AddLaxKey(groupingCols, rel.OutputCols)
+		} else {
+			rel.FuncDeps.AddStrictKey(groupingCols, rel.OutputCols)
+		}
+	}
+}
+
 func (b *logicalPropsBuilder) buildUnionProps(union *UnionExpr, rel *props.Relational) {
 	b.buildSetProps(union, rel)
 }
@@ -1483,6 +1520,12 @@ func (b *logicalPropsBuilder) buildCreateViewProps(cv *CreateViewExpr, rel *prop
 	BuildSharedProps(cv, &rel.Shared, b.evalCtx)
 }
 
+func (b *logicalPropsBuilder) buildCreateFunctionProps(
+	cf *CreateFunctionExpr, rel *props.Relational,
+) {
+	BuildSharedProps(cf, &rel.Shared, b.evalCtx)
+}
+
 func (b *logicalPropsBuilder) buildFiltersItemProps(item *FiltersItem, scalar *props.Scalar) {
 	BuildSharedProps(item.Condition, &scalar.Shared, b.evalCtx)
 
@@ -1809,6 +1852,12 @@ func MakeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 				continue
 			}
 
+			if unique.Deferrability().Deferrable() {
+				// The checks of this unique constraint may be deferred, so there may
+				// be duplicates until the end of the transaction.
+				continue
+			}
+
 			if _, isPartial := unique.Predicate(); isPartial {
 				// Partial constraints cannot be considered while building functional
 				// dependency keys for the table because their keys are only unique
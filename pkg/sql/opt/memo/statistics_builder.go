@@ -1738,6 +1738,19 @@ func (sb *statisticsBuilder) buildGroupBy(groupNode RelExpr, relProps *props.Rel
 			inputStats := sb.statsFromChild(groupNode, 0 /* childIdx */)
 			s.RowCount = min(1, inputStats.RowCount)
 		}
+	} else if groupingPrivate.HasGroupingSets() {
+		// Estimate the row count of every grouping set separately, based on the
+		// distinct count of its columns. An empty set returns exactly one row.
+		inputStats := sb.statsFromChild(groupNode, 0 /* childIdx */)
+		s.RowCount = 0
+		for _, set := range groupingPrivate.GroupingSets {
+			if set.Empty() {
+				s.RowCount++
+				continue
+			}
+			colStat := sb.colStatFromChild(set, groupNode, 0 /* childIdx */)
+			s.RowCount += min(colStat.DistinctCount, inputStats.RowCount)
+		}
 	} else {
 		inputStats := sb.statsFromChild(groupNode, 0 /* childIdx */)
 
@@ -110,6 +110,13 @@ func (c *CustomFuncs) HasNoGroupingCols(private *memo.GroupingPrivate) bool {
 	return private.GroupingCols.Empty()
 }
 
+// HasGroupingSets returns true if the private describes a GroupBy with
+// multiple grouping sets (i.e. GROUPING SETS, ROLLUP or CUBE). Most of the
+// transformations of GroupBy operators are not valid in that case.
+func (c *CustomFuncs) HasGroupingSets(private *memo.GroupingPrivate) bool {
+	return private.HasGroupingSets()
+}
+
 // GroupingInputOrdering returns the Ordering in the private.
 func (c *CustomFuncs) GroupingInputOrdering(private *memo.GroupingPrivate) props.OrderingChoice {
 	return private.Ordering
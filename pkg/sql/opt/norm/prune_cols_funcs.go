@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -77,6 +78,9 @@ func (c *CustomFuncs) neededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.DeleteCol != 0 {
+		cols.Add(private.DeleteCol)
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -145,6 +149,26 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// addDeleteCols adds the columns that are needed to delete existing rows.
+	addDeleteCols := func() {
+		// Add in all strict key columns from all indexes, since these are needed
+		// to compose the keys of rows to delete. Include mutation indexes, since
+		// it is necessary to delete rows even from indexes that are being added
+		// or dropped.
+		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
+			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+		}
+
+		// Add inbound foreign keys that may require a check or cascade.
+		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
+			inboundFK := tabMeta.Table.InboundForeignKey(i)
+			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
+				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -222,28 +246,45 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 			}
 		}
 
-	case opt.DeleteOp:
-		// Add in all strict key columns from all indexes, since these are needed
-		// to compose the keys of rows to delete. Include mutation indexes, since
-		// it is necessary to delete rows even from indexes that are being added
-		// or dropped.
-		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
-			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+		// Add the columns of the rows passed to AFTER UPDATE triggers.
+		if op == opt.UpdateOp {
+			cols.UnionWith(triggerRowCols(tabMeta, tree.TriggerEventUpdate))
 		}
 
-		// Add inbound foreign keys that may require a check or cascade.
-		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
-			inboundFK := tabMeta.Table.InboundForeignKey(i)
-			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
-				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
-				cols.Add(tabMeta.MetaID.ColumnID(ord))
-			}
+		// The Upsert built for a MERGE statement can also delete existing rows.
+		if op == opt.UpsertOp && private.DeleteCol != 0 {
+			addDeleteCols()
 		}
+
+	case opt.DeleteOp:
+		addDeleteCols()
+
+		// Add the columns of the rows passed to AFTER DELETE triggers.
+		cols.UnionWith(triggerRowCols(tabMeta, tree.TriggerEventDelete))
 	}
 
 	return cols
 }
 
+// triggerRowCols returns the set of columns which are passed to the AFTER
+// triggers on the table that fire for the given event. These are the visible
+// columns of the table. The column IDs are relative to tabMeta.
+func triggerRowCols(tabMeta *opt.TableMeta, event tree.TriggerEvent) opt.ColSet {
+	var cols opt.ColSet
+	tab := tabMeta.Table
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		if t := tab.Trigger(i); t.ActionTime == tree.TriggerAfter && t.Events.Contains(event) {
+			for ord, m := 0, tab.ColumnCount(); ord < m; ord++ {
+				if col := tab.Column(ord); col.Visibility() == cat.Visible && col.Kind() == cat.Ordinary {
+					cols.Add(tabMeta.MetaID.ColumnID(ord))
+				}
+			}
+			break
+		}
+	}
+	return cols
+}
+
 // CanPruneCols returns true if the target expression has extra columns that are
 // not needed at this level of the tree, and can be eliminated by one of the
 // PruneCols rules. CanPruneCols uses the PruneCols property to determine the
@@ -118,6 +118,21 @@ type Builder struct {
 	// are disabled and certain statements (like mutations) are disallowed.
 	insideViewDef bool
 
+	// If set, we are processing the body of a CREATE FUNCTION statement; in
+	// this case, references to user-defined functions are disallowed.
+	insideFuncDef bool
+
+	// udfArgScope contains the columns which hold the arguments of the
+	// user-defined function whose body is currently being built, if any. The
+	// $n placeholders in the body are resolved to these columns.
+	udfArgScope *scope
+
+	// triggerArgScope contains the column which holds the argument of the
+	// function of an AFTER trigger whose body is being built, if any. The
+	// input of the INSERT statement in the body is joined with it; see
+	// buildInputForInsert.
+	triggerArgScope *scope
+
 	// If set, we are collecting view dependencies in viewDeps. This can only
 	// happen inside view definitions.
 	//
@@ -127,6 +142,7 @@ type Builder struct {
 	trackViewDeps bool
 	viewDeps      opt.ViewDeps
 	viewTypeDeps  opt.ViewTypeDeps
+	viewFuncDeps  opt.ViewFuncDeps
 
 	// If set, the data source names in the AST are rewritten to the fully
 	// qualified version (after resolution). Used to construct the strings for
@@ -264,14 +280,19 @@ func (b *Builder) buildStmtAtRoot(stmt tree.Statement, desiredTypes []*types.T)
 func (b *Builder) buildStmt(
 	stmt tree.Statement, desiredTypes []*types.T, inScope *scope,
 ) (outScope *scope) {
-	if b.insideViewDef {
-		// A blocklist of statements that can't be used from inside a view.
+	if b.insideViewDef || b.insideFuncDef {
+		// A blocklist of statements that can't be used from inside a view or a
+		// function body.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.Merge, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
+			kind := "view"
+			if b.insideFuncDef {
+				kind = "function"
+			}
 			panic(pgerror.Newf(
-				pgcode.Syntax, "%s cannot be used inside a view definition", stmt.StatementTag(),
+				pgcode.Syntax, "%s cannot be used inside a %s definition", stmt.StatementTag(), kind,
 			))
 		}
 	}
@@ -298,12 +319,20 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
+	case *tree.CreateFunction:
+		return b.buildCreateFunction(stmt, inScope)
+
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
@@ -0,0 +1,201 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	fnName := cf.FuncName.ToTableName()
+	sch, _ := b.resolveSchemaForCreate(&fnName)
+	schID := b.factory.Metadata().AddSchema(sch)
+
+	body := b.checkFunctionOptions(cf.Options)
+
+	// We build the body of the function to:
+	//  - check the statement semantically,
+	//  - get the fully resolved names into the AST, and
+	//  - collect the dependencies of the function in b.viewDeps and
+	//    b.viewTypeDeps.
+	// The result is not otherwise used.
+	b.insideFuncDef = true
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+	defer func() {
+		b.insideFuncDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.viewTypeDeps = util.FastIntSet{}
+		b.qualifyDataSourceNamesInAST = false
+		b.udfArgScope = nil
+	}()
+
+	// Resolve the types in the signature of the function, and synthesize a
+	// column for each argument which the body can reference.
+	argScope := b.allocScope()
+	for i := range cf.Args {
+		arg := &cf.Args[i]
+		for j := 0; j < i; j++ {
+			if arg.Name != "" && cf.Args[j].Name == arg.Name {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", arg.Name))
+			}
+		}
+		typ := b.resolveFunctionSignatureType(arg.Type)
+		b.synthesizeColumn(argScope, scopeColName(arg.Name), typ, nil /* expr */, nil /* scalar */)
+	}
+	retTyp := b.resolveFunctionSignatureType(cf.ReturnType)
+
+	stmts, err := parser.Parse(body)
+	if err != nil {
+		panic(err)
+	}
+	if len(stmts) != 1 {
+		panic(unimplemented.NewWithIssuef(17511,
+			"function bodies must contain exactly one statement, found %d", len(stmts)))
+	}
+	var bodyScope *scope
+	bodyStmt := stmts[0].AST
+	b.udfArgScope = argScope
+	switch t := bodyStmt.(type) {
+	case *tree.Select:
+		bodyScope = b.buildStmt(t, nil /* desiredTypes */, argScope)
+		bodyScope.removeHiddenCols()
+
+	case *tree.Insert:
+		// Functions which insert rows can only be invoked by AFTER triggers, so
+		// they cannot produce a result.
+		if retTyp.Family() != types.VoidFamily {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+				"functions with an INSERT body must return VOID"))
+		}
+		if tree.HasReturningClause(t.Returning) {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+				"INSERT statements in function bodies cannot have a RETURNING clause"))
+		}
+		b.buildUDFInsertBody(t, argScope)
+
+	default:
+		panic(unimplemented.NewWithIssuef(17511,
+			"%s statements are not supported in function bodies", bodyStmt.StatementTag()))
+	}
+	b.udfArgScope = nil
+
+	// If the type of any column that the body references is user defined, add
+	// a type dependency between the function and the UDT.
+	for _, d := range b.viewDeps {
+		d.ColumnOrdinals.ForEach(func(ord int) {
+			ids, err := d.DataSource.CollectTypes(ord)
+			if err != nil {
+				panic(err)
+			}
+			for _, id := range ids {
+				b.viewTypeDeps.Add(int(id))
+			}
+		})
+	}
+
+	// Check that the result of the body can be converted to the return type,
+	// in the same way as when the function is called.
+	if bodyScope != nil {
+		resultScope := b.allocScope()
+		b.buildUDFResult(retTyp, fnName.Object(), bodyScope, resultScope, false /* expandTuple */)
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:   schID,
+			Syntax:   cf,
+			FuncBody: tree.AsStringWithFlags(bodyStmt, tree.FmtParsable),
+			Deps:     b.viewDeps,
+			TypeDeps: b.viewTypeDeps,
+		},
+	)
+	return outScope
+}
+
+// resolveFunctionSignatureType resolves a type in the signature of a function
+// which is being created, and adds a dependency on it if it is user-defined.
+func (b *Builder) resolveFunctionSignatureType(ref tree.ResolvableTypeReference) *types.T {
+	typ, err := tree.ResolveType(b.ctx, ref, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+	if typ.UserDefined() {
+		children, err := typedesc.GetTypeDescriptorClosure(typ)
+		if err != nil {
+			panic(err)
+		}
+		for id := range children {
+			b.viewTypeDeps.Add(int(id))
+		}
+	}
+	return typ
+}
+
+// checkFunctionOptions validates the options of a CREATE FUNCTION statement
+// and returns the body of the function.
+func (b *Builder) checkFunctionOptions(options tree.FunctionOptions) (body string) {
+	var seenBody, seenLang, seenVolatility, seenLeakproof, seenNullInput bool
+	volatility := tree.FunctionVolatile
+	leakproof := false
+	checkDup := func(seen *bool) {
+		if *seen {
+			panic(pgerror.New(pgcode.Syntax, "conflicting or redundant options"))
+		}
+		*seen = true
+	}
+	for _, option := range options {
+		switch t := option.(type) {
+		case tree.FunctionBodyStr:
+			checkDup(&seenBody)
+			body = string(t)
+		case tree.FunctionLanguage:
+			checkDup(&seenLang)
+			if !strings.EqualFold(string(t), string(tree.FunctionLangSQL)) {
+				panic(unimplemented.NewWithIssuef(17511,
+					"language %q is not supported for user-defined functions", string(t)))
+			}
+		case tree.FunctionVolatility:
+			checkDup(&seenVolatility)
+			volatility = t
+		case tree.FunctionLeakproof:
+			checkDup(&seenLeakproof)
+			leakproof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			checkDup(&seenNullInput)
+		}
+	}
+	if !seenLang {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified"))
+	}
+	if !seenBody {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified"))
+	}
+	if leakproof && volatility != tree.FunctionImmutable {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+			"only immutable functions can be leakproof"))
+	}
+	return body
+}
@@ -26,7 +26,8 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 	// We build the select statement to:
 	//  - check the statement semantically,
 	//  - get the fully resolved names into the AST, and
-	//  - collect the view dependencies in b.viewDeps.
+	//  - collect the view dependencies in b.viewDeps, b.viewTypeDeps and
+	//    b.viewFuncDeps.
 	// The result is not otherwise used.
 	b.insideViewDef = true
 	b.trackViewDeps = true
@@ -36,6 +37,7 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 		b.trackViewDeps = false
 		b.viewDeps = nil
 		b.viewTypeDeps = util.FastIntSet{}
+		b.viewFuncDeps = util.FastIntSet{}
 		b.qualifyDataSourceNamesInAST = false
 	}()
 
@@ -87,6 +89,7 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 			Columns:      p,
 			Deps:         b.viewDeps,
 			TypeDeps:     b.viewTypeDeps,
+			FuncDeps:     b.viewFuncDeps,
 		},
 	)
 	return outScope
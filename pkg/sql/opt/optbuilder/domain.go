@@ -0,0 +1,119 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// domainValue is a reference to the value being checked against the
+// constraints of a domain. It stands in for VALUE in the CHECK constraint
+// expressions of the domain, and is built as the already-built scalar
+// expression of the value.
+type domainValue struct {
+	scalar opt.ScalarExpr
+	typ    *types.T
+}
+
+// String is part of the tree.Expr interface.
+func (v *domainValue) String() string {
+	return tree.AsString(v)
+}
+
+// Format is part of the tree.Expr interface.
+func (v *domainValue) Format(ctx *tree.FmtCtx) {
+	name := schemaexpr.DomainValueName
+	ctx.FormatNode(&name)
+}
+
+// Walk is part of the tree.Expr interface.
+func (v *domainValue) Walk(_ tree.Visitor) tree.Expr {
+	return v
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (v *domainValue) TypeCheck(
+	_ context.Context, _ *tree.SemaContext, _ *types.T,
+) (tree.TypedExpr, error) {
+	return v, nil
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (v *domainValue) ResolvedType() *types.T {
+	return v.typ
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (v *domainValue) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("domainValue must be replaced before evaluation"))
+}
+
+var _ tree.Expr = &domainValue{}
+var _ tree.TypedExpr = &domainValue{}
+
+// buildDomainConstraints builds a scalar expression which casts the given input
+// to the domain type typ, after checking that it satisfies the NOT NULL and
+// CHECK constraints of the domain. An error is raised during evaluation if any
+// of the constraints is violated.
+//
+// Note that the input is referenced by each of the constraints, so it is
+// evaluated once per constraint.
+func (b *Builder) buildDomainConstraints(input opt.ScalarExpr, typ *types.T) opt.ScalarExpr {
+	md := typ.TypeMeta.DomainData
+	if md == nil {
+		panic(errors.AssertionFailedf("domain type %s has not been hydrated", typ.SQLString()))
+	}
+	// Track the domain so that the query is invalidated when its constraints
+	// change.
+	b.factory.Metadata().AddUserDefinedType(typ)
+	baseType := typ.DomainBaseType()
+	value := &domainValue{scalar: b.factory.ConstructCast(input, baseType), typ: baseType}
+	domainName := tree.NewDString(typ.Name())
+
+	var expr tree.Expr = value
+	if md.NotNull {
+		expr = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.assert_domain_not_null"),
+			Exprs: tree.Exprs{expr, domainName},
+		}
+	}
+	for i := range md.CheckConstraints {
+		c := &md.CheckConstraints[i]
+		check, err := parser.ParseExpr(c.Expr)
+		if err != nil {
+			panic(err)
+		}
+		check, err = schemaexpr.ReplaceDomainValue(check, value)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.assert_domain_check"),
+			Exprs: tree.Exprs{expr, check, domainName, tree.NewDString(c.Name)},
+		}
+	}
+
+	texpr, err := tree.TypeCheck(b.ctx, expr, b.semaCtx, baseType)
+	if err != nil {
+		panic(err)
+	}
+	// The constraint expressions only reference the already-built input, so they
+	// are built in an empty scope.
+	out := b.buildScalar(texpr, b.allocScope(), nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+	return b.factory.ConstructCast(out, typ)
+}
@@ -100,6 +100,16 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping sets of a GROUP BY clause with
+	// GROUPING SETS, ROLLUP or CUBE, as sets of grouping columns. It is nil for
+	// a regular GROUP BY, or if the clause has a single grouping set.
+	groupingSets []opt.ColSet
+
+	// groupingSetIDCol is the extra grouping column that identifies the
+	// grouping set of each row. It is always the last grouping column in
+	// aggInScope, and is only set if groupingSets is set.
+	groupingSetIDCol opt.ColumnID
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -127,18 +137,37 @@ func (g *groupby) hasNonCommutativeAggregates() bool {
 	return false
 }
 
+// numGroupingCols returns the number of grouping columns, including the
+// grouping set ID column.
+func (g *groupby) numGroupingCols() int {
+	if g.groupingSetIDCol != 0 {
+		return len(g.groupStrs) + 1
+	}
+	return len(g.groupStrs)
+}
+
 // groupingCols returns the columns in the aggInScope corresponding to grouping
 // columns.
 func (g *groupby) groupingCols() []scopeColumn {
 	// Grouping cols are always clustered at the end of the column list.
-	return g.aggInScope.cols[len(g.aggInScope.cols)-len(g.groupStrs):]
+	return g.aggInScope.cols[len(g.aggInScope.cols)-g.numGroupingCols():]
+}
+
+// groupingColSet returns the set of grouping columns.
+func (g *groupby) groupingColSet() opt.ColSet {
+	var cols opt.ColSet
+	groupingCols := g.groupingCols()
+	for i := range groupingCols {
+		cols.Add(groupingCols[i].id)
+	}
+	return cols
 }
 
 // getAggregateArgCols returns the columns in the aggInScope corresponding to
 // arguments to aggregate functions. If the aggregate has a filter, the column
 // corresponding to the filter's input will immediately follow the arguments.
 func (g *groupby) aggregateArgCols() []scopeColumn {
-	return g.aggInScope.cols[:len(g.aggInScope.cols)-len(g.groupStrs)]
+	return g.aggInScope.cols[:len(g.aggInScope.cols)-g.numGroupingCols()]
 }
 
 // getAggregateResultCols returns the columns in the aggOutScope corresponding
@@ -290,7 +319,12 @@ func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 }
 
 func (b *Builder) constructGroupBy(
-	input memo.RelExpr, groupingColSet opt.ColSet, aggCols []scopeColumn, ordering opt.Ordering,
+	input memo.RelExpr,
+	groupingColSet opt.ColSet,
+	aggCols []scopeColumn,
+	ordering opt.Ordering,
+	groupingSets []opt.ColSet,
+	groupingSetIDCol opt.ColumnID,
 ) memo.RelExpr {
 	aggs := make(memo.AggregationsExpr, 0, len(aggCols))
 
@@ -309,7 +343,11 @@ func (b *Builder) constructGroupBy(
 		}
 	}
 
-	private := memo.GroupingPrivate{GroupingCols: groupingColSet}
+	private := memo.GroupingPrivate{
+		GroupingCols:     groupingColSet,
+		GroupingSets:     groupingSets,
+		GroupingSetIDCol: groupingSetIDCol,
+	}
 
 	// The ordering of the GROUP BY is inherited from the input. This ordering is
 	// only useful for intra-group ordering (for order-sensitive aggregations like
@@ -354,6 +392,10 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil {
+			panic(unimplementedWithIssueDetailf(46280, "",
+				"ordered aggregate functions are not supported with grouping sets"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -435,6 +477,8 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		groupingColSet,
 		aggCols,
 		g.aggInScope.ordering,
+		g.groupingSets,
+		g.groupingSetIDCol,
 	)
 
 	// Wrap with having filter if it exists.
@@ -507,15 +551,176 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+// clause can expand to. It matches the limit in Postgres.
+const maxGroupingSets = 4096
+
+// hasGroupingSets returns true if the GROUP BY clause contains GROUPING SETS,
+// ROLLUP or CUBE.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := tree.StripParens(e).(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGroupingSets builds the grouping columns for a GROUP BY clause that
+// contains GROUPING SETS, ROLLUP or CUBE, and computes the grouping sets that
+// the clause expands to. As in Postgres, the grouping sets of the GROUP BY
+// items are combined by a cross product, so that, for example,
+//   GROUP BY a, ROLLUP (b, c)
+// is equivalent to
+//   GROUP BY GROUPING SETS ((a, b, c), (a, b), (a))
+//
+// If there are multiple grouping sets, an extra grouping column is added to
+// aggInScope which identifies the grouping set of each row.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) {
+	g := fromScope.groupby
+	sets := []opt.ColSet{{}}
+	for _, e := range groupBy {
+		itemSets := b.buildGroupingSetItem(e, selects, projectionsScope, fromScope)
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([]opt.ColSet, 0, len(sets)*len(itemSets))
+		for i := range sets {
+			for j := range itemSets {
+				product = append(product, sets[i].Union(itemSets[j]))
+			}
+		}
+		sets = product
+	}
+	if len(sets) == 1 {
+		// A single grouping set is equivalent to a regular GROUP BY.
+		return
+	}
+
+	// The grouping columns that are not part of a grouping set are NULL for the
+	// rows of that set, so they must not share a column with an aggregate
+	// argument (which must not be NULLed). Synthesize new columns for any
+	// grouping columns that are passed through from the input or that reuse an
+	// argument column.
+	var argCols opt.ColSet
+	for _, col := range g.aggregateArgCols() {
+		argCols.Add(col.id)
+	}
+	groupingCols := g.groupingCols()
+	oldCols := make(opt.ColList, len(groupingCols))
+	newCols := make(opt.ColList, len(groupingCols))
+	for i := range groupingCols {
+		col := &groupingCols[i]
+		oldCols[i] = col.id
+		if col.scalar == nil || argCols.Contains(col.id) {
+			scalar := col.scalar
+			if scalar == nil {
+				scalar = b.factory.ConstructVariable(col.id)
+			}
+			b.populateSynthesizedColumn(col, scalar)
+		}
+		newCols[i] = col.id
+	}
+	for i := range sets {
+		sets[i] = opt.TranslateColSetStrict(sets[i], oldCols, newCols)
+	}
+
+	idCol := b.synthesizeColumn(
+		g.aggInScope, scopeColName(""), types.Int, tree.DZero, b.factory.ConstructConstVal(tree.DZero, types.Int),
+	)
+	idCol.visibility = inaccessible
+	g.groupingSets = sets
+	g.groupingSetIDCol = idCol.id
+}
+
+// buildGroupingSetItem builds the grouping columns for a single item of a
+// GROUP BY clause with grouping sets, and returns the grouping sets that the
+// item expands to.
+func (b *Builder) buildGroupingSetItem(
+	e tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []opt.ColSet {
+	g := fromScope.groupby
+	gs, ok := tree.StripParens(e).(*tree.GroupingSet)
+	if !ok {
+		// A regular grouping expression (possibly a tuple of expressions, or the
+		// empty set "()") forms a single grouping set.
+		return []opt.ColSet{b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)}
+	}
+
+	switch gs.Kind {
+	case tree.Rollup:
+		// ROLLUP (e1, e2, ..., en) expands to the sets (e1, e2, ..., en),
+		// (e1, e2, ..., en-1), ..., (e1), ().
+		units := make([]opt.ColSet, len(gs.Exprs))
+		for i, expr := range gs.Exprs {
+			units[i] = b.buildGrouping(expr, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+		n := len(units)
+		sets := make([]opt.ColSet, n+1)
+		var prefix opt.ColSet
+		for i := 0; i <= n; i++ {
+			sets[n-i] = prefix
+			if i < n {
+				prefix = prefix.Union(units[i])
+			}
+		}
+		return sets
+
+	case tree.Cube:
+		// CUBE (e1, e2, ..., en) expands to all 2^n subsets of its elements,
+		// ordered like Postgres does: the leftmost element corresponds to the
+		// most significant bit of a descending counter.
+		if len(gs.Exprs) > 12 {
+			panic(errTooManyGroupingSets)
+		}
+		units := make([]opt.ColSet, len(gs.Exprs))
+		for i, expr := range gs.Exprs {
+			units[i] = b.buildGrouping(expr, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+		n := len(units)
+		sets := make([]opt.ColSet, 0, 1<<n)
+		for mask := (1 << n) - 1; mask >= 0; mask-- {
+			var set opt.ColSet
+			for i := range units {
+				if mask&(1<<(n-i-1)) != 0 {
+					set.UnionWith(units[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	default:
+		// GROUPING SETS (s1, s2, ..., sn) is the concatenation of the grouping
+		// sets of its elements, which can themselves be ROLLUP, CUBE or nested
+		// GROUPING SETS.
+		var sets []opt.ColSet
+		for _, expr := range gs.Exprs {
+			sets = append(sets, b.buildGroupingSetItem(expr, selects, projectionsScope, fromScope)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+	}
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the set of grouping columns that
+// correspond to the expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +734,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -627,7 +832,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -637,7 +843,68 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
+}
+
+// buildGroupingFunc builds the GROUPING function, which returns a bit mask
+// indicating which of its arguments are not part of the grouping set of the
+// current row. The rightmost argument corresponds to the least significant
+// bit. Each argument must match a GROUP BY expression, e.g.:
+//
+//   SELECT a, b, grouping(a, b) FROM t GROUP BY ROLLUP (a, b)
+//
+// The function is built as a CASE expression over the grouping set ID column
+// that maps each grouping set to its bit mask.
+func (b *Builder) buildGroupingFunc(
+	f *tree.FuncExpr, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	g := inScope.groupby
+	if g == nil || inScope.inAgg || g.buildingGroupingCols {
+		panic(errGroupingArgs)
+	}
+	if len(f.Exprs) > 31 {
+		panic(pgerror.New(pgcode.TooManyArguments,
+			"GROUPING must have fewer than 32 arguments"))
+	}
+	args := make([]opt.ColumnID, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+		if !ok {
+			panic(errGroupingArgs)
+		}
+		args[i] = col.id
+	}
+	mask := func(set opt.ColSet) opt.ScalarExpr {
+		var m int64
+		for i := range args {
+			m <<= 1
+			if !set.Contains(args[i]) {
+				m |= 1
+			}
+		}
+		return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(m)), types.Int)
+	}
+
+	if g.groupingSets == nil {
+		// All the grouping columns are part of the only grouping set.
+		return mask(g.groupingColSet())
+	}
+
+	if colRefs != nil {
+		colRefs.Add(g.groupingSetIDCol)
+	}
+	idCol := b.factory.ConstructVariable(g.groupingSetIDCol)
+	last := len(g.groupingSets) - 1
+	whens := make(memo.ScalarListExpr, last)
+	for i := 0; i < last; i++ {
+		whens[i] = b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			mask(g.groupingSets[i]),
+		)
+	}
+	return b.factory.ConstructCase(idCol, whens, mask(g.groupingSets[last]))
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -891,6 +1158,14 @@ func isSQLFn(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.SQLClass
 }
 
+var errTooManyGroupingSets = pgerror.Newf(pgcode.StatementTooComplex,
+	"too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+var errGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level",
+)
+
 func newGroupingError(name tree.Name) error {
 	return pgerror.Newf(pgcode.Grouping,
 		"column \"%s\" must appear in the GROUP BY clause or be used in an aggregate function",
@@ -903,6 +1178,10 @@ func newGroupingError(name tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The grouping columns are not all grouped on in every grouping set.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
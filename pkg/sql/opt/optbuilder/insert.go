@@ -215,6 +215,13 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	// Check if this table has already been mutated in another subquery.
 	b.checkMultipleMutations(tab, ins.OnConflict == nil /* simpleInsert */)
 
+	// Triggers are not supported for upserts, since it is not known whether
+	// each row is inserted or updated until the mutation is executed.
+	if ins.OnConflict != nil && !ins.OnConflict.DoNothing && tab.TriggerCount() > 0 {
+		panic(unimplemented.NewWithIssuef(28296,
+			"UPSERT and INSERT ... ON CONFLICT DO UPDATE are not supported on tables with triggers"))
+	}
+
 	var mb mutationBuilder
 	if ins.OnConflict != nil && ins.OnConflict.IsUpsertAlias() {
 		mb.init(b, "upsert", tab, alias)
@@ -554,6 +561,21 @@ func (mb *mutationBuilder) addTargetTableColsForInsert(maxCols int) {
 // buildInputForInsert constructs the memo group for the input expression and
 // constructs a new output scope containing that expression's output columns.
 func (mb *mutationBuilder) buildInputForInsert(inScope *scope, inputRows *tree.Select) {
+	// If this is the body of the function of an AFTER trigger, the input is
+	// correlated with the argument of the function, so it must be joined with
+	// the rows passed to the trigger. Clear the scope so that any nested
+	// statements are not joined with it as well.
+	argScope := mb.b.triggerArgScope
+	mb.b.triggerArgScope = nil
+	defer func() {
+		if argScope != nil {
+			mb.outScope.expr = mb.b.factory.ConstructInnerJoinApply(
+				argScope.expr, mb.outScope.expr, memo.TrueFilter, memo.EmptyJoinPrivate,
+			)
+			mb.outScope.expr = mb.b.constructProject(mb.outScope.expr, mb.outScope.cols)
+		}
+	}()
+
 	// Handle DEFAULT VALUES case by creating a single empty row as input.
 	if inputRows == nil {
 		mb.outScope = inScope.push()
@@ -655,6 +677,11 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// Invoke any BEFORE INSERT triggers, which can modify the new rows. This
+	// must happen before computed columns are added, since those may depend
+	// on the modified columns.
+	mb.buildBeforeTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
@@ -677,8 +704,12 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForInsert()
 
+	mb.buildExclusionChecksForInsert()
+
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -900,6 +931,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpsert()
 
+	mb.buildExclusionChecksForUpsert()
+
 	mb.buildFKChecksForUpsert()
 
 	private := mb.makeMutationPrivate(returning != nil)
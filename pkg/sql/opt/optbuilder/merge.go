@@ -0,0 +1,473 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// duplicateMergeErrText is error text used when a row is modified twice by a
+// MERGE statement.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for a MERGE statement. MERGE is executed by
+// the Upsert operator: the source rows are left-joined to the target table
+// using the ON condition, and a CASE expression determines which WHEN clause
+// applies to each joined row. For example:
+//
+//   CREATE TABLE abc (a INT PRIMARY KEY, b INT, c INT)
+//   MERGE INTO abc USING xyz ON a = x
+//   WHEN MATCHED AND z THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET b = y
+//   WHEN NOT MATCHED THEN INSERT VALUES (x, y, z)
+//
+// This would create an input expression similar to this SQL:
+//
+//   SELECT
+//     CASE WHEN action = 3 THEN x ELSE fetch_a END AS ins_a,
+//     CASE WHEN action = 3 THEN y ELSE fetch_b END AS ins_b,
+//     CASE WHEN action = 3 THEN z ELSE fetch_c END AS ins_c,
+//     fetch_a, fetch_b, fetch_c,
+//     CASE WHEN action = 2 THEN y ELSE fetch_b END AS upd_b,
+//     action = 1 AS del
+//   FROM (
+//     SELECT *,
+//       CASE
+//         WHEN fetch_a IS NOT NULL AND z THEN 1
+//         WHEN fetch_a IS NOT NULL THEN 2
+//         WHEN fetch_a IS NULL THEN 3
+//         ELSE 0
+//       END AS action
+//     FROM xyz LEFT JOIN abc AS fetch ON a = x
+//   )
+//   WHERE action != 0
+//
+// As with other upserts, the canary column (fetch_a in the example) is null
+// for the rows that must be inserted. The Upsert operator deletes instead of
+// updating the existing rows for which the delete column is true. The input is
+// wrapped in an EnsureUpsertDistinctOn operator on the primary key of the
+// target table, so that an error is raised if a target row is affected more
+// than once.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Existing rows
+	// are always read.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	for _, when := range merge.Whens {
+		switch when.Action {
+		case tree.MergeUpdate:
+			b.checkPrivilege(depName, tab, privilege.UPDATE)
+		case tree.MergeDelete:
+			b.checkPrivilege(depName, tab, privilege.DELETE)
+		case tree.MergeInsert:
+			b.checkPrivilege(depName, tab, privilege.INSERT)
+		}
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, false /* simpleInsert */)
+
+	// Triggers are not supported for MERGE, since it is not known whether each
+	// row is inserted, updated or deleted until the mutation is executed.
+	if tab.TriggerCount() > 0 {
+		panic(unimplemented.NewWithIssuef(28296,
+			"MERGE is not supported on tables with triggers"))
+	}
+
+	// WHEN clauses should reject aggregates, generators, etc.
+	scalarProps := &b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	b.semaCtx.Properties.Require("MERGE", tree.RejectSpecial)
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+
+	// Build the input expression that joins the source rows with the target
+	// table, and filter out the rows that are not affected by any WHEN clause.
+	sourceScope := mb.buildInputForMerge(inScope, merge.Table, merge.Source, merge.On)
+	actionColID := mb.addMergeActionCol(merge.Whens, sourceScope)
+
+	// Build the values of the WHEN clauses.
+	mb.addMergeCols(merge.Whens, actionColID, sourceScope)
+
+	// Add the default and computed columns of inserted rows. They are built
+	// before the default and computed columns of updated rows, so that the
+	// table column names can refer to the insert columns.
+	mb.setMergeColNames(mb.insertColIDs)
+	mb.addSynthesizedDefaultCols(
+		mb.insertColIDs,
+		false, /* includeOrdinary */
+		false, /* applyOnUpdate */
+	)
+	mb.addAssignmentCasts(mb.insertColIDs)
+	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
+	mb.addAssignmentCasts(mb.insertColIDs)
+
+	// Add the default and computed columns of updated rows.
+	newColIDs := make(opt.OptionalColList, len(mb.updateColIDs))
+	for i := range newColIDs {
+		newColIDs[i] = mb.updateColIDs[i]
+		if newColIDs[i] == 0 {
+			newColIDs[i] = mb.fetchColIDs[i]
+		}
+	}
+	mb.setMergeColNames(newColIDs)
+	mb.addSynthesizedColsForUpdate()
+
+	mb.buildUpsert(nil /* returning */)
+	return mb.outScope
+}
+
+// buildInputForMerge constructs a left join of the source of a MERGE statement
+// with the target table, using the ON condition as the join condition. All
+// columns of the target table are fetched, and the first not-null column of
+// the primary key is used as the canary column. It returns the scope of the
+// source, which is used to build the expressions of the WHEN NOT MATCHED
+// clauses.
+func (mb *mutationBuilder) buildInputForMerge(
+	inScope *scope, texpr tree.TableExpr, source tree.TableExpr, on tree.Expr,
+) (sourceScope *scope) {
+	var indexFlags *tree.IndexFlags
+	if target, ok := texpr.(*tree.AliasedTableExpr); ok && target.IndexFlags != nil {
+		indexFlags = target.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	mb.fetchScope = mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations:       true,
+			includeSystem:          true,
+			includeInverted:        false,
+			includeVirtualComputed: true,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+	)
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	sourceScope = mb.b.buildFromTables(tree.TableExprs{source}, noRowLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	mb.b.validateJoinTableNames(sourceScope, mb.fetchScope)
+
+	// We create a new scope so that fetchScope is not modified. It will be used
+	// later to build partial index predicate expressions, and we do not want
+	// ambiguities with column names in the source.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(sourceScope)
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+
+	filter := mb.b.buildScalar(
+		mb.outScope.resolveAndRequireType(on, types.Bool), mb.outScope, nil, nil, nil,
+	)
+	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
+		sourceScope.expr,
+		mb.fetchScope.expr,
+		memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(filter)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Record a not-null "canary" column. After the left-join, this will be null
+	// if the source row does not match any target row, or not null otherwise.
+	mb.canaryColID = mb.fetchColIDs[findNotNullIndexCol(mb.tab.Index(cat.PrimaryIndex))]
+	return sourceScope
+}
+
+// addMergeActionCol projects a column that contains the 1-based index of the
+// WHEN clause that applies to each row of the input, or 0 if the row is not
+// affected by the statement, either because no WHEN clause applies or because
+// the first one that applies is a DO NOTHING clause. The rows with an action of
+// 0 are filtered out, and an error is raised if any target row is affected by
+// several source rows. It returns the ID of the action column.
+func (mb *mutationBuilder) addMergeActionCol(
+	whens tree.MergeWhens, sourceScope *scope,
+) (actionColID opt.ColumnID) {
+	f := mb.b.factory
+	caseWhens := make(memo.ScalarListExpr, len(whens))
+	for i, when := range whens {
+		// The WHEN NOT MATCHED clauses can only refer to the source columns.
+		condScope := mb.outScope
+		cond := f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		if !when.Matched {
+			condScope = sourceScope
+			cond = f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		}
+		if when.Cond != nil {
+			texpr := condScope.resolveAndRequireType(when.Cond, types.Bool)
+			cond = f.ConstructAnd(cond, mb.b.buildScalar(texpr, condScope, nil, nil, nil))
+		}
+
+		action := 0
+		if when.Action != tree.MergeDoNothing {
+			action = i + 1
+		}
+		caseWhens[i] = f.ConstructWhen(cond, f.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int))
+	}
+	caseExpr := f.ConstructCase(memo.TrueSingleton, caseWhens, f.ConstructConstVal(tree.DZero, types.Int))
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	name := scopeColName("").WithMetadataName("merge_action")
+	actionColID = mb.b.synthesizeColumn(projectionsScope, name, types.Int, nil /* expr */, caseExpr).id
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Remove the rows that are not affected.
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructNe(f.ConstructVariable(actionColID), f.ConstructConstVal(tree.DZero, types.Int)),
+		)},
+	)
+
+	// Ensure that each target row is affected at most once. The source rows that
+	// do not match any target row have null primary key values, so they are
+	// never considered duplicates.
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primaryIndex.KeyColumnCount(); i < n; i++ {
+		pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+	}
+	mb.outScope.ordering = nil
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+	)
+	return actionColID
+}
+
+// addMergeCols projects the insert, update and delete columns of the Upsert
+// operator for the given WHEN clauses. For each table column, the insert and
+// update columns are CASE expressions that select the value of the WHEN clause
+// that applies to the row, or the existing value if none does. The existing
+// values are also used as the insert values of the matched rows, so that they
+// satisfy the NOT NULL constraints.
+func (mb *mutationBuilder) addMergeCols(
+	whens tree.MergeWhens, actionColID opt.ColumnID, sourceScope *scope,
+) {
+	f := mb.b.factory
+	numCols := mb.tab.ColumnCount()
+	updateWhens := make([]memo.ScalarListExpr, numCols)
+	insertWhens := make([]memo.ScalarListExpr, numCols)
+	var deleteCond opt.ScalarExpr
+	for i, when := range whens {
+		isAction := f.ConstructEq(
+			f.ConstructVariable(actionColID),
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+		)
+		switch when.Action {
+		case tree.MergeUpdate:
+			for ord, val := range mb.buildMergeUpdateValues(when.Exprs) {
+				if val != nil {
+					updateWhens[ord] = append(updateWhens[ord], f.ConstructWhen(isAction, val))
+				}
+			}
+
+		case tree.MergeInsert:
+			for ord, val := range mb.buildMergeInsertValues(when, sourceScope) {
+				if val != nil {
+					insertWhens[ord] = append(insertWhens[ord], f.ConstructWhen(isAction, val))
+				}
+			}
+
+		case tree.MergeDelete:
+			if deleteCond == nil {
+				deleteCond = isAction
+			} else {
+				deleteCond = f.ConstructOr(deleteCond, isAction)
+			}
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for ord := 0; ord < numCols; ord++ {
+		tabCol := mb.tab.Column(ord)
+		if updateWhens[ord] != nil {
+			caseExpr := f.ConstructCase(
+				memo.TrueSingleton, updateWhens[ord], f.ConstructVariable(mb.fetchColIDs[ord]),
+			)
+			name := scopeColName("").WithMetadataName(string(tabCol.ColName()) + "_new")
+			scopeCol := mb.b.synthesizeColumn(projectionsScope, name, tabCol.DatumType(), nil /* expr */, caseExpr)
+			mb.updateColIDs[ord] = scopeCol.id
+		}
+
+		if tabCol.Kind() != cat.Ordinary || tabCol.IsComputed() {
+			continue
+		}
+		if insertWhens[ord] == nil {
+			// There is no WHEN NOT MATCHED THEN INSERT clause.
+			mb.insertColIDs[ord] = mb.fetchColIDs[ord]
+			continue
+		}
+		caseExpr := f.ConstructCase(
+			memo.TrueSingleton, insertWhens[ord], f.ConstructVariable(mb.fetchColIDs[ord]),
+		)
+		name := scopeColName("").WithMetadataName(string(tabCol.ColName()) + "_ins")
+		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, tabCol.DatumType(), nil /* expr */, caseExpr)
+		mb.insertColIDs[ord] = scopeCol.id
+	}
+
+	if deleteCond != nil {
+		name := scopeColName("").WithMetadataName("merge_delete")
+		mb.deleteColID = mb.b.synthesizeColumn(projectionsScope, name, types.Bool, nil /* expr */, deleteCond).id
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildMergeUpdateValues builds the values that are assigned by the SET
+// expressions of a WHEN MATCHED THEN UPDATE clause. The values are indexed by
+// the ordinal of the table column; the value of a column that is not assigned
+// is nil.
+func (mb *mutationBuilder) buildMergeUpdateValues(exprs tree.UpdateExprs) []opt.ScalarExpr {
+	for _, set := range exprs {
+		if _, ok := set.Expr.(*tree.Subquery); ok && set.Tuple {
+			panic(unimplemented.Newf("merge-update-subquery",
+				"multiple-column sub-SELECT in a MERGE UPDATE clause is not supported"))
+		}
+	}
+
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	mb.addTargetColsForUpdate(exprs)
+
+	vals := make([]opt.ScalarExpr, mb.tab.ColumnCount())
+	addVal := func(expr tree.Expr, targetColID opt.ColumnID) {
+		ord := mb.tabID.ColumnOrdinal(targetColID)
+
+		// Allow right side of SET to be DEFAULT.
+		if _, ok := expr.(tree.DefaultVal); ok {
+			expr = mb.parseDefaultExpr(targetColID)
+		} else if targetCol := mb.tab.Column(ord); targetCol.IsGeneratedAlwaysAsIdentity() {
+			panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnUpdateError(string(targetCol.ColName())))
+		}
+		vals[ord] = mb.buildMergeValue(expr, ord, mb.outScope)
+	}
+
+	n := 0
+	for _, set := range exprs {
+		if t, ok := set.Expr.(*tree.Tuple); ok && set.Tuple {
+			for _, expr := range t.Exprs {
+				addVal(expr, mb.targetColList[n])
+				n++
+			}
+		} else {
+			addVal(set.Expr, mb.targetColList[n])
+			n++
+		}
+	}
+	return vals
+}
+
+// buildMergeInsertValues builds the values that are inserted by a WHEN NOT
+// MATCHED THEN INSERT clause. The values are indexed by the ordinal of the
+// table column. Default values are built for the columns that are not
+// specified; the value of a mutation or computed column is nil.
+func (mb *mutationBuilder) buildMergeInsertValues(
+	when *tree.MergeWhen, sourceScope *scope,
+) []opt.ScalarExpr {
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+
+	exprs := make([]tree.Expr, mb.tab.ColumnCount())
+	if when.Values != nil {
+		if len(when.Columns) != 0 {
+			mb.addTargetNamedColsForInsert(when.Columns)
+			mb.checkNumCols(len(mb.targetColList), len(when.Values))
+		} else {
+			mb.addTargetTableColsForInsert(len(when.Values))
+		}
+
+		for i, colID := range mb.targetColList {
+			ord := mb.tabID.ColumnOrdinal(colID)
+			if _, ok := when.Values[i].(tree.DefaultVal); ok {
+				continue
+			}
+			if col := mb.tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+				panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(col.ColName())))
+			}
+			exprs[ord] = when.Values[i]
+		}
+	}
+
+	vals := make([]opt.ScalarExpr, len(exprs))
+	for ord, expr := range exprs {
+		tabCol := mb.tab.Column(ord)
+		if tabCol.Kind() != cat.Ordinary || tabCol.IsComputed() {
+			continue
+		}
+		if expr == nil {
+			expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+		}
+		vals[ord] = mb.buildMergeValue(expr, ord, sourceScope)
+	}
+	return vals
+}
+
+// buildMergeValue builds a scalar expression for a value that is assigned to
+// the table column with the given ordinal, adding an assignment cast if the
+// type of the value is not the type of the column.
+func (mb *mutationBuilder) buildMergeValue(
+	expr tree.Expr, ord int, inScope *scope,
+) opt.ScalarExpr {
+	targetCol := mb.tab.Column(ord)
+	targetType := targetCol.DatumType()
+	texpr := inScope.resolveType(expr, targetType)
+	scalar := mb.b.buildScalar(texpr, inScope, nil, nil, nil)
+
+	if srcType := texpr.ResolvedType(); !srcType.Identical(targetType) {
+		if !tree.ValidCast(srcType, targetType, tree.CastContextAssignment) {
+			panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(targetCol.ColName())))
+		}
+		scalar = mb.b.factory.ConstructAssignmentCast(scalar, targetType)
+	}
+	return scalar
+}
+
+// setMergeColNames makes the given columns, which are indexed by the ordinal
+// of the table column, referenceable by the names of the table columns, and
+// makes all other columns anonymous. This allows default and computed column
+// expressions to refer to the correct columns.
+func (mb *mutationBuilder) setMergeColNames(colIDs opt.OptionalColList) {
+	for i := range mb.outScope.cols {
+		mb.outScope.cols[i].clearName()
+	}
+	for ord, colID := range colIDs {
+		if colID == 0 {
+			continue
+		}
+		col := mb.outScope.getColumn(colID)
+		col.name = scopeColName(mb.tab.Column(ord).ColName()).WithMetadataName(col.name.MetadataName())
+	}
+}
@@ -132,6 +132,11 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// deleteColID is the ID of the boolean column that is used by an Upsert
+	// operator built for a MERGE statement to decide whether an existing row
+	// is deleted rather than updated. It is 0 if no row is ever deleted.
+	deleteColID opt.ColumnID
+
 	// arbiters is the set of indexes and unique constraints that are used to
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
@@ -178,10 +183,15 @@ type mutationBuilder struct {
 
 	// extraAccessibleCols stores all the columns that are available to the
 	// mutation that are not part of the target table. This is useful for
-	// UPDATE ... FROM queries, as the columns from the FROM tables must be
-	// made accessible to the RETURNING clause.
+	// UPDATE ... FROM and DELETE ... USING queries, as the columns from the
+	// FROM and USING tables must be made accessible to the RETURNING clause.
 	extraAccessibleCols []scopeColumn
 
+	// domainCheckedCols contains the IDs of the columns produced by
+	// addAssignmentCasts which have already been checked against the
+	// constraints of a domain, so that they are not checked again.
+	domainCheckedCols opt.ColSet
+
 	// fkCheckHelper is used to prevent allocating the helper separately.
 	fkCheckHelper fkCheckHelper
 
@@ -376,9 +386,18 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, the USING tables are joined with the target
+// table in the same way as the FROM tables of an UPDATE; see
+// buildInputForUpdate. A row of the target table is deleted at most once, even
+// if it matches several rows of the USING tables.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -407,7 +426,35 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		usingScope := mb.b.buildFromTables(using, noRowLocking, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.fetchScope, usingScope)
+
+		// The USING table columns can be accessed by the RETURNING clause of the
+		// query and so we have to make them accessible.
+		mb.extraAccessibleCols = usingScope.cols
+
+		// Add the columns in the USING scope. As with UPDATE ... FROM, we
+		// create a new scope so that fetchScope is not modified.
+		mb.outScope = mb.fetchScope.replace()
+		mb.outScope.appendColumnsFromScope(mb.fetchScope)
+		mb.outScope.appendColumnsFromScope(usingScope)
+
+		left := mb.fetchScope.expr
+		right := usingScope.expr
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
@@ -426,8 +473,22 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table.
+	if usingClausePresent {
+		var pkCols opt.ColSet
+
+		// We need to ensure that the join has a maximum of one row for every row
+		// in the table and we ensure this by constructing a distinct on the primary
+		// key columns, including any hidden ones.
+		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+			pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+		}
+
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -902,6 +963,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:           checkEmptyList(mb.fetchColIDs),
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
+		DeleteCol:           mb.deleteColID,
 		ArbiterIndexes:      mb.arbiters.IndexOrdinals(),
 		ArbiterConstraints:  mb.arbiters.UniqueConstraintOrdinals(),
 		CheckCols:           checkEmptyList(mb.checkColIDs),
@@ -995,8 +1057,8 @@ func (mb *mutationBuilder) buildReturning(returning tree.ReturningExprs) {
 
 	// extraAccessibleCols contains all the columns that the RETURNING
 	// clause can refer to in addition to the table columns. This is useful for
-	// UPDATE ... FROM statements, where all columns from tables in the FROM clause
-	// are in scope for the RETURNING clause.
+	// UPDATE ... FROM and DELETE ... USING statements, where all columns from
+	// tables in the FROM and USING clauses are in scope for the RETURNING clause.
 	inScope.appendColumns(mb.extraAccessibleCols)
 
 	// Construct the Project operator that projects the RETURNING expressions.
@@ -1048,6 +1110,14 @@ func (mb *mutationBuilder) parseDefaultExpr(colID opt.ColumnID) tree.Expr {
 	col := mb.tab.Column(ord)
 	exprStr := col.DefaultExprStr()
 
+	// A column of a domain type without a default expression of its own uses
+	// the default expression of the domain, if any.
+	if typ := col.DatumType(); exprStr == "" && typ.IsDomain() {
+		if md := typ.TypeMeta.DomainData; md != nil && md.DefaultExpr != nil {
+			exprStr = *md.DefaultExpr
+		}
+	}
+
 	// If no default expression, return NULL or a default value.
 	if exprStr == "" {
 		if col.IsMutation() && !col.IsNullable() {
@@ -1224,7 +1294,8 @@ func resultsNeeded(r tree.ReturningClause) bool {
 
 // addAssignmentCasts builds a projection that wraps columns in srcCols with
 // assignment casts when necessary so that the resulting columns have types
-// identical to their target column types.
+// identical to their target column types. Values written to columns of a
+// domain type are additionally checked against the constraints of the domain.
 //
 // srcCols should be either insertColIDs, updateColIDs, or upsertColsIDs where
 // the length of srcCols is equal to the number of columns in the target table.
@@ -1246,8 +1317,10 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 		targetType := mb.tab.Column(ord).DatumType()
 
 		// An assignment cast is not necessary if the source and target types
-		// are identical.
-		if srcType.Identical(targetType) {
+		// are identical. Values written to a column of a domain type must still
+		// be checked against the constraints of the domain.
+		if srcType.Identical(targetType) &&
+			(!targetType.IsDomain() || mb.domainCheckedCols.Contains(colID)) {
 			continue
 		}
 
@@ -1260,6 +1333,9 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 		// Create the cast expression.
 		variable := mb.b.factory.ConstructVariable(colID)
 		cast := mb.b.factory.ConstructAssignmentCast(variable, targetType)
+		if targetType.IsDomain() {
+			cast = mb.b.buildDomainConstraints(cast, targetType)
+		}
 
 		// Lazily create the new scope.
 		if projectionScope == nil {
@@ -1278,6 +1354,9 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 
 		// Replace old source column with the new one.
 		srcCols[ord] = scopeCol.id
+		if targetType.IsDomain() {
+			mb.domainCheckedCols.Add(scopeCol.id)
+		}
 	}
 
 	if projectionScope != nil {
@@ -0,0 +1,220 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecksForInsert builds exclusion check queries for an insert.
+// These check queries are used to enforce EXCLUDE constraints.
+//
+// Note that, unlike unique constraints, exclusion constraints are never
+// arbiters of an INSERT ... ON CONFLICT statement, so a conflict always results
+// in an error.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	if mb.tab.ExclusionCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		mb.uniqueChecks = append(mb.uniqueChecks, mb.buildExclusionCheck(i))
+	}
+	telemetry.Inc(sqltelemetry.ExclusionChecksUseCounter)
+}
+
+// buildExclusionChecksForUpdate builds exclusion check queries for an update.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	if mb.tab.ExclusionCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		// The insertion check works for updates too since it simply checks that
+		// the newly inserted or updated rows do not conflict with any existing
+		// rows.
+		mb.uniqueChecks = append(mb.uniqueChecks, mb.buildExclusionCheck(i))
+	}
+	telemetry.Inc(sqltelemetry.ExclusionChecksUseCounter)
+}
+
+// buildExclusionChecksForUpsert builds exclusion check queries for an upsert.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpsert() {
+	// An upsert can insert new rows, so all constraints must be checked.
+	mb.buildExclusionChecksForInsert()
+}
+
+// exclusionColsUpdated returns true if any of the columns for an exclusion
+// constraint are being updated (according to updateColIDs). When the exclusion
+// constraint has a partial predicate, it also returns true if the predicate
+// references any of the columns being updated.
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.Exclusion(exclusionOrdinal)
+
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if ord := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+
+	if _, isPartial := ec.Predicate(); isPartial {
+		pred := parseExclusionConstraintPredicateExpr(ec)
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			ord := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[ord] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildExclusionCheck creates an exclusion check for rows which are added to
+// or updated in a table. The check is a self-join of the new rows with the
+// existing rows of the table (which include the new rows, since checks run
+// after the mutation) on the constraint's operators:
+//
+//   SELECT new.a, new.b, existing.a, existing.b
+//   FROM new JOIN tab AS existing
+//   ON new.a = existing.a AND new.b && existing.b AND new.pk != existing.pk
+//
+// Any row returned by the check is a violation of the constraint.
+func (mb *mutationBuilder) buildExclusionCheck(exclusionOrdinal int) memo.UniqueChecksItem {
+	f := mb.b.factory
+	ec := mb.tab.Exclusion(exclusionOrdinal)
+
+	// Reuse the table scan of the uniqueness checks; it ignores the keys implied
+	// by UNIQUE WITHOUT INDEX constraints, which may not hold yet.
+	h := &mb.uniqueCheckHelper
+	*h = uniqueCheckHelper{mb: mb}
+	scanScope, scanOrdinals := h.buildTableScan()
+	withScanScope, _ := mb.buildCheckInputScan(checkInputScanNewVals, scanOrdinals)
+
+	// Build the join filters:
+	//   (new_a op_a existing_a) AND (new_b op_b existing_b) AND ...
+	//
+	// Set the capacity to account for one condition for each column in the
+	// constraint, one condition to prevent rows from matching themselves, and,
+	// if the constraint is partial, the predicate on both sides of the join.
+	numFilters := ec.ColumnCount() + 1
+	_, isPartial := ec.Predicate()
+	if isPartial {
+		numFilters += 2
+	}
+	filters := make(memo.FiltersExpr, 0, numFilters)
+	ords := make([]int, ec.ColumnCount())
+	for i := range ords {
+		ords[i] = ec.ColumnOrdinal(mb.tab, i)
+		left := f.ConstructVariable(withScanScope.cols[ords[i]].id)
+		right := f.ConstructVariable(scanScope.cols[ords[i]].id)
+		var cmp opt.ScalarExpr
+		switch op := ec.Operator(i); op {
+		case tree.EQ:
+			cmp = f.ConstructEq(left, right)
+		case tree.Overlaps:
+			cmp = f.ConstructOverlaps(left, right)
+		default:
+			panic(errors.AssertionFailedf("unsupported exclusion constraint operator %s", op))
+		}
+		filters = append(filters, f.ConstructFiltersItem(cmp))
+	}
+
+	// If the exclusion constraint is partial, only rows that satisfy the
+	// predicate can conflict with each other, so filter both sides of the join
+	// by the predicate.
+	if isPartial {
+		pred := parseExclusionConstraintPredicateExpr(ec)
+
+		typedPred := withScanScope.resolveAndRequireType(pred, types.Bool)
+		withScanPred := mb.b.buildScalar(typedPred, withScanScope, nil, nil, nil)
+		filters = append(filters, f.ConstructFiltersItem(withScanPred))
+
+		typedPred = scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := mb.b.buildScalar(typedPred, scanScope, nil, nil, nil)
+		filters = append(filters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// Prevent rows from matching themselves by comparing their primary keys:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	for i, ok := primaryOrds.Next(0); ok; i, ok = primaryOrds.Next(i + 1) {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(withScanScope.cols[i].id),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	filters = append(filters, f.ConstructFiltersItem(pkFilter))
+
+	join := f.ConstructInnerJoin(withScanScope.expr, scanScope.expr, filters, memo.EmptyJoinPrivate)
+
+	// Collect the key columns that will be shown in the error message if there
+	// is a violation: the constraint columns of the new row followed by those of
+	// the existing row it conflicts with.
+	keyCols := make(opt.ColList, 0, 2*len(ords))
+	for _, ord := range ords {
+		keyCols = append(keyCols, withScanScope.cols[ord].id)
+	}
+	for _, ord := range ords {
+		keyCols = append(keyCols, scanScope.cols[ord].id)
+	}
+
+	project := f.ConstructProject(join, nil /* projections */, keyCols.ToSet())
+
+	return f.ConstructUniqueChecksItem(project, &memo.UniqueChecksItemPrivate{
+		Table:        mb.tabID,
+		CheckOrdinal: exclusionOrdinal,
+		KeyCols:      keyCols,
+		OpName:       mb.opName,
+		Exclusion:    true,
+	})
+}
+
+// parseExclusionConstraintPredicateExpr parses the predicate of the given
+// partial exclusion constraint.
+func parseExclusionConstraintPredicateExpr(ec cat.ExclusionConstraint) tree.Expr {
+	predStr, isPartial := ec.Predicate()
+	if !isPartial {
+		panic(errors.AssertionFailedf("exclusion constraint %q is not partial", ec.Name()))
+	}
+	expr, err := parser.ParseExpr(predStr)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -394,6 +396,18 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 	}
 
 	for i := 0; i < numInbound; i++ {
+		// An Upsert built for a MERGE statement can delete existing rows, which
+		// requires the same checks as a Delete.
+		if mb.deleteColID != 0 && h.initWithInboundFK(mb, i) {
+			if a := h.fk.DeleteReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+				panic(unimplemented.Newf("merge-delete-fk-action",
+					"MERGE with DELETE is not supported on tables referenced by a foreign key with ON DELETE %s",
+					a))
+			}
+			deletedRows, deleteCols := mb.buildDeletedRowsForUpsert(h.tabOrdinals)
+			mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, deleteCols))
+		}
+
 		// Verify that at least one FK column is updated by the Upsert; columns that
 		// are not updated can get new values (through the insert path) but existing
 		// values are never removed.
@@ -463,6 +477,39 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
 
+// buildDeletedRowsForUpsert constructs an expression that produces the fetched
+// values of the given table columns for the existing rows that are deleted by
+// an Upsert operator built for a MERGE statement. It returns the expression
+// along with its output columns, which map 1-to-1 to tabOrdinals.
+func (mb *mutationBuilder) buildDeletedRowsForUpsert(
+	tabOrdinals []int,
+) (deletedRows memo.RelExpr, deleteCols opt.ColList) {
+	f := mb.b.factory
+	n := len(tabOrdinals)
+	inCols := make(opt.ColList, n+1)
+	outCols := make(opt.ColList, n+1)
+	for i, tabOrd := range tabOrdinals {
+		tableCol := mb.md.Table(mb.tabID).Column(tabOrd)
+		inCols[i] = mb.fetchColIDs[tabOrd]
+		outCols[i] = mb.md.AddColumn(string(tableCol.ColName()), tableCol.DatumType())
+	}
+	inCols[n] = mb.deleteColID
+	outCols[n] = mb.md.AddColumn("delete", types.Bool)
+
+	withScan := f.ConstructWithScan(&memo.WithScanPrivate{
+		With:    mb.withID,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      mb.md.NextUniqueID(),
+	})
+	deletedRows = f.ConstructSelect(
+		withScan,
+		memo.FiltersExpr{f.ConstructFiltersItem(f.ConstructVariable(outCols[n]))},
+	)
+	deleteCols = outCols[:n]
+	return f.ConstructProject(deletedRows, nil /* projections */, deleteCols.ToSet()), deleteCols
+}
+
 // outboundFKColsUpdated returns true if any of the FK columns for an outbound
 // constraint are being updated (according to updateColIDs).
 func (mb *mutationBuilder) outboundFKColsUpdated(fkOrdinal int) bool {
@@ -0,0 +1,372 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains methods that build the row-level triggers of the
+// mutated table.
+//
+// A trigger invokes a user-defined function which takes a single argument of
+// the implicit record type of the table. The row passed to the function has
+// an element for each visible column of the table.
+//
+// BEFORE triggers are invoked on each row as part of the mutation input:
+//
+//   - For INSERT and UPDATE, the function returns the row which is written
+//     in place of the new row. Computed columns are passed as NULL, and are
+//     computed from the returned row.
+//   - For DELETE, the function is invoked on the old row.
+//
+// In both cases the row is skipped if the function returns NULL.
+//
+// AFTER triggers are invoked once the mutation has been applied. They are
+// planned like FK cascades: the mutation input is buffered, and a query which
+// invokes the function on the new rows (or the old rows, for DELETE) is built
+// and executed after the original query. The function of an AFTER trigger
+// returns VOID and its body is an INSERT statement, which can be used to
+// maintain audit tables.
+
+// triggers returns the triggers on the mutated table that fire at the given
+// time for the given event. The triggers are ordered by name.
+func (mb *mutationBuilder) triggers(
+	actionTime tree.TriggerActionTime, event tree.TriggerEvent,
+) []cat.Trigger {
+	// Triggers are not invoked when the body of a function is built as part of
+	// its definition.
+	if mb.b.insideFuncDef {
+		return nil
+	}
+	var res []cat.Trigger
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		if t := mb.tab.Trigger(i); t.ActionTime == actionTime && t.Events.Contains(event) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// triggerRowOrdinals returns the ordinals of the table columns which make up
+// the row passed to trigger functions, in the order of the elements of the
+// implicit record type of the table.
+func triggerRowOrdinals(tab cat.Table) []int {
+	var ords []int
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		if col := tab.Column(i); col.Visibility() == cat.Visible && col.Kind() == cat.Ordinary {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// resolveTriggerFunction resolves the function invoked by the given trigger
+// on the given table, and checks that the current user can execute it.
+func (b *Builder) resolveTriggerFunction(
+	tab cat.Table, trigger *cat.Trigger,
+) (name string, o *tree.Overload) {
+	def, err := b.catalog.ResolveFunctionByID(b.ctx, trigger.FunctionID)
+	if err != nil {
+		panic(err)
+	}
+	o = def.Definition[0].(*tree.Overload)
+	if err := b.catalog.CheckFunctionPrivilege(b.ctx, trigger.FunctionID, privilege.EXECUTE); err != nil {
+		panic(err)
+	}
+	argTypes := o.Types.(tree.ArgTypes)
+	if len(argTypes) != 1 || argTypes[0].Typ.Family() != types.TupleFamily ||
+		len(argTypes[0].Typ.TupleContents()) != len(triggerRowOrdinals(tab)) {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"function %q of trigger %q must take a single argument of type %s",
+			def.Name, trigger.Name, tab.Name()))
+	}
+
+	// The body of the function may change, so the memo cannot be reused.
+	b.DisableMemoReuse = true
+	return def.Name, o
+}
+
+// buildBeforeTriggers invokes the BEFORE triggers which fire for the given
+// event on each row of the mutation input. For INSERT and UPDATE, the new
+// values of the columns are replaced with the row returned by the trigger
+// function. Rows for which the function returns NULL are filtered out.
+func (mb *mutationBuilder) buildBeforeTriggers(event tree.TriggerEvent) {
+	triggers := mb.triggers(tree.TriggerBefore, event)
+	if len(triggers) == 0 {
+		return
+	}
+	ords := triggerRowOrdinals(mb.tab)
+	for i := range triggers {
+		name, o := mb.b.resolveTriggerFunction(mb.tab, &triggers[i])
+		rowTyp := o.Types.(tree.ArgTypes)[0].Typ
+
+		// Build the row passed to the function. Computed columns have not yet
+		// been computed for new rows, so they are passed as NULL.
+		elems := make(memo.ScalarListExpr, len(ords))
+		for j, ord := range ords {
+			var colID opt.ColumnID
+			switch event {
+			case tree.TriggerEventInsert:
+				colID = mb.insertColIDs[ord]
+			case tree.TriggerEventUpdate:
+				colID = mb.updateColIDs[ord]
+				if colID == 0 {
+					colID = mb.fetchColIDs[ord]
+				}
+			case tree.TriggerEventDelete:
+				colID = mb.fetchColIDs[ord]
+			}
+			if colID == 0 || (event != tree.TriggerEventDelete && mb.tab.Column(ord).IsComputed()) {
+				elems[j] = mb.b.factory.ConstructNull(rowTyp.TupleContents()[j])
+			} else {
+				elems[j] = mb.b.factory.ConstructVariable(colID)
+			}
+		}
+		row := mb.b.factory.ConstructTuple(elems, rowTyp)
+
+		bodyScope, sel := mb.b.buildUDFBodyWithArgs(
+			name, o, memo.ScalarListExpr{row}, o.FixedReturnType(), false, /* expandTuple */
+		)
+		result := mb.b.factory.ConstructSubquery(bodyScope.expr, &memo.SubqueryPrivate{
+			OriginalExpr: &tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+		})
+
+		// Project the result of the function, and skip the rows for which it is
+		// NULL.
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		resultCol := mb.b.synthesizeColumn(
+			projectionsScope,
+			scopeColName("").WithMetadataName(string(triggers[i].Name)),
+			o.FixedReturnType(),
+			nil, /* expr */
+			result,
+		)
+		resultID := resultCol.id
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		projectionsScope.expr = mb.b.factory.ConstructSelect(
+			projectionsScope.expr,
+			memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(mb.b.factory.ConstructIsNot(
+				mb.b.factory.ConstructVariable(resultID), memo.NullSingleton,
+			))},
+		)
+		mb.outScope = projectionsScope
+		if event == tree.TriggerEventDelete {
+			continue
+		}
+
+		// Replace the new values of the non-computed columns with the elements
+		// of the returned row.
+		projectionsScope = mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		for j, ord := range ords {
+			tabCol := mb.tab.Column(ord)
+			if tabCol.IsComputed() {
+				continue
+			}
+			elem := mb.b.factory.ConstructColumnAccess(
+				mb.b.factory.ConstructVariable(resultID), memo.TupleOrdinal(j),
+			)
+			colName := scopeColName(tabCol.ColName()).WithMetadataName(
+				string(tabCol.ColName()) + "_new",
+			)
+			newCol := mb.b.synthesizeColumn(projectionsScope, colName, tabCol.DatumType(), nil /* expr */, elem)
+			if event == tree.TriggerEventInsert {
+				mb.insertColIDs[ord] = newCol.id
+			} else {
+				if mb.updateColIDs[ord] == 0 {
+					tabColID := mb.tabID.ColumnID(ord)
+					mb.targetColList = append(mb.targetColList, tabColID)
+					mb.targetColSet.Add(tabColID)
+				}
+				mb.updateColIDs[ord] = newCol.id
+			}
+		}
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+
+		// Disambiguate names so that references in the computed expressions
+		// refer to the values returned by the trigger.
+		mb.disambiguateColumns()
+	}
+}
+
+// buildAfterTriggers plans the AFTER triggers which fire for the given event
+// as post-queries of the mutation. See afterTriggerBuilder.
+//
+// Assumes that outScope.expr is the input to the mutation.
+func (mb *mutationBuilder) buildAfterTriggers(event tree.TriggerEvent) {
+	triggers := mb.triggers(tree.TriggerAfter, event)
+	if len(triggers) == 0 {
+		return
+	}
+	ords := triggerRowOrdinals(mb.tab)
+	for i := range triggers {
+		name, o := mb.b.resolveTriggerFunction(mb.tab, &triggers[i])
+		builder := &afterTriggerBuilder{
+			triggerName: string(triggers[i].Name),
+			funcName:    name,
+			overload:    o,
+		}
+		// Pass the final value of each column of the row which is available in
+		// the mutation input. Virtual computed columns may not be available, in
+		// which case they are passed as NULL.
+		var cols opt.ColList
+		for j, ord := range ords {
+			if colID := mb.mapToReturnColID(ord); colID != 0 {
+				builder.rowPositions = append(builder.rowPositions, j)
+				cols = append(cols, colID)
+			}
+		}
+
+		mb.ensureWithID()
+		cascade := memo.FKCascade{
+			FKName:  builder.triggerName,
+			Builder: builder,
+			WithID:  mb.withID,
+		}
+		if event == tree.TriggerEventDelete {
+			cascade.OldValues = cols
+		} else {
+			cascade.NewValues = cols
+		}
+		mb.cascades = append(mb.cascades, cascade)
+	}
+}
+
+// afterTriggerBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers.
+//
+// It provides a method to build the query which invokes the trigger function
+// on each row modified by the original mutation. The body of the function is
+// an INSERT statement whose input is correlated with the argument of the
+// function, so the query is equivalent to:
+//
+//   INSERT INTO audit
+//   SELECT ... FROM (SELECT (<cols>)::t AS r FROM original_mutation_input),
+//   LATERAL (<input of the INSERT in the function body>)
+//
+// For example:
+//
+//   insert audit
+//    └── project
+//         └── inner-join-apply
+//              ├── project
+//              │    ├── columns: r:8
+//              │    ├── with-scan &1
+//              │    │    ├── columns: a:6 b:7
+//              │    │    └── mapping:
+//              │    │         ├──  t.a:1 => a:6
+//              │    │         └──  t.b:2 => b:7
+//              │    └── projections
+//              │         └── ((a:6, b:7) AS a, b) [as=r:8]
+//              ├── values
+//              │    └── ((r:8).a, 'insert')
+//              └── filters (true)
+//
+type afterTriggerBuilder struct {
+	triggerName string
+	funcName    string
+	overload    *tree.Overload
+	// rowPositions are the positions in the row passed to the function of the
+	// columns which are passed by the mutation. The remaining elements of the
+	// row are NULL.
+	rowPositions []int
+}
+
+var _ memo.CascadeBuilder = &afterTriggerBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *afterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		values := newValues
+		if len(values) == 0 {
+			values = oldValues
+		}
+		if len(values) != len(tb.rowPositions) {
+			panic(errors.AssertionFailedf(
+				"expected %d columns for trigger %q, got %d", len(tb.rowPositions), tb.triggerName, len(values),
+			))
+		}
+
+		md := b.factory.Metadata()
+		outCols := make(opt.ColList, len(values))
+		for i := range outCols {
+			c := md.ColumnMeta(values[i])
+			outCols[i] = md.AddColumn(c.Alias, c.Type)
+		}
+
+		// Construct a dummy operator as the binding.
+		md.AddWithBinding(binding, b.factory.ConstructFakeRel(&memo.FakeRelPrivate{
+			Props: bindingProps,
+		}))
+		input := b.factory.ConstructWithScan(&memo.WithScanPrivate{
+			With:    binding,
+			InCols:  values,
+			OutCols: outCols,
+			ID:      md.NextUniqueID(),
+		})
+
+		rowTyp := tb.overload.Types.(tree.ArgTypes)[0].Typ
+		elems := make(memo.ScalarListExpr, len(rowTyp.TupleContents()))
+		for i, typ := range rowTyp.TupleContents() {
+			elems[i] = b.factory.ConstructNull(typ)
+		}
+		for i, pos := range tb.rowPositions {
+			elems[pos] = b.factory.ConstructVariable(outCols[i])
+		}
+		row := b.factory.ConstructTuple(elems, rowTyp)
+		argScope := b.buildUDFArgScope(tb.overload, input, memo.ScalarListExpr{row})
+
+		ins, ok := b.parseUDFBody(tb.funcName, tb.overload.UDF).(*tree.Insert)
+		if !ok {
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"function %q of AFTER trigger %q must have an INSERT body", tb.funcName, tb.triggerName))
+		}
+
+		// The input of the INSERT is joined with the argument of the function;
+		// see buildInputForInsert.
+		b.triggerArgScope = argScope
+		b.udfArgScope = argScope
+		outScope := b.buildUDFInsertBody(ins, argScope)
+		return outScope.expr
+	})
+}
+
+// buildUDFInsertBody builds an INSERT statement which is the body of a
+// user-defined function. The statement can reference the arguments of the
+// function, which are the columns of argScope.
+func (b *Builder) buildUDFInsertBody(ins *tree.Insert, argScope *scope) (outScope *scope) {
+	return b.processWiths(ins.With, argScope, func(inScope *scope) *scope {
+		return b.buildInsert(ins, inScope)
+	})
+}
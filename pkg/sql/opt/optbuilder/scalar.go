@@ -254,7 +254,11 @@ func (b *Builder) buildScalar(
 	case *tree.CastExpr:
 		texpr := t.Expr.(tree.TypedExpr)
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
-		out = b.factory.ConstructCast(arg, t.ResolvedType())
+		if typ := t.ResolvedType(); typ.IsDomain() {
+			out = b.buildDomainConstraints(arg, typ)
+		} else {
+			out = b.factory.ConstructCast(arg, typ)
+		}
 
 	case *tree.CoalesceExpr:
 		args := make(memo.ScalarListExpr, len(t.Exprs))
@@ -397,6 +401,9 @@ func (b *Builder) buildScalar(
 		}
 		out = b.factory.ConstructTuple(els, t.ResolvedType())
 
+	case *domainValue:
+		out = t.scalar
+
 	case *subquery:
 		out, _ = b.buildSingleRowSubquery(t, inScope)
 		// Perform correctness checks on the outer cols, update colRefs and
@@ -502,6 +509,15 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if f.ResolvedOverload().UDF != nil {
+		return b.buildUDF(f, def, inScope, outScope, outCol, colRefs)
+	}
+
+	if def.Name == "grouping" {
+		out = b.buildGroupingFunc(f, inScope, colRefs)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -720,6 +736,8 @@ func (b *Builder) constructComparison(
 			return b.factory.ConstructBBoxIntersects(left, right)
 		}
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp.Operator)))
 }
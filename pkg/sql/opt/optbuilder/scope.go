@@ -1011,10 +1011,8 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
-		if err != nil {
-			panic(err)
-		}
+		t, def := s.builder.resolveFunction(t)
+		expr = t
 
 		if isGenerator(def) && s.replaceSRFs {
 			expr = s.replaceSRF(t, def)
@@ -1036,6 +1034,16 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.Placeholder:
+		// Placeholders in the body of a user-defined function refer to its
+		// arguments.
+		if argScope := s.builder.udfArgScope; argScope != nil {
+			if int(t.Idx) >= len(argScope.cols) {
+				panic(pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter $%d", t.Idx+1))
+			}
+			return false, &argScope.cols[t.Idx]
+		}
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
@@ -103,6 +103,16 @@ func (b *Builder) buildZip(exprs tree.Exprs, inScope *scope) (outScope *scope) {
 			}
 		}
 
+		if def != nil && def.IsUDF {
+			// A call to a user-defined function is inlined as a relational
+			// expression, which cannot be zipped with other functions.
+			if len(exprs) > 1 {
+				panic(unimplemented.NewWithIssuef(17511,
+					"user-defined functions cannot be used in ROWS FROM with other functions"))
+			}
+			return b.buildUDFDataSource(texpr.(*tree.FuncExpr), def, alias, inScope)
+		}
+
 		var outCol *scopeColumn
 		startCols := len(outScope.cols)
 		if def == nil || def.Class != tree.GeneratorClass || b.shouldCreateDefaultColumn(texpr) {
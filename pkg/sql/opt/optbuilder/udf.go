@@ -0,0 +1,351 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// resolveFunction resolves the function called by the given expression.
+// Builtin functions take precedence over user-defined functions, which are
+// only looked up in the catalog if no builtin with the given name exists. If
+// a user-defined function is found, a copy of the expression which refers to
+// its definition is returned, so that type checking does not attempt to
+// resolve the name again.
+//
+// If the b.qualifyDataSourceNamesInAST flag is set, the name of a
+// user-defined function is updated in the AST to its fully qualified version,
+// so that the query of a view keeps calling the same function regardless of
+// the search path.
+func (b *Builder) resolveFunction(f *tree.FuncExpr) (*tree.FuncExpr, *tree.FunctionDefinition) {
+	def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+	if err == nil {
+		return f, def
+	}
+	name, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		panic(err)
+	}
+	udf, udfErr := b.catalog.ResolveFunction(b.ctx, name, b.semaCtx.SearchPath)
+	if udfErr != nil {
+		panic(udfErr)
+	}
+	if udf == nil {
+		panic(err)
+	}
+
+	// Functions do not track their dependencies on user-defined functions, so
+	// they cannot reference them.
+	if b.insideFuncDef {
+		panic(unimplemented.NewWithIssuef(17511,
+			"user-defined functions cannot be referenced in function bodies"))
+	}
+
+	// The body of the function may change, so the memo cannot be reused.
+	b.DisableMemoReuse = true
+
+	if b.qualifyDataSourceNamesInAST {
+		*name = *udf.QualifiedName.ToUnresolvedName()
+	}
+
+	resolved := *f
+	resolved.Func = tree.ResolvableFunctionReference{FunctionReference: udf}
+	return &resolved, udf
+}
+
+// buildUDF builds a call to a user-defined function by inlining its body. A
+// scalar function is built as a subquery which returns the first row produced
+// by the body. A set-returning function called in a projection is built as a
+// call to unnest over an ARRAY(...) subquery, so that it can take part in a
+// ProjectSet like any other set-returning function. Calls to set-returning
+// functions in the FROM clause are built by buildUDFDataSource instead.
+//
+// See Builder.buildStmt for a description of the remaining input and return
+// values.
+func (b *Builder) buildUDF(
+	f *tree.FuncExpr,
+	def *tree.FunctionDefinition,
+	inScope, outScope *scope,
+	outCol *scopeColumn,
+	colRefs *opt.ColSet,
+) (out opt.ScalarExpr) {
+	udf := f.ResolvedOverload().UDF
+	if !udf.ReturnSet {
+		bodyScope, sel := b.buildUDFBody(f, def, inScope, colRefs, false /* expandTuple */)
+		out = b.factory.ConstructSubquery(bodyScope.expr, &memo.SubqueryPrivate{
+			OriginalExpr: &tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+		})
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
+	typ := f.ResolvedType()
+	if typ.Family() == types.TupleFamily {
+		panic(unimplemented.NewWithIssuef(17511,
+			"set-returning function %s returning a composite type can only be called in the FROM clause",
+			def.Name))
+	}
+	bodyScope, sel := b.buildUDFBody(f, def, inScope, colRefs, false /* expandTuple */)
+	resultCol := bodyScope.cols[0].id
+	if err := types.CheckArrayElementType(typ); err != nil {
+		panic(err)
+	}
+	if !memo.AggregateOverloadExists(opt.ArrayAggOp, typ) {
+		panic(unimplemented.NewWithIssuef(17511,
+			"set-returning function %s returning %s can only be called in the FROM clause",
+			def.Name, typ))
+	}
+	arr := b.factory.ConstructArrayFlatten(bodyScope.expr, &memo.SubqueryPrivate{
+		OriginalExpr: &tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+		RequestedCol: resultCol,
+	})
+
+	props, overloads := builtins.GetBuiltinProperties("unnest")
+	var unnest *tree.Overload
+	for i := range overloads {
+		if overloads[i].Types.Length() == 1 {
+			unnest = &overloads[i]
+			break
+		}
+	}
+	if unnest == nil {
+		panic(errors.AssertionFailedf("could not find unnest overload"))
+	}
+	out = b.factory.ConstructFunction(memo.ScalarListExpr{arr}, &memo.FunctionPrivate{
+		Name:       "unnest",
+		Typ:        typ,
+		Properties: props,
+		Overload:   unnest,
+	})
+	return b.finishBuildGeneratorFunction(f, out, inScope, outScope, outCol)
+}
+
+// buildUDFDataSource builds a call to a user-defined function in the FROM
+// clause by inlining its body. If the function returns a composite type,
+// there is an output column for each of its elements; otherwise there is a
+// single output column with the given name.
+func (b *Builder) buildUDFDataSource(
+	f *tree.FuncExpr, def *tree.FunctionDefinition, colName string, inScope *scope,
+) (outScope *scope) {
+	bodyScope, _ := b.buildUDFBody(f, def, inScope, nil /* colRefs */, true /* expandTuple */)
+	outScope = inScope.push()
+	outScope.cols = bodyScope.cols
+	if f.ResolvedType().Family() != types.TupleFamily {
+		outScope.cols[0].name = scopeColName(tree.Name(colName))
+	}
+	outScope.expr = bodyScope.expr
+	if len(outScope.cols) == 1 {
+		outScope.singleSRFColumn = true
+	}
+	return outScope
+}
+
+// buildUDFBody builds the body of the user-defined function called by f as a
+// relational expression which is correlated with the arguments of the call.
+// The arguments are built in inScope. See buildUDFBodyWithArgs for more
+// details.
+func (b *Builder) buildUDFBody(
+	f *tree.FuncExpr,
+	def *tree.FunctionDefinition,
+	inScope *scope,
+	colRefs *opt.ColSet,
+	expandTuple bool,
+) (outScope *scope, sel *tree.Select) {
+	o := f.ResolvedOverload()
+	if err := b.catalog.CheckFunctionPrivilege(b.ctx, cat.StableID(o.UDF.ID), privilege.EXECUTE); err != nil {
+		panic(err)
+	}
+	if b.trackViewDeps {
+		b.viewFuncDeps.Add(int(o.UDF.ID))
+	}
+
+	// Build the arguments in the calling scope.
+	argTypes := o.Types.(tree.ArgTypes)
+	args := make(memo.ScalarListExpr, len(f.Exprs))
+	for i, pexpr := range f.Exprs {
+		texpr := pexpr.(tree.TypedExpr)
+		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
+		if typ := argTypes[i].Typ; !texpr.ResolvedType().Identical(typ) {
+			arg = b.factory.ConstructCast(arg, typ)
+		}
+		args[i] = arg
+	}
+	return b.buildUDFBodyWithArgs(def.Name, o, args, f.ResolvedType(), expandTuple)
+}
+
+// buildUDFBodyWithArgs builds the body of the given user-defined function
+// overload as a relational expression which is correlated with the given
+// arguments. The arguments are projected as columns which the body
+// references, either by name or with $n placeholders. If the function is
+// strict, the body produces no rows when any of the arguments is NULL.
+//
+// The returned scope has a single output column which holds the result of the
+// function, unless expandTuple is true and the function returns a composite
+// type, in which case there is an output column for each element of the
+// composite type. If the function does not return a set, the body is limited
+// to its first row.
+func (b *Builder) buildUDFBodyWithArgs(
+	name string, o *tree.Overload, args memo.ScalarListExpr, typ *types.T, expandTuple bool,
+) (outScope *scope, sel *tree.Select) {
+	udf := o.UDF
+	noColsRow := b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+		Cols: opt.ColList{},
+		ID:   b.factory.Metadata().NextUniqueID(),
+	})
+	argScope := b.buildUDFArgScope(o, noColsRow, args)
+
+	sel, ok := b.parseUDFBody(name, udf).(*tree.Select)
+	if !ok {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"function %q modifies data and can only be invoked by a trigger", name))
+	}
+
+	// Save and restore the state of the builder which is specific to the
+	// enclosing expression. The body is not a subquery of the caller; its
+	// only outer columns are the arguments, which are bound below.
+	// A view only depends on the functions it calls, and not on the objects
+	// referenced by their bodies, which the functions depend on themselves.
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+	outerSubquery, outerArgScope, outerTrackViewDeps := b.subquery, b.udfArgScope, b.trackViewDeps
+	defer func() {
+		b.subquery, b.udfArgScope, b.trackViewDeps = outerSubquery, outerArgScope, outerTrackViewDeps
+	}()
+	b.subquery, b.udfArgScope, b.trackViewDeps = nil, argScope, false
+
+	bodyScope := b.buildStmt(sel, nil /* desiredTypes */, argScope)
+	bodyScope.removeHiddenCols()
+	if !udf.ReturnSet {
+		one := b.factory.ConstructConstVal(tree.NewDInt(1), types.Int)
+		bodyScope.expr = b.factory.ConstructLimit(bodyScope.expr, one, bodyScope.makeOrderingChoice())
+	}
+
+	outScope = b.allocScope()
+	b.buildUDFResult(typ, name, bodyScope, outScope, expandTuple)
+
+	input := bodyScope.expr
+	if len(argScope.cols) > 0 {
+		input = b.factory.ConstructInnerJoinApply(
+			argScope.expr, input, memo.TrueFilter, memo.EmptyJoinPrivate,
+		)
+	}
+	outScope.expr = b.constructProject(input, outScope.cols)
+	return outScope, sel
+}
+
+// buildUDFArgScope returns a scope with a column for each of the given
+// arguments of a user-defined function, projected on top of input. The scope
+// has no parent, so that the body of the function cannot reference any
+// columns of the caller. If the function is strict, rows in which any of the
+// arguments is NULL are filtered out.
+func (b *Builder) buildUDFArgScope(
+	o *tree.Overload, input memo.RelExpr, args memo.ScalarListExpr,
+) *scope {
+	udf := o.UDF
+	argTypes := o.Types.(tree.ArgTypes)
+	argScope := b.allocScope()
+	for i := range args {
+		b.synthesizeColumn(argScope, scopeColName(tree.Name(udf.ArgNames[i])), argTypes[i].Typ, nil /* expr */, args[i])
+	}
+	argScope.expr = b.constructProject(input, argScope.cols)
+	if udf.Strict && len(argScope.cols) > 0 {
+		filters := make(memo.FiltersExpr, len(argScope.cols))
+		for i := range argScope.cols {
+			filters[i] = b.factory.ConstructFiltersItem(b.factory.ConstructIsNot(
+				b.factory.ConstructVariable(argScope.cols[i].id), memo.NullSingleton,
+			))
+		}
+		argScope.expr = b.factory.ConstructSelect(argScope.expr, filters)
+	}
+	return argScope
+}
+
+// parseUDFBody parses the body of the given user-defined function.
+func (b *Builder) parseUDFBody(name string, udf *tree.UDFOverload) tree.Statement {
+	stmt, err := parser.ParseOne(udf.Body)
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.Syntax,
+			"failed to parse body of function %q", name))
+	}
+	return stmt.AST
+}
+
+// buildUDFResult synthesizes the output columns of a user-defined function
+// in outScope from the columns produced by its body, casting them to the
+// return type of the function if necessary. See buildUDFBody for a
+// description of expandTuple.
+func (b *Builder) buildUDFResult(
+	typ *types.T, name string, bodyScope, outScope *scope, expandTuple bool,
+) {
+	cols := bodyScope.cols
+	mismatch := func() {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", typ.SQLString()))
+	}
+	variable := func(col *scopeColumn, t *types.T) opt.ScalarExpr {
+		v := b.factory.ConstructVariable(col.id)
+		if col.typ.Identical(t) {
+			return v
+		}
+		if !tree.ValidCast(col.typ, t, tree.CastContextAssignment) {
+			mismatch()
+		}
+		return b.factory.ConstructCast(v, t)
+	}
+	label := func(i int) scopeColumnName {
+		if labels := typ.TupleLabels(); i < len(labels) {
+			return scopeColName(tree.Name(labels[i]))
+		}
+		return scopeColName(tree.Name(name))
+	}
+
+	if typ.Family() != types.TupleFamily || (len(cols) == 1 && cols[0].typ.Family() == types.TupleFamily) {
+		if len(cols) != 1 {
+			mismatch()
+		}
+		result := variable(&cols[0], typ)
+		if !expandTuple || typ.Family() != types.TupleFamily {
+			b.synthesizeColumn(outScope, scopeColName(tree.Name(name)), typ, nil /* expr */, result)
+			return
+		}
+		for i, elemTyp := range typ.TupleContents() {
+			elem := b.factory.ConstructColumnAccess(result, memo.TupleOrdinal(i))
+			b.synthesizeColumn(outScope, label(i), elemTyp, nil /* expr */, elem)
+		}
+		return
+	}
+
+	// The body produces the elements of the composite type as separate columns.
+	contents := typ.TupleContents()
+	if len(cols) != len(contents) {
+		mismatch()
+	}
+	elems := make(memo.ScalarListExpr, len(cols))
+	for i := range cols {
+		elems[i] = variable(&cols[i], contents[i])
+	}
+	if !expandTuple {
+		b.synthesizeColumn(outScope, scopeColName(tree.Name(name)), typ, nil /* expr */, b.factory.ConstructTuple(elems, typ))
+		return
+	}
+	for i := range elems {
+		b.synthesizeColumn(outScope, label(i), contents[i], nil /* expr */, elems[i])
+	}
+}
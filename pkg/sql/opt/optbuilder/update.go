@@ -283,6 +283,9 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 	// Add assignment casts for update columns.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// Invoke any BEFORE UPDATE triggers, which can modify the new rows.
+	mb.buildBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Add additional columns for computed expressions that may depend on the
 	// updated columns.
 	mb.addSynthesizedColsForUpdate()
@@ -344,8 +347,12 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpdate()
 
+	mb.buildExclusionChecksForUpdate()
+
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -559,6 +559,12 @@ func (b *Builder) resolveTableForMutation(
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
+	// The body of a function can insert into a table, in which case the
+	// function depends on the table.
+	if b.trackViewDeps {
+		b.viewDeps = append(b.viewDeps, opt.ViewDep{DataSource: tab})
+	}
+
 	return tab, depName, alias, columns
 }
 
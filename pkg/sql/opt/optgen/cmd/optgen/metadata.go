@@ -201,6 +201,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Ordering":            {fullName: "opt.Ordering", passByVal: true},
 		"OrderingChoice":      {fullName: "props.OrderingChoice", passByVal: true},
 		"GroupingOrder":       {fullName: "memo.GroupingOrder", passByVal: true},
+		"GroupingSets":        {fullName: "memo.GroupingSets", passByVal: true},
 		"TupleOrdinal":        {fullName: "memo.TupleOrdinal", passByVal: true},
 		"ScanLimit":           {fullName: "memo.ScanLimit", passByVal: true},
 		"ScanFlags":           {fullName: "memo.ScanFlags", passByVal: true},
@@ -221,6 +222,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Statement":           {fullName: "tree.Statement", isInterface: true},
 		"Subquery":            {fullName: "tree.Subquery", isPointer: true, usePointerIntern: true},
 		"CreateTable":         {fullName: "tree.CreateTable", isPointer: true, usePointerIntern: true},
+		"CreateFunction":      {fullName: "tree.CreateFunction", isPointer: true, usePointerIntern: true},
 		"CreateStats":         {fullName: "tree.CreateStats", isPointer: true, usePointerIntern: true},
 		"TableName":           {fullName: "tree.TableName", isPointer: true, usePointerIntern: true},
 		"Constraint":          {fullName: "constraint.Constraint", isPointer: true, usePointerIntern: true},
@@ -242,6 +244,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"UniqueOrdinals":      {fullName: "cat.UniqueOrdinals", passByVal: true},
 		"ViewDeps":            {fullName: "opt.ViewDeps", passByVal: true},
 		"ViewTypeDeps":        {fullName: "opt.ViewTypeDeps", passByVal: true},
+		"ViewFuncDeps":        {fullName: "opt.ViewFuncDeps", passByVal: true},
 		"LockingItem":         {fullName: "tree.LockingItem", isPointer: true},
 		"MaterializeClause":   {fullName: "tree.MaterializeClause", passByVal: true},
 		"SpanExpression":      {fullName: "inverted.SpanExpression", isPointer: true, usePointerIntern: true},
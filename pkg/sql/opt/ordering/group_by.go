@@ -30,6 +30,11 @@ func groupByCanProvideOrdering(expr memo.RelExpr, required *props.OrderingChoice
 	// GroupBy may require a certain ordering of its input, but can also pass
 	// through a stronger ordering on the grouping columns.
 	groupBy := expr.(*memo.GroupByExpr)
+	if groupBy.HasGroupingSets() {
+		// The expansion of the input rows into grouping sets does not preserve
+		// any ordering on the grouping columns.
+		return required.Any()
+	}
 	return required.CanProjectCols(groupBy.GroupingCols) && required.Intersects(&groupBy.Ordering)
 }
 
@@ -103,6 +108,10 @@ func distinctOnBuildProvided(expr memo.RelExpr, required *props.OrderingChoice)
 func StreamingGroupingColOrdering(
 	g *memo.GroupingPrivate, required *props.OrderingChoice,
 ) opt.Ordering {
+	if g.HasGroupingSets() {
+		// Grouping sets are always computed by a hash aggregation.
+		return nil
+	}
 	inputOrdering := required.Intersection(&g.Ordering)
 	ordering := make(opt.Ordering, len(inputOrdering.Columns))
 	for i := range inputOrdering.Columns {
@@ -45,6 +45,10 @@ type ViewDep struct {
 // this view depends on.
 type ViewTypeDeps = util.FastIntSet
 
+// ViewFuncDeps contains a set of the IDs of user-defined functions that
+// this view depends on.
+type ViewFuncDeps = util.FastIntSet
+
 // GetColumnNames returns a sorted list of the names of the column dependencies
 // and a boolean to determine if the dependency was a table.
 // We only track column dependencies on tables.
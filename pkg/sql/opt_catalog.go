@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -37,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -261,6 +263,20 @@ func (oc *optCatalog) ResolveType(
 	return oc.planner.ResolveType(ctx, name)
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	return oc.planner.ResolveFunction(ctx, name, path)
+}
+
+// ResolveFunctionByID is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunctionByID(
+	ctx context.Context, id cat.StableID,
+) (*tree.FunctionDefinition, error) {
+	return oc.planner.ResolveFunctionByID(ctx, id)
+}
+
 func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, error) {
 	switch t := o.(type) {
 	case *optSchema:
@@ -302,6 +318,13 @@ func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv pri
 	return oc.planner.CheckPrivilege(ctx, desc, priv)
 }
 
+// CheckFunctionPrivilege is part of the cat.Catalog interface.
+func (oc *optCatalog) CheckFunctionPrivilege(
+	ctx context.Context, id cat.StableID, priv privilege.Kind,
+) error {
+	return oc.planner.CheckFunctionPrivilege(ctx, id, priv)
+}
+
 // CheckAnyPrivilege is part of the cat.Catalog interface.
 func (oc *optCatalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 	desc, err := getDescFromCatalogObjectForPermissions(o)
@@ -633,6 +656,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -641,6 +666,10 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers is the set of row-level triggers for this table, sorted by
+	// name, which is the order in which they fire.
+	triggers []cat.Trigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -764,12 +793,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability,
 		})
 	}
 
@@ -856,6 +886,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
@@ -870,6 +901,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
@@ -916,6 +948,41 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Add exclusion constraints.
+	if exclusions := desc.GetExclusionConstraints(); len(exclusions) > 0 {
+		ot.exclusionConstraints = make([]optExclusionConstraint, len(exclusions))
+		for i := range exclusions {
+			e := &exclusions[i]
+			ops := make([]tree.ComparisonOperatorSymbol, len(e.Operators))
+			for j := range ops {
+				op, err := e.Operator(j)
+				if err != nil {
+					return nil, err
+				}
+				ops[j] = op
+			}
+			ot.exclusionConstraints[i] = optExclusionConstraint{
+				name:      e.Name,
+				table:     ot.ID(),
+				columns:   e.ColumnIDs,
+				operators: ops,
+				predicate: e.Predicate,
+			}
+		}
+	}
+
+	// Add the triggers. Like in Postgres, triggers of the same kind fire in
+	// alphabetical order of their names.
+	if triggers := desc.GetTriggers(); len(triggers) > 0 {
+		ot.triggers = make([]cat.Trigger, len(triggers))
+		for i := range triggers {
+			ot.triggers[i] = mapTrigger(&triggers[i])
+		}
+		sort.Slice(ot.triggers, func(i, j int) bool {
+			return ot.triggers[i].Name < ot.triggers[j].Name
+		})
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1141,6 +1208,26 @@ func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return ot.triggers[i]
+}
+
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optTable) Exclusion(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1602,8 +1689,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1651,6 +1739,57 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[u.deferrability]
+}
+
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// exclusion constraint on a table.
+type optExclusionConstraint struct {
+	name string
+
+	table     cat.StableID
+	columns   []descpb.ColumnID
+	operators []tree.ComparisonOperatorSymbol
+	predicate string
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.columns)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(e.columns[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) tree.ComparisonOperatorSymbol {
+	return e.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Predicate() (string, bool) {
+	return e.predicate, e.predicate != ""
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1664,10 +1803,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction catpb.ForeignKeyAction
-	updateAction catpb.ForeignKeyAction
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  catpb.ForeignKeyAction
+	updateAction  catpb.ForeignKeyAction
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1739,6 +1879,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[fk.deferrability]
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2036,6 +2181,26 @@ func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionCount() int {
+	return 0
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optVirtualTable) Exclusion(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
 // CollectTypes is part of the cat.DataSource interface.
 func (ot *optVirtualTable) CollectTypes(ord int) (descpb.IDs, error) {
 	col := ot.desc.AllColumns()[ord]
@@ -2310,3 +2475,27 @@ func mapGeneratedAsIdentityType(inType catpb.GeneratedAsIdentityType) cat.Genera
 	}
 	return mapGeneratedAsIdentityType[inType]
 }
+
+// mapTrigger maps a descpb.TriggerDescriptor into the corresponding
+// cat.Trigger.
+func mapTrigger(t *descpb.TriggerDescriptor) cat.Trigger {
+	trigger := cat.Trigger{
+		Name:       tree.Name(t.Name),
+		FunctionID: cat.StableID(t.FunctionID),
+		Events:     make(tree.TriggerEvents, len(t.Events)),
+	}
+	if t.ActionTime == descpb.TriggerDescriptor_AFTER {
+		trigger.ActionTime = tree.TriggerAfter
+	}
+	for i, event := range t.Events {
+		switch event {
+		case descpb.TriggerDescriptor_INSERT:
+			trigger.Events[i] = tree.TriggerEventInsert
+		case descpb.TriggerDescriptor_UPDATE:
+			trigger.Events[i] = tree.TriggerEventUpdate
+		case descpb.TriggerDescriptor_DELETE:
+			trigger.Events[i] = tree.TriggerEventDelete
+		}
+	}
+	return trigger
+}
@@ -452,6 +452,8 @@ func (ef *execFactory) ConstructGroupBy(
 	aggregations []exec.AggInfo,
 	reqOrdering exec.OutputOrdering,
 	groupingOrderType exec.GroupingOrderType,
+	groupingSets []exec.NodeColumnOrdinalSet,
+	groupingSetIDCol exec.NodeColumnOrdinal,
 ) (exec.Node, error) {
 	inputPlan := input.(planNode)
 	inputCols := planColumns(inputPlan)
@@ -464,6 +466,8 @@ func (ef *execFactory) ConstructGroupBy(
 		groupCols:        convertNodeOrdinalsToInts(groupCols),
 		groupColOrdering: groupColOrdering,
 		isScalar:         false,
+		groupingSets:     groupingSets,
+		groupingSetIDCol: int(groupingSetIDCol),
 		reqOrdering:      ReqOrdering(reqOrdering),
 	}
 	for _, col := range n.groupCols {
@@ -1549,6 +1553,7 @@ func (ef *execFactory) ConstructUpsert(
 	arbiterIndexes cat.IndexOrdinals,
 	arbiterConstraints cat.UniqueOrdinals,
 	canaryCol exec.NodeColumnOrdinal,
+	deleteCol exec.NodeColumnOrdinal,
 	insertColOrdSet exec.TableColumnOrdinalSet,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	updateColOrdSet exec.TableColumnOrdinalSet,
@@ -1614,6 +1619,7 @@ func (ef *execFactory) ConstructUpsert(
 			tw: optTableUpserter{
 				ri:            ri,
 				canaryOrdinal: int(canaryCol),
+				deleteOrdinal: int(deleteCol),
 				fetchCols:     fetchCols,
 				updateCols:    updateCols,
 				ru:            ru,
@@ -1621,6 +1627,19 @@ func (ef *execFactory) ConstructUpsert(
 		},
 	}
 
+	// Create the table deleter if existing rows can be deleted, which is the
+	// case for MERGE statements with a WHEN MATCHED THEN DELETE clause.
+	if deleteCol != -1 {
+		ups.run.tw.rd = row.MakeDeleter(
+			ef.planner.ExecCfg().Codec,
+			tabDesc,
+			fetchCols,
+			&ef.planner.ExecCfg().Settings.SV,
+			internal,
+			ef.planner.ExecCfg().GetRowMetrics(internal),
+		)
+	}
+
 	// If rows are not needed, no columns are returned.
 	if rowsNeeded {
 		returnCols := makeColList(table, returnColOrdSet)
@@ -1655,6 +1674,7 @@ func (ef *execFactory) ConstructDelete(
 	table cat.Table,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	returnColOrdSet exec.TableColumnOrdinalSet,
+	passthrough colinfo.ResultColumns,
 	autoCommit bool,
 ) (exec.Node, error) {
 	// Derive table and column descriptors.
@@ -1686,7 +1706,8 @@ func (ef *execFactory) ConstructDelete(
 		source: input.(planNode),
 		run: deleteRun{
 			td:                        tableDeleter{rd: rd, alloc: ef.planner.alloc},
-			partialIndexDelValsOffset: len(rd.FetchCols),
+			partialIndexDelValsOffset: len(rd.FetchCols) + len(passthrough),
+			numPassthrough:            len(passthrough),
 		},
 	}
 
@@ -1696,6 +1717,8 @@ func (ef *execFactory) ConstructDelete(
 		// Delete returns the non-mutation columns specified, in the same
 		// order they are defined in the table.
 		del.columns = colinfo.ResultColumnsFromColumns(tabDesc.GetID(), returnCols)
+		// Add the passthrough columns to the returning columns.
+		del.columns = append(del.columns, passthrough...)
 
 		del.run.rowIdxToRetIdx = row.ColMapping(rd.FetchCols, returnCols)
 		del.run.rowsNeeded = true
@@ -1795,6 +1818,7 @@ func (ef *execFactory) ConstructCreateView(
 	columns colinfo.ResultColumns,
 	deps opt.ViewDeps,
 	typeDeps opt.ViewTypeDeps,
+	funcDeps opt.ViewFuncDeps,
 ) (exec.Node, error) {
 
 	if err := checkSchemaChangeEnabled(
@@ -1833,6 +1857,11 @@ func (ef *execFactory) ConstructCreateView(
 		typeDepSet[descpb.ID(id)] = struct{}{}
 	})
 
+	funcDepSet := make(funcDependencies, funcDeps.Len())
+	funcDeps.ForEach(func(id int) {
+		funcDepSet[descpb.ID(id)] = struct{}{}
+	})
+
 	return &createViewNode{
 		viewName:     viewName,
 		ifNotExists:  ifNotExists,
@@ -1844,6 +1873,50 @@ func (ef *execFactory) ConstructCreateView(
 		columns:      columns,
 		planDeps:     planDeps,
 		typeDeps:     typeDepSet,
+		funcDeps:     funcDepSet,
+	}, nil
+}
+
+// ConstructCreateFunction is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateFunction(
+	schema cat.Schema,
+	cf *tree.CreateFunction,
+	funcBody string,
+	deps opt.ViewDeps,
+	typeDeps opt.ViewTypeDeps,
+) (exec.Node, error) {
+
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	planDeps := make(planDependencies, len(deps))
+	for _, d := range deps {
+		desc, err := getDescForDataSource(d.DataSource)
+		if err != nil {
+			return nil, err
+		}
+		entry := planDeps[desc.GetID()]
+		entry.desc = desc
+		planDeps[desc.GetID()] = entry
+	}
+
+	typeDepSet := make(typeDependencies, typeDeps.Len())
+	typeDeps.ForEach(func(id int) {
+		typeDepSet[descpb.ID(id)] = struct{}{}
+	})
+
+	return &createFunctionNode{
+		cf:       cf,
+		funcBody: funcBody,
+		dbDesc:   schema.(*optSchema).database,
+		scDesc:   schema.(*optSchema).schema,
+		planDeps: planDeps,
+		typeDeps: typeDepSet,
 	}, nil
 }
 
@@ -1861,11 +1934,12 @@ func (ef *execFactory) ConstructSaveTable(
 
 // ConstructErrorIfRows is part of the exec.Factory interface.
 func (ef *execFactory) ConstructErrorIfRows(
-	input exec.Node, mkErr exec.MkErrFn,
+	input exec.Node, mkErr exec.MkErrFn, deferrable *exec.DeferrableCheck,
 ) (exec.Node, error) {
 	return &errorIfRowsNode{
-		plan:  input.(planNode),
-		mkErr: mkErr,
+		plan:       input.(planNode),
+		mkErr:      mkErr,
+		deferrable: deferrable,
 	}, nil
 }
 
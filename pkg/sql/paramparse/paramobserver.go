@@ -14,12 +14,17 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
+	"github.com/robfig/cron/v3"
 )
 
 // ApplyStorageParameters applies given storage parameters with the
@@ -68,7 +73,9 @@ type StorageParamObserver interface {
 }
 
 // TableStorageParamObserver observes storage parameters for tables.
-type TableStorageParamObserver struct{}
+type TableStorageParamObserver struct {
+	TableDesc *descpb.TableDescriptor
+}
 
 var _ StorageParamObserver = (*TableStorageParamObserver)(nil)
 
@@ -91,9 +98,39 @@ func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tr
 
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) RunPostChecks() error {
+	if ttl := a.TableDesc.RowLevelTTL; ttl != nil && ttl.DurationExpr == "" {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"ttl_expire_after" must be set if other TTL storage parameters are set`,
+		)
+	}
 	return nil
 }
 
+// rowLevelTTL returns the row-level TTL configuration of the table, creating
+// it if it is not yet set.
+func (a *TableStorageParamObserver) rowLevelTTL() *catpb.RowLevelTTL {
+	if a.TableDesc.RowLevelTTL == nil {
+		a.TableDesc.RowLevelTTL = &catpb.RowLevelTTL{}
+	}
+	return a.TableDesc.RowLevelTTL
+}
+
+// applyPositiveIntTTLStorageParam decodes a TTL storage parameter which must
+// be a positive integer.
+func applyPositiveIntTTLStorageParam(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) (int64, error) {
+	val, err := DatumAsInt(evalCtx, key, datum)
+	if err != nil {
+		return 0, err
+	}
+	if val <= 0 {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, "%q must be at least 1", key)
+	}
+	return val, nil
+}
+
 // Apply implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) Apply(
 	evalCtx *tree.EvalContext, key string, datum tree.Datum,
@@ -101,6 +138,70 @@ func (a *TableStorageParamObserver) Apply(
 	switch key {
 	case `fillfactor`:
 		return applyFillFactorStorageParam(evalCtx, key, datum)
+	case `ttl_expire_after`:
+		var d *tree.DInterval
+		if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
+			d, err = tree.ParseDInterval(evalCtx.GetIntervalStyle(), stringVal)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "value of %q must be an interval", key)
+			}
+		} else {
+			var ok bool
+			d, ok = datum.(*tree.DInterval)
+			if !ok {
+				return pgerror.Newf(pgcode.InvalidParameterValue, "value of %q must be an interval", key)
+			}
+		}
+		if d.Duration.Compare(duration.MakeDuration(0, 0, 0)) <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "value of %q must be greater than zero", key)
+		}
+		a.rowLevelTTL().DurationExpr = tree.Serialize(d)
+		return nil
+	case `ttl_select_batch_size`:
+		val, err := applyPositiveIntTTLStorageParam(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().SelectBatchSize = val
+		return nil
+	case `ttl_delete_batch_size`:
+		val, err := applyPositiveIntTTLStorageParam(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().DeleteBatchSize = val
+		return nil
+	case `ttl_range_concurrency`:
+		val, err := applyPositiveIntTTLStorageParam(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().RangeConcurrency = val
+		return nil
+	case `ttl_delete_rate_limit`:
+		val, err := applyPositiveIntTTLStorageParam(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().DeleteRateLimit = val
+		return nil
+	case `ttl_job_cron`:
+		str, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if _, err := cron.ParseStandard(str); err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid cron expression for %q", key)
+		}
+		a.rowLevelTTL().DeletionCron = str
+		return nil
+	case `ttl_pause`:
+		b, err := GetSingleBool(key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().Pause = bool(*b)
+		return nil
 	case `autovacuum_enabled`:
 		var boolVal bool
 		if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
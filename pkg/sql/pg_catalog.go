@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/commenter"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -773,7 +774,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -924,6 +924,7 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				f.FormatNode(descpb.ConstraintDeferrabilityType[con.UniqueWithoutIndexConstraint.Deferrability])
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -958,15 +959,56 @@ func populateTableConstraints(
 				validity = " NOT VALID"
 			}
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
+
+		case descpb.ConstraintTypeExclusion:
+			ec := con.ExclusionConstraint
+			oid = h.ExclusionConstraintOid(db.GetID(), scName, table.GetID(), ec)
+			contype = conTypeExclusion
+			if conkey, err = colIDArrayToDatum(ec.ColumnIDs); err != nil {
+				return err
+			}
+			colNames, err := table.NamesForColumnIDs(ec.ColumnIDs)
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			buf.WriteString("EXCLUDE ")
+			if ec.IndexMethod != "" {
+				fmt.Fprintf(&buf, "USING %s ", ec.IndexMethod)
+			}
+			buf.WriteByte('(')
+			for i := range colNames {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(&buf, "%s WITH %s", colNames[i], ec.Operators[i])
+			}
+			buf.WriteByte(')')
+			if ec.IsPartial() {
+				pred, err := schemaexpr.FormatExprForDisplay(ctx, table, ec.Predicate, p.SemaCtx(), p.SessionData(), tree.FmtPGCatalog)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(&buf, " WHERE (%s)", pred)
+			}
+			condef = tree.NewDString(buf.String())
 		}
 
+		deferrability := con.Deferrability()
+		condeferrable := tree.MakeDBool(
+			tree.DBool(deferrability != descpb.ConstraintDeferrability_NOT_DEFERRABLE),
+		)
+		condeferred := tree.MakeDBool(
+			tree.DBool(deferrability == descpb.ConstraintDeferrability_INITIALLY_DEFERRED),
+		)
+
 		if err := addRow(
 			oid,                  // oid
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -2661,9 +2703,7 @@ var (
 	typTypeRange     = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -2685,7 +2725,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
-	_ = typCategoryRange
 	_ = typCategoryBitString
 
 	typDelim = tree.NewDString(",")
@@ -2786,9 +2825,28 @@ func addPGTypeRow(
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 	}
+	if typ.Family() == types.RangeFamily {
+		typType = typTypeRange
+	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	typDefault := tree.DNull
+	if typ.IsDomain() {
+		// Domains use the I/O functions of their base type.
+		baseType := typ.DomainBaseType()
+		builtinPrefix = builtins.PGIOBuiltinPrefix(baseType)
+		typType = typTypeDomain
+		typBaseType = tree.NewDOid(tree.DInt(baseType.Oid()))
+		if md := typ.TypeMeta.DomainData; md != nil {
+			typNotNull = tree.MakeDBool(tree.DBool(md.NotNull))
+			if md.DefaultExpr != nil {
+				typDefault = tree.NewDString(*md.DefaultExpr)
+			}
+		}
+	}
 	typname := typ.PGName()
 
 	return addRow(
@@ -2818,13 +2876,13 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
 		tree.DNull,      // typdefaultbin
-		tree.DNull,      // typdefault
+		typDefault,      // typdefault
 		tree.DNull,      // typacl
 	)
 }
@@ -3071,12 +3129,36 @@ https://www.postgresql.org/docs/9.5/catalog-pg-shseclabel.html`,
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables of publications not created FOR ALL TABLES
+https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for i := range db.DatabaseDesc().Publications {
+					pub := &db.DatabaseDesc().Publications[i]
+					if pub.AllTables {
+						continue
+					}
+					tables, err := PublicationTables(ctx, p.txn, p.Descriptors(), db, pub)
+					if err != nil {
+						return err
+					}
+					pubOID := h.PublicationOid(db.GetID(), pub.Name)
+					for _, table := range tables {
+						if err := addRow(
+							h.publicationRelOid(pubOID, table.GetID()), // oid
+							pubOID,                  // prpubid
+							tableOid(table.GetID()), // prrelid
+						); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogConfigTable = virtualSchemaTable{
@@ -3161,12 +3243,37 @@ var pgCatalogTimezoneNamesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables of publications
+https://www.postgresql.org/docs/13/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for i := range db.DatabaseDesc().Publications {
+					pub := &db.DatabaseDesc().Publications[i]
+					tables, err := PublicationTables(ctx, p.txn, p.Descriptors(), db, pub)
+					if err != nil {
+						return err
+					}
+					for _, table := range tables {
+						sc, err := p.Descriptors().GetImmutableSchemaByID(
+							ctx, p.txn, table.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+						)
+						if err != nil {
+							return err
+						}
+						if err := addRow(
+							tree.NewDName(pub.Name),        // pubname
+							tree.NewDName(sc.GetName()),    // schemaname
+							tree.NewDName(table.GetName()), // tablename
+						); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogUserMappingsTable = virtualSchemaTable{
@@ -3241,12 +3348,33 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/13/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for i := range db.DatabaseDesc().Publications {
+					pub := &db.DatabaseDesc().Publications[i]
+					// All the changes are published, except for truncations.
+					if err := addRow(
+						tree.DBoolTrue,                            // pubupdate
+						h.PublicationOid(db.GetID(), pub.Name),    // oid
+						tree.MakeDBool(tree.DBool(pub.AllTables)), // puballtables
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolTrue,                            // pubinsert
+						tree.NewDName(pub.Name),                   // pubname
+						h.UserOid(pub.OwnerProto.Decode()),        // pubowner
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogGroupTable = virtualSchemaTable{
@@ -3430,12 +3558,39 @@ var pgCatalogLargeobjectTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
+	comment: `logical replication slots
+https://www.postgresql.org/docs/13/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+		// Like in Postgres, the replication slots of all the databases are
+		// listed.
+		return forEachDatabaseDesc(ctx, p, nil /* all databases */, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for i := range db.DatabaseDesc().ReplicationSlots {
+					slot := &db.DatabaseDesc().ReplicationSlots[i]
+					lsn := tree.NewDString(pgrepl.LSNFromTimestamp(slot.ConfirmedFlush).String())
+					if err := addRow(
+						tree.DNull,                  // safe_wal_size
+						tree.NewDString("reserved"), // wal_status
+						tree.NewDName(slot.Plugin),  // plugin
+						lsn,                         // restart_lsn
+						tree.DNull,                  // xmin
+						lsn,                         // confirmed_flush_lsn
+						tree.NewDName(db.GetName()), // database
+						dbOid(db.GetID()),           // datoid
+						tree.DBoolFalse,             // active
+						tree.DNull,                  // catalog_xmin
+						tree.NewDName(slot.Name),    // slot_name
+						tree.DNull,                  // active_pid
+						tree.NewDString("logical"),  // slot_type
+						tree.DBoolFalse,             // temporary
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogInitPrivsTable = virtualSchemaTable{
@@ -3957,6 +4112,9 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
 	types.VoidFamily:        typCategoryPseudo,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
+	types.RangeFamily:       typCategoryRange,
 }
 
 func typCategory(typ *types.T) tree.Datum {
@@ -4176,6 +4334,9 @@ const (
 	enumEntryTypeTag
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
+	exclusionConstraintTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4214,6 +4375,11 @@ func (h oidHasher) writeCheckConstraint(check *descpb.TableDescriptor_CheckConst
 	h.writeStr(check.Expr)
 }
 
+func (h oidHasher) writeExclusionConstraint(ec *descpb.ExclusionConstraint) {
+	h.writeUInt32(uint32(ec.TableID))
+	h.writeStr(ec.Name)
+}
+
 func (h oidHasher) writeForeignKeyConstraint(fk *descpb.ForeignKeyConstraint) {
 	h.writeUInt32(uint32(fk.ReferencedTableID))
 	h.writeStr(fk.Name)
@@ -4284,6 +4450,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scName string, tableID descpb.ID, ec *descpb.ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scName)
+	h.writeTable(tableID)
+	h.writeExclusionConstraint(ec)
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scName string, tableID descpb.ID, indexID descpb.IndexID,
 ) *tree.DOid {
@@ -4358,6 +4535,22 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+// PublicationOid creates an OID for the publication of the given database
+// with the given name.
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+func (h oidHasher) publicationRelOid(pubOID *tree.DOid, tableID descpb.ID) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeOID(pubOID)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(tree.DInt(id))
 }
@@ -241,6 +241,15 @@ func (c *conn) chooseDbRole(
 func (c *conn) findAuthenticationMethod(
 	authOpt authOptions,
 ) (tlsState tls.ConnectionState, hbaEntry *hba.Entry, methodFn AuthMethod, err error) {
+	if len(c.sessionArgs.SessionRevivalToken) > 0 {
+		// A session revival token takes precedence over the HBA configuration,
+		// since the session was already authenticated on another SQL pod.
+		methodFn = authSessionRevivalToken(c.sessionArgs.SessionRevivalToken)
+		c.sessionArgs.SessionRevivalToken = nil
+		hbaEntry = &sessionRevivalEntry
+		return
+	}
+
 	if authOpt.insecure {
 		// Insecure connections always use "trust" no matter what, and the
 		// remaining of the configuration is ignored.
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/identmap"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessionrevival"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -222,6 +223,35 @@ func authTrust(
 	return b, nil
 }
 
+// authSessionRevivalToken is the AuthMethod constructor for sessions that
+// are being re-established using a session revival token, e.g. by the SQL
+// proxy when migrating a connection to another SQL pod.
+func authSessionRevivalToken(token []byte) AuthMethod {
+	return func(
+		_ context.Context,
+		c AuthConn,
+		_ tls.ConnectionState,
+		execCfg *sql.ExecutorConfig,
+		_ *hba.Entry,
+		_ *identmap.Conf,
+	) (*AuthBehaviors, error) {
+		b := &AuthBehaviors{}
+		b.SetRoleMapper(UseProvidedIdentity)
+		b.SetAuthenticator(func(ctx context.Context, user security.SQLUsername, _ bool, _ PasswordRetrievalFn) error {
+			c.LogAuthInfof(ctx, "session revival token detected; attempting to use it")
+			if !sql.AllowSessionRevival.Get(&execCfg.Settings.SV) {
+				return errors.New("session revival tokens are not supported on this cluster")
+			}
+			cm, err := execCfg.RPCContext.SecurityContext.GetCertificateManager()
+			if err != nil {
+				return err
+			}
+			return sessionrevival.ValidateSessionRevivalToken(cm, user, token)
+		})
+		return b, nil
+	}
+}
+
 func authReject(
 	_ context.Context,
 	_ AuthConn,
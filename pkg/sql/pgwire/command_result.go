@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -59,6 +60,7 @@ type commandResult struct {
 	buffer struct {
 		notices            []pgnotice.Notice
 		paramStatusUpdates []paramStatusUpdate
+		notifications      []notify.Notification
 	}
 
 	err error
@@ -107,6 +109,7 @@ type paramStatusUpdate struct {
 }
 
 var _ sql.CommandResult = &commandResult{}
+var _ sql.RetriableCommandResult = &commandResult{}
 
 // Close is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
@@ -117,6 +120,13 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	}
 
 	r.conn.writerState.fi.registerCmd(r.pos)
+	// Notifications are sent even if the command failed, since they're
+	// removed from the session's queue when they're buffered on the result.
+	for _, n := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(n); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notification"))
+		}
+	}
 	if r.err != nil {
 		r.conn.bufferErr(ctx, r.err)
 		return
@@ -255,6 +265,11 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.NotificationSender interface.
+func (r *commandResult) BufferNotification(n notify.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, n)
+}
+
 // SetColumns is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
@@ -317,6 +332,23 @@ func (r *commandResult) ResetStmtType(stmt tree.Statement) {
 	r.cmdCompleteTag = stmt.StatementTag()
 }
 
+// ResetForStmtRetry is part of the sql.RetriableCommandResult interface.
+func (r *commandResult) ResetForStmtRetry(ctx context.Context) bool {
+	r.assertNotReleased()
+	cl := r.conn.LockCommunication()
+	defer cl.Close()
+	if cl.ClientPos() >= r.pos {
+		return false
+	}
+	cl.RTrim(ctx, r.pos)
+	r.err = nil
+	r.rowsAffected = 0
+	r.types = nil
+	// The notices of the failed attempt will be produced again by the retry.
+	r.buffer.notices = nil
+	return true
+}
+
 // release frees the commandResult and allows its memory to be reused.
 func (r *commandResult) release() {
 	*r = commandResult{released: true}
@@ -450,6 +482,14 @@ func (r *limitedCommandResult) SupportsAddBatch() bool {
 	return false
 }
 
+// ResetForStmtRetry is part of the sql.RetriableCommandResult interface.
+//
+// The rows produced by a portal are delivered to the client across several
+// Execute commands, so its statement can't be retried.
+func (r *limitedCommandResult) ResetForStmtRetry(context.Context) bool {
+	return false
+}
+
 // moreResultsNeeded is a restricted connection handler that waits for more
 // requests for rows from the active portal, during the "execute portal" flow
 // when a limit has been specified.
@@ -592,3 +632,82 @@ func (r *limitedCommandResult) rewindAndClosePortal(
 	r.conn.stmtBuf.Rewind(ctx, rewindTo)
 	return sql.ErrLimitedResultClosed
 }
+
+// copyOutResult is a commandResult for COPY TO statements. Its rows are sent in
+// CopyData messages, encoded in the format requested by the statement, instead
+// of DataRow messages. Closing the result sends a CopyDone message before the
+// CommandComplete message.
+type copyOutResult struct {
+	*commandResult
+	opts sql.CopyOutOptions
+
+	// sendBinaryHeader is set until the header of the binary format has been
+	// sent. Like Postgres, we send the header in the same CopyData message as
+	// the first row.
+	sendBinaryHeader bool
+
+	// scratch is used to encode the values of the text and CSV formats before
+	// they are escaped.
+	scratch writeBuffer
+}
+
+var _ sql.CopyOutResult = &copyOutResult{}
+
+// ResetForStmtRetry is part of the sql.RetriableCommandResult interface.
+//
+// The rows of COPY ... TO STDOUT are streamed to the client, so the statement
+// isn't retried.
+func (r *copyOutResult) ResetForStmtRetry(context.Context) bool {
+	return false
+}
+
+// SendCopyOut is part of the sql.CopyOutResult interface.
+func (r *copyOutResult) SendCopyOut(
+	ctx context.Context, cols colinfo.ResultColumns, opts sql.CopyOutOptions,
+) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.GetErr(); err != nil {
+		return err
+	}
+	r.opts = opts
+	r.sendBinaryHeader = opts.Format == tree.CopyFormatBinary
+	r.types = make([]*types.T, len(cols))
+	for i, col := range cols {
+		r.types[i] = col.Typ
+	}
+	format := pgwirebase.FormatText
+	if opts.Format == tree.CopyFormatBinary {
+		format = pgwirebase.FormatBinary
+	}
+	r.conn.bufferCopyOutResponse(len(cols), format)
+	return nil
+}
+
+// AddRow is part of the sql.CopyOutResult interface.
+func (r *copyOutResult) AddRow(ctx context.Context, row tree.Datums) error {
+	return r.addInternal(func() {
+		r.rowsAffected++
+		if r.opts.Format == tree.CopyFormatBinary {
+			r.conn.bufferCopyOutBinaryRow(ctx, row, r.location, r.types, r.sendBinaryHeader)
+			r.sendBinaryHeader = false
+		} else {
+			r.conn.bufferCopyOutTextRow(ctx, row, r.opts, r.conv, r.location, r.types, &r.scratch)
+		}
+	})
+}
+
+// Close is part of the sql.CopyOutResult interface.
+func (r *copyOutResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
+	r.assertNotReleased()
+	// If there was an error, the client only receives the ErrorResponse, which
+	// also terminates the Copy-out subprotocol.
+	if r.err == nil {
+		r.conn.writerState.fi.registerCmd(r.pos)
+		if r.opts.Format == tree.CopyFormatBinary {
+			r.conn.bufferCopyOutBinaryTrailer(r.sendBinaryHeader)
+		}
+		r.conn.bufferCopyDone()
+	}
+	r.commandResult.Close(ctx, t)
+}
@@ -27,10 +27,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
@@ -107,6 +109,10 @@ type conn struct {
 
 	// afterReadMsgTestingKnob is called after reading every message.
 	afterReadMsgTestingKnob func(context.Context) error
+
+	// replication is set while a START_REPLICATION command is being executed.
+	// It is only accessed by the network routine.
+	replication *replicationConn
 }
 
 // serveConn creates a conn that will serve the netConn. It returns once the
@@ -482,7 +488,12 @@ func (c *conn) serveImpl(
 				return false, isSimpleQuery, c.handleFlush(ctx)
 
 			case pgwirebase.ClientMsgCopyData, pgwirebase.ClientMsgCopyDone, pgwirebase.ClientMsgCopyFail:
-				// We're supposed to ignore these messages, per the protocol spec. This
+				// During logical replication, the client sends its status
+				// updates in CopyData messages.
+				if routed, err := c.routeReplicationMessage(ctx, typ); routed || err != nil {
+					return false, isSimpleQuery, err
+				}
+				// Otherwise, we're supposed to ignore these messages, per the protocol spec. This
 				// state will happen when an error occurs on the server-side during a copy
 				// operation: the server will send an error and a ready message back to
 				// the client, and must then ignore further copy messages. See:
@@ -687,6 +698,16 @@ func (c *conn) bufferNotice(ctx context.Context, noticeErr pgnotice.Notice) erro
 	return writeErrFields(ctx, c.sv, noticeErr, &c.msgBuilder, &c.writerState.buf)
 }
 
+func (c *conn) bufferNotification(n notify.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	// The process ID of the notifying backend. Like in BackendKeyData, we
+	// don't have a meaningful value to send.
+	c.msgBuilder.putInt32(0)
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context, sqlServer *sql.Server, onDefaultIntSizeChange func(newSize int32),
 ) (sql.ConnectionHandler, error) {
@@ -767,6 +788,17 @@ func (c *conn) handleSimpleQuery(
 	}
 
 	startParse := timeutil.Now()
+	if c.sessionArgs.Replication {
+		// Connections in replication mode accept the commands of the streaming
+		// replication protocol in addition to SQL.
+		stmt, ok, err := pgrepl.Parse(query)
+		if err != nil {
+			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+		}
+		if ok {
+			return c.handleReplicationCommand(ctx, query, stmt, timeReceived, startParse, timeutil.Now())
+		}
+	}
 	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
 	if err != nil {
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
@@ -808,6 +840,14 @@ func (c *conn) handleSimpleQuery(
 			copyDone.Wait()
 			return nil
 		}
+		// COPY TO does not read from the connection, so unlike COPY FROM it can
+		// be combined with other statements.
+		if cp, ok := stmts[i].AST.(*tree.CopyTo); ok {
+			if err := c.stmtBuf.Push(ctx, sql.CopyOut{Stmt: cp}); err != nil {
+				return err
+			}
+			continue
+		}
 
 		if err := c.stmtBuf.Push(
 			ctx,
@@ -912,6 +952,11 @@ func (c *conn) handleParse(
 		// https://www.postgresql.org/message-id/flat/CAMsr%2BYGvp2wRx9pPSxaKFdaObxX8DzWse%2BOkWk2xpXSvT0rq-g%40mail.gmail.com#CAMsr+YGvp2wRx9pPSxaKFdaObxX8DzWse+OkWk2xpXSvT0rq-g@mail.gmail.com
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyFrom not supported in extended protocol mode")})
 	}
+	if _, ok := stmt.AST.(*tree.CopyTo); ok {
+		// The CommandComplete message of COPY TO is preceded by the Copy-out
+		// subprotocol, which the execution of portals does not know about.
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyTo not supported in extended protocol mode")})
+	}
 
 	return c.stmtBuf.Push(
 		ctx,
@@ -1238,7 +1283,7 @@ func cookTag(
 		tag = append(tag, ' ')
 		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 
-	case tree.Rows:
+	case tree.Rows, tree.CopyOut:
 		tag = append(tag, ' ')
 		tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
 
@@ -1370,6 +1415,182 @@ func (c *conn) bufferCloseComplete() {
 	}
 }
 
+func (c *conn) bufferCopyOutResponse(numCols int, format pgwirebase.FormatCode) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(format))
+	c.msgBuilder.putInt16(int16(numCols))
+	for i := 0; i < numCols; i++ {
+		c.msgBuilder.putInt16(int16(format))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+	}
+}
+
+// bufferCopyOutTextRow serializes a row in the text or CSV format of COPY TO
+// and adds it to the buffer as a CopyData message. scratch is used to encode
+// each value before it is escaped or quoted.
+func (c *conn) bufferCopyOutTextRow(
+	ctx context.Context,
+	row tree.Datums,
+	opts sql.CopyOutOptions,
+	conv sessiondatapb.DataConversionConfig,
+	sessionLoc *time.Location,
+	types []*types.T,
+	scratch *writeBuffer,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	for i, col := range row {
+		if i > 0 {
+			c.msgBuilder.writeByte(opts.Delimiter)
+		}
+		if col == tree.DNull {
+			c.msgBuilder.writeString(opts.Null)
+			continue
+		}
+		scratch.reset()
+		writeTextDatumNotNull(scratch, col, conv, sessionLoc, types[i])
+		if scratch.err != nil {
+			c.msgBuilder.setError(scratch.err)
+			break
+		}
+		// Skip the length prefix.
+		val := scratch.wrapped.Bytes()[4:]
+		if opts.Format == tree.CopyFormatCSV {
+			writeCopyOutCSVValue(&c.msgBuilder, val, opts, len(row) == 1)
+		} else {
+			writeCopyOutTextValue(&c.msgBuilder, val, opts.Delimiter)
+		}
+	}
+	c.msgBuilder.writeByte('\n')
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+	}
+}
+
+// copyOutBinarySignature starts the header of the binary format of COPY TO.
+const copyOutBinarySignature = "PGCOPY\n\377\r\n\000"
+
+// writeCopyOutBinaryHeader writes the header of the binary format of COPY TO:
+// the signature, followed by the flags field and the length of the header
+// extension area, which are always zero.
+func writeCopyOutBinaryHeader(b *writeBuffer) {
+	b.writeString(copyOutBinarySignature)
+	b.putInt32(0)
+	b.putInt32(0)
+}
+
+// bufferCopyOutBinaryRow serializes a row in the binary format of COPY TO and
+// adds it to the buffer as a CopyData message, preceded by the header of the
+// format if withHeader is set.
+func (c *conn) bufferCopyOutBinaryRow(
+	ctx context.Context,
+	row tree.Datums,
+	sessionLoc *time.Location,
+	types []*types.T,
+	withHeader bool,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if withHeader {
+		writeCopyOutBinaryHeader(&c.msgBuilder)
+	}
+	c.msgBuilder.putInt16(int16(len(row)))
+	for i, col := range row {
+		c.msgBuilder.writeBinaryDatum(ctx, col, sessionLoc, types[i])
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+	}
+}
+
+// bufferCopyOutBinaryTrailer adds the trailer of the binary format of COPY TO
+// to the buffer as a CopyData message, preceded by the header of the format if
+// withHeader is set (i.e. if no rows were sent).
+func (c *conn) bufferCopyOutBinaryTrailer(withHeader bool) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if withHeader {
+		writeCopyOutBinaryHeader(&c.msgBuilder)
+	}
+	c.msgBuilder.putInt16(-1)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+	}
+}
+
+func (c *conn) bufferCopyDone() {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+	}
+}
+
+// writeCopyOutTextValue writes a value in the text format of COPY TO, escaping
+// backslashes, the delimiter and the control characters that have a backslash
+// sequence.
+func writeCopyOutTextValue(b *writeBuffer, val []byte, delimiter byte) {
+	start := 0
+	for i, ch := range val {
+		var esc byte
+		switch ch {
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		case '\\':
+			esc = '\\'
+		default:
+			if ch != delimiter {
+				continue
+			}
+			esc = ch
+		}
+		b.write(val[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.write(val[start:])
+}
+
+// writeCopyOutCSVValue writes a value in the CSV format of COPY TO. Like
+// Postgres, the value is quoted if it contains the delimiter, a quote or a
+// line break, or if it could be confused with NULL or the end-of-data marker.
+// singleCol indicates whether the value is the only one of its row.
+func writeCopyOutCSVValue(b *writeBuffer, val []byte, opts sql.CopyOutOptions, singleCol bool) {
+	quote := string(val) == opts.Null || (singleCol && string(val) == `\.`)
+	if !quote {
+		for _, ch := range val {
+			if ch == opts.Delimiter || ch == '"' || ch == '\n' || ch == '\r' {
+				quote = true
+				break
+			}
+		}
+	}
+	if !quote {
+		b.write(val)
+		return
+	}
+	b.writeByte('"')
+	start := 0
+	for i, ch := range val {
+		if ch == '"' {
+			b.write(val[start : i+1])
+			b.writeByte('"')
+			start = i + 1
+		}
+	}
+	b.write(val[start:])
+	b.writeByte('"')
+}
+
 func (c *conn) bufferCommandComplete(tag []byte) {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgCommandComplete)
 	c.msgBuilder.write(tag)
@@ -1627,6 +1848,16 @@ func (c *conn) CreateDrainResult(pos sql.CmdPos) sql.DrainResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateDeliverNotificationsResult is part of the sql.ClientComm interface.
+func (c *conn) CreateDeliverNotificationsResult(pos sql.CmdPos) sql.DeliverNotificationsResult {
+	return c.newMiscResult(pos, flush)
+}
+
+// CreateStartReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateStartReplicationResult(pos sql.CmdPos) sql.StartReplicationResult {
+	return c.newMiscResult(pos, noCompletionMsg)
+}
+
 // CreateBindResult is part of the sql.ClientComm interface.
 func (c *conn) CreateBindResult(pos sql.CmdPos) sql.BindResult {
 	return c.newMiscResult(pos, bindComplete)
@@ -1664,6 +1895,28 @@ func (c *conn) CreateCopyInResult(pos sql.CmdPos) sql.CopyInResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateCopyOutResult is part of the sql.ClientComm interface.
+func (c *conn) CreateCopyOutResult(
+	stmt *tree.CopyTo,
+	pos sql.CmdPos,
+	conv sessiondatapb.DataConversionConfig,
+	location *time.Location,
+) sql.CopyOutResult {
+	r := c.allocCommandResult()
+	*r = commandResult{
+		conn:           c,
+		conv:           conv,
+		location:       location,
+		pos:            pos,
+		typ:            commandComplete,
+		cmdCompleteTag: stmt.StatementTag(),
+		stmtType:       stmt.StatementReturnType(),
+	}
+	res := &copyOutResult{commandResult: r}
+	res.scratch.init(c.metrics.BytesOutCount)
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
@@ -1588,8 +1588,8 @@ func TestSetSessionArguments(t *testing.T) {
 
 	expectedOptions := map[string]string{
 		"search_path": "public, testsp",
-		// setting an isolation level is a noop:
-		// all transactions execute with serializable isolation.
+		// READ UNCOMMITTED is upgraded to READ COMMITTED, which runs as
+		// SERIALIZABLE while sql.txn.read_committed_isolation.enabled is off.
 		"default_transaction_isolation": "serializable",
 		"application_name":              "test",
 		"datestyle":                     "ISO, YMD",
@@ -1618,6 +1618,21 @@ func TestSetSessionArguments(t *testing.T) {
 	require.NoError(t, conn.QueryRow(ctx, "SHOW custom_option.custom_option").Scan(&customOption))
 	require.Equal(t, "test2", customOption)
 
+	// Once READ COMMITTED is enabled, it is the default isolation level of the
+	// session.
+	_, err = db.Exec("SET CLUSTER SETTING sql.txn.read_committed_isolation.enabled = true")
+	require.NoError(t, err)
+	testutils.SucceedsSoon(t, func() error {
+		var isolation string
+		if err := conn.QueryRow(ctx, "SHOW default_transaction_isolation").Scan(&isolation); err != nil {
+			return err
+		}
+		if isolation != "read committed" {
+			return errors.Errorf("expected read committed, got %s", isolation)
+		}
+		return nil
+	})
+
 	if err := conn.Close(ctx); err != nil {
 		t.Fatal(err)
 	}
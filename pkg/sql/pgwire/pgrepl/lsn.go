@@ -0,0 +1,71 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// LSN is a position in a replication stream, in the form of a Postgres
+// write-ahead log location.
+//
+// CockroachDB has no write-ahead log shared by all the ranges of a cluster, so
+// LSNs are derived from MVCC timestamps instead: the LSN of a timestamp is its
+// wall time in nanoseconds. The logical component of timestamps is dropped, so
+// all the changes committed at the same wall time have the same LSN and are
+// streamed as a single transaction.
+type LSN uint64
+
+// String formats the LSN the way Postgres does, as two hexadecimal numbers
+// separated by a slash.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// ParseLSN parses an LSN formatted by String.
+func ParseLSN(s string) (LSN, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN %q", s)
+	}
+	hi, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN %q", s)
+	}
+	lo, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN %q", s)
+	}
+	return LSN(hi<<32 | lo), nil
+}
+
+// LSNFromTimestamp returns the LSN of the changes committed at the given
+// timestamp.
+func LSNFromTimestamp(ts hlc.Timestamp) LSN {
+	return LSN(ts.WallTime)
+}
+
+// Timestamp returns the highest timestamp whose LSN is l. All the changes
+// committed at or before the returned timestamp have an LSN lower than or
+// equal to l.
+func (l LSN) Timestamp() hlc.Timestamp {
+	if l == 0 {
+		return hlc.Timestamp{}
+	}
+	return hlc.Timestamp{WallTime: int64(l), Logical: math.MaxInt32}
+}
@@ -0,0 +1,356 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Parse parses a command of the streaming replication protocol, as sent in a
+// simple query on a connection started in replication mode. The second return
+// value is false if the query is not a replication command, in which case it
+// is to be parsed as SQL.
+//
+// The grammar is the subset of the one of Postgres' walsender that applies to
+// logical replication:
+//
+//	IDENTIFY_SYSTEM
+//	CREATE_REPLICATION_SLOT slot_name [ TEMPORARY ] LOGICAL plugin
+//	  [ EXPORT_SNAPSHOT | NOEXPORT_SNAPSHOT | USE_SNAPSHOT ]
+//	DROP_REPLICATION_SLOT slot_name [ WAIT ]
+//	START_REPLICATION SLOT slot_name LOGICAL XXX/XXX
+//	  [ ( option_name [ 'option_value' ] [, ...] ) ]
+func Parse(sql string) (tree.Statement, bool, error) {
+	s := scanner{in: sql}
+	first, err := s.next()
+	if err != nil || first.kind != tokIdent || first.quoted {
+		// Let the SQL parser report the error, if any.
+		return nil, false, nil
+	}
+	var stmt tree.Statement
+	switch first.val {
+	case "identify_system":
+		stmt = &tree.IdentifySystem{}
+	case "create_replication_slot":
+		stmt, err = parseCreateReplicationSlot(&s)
+	case "drop_replication_slot":
+		stmt, err = parseDropReplicationSlot(&s)
+	case "start_replication":
+		stmt, err = parseStartReplication(&s)
+	case "base_backup", "timeline_history", "read_replication_slot":
+		return nil, true, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported", strings.ToUpper(first.val))
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	if err := s.expectEnd(); err != nil {
+		return nil, true, err
+	}
+	return stmt, true, nil
+}
+
+func parseCreateReplicationSlot(s *scanner) (tree.Statement, error) {
+	name, err := s.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.CreateReplicationSlot{Name: tree.Name(name)}
+	if s.peekKeyword("temporary") {
+		_, _ = s.next()
+		n.Temporary = true
+	}
+	if s.peekKeyword("physical") {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"physical replication is not supported")
+	}
+	if err := s.expectKeyword("logical"); err != nil {
+		return nil, err
+	}
+	plugin, err := s.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	n.Plugin = tree.Name(plugin)
+	for _, action := range []string{"export_snapshot", "noexport_snapshot", "use_snapshot"} {
+		if s.peekKeyword(action) {
+			_, _ = s.next()
+			n.SnapshotAction = strings.ToUpper(action)
+			break
+		}
+	}
+	return n, nil
+}
+
+func parseDropReplicationSlot(s *scanner) (tree.Statement, error) {
+	name, err := s.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.DropReplicationSlot{Name: tree.Name(name)}
+	if s.peekKeyword("wait") {
+		_, _ = s.next()
+		n.Wait = true
+	}
+	return n, nil
+}
+
+func parseStartReplication(s *scanner) (tree.Statement, error) {
+	if s.peekKeyword("physical") || s.peekLSN() {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"physical replication is not supported")
+	}
+	if err := s.expectKeyword("slot"); err != nil {
+		return nil, err
+	}
+	name, err := s.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	if s.peekKeyword("physical") || s.peekLSN() {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"physical replication is not supported")
+	}
+	if err := s.expectKeyword("logical"); err != nil {
+		return nil, err
+	}
+	tok, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokLSN {
+		return nil, tok.syntaxError()
+	}
+	lsn, err := ParseLSN(tok.val)
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.StartReplication{Slot: tree.Name(name), StartLSN: uint64(lsn)}
+	if s.peekKind(tokOpenParen) {
+		_, _ = s.next()
+		for {
+			key, err := s.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			opt := tree.KVOption{Key: tree.Name(key)}
+			if s.peekKind(tokString) {
+				tok, _ := s.next()
+				opt.Value = tree.NewStrVal(tok.val)
+			}
+			n.Options = append(n.Options, opt)
+			tok, err := s.next()
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokCloseParen {
+				break
+			}
+			if tok.kind != tokComma {
+				return nil, tok.syntaxError()
+			}
+		}
+	}
+	return n, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokLSN
+	tokOpenParen
+	tokCloseParen
+	tokComma
+	tokSemicolon
+)
+
+type token struct {
+	kind tokenKind
+	// val is the value of identifiers, string literals and LSNs. Unquoted
+	// identifiers are folded to lower case.
+	val    string
+	quoted bool
+}
+
+func (t token) syntaxError() error {
+	if t.kind == tokEOF {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of input")
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error at or near %q", t.val)
+}
+
+// scanner splits a replication command into tokens.
+type scanner struct {
+	in  string
+	pos int
+	// peeked is set if the next token was already scanned, along with
+	// peekedErr if scanning it failed.
+	peeked    *token
+	peekedErr error
+}
+
+func (s *scanner) next() (token, error) {
+	if s.peeked != nil {
+		t, err := *s.peeked, s.peekedErr
+		s.peeked, s.peekedErr = nil, nil
+		return t, err
+	}
+	return s.scan()
+}
+
+func (s *scanner) peek() token {
+	if s.peeked == nil {
+		// An error is reported when the token is consumed.
+		t, err := s.scan()
+		s.peeked, s.peekedErr = &t, err
+	}
+	return *s.peeked
+}
+
+func (s *scanner) peekKind(kind tokenKind) bool {
+	return s.peek().kind == kind
+}
+
+func (s *scanner) peekKeyword(kw string) bool {
+	t := s.peek()
+	return t.kind == tokIdent && !t.quoted && t.val == kw
+}
+
+func (s *scanner) peekLSN() bool {
+	return s.peekKind(tokLSN)
+}
+
+func (s *scanner) expectKeyword(kw string) error {
+	if !s.peekKeyword(kw) {
+		t, err := s.next()
+		if err != nil {
+			return err
+		}
+		return t.syntaxError()
+	}
+	_, _ = s.next()
+	return nil
+}
+
+func (s *scanner) expectIdent() (string, error) {
+	t, err := s.next()
+	if err != nil {
+		return "", err
+	}
+	if t.kind != tokIdent {
+		return "", t.syntaxError()
+	}
+	return t.val, nil
+}
+
+func (s *scanner) expectEnd() error {
+	t, err := s.next()
+	if err != nil {
+		return err
+	}
+	if t.kind == tokSemicolon {
+		if t, err = s.next(); err != nil {
+			return err
+		}
+	}
+	if t.kind != tokEOF {
+		return t.syntaxError()
+	}
+	return nil
+}
+
+func (s *scanner) scan() (token, error) {
+	for s.pos < len(s.in) && unicode.IsSpace(rune(s.in[s.pos])) {
+		s.pos++
+	}
+	if s.pos == len(s.in) {
+		return token{kind: tokEOF}, nil
+	}
+	switch c := s.in[s.pos]; {
+	case c == '(':
+		s.pos++
+		return token{kind: tokOpenParen, val: "("}, nil
+	case c == ')':
+		s.pos++
+		return token{kind: tokCloseParen, val: ")"}, nil
+	case c == ',':
+		s.pos++
+		return token{kind: tokComma, val: ","}, nil
+	case c == ';':
+		s.pos++
+		return token{kind: tokSemicolon, val: ";"}, nil
+	case c == '\'' || c == '"':
+		val, err := s.scanQuoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		if c == '"' {
+			return token{kind: tokIdent, val: val, quoted: true}, nil
+		}
+		return token{kind: tokString, val: val}, nil
+	case isIdentChar(c):
+		start := s.pos
+		for s.pos < len(s.in) && isIdentChar(s.in[s.pos]) {
+			s.pos++
+		}
+		if s.pos < len(s.in) && s.in[s.pos] == '/' {
+			// An LSN is two hexadecimal numbers separated by a slash.
+			s.pos++
+			for s.pos < len(s.in) && isIdentChar(s.in[s.pos]) {
+				s.pos++
+			}
+			return token{kind: tokLSN, val: s.in[start:s.pos]}, nil
+		}
+		return token{kind: tokIdent, val: strings.ToLower(s.in[start:s.pos])}, nil
+	default:
+		return token{}, pgerror.Newf(pgcode.Syntax, "syntax error at or near %q", string(c))
+	}
+}
+
+// scanQuoted scans a string literal or a quoted identifier. The quote
+// character is escaped by doubling it.
+func (s *scanner) scanQuoted(quote byte) (string, error) {
+	var b strings.Builder
+	s.pos++
+	for s.pos < len(s.in) {
+		c := s.in[s.pos]
+		s.pos++
+		if c != quote {
+			b.WriteByte(c)
+			continue
+		}
+		if s.pos < len(s.in) && s.in[s.pos] == quote {
+			b.WriteByte(quote)
+			s.pos++
+			continue
+		}
+		return b.String(), nil
+	}
+	if quote == '"' {
+		return "", pgerror.New(pgcode.Syntax, "unterminated quoted identifier")
+	}
+	return "", pgerror.New(pgcode.Syntax, "unterminated quoted string")
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9') || c >= 0x80
+}
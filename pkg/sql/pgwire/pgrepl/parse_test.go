@@ -0,0 +1,117 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		sql      string
+		expected string
+	}{
+		{`IDENTIFY_SYSTEM`, `IDENTIFY_SYSTEM`},
+		{`identify_system;`, `IDENTIFY_SYSTEM`},
+		{`CREATE_REPLICATION_SLOT s LOGICAL pgoutput`, `CREATE_REPLICATION_SLOT s LOGICAL pgoutput`},
+		{
+			`CREATE_REPLICATION_SLOT "Slot" TEMPORARY LOGICAL pgoutput NOEXPORT_SNAPSHOT`,
+			`CREATE_REPLICATION_SLOT "Slot" TEMPORARY LOGICAL pgoutput NOEXPORT_SNAPSHOT`,
+		},
+		{`DROP_REPLICATION_SLOT s`, `DROP_REPLICATION_SLOT s`},
+		{`DROP_REPLICATION_SLOT s WAIT`, `DROP_REPLICATION_SLOT s WAIT`},
+		{`START_REPLICATION SLOT s LOGICAL 0/0`, `START_REPLICATION SLOT s LOGICAL 0/0`},
+		{
+			`START_REPLICATION SLOT s LOGICAL 16/B374D848 ("proto_version" '1', "publication_names" 'a,b')`,
+			`START_REPLICATION SLOT s LOGICAL 16/B374D848 (proto_version '1', publication_names 'a,b')`,
+		},
+		{
+			`START_REPLICATION SLOT s LOGICAL 0/1 (messages)`,
+			`START_REPLICATION SLOT s LOGICAL 0/1 (messages)`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, ok, err := Parse(tc.sql)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, tree.AsString(stmt))
+		})
+	}
+}
+
+func TestParseSQL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, sql := range []string{
+		`SELECT 1`,
+		`"identify_system"`,
+		`CREATE PUBLICATION p`,
+		`'unterminated`,
+		``,
+	} {
+		_, ok, err := Parse(sql)
+		require.NoError(t, err)
+		require.False(t, ok, sql)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		sql      string
+		expected string
+	}{
+		{`IDENTIFY_SYSTEM x`, `syntax error at or near "x"`},
+		{`CREATE_REPLICATION_SLOT s PHYSICAL`, `physical replication is not supported`},
+		{`CREATE_REPLICATION_SLOT s LOGICAL`, `syntax error at end of input`},
+		{`CREATE_REPLICATION_SLOT 's' LOGICAL pgoutput`, `syntax error at or near "s"`},
+		{`START_REPLICATION 0/0`, `physical replication is not supported`},
+		{`START_REPLICATION SLOT s 0/0`, `physical replication is not supported`},
+		{`START_REPLICATION SLOT s LOGICAL 0/G`, `invalid LSN "0/G"`},
+		{`START_REPLICATION SLOT s LOGICAL 0/0 (a 'b'`, `syntax error at end of input`},
+		{`START_REPLICATION SLOT s LOGICAL 0/0 (a 'b)`, `unterminated quoted string`},
+		{`BASE_BACKUP`, `BASE_BACKUP is not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			_, ok, err := Parse(tc.sql)
+			require.True(t, ok)
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestLSN(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, s := range []string{"0/0", "16/B374D848", "FFFFFFFF/FFFFFFFF"} {
+		lsn, err := ParseLSN(s)
+		require.NoError(t, err)
+		require.Equal(t, s, lsn.String())
+	}
+	for _, s := range []string{"", "0", "0/0/0", "x/0", "100000000/0"} {
+		_, err := ParseLSN(s)
+		require.Error(t, err, s)
+	}
+
+	lsn := LSN(1234)
+	ts := lsn.Timestamp()
+	require.Equal(t, lsn, LSNFromTimestamp(ts))
+	require.Equal(t, lsn+1, LSNFromTimestamp(ts.Next()))
+}
@@ -0,0 +1,321 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/lib/pq/oid"
+)
+
+// PgoutputPlugin is the name of the only supported output plugin. Its messages
+// are described in
+// https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html.
+const PgoutputPlugin = "pgoutput"
+
+// PgoutputOptions are the options of the pgoutput plugin passed to
+// START_REPLICATION.
+type PgoutputOptions struct {
+	// Publications are the names of the publications whose tables are
+	// streamed.
+	Publications []string
+}
+
+// ParsePgoutputOptions validates the options of the pgoutput plugin. Only
+// version 1 of the protocol is supported, with changes streamed in text
+// format once they are committed.
+func ParsePgoutputOptions(opts tree.KVOptions) (PgoutputOptions, error) {
+	var res PgoutputOptions
+	var version string
+	for _, opt := range opts {
+		var val string
+		if opt.Value != nil {
+			val = opt.Value.(*tree.StrVal).RawString()
+		}
+		switch opt.Key {
+		case "proto_version":
+			version = val
+		case "publication_names":
+			names, err := splitIdentifiers(val)
+			if err != nil {
+				return PgoutputOptions{}, err
+			}
+			res.Publications = names
+		case "binary", "streaming", "two_phase":
+			if b, ok := parseBool(val); !ok || b {
+				return PgoutputOptions{}, pgerror.Newf(pgcode.FeatureNotSupported,
+					"option %q is not supported", opt.Key)
+			}
+		case "messages":
+			// There are no logical decoding messages to stream.
+		default:
+			return PgoutputOptions{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Key)
+		}
+	}
+	if version == "" {
+		return PgoutputOptions{}, pgerror.New(pgcode.InvalidParameterValue,
+			"proto_version option missing")
+	}
+	if version != "1" {
+		return PgoutputOptions{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"client sent proto_version=%s but we only support protocol 1", version)
+	}
+	if len(res.Publications) == 0 {
+		return PgoutputOptions{}, pgerror.New(pgcode.InvalidParameterValue,
+			"publication_names parameter missing")
+	}
+	return res, nil
+}
+
+// parseBool parses a boolean option value the way Postgres does. An option
+// without a value is true.
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "", "true", "on", "yes", "1":
+		return true, true
+	case "false", "off", "no", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// splitIdentifiers splits a comma-separated list of identifiers. Unquoted
+// identifiers are folded to lower case.
+func splitIdentifiers(s string) ([]string, error) {
+	sc := scanner{in: s}
+	var res []string
+	for {
+		t, err := sc.next()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tokIdent {
+			return nil, pgerror.New(pgcode.InvalidNameSyntax, "invalid publication_names syntax")
+		}
+		res = append(res, t.val)
+		if t, err = sc.next(); err != nil {
+			return nil, err
+		}
+		switch t.kind {
+		case tokEOF:
+			return res, nil
+		case tokComma:
+		default:
+			return nil, pgerror.New(pgcode.InvalidNameSyntax, "invalid publication_names syntax")
+		}
+	}
+}
+
+// Relation describes a table in a relation message.
+type Relation struct {
+	ID        uint32
+	Namespace string
+	Name      string
+	Columns   []RelationColumn
+}
+
+// RelationColumn describes a column of a Relation.
+type RelationColumn struct {
+	Name    string
+	TypeOID oid.Oid
+	TypeMod int32
+	// Key is set for the columns of the replica identity, which is the primary
+	// key.
+	Key bool
+}
+
+// Tuple holds the values of the columns of a row, in text format. A nil value
+// is NULL.
+type Tuple [][]byte
+
+// Message types of the pgoutput plugin and of the streaming replication
+// protocol.
+const (
+	msgBegin    = 'B'
+	msgCommit   = 'C'
+	msgRelation = 'R'
+	msgInsert   = 'I'
+	msgUpdate   = 'U'
+	msgDelete   = 'D'
+
+	msgXLogData         = 'w'
+	msgPrimaryKeepalive = 'k'
+	msgStandbyStatus    = 'r'
+)
+
+// AppendBegin appends a begin message to dst. finalLSN is the LSN of the
+// commit of the transaction.
+func AppendBegin(dst []byte, finalLSN LSN, commitTime time.Time, xid uint32) []byte {
+	dst = append(dst, msgBegin)
+	dst = appendUint64(dst, uint64(finalLSN))
+	dst = appendTime(dst, commitTime)
+	return appendUint32(dst, xid)
+}
+
+// AppendCommit appends a commit message to dst.
+func AppendCommit(dst []byte, commitLSN LSN, commitTime time.Time) []byte {
+	dst = append(dst, msgCommit, 0 /* flags */)
+	dst = appendUint64(dst, uint64(commitLSN))
+	// The end of the transaction in the WAL is the commit LSN: each
+	// transaction has its own LSN.
+	dst = appendUint64(dst, uint64(commitLSN))
+	return appendTime(dst, commitTime)
+}
+
+// AppendRelation appends a relation message to dst. A relation message is
+// sent before the first change to a table, and again when its schema changes.
+func AppendRelation(dst []byte, rel *Relation) []byte {
+	dst = append(dst, msgRelation)
+	dst = appendUint32(dst, rel.ID)
+	dst = appendString(dst, rel.Namespace)
+	dst = appendString(dst, rel.Name)
+	// The replica identity is the primary key.
+	dst = append(dst, 'd')
+	dst = appendUint16(dst, uint16(len(rel.Columns)))
+	for i := range rel.Columns {
+		col := &rel.Columns[i]
+		var flags byte
+		if col.Key {
+			flags = 1
+		}
+		dst = append(dst, flags)
+		dst = appendString(dst, col.Name)
+		dst = appendUint32(dst, uint32(col.TypeOID))
+		dst = appendUint32(dst, uint32(col.TypeMod))
+	}
+	return dst
+}
+
+// AppendInsert appends an insert message to dst.
+func AppendInsert(dst []byte, relID uint32, row Tuple) []byte {
+	dst = append(dst, msgInsert)
+	dst = appendUint32(dst, relID)
+	dst = append(dst, 'N')
+	return appendTuple(dst, row)
+}
+
+// AppendUpdate appends an update message to dst. The old values of the key
+// are not sent, since updates never change the primary key of a row: such
+// changes are streamed as a delete followed by an insert.
+func AppendUpdate(dst []byte, relID uint32, row Tuple) []byte {
+	dst = append(dst, msgUpdate)
+	dst = appendUint32(dst, relID)
+	dst = append(dst, 'N')
+	return appendTuple(dst, row)
+}
+
+// AppendDelete appends a delete message to dst. key holds the values of the
+// key columns; the values of the other columns are expected to be nil.
+func AppendDelete(dst []byte, relID uint32, key Tuple) []byte {
+	dst = append(dst, msgDelete)
+	dst = appendUint32(dst, relID)
+	dst = append(dst, 'K')
+	return appendTuple(dst, key)
+}
+
+// AppendXLogData appends the header of a message of the replication stream
+// carrying the given pgoutput message to dst.
+func AppendXLogData(dst []byte, start, end LSN, now time.Time, msg []byte) []byte {
+	dst = append(dst, msgXLogData)
+	dst = appendUint64(dst, uint64(start))
+	dst = appendUint64(dst, uint64(end))
+	dst = appendTime(dst, now)
+	return append(dst, msg...)
+}
+
+// AppendKeepalive appends a primary keepalive message to dst. If
+// replyRequested is set, the client is asked to reply with a standby status
+// update right away.
+func AppendKeepalive(dst []byte, end LSN, now time.Time, replyRequested bool) []byte {
+	dst = append(dst, msgPrimaryKeepalive)
+	dst = appendUint64(dst, uint64(end))
+	dst = appendTime(dst, now)
+	if replyRequested {
+		return append(dst, 1)
+	}
+	return append(dst, 0)
+}
+
+// StandbyStatus is a standby status update sent by the client.
+type StandbyStatus struct {
+	// Flushed is the position up to which the client has durably stored the
+	// changes, which is where streaming resumes after a reconnection.
+	Flushed LSN
+	// ReplyRequested is set if the client wants a keepalive right away.
+	ReplyRequested bool
+}
+
+// ParseClientMessage parses a message sent by the client in the replication
+// stream. The second return value is false for messages other than standby
+// status updates, which carry no information of use to the server.
+func ParseClientMessage(msg []byte) (StandbyStatus, bool, error) {
+	if len(msg) == 0 {
+		return StandbyStatus{}, false, pgerror.New(pgcode.ProtocolViolation,
+			"empty replication message")
+	}
+	if msg[0] != msgStandbyStatus {
+		return StandbyStatus{}, false, nil
+	}
+	// Written, flushed and applied positions, the client time and the reply
+	// flag.
+	if len(msg) != 1+8+8+8+8+1 {
+		return StandbyStatus{}, false, pgerror.New(pgcode.ProtocolViolation,
+			"invalid standby status update")
+	}
+	return StandbyStatus{
+		Flushed:        LSN(binary.BigEndian.Uint64(msg[9:])),
+		ReplyRequested: msg[33] != 0,
+	}, true, nil
+}
+
+func appendTuple(dst []byte, row Tuple) []byte {
+	dst = appendUint16(dst, uint16(len(row)))
+	for _, val := range row {
+		if val == nil {
+			dst = append(dst, 'n')
+			continue
+		}
+		dst = append(dst, 't')
+		dst = appendUint32(dst, uint32(len(val)))
+		dst = append(dst, val...)
+	}
+	return dst
+}
+
+// appendTime appends a timestamp, as the number of microseconds since the
+// Postgres epoch.
+func appendTime(dst []byte, t time.Time) []byte {
+	return appendUint64(dst, uint64(t.Sub(pgwirebase.PGEpochJDate).Microseconds()))
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, s...)
+	return append(dst, 0)
+}
+
+func appendUint16(dst []byte, v uint16) []byte {
+	return append(dst, byte(v>>8), byte(v))
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	return append(dst, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(dst []byte, v uint64) []byte {
+	return appendUint32(appendUint32(dst, uint32(v>>32)), uint32(v))
+}
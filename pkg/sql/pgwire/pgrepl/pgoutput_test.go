@@ -0,0 +1,126 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePgoutputOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	parse := func(sql string) (PgoutputOptions, error) {
+		stmt, ok, err := Parse(sql)
+		require.NoError(t, err)
+		require.True(t, ok)
+		return ParsePgoutputOptions(stmt.(*tree.StartReplication).Options)
+	}
+
+	opts, err := parse(`START_REPLICATION SLOT s LOGICAL 0/0 ` +
+		`(proto_version '1', publication_names 'a, "B",c', messages 'false')`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "B", "c"}, opts.Publications)
+
+	for _, tc := range []struct {
+		options  string
+		expected string
+	}{
+		{`publication_names 'a'`, `proto_version option missing`},
+		{`proto_version '2', publication_names 'a'`,
+			`client sent proto_version=2 but we only support protocol 1`},
+		{`proto_version '1'`, `publication_names parameter missing`},
+		{`proto_version '1', publication_names 'a,'`, `invalid publication_names syntax`},
+		{`proto_version '1', publication_names 'a', binary 'true'`, `option "binary" is not supported`},
+		{`proto_version '1', publication_names 'a', streaming`, `option "streaming" is not supported`},
+		{`proto_version '1', publication_names 'a', foo 'bar'`, `unrecognized pgoutput option: foo`},
+	} {
+		_, err := parse(`START_REPLICATION SLOT s LOGICAL 0/0 (` + tc.options + `)`)
+		require.EqualError(t, err, tc.expected, tc.options)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// One second after the Postgres epoch.
+	ts := time.Date(2000, 1, 1, 0, 0, 1, 0, time.UTC)
+	usecs := []byte{0, 0, 0, 0, 0, 0x0f, 0x42, 0x40}
+
+	require.Equal(t,
+		append(append([]byte{'B', 0, 0, 0, 1, 0, 0, 0, 2}, usecs...), 0, 0, 0, 7),
+		AppendBegin(nil, LSN(1<<32|2), ts, 7))
+	require.Equal(t,
+		append([]byte{'C', 0, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 3}, usecs...),
+		AppendCommit(nil, LSN(3), ts))
+
+	rel := &Relation{
+		ID:        53,
+		Namespace: "public",
+		Name:      "t",
+		Columns: []RelationColumn{
+			{Name: "k", TypeOID: oid.T_int8, TypeMod: -1, Key: true},
+			{Name: "v", TypeOID: oid.T_text, TypeMod: -1},
+		},
+	}
+	require.Equal(t, []byte{
+		'R', 0, 0, 0, 53, 'p', 'u', 'b', 'l', 'i', 'c', 0, 't', 0, 'd', 0, 2,
+		1, 'k', 0, 0, 0, 0, 20, 0xff, 0xff, 0xff, 0xff,
+		0, 'v', 0, 0, 0, 0, 25, 0xff, 0xff, 0xff, 0xff,
+	}, AppendRelation(nil, rel))
+
+	row := Tuple{[]byte("1"), []byte{}}
+	require.Equal(t,
+		[]byte{'I', 0, 0, 0, 53, 'N', 0, 2, 't', 0, 0, 0, 1, '1', 't', 0, 0, 0, 0},
+		AppendInsert(nil, 53, row))
+	require.Equal(t,
+		[]byte{'U', 0, 0, 0, 53, 'N', 0, 2, 't', 0, 0, 0, 1, '1', 't', 0, 0, 0, 0},
+		AppendUpdate(nil, 53, row))
+	require.Equal(t,
+		[]byte{'D', 0, 0, 0, 53, 'K', 0, 2, 't', 0, 0, 0, 1, '1', 'n'},
+		AppendDelete(nil, 53, Tuple{[]byte("1"), nil}))
+
+	require.Equal(t,
+		append(append([]byte{'w', 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}, usecs...), 'x'),
+		AppendXLogData(nil, 1, 2, ts, []byte{'x'}))
+	require.Equal(t,
+		append(append([]byte{'k', 0, 0, 0, 0, 0, 0, 0, 2}, usecs...), 1),
+		AppendKeepalive(nil, 2, ts, true /* replyRequested */))
+}
+
+func TestParseClientMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msg := []byte{'r'}
+	for _, lsn := range []byte{3, 2, 1} {
+		msg = append(msg, 0, 0, 0, 0, 0, 0, 0, lsn)
+	}
+	msg = append(msg, 0, 0, 0, 0, 0, 0, 0, 0, 1)
+	status, ok, err := ParseClientMessage(msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, StandbyStatus{Flushed: 2, ReplyRequested: true}, status)
+
+	_, _, err = ParseClientMessage(nil)
+	require.Error(t, err)
+	_, _, err = ParseClientMessage(msg[:10])
+	require.EqualError(t, err, "invalid standby status update")
+
+	// Hot standby feedback messages are ignored.
+	_, ok, err = ParseClientMessage([]byte{'h'})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
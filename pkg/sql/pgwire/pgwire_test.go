@@ -2034,3 +2034,72 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestListenNotify checks that the notifications sent by NOTIFY on one node
+// are delivered to the sessions listening on another node, once the notifying
+// transaction commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	connect := func(idx int) *pgx.Conn {
+		pgURL, cleanupFn := sqlutils.PGUrl(
+			t, tc.Server(idx).ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+		t.Cleanup(cleanupFn)
+		conn, err := pgx.Connect(ctx, pgURL.String())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close(ctx) })
+		return conn
+	}
+	listener := connect(0)
+	notifier := connect(1)
+
+	exec := func(conn *pgx.Conn, stmt string) {
+		_, err := conn.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+	waitFor := func(channel, payload string) {
+		waitCtx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+		defer cancel()
+		n, err := listener.WaitForNotification(waitCtx)
+		require.NoError(t, err)
+		require.Equal(t, channel, n.Channel)
+		require.Equal(t, payload, n.Payload)
+	}
+
+	exec(listener, "LISTEN c")
+	exec(notifier, "NOTIFY c, 'a'")
+	waitFor("c", "a")
+
+	// Notifications on other channels and from transactions that roll back
+	// are not delivered.
+	exec(notifier, "NOTIFY other, 'x'")
+	exec(notifier, "BEGIN; SELECT pg_notify('c', 'rolled back'); ROLLBACK")
+	exec(notifier, "SELECT pg_notify('c', 'b')")
+	waitFor("c", "b")
+
+	// Notifications sent in an explicit transaction are delivered once it
+	// commits.
+	tx, err := notifier.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "NOTIFY c, 'in txn'")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+	waitFor("c", "in txn")
+
+	// The listening session itself also receives its notifications.
+	exec(listener, "NOTIFY c, 'self'")
+	waitFor("c", "self")
+
+	// Notifications sent after UNLISTEN are not delivered, even once the
+	// session listens again.
+	exec(listener, "UNLISTEN *")
+	exec(notifier, "NOTIFY c, 'unlistened'")
+	exec(listener, "LISTEN c")
+	exec(notifier, "NOTIFY c, 'd'")
+	waitFor("c", "d")
+}
@@ -14,6 +14,8 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // Conn exposes some functionality of a pgwire network connection to be
@@ -35,3 +37,35 @@ type Conn interface {
 	// payload.
 	SendCommandComplete(tag []byte) error
 }
+
+// ReplicationConn exposes the functionality of a pgwire network connection
+// used by the streaming replication protocol, started by a START_REPLICATION
+// command on a connection in replication mode.
+//
+// Unlike the Copy-in subprotocol, the execution of START_REPLICATION doesn't
+// read from the connection itself: the network routine keeps reading, and
+// hands the payloads of the CopyData messages sent by the client over through
+// ClientMessages.
+type ReplicationConn interface {
+	Conn
+
+	// BeginCopyBoth sends the message initiating the Copy-both subprotocol in
+	// which the replication stream is sent.
+	BeginCopyBoth(ctx context.Context) error
+
+	// SendCopyData sends a CopyData message with the given payload, and flushes
+	// it to the network.
+	SendCopyData(ctx context.Context, data []byte) error
+
+	// SendCopyDone sends a CopyDone message, which ends the replication stream.
+	SendCopyDone(ctx context.Context) error
+
+	// ClientMessages returns the channel on which the payloads of the CopyData
+	// messages sent by the client are delivered. The channel is closed when the
+	// client ends the stream with CopyDone or CopyFail.
+	ClientMessages() <-chan []byte
+
+	// EncodeTextDatum returns the text encoding of a non-NULL datum of type t,
+	// as it would be sent in a DataRow message.
+	EncodeTextDatum(ctx context.Context, d tree.Datum, t *types.T) []byte
+}
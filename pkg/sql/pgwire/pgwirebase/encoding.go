@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/errors"
 	"github.com/dustin/go-humanize"
@@ -303,6 +304,11 @@ func validateArrayDimensions(nDimensions int, nElements int) error {
 func DecodeDatum(
 	evalCtx *tree.EvalContext, t *types.T, code FormatCode, b []byte,
 ) (tree.Datum, error) {
+	// Domains are decoded like their base type. Their constraints are checked
+	// when the value is used.
+	if t.IsDomain() {
+		t = t.DomainBaseType()
+	}
 	id := t.Oid()
 	switch code {
 	case FormatText:
@@ -485,6 +491,23 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsquery:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSQuery(string(b))
+		case oid.T_tsvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSVector(string(b))
+		}
+		if t.Family() == types.RangeFamily {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDRangeFromString(evalCtx, string(b), t)
+			return d, err
 		}
 		if t.Family() == types.ArrayFamily {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -732,6 +755,18 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsquery:
+			q, err := tsearch.DecodeTSQuery(b)
+			if err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.Syntax, "could not decode tsquery")
+			}
+			return tree.NewDTSQuery(q), nil
+		case oid.T_tsvector:
+			v, err := tsearch.DecodeTSVector(b)
+			if err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.Syntax, "could not decode tsvector")
+			}
+			return tree.NewDTSVector(v), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -771,6 +806,9 @@ func DecodeDatum(
 			if t.Family() == types.ArrayFamily {
 				return decodeBinaryArray(evalCtx, t.ArrayContents(), b, code)
 			}
+			if t.Family() == types.RangeFamily {
+				return decodeBinaryRange(evalCtx, t, b)
+			}
 		}
 	default:
 		return nil, errors.AssertionFailedf(
@@ -973,6 +1011,83 @@ func decodeBinaryArray(
 	return arr, nil
 }
 
+// Flags used in the binary representation of a range. They match the flags
+// used by Postgres.
+const (
+	rangeEmpty    = 0x01
+	rangeLowerInc = 0x02
+	rangeUpperInc = 0x04
+	rangeLowerInf = 0x08
+	rangeUpperInf = 0x10
+)
+
+// RangeFlags returns the flags byte that starts the binary representation of
+// the given range.
+func RangeFlags(r *tree.DRange) byte {
+	if r.Empty {
+		return rangeEmpty
+	}
+	var flags byte
+	if r.Lower.IsInf() {
+		flags |= rangeLowerInf
+	} else if r.Lower.Inclusive {
+		flags |= rangeLowerInc
+	}
+	if r.Upper.IsInf() {
+		flags |= rangeUpperInf
+	} else if r.Upper.Inclusive {
+		flags |= rangeUpperInc
+	}
+	return flags
+}
+
+// decodeBinaryRange decodes the binary representation of a range: a flags
+// byte followed by each finite bound, prefixed by its length.
+func decodeBinaryRange(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) < 1 {
+		return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+	}
+	flags := b[0]
+	b = b[1:]
+	if flags&rangeEmpty != 0 {
+		if len(b) != 0 {
+			return nil, NewInvalidBinaryRepresentationErrorf("incorrect binary data")
+		}
+		return tree.NewDEmptyRange(t), nil
+	}
+	decodeBound := func() (tree.Datum, error) {
+		if len(b) < 4 {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+		}
+		vlen := int(int32(binary.BigEndian.Uint32(b)))
+		b = b[4:]
+		if vlen < 0 || vlen > len(b) {
+			return nil, NewInvalidBinaryRepresentationErrorf("incorrect binary data")
+		}
+		buf := b[:vlen]
+		b = b[vlen:]
+		return DecodeDatum(evalCtx, t.RangeContents(), FormatBinary, buf)
+	}
+	var lower, upper tree.RangeBound
+	var err error
+	if flags&rangeLowerInf == 0 {
+		if lower.Val, err = decodeBound(); err != nil {
+			return nil, err
+		}
+		lower.Inclusive = flags&rangeLowerInc != 0
+	}
+	if flags&rangeUpperInf == 0 {
+		if upper.Val, err = decodeBound(); err != nil {
+			return nil, err
+		}
+		upper.Inclusive = flags&rangeUpperInc != 0
+	}
+	if len(b) != 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("incorrect binary data")
+	}
+	return tree.NewDRange(t, lower, upper)
+}
+
 func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
 	if len(b) < 4 {
 		return nil, pgerror.Newf(pgcode.Syntax, "tuple requires a 4 byte header for binary format")
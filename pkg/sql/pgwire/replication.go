@@ -0,0 +1,154 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// replicationConn implements pgwirebase.ReplicationConn. It is handed to the
+// execution of a START_REPLICATION command, which writes the replication
+// stream from the connExecutor goroutine while the network routine keeps
+// reading the messages sent by the client.
+type replicationConn struct {
+	*conn
+
+	// buf is used to write the messages of the replication stream. The
+	// msgBuilder of the conn is not used since it is not meant to be used by
+	// the connExecutor.
+	buf writeBuffer
+	// scratch is used by EncodeTextDatum.
+	scratch writeBuffer
+
+	// msgs receives the payloads of the CopyData messages sent by the client.
+	msgs chan []byte
+	// done is closed once the execution of START_REPLICATION finishes.
+	done chan struct{}
+}
+
+var _ pgwirebase.ReplicationConn = &replicationConn{}
+
+func newReplicationConn(c *conn) *replicationConn {
+	rc := &replicationConn{
+		conn: c,
+		msgs: make(chan []byte),
+		done: make(chan struct{}),
+	}
+	rc.buf.init(c.metrics.BytesOutCount)
+	rc.scratch.init(c.metrics.BytesOutCount)
+	return rc
+}
+
+// BeginCopyBoth is part of the pgwirebase.ReplicationConn interface.
+func (rc *replicationConn) BeginCopyBoth(ctx context.Context) error {
+	rc.buf.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	// Like Postgres, announce the text format with no columns.
+	rc.buf.writeByte(byte(pgwirebase.FormatText))
+	rc.buf.putInt16(0)
+	return rc.buf.finishMsg(rc.conn.conn)
+}
+
+// SendCopyData is part of the pgwirebase.ReplicationConn interface.
+func (rc *replicationConn) SendCopyData(ctx context.Context, data []byte) error {
+	rc.buf.initMsg(pgwirebase.ServerMsgCopyData)
+	rc.buf.write(data)
+	return rc.buf.finishMsg(rc.conn.conn)
+}
+
+// SendCopyDone is part of the pgwirebase.ReplicationConn interface.
+func (rc *replicationConn) SendCopyDone(ctx context.Context) error {
+	rc.buf.initMsg(pgwirebase.ServerMsgCopyDone)
+	return rc.buf.finishMsg(rc.conn.conn)
+}
+
+// ClientMessages is part of the pgwirebase.ReplicationConn interface.
+func (rc *replicationConn) ClientMessages() <-chan []byte {
+	return rc.msgs
+}
+
+// EncodeTextDatum is part of the pgwirebase.ReplicationConn interface.
+func (rc *replicationConn) EncodeTextDatum(
+	ctx context.Context, d tree.Datum, t *types.T,
+) []byte {
+	rc.scratch.reset()
+	writeTextDatumNotNull(&rc.scratch, d, sessiondatapb.DataConversionConfig{}, time.UTC, t)
+	// Skip the length prefix.
+	return append([]byte{}, rc.scratch.wrapped.Bytes()[4:]...)
+}
+
+// handleReplicationCommand schedules the execution of a command of the
+// streaming replication protocol, parsed by pgrepl.Parse.
+func (c *conn) handleReplicationCommand(
+	ctx context.Context,
+	query string,
+	stmt tree.Statement,
+	timeReceived, startParse, endParse time.Time,
+) error {
+	if n, ok := stmt.(*tree.StartReplication); ok {
+		// START_REPLICATION runs until the client ends the stream. Unlike
+		// COPY FROM, its execution doesn't take control over the connection: the
+		// CopyData messages sent by the client are routed to it by the network
+		// routine, see routeReplicationMessage.
+		rc := newReplicationConn(c)
+		c.replication = rc
+		return c.stmtBuf.Push(ctx, sql.StartReplication{Stmt: n, Conn: rc, Done: rc.done})
+	}
+	return c.stmtBuf.Push(
+		ctx,
+		sql.ExecStmt{
+			Statement:    parser.Statement{AST: stmt, SQL: query},
+			TimeReceived: timeReceived,
+			ParseStart:   startParse,
+			ParseEnd:     endParse,
+		})
+}
+
+// routeReplicationMessage hands a Copy subprotocol message sent by the client
+// over to the replication stream in progress. It returns false if there is no
+// stream in progress, in which case the message is to be ignored.
+func (c *conn) routeReplicationMessage(
+	ctx context.Context, typ pgwirebase.ClientMessageType,
+) (bool, error) {
+	rc := c.replication
+	if rc == nil {
+		return false, nil
+	}
+	select {
+	case <-rc.done:
+		// The stream has ended already, possibly with an error.
+		c.replication = nil
+		return false, nil
+	default:
+	}
+	if typ != pgwirebase.ClientMsgCopyData {
+		// CopyDone or CopyFail: the client ends the stream.
+		close(rc.msgs)
+		c.replication = nil
+		return true, nil
+	}
+	msg := append([]byte(nil), c.readBuf.Msg...)
+	select {
+	case rc.msgs <- msg:
+	case <-rc.done:
+		c.replication = nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+	return true, nil
+}
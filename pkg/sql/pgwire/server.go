@@ -13,6 +13,7 @@ package pgwire
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net"
@@ -821,6 +822,37 @@ func parseClientProvidedSessionParameters(
 			}
 			args.RemoteAddr = &net.TCPAddr{IP: ip, Port: port}
 
+		case "crdb:session_revival_token_base64":
+			if sv == nil || !sql.AllowSessionRevival.Get(sv) {
+				return sql.SessionArgs{}, pgerror.New(pgcode.ProtocolViolation,
+					"session revival tokens are not supported on this cluster")
+			}
+			token, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return sql.SessionArgs{}, pgerror.Wrapf(
+					err, pgcode.ProtocolViolation,
+					"%s", "crdb:session_revival_token_base64",
+				)
+			}
+			args.SessionRevivalToken = token
+
+		case "replication":
+			// Only logical replication is supported; it is requested with
+			// "database". Like Postgres, a boolean requests physical
+			// replication.
+			switch strings.ToLower(value) {
+			case "database":
+				args.Replication = true
+			case "false", "off", "no", "0":
+				args.Replication = false
+			case "true", "on", "yes", "1":
+				return sql.SessionArgs{}, pgerror.New(pgcode.FeatureNotSupported,
+					"physical replication is not supported")
+			default:
+				return sql.SessionArgs{}, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for parameter \"replication\": %q", value)
+			}
+
 		case "options":
 			opts, err := parseOptions(value)
 			if err != nil {
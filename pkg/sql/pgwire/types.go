@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -57,6 +58,10 @@ type pgType struct {
 }
 
 func pgTypeForParserType(t *types.T) pgType {
+	// Like Postgres, report the base type of domains to the client.
+	if t.IsDomain() {
+		t = t.DomainBaseType()
+	}
 	size := -1
 	if s, variable := tree.DatumTypeSize(t); !variable {
 		size = int(s)
@@ -162,6 +167,10 @@ func writeTextDatumNotNull(
 ) {
 	oldDCC := b.textFormatter.SetDataConversionConfig(conv)
 	defer b.textFormatter.SetDataConversionConfig(oldDCC)
+	// Domains are encoded like their base type.
+	if t.IsDomain() {
+		t = t.DomainBaseType()
+	}
 	switch v := tree.UnwrapDatum(nil, d).(type) {
 	case *tree.DBitArray:
 		b.textFormatter.FormatNode(v)
@@ -242,6 +251,16 @@ func writeTextDatumNotNull(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DRange:
+		b.textFormatter.FormatNode(rangeInLocation(v, sessionLoc))
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -263,6 +282,22 @@ func writeTextDatumNotNull(
 	}
 }
 
+// rangeInLocation returns r with any TIMESTAMPTZ bounds converted to the
+// session's time zone, so that they are formatted the same way as standalone
+// TIMESTAMPTZ values.
+func rangeInLocation(r *tree.DRange, sessionLoc *time.Location) *tree.DRange {
+	if sessionLoc == nil || r.Empty || r.ResolvedType().Oid() != oid.T_tstzrange {
+		return r
+	}
+	ret := *r
+	for _, bound := range []*tree.RangeBound{&ret.Lower, &ret.Upper} {
+		if ts, ok := bound.Val.(*tree.DTimestampTZ); ok {
+			bound.Val = &tree.DTimestampTZ{Time: ts.Time.In(sessionLoc)}
+		}
+	}
+	return &ret
+}
+
 // getInt64 returns an int64 from vectors of Int family.
 func getInt64(vecs *coldata.TypedVecs, vecIdx, rowIdx int, typ *types.T) int64 {
 	colIdx := vecs.ColsMap[vecIdx]
@@ -554,6 +589,10 @@ func (b *writeBuffer) writeBinaryDatum(
 func writeBinaryDatumNotNull(
 	ctx context.Context, b *writeBuffer, d tree.Datum, sessionLoc *time.Location, t *types.T,
 ) {
+	// Domains are encoded like their base type.
+	if t.IsDomain() {
+		t = t.DomainBaseType()
+	}
 	switch v := tree.UnwrapDatum(nil, d).(type) {
 	case *tree.DBitArray:
 		words, lastBitsUsed := v.EncodingParts()
@@ -745,6 +784,37 @@ func writeBinaryDatumNotNull(
 	case *tree.DJSON:
 		writeBinaryJSON(b, v.JSON)
 
+	case *tree.DTSQuery:
+		encoded := tsearch.EncodeTSQuery(nil, v.TSQuery)
+		b.putInt32(int32(len(encoded)))
+		b.write(encoded)
+
+	case *tree.DTSVector:
+		encoded := tsearch.EncodeTSVector(nil, v.TSVector)
+		b.putInt32(int32(len(encoded)))
+		b.write(encoded)
+
+	case *tree.DRange:
+		initialLen := b.Len()
+
+		// Reserve bytes for writing length later.
+		b.putInt32(int32(0))
+
+		// Put the range flags, followed by each finite bound.
+		b.writeByte(pgwirebase.RangeFlags(v))
+		if !v.Empty {
+			subtype := v.ResolvedType().RangeContents()
+			if !v.Lower.IsInf() {
+				b.writeBinaryDatum(ctx, v.Lower.Val, sessionLoc, subtype)
+			}
+			if !v.Upper.IsInf() {
+				b.writeBinaryDatum(ctx, v.Upper.Val, sessionLoc, subtype)
+			}
+		}
+
+		lengthToWrite := b.Len() - (initialLen + 4)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
+
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
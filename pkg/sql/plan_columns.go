@@ -114,6 +114,10 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.getColumns(mut, colinfo.AlterTableScatterColumns)
 	case *showFingerprintsNode:
 		return n.getColumns(mut, colinfo.ShowFingerprintsColumns)
+	case *identifySystemNode:
+		return n.getColumns(mut, colinfo.IdentifySystemColumns)
+	case *createReplicationSlotNode:
+		return n.getColumns(mut, colinfo.CreateReplicationSlotColumns)
 	case *splitNode:
 		return n.getColumns(mut, colinfo.AlterTableSplitColumns)
 	case *unsplitNode:
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
@@ -100,6 +101,11 @@ type extendedEvalContext struct {
 	indexUsageStats *idxusage.LocalIndexUsageStats
 
 	SchemaChangerState *SchemaChangerState
+
+	// DeferredConstraints refers to deferredConstraints in extraTxnState of
+	// sql.connExecutor. It tracks the DEFERRABLE constraint checks that must be
+	// run when the transaction is committed.
+	DeferredConstraints *deferredConstraints
 }
 
 // copyFromExecCfg copies relevant fields from an ExecutorConfig.
@@ -231,6 +237,11 @@ type planner struct {
 	// instead.
 	noticeSender noticeSender
 
+	// notificationListener queues the asynchronous notifications for the
+	// channels the session listens on. It is nil for internal sessions, which
+	// can't LISTEN.
+	notificationListener *notify.Listener
+
 	queryCacheSession querycache.Session
 
 	// contextDatabaseID is the ID of a database. It is set during some name
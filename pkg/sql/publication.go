@@ -0,0 +1,278 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+	tables []catalog.TableDescriptor
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createPublicationNode{n: nil}
+
+// CreatePublication creates a publication in the current database.
+// Privileges: CREATE on the database, ownership of the tables in the
+// publication, and the admin role for FOR ALL TABLES.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Publications) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"publications are only available once the cluster is fully upgraded",
+		)
+	}
+
+	dbDesc, err := p.currentMutableDatabaseForPublication(ctx, "create publication")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if dbDesc.GetPublication(string(n.Name)) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"publication %q already exists", n.Name)
+	}
+
+	if n.AllTables {
+		if err := p.RequireAdminRole(ctx, "CREATE PUBLICATION ... FOR ALL TABLES"); err != nil {
+			return nil, err
+		}
+	}
+	tables := make([]catalog.TableDescriptor, 0, len(n.Tables))
+	seen := make(map[descpb.ID]struct{}, len(n.Tables))
+	for i := range n.Tables {
+		tn := &n.Tables[i]
+		tableDesc, err := p.ResolveUncachedTableDescriptorEx(
+			ctx, tn.ToUnresolvedObjectName(), true /* required */, tree.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPublicationTable(tableDesc, dbDesc, tn); err != nil {
+			return nil, err
+		}
+		hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			if err := p.RequireAdminRole(ctx, "CREATE PUBLICATION"); err != nil {
+				return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+					"must be owner of table %s", tn)
+			}
+		}
+		if _, ok := seen[tableDesc.GetID()]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"table %s specified more than once", tn)
+		}
+		seen[tableDesc.GetID()] = struct{}{}
+		tables = append(tables, tableDesc)
+	}
+
+	return &createPublicationNode{n: n, dbDesc: dbDesc, tables: tables}, nil
+}
+
+// currentMutableDatabaseForPublication returns the current database, in which
+// publications are created and dropped.
+func (p *planner) currentMutableDatabaseForPublication(
+	ctx context.Context, op string,
+) (*dbdesc.Mutable, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.Newf(pgcode.UndefinedDatabase,
+			"cannot %s without being connected to a database", op)
+	}
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot %s in the system database", op)
+	}
+	return dbDesc, nil
+}
+
+// checkPublicationTable verifies that the given table can be part of a
+// publication of the given database.
+func checkPublicationTable(
+	tableDesc catalog.TableDescriptor, dbDesc catalog.DatabaseDescriptor, tn *tree.TableName,
+) error {
+	if tableDesc.GetParentID() != dbDesc.GetID() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot add table %s of another database to publication", tn)
+	}
+	if !tableDesc.IsTable() {
+		return errors.WithDetail(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", tableDesc.GetName()),
+			"Only tables can be added to publications.")
+	}
+	if tableDesc.IsTemporary() {
+		return errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication", tableDesc.GetName()),
+			"Temporary tables cannot be replicated.")
+	}
+	return nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	pub := descpb.DatabaseDescriptor_Publication{
+		Name:       string(n.n.Name),
+		OwnerProto: params.p.User().EncodeProto(),
+		AllTables:  n.n.AllTables,
+	}
+	for _, tableDesc := range n.tables {
+		pub.TableIDs = append(pub.TableIDs, tableDesc.GetID())
+	}
+	n.dbDesc.AddPublication(pub)
+
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createPublicationNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createPublicationNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createPublicationNode) Close(ctx context.Context)           {}
+
+type dropPublicationNode struct {
+	n      *tree.DropPublication
+	dbDesc *dbdesc.Mutable
+	names  []string
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropPublicationNode{n: nil}
+
+// DropPublication drops publications of the current database.
+// Privileges: ownership of the publications.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.currentMutableDatabaseForPublication(ctx, "drop publication")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(n.Names))
+	for _, name := range n.Names {
+		pub := dbDesc.GetPublication(string(name))
+		if pub == nil {
+			if n.IfExists {
+				p.BufferClientNotice(ctx,
+					pgnotice.Newf("publication %q does not exist, skipping", name))
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"publication %q does not exist", name)
+		}
+		owner := pub.OwnerProto.Decode()
+		hasOwnership, err := p.checkRolePredicate(ctx, p.User(), func(role security.SQLUsername) bool {
+			return role == owner
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			if err := p.RequireAdminRole(ctx, "DROP PUBLICATION"); err != nil {
+				return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+					"must be owner of publication %s", name)
+			}
+		}
+		names = append(names, pub.Name)
+	}
+
+	return &dropPublicationNode{n: n, dbDesc: dbDesc, names: names}, nil
+}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	if len(n.names) == 0 {
+		return nil
+	}
+	for _, name := range n.names {
+		// A publication may be listed twice.
+		n.dbDesc.RemovePublication(name)
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropPublicationNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropPublicationNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropPublicationNode) Close(ctx context.Context)           {}
+
+// PublicationTables returns the tables of the given publication of the
+// database, in the order of their IDs. Tables that were dropped since they
+// were added to the publication are skipped.
+func PublicationTables(
+	ctx context.Context,
+	txn *kv.Txn,
+	col *descs.Collection,
+	dbDesc catalog.DatabaseDescriptor,
+	pub *descpb.DatabaseDescriptor_Publication,
+) ([]catalog.TableDescriptor, error) {
+	all, err := col.GetAllTableDescriptorsInDatabase(ctx, txn, dbDesc.GetID())
+	if err != nil {
+		return nil, err
+	}
+	var ids catalog.DescriptorIDSet
+	for _, id := range pub.TableIDs {
+		ids.Add(id)
+	}
+	var tables []catalog.TableDescriptor
+	for _, table := range all {
+		if table.Dropped() || table.IsTemporary() || !table.IsPhysicalTable() || table.IsSequence() {
+			continue
+		}
+		if pub.AllTables || ids.Contains(table.GetID()) {
+			tables = append(tables, table)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].GetID() < tables[j].GetID() })
+	return tables, nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -94,6 +95,29 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 		default:
 			panic(errors.AssertionFailedf("float with an unexpected width %d", typ.Width()))
 		}
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
+	case types.TSVectorFamily:
+		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case types.RangeFamily:
+		if rng.Intn(10) == 0 {
+			return tree.NewDEmptyRange(typ)
+		}
+		var lower, upper tree.RangeBound
+		if rng.Intn(5) != 0 {
+			lower = tree.RangeBound{Val: RandDatum(rng, typ.RangeContents(), false), Inclusive: rng.Intn(2) == 0}
+		}
+		if rng.Intn(5) != 0 {
+			upper = tree.RangeBound{Val: RandDatum(rng, typ.RangeContents(), false), Inclusive: rng.Intn(2) == 0}
+		}
+		r, err := tree.NewDRange(typ, lower, upper)
+		if err != nil {
+			// The bounds were out of order, so swap them.
+			if r, err = tree.NewDRange(typ, upper, lower); err != nil {
+				panic(err)
+			}
+		}
+		return r
 	case types.Box2DFamily:
 		b := geo.NewCartesianBoundingBox().AddPoint(rng.NormFloat64(), rng.NormFloat64()).AddPoint(rng.NormFloat64(), rng.NormFloat64())
 		return tree.NewDBox2D(*b)
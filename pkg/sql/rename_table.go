@@ -89,6 +89,11 @@ func (p *planner) RenameTable(ctx context.Context, n *tree.RenameTable) (planNod
 			)
 		}
 	}
+	if err := p.dependentFunctionError(
+		ctx, string(tableDesc.DescriptorType()), oldTn.String(), tableDesc.DependedOnByFunctions, "rename",
+	); err != nil {
+		return nil, err
+	}
 
 	return &renameTableNode{n: n, oldTn: &oldTn, newTn: &newTn, tableDesc: tableDesc}, nil
 }
@@ -0,0 +1,395 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// maxReplicationSlotNameLength is the maximum length of the name of a
+// replication slot, as in Postgres.
+const maxReplicationSlotNameLength = 63
+
+type identifySystemNode struct {
+	optColumnsSlot
+
+	row  tree.Datums
+	done bool
+}
+
+// IdentifySystem implements the IDENTIFY_SYSTEM replication command, which
+// returns the position of the replication streams of the cluster.
+// Privileges: CONTROLCHANGEFEED.
+func (p *planner) IdentifySystem(ctx context.Context, n *tree.IdentifySystem) (planNode, error) {
+	if err := p.CheckRoleOption(ctx, roleoption.CONTROLCHANGEFEED); err != nil {
+		return nil, err
+	}
+	// Postgres identifies the system with a 64-bit integer.
+	systemID := strconv.FormatUint(p.ExecCfg().ClusterID().ToUint128().Lo, 10)
+	dbName := tree.DNull
+	if p.CurrentDatabase() != "" {
+		dbName = tree.NewDString(p.CurrentDatabase())
+	}
+	return &identifySystemNode{
+		row: tree.Datums{
+			tree.NewDString(systemID),
+			// There is a single timeline.
+			tree.NewDInt(1),
+			tree.NewDString(pgrepl.LSNFromTimestamp(p.txn.ReadTimestamp()).String()),
+			dbName,
+		},
+	}, nil
+}
+
+func (n *identifySystemNode) startExec(params runParams) error { return nil }
+
+func (n *identifySystemNode) Next(params runParams) (bool, error) {
+	if n.done {
+		return false, nil
+	}
+	n.done = true
+	return true, nil
+}
+
+func (n *identifySystemNode) Values() tree.Datums       { return n.row }
+func (n *identifySystemNode) Close(ctx context.Context) {}
+
+type createReplicationSlotNode struct {
+	optColumnsSlot
+
+	n      *tree.CreateReplicationSlot
+	dbDesc *dbdesc.Mutable
+
+	run struct {
+		row  tree.Datums
+		done bool
+	}
+}
+
+// CreateReplicationSlot implements the CREATE_REPLICATION_SLOT replication
+// command. A replication slot belongs to the current database, and remembers
+// the position up to which its client has consumed the changes of the
+// replication stream.
+// Privileges: CONTROLCHANGEFEED.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *tree.CreateReplicationSlot,
+) (planNode, error) {
+	if err := p.CheckRoleOption(ctx, roleoption.CONTROLCHANGEFEED); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Publications) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"replication slots are only available once the cluster is fully upgraded",
+		)
+	}
+	if err := checkReplicationSlotName(string(n.Name)); err != nil {
+		return nil, err
+	}
+	if n.Plugin != pgrepl.PgoutputPlugin {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"output plugin %q is not supported", n.Plugin)
+	}
+	if n.Temporary {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"temporary replication slots are not supported")
+	}
+	if n.SnapshotAction == "EXPORT_SNAPSHOT" || n.SnapshotAction == "USE_SNAPSHOT" {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported", n.SnapshotAction)
+	}
+	dbDesc, err := p.currentMutableDatabaseForPublication(ctx, "create replication slot")
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetReplicationSlot(string(n.Name)) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"replication slot %q already exists", n.Name)
+	}
+	return &createReplicationSlotNode{n: n, dbDesc: dbDesc}, nil
+}
+
+// checkReplicationSlotName verifies that the name of a replication slot is
+// made of the characters allowed by Postgres.
+func checkReplicationSlotName(name string) error {
+	if name == "" {
+		return pgerror.New(pgcode.InvalidName, "replication slot name is empty")
+	}
+	if len(name) > maxReplicationSlotNameLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !(('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '_') {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidName,
+					"replication slot name %q contains invalid character", name),
+				"Replication slot names may only contain lower case letters, numbers, and the underscore character.")
+		}
+	}
+	return nil
+}
+
+func (n *createReplicationSlotNode) startExec(params runParams) error {
+	// The changes committed after the transaction creating the slot are
+	// streamed from it.
+	ts := params.p.txn.ReadTimestamp()
+	n.dbDesc.AddReplicationSlot(descpb.DatabaseDescriptor_ReplicationSlot{
+		Name:           string(n.n.Name),
+		Plugin:         string(n.n.Plugin),
+		ConfirmedFlush: ts,
+	})
+	if err := params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+	n.run.row = tree.Datums{
+		tree.NewDString(string(n.n.Name)),
+		tree.NewDString(pgrepl.LSNFromTimestamp(ts).String()),
+		// Snapshots are not exported.
+		tree.DNull,
+		tree.NewDString(string(n.n.Plugin)),
+	}
+	return nil
+}
+
+func (n *createReplicationSlotNode) Next(params runParams) (bool, error) {
+	if n.run.done {
+		return false, nil
+	}
+	n.run.done = true
+	return true, nil
+}
+
+func (n *createReplicationSlotNode) Values() tree.Datums       { return n.run.row }
+func (n *createReplicationSlotNode) Close(ctx context.Context) {}
+
+type dropReplicationSlotNode struct {
+	n      *tree.DropReplicationSlot
+	dbDesc *dbdesc.Mutable
+}
+
+// DropReplicationSlot implements the DROP_REPLICATION_SLOT replication
+// command.
+// Privileges: CONTROLCHANGEFEED.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *tree.DropReplicationSlot,
+) (planNode, error) {
+	if err := p.CheckRoleOption(ctx, roleoption.CONTROLCHANGEFEED); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.currentMutableDatabaseForPublication(ctx, "drop replication slot")
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetReplicationSlot(string(n.Name)) == nil {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", n.Name)
+	}
+	return &dropReplicationSlotNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	n.dbDesc.RemoveReplicationSlot(string(n.n.Name))
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropReplicationSlotNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropReplicationSlotNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropReplicationSlotNode) Close(ctx context.Context)           {}
+
+// ReplicationStream describes the changes streamed by a START_REPLICATION
+// command, as resolved from its replication slot and publications.
+type ReplicationStream struct {
+	// DatabaseID is the ID of the database of the replication slot.
+	DatabaseID descpb.ID
+	// SlotName is the name of the replication slot.
+	SlotName string
+	// StartTime is the timestamp after which changes are streamed.
+	StartTime hlc.Timestamp
+	// Tables are the tables of the publications, as of StartTime.
+	Tables []catalog.TableDescriptor
+	// SchemaNames maps the IDs of the schemas of Tables to their names.
+	SchemaNames map[descpb.ID]string
+	// User is the user that started the stream.
+	User security.SQLUsername
+}
+
+// StartReplicationHook streams the changes described by the given
+// ReplicationStream on the connection, until the client ends the stream. It is
+// set by the CCL changefeed package.
+var StartReplicationHook func(
+	ctx context.Context, execCfg *ExecutorConfig, stream ReplicationStream, conn pgwirebase.ReplicationConn,
+) error
+
+// execStartReplication executes a START_REPLICATION command. The replication
+// stream runs outside of any transaction and keeps the connection in the
+// Copy-both subprotocol until the client ends it.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication,
+) (fsm.Event, fsm.EventPayload) {
+	// When we're done, stop handing the messages of the client over to the
+	// stream.
+	defer close(cmd.Done)
+
+	if err := func() error {
+		if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+			return pgerror.New(pgcode.ActiveSQLTransaction,
+				"START_REPLICATION cannot run inside a transaction block")
+		}
+		if StartReplicationHook == nil {
+			return sqlerrors.NewCCLRequiredError(
+				errors.New("logical replication requires a CCL binary"))
+		}
+		opts, err := pgrepl.ParsePgoutputOptions(cmd.Stmt.Options)
+		if err != nil {
+			return err
+		}
+		execCfg := ex.server.cfg
+		var stream ReplicationStream
+		if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			p, cleanup := newInternalPlanner(
+				"start-replication", txn, ex.sessionData().User(), &MemoryMetrics{}, execCfg,
+				ex.sessionData().SessionData,
+			)
+			defer cleanup()
+			var err error
+			stream, err = p.resolveReplicationStream(ctx, cmd.Stmt, opts)
+			return err
+		}); err != nil {
+			return err
+		}
+		return StartReplicationHook(ctx, execCfg, stream, cmd.Conn)
+	}(); err != nil {
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}
+	}
+	return nil, nil
+}
+
+// resolveReplicationStream resolves the replication slot and the
+// publications of a START_REPLICATION command in the current database.
+func (p *planner) resolveReplicationStream(
+	ctx context.Context, n *tree.StartReplication, opts pgrepl.PgoutputOptions,
+) (ReplicationStream, error) {
+	if err := p.CheckRoleOption(ctx, roleoption.CONTROLCHANGEFEED); err != nil {
+		return ReplicationStream{}, err
+	}
+	if p.CurrentDatabase() == "" {
+		return ReplicationStream{}, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot start logical replication without being connected to a database")
+	}
+	_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return ReplicationStream{}, err
+	}
+	slot := dbDesc.GetReplicationSlot(string(n.Slot))
+	if slot == nil {
+		return ReplicationStream{}, pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", n.Slot)
+	}
+	stream := ReplicationStream{
+		DatabaseID:  dbDesc.GetID(),
+		SlotName:    slot.Name,
+		StartTime:   slot.ConfirmedFlush,
+		SchemaNames: make(map[descpb.ID]string),
+		User:        p.User(),
+	}
+	// Like Postgres, streaming resumes from the position confirmed by the
+	// client, unless it asks for a later one.
+	if ts := pgrepl.LSN(n.StartLSN).Timestamp(); stream.StartTime.Less(ts) {
+		stream.StartTime = ts
+	}
+	if pgrepl.LSNFromTimestamp(p.txn.ReadTimestamp()) < pgrepl.LSN(n.StartLSN) {
+		return ReplicationStream{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"requested starting point %s is ahead of the current position",
+			pgrepl.LSN(n.StartLSN))
+	}
+
+	var seen catalog.DescriptorIDSet
+	for _, name := range opts.Publications {
+		pub := dbDesc.GetPublication(name)
+		if pub == nil {
+			return ReplicationStream{}, pgerror.Newf(pgcode.UndefinedObject,
+				"publication %q does not exist", name)
+		}
+		tables, err := PublicationTables(ctx, p.txn, p.Descriptors(), dbDesc, pub)
+		if err != nil {
+			return ReplicationStream{}, err
+		}
+		for _, table := range tables {
+			if seen.Contains(table.GetID()) {
+				continue
+			}
+			seen.Add(table.GetID())
+			stream.Tables = append(stream.Tables, table)
+			if _, ok := stream.SchemaNames[table.GetParentSchemaID()]; ok {
+				continue
+			}
+			sc, err := p.Descriptors().GetImmutableSchemaByID(
+				ctx, p.txn, table.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+			)
+			if err != nil {
+				return ReplicationStream{}, err
+			}
+			stream.SchemaNames[sc.GetID()] = sc.GetName()
+		}
+	}
+	return stream, nil
+}
+
+// AdvanceReplicationSlot records that the client of a replication slot has
+// consumed the changes up to the given timestamp. The position of a slot only
+// moves forward.
+func AdvanceReplicationSlot(
+	ctx context.Context, execCfg *ExecutorConfig, dbID descpb.ID, slotName string, ts hlc.Timestamp,
+) error {
+	return DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		desc, err := col.GetMutableDescriptorByID(ctx, dbID, txn)
+		if err != nil {
+			return err
+		}
+		dbDesc, ok := desc.(*dbdesc.Mutable)
+		if !ok {
+			return errors.AssertionFailedf("descriptor %d is not a database", dbID)
+		}
+		slot := dbDesc.GetReplicationSlot(slotName)
+		if slot == nil {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q does not exist", slotName)
+		}
+		if !slot.ConfirmedFlush.Less(ts) {
+			return nil
+		}
+		slot.ConfirmedFlush = ts
+		return col.WriteDesc(ctx, false /* kvTrace */, dbDesc, txn)
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// rowLevelTTLAutomaticColumnExpr returns the expression used as both the
+// DEFAULT and the ON UPDATE expression of the expiration column of a table
+// with row-level TTL, i.e. the current time plus the TTL.
+func rowLevelTTLAutomaticColumnExpr(ttl *catpb.RowLevelTTL) (tree.Expr, error) {
+	intervalExpr, err := parser.ParseExpr(ttl.DurationExpr)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.BinaryExpr{
+		Operator: tree.MakeBinaryOperator(tree.Plus),
+		Left:     &tree.FuncExpr{Func: tree.WrapFunction("current_timestamp")},
+		Right:    intervalExpr,
+	}, nil
+}
+
+// rowLevelTTLAutomaticColumnDef returns the definition of the hidden column
+// holding the expiration time of the rows of a table with row-level TTL.
+func rowLevelTTLAutomaticColumnDef(ttl *catpb.RowLevelTTL) (*tree.ColumnTableDef, error) {
+	expr, err := rowLevelTTLAutomaticColumnExpr(ttl)
+	if err != nil {
+		return nil, err
+	}
+	def := &tree.ColumnTableDef{
+		Name:   catpb.TTLDefaultExpirationColumnName,
+		Type:   types.TimestampTZ,
+		Hidden: true,
+	}
+	def.Nullable.Nullability = tree.NotNull
+	def.DefaultExpr.Expr = expr
+	def.OnUpdateExpr.Expr = expr
+	return def, nil
+}
+
+// createRowLevelTTLScheduledJob creates the schedule of the job deleting the
+// expired rows of the given table.
+func createRowLevelTTLScheduledJob(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	owner security.SQLUsername,
+	tblID descpb.ID,
+	ttl *catpb.RowLevelTTL,
+) (*jobs.ScheduledJob, error) {
+	sj := jobs.NewScheduledJob(jobSchedulerEnv(execCfg))
+	sj.SetScheduleLabel(fmt.Sprintf("row-level-ttl-%d", tblID))
+	sj.SetOwner(owner)
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait: jobspb.ScheduleDetails_WAIT,
+		// If a job fails, try again at the allocated cron time.
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+	if err := sj.SetSchedule(ttl.DeletionCronOrDefault()); err != nil {
+		return nil, err
+	}
+	args, err := pbtypes.MarshalAny(&catpb.ScheduledRowLevelTTLArgs{TableID: uint32(tblID)})
+	if err != nil {
+		return nil, err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: args},
+	)
+	if err := sj.Create(ctx, execCfg.InternalExecutor, txn); err != nil {
+		return nil, err
+	}
+	return sj, nil
+}
+
+// deleteRowLevelTTLSchedule deletes the schedule of the row-level TTL job of
+// a table which is being dropped.
+func (p *planner) deleteRowLevelTTLSchedule(ctx context.Context, ttl *catpb.RowLevelTTL) error {
+	env := jobSchedulerEnv(p.ExecCfg())
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"delete-row-level-ttl-schedule",
+		p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE schedule_id = $1",
+			env.ScheduledJobsTableName(),
+		),
+		ttl.ScheduleID,
+	)
+	return err
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
 	"github.com/cockroachdb/errors"
 )
@@ -592,8 +593,9 @@ func EncodeInvertedIndexPrefixKeys(
 }
 
 // EncodeInvertedIndexTableKeys produces one inverted index key per element in
-// the input datum, which should be a container (either JSON or Array). For
-// JSON, "element" means unique path through the document. Each output key is
+// the input datum, which should be a container (either JSON, Array, or
+// TSVector). For JSON, "element" means unique path through the document, and
+// for TSVector it means each distinct lexeme. Each output key is
 // prefixed by inKey, and is guaranteed to be lexicographically sortable, but
 // not guaranteed to be round-trippable during decoding. If the input Datum
 // is (SQL) NULL, no inverted index keys will be produced, because inverted
@@ -619,6 +621,8 @@ func EncodeInvertedIndexTableKeys(
 		return json.EncodeInvertedIndexKeys(inKey, val.(*tree.DJSON).JSON)
 	case types.ArrayFamily:
 		return encodeArrayInvertedIndexTableKeys(val.(*tree.DArray), inKey, version, false /* excludeNulls */)
+	case types.TSVectorFamily:
+		return tsearch.EncodeInvertedIndexKeys(inKey, val.(*tree.DTSVector).TSVector), nil
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType())
 }
@@ -46,6 +46,8 @@ func Decode(
 	switch valType.Family() {
 	case types.ArrayFamily:
 		return decodeArrayKey(a, valType, key, dir)
+	case types.RangeFamily:
+		return decodeRangeKey(a, valType, key, dir)
 	case types.BitFamily:
 		var r bitarray.BitArray
 		if dir == encoding.Ascending {
@@ -116,9 +118,9 @@ func Decode(
 		}
 		d, err := a.NewDCollatedString(r, valType.Locale())
 		return d, rkey, err
-	case types.JsonFamily:
-		// Don't attempt to decode the JSON value. Instead, just return the
-		// remaining bytes of the key.
+	case types.JsonFamily, types.TSVectorFamily:
+		// Don't attempt to decode the JSON or TSVector value. Instead, just
+		// return the remaining bytes of the key.
 		jsonLen, err := encoding.PeekLength(key)
 		if err != nil {
 			return nil, nil, err
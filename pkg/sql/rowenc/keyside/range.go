@@ -0,0 +1,141 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package keyside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// Range bound markers used inside the ordered encoding of a range. They are
+// chosen so that the encoding sorts the same way as tree.DRange.Compare:
+// empty ranges first, then by lower bound (with -infinity first and, for
+// equal values, inclusive before exclusive), then by upper bound (with
+// +infinity last and, for equal values, exclusive before inclusive).
+const (
+	rangeEmptyMarker    = 0x00
+	rangeNonEmptyMarker = 0x01
+
+	rangeLowerInfMarker    = 0x00
+	rangeBoundFiniteMarker = 0x01
+	rangeUpperInfMarker    = 0x02
+
+	rangeLowerInclusive = 0x00
+	rangeLowerExclusive = 0x01
+	rangeUpperExclusive = 0x00
+	rangeUpperInclusive = 0x01
+)
+
+// encodeRangeKey generates an ordered key encoding of a range. The bounds are
+// first encoded in ascending order into an inner byte string of the form
+// [nonEmptyMarker, lower, upper], where each finite bound is
+// [finiteMarker, enc(value), inclusivity] and infinite bounds are a single
+// marker byte. The inner byte string is then encoded as bytes in the
+// requested direction, which keeps the encoding self-delimiting.
+func encodeRangeKey(b []byte, r *tree.DRange, dir encoding.Direction) ([]byte, error) {
+	inner := make([]byte, 0, 16)
+	if r.Empty {
+		inner = append(inner, rangeEmptyMarker)
+	} else {
+		var err error
+		inner = append(inner, rangeNonEmptyMarker)
+		if r.Lower.IsInf() {
+			inner = append(inner, rangeLowerInfMarker)
+		} else {
+			inner = append(inner, rangeBoundFiniteMarker)
+			if inner, err = Encode(inner, r.Lower.Val, encoding.Ascending); err != nil {
+				return nil, err
+			}
+			if r.Lower.Inclusive {
+				inner = append(inner, rangeLowerInclusive)
+			} else {
+				inner = append(inner, rangeLowerExclusive)
+			}
+		}
+		if r.Upper.IsInf() {
+			inner = append(inner, rangeUpperInfMarker)
+		} else {
+			inner = append(inner, rangeBoundFiniteMarker)
+			if inner, err = Encode(inner, r.Upper.Val, encoding.Ascending); err != nil {
+				return nil, err
+			}
+			if r.Upper.Inclusive {
+				inner = append(inner, rangeUpperInclusive)
+			} else {
+				inner = append(inner, rangeUpperExclusive)
+			}
+		}
+	}
+	if dir == encoding.Ascending {
+		return encoding.EncodeBytesAscending(b, inner), nil
+	}
+	return encoding.EncodeBytesDescending(b, inner), nil
+}
+
+// decodeRangeKey decodes a range key generated by encodeRangeKey.
+func decodeRangeKey(
+	a *tree.DatumAlloc, t *types.T, key []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	var inner []byte
+	var err error
+	if dir == encoding.Ascending {
+		key, inner, err = encoding.DecodeBytesAscending(key, nil)
+	} else {
+		key, inner, err = encoding.DecodeBytesDescending(key, nil)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(inner) == 0 {
+		return nil, nil, errors.AssertionFailedf("invalid range encoding (empty)")
+	}
+	if inner[0] == rangeEmptyMarker {
+		return tree.NewDEmptyRange(t), key, nil
+	}
+	inner = inner[1:]
+
+	decodeBound := func(infMarker, inclusiveMarker byte) (tree.RangeBound, error) {
+		var bound tree.RangeBound
+		if len(inner) == 0 {
+			return bound, errors.AssertionFailedf("invalid range encoding (missing bound)")
+		}
+		if inner[0] == infMarker {
+			inner = inner[1:]
+			return bound, nil
+		}
+		var err error
+		bound.Val, inner, err = Decode(a, t.RangeContents(), inner[1:], encoding.Ascending)
+		if err != nil {
+			return bound, err
+		}
+		if len(inner) == 0 {
+			return bound, errors.AssertionFailedf("invalid range encoding (missing inclusivity)")
+		}
+		bound.Inclusive = inner[0] == inclusiveMarker
+		inner = inner[1:]
+		return bound, nil
+	}
+	lower, err := decodeBound(rangeLowerInfMarker, rangeLowerInclusive)
+	if err != nil {
+		return nil, nil, err
+	}
+	upper, err := decodeBound(rangeUpperInfMarker, rangeUpperInclusive)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := tree.NewDRange(t, lower, upper)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, key, nil
+}
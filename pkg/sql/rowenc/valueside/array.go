@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -227,6 +228,12 @@ func DatumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 		return encoding.IPAddr, nil
 	case types.JsonFamily:
 		return encoding.JSON, nil
+	case types.TSQueryFamily:
+		return encoding.TSQuery, nil
+	case types.TSVectorFamily:
+		return encoding.TSVector, nil
+	case types.RangeFamily:
+		return encoding.Range, nil
 	case types.TupleFamily:
 		return encoding.Tuple, nil
 	default:
@@ -305,6 +312,16 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 			return nil, err
 		}
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeUntaggedBytesValue(b, tsearch.EncodeTSQuery(nil, t.TSQuery)), nil
+	case *tree.DTSVector:
+		return encoding.EncodeUntaggedBytesValue(b, tsearch.EncodeTSVector(nil, t.TSVector)), nil
+	case *tree.DRange:
+		encoded, err := encodeRange(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
 	case *tree.DTuple:
 		return encodeUntaggedTuple(t, b, encoding.NoColumnID, nil)
 	default:
@@ -0,0 +1,91 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package valueside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Range flags, matching the flags byte of the Postgres range representation.
+const (
+	rangeEmpty    = 0x01
+	rangeLowerInc = 0x02
+	rangeUpperInc = 0x04
+	rangeLowerInf = 0x08
+	rangeUpperInf = 0x10
+)
+
+// encodeRange produces the value encoding for a range, without a value tag or
+// length prefix. The encoding is a flags byte followed by the untagged
+// encoding of each finite bound.
+func encodeRange(d *tree.DRange, scratch []byte) ([]byte, error) {
+	var flags byte
+	switch {
+	case d.Empty:
+		flags |= rangeEmpty
+	default:
+		if d.Lower.IsInf() {
+			flags |= rangeLowerInf
+		} else if d.Lower.Inclusive {
+			flags |= rangeLowerInc
+		}
+		if d.Upper.IsInf() {
+			flags |= rangeUpperInf
+		} else if d.Upper.Inclusive {
+			flags |= rangeUpperInc
+		}
+	}
+	b := append(scratch[:0], flags)
+	if d.Empty {
+		return b, nil
+	}
+	var err error
+	if !d.Lower.IsInf() {
+		if b, err = encodeArrayElement(b, d.Lower.Val); err != nil {
+			return nil, err
+		}
+	}
+	if !d.Upper.IsInf() {
+		if b, err = encodeArrayElement(b, d.Upper.Val); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// decodeRange decodes a range from the encoding produced by encodeRange.
+func decodeRange(a *tree.DatumAlloc, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) == 0 {
+		return nil, errors.AssertionFailedf("invalid range encoding (empty)")
+	}
+	flags := b[0]
+	b = b[1:]
+	if flags&rangeEmpty != 0 {
+		return tree.NewDEmptyRange(t), nil
+	}
+	var lower, upper tree.RangeBound
+	var err error
+	if flags&rangeLowerInf == 0 {
+		if lower.Val, b, err = DecodeUntaggedDatum(a, t.RangeContents(), b); err != nil {
+			return nil, err
+		}
+		lower.Inclusive = flags&rangeLowerInc != 0
+	}
+	if flags&rangeUpperInf == 0 {
+		if upper.Val, _, err = DecodeUntaggedDatum(a, t.RangeContents(), b); err != nil {
+			return nil, err
+		}
+		upper.Inclusive = flags&rangeUpperInc != 0
+	}
+	return tree.NewDRange(t, lower, upper)
+}
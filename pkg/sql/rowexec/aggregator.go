@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -70,6 +71,11 @@ type aggregatorBase struct {
 	orderedGroupCols []uint32
 	aggregations     []execinfrapb.AggregatorSpec_Aggregation
 
+	// groupingSets is set if the aggregator computes multiple grouping sets.
+	// In that case, every input row is expanded into one row per grouping set
+	// (see groupingSetsExpander.expand).
+	groupingSets groupingSetsExpander
+
 	lastOrdGroupCols rowenc.EncDatumRow
 	arena            stringarena.Arena
 	row              rowenc.EncDatumRow
@@ -103,6 +109,7 @@ func (ag *aggregatorBase) init(
 	ag.groupCols = spec.GroupCols
 	ag.orderedGroupCols = spec.OrderedGroupCols
 	ag.aggregations = spec.Aggregations
+	ag.groupingSets.init(spec)
 	ag.funcs = make([]*aggregateFuncHolder, len(spec.Aggregations))
 	ag.outputTypes = make([]*types.T, len(spec.Aggregations))
 	ag.row = make(rowenc.EncDatumRow, len(spec.Aggregations))
@@ -257,7 +264,7 @@ func newAggregator(
 	if spec.IsRowCount() {
 		return newCountAggregator(flowCtx, processorID, input, post, output)
 	}
-	if len(spec.OrderedGroupCols) == len(spec.GroupCols) {
+	if len(spec.OrderedGroupCols) == len(spec.GroupCols) && !spec.HasGroupingSets() {
 		return newOrderedAggregator(flowCtx, processorID, spec, input, post, output)
 	}
 
@@ -434,6 +441,13 @@ func (ag *hashAggregator) accumulateRows() (
 				break
 			}
 		}
+		if ag.groupingSets.enabled() {
+			if err := ag.groupingSets.expand(row, ag.accumulateRow); err != nil {
+				ag.MoveToDraining(err)
+				return aggStateUnknown, nil, nil
+			}
+			continue
+		}
 		if err := ag.accumulateRow(row); err != nil {
 			ag.MoveToDraining(err)
 			return aggStateUnknown, nil, nil
@@ -451,6 +465,15 @@ func (ag *hashAggregator) accumulateRows() (
 		ag.buckets[""] = bucket
 	}
 
+	// Similarly, every empty grouping set produces a row even if nothing was
+	// aggregated.
+	if len(ag.buckets) < 1 && ag.groupingSets.enabled() {
+		if err := ag.createEmptyGroupingSetBuckets(); err != nil {
+			ag.MoveToDraining(err)
+			return aggStateUnknown, nil, nil
+		}
+	}
+
 	// Note that, for simplicity, we're ignoring the overhead of the slice of
 	// strings.
 	if err := ag.bucketsAcc.Grow(ag.Ctx, int64(len(ag.buckets))*memsize.String); err != nil {
@@ -855,6 +878,42 @@ func (ag *hashAggregator) accumulateRow(row rowenc.EncDatumRow) error {
 	return ag.accumulateRowIntoBucket(row, encoded, bucket)
 }
 
+// createEmptyGroupingSetBuckets creates a bucket for each empty grouping set.
+// It must only be called if there were no input rows. The grouping set ID
+// column is the only group column that is not NULL for these buckets, so the
+// ANY_NOT_NULL aggregations over it are fed the ID of the set.
+func (ag *hashAggregator) createEmptyGroupingSetBuckets() error {
+	for i := range ag.groupingSets.sets {
+		if len(ag.groupingSets.sets[i].Cols) > 0 {
+			continue
+		}
+		row := ag.groupingSets.emptySetRow(i, len(ag.inputTypes))
+		encoded, err := ag.encode(ag.scratch, row)
+		if err != nil {
+			return err
+		}
+		ag.scratch = encoded[:0]
+		s, err := ag.arena.AllocBytes(ag.Ctx, encoded)
+		if err != nil {
+			return err
+		}
+		bucket, err := ag.createAggregateFuncs()
+		if err != nil {
+			return err
+		}
+		for j, a := range ag.aggregations {
+			if a.Func == execinfrapb.AnyNotNull && len(a.ColIdx) == 1 &&
+				a.ColIdx[0] == ag.groupingSets.idCol {
+				if err := bucket[j].Add(ag.Ctx, row[a.ColIdx[0]].Datum); err != nil {
+					return err
+				}
+			}
+		}
+		ag.buckets[s] = bucket
+	}
+	return nil
+}
+
 // accumulateRow accumulates a single row, returning an error if accumulation
 // failed for any reason.
 func (ag *orderedAggregator) accumulateRow(row rowenc.EncDatumRow) error {
@@ -873,6 +932,81 @@ func (ag *orderedAggregator) accumulateRow(row rowenc.EncDatumRow) error {
 	return ag.accumulateRowIntoBucket(row, nil /* groupKey */, ag.bucket)
 }
 
+// groupingSetsExpander expands the input rows of an aggregator that computes
+// multiple grouping sets into one row per grouping set.
+type groupingSetsExpander struct {
+	sets []execinfrapb.AggregatorSpec_GroupingSet
+	// idCol is the group column that identifies the grouping set.
+	idCol uint32
+	// nullCols contains, for each grouping set, the group columns (other than
+	// idCol) that are not part of the set and thus have to be set to NULL.
+	nullCols [][]uint32
+	// ids contains the encoded ID of each grouping set.
+	ids     []rowenc.EncDatum
+	scratch rowenc.EncDatumRow
+}
+
+func (e *groupingSetsExpander) init(spec *execinfrapb.AggregatorSpec) {
+	if !spec.HasGroupingSets() {
+		return
+	}
+	e.sets = spec.GroupingSets
+	e.idCol = spec.GroupingSetIDCol
+	e.nullCols = make([][]uint32, len(e.sets))
+	e.ids = make([]rowenc.EncDatum, len(e.sets))
+	for i := range e.sets {
+		var inSet util.FastIntSet
+		for _, c := range e.sets[i].Cols {
+			inSet.Add(int(c))
+		}
+		for _, c := range spec.GroupCols {
+			if c != e.idCol && !inSet.Contains(int(c)) {
+				e.nullCols[i] = append(e.nullCols[i], c)
+			}
+		}
+		e.ids[i] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
+	}
+}
+
+// enabled returns true if the aggregator computes multiple grouping sets.
+func (e *groupingSetsExpander) enabled() bool {
+	return len(e.sets) > 0
+}
+
+// expand calls accumulate once for each grouping set with a copy of the
+// given row in which the group columns that are not part of the set are NULL
+// and the grouping set ID column is set to the ID of the set.
+func (e *groupingSetsExpander) expand(
+	row rowenc.EncDatumRow, accumulate func(rowenc.EncDatumRow) error,
+) error {
+	if e.scratch == nil {
+		e.scratch = make(rowenc.EncDatumRow, len(row))
+	}
+	for i := range e.sets {
+		copy(e.scratch, row)
+		for _, c := range e.nullCols[i] {
+			e.scratch[c] = rowenc.EncDatum{Datum: tree.DNull}
+		}
+		e.scratch[e.idCol] = e.ids[i]
+		if err := accumulate(e.scratch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emptySetRow returns a row of the given width in which all columns except
+// for the grouping set ID column (which is set to the ID of the given empty
+// grouping set) are NULL.
+func (e *groupingSetsExpander) emptySetRow(setIdx int, width int) rowenc.EncDatumRow {
+	row := make(rowenc.EncDatumRow, width)
+	for i := range row {
+		row[i] = rowenc.EncDatum{Datum: tree.DNull}
+	}
+	row[e.idCol] = e.ids[setIdx]
+	return row
+}
+
 type aggregateFuncHolder struct {
 	create func(*tree.EvalContext, tree.Datums) tree.AggregateFunc
 
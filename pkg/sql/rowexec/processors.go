@@ -250,6 +250,15 @@ func NewProcessor(
 		}
 		return NewBackupDataProcessor(flowCtx, processorID, *core.BackupData, post, outputs[0])
 	}
+	if core.VerifyBackupData != nil {
+		if err := checkNumInOut(inputs, outputs, 0, 1); err != nil {
+			return nil, err
+		}
+		if NewVerifyBackupDataProcessor == nil {
+			return nil, errors.New("VerifyBackupData processor unimplemented")
+		}
+		return NewVerifyBackupDataProcessor(flowCtx, processorID, *core.VerifyBackupData, post, outputs[0])
+	}
 	if core.SplitAndScatter != nil {
 		if err := checkNumInOut(inputs, outputs, 0, 1); err != nil {
 			return nil, err
@@ -296,6 +305,15 @@ func NewProcessor(
 		return NewParquetWriterProcessor(flowCtx, processorID, *core.ParquetWriter, inputs[0],
 			outputs[0])
 	}
+	if core.JSONWriter != nil {
+		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
+			return nil, err
+		}
+		if NewJSONWriterProcessor == nil {
+			return nil, errors.New("JSONWriter processor unimplemented")
+		}
+		return NewJSONWriterProcessor(flowCtx, processorID, *core.JSONWriter, inputs[0], outputs[0])
+	}
 	if core.BulkRowWriter != nil {
 		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
 			return nil, err
@@ -385,6 +403,9 @@ var NewReadImportDataProcessor func(*execinfra.FlowCtx, int32, execinfrapb.ReadI
 // NewBackupDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewBackupDataProcessor func(*execinfra.FlowCtx, int32, execinfrapb.BackupDataSpec, *execinfrapb.PostProcessSpec, execinfra.RowReceiver) (execinfra.Processor, error)
 
+// NewVerifyBackupDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewVerifyBackupDataProcessor func(*execinfra.FlowCtx, int32, execinfrapb.VerifyBackupDataSpec, *execinfrapb.PostProcessSpec, execinfra.RowReceiver) (execinfra.Processor, error)
+
 // NewSplitAndScatterProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewSplitAndScatterProcessor func(*execinfra.FlowCtx, int32, execinfrapb.SplitAndScatterSpec, *execinfrapb.PostProcessSpec, execinfra.RowReceiver) (execinfra.Processor, error)
 
@@ -400,6 +421,9 @@ var NewCSVWriterProcessor func(*execinfra.FlowCtx, int32, execinfrapb.CSVWriterS
 // NewParquetWriterProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewParquetWriterProcessor func(*execinfra.FlowCtx, int32, execinfrapb.ParquetWriterSpec, execinfra.RowSource, execinfra.RowReceiver) (execinfra.Processor, error)
 
+// NewJSONWriterProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewJSONWriterProcessor func(*execinfra.FlowCtx, int32, execinfrapb.JSONWriterSpec, execinfra.RowSource, execinfra.RowReceiver) (execinfra.Processor, error)
+
 // NewChangeAggregatorProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewChangeAggregatorProcessor func(*execinfra.FlowCtx, int32, execinfrapb.ChangeAggregatorSpec, *execinfrapb.PostProcessSpec, execinfra.RowReceiver) (execinfra.Processor, error)
 
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -129,3 +130,26 @@ func checkIfDescOrElementAreDropped(b BuildCtx, id descpb.ID) bool {
 	})
 	return matches
 }
+
+// panicIfReferencedByFunctions falls back to the legacy schema changer when
+// user-defined functions depend on the descriptor which is being dropped, or
+// when it is a view which calls user-defined functions, since functions are
+// not yet supported by the declarative schema changer.
+func panicIfReferencedByFunctions(desc catalog.Descriptor) {
+	var hasFunctionRefs bool
+	switch t := desc.(type) {
+	case catalog.TableDescriptor:
+		hasFunctionRefs = len(t.GetDependedOnByFunctions()) > 0 || len(t.GetDependsOnFunctions()) > 0
+	case catalog.TypeDescriptor:
+		hasFunctionRefs = len(t.GetReferencingFunctionIDs()) > 0
+	case catalog.SchemaDescriptor:
+		onErrPanic(t.ForEachFunction(func(descpb.SchemaDescriptor_Function) error {
+			hasFunctionRefs = true
+			return iterutil.StopIteration()
+		}))
+	}
+	if hasFunctionRefs {
+		panic(scerrors.NotImplementedErrorf(nil,
+			"%s %q references or is referenced by user-defined functions", desc.DescriptorType(), desc.GetName()))
+	}
+}
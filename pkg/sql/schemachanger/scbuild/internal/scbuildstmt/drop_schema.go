@@ -41,6 +41,7 @@ func dropSchema(
 	sc catalog.SchemaDescriptor,
 	behavior tree.DropBehavior,
 ) (nodeAdded bool, dropIDs catalog.DescriptorIDSet) {
+	panicIfReferencedByFunctions(sc)
 	descsThatNeedElements := catalog.DescriptorIDSet{}
 	_, objectIDs := b.CatalogReader().ReadObjectNamesAndIDs(b, db, sc)
 	for _, id := range objectIDs {
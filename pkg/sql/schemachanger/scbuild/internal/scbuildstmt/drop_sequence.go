@@ -44,6 +44,7 @@ func DropSequence(b BuildCtx, n *tree.DropSequence) {
 // dropSequence builds targets and transformations using a descriptor.
 func dropSequence(b BuildCtx, seq catalog.TableDescriptor, cascade tree.DropBehavior) {
 	onErrPanic(b.AuthorizationAccessor().CheckPrivilege(b, seq, privilege.DROP))
+	panicIfReferencedByFunctions(seq)
 	// Add a node to drop the sequence
 	decomposeTableDescToElements(b, seq, scpb.Status_ABSENT)
 	// Check if there are dependencies.
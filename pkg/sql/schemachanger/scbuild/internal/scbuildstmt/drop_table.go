@@ -67,6 +67,7 @@ func dropTable(b BuildCtx, tbl catalog.TableDescriptor, behavior tree.DropBehavi
 // any objects that may need to be dealt with when cascading. The BuildCtx for
 // cascaded drops is returned.
 func dropTableBasic(b BuildCtx, tbl catalog.TableDescriptor) BuildCtx {
+	panicIfReferencedByFunctions(tbl)
 	decomposeTableDescToElements(b, tbl, scpb.Status_ABSENT)
 	return b.WithNewSourceElementID()
 }
@@ -56,11 +56,14 @@ func dropType(b BuildCtx, typ catalog.TypeDescriptor, behavior tree.DropBehavior
 		return
 	case descpb.TypeDescriptor_ENUM:
 		sqltelemetry.IncrementEnumCounter(sqltelemetry.EnumDrop)
+	case descpb.TypeDescriptor_DOMAIN:
+		// Domains are dropped along with their array type, like enums.
 	default:
 		panic(errors.AssertionFailedf("unexpected kind %s for type %q", typ.GetKind(), typ.GetName()))
 	}
 	canDrop := func(desc catalog.TypeDescriptor) {
 		b.MustOwn(desc)
+		panicIfReferencedByFunctions(desc)
 		if desc.NumReferencingDescriptors() > 0 && behavior != tree.DropCascade {
 			dependentNames := make([]string, 0, desc.NumReferencingDescriptors())
 			for i := 0; i < desc.NumReferencingDescriptors(); i++ {
@@ -72,6 +72,7 @@ func dropView(b BuildCtx, view catalog.TableDescriptor, behavior tree.DropBehavi
 // any objects that may need to be dealt with when cascading. The BuildCtx for
 // cascaded drops is returned.
 func dropViewBasic(b BuildCtx, view catalog.TableDescriptor) BuildCtx {
+	panicIfReferencedByFunctions(view)
 	decomposeTableDescToElements(b, view, scpb.Status_ABSENT)
 	return b.WithNewSourceElementID()
 }
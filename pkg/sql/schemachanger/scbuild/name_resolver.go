@@ -89,7 +89,7 @@ func (b buildCtx) ResolveType(
 			pgerror.Newf(pgcode.DependentObjectsStillExist,
 				"%q is a multi-region enum and cannot be modified directly", typ.GetName()),
 			"try ALTER DATABASE %s DROP REGION %s", prefix.Database.GetName(), typ.GetName()))
-	case descpb.TypeDescriptor_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_DOMAIN:
 		b.MustOwn(typ)
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		// Implicit record types are not directly modifiable.
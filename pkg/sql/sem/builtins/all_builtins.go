@@ -39,6 +39,8 @@ func init() {
 	initPGBuiltins()
 	initMathBuiltins()
 	initReplicationBuiltins()
+	initTSearchBuiltins()
+	initRangeBuiltins()
 
 	AllBuiltinNames = make([]string, 0, len(builtins))
 	AllAggregateBuiltinNames = make([]string, 0, len(aggregates))
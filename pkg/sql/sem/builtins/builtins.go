@@ -30,6 +30,7 @@ import (
 	"math/rand"
 	"net"
 	"regexp/syntax"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -97,7 +98,9 @@ var (
 		"input value must be <= %d (maximum Unicode code point)", utf8.MaxRune)
 	errStringTooLarge = pgerror.Newf(pgcode.ProgramLimitExceeded,
 		"requested length too large, exceeds %s", humanizeutil.IBytes(maxAllocatedStringSize))
-	errInvalidNull = pgerror.New(pgcode.InvalidParameterValue, "input cannot be NULL")
+	errInvalidNull  = pgerror.New(pgcode.InvalidParameterValue, "input cannot be NULL")
+	errGroupingArgs = pgerror.New(pgcode.Grouping,
+		"arguments to GROUPING must be grouping expressions of the associated query level")
 	// SequenceNameArg represents the name of sequence (string) arguments in
 	// builtin functions.
 	SequenceNameArg = "sequence_name"
@@ -124,6 +127,7 @@ const (
 	categoryJSON                = "JSONB"
 	categoryMultiRegion         = "Multi-region"
 	categoryMultiTenancy        = "Multi-tenancy"
+	categoryRange               = "Range"
 	categorySequences           = "Sequence"
 	categorySpatial             = "Spatial"
 	categoryString              = "String and byte"
@@ -2395,6 +2399,24 @@ var builtins = map[string]builtinDefinition{
 		},
 	),
 
+	// grouping is replaced by the optimizer with an expression over the
+	// grouping set identifier of the enclosing GROUP BY, so it is never
+	// evaluated directly.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{NullableArgs: true},
+		tree.Overload{
+			Types:      tree.VariadicType{VarType: types.Any},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return nil, errGroupingArgs
+			},
+			Info: "Returns a bit mask indicating which of its arguments are not " +
+				"included in the grouping set of the current row. The rightmost " +
+				"argument corresponds to the least significant bit.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	// Timestamp/Date functions.
 
 	"experimental_strftime": makeBuiltin(
@@ -3670,33 +3692,16 @@ value if you rely on the HLC for accuracy.`,
 	})),
 
 	// Full text search functions.
-	"ts_match_qv":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_match_vq":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsvector_cmp":                   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsvector_concat":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_debug":                       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_headline":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_lexize":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"websearch_to_tsquery":           makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"array_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"get_current_ts_config":          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"numnode":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"plainto_tsquery":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"phraseto_tsquery":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"querytree":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"setweight":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"strip":                          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsquery":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsvector":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"json_to_tsvector":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"jsonb_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_delete":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_filter":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_rank":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_rank_cd":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_rewrite":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsquery_phrase":                 makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsvector_to_array":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsvector_update_trigger":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsvector_update_trigger_column": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 
@@ -5493,6 +5498,57 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	"crdb_internal.assert_domain_not_null": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.Any},
+				{"domain_name", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.Newf(pgcode.NotNullViolation,
+						"domain %s does not allow null values", tree.MustBeDString(args[1]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce the NOT NULL constraint of domain types.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"crdb_internal.assert_domain_check": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.Any},
+				{"ok", types.Bool},
+				{"domain_name", types.String},
+				{"constraint_name", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// As for table CHECK constraints, a NULL result satisfies the
+				// constraint.
+				if args[1] == tree.DBoolFalse {
+					return nil, pgerror.Newf(pgcode.CheckViolation,
+						"value for domain %s violates check constraint %q",
+						tree.MustBeDString(args[2]), tree.MustBeDString(args[3]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce the CHECK constraints of domain types.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	"crdb_internal.round_decimal_values": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -6246,10 +6302,10 @@ table's zone configuration this will return NULL.`,
 					)
 				}
 
-				if evalCtx.PreparedStatementState.HasPrepared() {
+				if evalCtx.PreparedStatementState.HasActivePortals() {
 					return nil, pgerror.Newf(
 						pgcode.InvalidTransactionState,
-						"cannot serialize a session which has portals or prepared statements",
+						"cannot serialize a session which has portals",
 					)
 				}
 
@@ -6272,6 +6328,10 @@ table's zone configuration this will return NULL.`,
 				m.SessionData = sd.SessionData
 				sessiondata.MarshalNonLocal(sd, &m.SessionData)
 				m.LocalOnlySessionData = sd.LocalOnlySessionData
+				m.PreparedStatements = evalCtx.PreparedStatementState.MigratablePreparedStatements()
+				sort.Slice(m.PreparedStatements, func(i, j int) bool {
+					return m.PreparedStatements[i].Name < m.PreparedStatements[j].Name
+				})
 
 				b, err := protoutil.Marshal(&m)
 				if err != nil {
@@ -6280,7 +6340,8 @@ table's zone configuration this will return NULL.`,
 
 				return tree.NewDBytes(tree.DBytes(b)), nil
 			},
-			Info:       `This function serializes the variables in the current session.`,
+			Info: `This function serializes the variables and the prepared statements ` +
+				`in the current session.`,
 			Volatility: tree.VolatilityVolatile,
 		},
 	),
@@ -6323,7 +6384,23 @@ table's zone configuration this will return NULL.`,
 				*evalCtx.SessionData() = *sd
 				return tree.MakeDBool(true), nil
 			},
-			Info:       `This function deserializes the serialized variables into the current session.`,
+			Info: `This function deserializes the serialized variables into the current session. ` +
+				`Prepared statements are not restored, and must be re-created by the caller.`,
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
+	"crdb_internal.create_session_revival_token": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return evalCtx.Planner.CreateSessionRevivalToken()
+			},
+			Info:       `Generate a token that can be used to create a new session for the current user.`,
 			Volatility: tree.VolatilityVolatile,
 		},
 	),
@@ -8589,7 +8666,8 @@ func asJSONBuildObjectKey(
 		), nil
 	case *tree.DBool, *tree.DInt, *tree.DFloat, *tree.DDecimal, *tree.DTimestamp,
 		*tree.DDate, *tree.DUuid, *tree.DInterval, *tree.DBytes, *tree.DIPAddr, *tree.DOid,
-		*tree.DTime, *tree.DTimeTZ, *tree.DBitArray, *tree.DGeography, *tree.DGeometry, *tree.DBox2D:
+		*tree.DTime, *tree.DTimeTZ, *tree.DBitArray, *tree.DGeography, *tree.DGeometry, *tree.DBox2D,
+		*tree.DTSQuery, *tree.DTSVector:
 		return tree.AsStringWithFlags(d, tree.FmtBareStrings), nil
 	default:
 		return "", errors.AssertionFailedf("unexpected type %T for key value", d)
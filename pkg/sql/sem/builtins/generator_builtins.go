@@ -175,6 +175,23 @@ var generators = map[string]builtinDefinition{
 		),
 	),
 
+	// See https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION-TABLE
+	"pg_listening_channels": makeBuiltin(
+		tree.FunctionProperties{
+			Class:            tree.GeneratorClass,
+			Category:         categoryGenerator,
+			DistsqlBlocklist: true,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{},
+			types.String,
+			makeListeningChannelsGenerator,
+			"Produces a virtual table containing the names of the channels the current "+
+				"session is listening on.",
+			tree.VolatilityVolatile,
+		),
+	),
+
 	"regexp_split_to_table": makeBuiltin(
 		genProps(),
 		makeGeneratorOverload(
@@ -852,6 +869,18 @@ func makeArrayGenerator(_ *tree.EvalContext, args tree.Datums) (tree.ValueGenera
 	return &arrayValueGenerator{array: arr}, nil
 }
 
+func makeListeningChannelsGenerator(
+	ctx *tree.EvalContext, _ tree.Datums,
+) (tree.ValueGenerator, error) {
+	arr := tree.NewDArray(types.String)
+	for _, channel := range ctx.Planner.ListeningChannels() {
+		if err := arr.Append(tree.NewDString(channel)); err != nil {
+			return nil, err
+		}
+	}
+	return &arrayValueGenerator{array: arr}, nil
+}
+
 // arrayValueGenerator is a value generator that returns each element of an
 // array.
 type arrayValueGenerator struct {
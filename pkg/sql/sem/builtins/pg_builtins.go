@@ -1410,6 +1410,34 @@ SELECT description
 		},
 	),
 
+	// https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-NOTIFY
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlocklist: true,
+			NullableArgs:     true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.SendNotification(ctx.Ctx(), channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
+			},
+			Info: "Sends an asynchronous notification with the given payload on the given " +
+				"channel to the sessions listening on it, once the current transaction commits.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	// pg_is_in_recovery returns true if the Postgres database is currently in
 	// recovery.  This is not applicable so this can always return false.
 	// https://www.postgresql.org/docs/current/static/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
@@ -0,0 +1,194 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func initRangeBuiltins() {
+	// Add a constructor function for each range type, named after the type.
+	for _, typ := range types.Ranges {
+		rangeBuiltins[typ.Name()] = makeRangeConstructorBuiltin(typ)
+	}
+
+	// Add all rangeBuiltins to the Builtins map after a sanity check.
+	for k, v := range rangeBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+
+	// lower and upper are also defined for ranges, where they return the
+	// corresponding bound.
+	for _, name := range []string{"lower", "upper"} {
+		isLower := name == "lower"
+		info := "Returns the upper bound of `range`, or NULL if it is empty or " +
+			"the upper bound is infinite."
+		if isLower {
+			info = "Returns the lower bound of `range`, or NULL if it is empty or " +
+				"the lower bound is infinite."
+		}
+		def := builtins[name]
+		def.overloads = append(def.overloads, tree.Overload{
+			Types:      tree.ArgTypes{{"range", types.AnyRange}},
+			ReturnType: rangeContentsReturnType,
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				r := tree.MustBeDRange(args[0])
+				bound := r.Upper
+				if isLower {
+					bound = r.Lower
+				}
+				if r.Empty || bound.IsInf() {
+					return tree.DNull, nil
+				}
+				return bound.Val, nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		})
+		builtins[name] = def
+	}
+}
+
+// rangeBuiltins contains the range built-in functions indexed by name. The
+// range constructors are added to it by initRangeBuiltins.
+//
+// For use in other packages, see AllBuiltinNames and GetBuiltinProperties().
+var rangeBuiltins = map[string]builtinDefinition{
+	"isempty": makeRangeBoolBuiltin(
+		"Returns whether `range` is empty.",
+		func(r *tree.DRange) bool { return r.Empty },
+	),
+	"lower_inc": makeRangeBoolBuiltin(
+		"Returns whether the lower bound of `range` is inclusive.",
+		func(r *tree.DRange) bool { return !r.Empty && r.Lower.Inclusive },
+	),
+	"upper_inc": makeRangeBoolBuiltin(
+		"Returns whether the upper bound of `range` is inclusive.",
+		func(r *tree.DRange) bool { return !r.Empty && r.Upper.Inclusive },
+	),
+	"lower_inf": makeRangeBoolBuiltin(
+		"Returns whether the lower bound of `range` is infinite.",
+		func(r *tree.DRange) bool { return !r.Empty && r.Lower.IsInf() },
+	),
+	"upper_inf": makeRangeBoolBuiltin(
+		"Returns whether the upper bound of `range` is infinite.",
+		func(r *tree.DRange) bool { return !r.Empty && r.Upper.IsInf() },
+	),
+}
+
+// rangeContentsReturnType returns the subtype of the range passed as the first
+// argument, or anyelement if the range type is not known.
+func rangeContentsReturnType(args []tree.TypedExpr) *types.T {
+	if len(args) == 0 {
+		return tree.UnknownReturnType
+	}
+	if contents := args[0].ResolvedType().RangeContents(); contents != nil {
+		return contents
+	}
+	return types.Any
+}
+
+func makeRangeBoolBuiltin(info string, fn func(*tree.DRange) bool) builtinDefinition {
+	return makeBuiltin(tree.FunctionProperties{Category: categoryRange},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"range", types.AnyRange}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.MakeDBool(tree.DBool(fn(tree.MustBeDRange(args[0])))), nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// makeRangeConstructorBuiltin returns the constructor function for the given
+// range type. NULL bounds denote infinite bounds, so the function handles NULL
+// arguments itself.
+func makeRangeConstructorBuiltin(typ *types.T) builtinDefinition {
+	subtype := typ.RangeContents()
+	construct := func(args tree.Datums, bounds string) (tree.Datum, error) {
+		lowerInc, upperInc, err := parseRangeBoundFlags(bounds)
+		if err != nil {
+			return nil, err
+		}
+		lower := tree.RangeBound{Inclusive: lowerInc}
+		if args[0] != tree.DNull {
+			lower.Val = args[0]
+		}
+		upper := tree.RangeBound{Inclusive: upperInc}
+		if args[1] != tree.DNull {
+			upper.Val = args[1]
+		}
+		return tree.NewDRange(typ, lower, upper)
+	}
+	return makeBuiltin(tree.FunctionProperties{Category: categoryRange, NullableArgs: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", subtype}, {"upper", subtype}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return construct(args, "[)")
+			},
+			Info: "Constructs a range from `lower` and `upper`, with an inclusive lower " +
+				"bound and an exclusive upper bound. A NULL bound is infinite.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", subtype}, {"upper", subtype}, {"bounds", types.String}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[2] == tree.DNull {
+					return nil, pgerror.New(pgcode.NullValueNotAllowed,
+						"range constructor flags argument must not be null")
+				}
+				return construct(args, string(tree.MustBeDString(args[2])))
+			},
+			Info: "Constructs a range from `lower` and `upper`. `bounds` " +
+				"is one of `[]`, `[)`, `(]` or `()`, and specifies whether each bound " +
+				"is inclusive. A NULL bound is infinite.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// parseRangeBoundFlags parses the bounds argument of a range constructor.
+func parseRangeBoundFlags(bounds string) (lowerInc, upperInc bool, _ error) {
+	if len(bounds) == 2 {
+		switch bounds[0] {
+		case '[':
+			lowerInc = true
+		case '(':
+		default:
+			return false, false, invalidRangeBoundFlagsError
+		}
+		switch bounds[1] {
+		case ']':
+			upperInc = true
+		case ')':
+		default:
+			return false, false, invalidRangeBoundFlagsError
+		}
+		return lowerInc, upperInc, nil
+	}
+	return false, false, invalidRangeBoundFlagsError
+}
+
+var invalidRangeBoundFlagsError = errors.WithHint(
+	pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+	`Valid values are "[]", "[)", "(]", and "()".`,
+)
@@ -0,0 +1,395 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+func initTSearchBuiltins() {
+	// Add all tsearchBuiltins to the Builtins map after a sanity check.
+	for k, v := range tsearchBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+
+	// length is also defined for TSVector, where it returns the number of
+	// lexemes.
+	length := builtins["length"]
+	length.overloads = append(length.overloads, tree.Overload{
+		Types:      tree.ArgTypes{{"vector", types.TSVector}},
+		ReturnType: tree.FixedReturnType(types.Int),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return tree.NewDInt(tree.DInt(tree.MustBeDTSVector(args[0]).Len())), nil
+		},
+		Info:       "Returns the number of lexemes in `vector`.",
+		Volatility: tree.VolatilityImmutable,
+	})
+	builtins["length"] = length
+}
+
+// tsearchBuiltins contains the full text search built-in functions indexed by
+// name.
+//
+// For use in other packages, see AllBuiltinNames and GetBuiltinProperties().
+var tsearchBuiltins = map[string]builtinDefinition{
+	"to_tsvector": makeTSConfigBuiltin(
+		types.TSVector,
+		"Parses `text` into a tsvector, normalizing each word into a lexeme using "+
+			"the given text search configuration.",
+		func(c *tsearch.Config, input string) (tree.Datum, error) {
+			return tree.NewDTSVector(c.DocumentToTSVector(input)), nil
+		},
+	),
+	"to_tsquery": makeTSConfigBuiltin(
+		types.TSQuery,
+		"Parses `text` into a tsquery, normalizing each word into a lexeme using "+
+			"the given text search configuration. The input must follow tsquery "+
+			"syntax, with words separated by operators.",
+		func(c *tsearch.Config, input string) (tree.Datum, error) {
+			q, err := c.ToTSQuery(input)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
+		},
+	),
+	"plainto_tsquery": makeTSConfigBuiltin(
+		types.TSQuery,
+		"Converts `text` into a tsquery which matches all of its words, normalizing "+
+			"each word into a lexeme using the given text search configuration. "+
+			"Punctuation in the input is ignored.",
+		func(c *tsearch.Config, input string) (tree.Datum, error) {
+			return tree.NewDTSQuery(c.PlainToTSQuery(input)), nil
+		},
+	),
+	"phraseto_tsquery": makeTSConfigBuiltin(
+		types.TSQuery,
+		"Converts `text` into a tsquery which matches its words as a phrase, "+
+			"normalizing each word into a lexeme using the given text search "+
+			"configuration. Punctuation in the input is ignored.",
+		func(c *tsearch.Config, input string) (tree.Datum, error) {
+			return tree.NewDTSQuery(c.PhraseToTSQuery(input)), nil
+		},
+	),
+	"websearch_to_tsquery": makeTSConfigBuiltin(
+		types.TSQuery,
+		"Converts `text` into a tsquery using a syntax similar to that of web "+
+			"search engines: quoted text is matched as a phrase, `or` produces an "+
+			"OR operator, and a leading `-` produces a NOT operator. Each word is "+
+			"normalized into a lexeme using the given text search configuration.",
+		func(c *tsearch.Config, input string) (tree.Datum, error) {
+			return tree.NewDTSQuery(c.WebSearchToTSQuery(input)), nil
+		},
+	),
+
+	"ts_rank":    makeTSRankBuiltin(tsearch.Rank, "Ranks `vector` for the matches of `query` based on the frequency of its matching lexemes."),
+	"ts_rank_cd": makeTSRankBuiltin(tsearch.RankCD, "Ranks `vector` for the matches of `query` using cover density, which also takes the proximity of matching lexemes into account."),
+
+	"ts_match_vq": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0])
+				q := tree.MustBeDTSQuery(args[1])
+				return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+			},
+			Info:       "Returns whether `vector` matches `query`. Equivalent to `vector @@ query`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"ts_match_qv": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}, {"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				q := tree.MustBeDTSQuery(args[0])
+				v := tree.MustBeDTSVector(args[1])
+				return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+			},
+			Info:       "Returns whether `vector` matches `query`. Equivalent to `query @@ vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"tsvector_concat": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector1", types.TSVector}, {"vector2", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v1 := tree.MustBeDTSVector(args[0])
+				v2 := tree.MustBeDTSVector(args[1])
+				return tree.NewDTSVector(v1.Concat(v2.TSVector)), nil
+			},
+			Info: "Concatenates two tsvectors. The positions of `vector2` are offset by " +
+				"the largest position of `vector1`. Equivalent to `vector1 || vector2`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"tsvector_cmp": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector1", types.TSVector}, {"vector2", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v1 := tree.MustBeDTSVector(args[0])
+				v2 := tree.MustBeDTSVector(args[1])
+				return tree.NewDInt(tree.DInt(v1.Compare(v2.TSVector))), nil
+			},
+			Info:       "Returns -1, 0 or 1 depending on whether `vector1` sorts before, equal to or after `vector2`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"setweight": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0])
+				weighted, err := v.SetWeight(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(weighted), nil
+			},
+			Info:       "Assigns `weight` (one of A, B, C or D) to every position in `vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"strip": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).Strip()), nil
+			},
+			Info:       "Removes all positions and weights from `vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"tsvector_to_array": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				result := tree.NewDArray(types.String)
+				for _, lexeme := range tree.MustBeDTSVector(args[0]).Lexemes() {
+					if err := result.Append(tree.NewDString(lexeme)); err != nil {
+						return nil, err
+					}
+				}
+				return result, nil
+			},
+			Info:       "Returns the lexemes of `vector` as an array.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"array_to_tsvector": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lexemes", types.StringArray}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[0])
+				lexemes := make([]string, 0, arr.Len())
+				for _, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed, "lexeme array may not contain nulls")
+					}
+					lexemes = append(lexemes, string(tree.MustBeDString(d)))
+				}
+				v, err := tsearch.MakeTSVectorFromLexemes(lexemes)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(v), nil
+			},
+			Info:       "Converts an array of lexemes into a tsvector without positions.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"numnode": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDTSQuery(args[0]).NumNodes())), nil
+			},
+			Info:       "Returns the number of lexemes and operators in `query`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"tsquery_phrase": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query1", types.TSQuery}, {"query2", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsqueryPhrase(args[0], args[1], 1 /* distance */)
+			},
+			Info:       "Returns a tsquery which matches `query1` immediately followed by `query2`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"query1", types.TSQuery},
+				{"query2", types.TSQuery},
+				{"distance", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsqueryPhrase(args[0], args[1], int(tree.MustBeDInt(args[2])))
+			},
+			Info:       "Returns a tsquery which matches `query1` followed by `query2` at exactly `distance` positions apart.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+}
+
+// makeTSConfigBuiltin returns a builtin with two overloads: one which takes a
+// text search configuration name and the input text, and one which only takes
+// the input text and uses the default configuration.
+func makeTSConfigBuiltin(
+	returnType *types.T, info string, fn func(*tsearch.Config, string) (tree.Datum, error),
+) builtinDefinition {
+	return makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"text", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(tsearch.DefaultConfig)
+				if err != nil {
+					return nil, err
+				}
+				return fn(c, string(tree.MustBeDString(args[0])))
+			},
+			Info:       info + " The `" + tsearch.DefaultConfig + "` configuration is used.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"text", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return fn(c, string(tree.MustBeDString(args[1])))
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// makeTSRankBuiltin returns a builtin with the overloads of ts_rank and
+// ts_rank_cd, which differ only in their ranking function.
+func makeTSRankBuiltin(
+	rank func(weights [4]float32, v tsearch.TSVector, q tsearch.TSQuery, method int) (float32, error),
+	info string,
+) builtinDefinition {
+	eval := func(weights tree.Datum, vector, query tree.Datum, method int) (tree.Datum, error) {
+		w := tsearch.DefaultWeights
+		if weights != nil {
+			var err error
+			if w, err = tsWeightsFromArray(tree.MustBeDArray(weights)); err != nil {
+				return nil, err
+			}
+		}
+		r, err := rank(w, tree.MustBeDTSVector(vector).TSVector, tree.MustBeDTSQuery(query).TSQuery, method)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDFloat(tree.DFloat(r)), nil
+	}
+	const normInfo = " The rank is adjusted for the length of the document according to " +
+		"the `normalization` bit mask."
+	const weightsInfo = " The weights of lexemes with weights D, C, B and A are given by " +
+		"`weights`, in that order."
+	return makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return eval(nil /* weights */, args[0], args[1], 0 /* method */)
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return eval(nil /* weights */, args[0], args[1], int(tree.MustBeDInt(args[2])))
+			},
+			Info:       info + normInfo,
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return eval(args[0], args[1], args[2], 0 /* method */)
+			},
+			Info:       info + weightsInfo,
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return eval(args[0], args[1], args[2], int(tree.MustBeDInt(args[3])))
+			},
+			Info:       info + weightsInfo + normInfo,
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// tsWeightsFromArray converts the weights array argument of the ranking
+// functions into the weights used by tsearch.
+func tsWeightsFromArray(arr *tree.DArray) ([4]float32, error) {
+	weights := make([]float64, 0, arr.Len())
+	for _, d := range arr.Array {
+		if d == tree.DNull {
+			return [4]float32{}, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+		}
+		weights = append(weights, float64(tree.MustBeDFloat(d)))
+	}
+	return tsearch.MakeWeights(weights)
+}
+
+func tsqueryPhrase(left, right tree.Datum, distance int) (tree.Datum, error) {
+	q, err := tree.MustBeDTSQuery(left).FollowedBy(tree.MustBeDTSQuery(right).TSQuery, distance)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSQuery(q), nil
+}
@@ -64,9 +64,14 @@ type Backup struct {
 	Nested bool
 
 	// AppendToLatest is set to true if the user creates a backup with
-	//`BACKUP...INTO LATEST...`
+	//`BACKUP...INTO LATEST...`, or compacts the latest backup with `BACKUP
+	// COMPACT LATEST IN...`.
 	AppendToLatest bool
 
+	// Compact is set to true when the user compacts an existing chain of
+	// backups with `BACKUP COMPACT ... IN ...` syntax.
+	Compact bool
+
 	// Subdir may be set by the parser when the SQL query is of the form `BACKUP
 	// INTO 'subdir' IN...`. Alternatively, if Nested is true but a subdir was not
 	// explicitly specified by the user, then this will be set during BACKUP
@@ -83,7 +88,15 @@ func (node *Backup) Format(ctx *FmtCtx) {
 		ctx.FormatNode(node.Targets)
 		ctx.WriteString(" ")
 	}
-	if node.Nested {
+	if node.Compact {
+		ctx.WriteString("COMPACT ")
+		if node.Subdir != nil {
+			ctx.FormatNode(node.Subdir)
+			ctx.WriteString(" IN ")
+		} else if node.AppendToLatest {
+			ctx.WriteString("LATEST IN ")
+		}
+	} else if node.Nested {
 		ctx.WriteString("INTO ")
 		if node.Subdir != nil {
 			ctx.FormatNode(node.Subdir)
@@ -132,6 +145,8 @@ type RestoreOptions struct {
 	DebugPauseOn              Expr
 	NewDBName                 Expr
 	IncrementalStorage        StringOrPlaceholderOptList
+	Columns                   NameList
+	IntoExistingTable         bool
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -152,6 +167,10 @@ type Restore struct {
 	AsOf    AsOfClause
 	Options RestoreOptions
 
+	// Where, if set, restricts the restore of a single table to the rows for
+	// which the predicate evaluates to true.
+	Where Expr
+
 	// Subdir may be set by the parser when the SQL query is of the form `RESTORE
 	// ... FROM 'from' IN 'subdir'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM 'from' IN LATEST...`
@@ -182,12 +201,40 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.AsOf)
 	}
+	if node.Where != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Where)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
 }
 
+// VerifyBackup represents a VERIFY BACKUP statement, which checks that the
+// files of a backup chain are present and readable without restoring it.
+type VerifyBackup struct {
+	// Subdir is the subdirectory of the backup in the collection, which may be
+	// LATEST.
+	Subdir  Expr
+	From    StringOrPlaceholderOptList
+	Options KVOptions
+}
+
+var _ Statement = &VerifyBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *VerifyBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("VERIFY BACKUP ")
+	ctx.FormatNode(node.Subdir)
+	ctx.WriteString(" IN ")
+	ctx.FormatNode(&node.From)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
 // KVOption is a key-value option.
 type KVOption struct {
 	Key   Name
@@ -394,6 +441,18 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("incremental_storage = ")
 		ctx.FormatNode(&o.IncrementalStorage)
 	}
+
+	if o.Columns != nil {
+		maybeAddSep()
+		ctx.WriteString("columns = (")
+		ctx.FormatNode(&o.Columns)
+		ctx.WriteString(")")
+	}
+
+	if o.IntoExistingTable {
+		maybeAddSep()
+		ctx.WriteString("into_existing_table")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -483,6 +542,20 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		return errors.New("incremental_storage option specified multiple times")
 	}
 
+	if o.Columns == nil {
+		o.Columns = other.Columns
+	} else if other.Columns != nil {
+		return errors.New("columns specified multiple times")
+	}
+
+	if o.IntoExistingTable {
+		if other.IntoExistingTable {
+			return errors.New("into_existing_table specified multiple times")
+		}
+	} else {
+		o.IntoExistingTable = other.IntoExistingTable
+	}
+
 	return nil
 }
 
@@ -500,5 +573,7 @@ func (o RestoreOptions) IsDefault() bool {
 		o.SkipLocalitiesCheck == options.SkipLocalitiesCheck &&
 		o.DebugPauseOn == options.DebugPauseOn &&
 		o.NewDBName == options.NewDBName &&
-		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage)
+		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
+		cmp.Equal(o.Columns, options.Columns) &&
+		o.IntoExistingTable == options.IntoExistingTable
 }
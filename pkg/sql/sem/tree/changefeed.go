@@ -15,12 +15,20 @@ type CreateChangefeed struct {
 	Targets TargetList
 	SinkURI Expr
 	Options KVOptions
+	// Select is set when the changefeed was specified as a CDC query
+	// (CREATE CHANGEFEED ... AS SELECT ...). In that case Targets is empty and
+	// the single watched table is the one in the FROM clause.
+	Select *SelectClause
 }
 
 var _ Statement = &CreateChangefeed{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateChangefeed) Format(ctx *FmtCtx) {
+	if node.Select != nil {
+		node.formatWithSelect(ctx)
+		return
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString("CREATE ")
 	} else {
@@ -39,3 +47,18 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.Options)
 	}
 }
+
+// formatWithSelect formats a changefeed specified as a CDC query.
+func (node *CreateChangefeed) formatWithSelect(ctx *FmtCtx) {
+	ctx.WriteString("CREATE CHANGEFEED")
+	if node.SinkURI != nil {
+		ctx.WriteString(" INTO ")
+		ctx.FormatNode(node.SinkURI)
+	}
+	if node.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+	ctx.WriteString(" AS ")
+	ctx.FormatNode(node.Select)
+}
@@ -348,6 +348,124 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// CreateDomain represents a CREATE DOMAIN statement.
+type CreateDomain struct {
+	TypeName *UnresolvedObjectName
+	Type     ResolvableTypeReference
+	// DefaultExpr is the DEFAULT expression of the domain, if any.
+	DefaultExpr Expr
+	// NotNull is true if NOT NULL was specified.
+	NotNull bool
+	// CheckConstraints are the CHECK constraints of the domain.
+	CheckConstraints []DomainCheckConstraint
+	// IfNotExists is true if IF NOT EXISTS was requested.
+	IfNotExists bool
+}
+
+var _ Statement = &CreateDomain{}
+
+// DomainCheckConstraint represents a CHECK constraint of a domain. The
+// expression refers to the value being checked as VALUE.
+type DomainCheckConstraint struct {
+	Name Name
+	Expr Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DomainCheckConstraint) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("CHECK (")
+	ctx.FormatNode(node.Expr)
+	ctx.WriteByte(')')
+}
+
+// NewCreateDomain constructs a CreateDomain statement from the constraints
+// given in the column qualification syntax. Qualifications which do not apply
+// to domains are rejected.
+func NewCreateDomain(
+	name *UnresolvedObjectName,
+	typRef ResolvableTypeReference,
+	qualifications []NamedColumnQualification,
+	ifNotExists bool,
+) (*CreateDomain, error) {
+	d := &CreateDomain{
+		TypeName:    name,
+		Type:        typRef,
+		IfNotExists: ifNotExists,
+	}
+	nullability := SilentNull
+	for _, c := range qualifications {
+		switch t := c.Qualification.(type) {
+		case *ColumnDefault:
+			if d.DefaultExpr != nil {
+				return nil, pgerror.New(pgcode.Syntax, "multiple default expressions")
+			}
+			d.DefaultExpr = t.Expr
+		case NotNullConstraint:
+			if nullability == Null {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			nullability = NotNull
+		case NullConstraint:
+			if nullability == NotNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			nullability = Null
+		case *ColumnCheckConstraint:
+			d.CheckConstraints = append(d.CheckConstraints, DomainCheckConstraint{
+				Name: c.Name,
+				Expr: t.Expr,
+			})
+		case PrimaryKeyConstraint, ShardedPrimaryKeyConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "primary key constraints not possible for domains")
+		case UniqueConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "unique constraints not possible for domains")
+		case *ColumnFKConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "foreign key constraints not possible for domains")
+		case *ColumnComputedDef, *GeneratedAlwaysAsIdentity, *GeneratedByDefAsIdentity:
+			return nil, pgerror.New(pgcode.Syntax, "generated columns not possible for domains")
+		case *ColumnOnUpdate:
+			return nil, pgerror.New(pgcode.Syntax, "ON UPDATE expressions not possible for domains")
+		case HiddenConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "NOT VISIBLE not possible for domains")
+		case *ColumnFamilyConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "column families not possible for domains")
+		case ColumnCollation:
+			return nil, pgerror.New(pgcode.FeatureNotSupported, "COLLATE is not supported for domains")
+		default:
+			return nil, errors.AssertionFailedf("unexpected domain qualification %T", t)
+		}
+	}
+	d.NotNull = nullability == NotNull
+	return d, nil
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE DOMAIN ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" AS ")
+	ctx.FormatTypeReference(node.Type)
+	if node.DefaultExpr != nil {
+		ctx.WriteString(" DEFAULT ")
+		ctx.FormatNode(node.DefaultExpr)
+	}
+	if node.NotNull {
+		ctx.WriteString(" NOT NULL")
+	}
+	for i := range node.CheckConstraints {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.CheckConstraints[i])
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -362,6 +480,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -439,6 +558,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -620,6 +740,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -773,6 +894,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -899,10 +1021,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -985,14 +1108,16 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
-	IfNotExists  bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	IfNotExists   bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -1037,6 +1162,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1110,15 +1236,60 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the checks of a constraint can be
+// deferred until the end of the transaction, and whether they are deferred by
+// default. See https://www.postgresql.org/docs/current/sql-set-constraints.html.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	// ConstraintNotDeferrable constraints are always checked at the end of each
+	// statement.
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	// ConstraintInitiallyImmediate constraints are checked at the end of each
+	// statement, unless SET CONSTRAINTS ... DEFERRED was used.
+	ConstraintInitiallyImmediate
+	// ConstraintInitiallyDeferred constraints are checked at the end of the
+	// transaction, unless SET CONSTRAINTS ... IMMEDIATE was used.
+	ConstraintInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	ConstraintNotDeferrable:      "NOT DEFERRABLE",
+	ConstraintInitiallyImmediate: "DEFERRABLE INITIALLY IMMEDIATE",
+	ConstraintInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Deferrable returns true if the checks of the constraint can be deferred.
+func (d ConstraintDeferrability) Deferrable() bool {
+	return d != ConstraintNotDeferrable
+}
+
+// Format implements the NodeFormatter interface. Nothing is printed for the
+// default (NOT DEFERRABLE).
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	switch d {
+	case ConstraintInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case ConstraintInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name        Name
-	Table       TableName
-	FromCols    NameList
-	ToCols      NameList
-	Actions     ReferenceActions
-	Match       CompositeKeyMatchMethod
-	IfNotExists bool
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	IfNotExists   bool
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1149,6 +1320,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1195,6 +1367,82 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an exclusion constraint within a
+// CREATE TABLE statement. An exclusion constraint guarantees that if any two
+// rows are compared on the specified columns using the specified operators,
+// at least one of these comparisons returns false or NULL.
+type ExclusionConstraintTableDef struct {
+	Name Name
+	// IndexMethod is the index access method named in the USING clause, if
+	// any.
+	IndexMethod string
+	Elems       ExclusionConstraintElemList
+	Predicate   Expr
+	IfNotExists bool
+}
+
+// ExclusionConstraintElem is a single element of an exclusion constraint: a
+// column along with the operator used to compare its values.
+type ExclusionConstraintElem struct {
+	Elem     IndexElem
+	Operator ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Elem)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionConstraintElemList is a list of ExclusionConstraintElem.
+type ExclusionConstraintElemList []ExclusionConstraintElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionConstraintElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.IndexMethod != "" {
+		ctx.WriteString("USING ")
+		ctx.WriteString(node.IndexMethod)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1544,6 +1792,8 @@ func (node *CreateTable) HoistConstraints() {
 					Name:     col.References.ConstraintName,
 					Actions:  col.References.Actions,
 					Match:    col.References.Match,
+
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
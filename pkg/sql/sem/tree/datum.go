@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -3411,6 +3412,634 @@ func (d *DBox2D) Size() uintptr {
 	return unsafe.Sizeof(*d) + unsafe.Sizeof(d.CartesianBoundingBox)
 }
 
+// DTSQuery is the tsquery Datum.
+type DTSQuery struct {
+	tsearch.TSQuery
+}
+
+// NewDTSQuery is a helper routine to create a DTSQuery initialized from its
+// argument.
+func NewDTSQuery(q tsearch.TSQuery) *DTSQuery {
+	return &DTSQuery{TSQuery: q}
+}
+
+// ParseDTSQuery takes a string of TSQuery and returns a DTSQuery value.
+func ParseDTSQuery(s string) (*DTSQuery, error) {
+	q, err := tsearch.ParseTSQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewDTSQuery(q), nil
+}
+
+// AsDTSQuery attempts to retrieve a DTSQuery from an Expr, returning a
+// DTSQuery and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSQuery wrapped by a *DOidWrapper is possible.
+func AsDTSQuery(e Expr) (*DTSQuery, bool) {
+	switch t := e.(type) {
+	case *DTSQuery:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSQuery(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSQuery attempts to retrieve a DTSQuery from an Expr, panicking if
+// the assertion fails.
+func MustBeDTSQuery(e Expr) *DTSQuery {
+	i, ok := AsDTSQuery(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DTSQuery, found %T", e))
+	}
+	return i
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSQuery) ResolvedType() *types.T {
+	return types.TSQuery
+}
+
+// Compare implements the Datum interface.
+func (d *DTSQuery) Compare(ctx *EvalContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DTSQuery) CompareError(ctx *EvalContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSQuery)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.TSQuery.Compare(v.TSQuery), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DTSQuery) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSQuery) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSQuery) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSQuery) IsMin(_ *EvalContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DTSQuery) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSQuery) Min(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSQuery) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSQuery) Format(ctx *FmtCtx) {
+	bareStrings := ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if bareStrings {
+		ctx.WriteString(d.TSQuery.String())
+		return
+	}
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, d.TSQuery.String(), ctx.flags.EncodeFlags())
+}
+
+// Size implements the Datum interface.
+func (d *DTSQuery) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.TSQuery.String()))
+}
+
+// DTSVector is the tsvector Datum.
+type DTSVector struct {
+	tsearch.TSVector
+}
+
+// NewDTSVector is a helper routine to create a DTSVector initialized from its
+// argument.
+func NewDTSVector(v tsearch.TSVector) *DTSVector {
+	return &DTSVector{TSVector: v}
+}
+
+// ParseDTSVector takes a string of TSVector and returns a DTSVector value.
+func ParseDTSVector(s string) (*DTSVector, error) {
+	v, err := tsearch.ParseTSVector(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewDTSVector(v), nil
+}
+
+// AsDTSVector attempts to retrieve a DTSVector from an Expr, returning a
+// DTSVector and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSVector wrapped by a *DOidWrapper is possible.
+func AsDTSVector(e Expr) (*DTSVector, bool) {
+	switch t := e.(type) {
+	case *DTSVector:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSVector(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSVector attempts to retrieve a DTSVector from an Expr, panicking if
+// the assertion fails.
+func MustBeDTSVector(e Expr) *DTSVector {
+	i, ok := AsDTSVector(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DTSVector, found %T", e))
+	}
+	return i
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSVector) ResolvedType() *types.T {
+	return types.TSVector
+}
+
+// Compare implements the Datum interface.
+func (d *DTSVector) Compare(ctx *EvalContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DTSVector) CompareError(ctx *EvalContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSVector)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.TSVector.Compare(v.TSVector), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DTSVector) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSVector) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSVector) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSVector) IsMin(_ *EvalContext) bool {
+	return len(d.TSVector) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DTSVector) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSVector) Min(_ *EvalContext) (Datum, bool) {
+	return NewDTSVector(tsearch.TSVector{}), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSVector) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSVector) Format(ctx *FmtCtx) {
+	bareStrings := ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if bareStrings {
+		ctx.WriteString(d.TSVector.String())
+		return
+	}
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, d.TSVector.String(), ctx.flags.EncodeFlags())
+}
+
+// Size implements the Datum interface.
+func (d *DTSVector) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(d.TSVector.StringSize())
+}
+
+// RangeBound is the lower or upper bound of a DRange.
+type RangeBound struct {
+	// Val is the value of the bound, or nil if the bound is infinite.
+	Val Datum
+	// Inclusive is true if the range contains Val. It is always false for
+	// infinite bounds.
+	Inclusive bool
+}
+
+// IsInf returns true if the bound is infinite, i.e. the range is unbounded on
+// that side.
+func (b RangeBound) IsInf() bool {
+	return b.Val == nil
+}
+
+// DRange is the Datum for the built-in range types, such as int8range and
+// tstzrange. Ranges are always canonicalized when they are constructed: the
+// bounds of ranges over a discrete subtype are always of the form [a,b), and a
+// range that contains no values is always represented as empty.
+type DRange struct {
+	typ *types.T
+	// Empty is true if the range contains no values, in which case the bounds
+	// are not set.
+	Empty bool
+	Lower RangeBound
+	Upper RangeBound
+}
+
+// NewDEmptyRange returns an empty range of the given type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Empty: true}
+}
+
+// NewDRange returns a range of the given type with the given bounds, which
+// must have the subtype of the range. The range is canonicalized, and an error
+// is returned if the lower bound is greater than the upper bound.
+func NewDRange(typ *types.T, lower, upper RangeBound) (*DRange, error) {
+	if lower.IsInf() {
+		lower.Inclusive = false
+	}
+	if upper.IsInf() {
+		upper.Inclusive = false
+	}
+	if !lower.IsInf() && !upper.IsInf() {
+		cmp := compareRangeValues(lower.Val, upper.Val)
+		if cmp > 0 {
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound")
+		}
+		if cmp == 0 && !(lower.Inclusive && upper.Inclusive) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	if isDiscreteRangeType(typ) {
+		// Ranges over discrete subtypes are canonicalized to the [a,b) form, so
+		// that all of the representations of a range compare and encode equally.
+		var err error
+		if !lower.IsInf() && !lower.Inclusive {
+			if lower.Val, err = discreteRangeValueNext(typ, lower.Val); err != nil {
+				return nil, err
+			}
+			lower.Inclusive = true
+		}
+		if !upper.IsInf() && upper.Inclusive {
+			if upper.Val, err = discreteRangeValueNext(typ, upper.Val); err != nil {
+				return nil, err
+			}
+			upper.Inclusive = false
+		}
+		if !lower.IsInf() && !upper.IsInf() && compareRangeValues(lower.Val, upper.Val) >= 0 {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	return &DRange{typ: typ, Lower: lower, Upper: upper}, nil
+}
+
+// isDiscreteRangeType returns true if the subtype of the given range type is
+// discrete, in which case the range is canonicalized to the [a,b) form.
+func isDiscreteRangeType(typ *types.T) bool {
+	switch typ.Oid() {
+	case oid.T_int4range, oid.T_int8range, oid.T_daterange:
+		return true
+	}
+	return false
+}
+
+// discreteRangeValueNext returns the value following d in the subtype of the
+// given discrete range type.
+func discreteRangeValueNext(typ *types.T, d Datum) (Datum, error) {
+	switch t := d.(type) {
+	case *DInt:
+		if typ.Oid() == oid.T_int4range {
+			if *t >= math.MaxInt32 {
+				return nil, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+			}
+		} else if *t == math.MaxInt64 {
+			return nil, pgerror.New(pgcode.NumericValueOutOfRange, "bigint out of range")
+		}
+		return NewDInt(*t + 1), nil
+	case *DDate:
+		// Like in Postgres, infinite dates are left alone.
+		if !t.IsFinite() {
+			return t, nil
+		}
+		n, err := t.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(n), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected discrete range value %T", d)
+}
+
+// compareRangeValues compares two values of the subtype of a range. Unlike
+// Datum.Compare, the result does not depend on the session, since both values
+// always have the same type.
+func compareRangeValues(a, b Datum) int {
+	switch ta := a.(type) {
+	case *DTimestamp:
+		return compareRangeTimes(ta.Time, b.(*DTimestamp).Time)
+	case *DTimestampTZ:
+		return compareRangeTimes(ta.Time, b.(*DTimestampTZ).Time)
+	}
+	return a.Compare(nil /* ctx */, b)
+}
+
+func compareRangeTimes(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	}
+	if b.Before(a) {
+		return 1
+	}
+	return 0
+}
+
+// compareRangeBounds compares two range bounds, each of which is either a
+// lower or an upper bound, as done by range_cmp_bounds in Postgres. An
+// infinite lower bound is less than any other bound and an infinite upper
+// bound is greater than any other bound. Bounds with equal values are ordered
+// by whether they include the value: for example, the exclusive upper bound of
+// [1,2) is less than the inclusive lower bound of [2,3].
+func compareRangeBounds(b1 RangeBound, b1Lower bool, b2 RangeBound, b2Lower bool) int {
+	lowerFirst := func(lower bool) int {
+		if lower {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case b1.IsInf() && b2.IsInf():
+		if b1Lower == b2Lower {
+			return 0
+		}
+		return lowerFirst(b1Lower)
+	case b1.IsInf():
+		return lowerFirst(b1Lower)
+	case b2.IsInf():
+		return -lowerFirst(b2Lower)
+	}
+	if cmp := compareRangeValues(b1.Val, b2.Val); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case !b1.Inclusive && !b2.Inclusive:
+		if b1Lower == b2Lower {
+			return 0
+		}
+		return -lowerFirst(b1Lower)
+	case !b1.Inclusive:
+		return -lowerFirst(b1Lower)
+	case !b2.Inclusive:
+		return lowerFirst(b2Lower)
+	}
+	return 0
+}
+
+// Contains returns true if every value in other is also in d. The empty range
+// is contained by every range.
+func (d *DRange) Contains(other *DRange) bool {
+	if other.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(d.Lower, true, other.Lower, true) <= 0 &&
+		compareRangeBounds(d.Upper, false, other.Upper, false) >= 0
+}
+
+// ContainsValue returns true if the given value of the subtype of the range is
+// in d.
+func (d *DRange) ContainsValue(v Datum) bool {
+	if d.Empty {
+		return false
+	}
+	if !d.Lower.IsInf() {
+		cmp := compareRangeValues(d.Lower.Val, v)
+		if cmp > 0 || (cmp == 0 && !d.Lower.Inclusive) {
+			return false
+		}
+	}
+	if !d.Upper.IsInf() {
+		cmp := compareRangeValues(d.Upper.Val, v)
+		if cmp < 0 || (cmp == 0 && !d.Upper.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps returns true if d and other have any values in common.
+func (d *DRange) Overlaps(other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	if compareRangeBounds(d.Lower, true, other.Lower, true) >= 0 &&
+		compareRangeBounds(d.Lower, true, other.Upper, false) <= 0 {
+		return true
+	}
+	if compareRangeBounds(other.Lower, true, d.Lower, true) >= 0 &&
+		compareRangeBounds(other.Lower, true, d.Upper, false) <= 0 {
+		return true
+	}
+	return false
+}
+
+// AsDRange attempts to retrieve a DRange from an Expr, returning a DRange and
+// a flag signifying whether the assertion was successful. The function should
+// be used instead of direct type assertions wherever a *DRange wrapped by a
+// *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DRange) IsComposite() bool {
+	for _, b := range [2]RangeBound{d.Lower, d.Upper} {
+		if cdatum, ok := b.Val.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare implements the Datum interface.
+func (d *DRange) Compare(ctx *EvalContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface. Like in Postgres, the empty
+// range sorts before all other ranges, and other ranges are ordered by their
+// lower bounds and then by their upper bounds.
+func (d *DRange) CompareError(ctx *EvalContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DRange)
+	if !ok || !d.typ.Equivalent(v.typ) {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	switch {
+	case d.Empty && v.Empty:
+		return 0, nil
+	case d.Empty:
+		return -1, nil
+	case v.Empty:
+		return 1, nil
+	}
+	if cmp := compareRangeBounds(d.Lower, true, v.Lower, true); cmp != 0 {
+		return cmp, nil
+	}
+	return compareRangeBounds(d.Upper, false, v.Upper, false), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(_ *EvalContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(_ *EvalContext) (Datum, bool) {
+	return NewDEmptyRange(d.typ), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	if ctx.HasFlags(fmtPgwireFormat) {
+		d.pgwireFormat(ctx)
+		return
+	}
+	s := d.formatWithBoundFlags(FmtBareStrings, ctx.dataConversionConfig)
+	if ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		ctx.WriteString(s)
+		return
+	}
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+}
+
+// formatWithBoundFlags returns the text representation of the range, such as
+// [1,10), formatting the bounds with the given flags. Like in Postgres, bounds
+// which contain special characters are double quoted.
+func (d *DRange) formatWithBoundFlags(
+	flags FmtFlags, dcc sessiondatapb.DataConversionConfig,
+) string {
+	if d.Empty {
+		return "empty"
+	}
+	var buf bytes.Buffer
+	if d.Lower.Inclusive {
+		buf.WriteByte('[')
+	} else {
+		buf.WriteByte('(')
+	}
+	if !d.Lower.IsInf() {
+		formatRangeBound(&buf, AsStringWithFlags(d.Lower.Val, flags, FmtDataConversionConfig(dcc)))
+	}
+	buf.WriteByte(',')
+	if !d.Upper.IsInf() {
+		formatRangeBound(&buf, AsStringWithFlags(d.Upper.Val, flags, FmtDataConversionConfig(dcc)))
+	}
+	if d.Upper.Inclusive {
+		buf.WriteByte(']')
+	} else {
+		buf.WriteByte(')')
+	}
+	return buf.String()
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	if !d.Lower.IsInf() {
+		sz += d.Lower.Val.Size()
+	}
+	if !d.Upper.IsInf() {
+		sz += d.Upper.Val.Size()
+	}
+	return sz
+}
+
 // DJSON is the JSON Datum.
 type DJSON struct{ json.JSON }
 
@@ -3532,7 +4161,8 @@ func AsJSON(
 	case *DTimestamp:
 		// This is RFC3339Nano, but without the TZ fields.
 		return json.FromString(t.UTC().Format("2006-01-02T15:04:05.999999999")), nil
-	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D:
+	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D,
+		*DTSQuery, *DTSVector, *DRange:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings, FmtDataConversionConfig(dcc))), nil
 	case *DGeometry:
 		return json.FromSpatialObject(t.Geometry.SpatialObject(), geo.DefaultGeoJSONDecimalDigits)
@@ -5391,6 +6021,12 @@ func NewDefaultDatum(evalCtx *EvalContext, t *types.T) (d Datum, err error) {
 		return dNullJSON, nil
 	case types.TimeTZFamily:
 		return dZeroTimeTZ, nil
+	case types.TSQueryFamily:
+		return NewDTSQuery(tsearch.TSQuery{}), nil
+	case types.TSVectorFamily:
+		return NewDTSVector(tsearch.TSVector{}), nil
+	case types.RangeFamily:
+		return NewDEmptyRange(t), nil
 	case types.GeometryFamily, types.GeographyFamily, types.Box2DFamily:
 		// TODO(otan): force Geometry/Geography to not allow `NOT NULL` columns to
 		// make this impossible.
@@ -5506,6 +6142,9 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DInt(0)), fixedSize},
 	types.EnumFamily:           {unsafe.Sizeof(DEnum{}), variableSize},
+	types.TSQueryFamily:        {unsafe.Sizeof(DTSQuery{}), variableSize},
+	types.TSVectorFamily:       {unsafe.Sizeof(DTSVector{}), variableSize},
+	types.RangeFamily:          {unsafe.Sizeof(DRange{}), variableSize},
 
 	types.VoidFamily: {sz: unsafe.Sizeof(DVoid{}), variable: fixedSize},
 	// TODO(jordan,justin): This seems suspicious.
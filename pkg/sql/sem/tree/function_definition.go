@@ -21,6 +21,11 @@ type FunctionDefinition struct {
 	// Name is the short name of the function.
 	Name string
 
+	// QualifiedName is the fully qualified name of a user-defined function,
+	// which is used to format references to it, so that they resolve to the
+	// same function regardless of the search path. It is nil for builtins.
+	QualifiedName *UnresolvedObjectName
+
 	// Definition is the set of overloads for this function name.
 	// We use []overloadImpl here although all the uses of this struct
 	// could actually write a []Overload, because we want to share
@@ -113,6 +118,10 @@ type FunctionProperties struct {
 	//
 	// See memo.CanBeCompositeSensitive.
 	CompositeInsensitive bool
+
+	// IsUDF is true if the function is a user-defined function, in which case
+	// the UDF field is set on all of its overloads.
+	IsUDF bool
 }
 
 // ShouldDocument returns whether the built-in function should be included in
@@ -185,6 +194,10 @@ var OidToBuiltinName map[oid.Oid]string
 
 // Format implements the NodeFormatter interface.
 func (fd *FunctionDefinition) Format(ctx *FmtCtx) {
+	if fd.QualifiedName != nil {
+		ctx.FormatNode(fd.QualifiedName)
+		return
+	}
 	ctx.WriteString(fd.Name)
 }
 func (fd *FunctionDefinition) String() string { return AsString(fd) }
@@ -0,0 +1,112 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With   *With
+	Table  TableExpr
+	Source TableExpr
+	On     Expr
+	Whens  MergeWhens
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Whens)
+}
+
+// MergeWhens represents the list of WHEN clauses of a MERGE statement.
+type MergeWhens []*MergeWhen
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhens) Format(ctx *FmtCtx) {
+	for i, n := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(n)
+	}
+}
+
+// MergeActionType is the type of the action of a WHEN clause in a MERGE
+// statement.
+type MergeActionType int
+
+// MergeActionType values.
+const (
+	MergeDoNothing MergeActionType = iota
+	MergeUpdate
+	MergeDelete
+	MergeInsert
+)
+
+// MergeWhen represents a WHEN [NOT] MATCHED clause of a MERGE statement.
+type MergeWhen struct {
+	// Matched is true for WHEN MATCHED clauses, which apply to the source rows
+	// that match a row of the target table, and false for WHEN NOT MATCHED
+	// clauses.
+	Matched bool
+	// Cond is the optional AND condition of the clause.
+	Cond   Expr
+	Action MergeActionType
+	// Exprs is the SET list of a MergeUpdate action.
+	Exprs UpdateExprs
+	// Columns and Values are the target columns and the values of a
+	// MergeInsert action. Values is nil for INSERT DEFAULT VALUES.
+	Columns NameList
+	Values  Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	ctx.WriteString("WHEN ")
+	if !node.Matched {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("MATCHED")
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	switch node.Action {
+	case MergeDoNothing:
+		ctx.WriteString("DO NOTHING")
+	case MergeUpdate:
+		ctx.WriteString("UPDATE SET ")
+		ctx.FormatNode(&node.Exprs)
+	case MergeDelete:
+		ctx.WriteString("DELETE")
+	case MergeInsert:
+		ctx.WriteString("INSERT ")
+		if node.Values == nil {
+			ctx.WriteString("DEFAULT VALUES")
+			return
+		}
+		if len(node.Columns) > 0 {
+			ctx.WriteByte('(')
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(") ")
+		}
+		ctx.WriteString("VALUES (")
+		ctx.FormatNode(&node.Values)
+		ctx.WriteByte(')')
+	}
+}
@@ -111,6 +111,11 @@ type Overload struct {
 	// DistSQL. One example is when the type information for function arguments
 	// cannot be recovered.
 	DistsqlBlocklist bool
+
+	// UDF is set if the overload is a user-defined function. Calls to
+	// user-defined functions are inlined by the optimizer, so such overloads
+	// have no evaluation implementation.
+	UDF *UDFOverload
 }
 
 // params implements the overloadImpl interface.
@@ -0,0 +1,177 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+var malformedRangeError = pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed range literal")
+
+type rangeParseState struct {
+	s string
+}
+
+func (p *rangeParseState) eatWhitespace() {
+	p.s = strings.TrimLeftFunc(p.s, unicode.IsSpace)
+}
+
+// parseBound parses the text of a range bound up to (but not including) the
+// first unquoted character for which isTerminatingChar returns true. It
+// returns ok=false if the bound is empty, which denotes an infinite bound. A
+// quoted empty string ("") is a finite bound whose text is empty.
+func (p *rangeParseState) parseBound(isTerminatingChar func(byte) bool) (string, bool, error) {
+	var result strings.Builder
+	inQuote := false
+	quoted := false
+	i := 0
+	for ; i < len(p.s); i++ {
+		ch := p.s[i]
+		if !inQuote && isTerminatingChar(ch) {
+			break
+		}
+		switch ch {
+		case '\\':
+			i++
+			if i >= len(p.s) {
+				return "", false, errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+			}
+			result.WriteByte(p.s[i])
+		case '"':
+			quoted = true
+			if inQuote && i+1 < len(p.s) && p.s[i+1] == '"' {
+				// Two double quotes inside a quoted string are an escape sequence
+				// for one double quote.
+				result.WriteByte('"')
+				i++
+			} else {
+				inQuote = !inQuote
+			}
+		default:
+			result.WriteByte(ch)
+		}
+	}
+	if i >= len(p.s) {
+		return "", false, errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+	}
+	p.s = p.s[i:]
+	if result.Len() == 0 && !quoted {
+		return "", false, nil
+	}
+	return result.String(), true, nil
+}
+
+// ParseDRangeFromString parses the string-form of constructing ranges, such as
+// `'[1,10)'::int8range`, `'(,"2022-01-01")'::daterange`, or `'empty'`. The
+// input type t is the type of the range to parse.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	ret, dependsOnContext, err := doParseDRangeFromString(ctx, s, t)
+	if err != nil {
+		return ret, false, MakeParseError(s, t, err)
+	}
+	return ret, dependsOnContext, nil
+}
+
+// doParseDRangeFromString does most of the work of ParseDRangeFromString,
+// except the error it returns isn't prettified as a parsing error.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func doParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	subtype := t.RangeContents()
+	if subtype == nil {
+		return nil, false, errors.AssertionFailedf("not a range type %s (%T)", t, t)
+	}
+	p := rangeParseState{s: s}
+	p.eatWhitespace()
+	if len(p.s) >= len("empty") && strings.EqualFold(p.s[:len("empty")], "empty") {
+		p.s = p.s[len("empty"):]
+		p.eatWhitespace()
+		if len(p.s) > 0 {
+			return nil, false, errors.WithDetail(malformedRangeError, "Junk after \"empty\" key word.")
+		}
+		return NewDEmptyRange(t), false, nil
+	}
+
+	var lower, upper RangeBound
+	switch {
+	case strings.HasPrefix(p.s, "["):
+		lower.Inclusive = true
+	case strings.HasPrefix(p.s, "("):
+	default:
+		return nil, false, errors.WithDetail(malformedRangeError, "Missing left parenthesis or bracket.")
+	}
+	p.s = p.s[1:]
+
+	parseValue := func(text string) (Datum, error) {
+		d, dep, err := ParseAndRequireString(subtype, text, ctx)
+		if dep {
+			dependsOnContext = true
+		}
+		return d, err
+	}
+
+	lowerText, ok, err := p.parseBound(func(ch byte) bool { return ch == ',' || ch == ')' || ch == ']' })
+	if err != nil {
+		return nil, false, err
+	}
+	if p.s[0] != ',' {
+		return nil, false, errors.WithDetail(malformedRangeError, "Missing comma after lower bound.")
+	}
+	p.s = p.s[1:]
+	if ok {
+		if lower.Val, err = parseValue(lowerText); err != nil {
+			return nil, false, err
+		}
+	}
+
+	upperText, ok, err := p.parseBound(func(ch byte) bool { return ch == ',' || ch == ')' || ch == ']' })
+	if err != nil {
+		return nil, false, err
+	}
+	switch p.s[0] {
+	case ']':
+		upper.Inclusive = true
+	case ')':
+	default:
+		return nil, false, errors.WithDetail(malformedRangeError, "Too many commas.")
+	}
+	p.s = p.s[1:]
+	if ok {
+		if upper.Val, err = parseValue(upperText); err != nil {
+			return nil, false, err
+		}
+	}
+
+	p.eatWhitespace()
+	if len(p.s) > 0 {
+		return nil, false, errors.WithDetail(malformedRangeError, "Junk after right parenthesis or bracket.")
+	}
+
+	r, err := NewDRange(t, lower, upper)
+	if err != nil {
+		return nil, false, err
+	}
+	return r, dependsOnContext, nil
+}
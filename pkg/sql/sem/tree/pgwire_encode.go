@@ -148,10 +148,42 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	}
 }
 
-var tupleQuoteSet, arrayQuoteSet asciiSet
+func (d *DRange) pgwireFormat(ctx *FmtCtx) {
+	// When converting a range to text in "postgres mode", the bounds are
+	// printed in "postgres mode" and then quoted if necessary, like the
+	// elements of tuples and arrays.
+	ctx.WriteString(d.formatWithBoundFlags(ctx.flags, ctx.dataConversionConfig))
+}
+
+// formatRangeBound writes the text representation of a range bound, quoting it
+// if it contains any of the characters that are special in range literals.
+// Like in Postgres, double quotes and backslashes are doubled.
+func formatRangeBound(buf *bytes.Buffer, in string) {
+	quote := in == "" || rangeQuoteSet.in(in)
+	if quote {
+		buf.WriteByte('"')
+	}
+	for _, r := range in {
+		if r == '"' || r == '\\' {
+			buf.WriteByte(byte(r))
+			buf.WriteByte(byte(r))
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	if quote {
+		buf.WriteByte('"')
+	}
+}
+
+var tupleQuoteSet, arrayQuoteSet, rangeQuoteSet asciiSet
 
 func init() {
 	var ok bool
+	rangeQuoteSet, ok = makeASCIISet(" \t\v\f\r\n()[],\"\\")
+	if !ok {
+		panic("range asciiset")
+	}
 	tupleQuoteSet, ok = makeASCIISet(" \t\v\f\r\n(),\"\\")
 	if !ok {
 		panic("tuple asciiset")
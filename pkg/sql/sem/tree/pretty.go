@@ -1159,10 +1159,15 @@ func (node *Update) doc(p *PrettyCfg) pretty.Doc {
 }
 
 func (node *Delete) doc(p *PrettyCfg) pretty.Doc {
-	items := make([]pretty.TableRow, 6)
+	items := make([]pretty.TableRow, 7)
 	items = append(items,
 		node.With.docRow(p),
-		p.row("DELETE FROM", p.Doc(node.Table)),
+		p.row("DELETE FROM", p.Doc(node.Table)))
+	if len(node.Using) > 0 {
+		items = append(items,
+			p.row("USING", p.Doc(&node.Using)))
+	}
+	items = append(items,
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
@@ -1758,6 +1763,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionByIndex != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByIndex))
 	}
+	if node.Deferrability.Deferrable() {
+		clauses = append(clauses, p.Doc(node.Deferrability))
+	}
 	if node.Predicate != nil {
 		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
 	}
@@ -1808,9 +1816,23 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if node.Deferrability.Deferrable() {
+		clauses = append(clauses, p.Doc(node.Deferrability))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
+func (d ConstraintDeferrability) doc(p *PrettyCfg) pretty.Doc {
+	switch d {
+	case ConstraintInitiallyImmediate:
+		return pretty.Keyword("DEFERRABLE")
+	case ConstraintInitiallyDeferred:
+		return pretty.Keyword("DEFERRABLE INITIALLY DEFERRED")
+	}
+	return pretty.Nil
+}
+
 func (p *PrettyCfg) maybePrependConstraintName(constraintName *Name, d pretty.Doc) pretty.Doc {
 	if *constraintName != "" {
 		return pretty.Fold(pretty.ConcatSpace,
@@ -1971,6 +1993,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrability.Deferrable() {
+			fkDetails = append(fkDetails, p.Doc(node.References.Deferrability))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -2049,7 +2074,14 @@ func (node *Backup) doc(p *PrettyCfg) pretty.Doc {
 	if node.Targets != nil {
 		items = append(items, node.Targets.docRow(p))
 	}
-	if node.Nested {
+	if node.Compact {
+		if node.Subdir != nil {
+			items = append(items, p.row("COMPACT ", p.Doc(node.Subdir)))
+			items = append(items, p.row(" IN ", p.Doc(&node.To)))
+		} else {
+			items = append(items, p.row("COMPACT LATEST IN", p.Doc(&node.To)))
+		}
+	} else if node.Nested {
 		if node.Subdir != nil {
 			items = append(items, p.row("INTO ", p.Doc(node.Subdir)))
 			items = append(items, p.row(" IN ", p.Doc(&node.To)))
@@ -2095,6 +2127,9 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
 	}
+	if node.Where != nil {
+		items = append(items, p.row("WHERE", p.Doc(node.Where)))
+	}
 	if !node.Options.IsDefault() {
 		items = append(items, p.row("WITH", p.Doc(&node.Options)))
 	}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "fmt"
+
+// The statements in this file are the commands of the PostgreSQL streaming
+// replication protocol. They are not part of the SQL grammar: they are only
+// accepted on connections started in replication mode, and are parsed by the
+// pgrepl package.
+
+// IdentifySystem represents an IDENTIFY_SYSTEM replication command.
+type IdentifySystem struct{}
+
+var _ Statement = &IdentifySystem{}
+
+// Format implements the NodeFormatter interface.
+func (node *IdentifySystem) Format(ctx *FmtCtx) {
+	ctx.WriteString("IDENTIFY_SYSTEM")
+}
+
+// CreateReplicationSlot represents a CREATE_REPLICATION_SLOT replication
+// command. Only logical replication slots can be created.
+type CreateReplicationSlot struct {
+	Name      Name
+	Temporary bool
+	// Plugin is the name of the output plugin of the slot.
+	Plugin Name
+	// SnapshotAction is one of EXPORT_SNAPSHOT, NOEXPORT_SNAPSHOT or
+	// USE_SNAPSHOT, or empty if not specified.
+	SnapshotAction string
+}
+
+var _ Statement = &CreateReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Name)
+	if node.Temporary {
+		ctx.WriteString(" TEMPORARY")
+	}
+	ctx.WriteString(" LOGICAL ")
+	ctx.FormatNode(&node.Plugin)
+	if node.SnapshotAction != "" {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.SnapshotAction)
+	}
+}
+
+// DropReplicationSlot represents a DROP_REPLICATION_SLOT replication command.
+type DropReplicationSlot struct {
+	Name Name
+	Wait bool
+}
+
+var _ Statement = &DropReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Name)
+	if node.Wait {
+		ctx.WriteString(" WAIT")
+	}
+}
+
+// StartReplication represents a START_REPLICATION replication command for a
+// logical replication slot.
+type StartReplication struct {
+	Slot Name
+	// StartLSN is the position from which to start streaming, formatted as
+	// two hexadecimal numbers separated by a slash.
+	StartLSN uint64
+	// Options are the options passed to the output plugin of the slot. The
+	// values are *StrVal or nil.
+	Options KVOptions
+}
+
+var _ Statement = &StartReplication{}
+
+// Format implements the NodeFormatter interface.
+func (node *StartReplication) Format(ctx *FmtCtx) {
+	ctx.WriteString("START_REPLICATION SLOT ")
+	ctx.FormatNode(&node.Slot)
+	ctx.WriteString(" LOGICAL ")
+	ctx.WriteString(fmt.Sprintf("%X/%X", node.StartLSN>>32, uint32(node.StartLSN)))
+	if len(node.Options) > 0 {
+		ctx.WriteString(" (")
+		for i := range node.Options {
+			opt := &node.Options[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.WithFlags(ctx.flags&^FmtMarkRedactionNode, func() {
+				ctx.FormatNode(&opt.Key)
+			})
+			if opt.Value != nil {
+				ctx.WriteByte(' ')
+				ctx.FormatNode(opt.Value)
+			}
+		}
+		ctx.WriteByte(')')
+	}
+}
@@ -696,6 +696,48 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetKind indicates the kind of a GroupingSet.
+type GroupingSetKind int
+
+const (
+	// GroupingSets represents a GROUPING SETS (...) item.
+	GroupingSets GroupingSetKind = iota
+	// Rollup represents a ROLLUP (...) item.
+	Rollup
+	// Cube represents a CUBE (...) item.
+	Cube
+)
+
+var groupingSetKindName = [...]string{
+	GroupingSets: "GROUPING SETS",
+	Rollup:       "ROLLUP",
+	Cube:         "CUBE",
+}
+
+// String implements the fmt.Stringer interface.
+func (k GroupingSetKind) String() string {
+	return groupingSetKindName[k]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE item in a GROUP BY
+// clause. For ROLLUP and CUBE, each element of Exprs is either a single
+// expression or a parenthesized list of expressions that is treated as a
+// unit. For GROUPING SETS, each element is itself a grouping item and may
+// contain nested ROLLUP, CUBE or GROUPING SETS items; an empty tuple
+// represents the empty grouping set.
+type GroupingSet struct {
+	Kind  GroupingSetKind
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Kind.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
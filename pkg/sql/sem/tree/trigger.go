@@ -0,0 +1,127 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	FuncName   *UnresolvedObjectName
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(node.ActionTime)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" FOR EACH ROW EXECUTE FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteString("()")
+}
+
+// TriggerActionTime specifies whether a trigger fires before or after the
+// row is modified.
+type TriggerActionTime int
+
+// TriggerActionTime values.
+const (
+	TriggerBefore TriggerActionTime = iota
+	TriggerAfter
+)
+
+// Format implements the NodeFormatter interface.
+func (node TriggerActionTime) Format(ctx *FmtCtx) {
+	switch node {
+	case TriggerBefore:
+		ctx.WriteString("BEFORE")
+	case TriggerAfter:
+		ctx.WriteString("AFTER")
+	}
+}
+
+// TriggerEvent is a kind of modification of a row which fires a trigger.
+type TriggerEvent int
+
+// TriggerEvent values.
+const (
+	TriggerEventInsert TriggerEvent = iota
+	TriggerEventUpdate
+	TriggerEventDelete
+)
+
+// Format implements the NodeFormatter interface.
+func (node TriggerEvent) Format(ctx *FmtCtx) {
+	switch node {
+	case TriggerEventInsert:
+		ctx.WriteString("INSERT")
+	case TriggerEventUpdate:
+		ctx.WriteString("UPDATE")
+	case TriggerEventDelete:
+		ctx.WriteString("DELETE")
+	}
+}
+
+// TriggerEvents represents the list of events which fire a trigger.
+type TriggerEvents []TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i, e := range *node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.FormatNode(e)
+	}
+}
+
+// Contains returns true if the list contains the given event.
+func (node TriggerEvents) Contains(event TriggerEvent) bool {
+	for _, e := range node {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name         Name
+	Table        TableName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
@@ -526,24 +526,32 @@ func (expr *CastExpr) TypeCheck(
 	}
 	expr.Type = exprType
 	canElideCast := true
+	// A cast to a domain is never elided, since the constraints of the domain
+	// must be checked. Constants and placeholders are given the base type of
+	// the domain.
+	castType := exprType
+	if exprType.IsDomain() {
+		castType = exprType.DomainBaseType()
+		canElideCast = false
+	}
 	switch {
 	case isConstant(expr.Expr):
 		c := expr.Expr.(Constant)
-		if canConstantBecome(c, exprType) {
+		if canConstantBecome(c, castType) {
 			// If a Constant is subject to a cast which it can naturally become (which
 			// is in its resolvable type set), we desire the cast's type for the
 			// Constant. In many cases, the CastExpr will then become a no-op and will
 			// be elided below. In other cases, the types may be equivalent but not
 			// Identical (e.g. string::char(2) or oid::regclass) and the CastExpr is
 			// still needed.
-			desired = exprType
+			desired = castType
 		}
 	case semaCtx.isUnresolvedPlaceholder(expr.Expr):
 		// If the placeholder has not yet been resolved, then we can make its
 		// expected type be the cast type. If it already has been resolved, but the
 		// type we gave it before is not compatible with the usage here, then
 		// type-checking will fail as desired.
-		desired = exprType
+		desired = castType
 	case isArrayExpr(expr.Expr):
 		// If we're going to cast to another array type, which is a common pattern
 		// in SQL (select array[]::int[], select array[$1]::int[]), use the cast
@@ -1473,6 +1481,14 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgcode.Syntax,
+		"%s can only appear in a GROUP BY clause", expr.Kind)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -1766,6 +1782,24 @@ func (d *DJSON) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedE
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DRange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
@@ -0,0 +1,295 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/errors"
+)
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Replace    bool
+	FuncName   *UnresolvedObjectName
+	Args       FuncArgs
+	ReturnType ResolvableTypeReference
+	// ReturnSet is true if the function was declared as RETURNS SETOF.
+	ReturnSet bool
+	Options   FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	if node.ReturnSet {
+		ctx.WriteString("SETOF ")
+	}
+	ctx.FormatTypeReference(node.ReturnType)
+	for _, opt := range node.Options {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(opt)
+	}
+}
+
+// FuncArg represents an argument in the signature of a user-defined function.
+type FuncArg struct {
+	// Name is the name of the argument. It is empty if the argument can only be
+	// referenced by its position.
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncArgs represents a list of arguments of a user-defined function.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an option of a CREATE FUNCTION statement, such as its
+// volatility or its body.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakproof) functionOption()         {}
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionLanguage) functionOption()          {}
+func (FunctionBodyStr) functionOption()           {}
+
+// FunctionOptions represents a list of options of a CREATE FUNCTION
+// statement, in the order in which they were specified.
+type FunctionOptions []FunctionOption
+
+// FunctionVolatility represents the volatility of a user-defined function.
+type FunctionVolatility int
+
+// FunctionVolatility values.
+const (
+	FunctionVolatile FunctionVolatility = iota
+	FunctionStable
+	FunctionImmutable
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionVolatile:
+		ctx.WriteString("VOLATILE")
+	case FunctionStable:
+		ctx.WriteString("STABLE")
+	case FunctionImmutable:
+		ctx.WriteString("IMMUTABLE")
+	}
+}
+
+// FunctionLeakproof indicates whether a user-defined function was declared as
+// LEAKPROOF or NOT LEAKPROOF.
+type FunctionLeakproof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakproof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionNullInputBehavior represents how a user-defined function behaves
+// when any of its arguments is NULL.
+type FunctionNullInputBehavior int
+
+// FunctionNullInputBehavior values.
+const (
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	FunctionReturnsNullOnNullInput
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionLanguage is the language in which the body of a user-defined
+// function is written.
+type FunctionLanguage string
+
+// FunctionLangSQL is the only supported language for user-defined functions.
+const FunctionLangSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	// The language is not anonymized, since it is not a user-provided name.
+	ctx.WriteString("LANGUAGE ")
+	lexbase.EncodeRestrictedSQLIdent(&ctx.Buffer, string(node), lexbase.EncNoFlags)
+}
+
+// FunctionBodyStr is the body of a user-defined function, as specified with
+// AS '...'.
+type FunctionBodyStr string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBodyStr) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	if ctx.flags.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+	}
+}
+
+// FuncObj identifies a user-defined function, optionally by its argument
+// types, in statements such as DROP FUNCTION.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	// Args are the types of the arguments of the function. It is nil if the
+	// argument list was omitted, in which case the name must identify a single
+	// overload.
+	Args []ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Args == nil {
+		return
+	}
+	ctx.WriteByte('(')
+	for i, t := range node.Args {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatTypeReference(t)
+	}
+	ctx.WriteByte(')')
+}
+
+// FuncObjs is a list of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// UDFOverload holds the definition of an overload of a user-defined function.
+type UDFOverload struct {
+	// ID is the ID of the descriptor of the overload.
+	ID uint32
+	// ArgNames are the names of the arguments of the function. Unnamed
+	// arguments have an empty name.
+	ArgNames []string
+	// Body is the statement which produces the result of the function. Data
+	// sources referenced by the body are fully qualified.
+	Body string
+	// ReturnSet is true if the function returns a set of rows.
+	ReturnSet bool
+	// Strict is true if the function returns NULL, without evaluating its
+	// body, when any of its arguments is NULL.
+	Strict bool
+}
+
+// NewUDFDefinition creates a FunctionDefinition for the user-defined function
+// with the given fully qualified name and overloads, all of which must have
+// their UDF field set. Unlike NewFunctionDefinition, no telemetry counters are
+// attached to the overloads, since the names of user-defined functions are
+// not safe to report.
+func NewUDFDefinition(
+	name *UnresolvedObjectName, props *FunctionProperties, def []Overload,
+) *FunctionDefinition {
+	props.IsUDF = true
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		if def[i].UDF == nil {
+			panic(errors.AssertionFailedf("overload %d of %s is not a user-defined function", i, name))
+		}
+		// Calls to user-defined functions are always inlined by the optimizer,
+		// so these implementations should never be reached.
+		if def[i].UDF.ReturnSet {
+			def[i].Generator = func(*EvalContext, Datums) (ValueGenerator, error) {
+				return nil, errors.AssertionFailedf("user-defined function %s was not inlined", name)
+			}
+		} else {
+			def[i].Fn = func(*EvalContext, Datums) (Datum, error) {
+				return nil, errors.AssertionFailedf("user-defined function %s was not inlined", name)
+			}
+		}
+		overloads[i] = &def[i]
+	}
+	return &FunctionDefinition{
+		Name:               name.Object(),
+		QualifiedName:      name,
+		Definition:         overloads,
+		FunctionProperties: *props,
+	}
+}
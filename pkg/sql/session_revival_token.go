@@ -0,0 +1,54 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessionrevival"
+)
+
+// AllowSessionRevival is true if the cluster is allowed to create session
+// revival tokens and use them to authenticate a session. It is a non-public
+// setting since this is only intended to be used by the SQL proxy.
+var AllowSessionRevival = settings.RegisterBoolSetting(
+	settings.TenantReadOnly,
+	"server.user_login.session_revival_token.enabled",
+	"if set, the cluster is able to create session revival tokens and use them "+
+		"to authenticate a new session",
+	false,
+)
+
+// CreateSessionRevivalToken is a wrapper for
+// sessionrevival.CreateSessionRevivalToken, and uses the planner.
+func (p *planner) CreateSessionRevivalToken() (*tree.DBytes, error) {
+	if !AllowSessionRevival.Get(&p.ExecCfg().Settings.SV) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "session revival tokens are not supported on this cluster")
+	}
+	// Note that we use SessionUser here and not CurrentUser, since when the
+	// token is used to create a new session, it should be for the user who was
+	// originally authenticated.
+	user := p.SessionData().SessionUser()
+	if user.IsRootUser() {
+		return nil, pgerror.New(pgcode.InsufficientPrivilege, "cannot create token for root user")
+	}
+	cm, err := p.ExecCfg().RPCContext.SecurityContext.GetCertificateManager()
+	if err != nil {
+		return nil, err
+	}
+	token, err := sessionrevival.CreateSessionRevivalToken(cm, user)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDBytes(tree.DBytes(token)), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package sessionrevival implements the tokens which allow a SQL session to
+// be re-established on another SQL pod without the credentials of the user,
+// which is used by the SQL proxy to migrate connections between pods.
+package sessionrevival
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// tokenLifetime is the duration for which a session revival token is valid.
+// Tokens are meant to be used right away by the proxy, so this is short.
+const tokenLifetime = 10 * time.Minute
+
+// CreateSessionRevivalToken creates a token which can be used to authenticate
+// the given user, signed using the tenant signing key.
+func CreateSessionRevivalToken(
+	cm *security.CertificateManager, user security.SQLUsername,
+) ([]byte, error) {
+	cert, err := cm.GetTenantSigningCert()
+	if err != nil {
+		return nil, err
+	}
+	key, err := security.PEMToPrivateKey(cert.KeyFileContents)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.AssertionFailedf("tenant signing key is not an Ed25519 key")
+	}
+
+	now := timeutil.Now()
+	issuedAt, err := pbtypes.TimestampProto(now)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := pbtypes.TimestampProto(now.Add(tokenLifetime))
+	if err != nil {
+		return nil, err
+	}
+	payload := &sessiondatapb.SessionRevivalToken_Payload{
+		User:      user.Normalized(),
+		Algorithm: x509.PureEd25519.String(),
+		ExpiresAt: expiresAt,
+		IssuedAt:  issuedAt,
+	}
+	payloadBytes, err := protoutil.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	token := &sessiondatapb.SessionRevivalToken{
+		PayloadBytes: payloadBytes,
+		Signature:    ed25519.Sign(privateKey, payloadBytes),
+	}
+	return protoutil.Marshal(token)
+}
+
+// ValidateSessionRevivalToken checks that the given token was signed by the
+// tenant signing key, has not expired and is for the given user.
+func ValidateSessionRevivalToken(
+	cm *security.CertificateManager, user security.SQLUsername, tokenBytes []byte,
+) error {
+	cert, err := cm.GetTenantSigningCert()
+	if err != nil {
+		return err
+	}
+	token := &sessiondatapb.SessionRevivalToken{}
+	if err := protoutil.Unmarshal(tokenBytes, token); err != nil {
+		return errors.Wrap(err, "invalid session revival token")
+	}
+	payload := &sessiondatapb.SessionRevivalToken_Payload{}
+	if err := protoutil.Unmarshal(token.PayloadBytes, payload); err != nil {
+		return errors.Wrap(err, "invalid session revival token")
+	}
+	if payload.Algorithm != x509.PureEd25519.String() {
+		return errors.Newf("unsupported session revival token algorithm %q", payload.Algorithm)
+	}
+	if err := cert.ParsedCertificates[0].CheckSignature(
+		x509.PureEd25519, token.PayloadBytes, token.Signature,
+	); err != nil {
+		return errors.Wrap(err, "invalid session revival token signature")
+	}
+	expiresAt, err := pbtypes.TimestampFromProto(payload.ExpiresAt)
+	if err != nil {
+		return errors.Wrap(err, "invalid session revival token")
+	}
+	if timeutil.Now().After(expiresAt) {
+		return errors.New("session revival token is expired")
+	}
+	if payload.User != user.Normalized() {
+		return errors.New("session revival token does not match the user")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sessionrevival_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessionrevival"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRevivalToken(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tenantID := security.EmbeddedTenantIDs()[0]
+	cm, err := security.NewCertificateManager(
+		security.EmbeddedCertsDir, security.CommandTLSSettings{}, security.ForTenant(tenantID),
+	)
+	require.NoError(t, err)
+
+	user := security.MakeSQLUsernameFromPreNormalizedString("testuser")
+	token, err := sessionrevival.CreateSessionRevivalToken(cm, user)
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		require.NoError(t, sessionrevival.ValidateSessionRevivalToken(cm, user, token))
+	})
+
+	t.Run("wrong user", func(t *testing.T) {
+		otherUser := security.MakeSQLUsernameFromPreNormalizedString("testuser2")
+		err := sessionrevival.ValidateSessionRevivalToken(cm, otherUser, token)
+		require.EqualError(t, err, "session revival token does not match the user")
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		var tok sessiondatapb.SessionRevivalToken
+		require.NoError(t, protoutil.Unmarshal(token, &tok))
+		var payload sessiondatapb.SessionRevivalToken_Payload
+		require.NoError(t, protoutil.Unmarshal(tok.PayloadBytes, &payload))
+		payload.User = "testuser2"
+		tok.PayloadBytes, err = protoutil.Marshal(&payload)
+		require.NoError(t, err)
+		tampered, err := protoutil.Marshal(&tok)
+		require.NoError(t, err)
+
+		otherUser := security.MakeSQLUsernameFromPreNormalizedString("testuser2")
+		err = sessionrevival.ValidateSessionRevivalToken(cm, otherUser, tampered)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid session revival token signature")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		err := sessionrevival.ValidateSessionRevivalToken(cm, user, []byte("garbage"))
+		require.Error(t, err)
+	})
+}
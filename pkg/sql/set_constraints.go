@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/errors"
+)
+
+// SetConstraints sets the checking mode of DEFERRABLE constraints for the
+// current transaction. If constraints become IMMEDIATE, their pending checks
+// are run right away.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	for _, name := range n.Names {
+		row, err := p.QueryRowEx(
+			ctx, "set-constraints", p.txn, sessiondata.NoSessionDataOverride,
+			`SELECT count(*), count(*) FILTER (WHERE condeferrable)
+			   FROM pg_catalog.pg_constraint WHERE conname = $1`,
+			string(name),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return nil, errors.AssertionFailedf("expected a row for constraint %q", name)
+		}
+		if tree.MustBeDInt(row[0]) == 0 {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", name)
+		}
+		if tree.MustBeDInt(row[0]) != tree.MustBeDInt(row[1]) {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"constraint %q is not deferrable", name)
+		}
+	}
+
+	dc := p.extendedEvalCtx.DeferredConstraints
+	if p.extendedEvalCtx.TxnImplicit || dc == nil {
+		// Like Postgres, we only warn if there is no transaction block, since
+		// the statement would have no effect.
+		p.BufferClientNotice(ctx, pgnotice.NewWithSeverityf(
+			"WARNING", "SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	dc.setMode(n.Names, n.Deferred)
+	if !n.Deferred {
+		if err := dc.runChecks(
+			ctx, p.txn, p.ExecCfg().InternalExecutor, p.Descriptors(), true, /* onlyImmediate */
+		); err != nil {
+			return nil, err
+		}
+	}
+	return newZeroNode(nil /* columns */), nil
+}
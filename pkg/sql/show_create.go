@@ -175,6 +175,8 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	showCreateStorageParams(desc, f)
+
 	if err := showCreateLocality(desc, f); err != nil {
 		return "", err
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -358,6 +359,7 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	buf.WriteString(tree.AsString(descpb.ConstraintDeferrabilityType[fk.Deferrability]))
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -425,6 +427,37 @@ func showCreateLocality(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
 	return nil
 }
 
+// showCreateStorageParams writes the WITH clause holding the storage
+// parameters of the table, if it has any.
+func showCreateStorageParams(desc catalog.TableDescriptor, f *tree.FmtCtx) {
+	ttl := desc.GetRowLevelTTL()
+	if ttl == nil {
+		return
+	}
+	params := []string{fmt.Sprintf("ttl_expire_after = %s", ttl.DurationExpr)}
+	if ttl.DeletionCron != "" {
+		params = append(params, fmt.Sprintf("ttl_job_cron = %s", lexbase.EscapeSQLString(ttl.DeletionCron)))
+	}
+	if ttl.SelectBatchSize != 0 {
+		params = append(params, fmt.Sprintf("ttl_select_batch_size = %d", ttl.SelectBatchSize))
+	}
+	if ttl.DeleteBatchSize != 0 {
+		params = append(params, fmt.Sprintf("ttl_delete_batch_size = %d", ttl.DeleteBatchSize))
+	}
+	if ttl.RangeConcurrency != 0 {
+		params = append(params, fmt.Sprintf("ttl_range_concurrency = %d", ttl.RangeConcurrency))
+	}
+	if ttl.DeleteRateLimit != 0 {
+		params = append(params, fmt.Sprintf("ttl_delete_rate_limit = %d", ttl.DeleteRateLimit))
+	}
+	if ttl.Pause {
+		params = append(params, "ttl_pause = true")
+	}
+	f.WriteString(" WITH (")
+	f.WriteString(strings.Join(params, ", "))
+	f.WriteString(")")
+}
+
 // ShowCreatePartitioning returns a PARTITION BY clause for the specified
 // index, if applicable.
 func ShowCreatePartitioning(
@@ -598,6 +631,7 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		f.FormatNode(descpb.ConstraintDeferrabilityType[c.Deferrability])
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
@@ -610,6 +644,40 @@ func showConstraintClause(
 			f.WriteString(" NOT VALID")
 		}
 	}
+	for _, c := range desc.GetExclusionConstraints() {
+		f.WriteString(",\n\t")
+		f.WriteString("CONSTRAINT ")
+		formatQuoteNames(&f.Buffer, c.Name)
+		f.WriteString(" EXCLUDE ")
+		if c.IndexMethod != "" {
+			f.WriteString("USING ")
+			f.WriteString(c.IndexMethod)
+			f.WriteString(" ")
+		}
+		f.WriteString("(")
+		for i, colID := range c.ColumnIDs {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			col, err := desc.FindColumnWithID(colID)
+			if err != nil {
+				return err
+			}
+			formatQuoteNames(&f.Buffer, col.GetName())
+			f.WriteString(" WITH ")
+			f.WriteString(c.Operators[i])
+		}
+		f.WriteString(")")
+		if c.IsPartial() {
+			f.WriteString(" WHERE (")
+			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
+			if err != nil {
+				return err
+			}
+			f.WriteString(pred)
+			f.WriteString(")")
+		}
+	}
 	f.WriteString("\n)")
 	return nil
 }
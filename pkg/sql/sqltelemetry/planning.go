@@ -37,6 +37,10 @@ var CorrelatedSubqueryUseCounter = telemetry.GetCounterOnce("sql.plan.subquery.c
 // unique checks and the checks are planned by the optimizer.
 var UniqueChecksUseCounter = telemetry.GetCounterOnce("sql.plan.unique.checks")
 
+// ExclusionChecksUseCounter is to be incremented every time a mutation has
+// exclusion checks and the checks are planned by the optimizer.
+var ExclusionChecksUseCounter = telemetry.GetCounterOnce("sql.plan.exclusion.checks")
+
 // ForeignKeyChecksUseCounter is to be incremented every time a mutation has
 // foreign key checks and the checks are planned by the optimizer.
 var ForeignKeyChecksUseCounter = telemetry.GetCounterOnce("sql.plan.fk.checks")
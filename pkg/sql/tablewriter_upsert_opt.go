@@ -79,12 +79,22 @@ type optTableUpserter struct {
 	// an update is performed. This column will always be one of the fetchCols.
 	canaryOrdinal int
 
+	// deleteOrdinal is the ordinal position of the boolean column within the
+	// input row that is true if the existing row must be deleted instead of
+	// updated. It is -1 if rows are never deleted, which is only possible with
+	// a MERGE statement.
+	deleteOrdinal int
+
 	// resultRow is a reusable slice of Datums used to store result rows.
 	resultRow tree.Datums
 
 	// ru is used when updating rows.
 	ru row.Updater
 
+	// rd is used when deleting rows. It is only initialized if deleteOrdinal is
+	// not -1.
+	rd row.Deleter
+
 	// tabColIdxToRetIdx is the mapping from the columns in the table to the
 	// columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the table column at that index is not part of the resultRowBuffer
@@ -180,8 +190,13 @@ func (tu *optTableUpserter) row(
 		return tu.insertNonConflictingRow(ctx, tu.b, row[:insertEnd], pm, false /* overwrite */, traceKV)
 	}
 
-	// If no columns need to be updated, then possibly collect the unchanged row.
 	fetchEnd := insertEnd + len(tu.fetchCols)
+	if tu.deleteOrdinal != -1 && row[tu.deleteOrdinal] == tree.DBoolTrue {
+		// Delete the existing row.
+		return tu.deleteConflictingRow(ctx, tu.b, row[insertEnd:fetchEnd], pm, traceKV)
+	}
+
+	// If no columns need to be updated, then possibly collect the unchanged row.
 	if len(tu.updateCols) == 0 {
 		if !tu.rowsNeeded {
 			return nil
@@ -317,6 +332,34 @@ func (tu *optTableUpserter) updateConflictingRow(
 	return err
 }
 
+// deleteConflictingRow deletes an existing row from the table. The existing
+// values from the row are provided in fetchRow. If the RETURNING clause was
+// specified, then the deleted row is stored in the rowsUpserted collection.
+func (tu *optTableUpserter) deleteConflictingRow(
+	ctx context.Context,
+	b *kv.Batch,
+	fetchRow tree.Datums,
+	pm row.PartialIndexUpdateHelper,
+	traceKV bool,
+) error {
+	if err := tu.rd.DeleteRow(ctx, b, fetchRow, pm, traceKV); err != nil {
+		return err
+	}
+
+	if !tu.rowsNeeded {
+		return nil
+	}
+
+	tableRow := tu.makeResultFromRow(fetchRow, tu.rd.FetchColIDtoRowIndex)
+	for tabIdx := range tableRow {
+		if retIdx := tu.tabColIdxToRetIdx[tabIdx]; retIdx >= 0 {
+			tu.resultRow[retIdx] = tableRow[tabIdx]
+		}
+	}
+	_, err := tu.rows.AddRow(ctx, tu.resultRow)
+	return err
+}
+
 // tableDesc is part of the tableWriter interface.
 func (tu *optTableUpserter) tableDesc() catalog.TableDescriptor {
 	return tu.ri.Helper.TableDesc
@@ -0,0 +1,85 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+)
+
+// KeyToDatums is a wrapper around the internal function that decodes the
+// primary key columns encoded in a key of the primary index.
+func KeyToDatums(
+	key roachpb.Key, codec keys.SQLCodec, pkTypes []*types.T,
+) (tree.Datums, error) {
+	var alloc tree.DatumAlloc
+	return keyToDatums(roachpb.RKey(key), codec, pkTypes, &alloc)
+}
+
+// SplitIntoRanges is a wrapper around the internal function that splits the
+// span along range boundaries. It returns the start and end primary keys of
+// each range.
+func SplitIntoRanges(
+	ctx context.Context, execCfg *sql.ExecutorConfig, span roachpb.Span, pkTypes []*types.T,
+) ([][2]tree.Datums, error) {
+	ranges, err := splitIntoRanges(ctx, execCfg.DistSender, execCfg.Codec, span, pkTypes)
+	if err != nil {
+		return nil, err
+	}
+	bounds := make([][2]tree.Datums, len(ranges))
+	for i, r := range ranges {
+		bounds[i] = [2]tree.Datums{r.startPK, r.endPK}
+	}
+	return bounds, nil
+}
+
+// ProcessRange is a wrapper around the internal function that deletes the
+// rows of the table between the given primary keys which expired before the
+// cutoff. It returns the number of rows deleted, and the number of SELECT and
+// DELETE queries run to do so.
+func ProcessRange(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tableID descpb.ID,
+	pkColumns []string,
+	startPK, endPK tree.Datums,
+	cutoff time.Time,
+	selectBatchSize, deleteBatchSize int64,
+) (rowCount int64, selects int64, deletes int64, err error) {
+	metrics := makeRowLevelTTLAggMetrics(time.Minute).(*RowLevelTTLAggMetrics).loadMetrics("test")
+	q := ttlQuerier{
+		ie:              execCfg.InternalExecutor,
+		db:              execCfg.DB,
+		tableID:         tableID,
+		pkColumns:       pkColumns,
+		cutoff:          cutoff,
+		aost:            execCfg.Clock.Now(),
+		selectBatchSize: selectBatchSize,
+		deleteBatchSize: deleteBatchSize,
+		rateLimiter: quotapool.NewRateLimiter(
+			"ttl-delete", quotapool.Limit(math.MaxInt64), deleteBatchSize,
+		),
+		metrics: metrics,
+	}
+	rowCount, err = q.processRange(ctx, rangeToProcess{startPK: startPK, endPK: endPK})
+	selects = int64(metrics.SelectDuration.ToPrometheusMetric().Histogram.GetSampleCount())
+	deletes = int64(metrics.DeleteDuration.ToPrometheusMetric().Histogram.GetSampleCount())
+	return rowCount, selects, deletes, err
+}
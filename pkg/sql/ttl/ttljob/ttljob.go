@@ -0,0 +1,520 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ttljob implements the job which deletes the expired rows of a table
+// with row-level TTL.
+//
+// The job splits the primary index of the table along range boundaries and
+// processes ranges concurrently. For each range, it pages through the expired
+// rows in ascending primary key order using AS OF SYSTEM TIME queries, and
+// deletes them in small batches, each in its own transaction, re-checking
+// that rows are still expired. Deletions are paced by a rate limiter.
+package ttljob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	defaultSelectBatchSize = settings.RegisterIntSetting(
+		settings.TenantWritable,
+		"sql.ttl.default_select_batch_size",
+		"default amount of rows to select in a single query during a TTL job",
+		500,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteBatchSize = settings.RegisterIntSetting(
+		settings.TenantWritable,
+		"sql.ttl.default_delete_batch_size",
+		"default amount of rows to delete in a single query during a TTL job",
+		100,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultRangeConcurrency = settings.RegisterIntSetting(
+		settings.TenantWritable,
+		"sql.ttl.default_range_concurrency",
+		"default amount of ranges to process at once during a TTL delete",
+		1,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteRateLimit = settings.RegisterIntSetting(
+		settings.TenantWritable,
+		"sql.ttl.default_delete_rate_limit",
+		"default delete rate limit for all TTL jobs; use 0 to signify no rate limit",
+		0,
+		settings.NonNegativeInt,
+	).WithPublic()
+	jobEnabled = settings.RegisterBoolSetting(
+		settings.TenantWritable,
+		"sql.ttl.job.enabled",
+		"whether the TTL job is enabled",
+		true,
+	).WithPublic()
+)
+
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*rowLevelTTLResumer)(nil)
+
+// rangeToProcess is the part of the primary index of the table covered by a
+// range, as bounds on the primary key. A nil bound is unbounded. Bounds may
+// only cover a prefix of the primary key columns.
+type rangeToProcess struct {
+	startPK, endPK tree.Datums
+}
+
+// Resume implements the jobs.Resumer interface.
+func (t rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	db := execCfg.DB
+	details := t.job.Details().(jobspb.RowLevelTTLDetails)
+
+	if !jobEnabled.Get(&t.st.SV) {
+		return errors.Newf(
+			"ttl jobs are currently disabled by CLUSTER SETTING %s",
+			jobEnabled.Key(),
+		)
+	}
+
+	aost := hlc.Timestamp{WallTime: details.Cutoff.UnixNano()}
+
+	var relationName string
+	var pkColumns []string
+	var pkTypes []*types.T
+	var primaryIndexSpan roachpb.Span
+	var ttlSettings catpb.RowLevelTTL
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, descriptors *descs.Collection,
+	) error {
+		desc, err := descriptors.GetImmutableTableByID(
+			ctx, txn, details.TableID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() {
+			return errors.Newf("table %s is being dropped", desc.GetName())
+		}
+		ttl := desc.GetRowLevelTTL()
+		if ttl == nil {
+			return errors.Newf("unable to find TTL on table %s", desc.GetName())
+		}
+		ttlSettings = *ttl
+
+		pk := desc.GetPrimaryIndex()
+		pkColumns = make([]string, pk.NumKeyColumns())
+		pkTypes = make([]*types.T, pk.NumKeyColumns())
+		for i := 0; i < pk.NumKeyColumns(); i++ {
+			col, err := desc.FindColumnWithID(pk.GetKeyColumnID(i))
+			if err != nil {
+				return err
+			}
+			pkColumns[i] = col.GetName()
+			pkTypes[i] = col.GetType()
+		}
+		primaryIndexSpan = desc.PrimaryIndexSpan(execCfg.Codec)
+
+		_, dbDesc, err := descriptors.GetImmutableDatabaseByID(
+			ctx, txn, desc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		schemaDesc, err := descriptors.GetImmutableSchemaByID(
+			ctx, txn, desc.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		relationName = fmt.Sprintf("%s_%s_%s", dbDesc.GetName(), schemaDesc.GetName(), desc.GetName())
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if ttlSettings.Pause {
+		return errors.Newf("ttl jobs on table %s are currently paused", relationName)
+	}
+
+	selectBatchSize := ttlSettings.SelectBatchSize
+	if selectBatchSize == 0 {
+		selectBatchSize = defaultSelectBatchSize.Get(&t.st.SV)
+	}
+	deleteBatchSize := ttlSettings.DeleteBatchSize
+	if deleteBatchSize == 0 {
+		deleteBatchSize = defaultDeleteBatchSize.Get(&t.st.SV)
+	}
+	rangeConcurrency := ttlSettings.RangeConcurrency
+	if rangeConcurrency == 0 {
+		rangeConcurrency = defaultRangeConcurrency.Get(&t.st.SV)
+	}
+	deleteRateLimit := ttlSettings.DeleteRateLimit
+	if deleteRateLimit == 0 {
+		deleteRateLimit = defaultDeleteRateLimit.Get(&t.st.SV)
+	}
+	// A rate limit of zero means no limit.
+	rateLimit := quotapool.Limit(deleteRateLimit)
+	if deleteRateLimit == 0 {
+		rateLimit = quotapool.Limit(math.MaxInt64)
+	}
+	deleteRateLimiter := quotapool.NewRateLimiter(
+		"ttl-delete",
+		rateLimit,
+		deleteBatchSize,
+	)
+
+	ranges, err := splitIntoRanges(ctx, execCfg.DistSender, execCfg.Codec, primaryIndexSpan, pkTypes)
+	if err != nil {
+		return err
+	}
+	if err := t.job.Update(ctx, nil /* txn */, func(
+		_ *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress
+		prog := progress.Details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+		prog.RangeCount = int64(len(ranges))
+		ju.UpdateProgress(progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	metrics := execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*RowLevelTTLAggMetrics).loadMetrics(relationName)
+	q := ttlQuerier{
+		ie:              execCfg.InternalExecutor,
+		db:              db,
+		tableID:         details.TableID,
+		pkColumns:       pkColumns,
+		cutoff:          details.Cutoff,
+		aost:            aost,
+		selectBatchSize: selectBatchSize,
+		deleteBatchSize: deleteBatchSize,
+		rateLimiter:     deleteRateLimiter,
+		metrics:         metrics,
+	}
+
+	ch := make(chan rangeToProcess)
+	g := ctxgroup.WithContext(ctx)
+	for i := int64(0); i < rangeConcurrency; i++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for r := range ch {
+				start := timeutil.Now()
+				metrics.NumActiveRanges.Inc(1)
+				rowCount, err := q.processRange(ctx, r)
+				metrics.NumActiveRanges.Dec(1)
+				metrics.RangeTotalDuration.RecordValue(int64(timeutil.Since(start)))
+				if err != nil {
+					return err
+				}
+				if err := t.job.FractionProgressed(ctx, nil /* txn */, func(
+					ctx context.Context, details jobspb.ProgressDetails,
+				) float32 {
+					prog := details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+					prog.RowCount += rowCount
+					prog.RangesProcessed++
+					return float32(prog.RangesProcessed) / float32(prog.RangeCount)
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(ch)
+		for _, r := range ranges {
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	return g.Wait()
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (t rowLevelTTLResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	return nil
+}
+
+// splitIntoRanges returns the parts of the span covered by each of its
+// ranges, as bounds on the primary key.
+func splitIntoRanges(
+	ctx context.Context,
+	ds *kvcoord.DistSender,
+	codec keys.SQLCodec,
+	span roachpb.Span,
+	pkTypes []*types.T,
+) ([]rangeToProcess, error) {
+	rSpan, err := keys.SpanAddr(span)
+	if err != nil {
+		return nil, err
+	}
+	var alloc tree.DatumAlloc
+	var ranges []rangeToProcess
+	ri := kvcoord.MakeRangeIterator(ds)
+	for ri.Seek(ctx, rSpan.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+		if !ri.Valid() {
+			return nil, ri.Error()
+		}
+		startKey, endKey := ri.Desc().StartKey, ri.Desc().EndKey
+		if startKey.Less(rSpan.Key) {
+			startKey = rSpan.Key
+		}
+		if rSpan.EndKey.Less(endKey) {
+			endKey = rSpan.EndKey
+		}
+		startPK, err := keyToDatums(startKey, codec, pkTypes, &alloc)
+		if err != nil {
+			return nil, err
+		}
+		endPK, err := keyToDatums(endKey, codec, pkTypes, &alloc)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rangeToProcess{startPK: startPK, endPK: endPK})
+		if !ri.NeedAnother(rSpan) {
+			return ranges, nil
+		}
+	}
+}
+
+// keyToDatums decodes the primary key columns encoded in the given key of
+// the primary index. Range boundaries need not fall on row boundaries, so
+// only the prefix of columns which are fully encoded in the key is decoded. A
+// key which does not extend past the index prefix results in no datums.
+// Descriptor validation ensures the primary key columns are all ascending.
+func keyToDatums(
+	key roachpb.RKey, codec keys.SQLCodec, pkTypes []*types.T, alloc *tree.DatumAlloc,
+) (tree.Datums, error) {
+	rest, _, _, err := codec.DecodeIndexPrefix(key.AsRawKey())
+	if err != nil {
+		// The key falls before the start of the index, e.g. on the table
+		// prefix, so it doesn't bound the primary key.
+		return nil, nil //nolint:returnerrcheck
+	}
+	var datums tree.Datums
+	for len(rest) > 0 && len(datums) < len(pkTypes) {
+		typ := pkTypes[len(datums)]
+		var encDatum rowenc.EncDatum
+		encDatum, rest, err = rowenc.EncDatumFromBuffer(typ, descpb.DatumEncoding_ASCENDING_KEY, rest)
+		if err != nil {
+			break
+		}
+		if err := encDatum.EnsureDecoded(typ, alloc); err != nil {
+			break
+		}
+		datums = append(datums, encDatum.Datum)
+	}
+	return datums, nil
+}
+
+// ttlQuerier runs the queries of the TTL job for a table.
+type ttlQuerier struct {
+	ie              *sql.InternalExecutor
+	db              *kv.DB
+	tableID         descpb.ID
+	pkColumns       []string
+	cutoff          time.Time
+	aost            hlc.Timestamp
+	selectBatchSize int64
+	deleteBatchSize int64
+	rateLimiter     *quotapool.RateLimiter
+	metrics         rowLevelTTLMetrics
+}
+
+// processRange deletes the expired rows of the range, and returns the number
+// of rows which were deleted.
+func (q *ttlQuerier) processRange(ctx context.Context, r rangeToProcess) (int64, error) {
+	var rowCount int64
+	var lastRow tree.Datums
+	for {
+		expiredPKs, err := q.selectExpired(ctx, r, lastRow)
+		if err != nil {
+			return rowCount, err
+		}
+		for startIdx := 0; startIdx < len(expiredPKs); startIdx += int(q.deleteBatchSize) {
+			endIdx := startIdx + int(q.deleteBatchSize)
+			if endIdx > len(expiredPKs) {
+				endIdx = len(expiredPKs)
+			}
+			deleted, err := q.deleteExpired(ctx, expiredPKs[startIdx:endIdx])
+			if err != nil {
+				return rowCount, err
+			}
+			rowCount += deleted
+		}
+		if int64(len(expiredPKs)) < q.selectBatchSize {
+			return rowCount, nil
+		}
+		lastRow = expiredPKs[len(expiredPKs)-1]
+	}
+}
+
+// selectExpired returns the primary keys of the next page of expired rows in
+// the range, after lastRow if it is set.
+func (q *ttlQuerier) selectExpired(
+	ctx context.Context, r rangeToProcess, lastRow tree.Datums,
+) ([]tree.Datums, error) {
+	var buf bytes.Buffer
+	pkList := q.pkColumnList(len(q.pkColumns))
+	fmt.Fprintf(&buf, "SELECT %s FROM [%d AS tbl] AS OF SYSTEM TIME %s WHERE %s <= $1",
+		pkList,
+		q.tableID,
+		lexbase.EscapeSQLString(q.aost.AsOfSystemTime()),
+		catpb.TTLDefaultExpirationColumnName,
+	)
+	args := []interface{}{q.cutoff}
+	addBound := func(op string, datums tree.Datums) {
+		if len(datums) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, " AND (%s) %s (", q.pkColumnList(len(datums)), op)
+		for i, d := range datums {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			args = append(args, d)
+			fmt.Fprintf(&buf, "$%d", len(args))
+		}
+		buf.WriteString(")")
+	}
+	addBound(">=", r.startPK)
+	addBound("<", r.endPK)
+	addBound(">", lastRow)
+	fmt.Fprintf(&buf, " ORDER BY %s LIMIT %d", pkList, q.selectBatchSize)
+
+	start := timeutil.Now()
+	rows, err := q.ie.QueryBufferedEx(
+		ctx,
+		"ttl-select",
+		nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		buf.String(),
+		args...,
+	)
+	q.metrics.SelectDuration.RecordValue(int64(timeutil.Since(start)))
+	if err != nil {
+		return nil, err
+	}
+	q.metrics.RowSelections.Inc(int64(len(rows)))
+	return rows, nil
+}
+
+// deleteExpired deletes the rows with the given primary keys which are still
+// expired, and returns the number of rows which were deleted.
+func (q *ttlQuerier) deleteExpired(ctx context.Context, pks []tree.Datums) (int64, error) {
+	if len(pks) == 0 {
+		return 0, nil
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DELETE FROM [%d AS tbl] WHERE %s <= $1 AND (%s) IN (",
+		q.tableID,
+		catpb.TTLDefaultExpirationColumnName,
+		q.pkColumnList(len(q.pkColumns)),
+	)
+	args := []interface{}{q.cutoff}
+	for i, pk := range pks {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(")
+		for j, d := range pk {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			args = append(args, d)
+			fmt.Fprintf(&buf, "$%d", len(args))
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(")")
+
+	if err := q.rateLimiter.WaitN(ctx, int64(len(pks))); err != nil {
+		return 0, err
+	}
+	var deleted int
+	start := timeutil.Now()
+	if err := q.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		var err error
+		deleted, err = q.ie.ExecEx(
+			ctx,
+			"ttl-delete",
+			txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			buf.String(),
+			args...,
+		)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	q.metrics.DeleteDuration.RecordValue(int64(timeutil.Since(start)))
+	q.metrics.RowDeletions.Inc(int64(deleted))
+	log.VEventf(ctx, 2, "deleted %d expired rows from table %d", deleted, q.tableID)
+	return int64(deleted), nil
+}
+
+// pkColumnList returns the comma separated, quoted names of the first n
+// primary key columns.
+func (q *ttlQuerier) pkColumnList(n int) string {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		lexbase.EncodeRestrictedSQLIdent(&buf, q.pkColumns[i], lexbase.EncNoFlags)
+	}
+	return buf.String()
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{
+			job: job,
+			st:  settings,
+		}
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// defaultRelationLabel is the relation label of the metrics of tables past
+// maxRelationLabels.
+const defaultRelationLabel = "default"
+
+// maxRelationLabels bounds the number of tables which get their own metric
+// labels, so that a cluster with many TTL tables does not explode the number
+// of exported time series.
+const maxRelationLabels = 1024
+
+var (
+	metaRangeTotalDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.range_total_duration",
+		Help:        "Duration for processing a range during row level TTL",
+		Measurement: "nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaSelectDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.select_duration",
+		Help:        "Duration for select requests during row level TTL",
+		Measurement: "nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaDeleteDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.delete_duration",
+		Help:        "Duration for delete requests during row level TTL",
+		Measurement: "nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRowsSelected = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_selected",
+		Help:        "Number of expired rows selected for deletion by row level TTL",
+		Measurement: "num_rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowsDeleted = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_deleted",
+		Help:        "Number of rows deleted by row level TTL",
+		Measurement: "num_rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaNumActiveRanges = metric.Metadata{
+		Name:        "jobs.row_level_ttl.num_active_ranges",
+		Help:        "Number of active workers attempting to delete for row level TTL",
+		Measurement: "num_active_workers",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// RowLevelTTLAggMetrics are the row-level TTL job metrics, labeled by the
+// table they were recorded for.
+type RowLevelTTLAggMetrics struct {
+	RangeTotalDuration *aggmetric.AggHistogram
+	SelectDuration     *aggmetric.AggHistogram
+	DeleteDuration     *aggmetric.AggHistogram
+	RowSelections      *aggmetric.AggCounter
+	RowDeletions       *aggmetric.AggCounter
+	NumActiveRanges    *aggmetric.AggGauge
+
+	mu struct {
+		syncutil.Mutex
+		m map[string]rowLevelTTLMetrics
+	}
+}
+
+var _ metric.Struct = (*RowLevelTTLAggMetrics)(nil)
+
+// MetricStruct implements the metric.Struct interface.
+func (m *RowLevelTTLAggMetrics) MetricStruct() {}
+
+// rowLevelTTLMetrics are the row-level TTL metrics of a single table.
+type rowLevelTTLMetrics struct {
+	RangeTotalDuration *aggmetric.Histogram
+	SelectDuration     *aggmetric.Histogram
+	DeleteDuration     *aggmetric.Histogram
+	RowSelections      *aggmetric.Counter
+	RowDeletions       *aggmetric.Counter
+	NumActiveRanges    *aggmetric.Gauge
+}
+
+// loadMetrics returns the metrics of the given relation, creating them if
+// they do not exist yet.
+func (m *RowLevelTTLAggMetrics) loadMetrics(relation string) rowLevelTTLMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ret, ok := m.mu.m[relation]; ok {
+		return ret
+	}
+	if len(m.mu.m) >= maxRelationLabels {
+		relation = defaultRelationLabel
+		if ret, ok := m.mu.m[relation]; ok {
+			return ret
+		}
+	}
+	ret := rowLevelTTLMetrics{
+		RangeTotalDuration: m.RangeTotalDuration.AddChild(relation),
+		SelectDuration:     m.SelectDuration.AddChild(relation),
+		DeleteDuration:     m.DeleteDuration.AddChild(relation),
+		RowSelections:      m.RowSelections.AddChild(relation),
+		RowDeletions:       m.RowDeletions.AddChild(relation),
+		NumActiveRanges:    m.NumActiveRanges.AddChild(relation),
+	}
+	m.mu.m[relation] = ret
+	return ret
+}
+
+func makeRowLevelTTLAggMetrics(histogramWindowInterval time.Duration) metric.Struct {
+	sigFigs := 1
+	b := aggmetric.MakeBuilder("relation")
+	ret := &RowLevelTTLAggMetrics{
+		RangeTotalDuration: b.Histogram(
+			metaRangeTotalDuration,
+			histogramWindowInterval,
+			time.Hour.Nanoseconds(),
+			sigFigs,
+		),
+		SelectDuration: b.Histogram(
+			metaSelectDuration,
+			histogramWindowInterval,
+			time.Minute.Nanoseconds(),
+			sigFigs,
+		),
+		DeleteDuration: b.Histogram(
+			metaDeleteDuration,
+			histogramWindowInterval,
+			time.Minute.Nanoseconds(),
+			sigFigs,
+		),
+		RowSelections:   b.Counter(metaRowsSelected),
+		RowDeletions:    b.Counter(metaRowsDeleted),
+		NumActiveRanges: b.Gauge(metaNumActiveRanges),
+	}
+	ret.mu.m = make(map[string]rowLevelTTLMetrics)
+	return ret
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = makeRowLevelTTLAggMetrics
+}
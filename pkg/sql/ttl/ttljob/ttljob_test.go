@@ -0,0 +1,177 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/ttl/ttljob"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyToDatums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	codec := keys.SystemSQLCodec
+	pkTypes := []*types.T{types.Int, types.String}
+	indexPrefix := codec.IndexPrefix(100, 1)
+	key := func(suffix ...byte) roachpb.Key {
+		return append(append(roachpb.Key(nil), indexPrefix...), suffix...)
+	}
+	fullKey := key(encoding.EncodeStringAscending(encoding.EncodeVarintAscending(nil, 1), "abc")...)
+
+	testCases := []struct {
+		desc     string
+		key      roachpb.Key
+		expected string
+	}{
+		{desc: "table prefix", key: codec.TablePrefix(100), expected: `()`},
+		{desc: "index prefix", key: indexPrefix, expected: `()`},
+		{desc: "index end", key: indexPrefix.PrefixEnd(), expected: `()`},
+		{desc: "first column", key: key(encoding.EncodeVarintAscending(nil, 1)...), expected: `(1)`},
+		{desc: "truncated column", key: fullKey[:len(fullKey)-2], expected: `(1)`},
+		{desc: "full key", key: fullKey, expected: `(1, 'abc')`},
+		{desc: "family key", key: keys.MakeFamilyKey(fullKey, 0), expected: `(1, 'abc')`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			datums, err := ttljob.KeyToDatums(tc.key, codec, pkTypes)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, tree.AsString(&datums))
+		})
+	}
+}
+
+func TestSplitIntoRanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+	runner.Exec(t, `CREATE DATABASE db`)
+	runner.Exec(t, `CREATE TABLE db.t (a INT, b STRING, PRIMARY KEY (a, b)) WITH (ttl_expire_after = '10 minutes')`)
+	runner.Exec(t, `ALTER TABLE db.t SPLIT AT VALUES (1, 'x'), (2)`)
+
+	desc := catalogkv.TestingGetImmutableTableDescriptor(kvDB, keys.SystemSQLCodec, "db", "t")
+	bounds, err := ttljob.SplitIntoRanges(
+		ctx, &execCfg, desc.PrimaryIndexSpan(keys.SystemSQLCodec), []*types.T{types.Int, types.String},
+	)
+	require.NoError(t, err)
+
+	var actual [][2]string
+	for _, b := range bounds {
+		actual = append(actual, [2]string{tree.AsString(&b[0]), tree.AsString(&b[1])})
+	}
+	require.Equal(t, [][2]string{
+		{`()`, `(1, 'x')`},
+		{`(1, 'x')`, `(2)`},
+		{`(2)`, `()`},
+	}, actual)
+}
+
+func TestProcessRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+	runner.Exec(t, `CREATE DATABASE db`)
+	runner.Exec(t, `CREATE TABLE db.t (id INT PRIMARY KEY) WITH (ttl_expire_after = '10 minutes')`)
+	// Rows 1 to 10 have expired, rows 11 and 12 have not.
+	runner.Exec(t, `INSERT INTO db.t (id, crdb_internal_expiration)
+SELECT i, now() - '1 hour'::INTERVAL FROM generate_series(1, 10) AS g(i)`)
+	runner.Exec(t, `INSERT INTO db.t (id) VALUES (11), (12)`)
+
+	desc := catalogkv.TestingGetImmutableTableDescriptor(kvDB, keys.SystemSQLCodec, "db", "t")
+	cutoff := timeutil.Now()
+
+	testCases := []struct {
+		desc            string
+		startPK, endPK  tree.Datums
+		selectBatchSize int64
+		deleteBatchSize int64
+
+		expectedRows    int64
+		expectedSelects int64
+		expectedDeletes int64
+		expectedIDs     [][]string
+	}{
+		{
+			// Rows 3 to 6 are selected in two full pages, followed by an empty
+			// page, and deleted one at a time.
+			desc:            "bounded range",
+			startPK:         tree.Datums{tree.NewDInt(3)},
+			endPK:           tree.Datums{tree.NewDInt(7)},
+			selectBatchSize: 2,
+			deleteBatchSize: 1,
+			expectedRows:    4,
+			expectedSelects: 3,
+			expectedDeletes: 4,
+			expectedIDs:     [][]string{{"1"}, {"2"}, {"7"}, {"8"}, {"9"}, {"10"}, {"11"}, {"12"}},
+		},
+		{
+			// Rows 1, 2 and 7 to 10 are selected in two pages of three, followed
+			// by an empty page, and each page is deleted in batches of two and one.
+			desc:            "unbounded range",
+			selectBatchSize: 3,
+			deleteBatchSize: 2,
+			expectedRows:    6,
+			expectedSelects: 3,
+			expectedDeletes: 4,
+			expectedIDs:     [][]string{{"11"}, {"12"}},
+		},
+		{
+			desc:            "nothing expired",
+			selectBatchSize: 3,
+			deleteBatchSize: 2,
+			expectedRows:    0,
+			expectedSelects: 1,
+			expectedDeletes: 0,
+			expectedIDs:     [][]string{{"11"}, {"12"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rows, selects, deletes, err := ttljob.ProcessRange(
+				ctx, &execCfg, desc.GetID(), []string{"id"}, tc.startPK, tc.endPK, cutoff,
+				tc.selectBatchSize, tc.deleteBatchSize,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRows, rows)
+			require.Equal(t, tc.expectedSelects, selects)
+			require.Equal(t, tc.expectedDeletes, deletes)
+			runner.CheckQueryResults(t, `SELECT id FROM db.t ORDER BY id`, tc.expectedIDs)
+		})
+	}
+}
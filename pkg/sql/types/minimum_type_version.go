@@ -16,7 +16,16 @@ import "github.com/cockroachdb/cockroach/pkg/clusterversion"
 // data type.
 // Note: please do not remove this map or IsTypeSupportedInVersion even
 // if the map becomes empty temporarily.
-var minimumTypeUsageVersions = map[*T]clusterversion.Key{}
+var minimumTypeUsageVersions = map[*T]clusterversion.Key{
+	TSQuery:   clusterversion.TSearch,
+	TSVector:  clusterversion.TSearch,
+	Int4Range: clusterversion.RangeTypes,
+	Int8Range: clusterversion.RangeTypes,
+	NumRange:  clusterversion.RangeTypes,
+	TSRange:   clusterversion.RangeTypes,
+	TSTZRange: clusterversion.RangeTypes,
+	DateRange: clusterversion.RangeTypes,
+}
 
 // IsTypeSupportedInVersion returns whether a given type is supported in the given version.
 func IsTypeSupportedInVersion(v clusterversion.ClusterVersion, t *T) bool {
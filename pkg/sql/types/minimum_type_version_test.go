@@ -32,6 +32,14 @@ func TestIsTypeSupportedInVersion(t *testing.T) {
 	}{
 		{clusterversion.TODOPreV21_2, RegRole, true},
 		{clusterversion.TODOPreV21_2, MakeArray(RegRole), true},
+		{clusterversion.RowLevelTriggers, TSVector, false},
+		{clusterversion.RowLevelTriggers, MakeArray(TSQuery), false},
+		{clusterversion.TSearch, TSVector, true},
+		{clusterversion.TSearch, TSQuery, true},
+		{clusterversion.TSearch, TSTZRange, false},
+		{clusterversion.TSearch, MakeArray(Int8Range), false},
+		{clusterversion.RangeTypes, Int4Range, true},
+		{clusterversion.RangeTypes, DateRange, true},
 	}
 
 	for _, tc := range testCases {
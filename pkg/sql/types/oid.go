@@ -66,17 +66,21 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_bytea:        Bytes,
 	oid.T_char:         QChar,
 	oid.T_date:         Date,
+	oid.T_daterange:    DateRange,
 	oid.T_float4:       Float4,
 	oid.T_float8:       Float,
 	oid.T_int2:         Int2,
 	oid.T_int2vector:   Int2Vector,
 	oid.T_int4:         Int4,
+	oid.T_int4range:    Int4Range,
 	oid.T_int8:         Int,
+	oid.T_int8range:    Int8Range,
 	oid.T_inet:         INet,
 	oid.T_interval:     Interval,
 	oid.T_jsonb:        Jsonb,
 	oid.T_name:         Name,
 	oid.T_numeric:      Decimal,
+	oid.T_numrange:     NumRange,
 	oid.T_oid:          Oid,
 	oid.T_oidvector:    OidVector,
 	oid.T_record:       AnyTuple,
@@ -91,6 +95,10 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_tsquery:      TSQuery,
+	oid.T_tsrange:      TSRange,
+	oid.T_tstzrange:    TSTZRange,
+	oid.T_tsvector:     TSVector,
 	oid.T_unknown:      Unknown,
 	oid.T_uuid:         Uuid,
 	oid.T_varbit:       VarBit,
@@ -111,17 +119,21 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_bytea:        oid.T__bytea,
 	oid.T_char:         oid.T__char,
 	oid.T_date:         oid.T__date,
+	oid.T_daterange:    oid.T__daterange,
 	oid.T_float4:       oid.T__float4,
 	oid.T_float8:       oid.T__float8,
 	oid.T_inet:         oid.T__inet,
 	oid.T_int2:         oid.T__int2,
 	oid.T_int2vector:   oid.T__int2vector,
 	oid.T_int4:         oid.T__int4,
+	oid.T_int4range:    oid.T__int4range,
 	oid.T_int8:         oid.T__int8,
+	oid.T_int8range:    oid.T__int8range,
 	oid.T_interval:     oid.T__interval,
 	oid.T_jsonb:        oid.T__jsonb,
 	oid.T_name:         oid.T__name,
 	oid.T_numeric:      oid.T__numeric,
+	oid.T_numrange:     oid.T__numrange,
 	oid.T_oid:          oid.T__oid,
 	oid.T_oidvector:    oid.T__oidvector,
 	oid.T_record:       oid.T__record,
@@ -136,6 +148,10 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_timetz:       oid.T__timetz,
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
+	oid.T_tsquery:      oid.T__tsquery,
+	oid.T_tsrange:      oid.T__tsrange,
+	oid.T_tstzrange:    oid.T__tstzrange,
+	oid.T_tsvector:     oid.T__tsvector,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
 	oid.T_varchar:      oid.T__varchar,
@@ -171,6 +187,9 @@ var familyToOid = map[Family]oid.Oid{
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
 	AnyFamily:            oid.T_anyelement,
+	TSQueryFamily:        oid.T_tsquery,
+	TSVectorFamily:       oid.T_tsvector,
+	RangeFamily:          oid.T_anyrange,
 
 	GeometryFamily:  oidext.T_geometry,
 	GeographyFamily: oidext.T_geography,
@@ -190,6 +209,9 @@ func init() {
 // CalcArrayOid returns the OID of the array type having elements of the given
 // type.
 func CalcArrayOid(elemTyp *T) oid.Oid {
+	if elemTyp.IsDomain() {
+		return elemTyp.UserDefinedArrayOID()
+	}
 	o := elemTyp.Oid()
 	switch elemTyp.Family() {
 	case ArrayFamily:
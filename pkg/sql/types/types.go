@@ -196,6 +196,9 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -222,6 +225,23 @@ func (e *EnumMetadata) debugString() string {
 	)
 }
 
+// DomainMetadata is metadata about a DOMAIN needed for evaluation.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// DefaultExpr is the serialized default expression of the domain, if any.
+	DefaultExpr *string
+	// CheckConstraints are the CHECK constraints of the domain. Their
+	// expressions refer to the value being checked as VALUE.
+	CheckConstraints []DomainCheckConstraint
+}
+
+// DomainCheckConstraint is a CHECK constraint of a DOMAIN.
+type DomainCheckConstraint struct {
+	Name string
+	Expr string
+}
+
 // UserDefinedTypeName is a struct representing a qualified user defined
 // type name. We redefine a common struct from higher level packages. We
 // do so because proto will panic if any members of a proto struct are
@@ -463,6 +483,103 @@ var (
 		},
 	}
 
+	// TSQuery is the type of a full-text search query, which is a boolean
+	// expression of lexemes. For example:
+	//
+	//   'fat' & 'rat':* | !'cat'
+	//
+	TSQuery = &T{
+		InternalType: InternalType{
+			Family: TSQueryFamily,
+			Oid:    oid.T_tsquery,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSVector is the type of a document prepared for full-text search, which
+	// is a sorted list of distinct lexemes along with their positions in the
+	// document. For example:
+	//
+	//   'cat':3 'fat':2,5A 'rat':4
+	//
+	TSVector = &T{
+		InternalType: InternalType{
+			Family: TSVectorFamily,
+			Oid:    oid.T_tsvector,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int4Range is the type of a range of INT4 values. For example:
+	//
+	//   [1,10)
+	//
+	Int4Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int4range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int8Range is the type of a range of INT8 values.
+	Int8Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int8range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// NumRange is the type of a range of DECIMAL values.
+	NumRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_numrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSRange is the type of a range of TIMESTAMP values.
+	TSRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tsrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSTZRange is the type of a range of TIMESTAMPTZ values. For example:
+	//
+	//   ["2022-01-01 00:00:00+00","2022-01-02 00:00:00+00")
+	//
+	TSTZRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tstzrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// DateRange is the type of a range of DATE values.
+	DateRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_daterange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Ranges contains all of the built-in range types.
+	Ranges = []*T{
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
+	}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -490,6 +607,14 @@ var (
 		TimeTZ,
 		Jsonb,
 		VarBit,
+		TSQuery,
+		TSVector,
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -512,6 +637,12 @@ var (
 	AnyEnum = &T{InternalType: InternalType{
 		Family: EnumFamily, Locale: &emptyLocale, Oid: oid.T_anyenum}}
 
+	// AnyRange is a special type only used during static analysis as a wildcard
+	// type that matches a range of any subtype. Execution-time values should
+	// never have this type.
+	AnyRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_anyrange, Locale: &emptyLocale}}
+
 	// AnyTuple is a special type used only during static analysis as a wildcard
 	// type that matches a tuple with any number of fields of any type (including
 	// tuple types). Execution-time values should never have this type.
@@ -1073,6 +1204,21 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new instance of a domain type with the given stable
+// type ID over the given base type. The domain shares the family, width,
+// precision and locale of its base type, so that its values are represented,
+// encoded and compared exactly like values of the base type. Note that it does
+// not hydrate cached fields on the type.
+func MakeDomain(typeOID, arrayTypeOID oid.Oid, base *T) *T {
+	internal := base.InternalType
+	internal.Oid = typeOID
+	internal.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID:  arrayTypeOID,
+		DomainBaseOID: base.Oid(),
+	}
+	return &T{InternalType: internal}
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1332,6 +1478,30 @@ func (t *T) ArrayContents() *T {
 	return t.InternalType.ArrayContents
 }
 
+// RangeContents returns the subtype of the values in a range. This is nil for
+// types that are not in the RangeFamily, as well as for the AnyRange wildcard
+// type.
+func (t *T) RangeContents() *T {
+	if t.Family() != RangeFamily {
+		return nil
+	}
+	switch t.Oid() {
+	case oid.T_int4range:
+		return Int4
+	case oid.T_int8range:
+		return Int
+	case oid.T_numrange:
+		return Decimal
+	case oid.T_tsrange:
+		return Timestamp
+	case oid.T_tstzrange:
+		return TimestampTZ
+	case oid.T_daterange:
+		return Date
+	}
+	return nil
+}
+
 // TupleContents returns a slice containing the type of each tuple field. This
 // is nil for non-TupleFamily types.
 func (t *T) TupleContents() []*T {
@@ -1370,6 +1540,23 @@ func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
 	}
 }
 
+// IsDomain returns whether or not t is a user defined domain type.
+func (t *T) IsDomain() bool {
+	return t.InternalType.UDTMetadata != nil && t.InternalType.UDTMetadata.DomainBaseOID != 0
+}
+
+// DomainBaseType returns the base type of a domain type. It must only be called
+// on domain types.
+func (t *T) DomainBaseType() *T {
+	if !t.IsDomain() {
+		panic(errors.AssertionFailedf("DomainBaseType called on non-domain type %s", t.DebugString()))
+	}
+	base := &T{InternalType: t.InternalType}
+	base.InternalType.Oid = t.InternalType.UDTMetadata.DomainBaseOID
+	base.InternalType.UDTMetadata = nil
+	return base
+}
+
 // UserDefined returns whether or not t is a user defined type.
 func (t *T) UserDefined() bool {
 	return IsOIDUserDefinedType(t.Oid())
@@ -1401,7 +1588,10 @@ var familyNames = map[Family]string{
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
 	OidFamily:            "oid",
+	RangeFamily:          "range",
 	StringFamily:         "string",
+	TSQueryFamily:        "tsquery",
+	TSVectorFamily:       "tsvector",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
@@ -1431,6 +1621,13 @@ func (f Family) Name() string {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() {
+		// This can be nil during unit testing.
+		if t.TypeMeta.Name == nil {
+			return "unknown_domain"
+		}
+		return t.TypeMeta.Name.Basename()
+	}
 	switch fam := t.Family(); fam {
 	case AnyFamily:
 		return "anyelement"
@@ -1472,7 +1669,7 @@ func (t *T) Name() string {
 			panic(errors.AssertionFailedf("programming error: unknown int width: %d", t.Width()))
 		}
 
-	case OidFamily:
+	case OidFamily, RangeFamily:
 		return t.SQLStandardName()
 
 	case StringFamily, CollatedStringFamily:
@@ -1570,6 +1767,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() {
+		return t.Name()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1724,6 +1924,29 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case RangeFamily:
+		switch t.Oid() {
+		case oid.T_int4range:
+			return "int4range"
+		case oid.T_int8range:
+			return "int8range"
+		case oid.T_numrange:
+			return "numrange"
+		case oid.T_tsrange:
+			return "tsrange"
+		case oid.T_tstzrange:
+			return "tstzrange"
+		case oid.T_daterange:
+			return "daterange"
+		case oid.T_anyrange:
+			return "anyrange"
+		default:
+			panic(errors.AssertionFailedf("unexpected Oid: %v", errors.Safe(t.Oid())))
+		}
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
 		if t.UserDefined() {
 			// If we have a user-defined tuple type, use its user-defined name.
@@ -1751,6 +1974,10 @@ func (t *T) InformationSchemaName() string {
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
 	}
+	// Columns of a domain type report the data type of the domain's base type.
+	if t.IsDomain() {
+		return t.DomainBaseType().InformationSchemaName()
+	}
 	// TypeMeta attributes are populated only when it is user defined type.
 	if t.TypeMeta.Name != nil {
 		return "USER-DEFINED"
@@ -1762,6 +1989,9 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() {
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -1919,6 +2149,17 @@ func (t *T) Equivalent(other *T) bool {
 		if t.Oid() != other.Oid() {
 			return false
 		}
+
+	case RangeFamily:
+		// If one of the types is anyrange, then allow the comparison to go
+		// through -- anyrange is used when matching overloads. Otherwise, the
+		// ranges must have the same subtype.
+		if t.Oid() == oid.T_anyrange || other.Oid() == oid.T_anyrange {
+			return true
+		}
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -2068,6 +2309,9 @@ func (t *InternalType) Identical(other *InternalType) bool {
 		if t.UDTMetadata.ArrayTypeOID != other.UDTMetadata.ArrayTypeOID {
 			return false
 		}
+		if t.UDTMetadata.DomainBaseOID != other.UDTMetadata.DomainBaseOID {
+			return false
+		}
 	} else if t.UDTMetadata != nil {
 		return false
 	} else if other.UDTMetadata != nil {
@@ -2100,6 +2344,10 @@ func (t *T) Unmarshal(data []byte) error {
 // setting required values. This is necessary to preserve backwards-
 // compatibility with older formats (e.g. restoring database from old backup).
 func (t *T) upgradeType() error {
+	if t.IsDomain() {
+		// A domain is upgraded like its base type, but keeps its own OID.
+		return t.asDomainBaseType(t.upgradeType)
+	}
 	switch t.Family() {
 	case IntFamily:
 		// Check VisibleType field that was populated in previous versions.
@@ -2338,6 +2586,10 @@ func (t *T) MarshalTo(data []byte) (int, error) {
 // CRDB. This is necessary to preserve backwards-compatibility in mixed-version
 // scenarios, such as during upgrade.
 func (t *T) downgradeType() error {
+	if t.IsDomain() {
+		// A domain is downgraded like its base type, but keeps its own OID.
+		return t.asDomainBaseType(t.downgradeType)
+	}
 	// Set Family and VisibleType for 19.1 backwards-compatibility.
 	switch t.Family() {
 	case BitFamily:
@@ -2401,6 +2653,16 @@ func (t *T) downgradeType() error {
 	return nil
 }
 
+// asDomainBaseType temporarily turns the domain type t into its base type while
+// calling fn, and then restores the domain's OID and metadata.
+func (t *T) asDomainBaseType(fn func() error) error {
+	domainOID, md := t.InternalType.Oid, t.InternalType.UDTMetadata
+	t.InternalType.Oid, t.InternalType.UDTMetadata = md.DomainBaseOID, nil
+	err := fn()
+	t.InternalType.Oid, t.InternalType.UDTMetadata = domainOID, md
+	return err
+}
+
 // String returns the name of the type, similar to the Name method. However, it
 // expands CollatedStringFamily, ArrayFamily, and TupleFamily types to be more
 // descriptive.
@@ -2408,6 +2670,9 @@ func (t *T) downgradeType() error {
 // TODO(andyk): It'd be nice to have this return SqlString() method output,
 // since that is more descriptive.
 func (t *T) String() string {
+	if t.IsDomain() {
+		return t.Name()
+	}
 	switch t.Family() {
 	case CollatedStringFamily:
 		if t.Locale() == "" {
@@ -2502,6 +2767,8 @@ func (t *T) IsAmbiguous() bool {
 		return t.ArrayContents().IsAmbiguous()
 	case EnumFamily:
 		return t.Oid() == oid.T_anyenum
+	case RangeFamily:
+		return t.Oid() == oid.T_anyrange
 	}
 	return false
 }
@@ -2747,6 +3014,7 @@ var unreservedTypeTokens = map[string]*T{
 	"bytea":      Bytes,
 	"bytes":      Bytes,
 	"date":       Date,
+	"daterange":  DateRange,
 	"float4":     Float,
 	"float8":     Float,
 	"inet":       INet,
@@ -2755,9 +3023,12 @@ var unreservedTypeTokens = map[string]*T{
 	"int8":       Int,
 	"int64":      Int,
 	"int2vector": Int2Vector,
+	"int4range":  Int4Range,
+	"int8range":  Int8Range,
 	"json":       Jsonb,
 	"jsonb":      Jsonb,
 	"name":       Name,
+	"numrange":   NumRange,
 	"oid":        Oid,
 	"oidvector":  OidVector,
 	// Postgres OID pseudo-types. See https://www.postgresql.org/docs/9.4/static/datatype-oid.html.
@@ -2774,8 +3045,12 @@ var unreservedTypeTokens = map[string]*T{
 	"smallserial": &Serial2Type,
 	"bigserial":   &Serial8Type,
 
-	"string": String,
-	"uuid":   Uuid,
+	"string":    String,
+	"tsquery":   TSQuery,
+	"tsrange":   TSRange,
+	"tstzrange": TSTZRange,
+	"tsvector":  TSVector,
+	"uuid":      Uuid,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2794,8 +3069,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"money":         -1,
 	"path":          21286,
 	"pg_lsn":        -1,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
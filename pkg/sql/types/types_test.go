@@ -481,6 +481,21 @@ func TestTypes(t *testing.T) {
 			Family: TimestampTZFamily, Oid: oid.T_timestamptz, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTimestampTZ(6), MakeScalar(TimestampTZFamily, oid.T_timestamptz, 6, 0, emptyLocale)},
 
+		// RANGE
+		{Int4Range, &T{InternalType: InternalType{
+			Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}},
+		{TSTZRange, MakeScalar(RangeFamily, oid.T_tstzrange, 0, 0, emptyLocale)},
+
+		// TSQUERY
+		{TSQuery, &T{InternalType: InternalType{
+			Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}},
+		{TSQuery, MakeScalar(TSQueryFamily, oid.T_tsquery, 0, 0, emptyLocale)},
+
+		// TSVECTOR
+		{TSVector, &T{InternalType: InternalType{
+			Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}},
+		{TSVector, MakeScalar(TSVectorFamily, oid.T_tsvector, 0, 0, emptyLocale)},
+
 		// TUPLE
 		{MakeTuple(nil), EmptyTuple},
 		{MakeTuple([]*T{Any}), AnyTuple},
@@ -972,6 +987,27 @@ func TestOidSetDuringUpgrade(t *testing.T) {
 	}
 }
 
+func TestDomainMarshalRoundTrip(t *testing.T) {
+	const domainOID, arrayOID = oid.Oid(100100), oid.Oid(100101)
+	for _, base := range []*T{Int4, MakeVarChar(10), MakeChar(3), MakeVarBit(4), Float4, MakeTimestamp(3)} {
+		t.Run(base.Name(), func(t *testing.T) {
+			typ := MakeDomain(domainOID, arrayOID, base)
+			require.True(t, typ.IsDomain())
+			require.True(t, typ.UserDefined())
+			require.Equal(t, base.Family(), typ.Family())
+
+			data, err := protoutil.Marshal(typ)
+			require.NoError(t, err)
+			var actual T
+			require.NoError(t, protoutil.Unmarshal(data, &actual))
+			require.True(t, actual.Identical(typ), "expected %s, got %s", typ.DebugString(), actual.DebugString())
+			require.Equal(t, domainOID, actual.Oid())
+			require.Equal(t, arrayOID, actual.UserDefinedArrayOID())
+			require.True(t, actual.DomainBaseType().Identical(base))
+		})
+	}
+}
+
 func TestSQLStandardName(t *testing.T) {
 	for _, typ := range Scalar {
 		t.Run(typ.Name(), func(t *testing.T) {
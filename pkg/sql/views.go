@@ -66,6 +66,10 @@ func (d planDependencies) String() string {
 // this view depends on.
 type typeDependencies map[descpb.ID]struct{}
 
+// funcDependencies contains a set of the IDs of user-defined functions that
+// this view depends on.
+type funcDependencies map[descpb.ID]struct{}
+
 // checkViewMatchesMaterialized ensures that if a view is required, then the view
 // is materialized or not as desired.
 func checkViewMatchesMaterialized(
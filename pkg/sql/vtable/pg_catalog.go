@@ -960,8 +960,9 @@ CREATE TABLE pg_catalog.pg_available_extension_versions (
 	version STRING
 )`
 
-// PgCatalogPublicationRel is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationRel describes the schema of the
+// pg_catalog.pg_publication_rel table.
+// https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html
 const PgCatalogPublicationRel = `
 CREATE TABLE pg_catalog.pg_publication_rel (
 	oid OID,
@@ -1010,8 +1011,9 @@ CREATE TABLE pg_catalog.pg_timezone_names (
 	utc_offset INTERVAL
 )`
 
-// PgCatalogPublicationTables is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationTables describes the schema of the
+// pg_catalog.pg_publication_tables view.
+// https://www.postgresql.org/docs/13/view-pg-publication-tables.html
 const PgCatalogPublicationTables = `
 CREATE TABLE pg_catalog.pg_publication_tables (
 	pubname NAME,
@@ -1067,8 +1069,9 @@ CREATE TABLE pg_catalog.pg_shadow (
 	useconfig STRING[]
 )`
 
-// PgCatalogPublication is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublication describes the schema of the pg_catalog.pg_publication
+// table.
+// https://www.postgresql.org/docs/13/catalog-pg-publication.html
 const PgCatalogPublication = `
 CREATE TABLE pg_catalog.pg_publication (
 	pubupdate BOOL,
@@ -1306,7 +1309,9 @@ CREATE TABLE pg_catalog.pg_replication_origin_status (
 	local_id OID
 )`
 
-//PgCatalogReplicationSlots is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogReplicationSlots describes the schema of the
+// pg_catalog.pg_replication_slots view.
+// https://www.postgresql.org/docs/13/view-pg-replication-slots.html
 const PgCatalogReplicationSlots = `
 CREATE TABLE pg_catalog.pg_replication_slots (
 	safe_wal_size INT,
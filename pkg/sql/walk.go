@@ -216,6 +216,7 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		}
 
 	case *createViewNode:
+	case *createFunctionNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
 
@@ -339,6 +340,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterDatabaseSurvivalGoalNode{}):  "alter database survive",
 	reflect.TypeOf(&alterDatabaseDropRegionNode{}):    "alter database drop region",
 	reflect.TypeOf(&alterDefaultPrivilegesNode{}):     "alter default privileges",
+	reflect.TypeOf(&alterDomainNode{}):                "alter domain",
 	reflect.TypeOf(&alterIndexNode{}):                 "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):              "alter sequence",
 	reflect.TypeOf(&alterSchemaNode{}):                "alter schema",
@@ -363,12 +365,17 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&controlJobsNode{}):                "control jobs",
 	reflect.TypeOf(&controlSchedulesNode{}):           "control schedules",
 	reflect.TypeOf(&createDatabaseNode{}):             "create database",
+	reflect.TypeOf(&createDomainNode{}):               "create domain",
 	reflect.TypeOf(&createExtensionNode{}):            "create extension",
+	reflect.TypeOf(&createFunctionNode{}):             "create function",
 	reflect.TypeOf(&createIndexNode{}):                "create index",
+	reflect.TypeOf(&createPublicationNode{}):          "create publication",
+	reflect.TypeOf(&createReplicationSlotNode{}):      "create replication slot",
 	reflect.TypeOf(&createSequenceNode{}):             "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):               "create schema",
 	reflect.TypeOf(&createStatsNode{}):                "create statistics",
 	reflect.TypeOf(&createTableNode{}):                "create table",
+	reflect.TypeOf(&createTriggerNode{}):              "create trigger",
 	reflect.TypeOf(&createTypeNode{}):                 "create type",
 	reflect.TypeOf(&CreateRoleNode{}):                 "create user/role",
 	reflect.TypeOf(&createViewNode{}):                 "create view",
@@ -377,10 +384,14 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&deleteRangeNode{}):                "delete range",
 	reflect.TypeOf(&distinctNode{}):                   "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):               "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):               "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                  "drop index",
+	reflect.TypeOf(&dropPublicationNode{}):            "drop publication",
+	reflect.TypeOf(&dropReplicationSlotNode{}):        "drop replication slot",
 	reflect.TypeOf(&dropSequenceNode{}):               "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                 "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                  "drop table",
+	reflect.TypeOf(&dropTriggerNode{}):                "drop trigger",
 	reflect.TypeOf(&dropTypeNode{}):                   "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                   "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                   "drop view",
@@ -393,6 +404,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&GrantRoleNode{}):                  "grant role",
 	reflect.TypeOf(&groupNode{}):                      "group",
 	reflect.TypeOf(&hookFnNode{}):                     "plugin",
+	reflect.TypeOf(&identifySystemNode{}):             "identify system",
 	reflect.TypeOf(&indexJoinNode{}):                  "index join",
 	reflect.TypeOf(&insertNode{}):                     "insert",
 	reflect.TypeOf(&insertFastPathNode{}):             "insert fast path",
@@ -400,8 +412,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&invertedJoinNode{}):               "inverted join",
 	reflect.TypeOf(&joinNode{}):                       "join",
 	reflect.TypeOf(&limitNode{}):                      "limit",
+	reflect.TypeOf(&listenNode{}):                     "listen",
 	reflect.TypeOf(&lookupJoinNode{}):                 "lookup join",
 	reflect.TypeOf(&max1RowNode{}):                    "max1row",
+	reflect.TypeOf(&notifyNode{}):                     "notify",
 	reflect.TypeOf(&ordinalityNode{}):                 "ordinality",
 	reflect.TypeOf(&projectSetNode{}):                 "project set",
 	reflect.TypeOf(&reassignOwnedByNode{}):            "reassign owned by",
@@ -442,6 +456,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&truncateNode{}):                   "truncate",
 	reflect.TypeOf(&unaryNode{}):                      "emptyrow",
 	reflect.TypeOf(&unionNode{}):                      "union",
+	reflect.TypeOf(&unlistenNode{}):                   "unlisten",
 	reflect.TypeOf(&updateNode{}):                     "update",
 	reflect.TypeOf(&upsertNode{}):                     "upsert",
 	reflect.TypeOf(&valuesNode{}):                     "values",
@@ -2616,6 +2616,19 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.scheduled-row-level-ttl-executor.started",
+					"schedules.scheduled-row-level-ttl-executor.succeeded",
+					"schedules.scheduled-row-level-ttl-executor.failed",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -2637,6 +2650,8 @@ var charts = []sectionDescription{
 					"jobs.auto_span_config_reconciliation.currently_running",
 					"jobs.auto_sql_stats_compaction.currently_running",
 					"jobs.stream_replication.currently_running",
+					"jobs.row_level_ttl.currently_running",
+					"jobs.verify_backup.currently_running",
 				},
 			},
 			{
@@ -2813,6 +2828,64 @@ var charts = []sectionDescription{
 					"jobs.auto_sql_stats_compaction.resume_retry_error",
 				},
 			},
+			{
+				Title: "Row Level TTL",
+				Metrics: []string{
+					"jobs.row_level_ttl.fail_or_cancel_completed",
+					"jobs.row_level_ttl.fail_or_cancel_failed",
+					"jobs.row_level_ttl.fail_or_cancel_retry_error",
+					"jobs.row_level_ttl.resume_completed",
+					"jobs.row_level_ttl.resume_failed",
+					"jobs.row_level_ttl.resume_retry_error",
+				},
+			},
+			{
+				Title: "Verify Backup",
+				Metrics: []string{
+					"jobs.verify_backup.fail_or_cancel_completed",
+					"jobs.verify_backup.fail_or_cancel_failed",
+					"jobs.verify_backup.fail_or_cancel_retry_error",
+					"jobs.verify_backup.resume_completed",
+					"jobs.verify_backup.resume_failed",
+					"jobs.verify_backup.resume_retry_error",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Rows",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+				AxisLabel: "Rows",
+				Rate:      DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
+			{
+				Title: "Active Ranges",
+				Metrics: []string{
+					"jobs.row_level_ttl.num_active_ranges",
+				},
+				AxisLabel: "Ranges",
+			},
+			{
+				Title: "Range Processing Latency",
+				Metrics: []string{
+					"jobs.row_level_ttl.range_total_duration",
+				},
+				AxisLabel: "Latency",
+			},
+			{
+				Title: "Query Latency",
+				Metrics: []string{
+					"jobs.row_level_ttl.select_duration",
+					"jobs.row_level_ttl.delete_duration",
+				},
+				AxisLabel: "Latency",
+			},
 		},
 	},
 	{
@@ -0,0 +1,293 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfig is the name of the text search configuration used when none
+// is specified.
+const DefaultConfig = "english"
+
+// Config is a text search configuration, which determines how the words of a
+// document or a query are normalized into lexemes.
+type Config struct {
+	name string
+	// normalize returns the lexeme for a lowercase word, or the empty string
+	// if the word is a stop word.
+	normalize func(word string) string
+}
+
+var configs = map[string]*Config{
+	"simple": {
+		name:      "simple",
+		normalize: func(word string) string { return word },
+	},
+	"english": {
+		name: "english",
+		normalize: func(word string) string {
+			if _, ok := englishStopWords[word]; ok {
+				return ""
+			}
+			return stemEnglish(word)
+		},
+	},
+}
+
+// GetConfig returns the text search configuration with the given name, which
+// may be qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	if c, ok := configs[strings.TrimPrefix(name, "pg_catalog.")]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// TSParse splits the input text into words, which are maximal sequences of
+// letters and digits, and returns them in lowercase.
+func TSParse(input string) []string {
+	return strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// lexemes returns the normalized lexemes for the words of the input. Stop
+// words are returned as empty strings, so that they still occupy a position.
+func (c *Config) lexemes(input string) []string {
+	words := TSParse(input)
+	for i, w := range words {
+		if len(w) > maxLexemeLength {
+			w = w[:maxLexemeLength]
+		}
+		words[i] = c.normalize(w)
+	}
+	return words
+}
+
+// DocumentToTSVector parses the document into words and normalizes them into
+// lexemes using the configuration, returning a vector which records the
+// positions of the lexemes in the document. This is the implementation of
+// to_tsvector.
+func (c *Config) DocumentToTSVector(input string) TSVector {
+	var terms []tsTerm
+	for i, lexeme := range c.lexemes(input) {
+		if lexeme == "" {
+			continue
+		}
+		pos := i + 1
+		if pos > maxTSVectorPosition {
+			pos = maxTSVectorPosition
+		}
+		terms = append(terms, tsTerm{
+			lexeme:    lexeme,
+			positions: []tsPosition{{position: uint16(pos), weight: weightD}},
+		})
+	}
+	return normalizeTSVector(terms)
+}
+
+// phraseQuery returns a query which matches the lexemes in sequence. Empty
+// lexemes, which are stop words, increase the distance between the lexemes
+// which surround them. The given weight and prefix are applied to each term.
+func phraseQuery(lexemes []string, weight tsWeight, prefix bool) TSQuery {
+	var q TSQuery
+	distance := 0
+	for _, l := range lexemes {
+		if l == "" {
+			distance++
+			continue
+		}
+		term := TSQuery{root: &tsNode{term: &tsQueryTerm{lexeme: l, weight: weight, prefix: prefix}}}
+		if q.root == nil {
+			q = term
+		} else {
+			q = combineQueries(followedBy, q, term, distance+1)
+		}
+		distance = 0
+	}
+	return q
+}
+
+// ToTSQuery parses the input representation of a TSQuery, and normalizes the
+// lexemes of its operands using the configuration. An operand which consists
+// of several words is replaced by a phrase of the words, and an operand which
+// is a stop word is removed. This is the implementation of to_tsquery.
+func (c *Config) ToTSQuery(input string) (TSQuery, error) {
+	q, err := ParseTSQuery(input)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	root, _ := c.normalizeNode(q.root)
+	return TSQuery{root: root.node}, nil
+}
+
+// normalizedNode is the result of normalizing a node of a query. If node is
+// nil, all of the operands of the original node were removed, and width is the
+// distance between the first and last positions the original node spanned.
+// Otherwise, lead and trail are the distances between the first and last
+// positions spanned by the original node and those spanned by node.
+type normalizedNode struct {
+	node               *tsNode
+	width, lead, trail int
+}
+
+func (c *Config) normalizeNode(n *tsNode) (normalizedNode, bool) {
+	if n == nil {
+		return normalizedNode{}, false
+	}
+	if n.term != nil {
+		q := phraseQuery(c.lexemes(n.term.lexeme), n.term.weight, n.term.prefix)
+		return normalizedNode{node: q.root}, true
+	}
+	left, _ := c.normalizeNode(n.left)
+	if n.op == not {
+		if left.node == nil {
+			return left, true
+		}
+		return normalizedNode{node: &tsNode{op: not, left: left.node}, lead: left.lead, trail: left.trail}, true
+	}
+	right, _ := c.normalizeNode(n.right)
+	if n.op != followedBy {
+		switch {
+		case left.node == nil:
+			return right, true
+		case right.node == nil:
+			return left, true
+		}
+		return normalizedNode{node: &tsNode{op: n.op, left: left.node, right: right.node}}, true
+	}
+	switch {
+	case left.node == nil && right.node == nil:
+		return normalizedNode{width: left.width + n.distance + right.width}, true
+	case left.node == nil:
+		right.lead += left.width + n.distance
+		return right, true
+	case right.node == nil:
+		left.trail += n.distance + right.width
+		return left, true
+	}
+	return normalizedNode{
+		node: &tsNode{
+			op:       followedBy,
+			left:     left.node,
+			right:    right.node,
+			distance: left.trail + n.distance + right.lead,
+		},
+		lead:  left.lead,
+		trail: right.trail,
+	}, true
+}
+
+// PlainToTSQuery returns a query which matches all of the lexemes of the
+// input, which is parsed as a document rather than as a query. This is the
+// implementation of plainto_tsquery.
+func (c *Config) PlainToTSQuery(input string) TSQuery {
+	var q TSQuery
+	for _, l := range c.lexemes(input) {
+		if l == "" {
+			continue
+		}
+		q = q.And(TSQuery{root: &tsNode{term: &tsQueryTerm{lexeme: l, weight: weightAny}}})
+	}
+	return q
+}
+
+// PhraseToTSQuery returns a query which matches the lexemes of the input in
+// sequence, where the input is parsed as a document rather than as a query.
+// This is the implementation of phraseto_tsquery.
+func (c *Config) PhraseToTSQuery(input string) TSQuery {
+	return phraseQuery(c.lexemes(input), weightAny, false /* prefix */)
+}
+
+// WebSearchToTSQuery returns a query for input written in the syntax accepted
+// by web search engines. This is the implementation of websearch_to_tsquery.
+// The syntax is:
+//
+//   - unquoted text: the words are combined with the & operator, after
+//     normalization as by plainto_tsquery.
+//   - "quoted text": the words are combined with the <-> operator, after
+//     normalization as by phraseto_tsquery.
+//   - or: the operands on either side are combined with the | operator.
+//   - a dash: the following operand is negated with the ! operator.
+//
+// The input never produces a syntax error.
+func (c *Config) WebSearchToTSQuery(input string) TSQuery {
+	var result, group TSQuery
+	// pendingOr is true if the previous operand was followed by "or".
+	pendingOr := false
+	negate := false
+	add := func(q TSQuery) {
+		if q.root == nil {
+			negate = false
+			return
+		}
+		if negate {
+			q = q.Not()
+			negate = false
+		}
+		if pendingOr && group.root != nil {
+			result = result.Or(group)
+			group = TSQuery{}
+		}
+		pendingOr = false
+		group = group.And(q)
+	}
+	for i := 0; i < len(input); {
+		r := rune(input[i])
+		switch {
+		case r == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			var phrase string
+			if end == -1 {
+				phrase = input[i+1:]
+				i = len(input)
+			} else {
+				phrase = input[i+1 : i+1+end]
+				i += end + 2
+			}
+			add(c.PhraseToTSQuery(phrase))
+		case r == '-' && !negate:
+			negate = true
+			i++
+		case unicode.IsSpace(r) || r == '-':
+			i++
+		default:
+			end := strings.IndexFunc(input[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || r == '"'
+			})
+			if end == -1 {
+				end = len(input) - i
+			}
+			word := input[i : i+end]
+			i += end
+			if strings.EqualFold(word, "or") {
+				if group.root != nil {
+					pendingOr = true
+				}
+				negate = false
+				continue
+			}
+			add(c.PhraseToTSQuery(word))
+		}
+	}
+	return result.Or(group)
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConfig(t *testing.T) {
+	for _, name := range []string{"simple", "english", "pg_catalog.english"} {
+		_, err := GetConfig(name)
+		require.NoError(t, err)
+	}
+	_, err := GetConfig("klingon")
+	require.Error(t, err)
+}
+
+func TestDocumentToTSVector(t *testing.T) {
+	tcs := []struct {
+		config   string
+		input    string
+		expected string
+	}{
+		{"simple", ``, ``},
+		{"simple", `The Fat Rats`, `'fat':2 'rats':3 'the':1`},
+		{"english", `The Fat Rats`, `'fat':2 'rat':3`},
+		{"english", `The fat cat sat on the mat, and bit the fat rat.`,
+			`'bit':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`},
+		{"english", `a-b_c  d.e`, `'b':2 'c':3 'd':4 'e':5`},
+		{"english", `Über naïve 2022`, `'2022':3 'naïve':2 'über':1`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.config+"/"+tc.input, func(t *testing.T) {
+			c, err := GetConfig(tc.config)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, c.DocumentToTSVector(tc.input).String())
+		})
+	}
+}
+
+func TestToTSQuery(t *testing.T) {
+	tcs := []struct {
+		config   string
+		input    string
+		expected string
+	}{
+		{"simple", `The & Fat & Rats`, `'the' & 'fat' & 'rats'`},
+		{"english", `The & Fat & Rats`, `'fat' & 'rat'`},
+		{"english", `Fat & Rats:AB`, `'fat' & 'rat':AB`},
+		{"english", `Fat | Rats:*`, `'fat' | 'rat':*`},
+		{"english", `'fat rats'`, `'fat' <-> 'rat'`},
+		{"english", `'fat the rats'`, `'fat' <2> 'rat'`},
+		{"english", `fat <-> the <-> rats`, `'fat' <2> 'rat'`},
+		{"english", `fat <-> (the <-> rats)`, `'fat' <2> 'rat'`},
+		{"english", `the <-> fat <-> rats`, `'fat' <-> 'rat'`},
+		{"english", `fat <-> rats <-> the`, `'fat' <-> 'rat'`},
+		{"english", `!the & fat`, `'fat'`},
+		{"english", `the | fat`, `'fat'`},
+		{"english", `the`, ``},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.config+"/"+tc.input, func(t *testing.T) {
+			c, err := GetConfig(tc.config)
+			require.NoError(t, err)
+			q, err := c.ToTSQuery(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.String())
+		})
+	}
+
+	c, err := GetConfig("english")
+	require.NoError(t, err)
+	_, err = c.ToTSQuery("fat rats")
+	require.Error(t, err)
+}
+
+func TestDocumentToTSQuery(t *testing.T) {
+	c, err := GetConfig("english")
+	require.NoError(t, err)
+	require.Equal(t, `'fat' & 'rat'`, c.PlainToTSQuery(`The Fat Rats`).String())
+	require.Equal(t, `'fat' & 'rat' & 'c'`, c.PlainToTSQuery(`The Fat & Rats:C`).String())
+	require.Equal(t, ``, c.PlainToTSQuery(`the`).String())
+	require.Equal(t, `'fat' <-> 'rat'`, c.PhraseToTSQuery(`The Fat Rats`).String())
+	require.Equal(t, `'cat' <2> 'rat'`, c.PhraseToTSQuery(`The Cat and Rats`).String())
+	require.Equal(t, `'cat' <3> 'rat'`, c.PhraseToTSQuery(`The Cat and the Rats`).String())
+}
+
+func TestWebSearchToTSQuery(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`fat rat`, `'fat' & 'rat'`},
+		{`"fat rat"`, `'fat' <-> 'rat'`},
+		{`"fat rat" or cat dog`, `'fat' <-> 'rat' | 'cat' & 'dog'`},
+		{`"supernovae stars" -crab`, `'supernova' <-> 'star' & !'crab'`},
+		{`signal -"segmentation fault"`, `'signal' & !( 'segment' <-> 'fault' )`},
+		{`or cat`, `'cat'`},
+		{`cat or`, `'cat'`},
+		{`cat or or dog`, `'cat' | 'dog'`},
+		{`cat -`, `'cat'`},
+		{`the cat`, `'cat'`},
+		{`-the cat`, `'cat'`},
+		{`"unterminated phrase`, `'untermin' <-> 'phrase'`},
+		{`fat & rat | cat`, `'fat' & 'rat' & 'cat'`},
+	}
+	c, err := GetConfig("english")
+	require.NoError(t, err)
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, c.WebSearchToTSQuery(tc.input).String())
+		})
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The encodings of vectors and queries are the binary formats used by
+// Postgres, which are also used for their pgwire binary representations.
+//
+// A vector is encoded as the number of lexemes as a uint32, followed by each
+// lexeme as a null-terminated string, the number of its positions as a
+// uint16 and its positions as uint16s, with the weight in the two high bits.
+//
+// A query is encoded as the number of nodes as a uint32, followed by the
+// nodes in prefix order, with the right operand of a binary operator before
+// its left operand. Each node begins with its type: a term is followed by its
+// weights, whether it is a prefix, and its lexeme as a null-terminated string,
+// and an operator is followed by its type and, for followedBy, its distance as
+// a uint16.
+
+const (
+	positionBits = 14
+	positionMask = 1<<positionBits - 1
+)
+
+// The node types and operators of the query encoding.
+const (
+	encodedTerm     = 1
+	encodedOperator = 2
+
+	encodedNot        = 1
+	encodedAnd        = 2
+	encodedOr         = 3
+	encodedFollowedBy = 4
+)
+
+var errCorruptEncoding = errors.New("corrupt text search encoding")
+
+// EncodeTSVector appends the encoding of the vector to the given buffer.
+func EncodeTSVector(appendTo []byte, v TSVector) []byte {
+	appendTo = encoding.EncodeUint32Ascending(appendTo, uint32(len(v)))
+	for i := range v {
+		appendTo = append(appendTo, v[i].lexeme...)
+		appendTo = append(appendTo, 0)
+		appendTo = appendUint16(appendTo, uint16(len(v[i].positions)))
+		for _, p := range v[i].positions {
+			appendTo = appendUint16(appendTo,
+				uint16(p.weight.weightIndex())<<positionBits|p.position)
+		}
+	}
+	return appendTo
+}
+
+// DecodeTSVector decodes a vector encoded by EncodeTSVector.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	if len(b) < 4 {
+		return nil, errCorruptEncoding
+	}
+	n := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if uint64(n) > uint64(len(b)) {
+		return nil, errCorruptEncoding
+	}
+	terms := make([]tsTerm, n)
+	for i := range terms {
+		var err error
+		if terms[i].lexeme, b, err = decodeCString(b); err != nil {
+			return nil, err
+		}
+		if len(b) < 2 {
+			return nil, errCorruptEncoding
+		}
+		numPositions := int(binary.BigEndian.Uint16(b))
+		b = b[2:]
+		if len(b) < 2*numPositions {
+			return nil, errCorruptEncoding
+		}
+		if numPositions > 0 {
+			terms[i].positions = make([]tsPosition, numPositions)
+		}
+		for j := range terms[i].positions {
+			p := binary.BigEndian.Uint16(b)
+			b = b[2:]
+			terms[i].positions[j] = tsPosition{
+				position: p & positionMask,
+				weight:   weightD << (p >> positionBits),
+			}
+		}
+	}
+	if len(b) != 0 {
+		return nil, errCorruptEncoding
+	}
+	return normalizeTSVector(terms), nil
+}
+
+func decodeCString(b []byte) (string, []byte, error) {
+	i := bytes.IndexByte(b, 0)
+	if i == -1 {
+		return "", nil, errCorruptEncoding
+	}
+	return string(b[:i]), b[i+1:], nil
+}
+
+// EncodeTSQuery appends the encoding of the query to the given buffer.
+func EncodeTSQuery(appendTo []byte, q TSQuery) []byte {
+	appendTo = encoding.EncodeUint32Ascending(appendTo, uint32(q.NumNodes()))
+	if q.root != nil {
+		appendTo = q.root.encode(appendTo)
+	}
+	return appendTo
+}
+
+func (n *tsNode) encode(appendTo []byte) []byte {
+	if n.term != nil {
+		weight := n.term.weight
+		if weight == weightAny {
+			// Postgres represents the absence of a weight restriction as 0.
+			weight = 0
+		}
+		prefix := byte(0)
+		if n.term.prefix {
+			prefix = 1
+		}
+		appendTo = append(appendTo, encodedTerm, byte(weight), prefix)
+		appendTo = append(appendTo, n.term.lexeme...)
+		return append(appendTo, 0)
+	}
+	appendTo = append(appendTo, encodedOperator)
+	switch n.op {
+	case not:
+		appendTo = append(appendTo, encodedNot)
+		return n.left.encode(appendTo)
+	case and:
+		appendTo = append(appendTo, encodedAnd)
+	case or:
+		appendTo = append(appendTo, encodedOr)
+	case followedBy:
+		appendTo = append(appendTo, encodedFollowedBy)
+		appendTo = appendUint16(appendTo, uint16(n.distance))
+	}
+	appendTo = n.right.encode(appendTo)
+	return n.left.encode(appendTo)
+}
+
+// DecodeTSQuery decodes a query encoded by EncodeTSQuery.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	if len(b) < 4 {
+		return TSQuery{}, errCorruptEncoding
+	}
+	n := int(binary.BigEndian.Uint32(b))
+	b = b[4:]
+	if n == 0 {
+		if len(b) != 0 {
+			return TSQuery{}, errCorruptEncoding
+		}
+		return TSQuery{}, nil
+	}
+	d := tsQueryDecoder{b: b, remaining: n}
+	root, err := d.decodeNode()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if d.remaining != 0 || len(d.b) != 0 {
+		return TSQuery{}, errCorruptEncoding
+	}
+	return TSQuery{root: root}, nil
+}
+
+type tsQueryDecoder struct {
+	b []byte
+	// remaining is the number of nodes which remain to be decoded.
+	remaining int
+}
+
+func (d *tsQueryDecoder) decodeNode() (*tsNode, error) {
+	if d.remaining == 0 || len(d.b) < 2 {
+		return nil, errCorruptEncoding
+	}
+	d.remaining--
+	typ, arg := d.b[0], d.b[1]
+	d.b = d.b[2:]
+	switch typ {
+	case encodedTerm:
+		if len(d.b) < 1 || arg&^byte(weightAny) != 0 {
+			return nil, errCorruptEncoding
+		}
+		term := &tsQueryTerm{weight: tsWeight(arg), prefix: d.b[0] != 0}
+		if term.weight == 0 {
+			term.weight = weightAny
+		}
+		var err error
+		if term.lexeme, d.b, err = decodeCString(d.b[1:]); err != nil {
+			return nil, err
+		}
+		return &tsNode{term: term}, nil
+	case encodedOperator:
+		n := &tsNode{}
+		switch arg {
+		case encodedNot:
+			n.op = not
+			left, err := d.decodeNode()
+			if err != nil {
+				return nil, err
+			}
+			n.left = left
+			return n, nil
+		case encodedAnd:
+			n.op = and
+		case encodedOr:
+			n.op = or
+		case encodedFollowedBy:
+			if len(d.b) < 2 {
+				return nil, errCorruptEncoding
+			}
+			n.op = followedBy
+			n.distance = int(binary.BigEndian.Uint16(d.b))
+			d.b = d.b[2:]
+		default:
+			return nil, errCorruptEncoding
+		}
+		var err error
+		if n.right, err = d.decodeNode(); err != nil {
+			return nil, err
+		}
+		if n.left, err = d.decodeNode(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, errCorruptEncoding
+}
+
+// EncodeInvertedIndexKeys returns the inverted index keys for the vector,
+// one per lexeme, each prefixed by inKey.
+func EncodeInvertedIndexKeys(inKey []byte, v TSVector) [][]byte {
+	keys := make([][]byte, len(v))
+	for i := range v {
+		key := make([]byte, len(inKey), len(inKey)+len(v[i].lexeme)+3)
+		copy(key, inKey)
+		keys[i] = encoding.EncodeStringAscending(key, v[i].lexeme)
+	}
+	return keys
+}
+
+// GetInvertedExpr returns the expression which must be evaluated over an
+// inverted index of vectors to find the vectors which may match the query.
+// The expression is tight if every vector it produces is guaranteed to
+// match. An error is returned if the query cannot be evaluated using an
+// inverted index, because it has a negated term which is not combined with an
+// indexable term using the and operator.
+func (q TSQuery) GetInvertedExpr() (inverted.Expression, error) {
+	if q.root == nil {
+		return &inverted.SpanExpression{Tight: true, Unique: true}, nil
+	}
+	expr := q.root.invertedExpr()
+	if _, ok := expr.(inverted.NonInvertedColExpression); ok {
+		return nil, errors.Newf("tsquery %s cannot be evaluated using an inverted index", q)
+	}
+	return expr, nil
+}
+
+// invertedExpr returns the inverted expression for the node. Negated nodes
+// cannot be evaluated using the index, and only restrict the results of an
+// enclosing and.
+func (n *tsNode) invertedExpr() inverted.Expression {
+	if n.term != nil {
+		key := encoding.EncodeStringAscending(nil, n.term.lexeme)
+		var span inverted.Span
+		if n.term.prefix {
+			// Remove the terminator of the encoded string, so that the span
+			// contains all of the lexemes which begin with the term.
+			key = key[:len(key)-2]
+			span = inverted.Span{Start: key, End: inverted.EncVal(roachpb.Key(key).PrefixEnd())}
+		} else {
+			span = inverted.MakeSingleValSpan(key)
+		}
+		// A term with a weight restriction may match a lexeme which does not
+		// have a position with the weight.
+		expr := inverted.ExprForSpan(span, n.term.weight == weightAny /* tight */)
+		expr.Unique = !n.term.prefix
+		return expr
+	}
+	switch n.op {
+	case and:
+		return inverted.And(n.left.invertedExpr(), n.right.invertedExpr())
+	case or:
+		return inverted.Or(n.left.invertedExpr(), n.right.invertedExpr())
+	case followedBy:
+		// The positions of the lexemes are not stored in the index.
+		expr := inverted.And(n.left.invertedExpr(), n.right.invertedExpr())
+		expr.SetNotTight()
+		return expr
+	}
+	return inverted.NonInvertedColExpression{}
+}
+
+func appendUint16(appendTo []byte, v uint16) []byte {
+	return append(appendTo, byte(v>>8), byte(v))
+}
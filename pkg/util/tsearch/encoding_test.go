@@ -0,0 +1,152 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTSVector(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`a`,
+		`'a' 'fat':2,5A cat:3B`,
+		`a:1A,2B,3C,4 b:16383 'with space' 'it''s'`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			v, err := ParseTSVector(input)
+			require.NoError(t, err)
+			encoded := EncodeTSVector(nil, v)
+			decoded, err := DecodeTSVector(encoded)
+			require.NoError(t, err)
+			require.Equal(t, v.String(), decoded.String())
+
+			for i := range encoded {
+				_, err := DecodeTSVector(encoded[:i])
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestEncodeTSQuery(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`a`,
+		`a:*AB`,
+		`'fat':AB & !cat | ra:* <-> (mat | bat)`,
+		`a <3> (b <-> c)`,
+		`!!a`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			q, err := ParseTSQuery(input)
+			require.NoError(t, err)
+			encoded := EncodeTSQuery(nil, q)
+			decoded, err := DecodeTSQuery(encoded)
+			require.NoError(t, err)
+			require.Equal(t, q.String(), decoded.String())
+
+			for i := range encoded {
+				_, err := DecodeTSQuery(encoded[:i])
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestGetInvertedExpr(t *testing.T) {
+	tcs := []struct {
+		query     string
+		indexable bool
+		tight     bool
+	}{
+		{`a`, true, true},
+		{`a:*`, true, true},
+		{`a:A`, true, false},
+		{`a & b`, true, true},
+		{`a | b:*`, true, true},
+		{`a & !b`, true, false},
+		{`!a & b`, true, false},
+		{`a <-> b`, true, false},
+		{`a | b:B`, true, false},
+		{`!a`, false, false},
+		{`a | !b`, false, false},
+		{`!a & !b`, false, false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			expr, err := q.GetInvertedExpr()
+			if !tc.indexable {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.tight, expr.IsTight())
+		})
+	}
+
+	// The keys of a vector are contained by the spans of queries which match
+	// it.
+	v, err := ParseTSVector(`fat:1 rat:2`)
+	require.NoError(t, err)
+	keys := EncodeInvertedIndexKeys(nil, v)
+	require.Len(t, keys, 2)
+	for _, tc := range []struct {
+		query    string
+		expected bool
+	}{
+		{`fat`, true},
+		{`fa:*`, true},
+		{`f:* & r:*`, true},
+		{`fat & cat`, false},
+		{`cat | rat`, true},
+		{`fatter`, false},
+		{`fatter:*`, false},
+	} {
+		q, err := ParseTSQuery(tc.query)
+		require.NoError(t, err)
+		expr, err := q.GetInvertedExpr()
+		require.NoError(t, err)
+		contains, err := expr.(*inverted.SpanExpression).ContainsKeys(keys)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, contains, tc.query)
+	}
+}
+
+func TestRandomRoundTrip(t *testing.T) {
+	rng, _ := randutil.NewTestRand()
+	for i := 0; i < 1000; i++ {
+		v := RandomTSVector(rng)
+		parsed, err := ParseTSVector(v.String())
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(parsed), v.String())
+		decoded, err := DecodeTSVector(EncodeTSVector(nil, v))
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(decoded), v.String())
+
+		q := RandomTSQuery(rng)
+		parsedQuery, err := ParseTSQuery(q.String())
+		require.NoError(t, err)
+		require.Equal(t, q.String(), parsedQuery.String())
+		decodedQuery, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+		require.NoError(t, err)
+		require.Equal(t, q.String(), decodedQuery.String())
+
+		// Evaluation must not depend on the representation.
+		require.Equal(t, EvalTSQuery(q, v), EvalTSQuery(parsedQuery, parsed))
+	}
+}
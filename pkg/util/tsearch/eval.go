@@ -0,0 +1,232 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "sort"
+
+// EvalTSQuery returns true if the query matches the vector, which is the
+// result of the @@ operator.
+func EvalTSQuery(q TSQuery, v TSVector) bool {
+	if q.root == nil {
+		return false
+	}
+	return evalNode(q.root, v)
+}
+
+// evalNode evaluates the node as a boolean expression over the lexemes of the
+// vector. Positions are only considered beneath followedBy operators.
+func evalNode(n *tsNode, v TSVector) bool {
+	if n.term != nil {
+		return len(termPositions(n.term, v)) > 0 || termMatchesStripped(n.term, v)
+	}
+	switch n.op {
+	case and:
+		return evalNode(n.left, v) && evalNode(n.right, v)
+	case or:
+		return evalNode(n.left, v) || evalNode(n.right, v)
+	case not:
+		return !evalNode(n.left, v)
+	case followedBy:
+		if !evalPositions(n, v).isEmpty() {
+			return true
+		}
+		// A phrase cannot be ruled out for lexemes without positions, so in
+		// that case the operator matches as if it were an and, as in Postgres.
+		return matchesStripped(n, v) && evalNode(n.left, v) && evalNode(n.right, v)
+	}
+	return false
+}
+
+// matchesStripped returns true if any term of the node matches a lexeme of the
+// vector which has no positions.
+func matchesStripped(n *tsNode, v TSVector) bool {
+	if n == nil {
+		return false
+	}
+	if n.term != nil {
+		return termMatchesStripped(n.term, v)
+	}
+	return matchesStripped(n.left, v) || matchesStripped(n.right, v)
+}
+
+// termMatchesStripped returns true if the term matches a lexeme of the vector
+// which has no positions. Such a lexeme matches terms without a weight
+// restriction.
+func termMatchesStripped(t *tsQueryTerm, v TSVector) bool {
+	if t.weight != weightAny {
+		return false
+	}
+	if !t.prefix {
+		i := v.find(t.lexeme)
+		return i != -1 && len(v[i].positions) == 0
+	}
+	start, end := v.findPrefix(t.lexeme)
+	for i := start; i < end; i++ {
+		if len(v[i].positions) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// termPositions returns the sorted positions of the lexemes of the vector
+// which match the term, filtered by the weights of the term.
+func termPositions(t *tsQueryTerm, v TSVector) []uint16 {
+	var ret []uint16
+	add := func(term *tsTerm) {
+		for _, p := range term.positions {
+			if p.weight&t.weight != 0 {
+				ret = append(ret, p.position)
+			}
+		}
+	}
+	if !t.prefix {
+		if i := v.find(t.lexeme); i != -1 {
+			add(&v[i])
+		}
+		return ret
+	}
+	start, end := v.findPrefix(t.lexeme)
+	for i := start; i < end; i++ {
+		add(&v[i])
+	}
+	if end-start > 1 {
+		ret = sortedUnique(ret)
+	}
+	return ret
+}
+
+// positionSet is a set of positions in a document. If negated is true, the
+// set contains every position except the listed ones.
+type positionSet struct {
+	positions []uint16
+	negated   bool
+}
+
+func (s positionSet) isEmpty() bool {
+	return !s.negated && len(s.positions) == 0
+}
+
+// evalPositions evaluates the node as an expression over positions of the
+// vector, returning the set of positions at which the node matches. For a
+// followedBy operator, this is the set of positions at which its right operand
+// matches.
+func evalPositions(n *tsNode, v TSVector) positionSet {
+	if n.term != nil {
+		return positionSet{positions: termPositions(n.term, v)}
+	}
+	switch n.op {
+	case not:
+		s := evalPositions(n.left, v)
+		s.negated = !s.negated
+		return s
+	case and:
+		return intersectPositions(evalPositions(n.left, v), evalPositions(n.right, v))
+	case or:
+		return unionPositions(evalPositions(n.left, v), evalPositions(n.right, v))
+	case followedBy:
+		left := evalPositions(n.left, v)
+		right := evalPositions(n.right, v)
+		// Shift the left positions by the distance, so that the result is the
+		// intersection of the shifted left positions with the right positions.
+		shifted := positionSet{negated: left.negated}
+		for _, p := range left.positions {
+			if pos := int(p) + n.distance; pos <= maxTSVectorPosition {
+				shifted.positions = append(shifted.positions, uint16(pos))
+			}
+		}
+		return intersectPositions(shifted, right)
+	}
+	return positionSet{}
+}
+
+// intersectPositions returns the intersection of the two sets.
+func intersectPositions(l, r positionSet) positionSet {
+	switch {
+	case !l.negated && !r.negated:
+		return positionSet{positions: intersectSorted(l.positions, r.positions)}
+	case l.negated && r.negated:
+		return positionSet{positions: unionSorted(l.positions, r.positions), negated: true}
+	case l.negated:
+		return positionSet{positions: subtractSorted(r.positions, l.positions)}
+	default:
+		return positionSet{positions: subtractSorted(l.positions, r.positions)}
+	}
+}
+
+// unionPositions returns the union of the two sets.
+func unionPositions(l, r positionSet) positionSet {
+	switch {
+	case !l.negated && !r.negated:
+		return positionSet{positions: unionSorted(l.positions, r.positions)}
+	case l.negated && r.negated:
+		return positionSet{positions: intersectSorted(l.positions, r.positions), negated: true}
+	case l.negated:
+		return positionSet{positions: subtractSorted(l.positions, r.positions), negated: true}
+	default:
+		return positionSet{positions: subtractSorted(r.positions, l.positions), negated: true}
+	}
+}
+
+func intersectSorted(l, r []uint16) []uint16 {
+	var ret []uint16
+	for i, j := 0, 0; i < len(l) && j < len(r); {
+		switch {
+		case l[i] < r[j]:
+			i++
+		case l[i] > r[j]:
+			j++
+		default:
+			ret = append(ret, l[i])
+			i++
+			j++
+		}
+	}
+	return ret
+}
+
+func unionSorted(l, r []uint16) []uint16 {
+	ret := make([]uint16, 0, len(l)+len(r))
+	ret = append(ret, l...)
+	ret = append(ret, r...)
+	return sortedUnique(ret)
+}
+
+// subtractSorted returns the positions of l which are not in r.
+func subtractSorted(l, r []uint16) []uint16 {
+	var ret []uint16
+	j := 0
+	for _, p := range l {
+		for j < len(r) && r[j] < p {
+			j++
+		}
+		if j < len(r) && r[j] == p {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// sortedUnique sorts the positions and removes duplicates.
+func sortedUnique(positions []uint16) []uint16 {
+	if len(positions) == 0 {
+		return positions
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	ret := positions[:1]
+	for _, p := range positions[1:] {
+		if p != ret[len(ret)-1] {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalTSQuery(t *testing.T) {
+	tcs := []struct {
+		vector   string
+		query    string
+		expected bool
+	}{
+		{`a b c`, `a`, true},
+		{`a b c`, `d`, false},
+		{`a b c`, ``, false},
+		{``, `a`, false},
+		{`a b c`, `a & b`, true},
+		{`a b c`, `a & d`, false},
+		{`a b c`, `a | d`, true},
+		{`a b c`, `!d`, true},
+		{`a b c`, `!a`, false},
+		{`a b c`, `a & !d`, true},
+		{`fat cat`, `fa:*`, true},
+		{`fat cat`, `fe:*`, false},
+
+		// Weights.
+		{`a:1A b:2`, `a:A`, true},
+		{`a:1A b:2`, `a:B`, false},
+		{`a:1A b:2`, `b:D`, true},
+		{`a:1A b:2`, `b:AB`, false},
+		{`a b`, `a:A`, false},
+		{`fat:1A fast:2`, `fa:*A`, true},
+		{`fat:1A fast:2`, `fa:*B`, false},
+
+		// Phrases.
+		{`a:1 b:2 c:3`, `a <-> b`, true},
+		{`a:1 b:2 c:3`, `b <-> a`, false},
+		{`a:1 b:2 c:3`, `a <2> c`, true},
+		{`a:1 b:2 c:3`, `a <-> c`, false},
+		{`a:1 b:2 c:3`, `a <-> b <-> c`, true},
+		{`a:1 b:2 c:3`, `a <0> a`, true},
+		{`a:1 b:1 c:3`, `a <0> b`, true},
+		{`a:1 b:2 c:3`, `a <-> (b | c)`, true},
+		{`a:1 b:2 c:3`, `a <-> (c | d)`, false},
+		{`a:1 b:2 c:3`, `a <-> !c`, true},
+		{`a:1 b:2 c:3`, `a <-> !b`, false},
+		{`a:1 b:2 c:3`, `(a <-> b) & c`, true},
+		{`a:1 b:2 c:3`, `a <-> (b & c)`, false},
+		{`a:1,4 b:2 c:5`, `a <-> c`, true},
+		{`a:1A b:2`, `a:B <-> b`, false},
+		{`a b`, `a <-> b`, true},
+		{`a b`, `a <-> c`, false},
+		{`the:1 fat:2 rat:3`, `fa:* <-> rat`, true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.vector+"@@"+tc.query, func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			require.NoError(t, err)
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, EvalTSQuery(q, v))
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// lexer scans the input representation of a TSVector or a TSQuery.
+type lexer struct {
+	input string
+	pos   int
+	// typName is the name of the type being parsed, used in error messages.
+	typName string
+}
+
+func (l *lexer) eof() bool {
+	return l.pos >= len(l.input)
+}
+
+func (l *lexer) peek() byte {
+	return l.input[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for !l.eof() {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+func (l *lexer) syntaxError() error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", l.typName, l.input)
+}
+
+// lexeme scans a lexeme, which is either quoted with single quotes or ends at
+// whitespace or at any of the given delimiters. A backslash escapes the
+// following character, and within quotes a doubled single quote represents a
+// single quote.
+func (l *lexer) lexeme(delimiters string) (string, error) {
+	var sb strings.Builder
+	if l.peek() == '\'' {
+		l.pos++
+		for {
+			if l.eof() {
+				return "", l.syntaxError()
+			}
+			c := l.peek()
+			switch {
+			case c == '\\':
+				l.pos++
+				if l.eof() {
+					return "", l.syntaxError()
+				}
+				sb.WriteByte(l.peek())
+			case c == '\'' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '\'':
+				l.pos++
+				sb.WriteByte('\'')
+			case c == '\'':
+				l.pos++
+				return l.checkLexeme(sb.String())
+			default:
+				sb.WriteByte(c)
+			}
+			l.pos++
+		}
+	}
+	for !l.eof() {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if unicode.IsSpace(r) || strings.ContainsRune(delimiters, r) {
+			break
+		}
+		if r == '\\' {
+			l.pos++
+			if l.eof() {
+				return "", l.syntaxError()
+			}
+			r, size = utf8.DecodeRuneInString(l.input[l.pos:])
+		}
+		sb.WriteRune(r)
+		l.pos += size
+	}
+	return l.checkLexeme(sb.String())
+}
+
+func (l *lexer) checkLexeme(lexeme string) (string, error) {
+	if lexeme == "" {
+		return "", l.syntaxError()
+	}
+	if len(lexeme) > maxLexemeLength {
+		return "", pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", len(lexeme), maxLexemeLength)
+	}
+	return lexeme, nil
+}
+
+// number scans a non-negative decimal integer. Values larger than max are
+// clamped to max.
+func (l *lexer) number(max int) (int, bool) {
+	start := l.pos
+	n := 0
+	for !l.eof() && l.peek() >= '0' && l.peek() <= '9' {
+		if n <= max {
+			n = n*10 + int(l.peek()-'0')
+		}
+		l.pos++
+	}
+	if n > max {
+		n = max
+	}
+	return n, l.pos > start
+}
+
+// lexTSVector scans the input representation of a TSVector into its terms,
+// which are neither sorted nor deduplicated.
+func lexTSVector(input string) ([]tsTerm, error) {
+	l := lexer{input: input, typName: "tsvector"}
+	var terms []tsTerm
+	for {
+		l.skipSpace()
+		if l.eof() {
+			return terms, nil
+		}
+		lexeme, err := l.lexeme(":")
+		if err != nil {
+			return nil, err
+		}
+		term := tsTerm{lexeme: lexeme}
+		if !l.eof() && l.peek() == ':' {
+			l.pos++
+			for {
+				pos, ok := l.number(maxTSVectorPosition)
+				if !ok || pos == 0 {
+					return nil, pgerror.Newf(pgcode.Syntax, "wrong position info in tsvector: %q", input)
+				}
+				p := tsPosition{position: uint16(pos), weight: weightD}
+				if !l.eof() {
+					if w, ok := parseWeight(l.peek()); ok {
+						p.weight = w
+						l.pos++
+					}
+				}
+				term.positions = append(term.positions, p)
+				if l.eof() || l.peek() != ',' {
+					break
+				}
+				l.pos++
+			}
+			if !l.eof() {
+				if r, _ := utf8.DecodeRuneInString(l.input[l.pos:]); !unicode.IsSpace(r) {
+					return nil, l.syntaxError()
+				}
+			}
+		}
+		terms = append(terms, term)
+	}
+}
+
+// tsQueryTokenKind is the kind of a token of the input representation of a
+// TSQuery.
+type tsQueryTokenKind int
+
+const (
+	tokenOperand tsQueryTokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenFollowedBy
+	tokenOpenParen
+	tokenCloseParen
+)
+
+// tsQueryToken is a token of the input representation of a TSQuery.
+type tsQueryToken struct {
+	kind tsQueryTokenKind
+	// term is set for operands.
+	term *tsQueryTerm
+	// distance is set for followed-by operators.
+	distance int
+}
+
+// maxFollowedByDistance is the maximum distance of a followed-by operator.
+const maxFollowedByDistance = maxTSVectorPosition
+
+// lexTSQuery scans the input representation of a TSQuery into tokens.
+func lexTSQuery(input string) ([]tsQueryToken, error) {
+	l := lexer{input: input, typName: "tsquery"}
+	var tokens []tsQueryToken
+	for {
+		l.skipSpace()
+		if l.eof() {
+			return tokens, nil
+		}
+		switch l.peek() {
+		case '&':
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenAnd})
+		case '|':
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenOr})
+		case '!':
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenNot})
+		case '(':
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenOpenParen})
+		case ')':
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenCloseParen})
+		case '<':
+			l.pos++
+			distance := 1
+			if !l.eof() && l.peek() == '-' {
+				l.pos++
+			} else {
+				n, ok := l.number(maxFollowedByDistance + 1)
+				if !ok {
+					return nil, l.syntaxError()
+				}
+				if n > maxFollowedByDistance {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"distance in phrase operator must be an integer value between zero and %d inclusive",
+						maxFollowedByDistance)
+				}
+				distance = n
+			}
+			if l.eof() || l.peek() != '>' {
+				return nil, l.syntaxError()
+			}
+			l.pos++
+			tokens = append(tokens, tsQueryToken{kind: tokenFollowedBy, distance: distance})
+		default:
+			lexeme, err := l.lexeme("&|!():<")
+			if err != nil {
+				return nil, err
+			}
+			term := &tsQueryTerm{lexeme: lexeme, weight: weightAny}
+			if !l.eof() && l.peek() == ':' {
+				l.pos++
+				var weight tsWeight
+				for !l.eof() {
+					c := l.peek()
+					if c == '*' {
+						term.prefix = true
+					} else if w, ok := parseWeight(c); ok {
+						weight |= w
+					} else {
+						break
+					}
+					l.pos++
+				}
+				if weight != 0 {
+					term.weight = weight
+				}
+			}
+			tokens = append(tokens, tsQueryToken{kind: tokenOperand, term: term})
+		}
+	}
+}
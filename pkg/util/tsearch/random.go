@@ -0,0 +1,84 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "math/rand"
+
+// Some matches will only be produced if vectors and queries share lexemes, so
+// we occasionally pull from a pool of common lexemes rather than generating a
+// completely random one.
+var staticLexemes = []string{
+	"a",
+	"b",
+	"cat",
+	"fat",
+	"rat",
+	"it's",
+}
+
+func randomLexeme(rng *rand.Rand) string {
+	if rng.Intn(2) == 0 {
+		return staticLexemes[rng.Intn(len(staticLexemes))]
+	}
+	result := make([]byte, rng.Intn(10)+1)
+	for i := range result {
+		result[i] = byte(rng.Intn(0x7f-0x20) + 0x20)
+	}
+	return string(result)
+}
+
+// RandomTSVector generates a random TSVector.
+func RandomTSVector(rng *rand.Rand) TSVector {
+	terms := make([]tsTerm, rng.Intn(10))
+	for i := range terms {
+		terms[i].lexeme = randomLexeme(rng)
+		for j, n := 0, rng.Intn(4); j < n; j++ {
+			terms[i].positions = append(terms[i].positions, tsPosition{
+				position: uint16(rng.Intn(maxTSVectorPosition) + 1),
+				weight:   weightD << rng.Intn(4),
+			})
+		}
+	}
+	return normalizeTSVector(terms)
+}
+
+// RandomTSQuery generates a random TSQuery.
+func RandomTSQuery(rng *rand.Rand) TSQuery {
+	if rng.Intn(10) == 0 {
+		return TSQuery{}
+	}
+	return TSQuery{root: randomTSNode(rng, 3 /* complexity */)}
+}
+
+func randomTSNode(rng *rand.Rand, complexity int) *tsNode {
+	if complexity <= 0 || rng.Intn(3) == 0 {
+		term := &tsQueryTerm{lexeme: randomLexeme(rng), weight: weightAny}
+		if rng.Intn(4) == 0 {
+			term.weight = tsWeight(rng.Intn(int(weightAny)) + 1)
+		}
+		term.prefix = rng.Intn(4) == 0
+		return &tsNode{term: term}
+	}
+	switch op := tsOperator(rng.Intn(int(followedBy)) + 1); op {
+	case not:
+		return &tsNode{op: not, left: randomTSNode(rng, complexity-1)}
+	default:
+		n := &tsNode{
+			op:    op,
+			left:  randomTSNode(rng, complexity-1),
+			right: randomTSNode(rng, complexity-1),
+		}
+		if op == followedBy {
+			n.distance = rng.Intn(4)
+		}
+		return n
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultWeights are the weights of the D, C, B and A labels used by the
+// ranking functions when none are specified.
+var DefaultWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
+
+// The normalization flags of the ranking functions, which may be combined.
+const (
+	// rankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	rankNormLogLength = 1 << iota
+	// rankNormLength divides the rank by the document length.
+	rankNormLength
+	// rankNormExtDist divides the rank by the mean harmonic distance between
+	// extents. It is only implemented by RankCD.
+	rankNormExtDist
+	// rankNormUniq divides the rank by the number of unique words in the
+	// document.
+	rankNormUniq
+	// rankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	rankNormLogUniq
+	// rankNormRDivRPlus1 divides the rank by itself + 1.
+	rankNormRDivRPlus1
+
+	rankNormMax = rankNormRDivRPlus1<<1 - 1
+)
+
+// MakeWeights converts the weights array argument of the ranking functions,
+// which is ordered {D, C, B, A}, to the weights used for ranking.
+func MakeWeights(weights []float64) ([4]float32, error) {
+	var ret [4]float32
+	if len(weights) < len(ret) {
+		return ret, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i := range ret {
+		w := weights[i]
+		if w > 1 {
+			return ret, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		}
+		if w < 0 {
+			w = float64(DefaultWeights[i])
+		}
+		ret[i] = float32(w)
+	}
+	return ret, nil
+}
+
+// rankItems returns the distinct terms of the query, which are the items the
+// ranking functions consider.
+func rankItems(q TSQuery) []*tsQueryTerm {
+	terms := q.terms(false /* skipNegated */)
+	ret := terms[:0]
+	seen := make(map[tsQueryTerm]struct{}, len(terms))
+	for _, t := range terms {
+		if _, ok := seen[*t]; ok {
+			continue
+		}
+		seen[*t] = struct{}{}
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// itemPositions returns the positions of the lexemes of the vector which
+// match the term, ignoring its weights. A lexeme without positions is treated
+// as having a single position 0 with weight D.
+func itemPositions(t *tsQueryTerm, v TSVector) []tsPosition {
+	start, end := v.findPrefix(t.lexeme)
+	if !t.prefix {
+		start, end = v.find(t.lexeme), 0
+		if start == -1 {
+			return nil
+		}
+		end = start + 1
+	}
+	var ret []tsPosition
+	for i := start; i < end; i++ {
+		if len(v[i].positions) == 0 {
+			ret = append(ret, tsPosition{weight: weightD})
+			continue
+		}
+		ret = append(ret, v[i].positions...)
+	}
+	return ret
+}
+
+// wordDistance is the contribution of the distance between two query items
+// to the rank of an and query.
+func wordDistance(dist int) float64 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return 1.0 / (1.005 + 0.05*math.Exp(float64(dist)/1.5-2))
+}
+
+// rankAnd ranks a vector for a query whose items must all match, considering
+// how close together the items appear.
+func rankAnd(weights [4]float32, v TSVector, items []*tsQueryTerm) float64 {
+	if len(items) < 2 {
+		return rankOr(weights, v, items)
+	}
+	positions := make([][]tsPosition, len(items))
+	for i, t := range items {
+		positions[i] = itemPositions(t, v)
+	}
+	res := -1.0
+	for i := range items {
+		for k := 0; k < i; k++ {
+			for _, pi := range positions[i] {
+				for _, pk := range positions[k] {
+					dist := int(pi.position) - int(pk.position)
+					if dist < 0 {
+						dist = -dist
+					}
+					if dist == 0 && pi.position != 0 && pk.position != 0 {
+						continue
+					}
+					curw := math.Sqrt(float64(weights[pi.weight.weightIndex()]) *
+						float64(weights[pk.weight.weightIndex()]) * wordDistance(dist))
+					if res < 0 {
+						res = curw
+					} else {
+						res = 1 - (1-res)*(1-curw)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// rankOr ranks a vector for a query of which any item may match.
+func rankOr(weights [4]float32, v TSVector, items []*tsQueryTerm) float64 {
+	var res float64
+	for _, t := range items {
+		positions := itemPositions(t, v)
+		if len(positions) == 0 {
+			continue
+		}
+		// Sum the weights of the positions, with the contribution of each
+		// successive position decreasing quadratically, except for the
+		// maximum weight, which contributes fully.
+		var resj, wjm float64 = 0, -1
+		jm := 0
+		for j, p := range positions {
+			wpos := float64(weights[p.weight.weightIndex()])
+			resj += wpos / float64((j+1)*(j+1))
+			if wpos > wjm {
+				wjm = wpos
+				jm = j
+			}
+		}
+		// Divide by the limit of the sum of 1/n^2, which is pi^2/6.
+		res += (wjm + resj - wjm/float64((jm+1)*(jm+1))) / 1.64493406685
+	}
+	if len(items) > 0 {
+		res /= float64(len(items))
+	}
+	return res
+}
+
+// documentLength returns the length of the vector for the purposes of
+// normalization, which is its number of positions. A lexeme without positions
+// counts once.
+func documentLength(v TSVector) int {
+	n := 0
+	for _, t := range v {
+		if len(t.positions) == 0 {
+			n++
+		}
+		n += len(t.positions)
+	}
+	return n
+}
+
+func checkNormalization(method int) error {
+	if method < 0 || method > rankNormMax {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"unrecognized normalization method: %d", method)
+	}
+	return nil
+}
+
+// normalizeRank applies the normalization flags, other than rankNormExtDist,
+// to the rank.
+func normalizeRank(res float64, v TSVector, method int) float64 {
+	if method&rankNormLogLength != 0 && len(v) > 0 {
+		res /= math.Log(float64(documentLength(v) + 1))
+	}
+	if method&rankNormLength != 0 {
+		if l := documentLength(v); l > 0 {
+			res /= float64(l)
+		}
+	}
+	if method&rankNormUniq != 0 && len(v) > 0 {
+		res /= float64(len(v))
+	}
+	if method&rankNormLogUniq != 0 && len(v) > 0 {
+		res /= math.Log(float64(len(v)+1)) / math.Log(2)
+	}
+	if method&rankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// Rank returns the rank of the vector for the query, based on the frequency
+// and weights of the matching lexemes. This is the implementation of ts_rank.
+func Rank(weights [4]float32, v TSVector, q TSQuery, method int) (float32, error) {
+	if err := checkNormalization(method); err != nil {
+		return 0, err
+	}
+	if len(v) == 0 || q.root == nil {
+		return 0, nil
+	}
+	items := rankItems(q)
+	var res float64
+	if q.root.op == and || q.root.op == followedBy {
+		res = rankAnd(weights, v, items)
+	} else {
+		res = rankOr(weights, v, items)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	return float32(normalizeRank(res, v, method)), nil
+}
+
+// docEntry is a position of a vector at which a query item matches.
+type docEntry struct {
+	position tsPosition
+	lexemes  []string
+}
+
+// extent is a minimal range of entries of a document within which the query
+// matches.
+type extent struct {
+	begin, end int
+}
+
+// RankCD returns the cover density rank of the vector for the query, based on
+// the number and length of the ranges of the document in which the query
+// matches. This is the implementation of ts_rank_cd.
+func RankCD(weights [4]float32, v TSVector, q TSQuery, method int) (float32, error) {
+	if err := checkNormalization(method); err != nil {
+		return 0, err
+	}
+	if len(v) == 0 || q.root == nil {
+		return 0, nil
+	}
+	doc := makeDocEntries(v, rankItems(q))
+	var invWeights [4]float64
+	for i, w := range weights {
+		if w > 0 {
+			invWeights[i] = 1 / float64(w)
+		}
+	}
+
+	var wdoc, sumDist, prevExtPos float64
+	nExtents := 0
+	for _, ext := range findExtents(doc, q) {
+		var invSum float64
+		for i := ext.begin; i <= ext.end; i++ {
+			invSum += invWeights[doc[i].position.weight.weightIndex()]
+		}
+		if invSum == 0 {
+			continue
+		}
+		cpos := float64(ext.end-ext.begin+1) / invSum
+		p, q := int(doc[ext.begin].position.position), int(doc[ext.end].position.position)
+		noise := (q - p) - (ext.end - ext.begin)
+		if noise < 0 {
+			noise = (ext.end - ext.begin) / 2
+		}
+		wdoc += cpos / float64(1+noise)
+
+		curExtPos := float64(p+q) / 2
+		if nExtents > 0 && curExtPos > prevExtPos {
+			sumDist += 1 / (curExtPos - prevExtPos)
+		}
+		prevExtPos = curExtPos
+		nExtents++
+	}
+	if method&rankNormExtDist != 0 && nExtents > 0 && sumDist > 0 {
+		wdoc /= float64(nExtents) / sumDist
+	}
+	return float32(normalizeRank(wdoc, v, method)), nil
+}
+
+// makeDocEntries returns the positions of the vector at which the items of
+// the query match, in order. Lexemes without positions are ignored.
+func makeDocEntries(v TSVector, items []*tsQueryTerm) []docEntry {
+	byPosition := make(map[uint16]*docEntry)
+	for _, t := range items {
+		start, end := v.findPrefix(t.lexeme)
+		for i := start; i < end; i++ {
+			if !t.prefix && v[i].lexeme != t.lexeme {
+				continue
+			}
+			for _, p := range v[i].positions {
+				e, ok := byPosition[p.position]
+				if !ok {
+					e = &docEntry{position: p}
+					byPosition[p.position] = e
+				}
+				e.lexemes = append(e.lexemes, v[i].lexeme)
+			}
+		}
+	}
+	doc := make([]docEntry, 0, len(byPosition))
+	for _, e := range byPosition {
+		doc = append(doc, *e)
+	}
+	sort.Slice(doc, func(i, j int) bool {
+		return doc[i].position.position < doc[j].position.position
+	})
+	return doc
+}
+
+// findExtents returns the extents of the document in which the query
+// matches. Each extent is found by extending its end until the query matches,
+// then advancing its beginning for as long as the query still matches. The
+// search for the next extent starts after the beginning of the previous one.
+func findExtents(doc []docEntry, q TSQuery) []extent {
+	var ret []extent
+	for start := 0; start < len(doc); {
+		end := -1
+		for e := start; e < len(doc); e++ {
+			if EvalTSQuery(q, docVector(doc[start:e+1])) {
+				end = e
+				break
+			}
+		}
+		if end == -1 {
+			break
+		}
+		begin := end
+		for ; begin > start; begin-- {
+			if EvalTSQuery(q, docVector(doc[begin:end+1])) {
+				break
+			}
+		}
+		ret = append(ret, extent{begin: begin, end: end})
+		start = begin + 1
+	}
+	return ret
+}
+
+// docVector returns a vector containing the lexemes of the entries.
+func docVector(entries []docEntry) TSVector {
+	var terms []tsTerm
+	for _, e := range entries {
+		for _, l := range e.lexemes {
+			terms = append(terms, tsTerm{lexeme: l, positions: []tsPosition{e.position}})
+		}
+	}
+	return normalizeTSVector(terms)
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	tcs := []struct {
+		vector string
+		query  string
+		method int
+		rank   float32
+		rankCD float32
+	}{
+		{`fat:2 cat:3`, `dog`, 0, 0, 0},
+		{`fat:2 cat:3`, `cat`, 0, 0.0607927, 0.1},
+		{`fat:2 cat:3`, `fat & cat`, 0, 0.0991032, 0.1},
+		{`fat:2 cat:3`, `fat <-> cat`, 0, 0.0991032, 0.1},
+		{`fat:2 cat:3`, `fat | cat`, 0, 0.0607927, 0.2},
+		{`fat:2A cat:3`, `cat`, 0, 0.0607927, 0.1},
+		{`fat:2A cat:3`, `fat`, 0, 0.6079271, 1},
+		{`fat:2,4 cat:3`, `fat`, 0, 0.0759909, 0.2},
+		{`fat:2 cat:3`, `cat`, 2, 0.0303964, 0.05},
+		{`fat:2 cat:3`, `cat`, 8, 0.0303964, 0.05},
+		{`fat:2 cat:3`, `cat`, 32, 0.0573088, 0.0909091},
+		{`fat:2 cat:3 rat:10`, `fat & rat`, 4, 0.0000000001, 0.0125},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.vector+"@@"+tc.query, func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			require.NoError(t, err)
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			if tc.method&rankNormExtDist == 0 {
+				rank, err := Rank(DefaultWeights, v, q, tc.method)
+				require.NoError(t, err)
+				require.InDelta(t, tc.rank, rank, 1e-6)
+			}
+			rankCD, err := RankCD(DefaultWeights, v, q, tc.method)
+			require.NoError(t, err)
+			require.InDelta(t, tc.rankCD, rankCD, 1e-6)
+		})
+	}
+
+	_, err := Rank(DefaultWeights, nil, TSQuery{}, 64)
+	require.Error(t, err)
+}
+
+func TestMakeWeights(t *testing.T) {
+	w, err := MakeWeights([]float64{0.5, -1, 0, 1})
+	require.NoError(t, err)
+	require.Equal(t, [4]float32{0.5, 0.2, 0, 1}, w)
+
+	_, err = MakeWeights([]float64{0.1, 0.2, 0.3})
+	require.Error(t, err)
+	_, err = MakeWeights([]float64{0.1, 0.2, 0.3, 1.1})
+	require.Error(t, err)
+}
@@ -0,0 +1,291 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+// stemEnglish returns the stem of a lowercase English word, computed with the
+// Porter stemming algorithm:
+//
+//   M.F. Porter, 1980, An algorithm for suffix stripping, Program, 14(3) pp
+//   130-137.
+//
+// Words which contain characters other than the lowercase ASCII letters, or
+// which are shorter than three letters, are returned unchanged.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	s := porterStemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+// porterStemmer holds the state of the Porter stemming algorithm. Following
+// the description of the algorithm, the word is b, and the stem which remains
+// when a suffix is removed is b[:j].
+type porterStemmer struct {
+	b []byte
+	j int
+}
+
+// isConsonant returns true if b[i] is a consonant. The letter y is a
+// consonant when it follows a vowel or begins the word.
+func (s *porterStemmer) isConsonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.isConsonant(i-1)
+	}
+	return true
+}
+
+// measure returns the number of vowel-consonant sequences in b[:j]. Writing
+// [C] and [V] for optional sequences of consonants and vowels, any stem has
+// the form [C](VC){m}[V], and m is its measure.
+func (s *porterStemmer) measure() int {
+	n, i := 0, 0
+	for ; i < s.j && s.isConsonant(i); i++ {
+	}
+	for i < s.j {
+		for ; i < s.j && !s.isConsonant(i); i++ {
+		}
+		if i >= s.j {
+			break
+		}
+		n++
+		for ; i < s.j && s.isConsonant(i); i++ {
+		}
+	}
+	return n
+}
+
+// stemHasVowel returns true if b[:j] contains a vowel.
+func (s *porterStemmer) stemHasVowel() bool {
+	for i := 0; i < s.j; i++ {
+		if !s.isConsonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant returns true if b[i-1:i+1] is a double consonant.
+func (s *porterStemmer) doubleConsonant(i int) bool {
+	return i >= 1 && s.b[i] == s.b[i-1] && s.isConsonant(i)
+}
+
+// cvc returns true if b[i-2:i+1] has the form consonant-vowel-consonant, and
+// the final consonant is not w, x or y. This is used to restore an e at the
+// end of short words, e.g. cav(e), lov(e), hop(e), crim(e), but not snow,
+// box or tray.
+func (s *porterStemmer) cvc(i int) bool {
+	if i < 2 || !s.isConsonant(i) || s.isConsonant(i-1) || !s.isConsonant(i-2) {
+		return false
+	}
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// endsWith returns true if the word ends with the given suffix, in which
+// case j is set to the length of the stem which precedes the suffix.
+func (s *porterStemmer) endsWith(suffix string) bool {
+	n := len(s.b) - len(suffix)
+	if n < 0 || string(s.b[n:]) != suffix {
+		return false
+	}
+	s.j = n
+	return true
+}
+
+// setTo replaces the suffix which follows the stem b[:j] with the given
+// string.
+func (s *porterStemmer) setTo(str string) {
+	s.b = append(s.b[:s.j], str...)
+}
+
+// replaceIfMeasure replaces the suffix with the given string if the measure
+// of the stem is greater than zero.
+func (s *porterStemmer) replaceIfMeasure(str string) {
+	if s.measure() > 0 {
+		s.setTo(str)
+	}
+}
+
+// step1a removes plurals, e.g. caresses -> caress, ponies -> poni,
+// cats -> cat.
+func (s *porterStemmer) step1a() {
+	if s.b[len(s.b)-1] != 's' {
+		return
+	}
+	switch {
+	case s.endsWith("sses"):
+		s.setTo("ss")
+	case s.endsWith("ies"):
+		s.setTo("i")
+	case s.endsWith("ss"):
+	case s.endsWith("s"):
+		s.setTo("")
+	}
+}
+
+// step1b removes -ed and -ing, e.g. agreed -> agree, plastered -> plaster,
+// motoring -> motor, hopping -> hop, filing -> file.
+func (s *porterStemmer) step1b() {
+	if s.endsWith("eed") {
+		if s.measure() > 0 {
+			s.setTo("ee")
+		}
+		return
+	}
+	if !(s.endsWith("ed") || s.endsWith("ing")) || !s.stemHasVowel() {
+		return
+	}
+	s.setTo("")
+	switch {
+	case s.endsWith("at"):
+		s.setTo("ate")
+	case s.endsWith("bl"):
+		s.setTo("ble")
+	case s.endsWith("iz"):
+		s.setTo("ize")
+	case s.doubleConsonant(len(s.b) - 1):
+		switch s.b[len(s.b)-1] {
+		case 'l', 's', 'z':
+		default:
+			s.b = s.b[:len(s.b)-1]
+		}
+	default:
+		s.j = len(s.b)
+		if s.measure() == 1 && s.cvc(len(s.b)-1) {
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem,
+// e.g. happy -> happi.
+func (s *porterStemmer) step1c() {
+	if s.endsWith("y") && s.stemHasVowel() {
+		s.setTo("i")
+	}
+}
+
+// suffixRule maps a suffix to its replacement.
+type suffixRule struct {
+	suffix, replacement string
+}
+
+var step2Rules = []suffixRule{
+	{"ational", "ate"},
+	{"tional", "tion"},
+	{"enci", "ence"},
+	{"anci", "ance"},
+	{"izer", "ize"},
+	{"bli", "ble"},
+	{"alli", "al"},
+	{"entli", "ent"},
+	{"eli", "e"},
+	{"ousli", "ous"},
+	{"ization", "ize"},
+	{"ation", "ate"},
+	{"ator", "ate"},
+	{"alism", "al"},
+	{"iveness", "ive"},
+	{"fulness", "ful"},
+	{"ousness", "ous"},
+	{"aliti", "al"},
+	{"iviti", "ive"},
+	{"biliti", "ble"},
+	{"logi", "log"},
+}
+
+// step2 maps double suffixes to single ones when the stem has a positive
+// measure, e.g. -ization -> -ize.
+func (s *porterStemmer) step2() {
+	for _, r := range step2Rules {
+		if s.endsWith(r.suffix) {
+			s.replaceIfMeasure(r.replacement)
+			return
+		}
+	}
+}
+
+var step3Rules = []suffixRule{
+	{"icate", "ic"},
+	{"ative", ""},
+	{"alize", "al"},
+	{"iciti", "ic"},
+	{"ical", "ic"},
+	{"ful", ""},
+	{"ness", ""},
+}
+
+// step3 handles -ic-, -full, -ness etc.
+func (s *porterStemmer) step3() {
+	for _, r := range step3Rules {
+		if s.endsWith(r.suffix) {
+			s.replaceIfMeasure(r.replacement)
+			return
+		}
+	}
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes -ant, -ence etc. when the measure of the stem is greater than
+// one.
+func (s *porterStemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !s.endsWith(suffix) {
+			continue
+		}
+		if suffix == "ion" && (s.j == 0 || (s.b[s.j-1] != 's' && s.b[s.j-1] != 't')) {
+			return
+		}
+		if s.measure() > 1 {
+			s.setTo("")
+		}
+		return
+	}
+}
+
+// step5 removes a final -e and changes -ll to -l when the measure of the stem
+// is greater than one.
+func (s *porterStemmer) step5() {
+	s.j = len(s.b)
+	if s.b[len(s.b)-1] == 'e' {
+		s.j = len(s.b) - 1
+		if m := s.measure(); m > 1 || (m == 1 && !s.cvc(len(s.b)-2)) {
+			s.b = s.b[:len(s.b)-1]
+		}
+		s.j = len(s.b)
+	}
+	if s.b[len(s.b)-1] == 'l' && s.doubleConsonant(len(s.b)-1) && s.measure() > 1 {
+		s.b = s.b[:len(s.b)-1]
+	}
+}
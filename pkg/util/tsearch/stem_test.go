@@ -0,0 +1,62 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStemEnglish(t *testing.T) {
+	// The expected stems are those given by the reference implementation of
+	// the Porter stemmer.
+	for word, expected := range map[string]string{
+		"a":               "a",
+		"is":              "is",
+		"cats":            "cat",
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"caress":          "caress",
+		"feed":            "feed",
+		"agreed":          "agre",
+		"plastered":       "plaster",
+		"motoring":        "motor",
+		"sing":            "sing",
+		"conflated":       "conflat",
+		"troubled":        "troubl",
+		"sized":           "size",
+		"hopping":         "hop",
+		"falling":         "fall",
+		"hissing":         "hiss",
+		"fizzed":          "fizz",
+		"failing":         "fail",
+		"filing":          "file",
+		"happy":           "happi",
+		"sky":             "sky",
+		"relational":      "relat",
+		"conditional":     "condit",
+		"rational":        "ration",
+		"digitizer":       "digit",
+		"generalizations": "gener",
+		"oscillators":     "oscil",
+		"triplicate":      "triplic",
+		"hopefulness":     "hope",
+		"revival":         "reviv",
+		"adoption":        "adopt",
+		"controlling":     "control",
+		"rolled":          "roll",
+		"naïve":           "naïve",
+		"2022":            "2022",
+	} {
+		require.Equal(t, expected, stemEnglish(word), word)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// tsOperator is the operator of an interior node of a TSQuery.
+type tsOperator byte
+
+const (
+	invalidOp tsOperator = iota
+	// and matches if both operands match.
+	and
+	// or matches if either operand matches.
+	or
+	// not matches if its operand does not match.
+	not
+	// followedBy matches if the right operand matches at a position which is
+	// a given distance after a position at which the left operand matches.
+	followedBy
+)
+
+// precedence returns the binding strength of the operator: a higher
+// precedence binds more tightly.
+func (o tsOperator) precedence() int {
+	switch o {
+	case or:
+		return 1
+	case and:
+		return 2
+	case followedBy:
+		return 3
+	case not:
+		return 4
+	}
+	return 0
+}
+
+// tsQueryTerm is a leaf of a TSQuery, which matches a lexeme of a TSVector.
+type tsQueryTerm struct {
+	lexeme string
+	// weight is the set of weights of the positions of the lexeme which the
+	// term matches.
+	weight tsWeight
+	// prefix is true if the term matches any lexeme which begins with it.
+	prefix bool
+}
+
+// tsNode is a node of a TSQuery. Leaves have a term, and interior nodes have
+// an operator along with one (for not) or two operands.
+type tsNode struct {
+	term *tsQueryTerm
+
+	op    tsOperator
+	left  *tsNode
+	right *tsNode
+	// distance is the distance of a followedBy operator.
+	distance int
+}
+
+// TSQuery is a boolean expression of lexemes which can be matched against a
+// TSVector. It is the Go representation of the Postgres tsquery type.
+type TSQuery struct {
+	// root is nil for an empty query, which does not match any vector.
+	root *tsNode
+}
+
+// ParseTSQuery parses the input representation of a TSQuery, for example:
+//
+//   'fat':AB & !cat | ra:* <-> (mat | bat)
+//
+// Operands are lexemes as described by ParseTSVector, which may be followed by
+// a colon, a * to match lexemes with the given prefix, and the weights to
+// match. In decreasing order of precedence, the operators are ! (not), <->
+// (followed by, where <N> specifies a distance of N), & (and) and | (or).
+// Lexemes are not normalized.
+func ParseTSQuery(input string) (TSQuery, error) {
+	tokens, err := lexTSQuery(input)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p := tsQueryParser{input: input, tokens: tokens}
+	return p.parse()
+}
+
+// tsQueryParser is a recursive-descent parser of tsquery tokens.
+type tsQueryParser struct {
+	input  string
+	tokens []tsQueryToken
+	pos    int
+}
+
+func (p *tsQueryParser) parse() (TSQuery, error) {
+	if len(p.tokens) == 0 {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseExpr(or.precedence())
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if p.pos != len(p.tokens) {
+		return TSQuery{}, p.syntaxError()
+	}
+	return TSQuery{root: root}, nil
+}
+
+func (p *tsQueryParser) syntaxError() error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in tsquery: %q", p.input)
+}
+
+// binaryOp returns the operator of the given token, if it is a binary
+// operator.
+func binaryOp(tok tsQueryToken) (tsOperator, bool) {
+	switch tok.kind {
+	case tokenAnd:
+		return and, true
+	case tokenOr:
+		return or, true
+	case tokenFollowedBy:
+		return followedBy, true
+	}
+	return invalidOp, false
+}
+
+// parseExpr parses a sequence of operands separated by binary operators whose
+// precedence is at least minPrecedence. All binary operators are left
+// associative.
+func (p *tsQueryParser) parseExpr(minPrecedence int) (*tsNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		op, ok := binaryOp(tok)
+		if !ok || op.precedence() < minPrecedence {
+			break
+		}
+		p.pos++
+		right, err := p.parseExpr(op.precedence() + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{op: op, left: left, right: right, distance: tok.distance}
+	}
+	return left, nil
+}
+
+// parseUnary parses an operand, a negated operand or a parenthesized
+// expression.
+func (p *tsQueryParser) parseUnary() (*tsNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.syntaxError()
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenOperand:
+		return &tsNode{term: tok.term}, nil
+	case tokenNot:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &tsNode{op: not, left: operand}, nil
+	case tokenOpenParen:
+		expr, err := p.parseExpr(or.precedence())
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenCloseParen {
+			return nil, p.syntaxError()
+		}
+		p.pos++
+		return expr, nil
+	}
+	return nil, p.syntaxError()
+}
+
+// String returns the output representation of the TSQuery.
+func (q TSQuery) String() string {
+	if q.root == nil {
+		return ""
+	}
+	var sb strings.Builder
+	q.root.format(&sb, 0 /* parentPrecedence */, false /* rightOfFollowedBy */)
+	return sb.String()
+}
+
+// format writes the output representation of the node. Parentheses are added
+// if the operator of the node binds less tightly than that of its parent. The
+// right operand of a followedBy operator is parenthesized if it is itself a
+// followedBy operator, since the operator is not associative.
+func (n *tsNode) format(sb *strings.Builder, parentPrecedence int, rightOfFollowedBy bool) {
+	if n.term != nil {
+		writeQuotedLexeme(sb, n.term.lexeme)
+		if n.term.prefix || n.term.weight != weightAny {
+			sb.WriteByte(':')
+			if n.term.prefix {
+				sb.WriteByte('*')
+			}
+			if n.term.weight != weightAny {
+				sb.WriteString(n.term.weight.string(true /* includeD */))
+			}
+		}
+		return
+	}
+	precedence := n.op.precedence()
+	parens := precedence < parentPrecedence || (n.op == followedBy && rightOfFollowedBy)
+	if parens {
+		sb.WriteString("( ")
+	}
+	switch n.op {
+	case not:
+		sb.WriteByte('!')
+		n.left.format(sb, precedence, false /* rightOfFollowedBy */)
+	default:
+		n.left.format(sb, precedence, false /* rightOfFollowedBy */)
+		switch n.op {
+		case and:
+			sb.WriteString(" & ")
+		case or:
+			sb.WriteString(" | ")
+		case followedBy:
+			if n.distance == 1 {
+				sb.WriteString(" <-> ")
+			} else {
+				sb.WriteString(" <" + strconv.Itoa(n.distance) + "> ")
+			}
+		}
+		n.right.format(sb, precedence, n.op == followedBy)
+	}
+	if parens {
+		sb.WriteString(" )")
+	}
+}
+
+// Compare returns -1, 0 or 1 if the query is less than, equal to or greater
+// than the other query. Queries are ordered by their number of nodes, then by
+// their output representations.
+func (q TSQuery) Compare(other TSQuery) int {
+	if l, r := q.NumNodes(), other.NumNodes(); l != r {
+		return compareInts(l, r)
+	}
+	return strings.Compare(q.String(), other.String())
+}
+
+// NumNodes returns the number of operands and operators of the query.
+func (q TSQuery) NumNodes() int {
+	var count func(n *tsNode) int
+	count = func(n *tsNode) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + count(n.left) + count(n.right)
+	}
+	return count(q.root)
+}
+
+// terms returns the terms of the query, in order of appearance. Terms which
+// appear only beneath a not operator are excluded if skipNegated is true.
+func (q TSQuery) terms(skipNegated bool) []*tsQueryTerm {
+	var ret []*tsQueryTerm
+	var walk func(n *tsNode)
+	walk = func(n *tsNode) {
+		if n == nil {
+			return
+		}
+		if n.term != nil {
+			ret = append(ret, n.term)
+			return
+		}
+		if n.op == not && skipNegated {
+			return
+		}
+		walk(n.left)
+		walk(n.right)
+	}
+	walk(q.root)
+	return ret
+}
+
+// And returns the conjunction of the two queries.
+func (q TSQuery) And(other TSQuery) TSQuery {
+	return combineQueries(and, q, other, 0 /* distance */)
+}
+
+// Or returns the disjunction of the two queries.
+func (q TSQuery) Or(other TSQuery) TSQuery {
+	return combineQueries(or, q, other, 0 /* distance */)
+}
+
+// FollowedBy returns a query which matches if other matches at the given
+// distance after a match of q.
+func (q TSQuery) FollowedBy(other TSQuery, distance int) (TSQuery, error) {
+	if distance < 0 || distance > maxFollowedByDistance {
+		return TSQuery{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxFollowedByDistance)
+	}
+	return combineQueries(followedBy, q, other, distance), nil
+}
+
+// Not returns the negation of the query.
+func (q TSQuery) Not() TSQuery {
+	if q.root == nil {
+		return q
+	}
+	return TSQuery{root: &tsNode{op: not, left: q.root}}
+}
+
+// combineQueries combines the two queries with a binary operator. If either
+// query is empty, the other is returned.
+func combineQueries(op tsOperator, left, right TSQuery, distance int) TSQuery {
+	if left.root == nil {
+		return right
+	}
+	if right.root == nil {
+		return left
+	}
+	return TSQuery{root: &tsNode{op: op, left: left.root, right: right.root, distance: distance}}
+}
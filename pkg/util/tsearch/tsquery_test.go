@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`   `, ``},
+		{`foo`, `'foo'`},
+		{`'foo bar'`, `'foo bar'`},
+		{`a & b`, `'a' & 'b'`},
+		{`a | b & c`, `'a' | 'b' & 'c'`},
+		{`(a | b) & c`, `( 'a' | 'b' ) & 'c'`},
+		{`a & (b & c)`, `'a' & 'b' & 'c'`},
+		{`!a`, `!'a'`},
+		{`!!a`, `!!'a'`},
+		{`!(a & b)`, `!( 'a' & 'b' )`},
+		{`a <-> b`, `'a' <-> 'b'`},
+		{`a <2> b`, `'a' <2> 'b'`},
+		{`a <0> b`, `'a' <0> 'b'`},
+		{`a <-> b <-> c`, `'a' <-> 'b' <-> 'c'`},
+		{`a <-> (b <-> c)`, `'a' <-> ( 'b' <-> 'c' )`},
+		{`a & b <-> c`, `'a' & 'b' <-> 'c'`},
+		{`(a & b) <-> c`, `( 'a' & 'b' ) <-> 'c'`},
+		{`a:*`, `'a':*`},
+		{`a:AB`, `'a':AB`},
+		{`a:ba`, `'a':AB`},
+		{`a:*d`, `'a':*D`},
+		{`a:d*`, `'a':*D`},
+		{`a:abcd`, `'a'`},
+		{`'fat':AB & !cat | ra:* <-> (mat | bat)`, `'fat':AB & !'cat' | 'ra':* <-> ( 'mat' | 'bat' )`},
+		{`'it''s'`, `'it''s'`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.String())
+
+			// The output representation must round trip.
+			q2, err := ParseTSQuery(q.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q2.String())
+		})
+	}
+}
+
+func TestParseTSQueryError(t *testing.T) {
+	for _, input := range []string{
+		`a b`,
+		`a &`,
+		`& a`,
+		`a | | b`,
+		`(a`,
+		`a)`,
+		`()`,
+		`!`,
+		`a <- b`,
+		`a <-1> b`,
+		`a <16384> b`,
+		`a <x> b`,
+		`a:e`,
+		`'unterminated`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSQuery(input)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTSQueryFunctions(t *testing.T) {
+	mustParse := func(s string) TSQuery {
+		q, err := ParseTSQuery(s)
+		require.NoError(t, err)
+		return q
+	}
+
+	a, b := mustParse(`a | b`), mustParse(`c`)
+	require.Equal(t, 3, a.NumNodes())
+	require.Equal(t, 0, TSQuery{}.NumNodes())
+	require.Equal(t, `( 'a' | 'b' ) & 'c'`, a.And(b).String())
+	require.Equal(t, `'a' | 'b' | 'c'`, a.Or(b).String())
+	require.Equal(t, `!( 'a' | 'b' )`, a.Not().String())
+	require.Equal(t, `'c'`, TSQuery{}.And(b).String())
+
+	followed, err := a.FollowedBy(b, 3)
+	require.NoError(t, err)
+	require.Equal(t, `( 'a' | 'b' ) <3> 'c'`, followed.String())
+	_, err = a.FollowedBy(b, -1)
+	require.Error(t, err)
+
+	require.Equal(t, -1, b.Compare(a))
+	require.Equal(t, 1, a.Compare(b))
+	require.Equal(t, 0, a.Compare(mustParse(`a|b`)))
+	require.Equal(t, -1, mustParse(`a`).Compare(mustParse(`b`)))
+}
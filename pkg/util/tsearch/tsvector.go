@@ -0,0 +1,387 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the full-text search data types, TSVector and
+// TSQuery, along with the text parsing, normalization, matching and ranking
+// algorithms which operate on them. The semantics follow those of the
+// identically named Postgres types and functions.
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// tsWeight is the weight of a position of a lexeme in a TSVector, or the set
+// of weights which a term of a TSQuery can match. Postgres labels weights with
+// the letters A through D, where D is the default and A is the most important.
+type tsWeight byte
+
+const (
+	weightD tsWeight = 1 << iota
+	weightC
+	weightB
+	weightA
+
+	// weightAny is the set of all weights. A query term without a weight
+	// restriction matches any weight.
+	weightAny = weightA | weightB | weightC | weightD
+)
+
+// weightIndex returns the index of a single weight in the weights array
+// passed to the ranking functions, which is ordered {D, C, B, A}.
+func (w tsWeight) weightIndex() int {
+	switch w {
+	case weightA:
+		return 3
+	case weightB:
+		return 2
+	case weightC:
+		return 1
+	}
+	return 0
+}
+
+// String returns the letters of the weights in the set, most important first.
+// The default weight, D, is omitted unless includeD is true.
+func (w tsWeight) string(includeD bool) string {
+	var sb strings.Builder
+	if w&weightA != 0 {
+		sb.WriteByte('A')
+	}
+	if w&weightB != 0 {
+		sb.WriteByte('B')
+	}
+	if w&weightC != 0 {
+		sb.WriteByte('C')
+	}
+	if includeD && w&weightD != 0 {
+		sb.WriteByte('D')
+	}
+	return sb.String()
+}
+
+// parseWeight returns the weight labeled with the given letter.
+func parseWeight(c byte) (tsWeight, bool) {
+	switch c {
+	case 'A', 'a':
+		return weightA, true
+	case 'B', 'b':
+		return weightB, true
+	case 'C', 'c':
+		return weightC, true
+	case 'D', 'd':
+		return weightD, true
+	}
+	return 0, false
+}
+
+const (
+	// maxTSVectorPosition is the largest position which can be stored in a
+	// TSVector. Larger positions are clamped to this value.
+	maxTSVectorPosition = 1<<14 - 1
+	// maxTSVectorPositions is the maximum number of positions stored for a
+	// single lexeme. Additional positions are dropped.
+	maxTSVectorPositions = 256
+	// maxLexemeLength is the maximum length, in bytes, of a lexeme.
+	maxLexemeLength = 1<<11 - 1
+)
+
+// tsPosition is a position of a lexeme in a document, along with its weight.
+type tsPosition struct {
+	position uint16
+	weight   tsWeight
+}
+
+// tsTerm is a lexeme in a TSVector, along with the positions at which it
+// appears in the document. A term without positions is the result of
+// stripping a TSVector, or of casting a string without positions to a
+// TSVector.
+type tsTerm struct {
+	lexeme    string
+	positions []tsPosition
+}
+
+// TSVector is a sorted list of distinct lexemes, each of which may be
+// annotated with the positions at which it appears in a document. It is the
+// Go representation of the Postgres tsvector type.
+type TSVector []tsTerm
+
+// ParseTSVector parses the input representation of a TSVector, for example:
+//
+//   'a' 'fat':2,5A cat:3B
+//
+// Lexemes are separated by whitespace, and may be quoted with single quotes.
+// Within a lexeme, a backslash escapes the following character. Lexemes are
+// not normalized.
+func ParseTSVector(input string) (TSVector, error) {
+	terms, err := lexTSVector(input)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeTSVector(terms), nil
+}
+
+// normalizeTSVector sorts the terms of the vector, merging the positions of
+// duplicate lexemes.
+func normalizeTSVector(terms []tsTerm) TSVector {
+	if len(terms) == 0 {
+		return TSVector{}
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return terms[i].lexeme < terms[j].lexeme
+	})
+	ret := terms[:1]
+	for _, t := range terms[1:] {
+		last := &ret[len(ret)-1]
+		if last.lexeme == t.lexeme {
+			last.positions = append(last.positions, t.positions...)
+			continue
+		}
+		ret = append(ret, t)
+	}
+	for i := range ret {
+		ret[i].positions = normalizePositions(ret[i].positions)
+	}
+	return ret
+}
+
+// normalizePositions sorts the positions and removes duplicates. When a
+// position is repeated, the most important weight is kept.
+func normalizePositions(positions []tsPosition) []tsPosition {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].position < positions[j].position
+	})
+	ret := positions[:1]
+	for _, p := range positions[1:] {
+		last := &ret[len(ret)-1]
+		if last.position == p.position {
+			if p.weight > last.weight {
+				last.weight = p.weight
+			}
+			continue
+		}
+		ret = append(ret, p)
+	}
+	if len(ret) > maxTSVectorPositions {
+		ret = ret[:maxTSVectorPositions]
+	}
+	return ret
+}
+
+// String returns the output representation of the TSVector.
+func (v TSVector) String() string {
+	var sb strings.Builder
+	for i, t := range v {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		writeQuotedLexeme(&sb, t.lexeme)
+		for j, p := range t.positions {
+			if j == 0 {
+				sb.WriteByte(':')
+			} else {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(strconv.Itoa(int(p.position)))
+			sb.WriteString(p.weight.string(false /* includeD */))
+		}
+	}
+	return sb.String()
+}
+
+// writeQuotedLexeme writes the lexeme in single quotes, doubling any single
+// quotes and backslashes it contains.
+func writeQuotedLexeme(sb *strings.Builder, lexeme string) {
+	sb.WriteByte('\'')
+	for i := 0; i < len(lexeme); i++ {
+		c := lexeme[i]
+		if c == '\'' || c == '\\' {
+			sb.WriteByte(c)
+		}
+		sb.WriteByte(c)
+	}
+	sb.WriteByte('\'')
+}
+
+// StringSize returns the size of the output representation of the TSVector.
+func (v TSVector) StringSize() int {
+	size := 0
+	for _, t := range v {
+		size += len(t.lexeme) + 3 + 6*len(t.positions)
+	}
+	return size
+}
+
+// Compare returns -1, 0 or 1 if the vector is less than, equal to or greater
+// than the other vector. Vectors are ordered by their number of lexemes, then
+// by their lexemes, then by their positions.
+func (v TSVector) Compare(other TSVector) int {
+	if len(v) != len(other) {
+		return compareInts(len(v), len(other))
+	}
+	for i := range v {
+		if c := strings.Compare(v[i].lexeme, other[i].lexeme); c != 0 {
+			return c
+		}
+		l, r := v[i].positions, other[i].positions
+		if len(l) != len(r) {
+			return compareInts(len(l), len(r))
+		}
+		for j := range l {
+			if l[j].position != r[j].position {
+				return compareInts(int(l[j].position), int(r[j].position))
+			}
+			if l[j].weight != r[j].weight {
+				return compareInts(int(l[j].weight), int(r[j].weight))
+			}
+		}
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Len returns the number of distinct lexemes in the vector.
+func (v TSVector) Len() int {
+	return len(v)
+}
+
+// Lexemes returns the lexemes of the vector, in order.
+func (v TSVector) Lexemes() []string {
+	ret := make([]string, len(v))
+	for i := range v {
+		ret[i] = v[i].lexeme
+	}
+	return ret
+}
+
+// Strip returns a copy of the vector without position and weight
+// information.
+func (v TSVector) Strip() TSVector {
+	ret := make(TSVector, len(v))
+	for i := range v {
+		ret[i] = tsTerm{lexeme: v[i].lexeme}
+	}
+	return ret
+}
+
+// SetWeight returns a copy of the vector with the weight of every position
+// set to the weight with the given label.
+func (v TSVector) SetWeight(weight string) (TSVector, error) {
+	if len(weight) != 1 {
+		return nil, errUnrecognizedWeight(weight)
+	}
+	w, ok := parseWeight(weight[0])
+	if !ok {
+		return nil, errUnrecognizedWeight(weight)
+	}
+	ret := make(TSVector, len(v))
+	for i := range v {
+		ret[i].lexeme = v[i].lexeme
+		if len(v[i].positions) > 0 {
+			ret[i].positions = make([]tsPosition, len(v[i].positions))
+			for j, p := range v[i].positions {
+				ret[i].positions[j] = tsPosition{position: p.position, weight: w}
+			}
+		}
+	}
+	return ret, nil
+}
+
+func errUnrecognizedWeight(weight string) error {
+	return pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", weight)
+}
+
+// Concat returns the concatenation of the two vectors. The positions of the
+// lexemes of the second vector are shifted past the largest position of the
+// first vector, as if the two documents were concatenated.
+func (v TSVector) Concat(other TSVector) TSVector {
+	var maxPos uint16
+	for _, t := range v {
+		for _, p := range t.positions {
+			if p.position > maxPos {
+				maxPos = p.position
+			}
+		}
+	}
+	terms := make([]tsTerm, 0, len(v)+len(other))
+	for _, t := range v {
+		terms = append(terms, tsTerm{
+			lexeme: t.lexeme, positions: append([]tsPosition(nil), t.positions...),
+		})
+	}
+	for _, t := range other {
+		term := tsTerm{lexeme: t.lexeme}
+		for _, p := range t.positions {
+			pos := int(p.position) + int(maxPos)
+			if pos > maxTSVectorPosition {
+				pos = maxTSVectorPosition
+			}
+			term.positions = append(term.positions, tsPosition{position: uint16(pos), weight: p.weight})
+		}
+		terms = append(terms, term)
+	}
+	return normalizeTSVector(terms)
+}
+
+// MakeTSVectorFromLexemes returns a vector containing the given lexemes,
+// without positions. It is an error for a lexeme to be empty.
+func MakeTSVectorFromLexemes(lexemes []string) (TSVector, error) {
+	terms := make([]tsTerm, len(lexemes))
+	for i, l := range lexemes {
+		if l == "" {
+			return nil, pgerror.New(pgcode.ZeroLengthCharacterString,
+				"lexeme array may not contain empty strings")
+		}
+		terms[i] = tsTerm{lexeme: l}
+	}
+	return normalizeTSVector(terms), nil
+}
+
+// find returns the index of the term of the vector with the given lexeme, or
+// -1 if there is no such term.
+func (v TSVector) find(lexeme string) int {
+	i := sort.Search(len(v), func(i int) bool {
+		return v[i].lexeme >= lexeme
+	})
+	if i < len(v) && v[i].lexeme == lexeme {
+		return i
+	}
+	return -1
+}
+
+// findPrefix returns the range [start, end) of the terms of the vector whose
+// lexemes begin with the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start = sort.Search(len(v), func(i int) bool {
+		return v[i].lexeme >= prefix
+	})
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].lexeme, prefix) {
+		end++
+	}
+	return start, end
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVector(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`foo`, `'foo'`},
+		{`foo bar`, `'bar' 'foo'`},
+		{`the fat rats the`, `'fat' 'rats' 'the'`},
+		{`'a' 'fat':2,5A cat:3B`, `'a' 'cat':3B 'fat':2,5A`},
+		{`a:3,1,2,1`, `'a':1,2,3`},
+		{`a:1C,1A,1B`, `'a':1A`},
+		{`a:1 a:2B`, `'a':1,2B`},
+		{`a:1d`, `'a':1`},
+		{`a:20000`, `'a':16383`},
+		{`'    ' 'with space'`, `'    ' 'with space'`},
+		{`'it''s' back\\slash`, `'back\\slash' 'it''s'`},
+		{`\'quote`, `'''quote'`},
+		{`a\ b`, `'a b'`},
+		{`'a':1 'a':1`, `'a':1`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v.String())
+
+			// The output representation must round trip.
+			v2, err := ParseTSVector(v.String())
+			require.NoError(t, err)
+			require.Equal(t, 0, v.Compare(v2))
+		})
+	}
+}
+
+func TestParseTSVectorError(t *testing.T) {
+	for _, input := range []string{
+		`'unterminated`,
+		`a:`,
+		`a:0`,
+		`a:1,`,
+		`a:1E`,
+		`a:x`,
+		`'':1`,
+		`trailing\`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSVector(input)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTSVectorCompare(t *testing.T) {
+	ordered := []string{
+		``,
+		`a`,
+		`a:1`,
+		`a:2`,
+		`a:2A`,
+		`a:1,2`,
+		`b`,
+		`a b`,
+		`a b:1`,
+	}
+	for i := range ordered {
+		l, err := ParseTSVector(ordered[i])
+		require.NoError(t, err)
+		for j := range ordered {
+			r, err := ParseTSVector(ordered[j])
+			require.NoError(t, err)
+			require.Equal(t, compareInts(i, j), l.Compare(r), "%q vs %q", ordered[i], ordered[j])
+		}
+	}
+}
+
+func TestTSVectorFunctions(t *testing.T) {
+	mustParse := func(s string) TSVector {
+		v, err := ParseTSVector(s)
+		require.NoError(t, err)
+		return v
+	}
+
+	v := mustParse(`a:1,3B b:2 c`)
+	require.Equal(t, 3, v.Len())
+	require.Equal(t, []string{"a", "b", "c"}, v.Lexemes())
+	require.Equal(t, `'a' 'b' 'c'`, v.Strip().String())
+	// Stripping returns a copy.
+	require.Equal(t, `'a':1,3B 'b':2 'c'`, v.String())
+
+	weighted, err := v.SetWeight("a")
+	require.NoError(t, err)
+	require.Equal(t, `'a':1A,3A 'b':2A 'c'`, weighted.String())
+	_, err = v.SetWeight("e")
+	require.Error(t, err)
+	_, err = v.SetWeight("ab")
+	require.Error(t, err)
+
+	require.Equal(t, `'a':1,3B,5 'b':2 'c' 'd':4C`, v.Concat(mustParse(`a:2 d:1C`)).String())
+	require.Equal(t, `'a':1 'b'`, mustParse(`a:1`).Concat(mustParse(`b`)).String())
+
+	fromLexemes, err := MakeTSVectorFromLexemes([]string{"b", "a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, `'a' 'b'`, fromLexemes.String())
+	_, err = MakeTSVectorFromLexemes([]string{"a", ""})
+	require.Error(t, err)
+}